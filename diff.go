@@ -0,0 +1,321 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cadence
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// Difference is a difference between two values, found by Diff.
+//
+type Difference struct {
+	// Path is the location of the difference in the compared values,
+	// e.g. `.items[1].balance`. The path of the compared values themselves is empty
+	Path string
+	// A and B are the differing values. One of them is nil if the value only exists in the other
+	A, B Value
+	// Message describes the difference
+	Message string
+}
+
+func (d Difference) String() string {
+	path := d.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("%s: %s", path, d.Message)
+}
+
+// Differences are the differences between two values, found by Diff.
+//
+type Differences []Difference
+
+func (d Differences) String() string {
+	var b strings.Builder
+	for i, difference := range d {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(difference.String())
+	}
+	return b.String()
+}
+
+// Equal reports whether the given values are deeply equal.
+//
+// Unlike reflect.DeepEqual, integers are compared by value, independent of their representation,
+// dictionaries are compared independent of the order of their entries,
+// and composites are compared by type ID, not by type identity.
+// The static types of arrays and dictionaries are not compared.
+//
+func Equal(a, b Value) bool {
+	return len(Diff(a, b)) == 0
+}
+
+// Diff returns the differences between the given values, compared like Equal does.
+// It returns no differences if the values are equal.
+//
+func Diff(a, b Value) Differences {
+	d := &differ{}
+	d.diff("", a, b)
+	return d.differences
+}
+
+type differ struct {
+	differences Differences
+}
+
+func (d *differ) report(path string, a, b Value, format string, args ...interface{}) {
+	d.differences = append(d.differences, Difference{
+		Path:    path,
+		A:       a,
+		B:       b,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (d *differ) reportValues(path string, a, b Value) {
+	d.report(path, a, b, "%s != %s", valueString(a), valueString(b))
+}
+
+func valueString(value Value) (result string) {
+	if value == nil {
+		return "<nil>"
+	}
+
+	// Composites without a type cannot be formatted
+
+	defer func() {
+		if recover() != nil {
+			result = fmt.Sprintf("%T", value)
+		}
+	}()
+
+	return value.String()
+}
+
+func (d *differ) diff(path string, a, b Value) {
+
+	if a == nil || b == nil {
+		if a != nil || b != nil {
+			d.reportValues(path, a, b)
+		}
+		return
+	}
+
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		d.report(path, a, b, "%T != %T", a, b)
+		return
+	}
+
+	switch a := a.(type) {
+	case Optional:
+		d.diffOptional(path, a, b.(Optional))
+
+	case Bytes:
+		if !bytes.Equal(a, b.(Bytes)) {
+			d.reportValues(path, a, b)
+		}
+
+	case Array:
+		d.diffArray(path, a, b.(Array))
+
+	case Dictionary:
+		d.diffDictionary(path, a, b.(Dictionary))
+
+	case Struct:
+		b := b.(Struct)
+		d.diffComposite(path, a, b, a.StructType, b.StructType, a.Fields, b.Fields)
+
+	case Resource:
+		b := b.(Resource)
+		d.diffComposite(path, a, b, a.ResourceType, b.ResourceType, a.Fields, b.Fields)
+
+	case Event:
+		b := b.(Event)
+		d.diffComposite(path, a, b, a.EventType, b.EventType, a.Fields, b.Fields)
+
+	case Contract:
+		b := b.(Contract)
+		d.diffComposite(path, a, b, a.ContractType, b.ContractType, a.Fields, b.Fields)
+
+	case Enum:
+		b := b.(Enum)
+		d.diffComposite(path, a, b, a.EnumType, b.EnumType, a.Fields, b.Fields)
+
+	default:
+		if aBig, ok := bigIntValue(a); ok {
+			bBig, _ := bigIntValue(b)
+			if aBig.Cmp(bBig) != 0 {
+				d.reportValues(path, a, b)
+			}
+			return
+		}
+
+		// All other values are comparable
+
+		if a != b {
+			d.reportValues(path, a, b)
+		}
+	}
+}
+
+// bigIntValue returns the big integer of the given integer value, if it is represented as one.
+//
+func bigIntValue(value Value) (*big.Int, bool) {
+	switch value := value.(type) {
+	case Int:
+		return value.Value, true
+	case Int128:
+		return value.Value, true
+	case Int256:
+		return value.Value, true
+	case UInt:
+		return value.Value, true
+	case UInt128:
+		return value.Value, true
+	case UInt256:
+		return value.Value, true
+	}
+
+	return nil, false
+}
+
+func (d *differ) diffOptional(path string, a, b Optional) {
+	if a.Value == nil || b.Value == nil {
+		if a.Value != nil || b.Value != nil {
+			d.reportValues(path, a, b)
+		}
+		return
+	}
+
+	d.diff(path, a.Value, b.Value)
+}
+
+func (d *differ) diffArray(path string, a, b Array) {
+	count := len(a.Values)
+	if len(b.Values) > count {
+		count = len(b.Values)
+	}
+
+	for i := 0; i < count; i++ {
+		elementPath := fmt.Sprintf("%s[%d]", path, i)
+
+		switch {
+		case i >= len(a.Values):
+			d.report(elementPath, nil, b.Values[i], "missing in A: %s", b.Values[i])
+		case i >= len(b.Values):
+			d.report(elementPath, a.Values[i], nil, "missing in B: %s", a.Values[i])
+		default:
+			d.diff(elementPath, a.Values[i], b.Values[i])
+		}
+	}
+}
+
+func (d *differ) diffDictionary(path string, a, b Dictionary) {
+
+	// Index the entries of B by the string representation of their keys,
+	// so equal keys are only compared against keys with the same representation
+
+	bIndices := map[string][]int{}
+	for i, pair := range b.Pairs {
+		key := valueString(pair.Key)
+		bIndices[key] = append(bIndices[key], i)
+	}
+
+	matched := make([]bool, len(b.Pairs))
+
+	findInB := func(key Value) (int, bool) {
+		for _, i := range bIndices[valueString(key)] {
+			if !matched[i] && Equal(key, b.Pairs[i].Key) {
+				return i, true
+			}
+		}
+		return 0, false
+	}
+
+	for _, pair := range a.Pairs {
+		entryPath := fmt.Sprintf("%s[%s]", path, valueString(pair.Key))
+
+		i, ok := findInB(pair.Key)
+		if !ok {
+			d.report(entryPath, pair.Value, nil, "missing in B: %s", valueString(pair.Value))
+			continue
+		}
+
+		matched[i] = true
+		d.diff(entryPath, pair.Value, b.Pairs[i].Value)
+	}
+
+	for i, pair := range b.Pairs {
+		if matched[i] {
+			continue
+		}
+
+		entryPath := fmt.Sprintf("%s[%s]", path, valueString(pair.Key))
+		d.report(entryPath, nil, pair.Value, "missing in A: %s", valueString(pair.Value))
+	}
+}
+
+func (d *differ) diffComposite(
+	path string,
+	a, b Value,
+	aType, bType CompositeType,
+	aFields, bFields []Value,
+) {
+	aTypeID := compositeTypeID(aType)
+	bTypeID := compositeTypeID(bType)
+
+	if aTypeID != bTypeID {
+		d.report(path, a, b, "type %s != %s", aTypeID, bTypeID)
+		return
+	}
+
+	if len(aFields) != len(bFields) {
+		d.report(path, a, b, "field count %d != %d", len(aFields), len(bFields))
+		return
+	}
+
+	var fieldTypes []Field
+	if aType != nil && !reflect.ValueOf(aType).IsNil() {
+		fieldTypes = aType.CompositeFields()
+	}
+
+	for i, aField := range aFields {
+		var fieldPath string
+		if i < len(fieldTypes) {
+			fieldPath = fmt.Sprintf("%s.%s", path, fieldTypes[i].Identifier)
+		} else {
+			fieldPath = fmt.Sprintf("%s.%d", path, i)
+		}
+
+		d.diff(fieldPath, aField, bFields[i])
+	}
+}
+
+func compositeTypeID(t CompositeType) string {
+	if t == nil || reflect.ValueOf(t).IsNil() {
+		return "<nil>"
+	}
+	return t.ID()
+}
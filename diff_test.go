@@ -0,0 +1,226 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cadence
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestEqual(t *testing.T) {
+
+	t.Parallel()
+
+	structType := &StructType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "Foo",
+		Fields: []Field{
+			{Identifier: "bar", Type: IntType{}},
+		},
+	}
+
+	// Equal type IDs, but different type pointers
+	otherStructType := &StructType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "Foo",
+		Fields:              structType.Fields,
+	}
+
+	type testCase struct {
+		name  string
+		a, b  Value
+		equal bool
+	}
+
+	testCases := []testCase{
+		{"nil", nil, nil, true},
+		{"nil and value", nil, NewInt(1), false},
+		{"Int", NewInt(1), NewIntFromBig(big.NewInt(1)), true},
+		{"Int zero representation", NewIntFromBig(new(big.Int)), NewIntFromBig(new(big.Int).SetBytes([]byte{0})), true},
+		{"Int different", NewInt(1), NewInt(2), false},
+		{"different types", NewInt(1), NewUInt8(1), false},
+		{"String", String("a"), String("a"), true},
+		{"Bytes", NewBytes([]byte{1}), NewBytes([]byte{1}), true},
+		{"Optional nil", NewOptional(nil), NewOptional(nil), true},
+		{"Optional nil and value", NewOptional(nil), NewOptional(NewInt(1)), false},
+		{"Array", NewArray([]Value{NewInt(1)}), NewArray([]Value{NewInt(1)}), true},
+		{"Array length", NewArray([]Value{NewInt(1)}), NewArray([]Value{}), false},
+		{
+			"Dictionary order",
+			NewDictionary([]KeyValuePair{
+				{Key: String("a"), Value: NewInt(1)},
+				{Key: String("b"), Value: NewInt(2)},
+			}),
+			NewDictionary([]KeyValuePair{
+				{Key: String("b"), Value: NewInt(2)},
+				{Key: String("a"), Value: NewInt(1)},
+			}),
+			true,
+		},
+		{
+			"Struct type identity",
+			NewStruct([]Value{NewInt(1)}).WithType(structType),
+			NewStruct([]Value{NewInt(1)}).WithType(otherStructType),
+			true,
+		},
+		{
+			"Path",
+			Path{Domain: "storage", Identifier: "foo"},
+			Path{Domain: "storage", Identifier: "bar"},
+			false,
+		},
+	}
+
+	for _, testCase := range testCases {
+
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+
+			t.Parallel()
+
+			assert.Equal(t, testCase.equal, Equal(testCase.a, testCase.b))
+			assert.Equal(t, testCase.equal, Equal(testCase.b, testCase.a))
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+
+	t.Parallel()
+
+	itemType := &StructType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "Item",
+		Fields: []Field{
+			{Identifier: "id", Type: UInt64Type{}},
+			{Identifier: "tags", Type: DictionaryType{KeyType: StringType{}, ElementType: BoolType{}}},
+		},
+	}
+
+	eventType := &EventType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "Created",
+		Fields: []Field{
+			{Identifier: "items", Type: VariableSizedArrayType{ElementType: itemType}},
+		},
+	}
+
+	item := func(id uint64, tags ...KeyValuePair) Value {
+		return NewStruct([]Value{
+			NewUInt64(id),
+			NewDictionary(tags),
+		}).WithType(itemType)
+	}
+
+	tag := func(name string, value bool) KeyValuePair {
+		return KeyValuePair{Key: String(name), Value: NewBool(value)}
+	}
+
+	a := NewEvent([]Value{
+		NewArray([]Value{
+			item(1, tag("a", true), tag("b", true)),
+			item(2),
+		}),
+	}).WithType(eventType)
+
+	b := NewEvent([]Value{
+		NewArray([]Value{
+			item(1, tag("c", true), tag("a", false)),
+			item(3),
+			item(4),
+		}),
+	}).WithType(eventType)
+
+	differences := Diff(a, b)
+
+	assert.Equal(t,
+		Differences{
+			{
+				Path:    `.items[0].tags["a"]`,
+				A:       NewBool(true),
+				B:       NewBool(false),
+				Message: "true != false",
+			},
+			{
+				Path:    `.items[0].tags["b"]`,
+				A:       NewBool(true),
+				Message: "missing in B: true",
+			},
+			{
+				Path:    `.items[0].tags["c"]`,
+				B:       NewBool(true),
+				Message: "missing in A: true",
+			},
+			{
+				Path:    ".items[1].id",
+				A:       NewUInt64(2),
+				B:       NewUInt64(3),
+				Message: "2 != 3",
+			},
+			{
+				Path:    ".items[2]",
+				B:       item(4),
+				Message: "missing in A: S.test.Item(id: 4, tags: {})",
+			},
+		},
+		differences,
+	)
+
+	assert.Equal(t,
+		`.items[0].tags["a"]: true != false
+.items[0].tags["b"]: missing in B: true
+.items[0].tags["c"]: missing in A: true
+.items[1].id: 2 != 3
+.items[2]: missing in A: S.test.Item(id: 4, tags: {})`,
+		differences.String(),
+	)
+
+	require.Empty(t, Diff(a, a))
+}
+
+func TestDiffCompositeTypes(t *testing.T) {
+
+	t.Parallel()
+
+	a := NewStruct(nil).WithType(&StructType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "A",
+	})
+
+	b := NewStruct(nil).WithType(&StructType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "B",
+	})
+
+	assert.Equal(t,
+		"(root): type S.test.A != S.test.B",
+		Diff(a, b).String(),
+	)
+
+	assert.Equal(t,
+		"(root): type S.test.A != <nil>",
+		Diff(a, NewStruct(nil)).String(),
+	)
+}
@@ -0,0 +1,173 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package ccf implements the Cadence Compact Format (CCF),
+// a compact CBOR-based encoding of Cadence values.
+//
+// CCF supports two modes:
+//
+// In the self-describing mode, the encoding contains the definitions of all composite types
+// of the encoded value, i.e. their kind, type ID, and field names,
+// so a value can be decoded without any additional information.
+//
+// In the schema-referenced mode, the encoding only contains the type IDs of the composite types,
+// and the types themselves (the schema) must be provided when decoding.
+// This mode is the most compact, e.g. for events, whose types are known to the receiver.
+//
+// In both modes, composite values reference their type by its index.
+//
+// Encoding layout:
+//
+//	self-describing:   tag(SelfDescribing) [ [definition...], value ]
+//	schema-referenced: tag(SchemaReferenced) [ [type ID...], value ]
+//	definition:        [kind, type ID, [field name...]]
+//
+//	Void:          tag(Void) null
+//	Optional:      null (nil) or tag(Some) value
+//	Bool:          bool
+//	String:        text string
+//	Bytes:         byte string
+//	Address:       tag(Address) byte string
+//	Int*, UInt*, Word*, Fix64, UFix64: tag(number type) integer or bignum
+//	Array:         [value...]
+//	Dictionary:    tag(Dictionary) [key, value, key, value, ...]
+//	Composite:     tag(Composite) [type index, [field value...]]
+//	Path:          tag(Path) [domain, identifier]
+//	Link:          tag(Link) [target path, borrow type]
+//	Type:          tag(Type) static type
+//	Capability:    tag(Capability) [path, address, borrow type]
+//
+package ccf
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+)
+
+// maxInt is math.MaxInt32 or math.MaxInt64 depending on arch.
+const maxInt = 1<<(bits.UintSize-1) - 1
+
+// The limits of the encoding, which the encoder enforces and the decoder accepts.
+//
+// maxArrayElements is the maximum number of elements of a CBOR array,
+// e.g. of the elements of an array value, or of the keys and values of a dictionary value.
+//
+// maxNestedLevels is the maximum number of nested CBOR data items, i.e. of tags and arrays,
+// e.g. of an array value in an array value.
+//
+const (
+	maxArrayElements = maxInt
+	maxNestedLevels  = math.MaxInt16
+)
+
+const cborTagBase = 128
+
+// !!! *WARNING* !!!
+//
+// Only add new types by:
+// - replacing existing placeholders (`_`) with new types
+// - appending new types
+//
+// Only remove types by:
+// - replace existing types with a placeholder `_`
+//
+// DO *NOT* REPLACE EXISTING TYPES!
+// DO *NOT* ADD NEW TYPES IN BETWEEN!
+
+const (
+	// Modes
+	cborTagSelfDescribing = cborTagBase + iota
+	cborTagSchemaReferenced
+	_
+	_
+	_
+	_
+	_
+	_
+
+	// Values
+	cborTagVoid
+	cborTagSome
+	cborTagAddress
+	cborTagDictionary
+	cborTagComposite
+	cborTagPath
+	cborTagLink
+	cborTagType
+	cborTagCapability
+	_
+	_
+	_
+	_
+	_
+	_
+	_
+
+	// Int*
+	cborTagInt
+	cborTagInt8
+	cborTagInt16
+	cborTagInt32
+	cborTagInt64
+	cborTagInt128
+	cborTagInt256
+	_
+
+	// UInt*
+	cborTagUInt
+	cborTagUInt8
+	cborTagUInt16
+	cborTagUInt32
+	cborTagUInt64
+	cborTagUInt128
+	cborTagUInt256
+	_
+
+	// Word*
+	_
+	cborTagWord8
+	cborTagWord16
+	cborTagWord32
+	cborTagWord64
+	_ // future: Word128
+	_ // future: Word256
+	_
+
+	// Fix*
+	cborTagFix64
+	cborTagUFix64
+)
+
+// compositeKind is the kind of a composite type definition.
+//
+type compositeKind uint64
+
+// !!! *WARNING* !!!
+//
+// Only append new kinds.
+//
+const (
+	compositeKindStruct compositeKind = iota
+	compositeKindResource
+	compositeKindEvent
+	compositeKindContract
+	compositeKindEnum
+)
+
+var ErrInvalidCCF = errors.New("invalid CCF encoding")
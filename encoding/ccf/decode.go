@@ -0,0 +1,822 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ccf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// Schema is a set of composite types, keyed by type ID.
+//
+// A schema is required to decode schema-referenced encodings.
+//
+type Schema map[string]cadence.Type
+
+// NewSchema returns a schema with the given composite types.
+//
+func NewSchema(types ...cadence.Type) Schema {
+	schema := make(Schema, len(types))
+	for _, ty := range types {
+		schema[ty.ID()] = ty
+	}
+	return schema
+}
+
+// A Decoder decodes CCF-encoded representations of Cadence values.
+type Decoder struct {
+	dec    *cbor.StreamDecoder
+	schema Schema
+}
+
+// DecoderOption is an option for a Decoder.
+type DecoderOption func(*Decoder)
+
+// WithSchema returns a decoder option which provides the schema
+// for decoding schema-referenced encodings.
+//
+func WithSchema(schema Schema) DecoderOption {
+	return func(decoder *Decoder) {
+		decoder.schema = schema
+	}
+}
+
+// Decode returns a Cadence value decoded from its CCF-encoded representation.
+//
+// This function returns an error if the bytes represent CBOR that is malformed
+// or does not conform to the CCF specification, or if the value's composite types
+// are not in the schema of a schema-referenced encoding.
+func Decode(b []byte, options ...DecoderOption) (cadence.Value, error) {
+	r := bytes.NewReader(b)
+	dec := NewDecoder(r, options...)
+
+	v, err := dec.Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+var decMode = func() cbor.DecMode {
+	decMode, err := cbor.DecOptions{
+		MaxArrayElements: maxArrayElements,
+		MaxMapPairs:      maxArrayElements,
+		MaxNestedLevels:  maxNestedLevels,
+	}.DecMode()
+	if err != nil {
+		panic(err)
+	}
+	return decMode
+}()
+
+// NewDecoder initializes a Decoder that will decode CCF-encoded bytes from the
+// given io.Reader.
+func NewDecoder(r io.Reader, options ...DecoderOption) *Decoder {
+	decoder := &Decoder{
+		dec: decMode.NewStreamDecoder(r),
+	}
+
+	for _, option := range options {
+		option(decoder)
+	}
+
+	return decoder
+}
+
+// Decode reads CCF-encoded bytes from the io.Reader and decodes them to a
+// Cadence value.
+//
+// This function returns an error if the bytes represent CBOR that is malformed
+// or does not conform to the CCF specification.
+func (d *Decoder) Decode() (value cadence.Value, err error) {
+	value, err = d.decode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode value: %w", err)
+	}
+
+	return value, nil
+}
+
+func (d *Decoder) decode() (cadence.Value, error) {
+	tag, err := d.dec.DecodeTagNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	valueDecoder := &valueDecoder{
+		dec: d.dec,
+	}
+
+	err = decodeArrayHead(d.dec, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	switch tag {
+	case cborTagSelfDescribing:
+		valueDecoder.definitions, err = d.decodeDefinitions()
+		if err != nil {
+			return nil, err
+		}
+
+	case cborTagSchemaReferenced:
+		valueDecoder.schemaTypes, err = d.decodeSchemaTypes()
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("%w: unknown mode tag %d", ErrInvalidCCF, tag)
+	}
+
+	return valueDecoder.decode()
+}
+
+func (d *Decoder) decodeDefinitions() ([]definition, error) {
+	count, err := d.dec.DecodeArrayHead()
+	if err != nil {
+		return nil, err
+	}
+
+	var definitions []definition
+
+	for i := uint64(0); i < count; i++ {
+		err := decodeArrayHead(d.dec, 3)
+		if err != nil {
+			return nil, err
+		}
+
+		kind, err := d.dec.DecodeUint64()
+		if err != nil {
+			return nil, err
+		}
+
+		if kind > uint64(compositeKindEnum) {
+			return nil, fmt.Errorf("%w: unknown composite kind %d", ErrInvalidCCF, kind)
+		}
+
+		typeID, err := d.dec.DecodeString()
+		if err != nil {
+			return nil, err
+		}
+
+		fieldCount, err := d.dec.DecodeArrayHead()
+		if err != nil {
+			return nil, err
+		}
+
+		var fieldNames []string
+
+		for j := uint64(0); j < fieldCount; j++ {
+			fieldName, err := d.dec.DecodeString()
+			if err != nil {
+				return nil, err
+			}
+
+			fieldNames = append(fieldNames, fieldName)
+		}
+
+		definitions = append(
+			definitions,
+			definition{
+				kind:       compositeKind(kind),
+				typeID:     typeID,
+				fieldNames: fieldNames,
+			},
+		)
+	}
+
+	return definitions, nil
+}
+
+// decodeSchemaTypes decodes the type IDs of a schema-referenced encoding
+// and resolves them using the schema.
+//
+func (d *Decoder) decodeSchemaTypes() ([]cadence.Type, error) {
+	count, err := d.dec.DecodeArrayHead()
+	if err != nil {
+		return nil, err
+	}
+
+	var schemaTypes []cadence.Type
+
+	for i := uint64(0); i < count; i++ {
+		typeID, err := d.dec.DecodeString()
+		if err != nil {
+			return nil, err
+		}
+
+		ty, ok := d.schema[typeID]
+		if !ok {
+			return nil, fmt.Errorf("unknown composite type %s: not in schema", typeID)
+		}
+
+		schemaTypes = append(schemaTypes, ty)
+	}
+
+	return schemaTypes, nil
+}
+
+type valueDecoder struct {
+	dec *cbor.StreamDecoder
+	// definitions are the composite type definitions of a self-describing encoding
+	definitions []definition
+	// schemaTypes are the composite types of a schema-referenced encoding
+	schemaTypes []cadence.Type
+}
+
+func (d *valueDecoder) decode() (cadence.Value, error) {
+	t, err := d.dec.NextType()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t {
+	case cbor.NilType:
+		err := d.dec.DecodeNil()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewOptional(nil), nil
+
+	case cbor.BoolType:
+		v, err := d.dec.DecodeBool()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewBool(v), nil
+
+	case cbor.TextStringType:
+		v, err := d.dec.DecodeString()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewString(v)
+
+	case cbor.ByteStringType:
+		v, err := d.dec.DecodeBytes()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewBytes(v), nil
+
+	case cbor.ArrayType:
+		return d.decodeArray()
+
+	case cbor.TagType:
+		tag, err := d.dec.DecodeTagNumber()
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeTagged(tag)
+
+	default:
+		return nil, fmt.Errorf("%w: unexpected %s", ErrInvalidCCF, t)
+	}
+}
+
+func (d *valueDecoder) decodeTagged(tag uint64) (cadence.Value, error) {
+	switch tag {
+	case cborTagVoid:
+		err := d.dec.DecodeNil()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewVoid(), nil
+
+	case cborTagSome:
+		value, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewOptional(value), nil
+
+	case cborTagAddress:
+		return d.decodeAddress()
+
+	case cborTagInt:
+		v, err := d.decodeBigInt()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewIntFromBig(v), nil
+
+	case cborTagInt8:
+		v, err := d.decodeInt64(math.MinInt8, math.MaxInt8)
+		return cadence.Int8(v), err
+
+	case cborTagInt16:
+		v, err := d.decodeInt64(math.MinInt16, math.MaxInt16)
+		return cadence.Int16(v), err
+
+	case cborTagInt32:
+		v, err := d.decodeInt64(math.MinInt32, math.MaxInt32)
+		return cadence.Int32(v), err
+
+	case cborTagInt64:
+		v, err := d.decodeInt64(math.MinInt64, math.MaxInt64)
+		return cadence.Int64(v), err
+
+	case cborTagInt128:
+		v, err := d.decodeBigInt()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewInt128FromBig(v)
+
+	case cborTagInt256:
+		v, err := d.decodeBigInt()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewInt256FromBig(v)
+
+	case cborTagUInt:
+		v, err := d.decodeBigInt()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewUIntFromBig(v)
+
+	case cborTagUInt8:
+		v, err := d.decodeUint64(math.MaxUint8)
+		return cadence.UInt8(v), err
+
+	case cborTagUInt16:
+		v, err := d.decodeUint64(math.MaxUint16)
+		return cadence.UInt16(v), err
+
+	case cborTagUInt32:
+		v, err := d.decodeUint64(math.MaxUint32)
+		return cadence.UInt32(v), err
+
+	case cborTagUInt64:
+		v, err := d.decodeUint64(math.MaxUint64)
+		return cadence.UInt64(v), err
+
+	case cborTagUInt128:
+		v, err := d.decodeBigInt()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewUInt128FromBig(v)
+
+	case cborTagUInt256:
+		v, err := d.decodeBigInt()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewUInt256FromBig(v)
+
+	case cborTagWord8:
+		v, err := d.decodeUint64(math.MaxUint8)
+		return cadence.Word8(v), err
+
+	case cborTagWord16:
+		v, err := d.decodeUint64(math.MaxUint16)
+		return cadence.Word16(v), err
+
+	case cborTagWord32:
+		v, err := d.decodeUint64(math.MaxUint32)
+		return cadence.Word32(v), err
+
+	case cborTagWord64:
+		v, err := d.decodeUint64(math.MaxUint64)
+		return cadence.Word64(v), err
+
+	case cborTagFix64:
+		v, err := d.decodeInt64(math.MinInt64, math.MaxInt64)
+		return cadence.Fix64(v), err
+
+	case cborTagUFix64:
+		v, err := d.decodeUint64(math.MaxUint64)
+		return cadence.UFix64(v), err
+
+	case cborTagDictionary:
+		return d.decodeDictionary()
+
+	case cborTagComposite:
+		return d.decodeComposite()
+
+	case cborTagPath:
+		path, err := d.decodePath()
+		if err != nil {
+			return nil, err
+		}
+		return path, nil
+
+	case cborTagLink:
+		return d.decodeLink()
+
+	case cborTagType:
+		staticType, err := d.dec.DecodeString()
+		if err != nil {
+			return nil, err
+		}
+		return cadence.TypeValue{StaticType: staticType}, nil
+
+	case cborTagCapability:
+		return d.decodeCapability()
+
+	default:
+		return nil, fmt.Errorf("%w: unknown tag %d", ErrInvalidCCF, tag)
+	}
+}
+
+// decodeBigInt decodes an arbitrary-size integer.
+//
+// The encoder uses the shortest form, i.e. integers which fit
+// into 64 bits are encoded as integers, and only larger integers as bignums.
+//
+func (d *valueDecoder) decodeBigInt() (*big.Int, error) {
+	t, err := d.dec.NextType()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t {
+	case cbor.UintType:
+		v, err := d.dec.DecodeUint64()
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetUint64(v), nil
+
+	case cbor.IntType:
+		v, err := d.dec.DecodeInt64()
+		if err != nil {
+			return nil, err
+		}
+		return big.NewInt(v), nil
+
+	default:
+		return d.dec.DecodeBigInt()
+	}
+}
+
+func (d *valueDecoder) decodeInt64(min, max int64) (int64, error) {
+	v, err := d.dec.DecodeInt64()
+	if err != nil {
+		return 0, err
+	}
+
+	if v < min || v > max {
+		return 0, fmt.Errorf("%w: integer %d out of range", ErrInvalidCCF, v)
+	}
+
+	return v, nil
+}
+
+func (d *valueDecoder) decodeUint64(max uint64) (uint64, error) {
+	v, err := d.dec.DecodeUint64()
+	if err != nil {
+		return 0, err
+	}
+
+	if v > max {
+		return 0, fmt.Errorf("%w: integer %d out of range", ErrInvalidCCF, v)
+	}
+
+	return v, nil
+}
+
+func (d *valueDecoder) decodeAddress() (cadence.Address, error) {
+	b, err := d.dec.DecodeBytes()
+	if err != nil {
+		return cadence.Address{}, err
+	}
+
+	if len(b) != cadence.AddressLength {
+		return cadence.Address{}, fmt.Errorf("%w: invalid address length %d", ErrInvalidCCF, len(b))
+	}
+
+	return cadence.BytesToAddress(b), nil
+}
+
+func (d *valueDecoder) decodeValues(count uint64) ([]cadence.Value, error) {
+	values := make([]cadence.Value, count)
+
+	for i := uint64(0); i < count; i++ {
+		value, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+func (d *valueDecoder) decodeArray() (cadence.Value, error) {
+	count, err := d.dec.DecodeArrayHead()
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := d.decodeValues(count)
+	if err != nil {
+		return nil, err
+	}
+
+	return cadence.NewArray(values), nil
+}
+
+func (d *valueDecoder) decodeDictionary() (cadence.Value, error) {
+	count, err := d.dec.DecodeArrayHead()
+	if err != nil {
+		return nil, err
+	}
+
+	if count%2 != 0 {
+		return nil, fmt.Errorf("%w: odd number of dictionary elements", ErrInvalidCCF)
+	}
+
+	pairs := make([]cadence.KeyValuePair, count/2)
+
+	for i := range pairs {
+		key, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+
+		pairs[i] = cadence.KeyValuePair{
+			Key:   key,
+			Value: value,
+		}
+	}
+
+	return cadence.NewDictionary(pairs), nil
+}
+
+func (d *valueDecoder) decodeComposite() (cadence.Value, error) {
+	err := decodeArrayHead(d.dec, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := d.dec.DecodeUint64()
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := d.decodeFields()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case index < uint64(len(d.definitions)):
+		return newCompositeFromDefinition(d.definitions[index], fields)
+
+	case index < uint64(len(d.schemaTypes)):
+		return newCompositeFromSchemaType(d.schemaTypes[index], fields)
+
+	default:
+		return nil, fmt.Errorf("%w: unknown composite type %d", ErrInvalidCCF, index)
+	}
+}
+
+func (d *valueDecoder) decodeFields() ([]cadence.Value, error) {
+	count, err := d.dec.DecodeArrayHead()
+	if err != nil {
+		return nil, err
+	}
+
+	return d.decodeValues(count)
+}
+
+func newCompositeFromDefinition(definition definition, fields []cadence.Value) (cadence.Value, error) {
+	if len(fields) != len(definition.fieldNames) {
+		return nil, fmt.Errorf(
+			"%w: composite field count (%d) does not match definition (%d)",
+			ErrInvalidCCF,
+			len(fields),
+			len(definition.fieldNames),
+		)
+	}
+
+	typeID := definition.typeID
+
+	location, qualifiedIdentifier, err := common.DecodeTypeID(typeID)
+	if err != nil ||
+		location == nil && sema.NativeCompositeTypes[typeID] == nil {
+
+		return nil, fmt.Errorf("%w: invalid type ID: `%s`", ErrInvalidCCF, typeID)
+	}
+
+	// Like in the JSON-Cadence format, the definition does not contain the field types,
+	// so the field types are the types of the field values
+
+	fieldTypes := make([]cadence.Field, len(fields))
+	for i, field := range fields {
+		fieldTypes[i] = cadence.Field{
+			Identifier: definition.fieldNames[i],
+			Type:       field.Type(),
+		}
+	}
+
+	switch definition.kind {
+	case compositeKindStruct:
+		return cadence.NewStruct(fields).WithType(&cadence.StructType{
+			Location:            location,
+			QualifiedIdentifier: qualifiedIdentifier,
+			Fields:              fieldTypes,
+		}), nil
+
+	case compositeKindResource:
+		return cadence.NewResource(fields).WithType(&cadence.ResourceType{
+			Location:            location,
+			QualifiedIdentifier: qualifiedIdentifier,
+			Fields:              fieldTypes,
+		}), nil
+
+	case compositeKindEvent:
+		return cadence.NewEvent(fields).WithType(&cadence.EventType{
+			Location:            location,
+			QualifiedIdentifier: qualifiedIdentifier,
+			Fields:              fieldTypes,
+		}), nil
+
+	case compositeKindContract:
+		return cadence.NewContract(fields).WithType(&cadence.ContractType{
+			Location:            location,
+			QualifiedIdentifier: qualifiedIdentifier,
+			Fields:              fieldTypes,
+		}), nil
+
+	case compositeKindEnum:
+		return cadence.NewEnum(fields).WithType(&cadence.EnumType{
+			Location:            location,
+			QualifiedIdentifier: qualifiedIdentifier,
+			Fields:              fieldTypes,
+		}), nil
+
+	default:
+		return nil, fmt.Errorf("%w: unknown composite kind %d", ErrInvalidCCF, definition.kind)
+	}
+}
+
+func newCompositeFromSchemaType(ty cadence.Type, fields []cadence.Value) (cadence.Value, error) {
+
+	checkFieldCount := func(fieldTypes []cadence.Field) error {
+		fieldNames := nonFunctionFieldNames(fieldTypes)
+		if len(fields) != len(fieldNames) {
+			return fmt.Errorf(
+				"%w: composite field count (%d) does not match schema type (%d)",
+				ErrInvalidCCF,
+				len(fields),
+				len(fieldNames),
+			)
+		}
+		return nil
+	}
+
+	switch ty := ty.(type) {
+	case *cadence.StructType:
+		if err := checkFieldCount(ty.Fields); err != nil {
+			return nil, err
+		}
+		return cadence.NewStruct(fields).WithType(ty), nil
+
+	case *cadence.ResourceType:
+		if err := checkFieldCount(ty.Fields); err != nil {
+			return nil, err
+		}
+		return cadence.NewResource(fields).WithType(ty), nil
+
+	case *cadence.EventType:
+		if err := checkFieldCount(ty.Fields); err != nil {
+			return nil, err
+		}
+		return cadence.NewEvent(fields).WithType(ty), nil
+
+	case *cadence.ContractType:
+		if err := checkFieldCount(ty.Fields); err != nil {
+			return nil, err
+		}
+		return cadence.NewContract(fields).WithType(ty), nil
+
+	case *cadence.EnumType:
+		if err := checkFieldCount(ty.Fields); err != nil {
+			return nil, err
+		}
+		return cadence.NewEnum(fields).WithType(ty), nil
+
+	default:
+		return nil, fmt.Errorf("schema type %s is not a composite type", ty.ID())
+	}
+}
+
+func (d *valueDecoder) decodePath() (cadence.Path, error) {
+	err := decodeArrayHead(d.dec, 2)
+	if err != nil {
+		return cadence.Path{}, err
+	}
+
+	domain, err := d.dec.DecodeString()
+	if err != nil {
+		return cadence.Path{}, err
+	}
+
+	identifier, err := d.dec.DecodeString()
+	if err != nil {
+		return cadence.Path{}, err
+	}
+
+	return cadence.Path{
+		Domain:     domain,
+		Identifier: identifier,
+	}, nil
+}
+
+func (d *valueDecoder) decodeLink() (cadence.Value, error) {
+	err := decodeArrayHead(d.dec, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	targetPath, err := d.decodePath()
+	if err != nil {
+		return nil, err
+	}
+
+	borrowType, err := d.dec.DecodeString()
+	if err != nil {
+		return nil, err
+	}
+
+	return cadence.NewLink(targetPath, borrowType), nil
+}
+
+func (d *valueDecoder) decodeCapability() (cadence.Value, error) {
+	err := decodeArrayHead(d.dec, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := d.decodePath()
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := d.decodeAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	borrowType, err := d.dec.DecodeString()
+	if err != nil {
+		return nil, err
+	}
+
+	return cadence.Capability{
+		Path:       path,
+		Address:    address,
+		BorrowType: borrowType,
+	}, nil
+}
+
+func decodeArrayHead(dec *cbor.StreamDecoder, expected uint64) error {
+	count, err := dec.DecodeArrayHead()
+	if err != nil {
+		return err
+	}
+
+	if count != expected {
+		return fmt.Errorf("%w: expected array of %d elements, got %d", ErrInvalidCCF, expected, count)
+	}
+
+	return nil
+}
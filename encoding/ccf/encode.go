@@ -0,0 +1,700 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ccf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/onflow/cadence"
+)
+
+// An Encoder converts Cadence values into CCF-encoded bytes.
+type Encoder struct {
+	w                io.Writer
+	schemaReferenced bool
+}
+
+// EncoderOption is an option for an Encoder.
+type EncoderOption func(*Encoder)
+
+// WithSchemaReferencedEncoding returns an encoder option which enables the schema-referenced mode:
+// Only the type IDs of composite types are included in the encoding,
+// but not their definitions.
+//
+func WithSchemaReferencedEncoding() EncoderOption {
+	return func(encoder *Encoder) {
+		encoder.schemaReferenced = true
+	}
+}
+
+// Encode returns the CCF-encoded representation of the given value.
+//
+// This function returns an error if the Cadence value cannot be represented in CCF.
+func Encode(value cadence.Value, options ...EncoderOption) ([]byte, error) {
+	var w bytes.Buffer
+	enc := NewEncoder(&w, options...)
+
+	err := enc.Encode(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.Bytes(), nil
+}
+
+// MustEncode returns the CCF-encoded representation of the given value, or panics
+// if the value cannot be represented in CCF.
+func MustEncode(value cadence.Value, options ...EncoderOption) []byte {
+	b, err := Encode(value, options...)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// NewEncoder initializes an Encoder that will write CCF-encoded bytes to the
+// given io.Writer.
+func NewEncoder(w io.Writer, options ...EncoderOption) *Encoder {
+	encoder := &Encoder{w: w}
+
+	for _, option := range options {
+		option(encoder)
+	}
+
+	return encoder
+}
+
+// Encode writes the CCF-encoded representation of the given value to this
+// encoder's io.Writer.
+//
+// This function returns an error if the given value's type is not supported
+// by this encoder.
+func (e *Encoder) Encode(value cadence.Value) error {
+
+	// Encode the value first, which collects the composite type definitions
+
+	var valueBuffer bytes.Buffer
+
+	valueEncoder := &valueEncoder{
+		enc:               cbor.NewStreamEncoder(&valueBuffer),
+		definitionIndices: map[string]uint64{},
+		// The value is nested in the tag and the array of the encoding
+		nestedLevels: 2,
+	}
+
+	err := valueEncoder.encode(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode value: %w", err)
+	}
+
+	err = valueEncoder.enc.Flush()
+	if err != nil {
+		return err
+	}
+
+	enc := cbor.NewStreamEncoder(e.w)
+
+	var tag uint64
+	if e.schemaReferenced {
+		tag = cborTagSchemaReferenced
+	} else {
+		tag = cborTagSelfDescribing
+	}
+
+	err = enc.EncodeTagHead(tag)
+	if err != nil {
+		return err
+	}
+
+	err = enc.EncodeArrayHead(2)
+	if err != nil {
+		return err
+	}
+
+	if e.schemaReferenced {
+		err = encodeTypeIDs(enc, valueEncoder.definitions)
+	} else {
+		err = encodeDefinitions(enc, valueEncoder.definitions)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = enc.EncodeRawBytes(valueBuffer.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return enc.Flush()
+}
+
+// definition is the definition of a composite type.
+//
+// In the self-describing mode, the whole definition is encoded,
+// in the schema-referenced mode, only the type ID.
+//
+type definition struct {
+	kind       compositeKind
+	typeID     string
+	fieldNames []string
+}
+
+func encodeDefinitions(enc *cbor.StreamEncoder, definitions []definition) error {
+	err := enc.EncodeArrayHead(uint64(len(definitions)))
+	if err != nil {
+		return err
+	}
+
+	for _, definition := range definitions {
+
+		err = enc.EncodeArrayHead(3)
+		if err != nil {
+			return err
+		}
+
+		err = enc.EncodeUint64(uint64(definition.kind))
+		if err != nil {
+			return err
+		}
+
+		err = enc.EncodeString(definition.typeID)
+		if err != nil {
+			return err
+		}
+
+		err = enc.EncodeArrayHead(uint64(len(definition.fieldNames)))
+		if err != nil {
+			return err
+		}
+
+		for _, fieldName := range definition.fieldNames {
+			err = enc.EncodeString(fieldName)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func encodeTypeIDs(enc *cbor.StreamEncoder, definitions []definition) error {
+	err := enc.EncodeArrayHead(uint64(len(definitions)))
+	if err != nil {
+		return err
+	}
+
+	for _, definition := range definitions {
+		err = enc.EncodeString(definition.typeID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type valueEncoder struct {
+	enc               *cbor.StreamEncoder
+	definitions       []definition
+	definitionIndices map[string]uint64
+	// nestedLevels is the number of tags and arrays which contain the currently encoded data item
+	nestedLevels int
+}
+
+// enterNested must be called before the head of a tag or an array is encoded,
+// and returns an error if the data item exceeds the maximum number of nested levels.
+// exitNested must be called after the content of the data item is encoded.
+//
+func (e *valueEncoder) enterNested() error {
+	if e.nestedLevels >= maxNestedLevels {
+		return fmt.Errorf("value exceeds the maximum nesting level of %d", maxNestedLevels)
+	}
+	e.nestedLevels++
+	return nil
+}
+
+func (e *valueEncoder) exitNested() {
+	e.nestedLevels--
+}
+
+func checkArrayElements(count uint64) error {
+	if count > maxArrayElements {
+		return fmt.Errorf("value exceeds the maximum number of elements of %d", maxArrayElements)
+	}
+	return nil
+}
+
+func (e *valueEncoder) encode(v cadence.Value) error {
+	switch v := v.(type) {
+	case cadence.Void:
+		return e.encodeTagged(cborTagVoid, e.enc.EncodeNil)
+
+	case cadence.Optional:
+		if v.Value == nil {
+			return e.enc.EncodeNil()
+		}
+		return e.encodeTagged(cborTagSome, func() error {
+			return e.encode(v.Value)
+		})
+
+	case cadence.Bool:
+		return e.enc.EncodeBool(bool(v))
+
+	case cadence.String:
+		return e.enc.EncodeString(string(v))
+
+	case cadence.Bytes:
+		return e.enc.EncodeBytes(v)
+
+	case cadence.Address:
+		return e.encodeTagged(cborTagAddress, func() error {
+			return e.enc.EncodeBytes(v.Bytes())
+		})
+
+	case cadence.Int:
+		return e.encodeBigInt(cborTagInt, v.Big())
+
+	case cadence.Int8:
+		return e.encodeInt64(cborTagInt8, int64(v))
+
+	case cadence.Int16:
+		return e.encodeInt64(cborTagInt16, int64(v))
+
+	case cadence.Int32:
+		return e.encodeInt64(cborTagInt32, int64(v))
+
+	case cadence.Int64:
+		return e.encodeInt64(cborTagInt64, int64(v))
+
+	case cadence.Int128:
+		return e.encodeBigInt(cborTagInt128, v.Big())
+
+	case cadence.Int256:
+		return e.encodeBigInt(cborTagInt256, v.Big())
+
+	case cadence.UInt:
+		return e.encodeBigInt(cborTagUInt, v.Big())
+
+	case cadence.UInt8:
+		return e.encodeUint64(cborTagUInt8, uint64(v))
+
+	case cadence.UInt16:
+		return e.encodeUint64(cborTagUInt16, uint64(v))
+
+	case cadence.UInt32:
+		return e.encodeUint64(cborTagUInt32, uint64(v))
+
+	case cadence.UInt64:
+		return e.encodeUint64(cborTagUInt64, uint64(v))
+
+	case cadence.UInt128:
+		return e.encodeBigInt(cborTagUInt128, v.Big())
+
+	case cadence.UInt256:
+		return e.encodeBigInt(cborTagUInt256, v.Big())
+
+	case cadence.Word8:
+		return e.encodeUint64(cborTagWord8, uint64(v))
+
+	case cadence.Word16:
+		return e.encodeUint64(cborTagWord16, uint64(v))
+
+	case cadence.Word32:
+		return e.encodeUint64(cborTagWord32, uint64(v))
+
+	case cadence.Word64:
+		return e.encodeUint64(cborTagWord64, uint64(v))
+
+	case cadence.Fix64:
+		return e.encodeInt64(cborTagFix64, int64(v))
+
+	case cadence.UFix64:
+		return e.encodeUint64(cborTagUFix64, uint64(v))
+
+	case cadence.Array:
+		return e.encodeArray(v)
+
+	case cadence.Dictionary:
+		return e.encodeDictionary(v)
+
+	case cadence.Struct:
+		return e.encodeComposite(compositeKindStruct, v.StructType.ID(), v.StructType.Fields, v.Fields)
+
+	case cadence.Resource:
+		return e.encodeComposite(compositeKindResource, v.ResourceType.ID(), v.ResourceType.Fields, v.Fields)
+
+	case cadence.Event:
+		return e.encodeComposite(compositeKindEvent, v.EventType.ID(), v.EventType.Fields, v.Fields)
+
+	case cadence.Contract:
+		return e.encodeComposite(compositeKindContract, v.ContractType.ID(), v.ContractType.Fields, v.Fields)
+
+	case cadence.Enum:
+		return e.encodeComposite(compositeKindEnum, v.EnumType.ID(), v.EnumType.Fields, v.Fields)
+
+	case cadence.Path:
+		return e.encodeTagged(cborTagPath, func() error {
+			return e.encodePath(v)
+		})
+
+	case cadence.Link:
+		return e.encodeLink(v)
+
+	case cadence.TypeValue:
+		return e.encodeTagged(cborTagType, func() error {
+			return e.enc.EncodeString(v.StaticType)
+		})
+
+	case cadence.Capability:
+		return e.encodeCapability(v)
+
+	default:
+		return fmt.Errorf("unsupported value: %T, %v", v, v)
+	}
+}
+
+func (e *valueEncoder) encodeTagged(tag uint64, encodeContent func() error) error {
+	err := e.enterNested()
+	if err != nil {
+		return err
+	}
+	defer e.exitNested()
+
+	err = e.enc.EncodeTagHead(tag)
+	if err != nil {
+		return err
+	}
+
+	return encodeContent()
+}
+
+func (e *valueEncoder) encodeInt64(tag uint64, v int64) error {
+	return e.encodeTagged(tag, func() error {
+		return e.enc.EncodeInt64(v)
+	})
+}
+
+func (e *valueEncoder) encodeUint64(tag uint64, v uint64) error {
+	return e.encodeTagged(tag, func() error {
+		return e.enc.EncodeUint64(v)
+	})
+}
+
+func (e *valueEncoder) encodeBigInt(tag uint64, v *big.Int) error {
+	return e.encodeTagged(tag, func() error {
+		return e.enc.EncodeBigInt(v)
+	})
+}
+
+func (e *valueEncoder) encodeArray(v cadence.Array) error {
+	err := checkArrayElements(uint64(len(v.Values)))
+	if err != nil {
+		return err
+	}
+
+	err = e.enterNested()
+	if err != nil {
+		return err
+	}
+	defer e.exitNested()
+
+	err = e.enc.EncodeArrayHead(uint64(len(v.Values)))
+	if err != nil {
+		return err
+	}
+
+	for _, value := range v.Values {
+		err = e.encode(value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *valueEncoder) encodeDictionary(v cadence.Dictionary) error {
+	// The keys and values are encoded in one array
+	err := checkArrayElements(uint64(len(v.Pairs)) * 2)
+	if err != nil {
+		return err
+	}
+
+	err = e.enterNested()
+	if err != nil {
+		return err
+	}
+	defer e.exitNested()
+
+	err = e.enc.EncodeTagHead(cborTagDictionary)
+	if err != nil {
+		return err
+	}
+
+	err = e.enterNested()
+	if err != nil {
+		return err
+	}
+	defer e.exitNested()
+
+	err = e.enc.EncodeArrayHead(uint64(len(v.Pairs) * 2))
+	if err != nil {
+		return err
+	}
+
+	for _, pair := range v.Pairs {
+		err = e.encode(pair.Key)
+		if err != nil {
+			return err
+		}
+
+		err = e.encode(pair.Value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *valueEncoder) encodeComposite(
+	kind compositeKind,
+	typeID string,
+	fieldTypes []cadence.Field,
+	fields []cadence.Value,
+) error {
+
+	fieldNames := nonFunctionFieldNames(fieldTypes)
+
+	if len(fieldNames) != len(fields) {
+		return fmt.Errorf(
+			"composite field count (%d) does not match declared type (%d)",
+			len(fields),
+			len(fieldNames),
+		)
+	}
+
+	err := checkArrayElements(uint64(len(fields)))
+	if err != nil {
+		return err
+	}
+
+	err = e.enterNested()
+	if err != nil {
+		return err
+	}
+	defer e.exitNested()
+
+	err = e.enc.EncodeTagHead(cborTagComposite)
+	if err != nil {
+		return err
+	}
+
+	err = e.enterNested()
+	if err != nil {
+		return err
+	}
+	defer e.exitNested()
+
+	err = e.enc.EncodeArrayHead(2)
+	if err != nil {
+		return err
+	}
+
+	index, err := e.definitionIndex(kind, typeID, fieldNames)
+	if err != nil {
+		return err
+	}
+
+	err = e.enc.EncodeUint64(index)
+	if err != nil {
+		return err
+	}
+
+	err = e.enterNested()
+	if err != nil {
+		return err
+	}
+	defer e.exitNested()
+
+	err = e.enc.EncodeArrayHead(uint64(len(fields)))
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		err = e.encode(field)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// definitionIndex returns the index of the definition of the given composite type,
+// and adds a new definition if the type was not encountered before.
+//
+func (e *valueEncoder) definitionIndex(kind compositeKind, typeID string, fieldNames []string) (uint64, error) {
+	if index, ok := e.definitionIndices[typeID]; ok {
+		existing := e.definitions[index]
+		if existing.kind != kind || !equalFieldNames(existing.fieldNames, fieldNames) {
+			return 0, fmt.Errorf("conflicting definitions of composite type %s", typeID)
+		}
+
+		return index, nil
+	}
+
+	index := uint64(len(e.definitions))
+
+	e.definitions = append(
+		e.definitions,
+		definition{
+			kind:       kind,
+			typeID:     typeID,
+			fieldNames: fieldNames,
+		},
+	)
+	e.definitionIndices[typeID] = index
+
+	return index, nil
+}
+
+func (e *valueEncoder) encodePath(v cadence.Path) error {
+	err := e.enterNested()
+	if err != nil {
+		return err
+	}
+	defer e.exitNested()
+
+	err = e.enc.EncodeArrayHead(2)
+	if err != nil {
+		return err
+	}
+
+	err = e.enc.EncodeString(v.Domain)
+	if err != nil {
+		return err
+	}
+
+	return e.enc.EncodeString(v.Identifier)
+}
+
+func (e *valueEncoder) encodeLink(v cadence.Link) error {
+	err := e.enterNested()
+	if err != nil {
+		return err
+	}
+	defer e.exitNested()
+
+	err = e.enc.EncodeTagHead(cborTagLink)
+	if err != nil {
+		return err
+	}
+
+	err = e.enterNested()
+	if err != nil {
+		return err
+	}
+	defer e.exitNested()
+
+	err = e.enc.EncodeArrayHead(2)
+	if err != nil {
+		return err
+	}
+
+	err = e.encodePath(v.TargetPath)
+	if err != nil {
+		return err
+	}
+
+	return e.enc.EncodeString(v.BorrowType)
+}
+
+func (e *valueEncoder) encodeCapability(v cadence.Capability) error {
+	err := e.enterNested()
+	if err != nil {
+		return err
+	}
+	defer e.exitNested()
+
+	err = e.enc.EncodeTagHead(cborTagCapability)
+	if err != nil {
+		return err
+	}
+
+	err = e.enterNested()
+	if err != nil {
+		return err
+	}
+	defer e.exitNested()
+
+	err = e.enc.EncodeArrayHead(3)
+	if err != nil {
+		return err
+	}
+
+	err = e.encodePath(v.Path)
+	if err != nil {
+		return err
+	}
+
+	err = e.enc.EncodeBytes(v.Address.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return e.enc.EncodeString(v.BorrowType)
+}
+
+// nonFunctionFieldNames returns the names of the given fields,
+// excluding function fields, which have no values.
+//
+func nonFunctionFieldNames(fields []cadence.Field) []string {
+	names := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		if _, ok := field.Type.(cadence.Function); ok {
+			continue
+		}
+		names = append(names, field.Identifier)
+	}
+
+	return names
+}
+
+func equalFieldNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i, name := range a {
+		if b[i] != name {
+			return false
+		}
+	}
+
+	return true
+}
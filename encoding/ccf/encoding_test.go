@@ -0,0 +1,489 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ccf_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/encoding/ccf"
+	"github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestEncodeAndDecode(t *testing.T) {
+
+	t.Parallel()
+
+	bigInt, ok := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	require.True(t, ok)
+
+	int128, err := cadence.NewInt128FromBig(big.NewInt(-42))
+	require.NoError(t, err)
+
+	uint256, err := cadence.NewUInt256FromBig(new(big.Int).Lsh(big.NewInt(1), 200))
+	require.NoError(t, err)
+
+	fix64, err := cadence.NewFix64("-1.5")
+	require.NoError(t, err)
+
+	ufix64, err := cadence.NewUFix64("2.25")
+	require.NoError(t, err)
+
+	path := cadence.Path{
+		Domain:     "storage",
+		Identifier: "foo",
+	}
+
+	values := map[string]cadence.Value{
+		"Void":         cadence.NewVoid(),
+		"nil":          cadence.NewOptional(nil),
+		"Some":         cadence.NewOptional(cadence.NewInt(1)),
+		"nested Some":  cadence.NewOptional(cadence.NewOptional(nil)),
+		"Bool":         cadence.NewBool(true),
+		"String":       cadence.String("hello, 世界"),
+		"empty String": cadence.String(""),
+		"Bytes":        cadence.NewBytes([]byte{1, 2, 3}),
+		"Address":      cadence.BytesToAddress([]byte{1, 2, 3, 4, 5}),
+		"Int":          cadence.NewIntFromBig(bigInt),
+		"Int8":         cadence.NewInt8(-128),
+		"Int16":        cadence.NewInt16(32767),
+		"Int32":        cadence.NewInt32(-42),
+		"Int64":        cadence.NewInt64(-9223372036854775808),
+		"Int128":       int128,
+		"Int256":       cadence.NewInt256(42),
+		"UInt":         cadence.NewUInt(42),
+		"UInt8":        cadence.NewUInt8(255),
+		"UInt16":       cadence.NewUInt16(42),
+		"UInt32":       cadence.NewUInt32(42),
+		"UInt64":       cadence.NewUInt64(18446744073709551615),
+		"UInt128":      cadence.NewUInt128(42),
+		"UInt256":      uint256,
+		"Word8":        cadence.NewWord8(42),
+		"Word16":       cadence.NewWord16(42),
+		"Word32":       cadence.NewWord32(42),
+		"Word64":       cadence.NewWord64(42),
+		"Fix64":        fix64,
+		"UFix64":       ufix64,
+		"Array": cadence.NewArray([]cadence.Value{
+			cadence.NewInt(1),
+			cadence.String("two"),
+			cadence.NewArray([]cadence.Value{}),
+		}),
+		"Dictionary": cadence.NewDictionary([]cadence.KeyValuePair{
+			{Key: cadence.String("a"), Value: cadence.NewInt(1)},
+			{Key: cadence.String("b"), Value: cadence.NewOptional(nil)},
+		}),
+		"Path": path,
+		"Link": cadence.NewLink(path, "&Int"),
+		"Type": cadence.TypeValue{StaticType: "Int"},
+		"Capability": cadence.Capability{
+			Path:       path,
+			Address:    cadence.BytesToAddress([]byte{1}),
+			BorrowType: "&Int",
+		},
+	}
+
+	for name, value := range values {
+		value := value
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			for _, options := range [][]ccf.EncoderOption{
+				nil,
+				{ccf.WithSchemaReferencedEncoding()},
+			} {
+				encoded, err := ccf.Encode(value, options...)
+				require.NoError(t, err)
+
+				decoded, err := ccf.Decode(encoded)
+				require.NoError(t, err)
+
+				assert.Equal(t, value, decoded)
+			}
+		})
+	}
+}
+
+func TestEncodeLayout(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("self-describing", func(t *testing.T) {
+
+		t.Parallel()
+
+		encoded, err := ccf.Encode(cadence.NewBool(true))
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]byte{
+				// tag: self-describing
+				0xd8, 0x80,
+				// array, 2 elements
+				0x82,
+				// array, 0 definitions
+				0x80,
+				// true
+				0xf5,
+			},
+			encoded,
+		)
+	})
+
+	t.Run("schema-referenced", func(t *testing.T) {
+
+		t.Parallel()
+
+		encoded, err := ccf.Encode(cadence.NewInt8(1), ccf.WithSchemaReferencedEncoding())
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]byte{
+				// tag: schema-referenced
+				0xd8, 0x81,
+				// array, 2 elements
+				0x82,
+				// array, 0 type IDs
+				0x80,
+				// tag: Int8
+				0xd8, 0x99,
+				// 1
+				0x01,
+			},
+			encoded,
+		)
+	})
+}
+
+var testEventType = &cadence.EventType{
+	Location:            utils.TestLocation,
+	QualifiedIdentifier: "Transfer",
+	Fields: []cadence.Field{
+		{
+			Identifier: "from",
+			Type:       cadence.OptionalType{Type: cadence.AddressType{}},
+		},
+		{
+			Identifier: "to",
+			Type:       cadence.OptionalType{Type: cadence.AddressType{}},
+		},
+		{
+			Identifier: "amount",
+			Type:       cadence.UFix64Type{},
+		},
+	},
+}
+
+func newTestEvent(amount uint64) cadence.Event {
+	return cadence.NewEvent([]cadence.Value{
+		cadence.NewOptional(cadence.BytesToAddress([]byte{1})),
+		cadence.NewOptional(cadence.BytesToAddress([]byte{2})),
+		cadence.UFix64(amount),
+	}).WithType(testEventType)
+}
+
+func TestEncodeComposite(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("self-describing", func(t *testing.T) {
+
+		t.Parallel()
+
+		structType := &cadence.StructType{
+			Location:            utils.TestLocation,
+			QualifiedIdentifier: "Foo",
+			Fields: []cadence.Field{
+				{
+					Identifier: "bar",
+					Type:       cadence.IntType{},
+				},
+				{
+					Identifier: "baz",
+					Type:       cadence.Function{},
+				},
+			},
+		}
+
+		newStruct := func(bar int) cadence.Value {
+			return cadence.NewStruct([]cadence.Value{
+				cadence.NewInt(bar),
+			}).WithType(structType)
+		}
+
+		value := cadence.NewArray([]cadence.Value{
+			newStruct(1),
+			newStruct(2),
+		})
+
+		encoded, err := ccf.Encode(value)
+		require.NoError(t, err)
+
+		decoded, err := ccf.Decode(encoded)
+		require.NoError(t, err)
+
+		// Like for JSON-Cadence, the decoded type only has the fields with values,
+		// and the types of the fields are the types of the values
+
+		expectedStructType := &cadence.StructType{
+			Location:            utils.TestLocation,
+			QualifiedIdentifier: "Foo",
+			Fields: []cadence.Field{
+				{
+					Identifier: "bar",
+					Type:       cadence.IntType{},
+				},
+			},
+		}
+
+		assert.Equal(t,
+			cadence.NewArray([]cadence.Value{
+				cadence.NewStruct([]cadence.Value{cadence.NewInt(1)}).WithType(expectedStructType),
+				cadence.NewStruct([]cadence.Value{cadence.NewInt(2)}).WithType(expectedStructType),
+			}),
+			decoded,
+		)
+	})
+
+	t.Run("schema-referenced", func(t *testing.T) {
+
+		t.Parallel()
+
+		event := newTestEvent(100)
+
+		encoded, err := ccf.Encode(event, ccf.WithSchemaReferencedEncoding())
+		require.NoError(t, err)
+
+		decoded, err := ccf.Decode(encoded, ccf.WithSchema(ccf.NewSchema(testEventType)))
+		require.NoError(t, err)
+
+		assert.Equal(t, event, decoded)
+	})
+
+	t.Run("schema-referenced, missing schema", func(t *testing.T) {
+
+		t.Parallel()
+
+		encoded, err := ccf.Encode(newTestEvent(100), ccf.WithSchemaReferencedEncoding())
+		require.NoError(t, err)
+
+		_, err = ccf.Decode(encoded)
+		require.Error(t, err)
+	})
+
+	t.Run("schema-referenced, field count mismatch", func(t *testing.T) {
+
+		t.Parallel()
+
+		encoded, err := ccf.Encode(newTestEvent(100), ccf.WithSchemaReferencedEncoding())
+		require.NoError(t, err)
+
+		schemaType := &cadence.EventType{
+			Location:            testEventType.Location,
+			QualifiedIdentifier: testEventType.QualifiedIdentifier,
+			Fields:              testEventType.Fields[:2],
+		}
+
+		_, err = ccf.Decode(encoded, ccf.WithSchema(ccf.NewSchema(schemaType)))
+		require.ErrorIs(t, err, ccf.ErrInvalidCCF)
+	})
+
+	t.Run("conflicting definitions", func(t *testing.T) {
+
+		t.Parallel()
+
+		otherEventType := &cadence.EventType{
+			Location:            testEventType.Location,
+			QualifiedIdentifier: testEventType.QualifiedIdentifier,
+			Fields:              testEventType.Fields[:1],
+		}
+
+		_, err := ccf.Encode(cadence.NewArray([]cadence.Value{
+			newTestEvent(100),
+			cadence.NewEvent([]cadence.Value{
+				cadence.NewOptional(nil),
+			}).WithType(otherEventType),
+		}))
+		require.Error(t, err)
+	})
+}
+
+func TestEncodeLimits(t *testing.T) {
+
+	t.Parallel()
+
+	nestedArray := func(levels int) cadence.Value {
+		value := cadence.NewArray([]cadence.Value{})
+		for i := 1; i < levels; i++ {
+			value = cadence.NewArray([]cadence.Value{value})
+		}
+		return value
+	}
+
+	roundTrip := func(t *testing.T, value cadence.Value) {
+		encoded, err := ccf.Encode(value)
+		require.NoError(t, err)
+
+		decoded, err := ccf.Decode(encoded)
+		require.NoError(t, err)
+
+		assert.Equal(t, value, decoded)
+	}
+
+	t.Run("large array", func(t *testing.T) {
+
+		t.Parallel()
+
+		values := make([]cadence.Value, 131_073)
+		for i := range values {
+			values[i] = cadence.UInt8(i)
+		}
+
+		roundTrip(t, cadence.NewArray(values))
+	})
+
+	t.Run("large dictionary", func(t *testing.T) {
+
+		t.Parallel()
+
+		pairs := make([]cadence.KeyValuePair, 70_000)
+		for i := range pairs {
+			pairs[i] = cadence.KeyValuePair{
+				Key:   cadence.UInt32(i),
+				Value: cadence.Bool(true),
+			}
+		}
+
+		roundTrip(t, cadence.NewDictionary(pairs))
+	})
+
+	t.Run("nested", func(t *testing.T) {
+
+		t.Parallel()
+
+		roundTrip(t, nestedArray(40))
+
+		var value cadence.Value = cadence.NewInt(1)
+		for i := 0; i < 40; i++ {
+			value = cadence.NewOptional(
+				cadence.NewDictionary([]cadence.KeyValuePair{
+					{
+						Key:   cadence.String("a"),
+						Value: cadence.NewArray([]cadence.Value{value}),
+					},
+				}),
+			)
+		}
+
+		roundTrip(t, value)
+	})
+
+	// The value is nested in the tag and the array of the encoding,
+	// and each array is one nested level
+
+	const maxNestedLevels = math.MaxInt16
+
+	t.Run("maximum nesting", func(t *testing.T) {
+
+		t.Parallel()
+
+		roundTrip(t, nestedArray(maxNestedLevels-2))
+	})
+
+	t.Run("exceeding nesting", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ccf.Encode(nestedArray(maxNestedLevels - 1))
+		require.Error(t, err)
+	})
+}
+
+func TestEncodeSize(t *testing.T) {
+
+	t.Parallel()
+
+	events := make([]cadence.Value, 100)
+	for i := range events {
+		events[i] = newTestEvent(uint64(i))
+	}
+
+	value := cadence.NewArray(events)
+
+	jsonEncoded, err := json.Encode(value)
+	require.NoError(t, err)
+
+	selfDescribing, err := ccf.Encode(value)
+	require.NoError(t, err)
+
+	schemaReferenced, err := ccf.Encode(value, ccf.WithSchemaReferencedEncoding())
+	require.NoError(t, err)
+
+	assert.Less(t, len(selfDescribing)*5, len(jsonEncoded))
+	assert.Less(t, len(schemaReferenced), len(selfDescribing))
+}
+
+func TestDecodeInvalid(t *testing.T) {
+
+	t.Parallel()
+
+	for name, encoded := range map[string][]byte{
+		"unknown mode": {0xd8, 0x82, 0x82, 0x80, 0xf5},
+		"unknown tag":  {0xd8, 0x81, 0x82, 0x80, 0xd8, 0x9f, 0x01},
+		// Int8 200
+		"out of range": {0xd8, 0x81, 0x82, 0x80, 0xd8, 0x99, 0x18, 0xc8},
+		// Dictionary with one element
+		"odd dictionary": {0xd8, 0x81, 0x82, 0x80, 0xd8, 0x8b, 0x81, 0xf5},
+		// Address with one byte
+		"short address": {0xd8, 0x81, 0x82, 0x80, 0xd8, 0x8a, 0x41, 0x01},
+		// Composite referencing definition 0, without definitions
+		"unknown definition": {0xd8, 0x80, 0x82, 0x80, 0xd8, 0x8c, 0x82, 0x00, 0x80},
+		// map
+		"unexpected type": {0xd8, 0x81, 0x82, 0x80, 0xa0},
+	} {
+		encoded := encoded
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			_, err := ccf.Decode(encoded)
+			require.ErrorIs(t, err, ccf.ErrInvalidCCF)
+		})
+	}
+
+	t.Run("truncated", func(t *testing.T) {
+
+		t.Parallel()
+
+		encoded, err := ccf.Encode(newTestEvent(100))
+		require.NoError(t, err)
+
+		_, err = ccf.Decode(encoded[:len(encoded)-1])
+		require.Error(t, err)
+	})
+}
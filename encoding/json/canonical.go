@@ -0,0 +1,198 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/onflow/cadence"
+)
+
+// ErrNonCanonicalEncoding is returned by VerifyCanonical
+// if the given bytes are a valid encoding of a value, but not its canonical encoding.
+var ErrNonCanonicalEncoding = errors.New("json-cdc: encoding is not canonical")
+
+// EncodeCanonical returns the canonical JSON-encoded representation of the given value.
+//
+// The canonical encoding of a value is its JSON-Cadence encoding with the following restrictions,
+// which guarantee that equal values have byte-for-byte equal encodings:
+//
+//   - Objects have no insignificant whitespace, and there is no trailing newline.
+//   - Object keys are in the order defined by the specification, e.g. "type" before "value".
+//   - Dictionary entries are sorted by the canonical encoding of their keys,
+//     in lexicographic byte order. Dictionaries must not have duplicate keys.
+//   - Composite fields are in the order of the fields of the composite type.
+//   - Numbers have no leading zeros and no plus sign,
+//     and fixed-point numbers have exactly 8 fractional digits.
+//   - Strings must be valid UTF-8.
+//
+// This function returns an error if the Cadence value cannot be represented as canonical JSON.
+func EncodeCanonical(value cadence.Value) ([]byte, error) {
+	return Encode(value, WithCanonicalEncoding())
+}
+
+// VerifyCanonical checks that the given bytes are the canonical encoding of a Cadence value.
+//
+// This function returns an error if the bytes are not a valid encoding of a value,
+// and ErrNonCanonicalEncoding if the bytes are a valid, but not the canonical encoding.
+func VerifyCanonical(b []byte) error {
+	value, err := Decode(b)
+	if err != nil {
+		return err
+	}
+
+	canonical, err := EncodeCanonical(value)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(b, canonical) {
+		return ErrNonCanonicalEncoding
+	}
+
+	return nil
+}
+
+func (e *Encoder) encodeCanonical(value cadence.Value) error {
+	preparedValue := Prepare(canonicalize(value))
+
+	b, err := json.Marshal(&preparedValue)
+	if err != nil {
+		return err
+	}
+
+	_, err = e.w.Write(b)
+	return err
+}
+
+// canonicalize returns a copy of the given value
+// in which all dictionary entries are sorted canonically.
+//
+// The given value is not modified.
+//
+func canonicalize(value cadence.Value) cadence.Value {
+	switch value := value.(type) {
+	case cadence.String:
+		if !utf8.ValidString(string(value)) {
+			panic(fmt.Errorf("invalid UTF-8 in string: %q", string(value)))
+		}
+		return value
+
+	case cadence.Optional:
+		if value.Value != nil {
+			value.Value = canonicalize(value.Value)
+		}
+		return value
+
+	case cadence.Array:
+		value.Values = canonicalizeValues(value.Values)
+		return value
+
+	case cadence.Dictionary:
+		value.Pairs = canonicalizePairs(value.Pairs)
+		return value
+
+	case cadence.Struct:
+		value.Fields = canonicalizeValues(value.Fields)
+		return value
+
+	case cadence.Resource:
+		value.Fields = canonicalizeValues(value.Fields)
+		return value
+
+	case cadence.Event:
+		value.Fields = canonicalizeValues(value.Fields)
+		return value
+
+	case cadence.Contract:
+		value.Fields = canonicalizeValues(value.Fields)
+		return value
+
+	case cadence.Enum:
+		value.Fields = canonicalizeValues(value.Fields)
+		return value
+
+	default:
+		return value
+	}
+}
+
+func canonicalizeValues(values []cadence.Value) []cadence.Value {
+	if values == nil {
+		return nil
+	}
+
+	result := make([]cadence.Value, len(values))
+	for i, value := range values {
+		result[i] = canonicalize(value)
+	}
+	return result
+}
+
+func canonicalizePairs(pairs []cadence.KeyValuePair) []cadence.KeyValuePair {
+	if pairs == nil {
+		return nil
+	}
+
+	type encodedPair struct {
+		encodedKey []byte
+		pair       cadence.KeyValuePair
+	}
+
+	encodedPairs := make([]encodedPair, len(pairs))
+
+	for i, pair := range pairs {
+		key := canonicalize(pair.Key)
+
+		preparedKey := Prepare(key)
+		encodedKey, err := json.Marshal(&preparedKey)
+		if err != nil {
+			panic(err)
+		}
+
+		encodedPairs[i] = encodedPair{
+			encodedKey: encodedKey,
+			pair: cadence.KeyValuePair{
+				Key:   key,
+				Value: canonicalize(pair.Value),
+			},
+		}
+	}
+
+	sort.Slice(encodedPairs, func(i, j int) bool {
+		return bytes.Compare(encodedPairs[i].encodedKey, encodedPairs[j].encodedKey) < 0
+	})
+
+	result := make([]cadence.KeyValuePair, len(encodedPairs))
+
+	for i, encodedPair := range encodedPairs {
+		if i > 0 && bytes.Equal(encodedPairs[i-1].encodedKey, encodedPair.encodedKey) {
+			panic(fmt.Errorf("duplicate dictionary key: %s", encodedPair.encodedKey))
+		}
+
+		result[i] = encodedPair.pair
+	}
+
+	return result
+}
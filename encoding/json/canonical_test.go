@@ -0,0 +1,207 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestEncodeCanonical(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("dictionary", func(t *testing.T) {
+
+		t.Parallel()
+
+		// integer keys are sorted by their encoding, not numerically
+
+		dictionary := cadence.NewDictionary([]cadence.KeyValuePair{
+			{Key: cadence.NewInt(9), Value: cadence.String("nine")},
+			{Key: cadence.NewInt(10), Value: cadence.String("ten")},
+			{Key: cadence.NewInt(1), Value: cadence.String("one")},
+		})
+
+		actual, err := json.EncodeCanonical(dictionary)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			`{"type":"Dictionary","value":[`+
+				`{"key":{"type":"Int","value":"1"},"value":{"type":"String","value":"one"}},`+
+				`{"key":{"type":"Int","value":"10"},"value":{"type":"String","value":"ten"}},`+
+				`{"key":{"type":"Int","value":"9"},"value":{"type":"String","value":"nine"}}`+
+				`]}`,
+			string(actual),
+		)
+
+		// the given value is not modified
+
+		assert.Equal(t, cadence.NewInt(9), dictionary.Pairs[0].Key)
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+
+		t.Parallel()
+
+		structType := &cadence.StructType{
+			Location:            utils.TestLocation,
+			QualifiedIdentifier: "Foo",
+			Fields: []cadence.Field{
+				{
+					Identifier: "names",
+					Type:       cadence.DictionaryType{KeyType: cadence.StringType{}, ElementType: cadence.IntType{}},
+				},
+			},
+		}
+
+		newValue := func(pairs ...cadence.KeyValuePair) cadence.Value {
+			return cadence.NewOptional(
+				cadence.NewArray([]cadence.Value{
+					cadence.NewStruct([]cadence.Value{
+						cadence.NewDictionary(pairs),
+					}).WithType(structType),
+				}),
+			)
+		}
+
+		a := cadence.KeyValuePair{Key: cadence.String("a"), Value: cadence.NewInt(1)}
+		b := cadence.KeyValuePair{Key: cadence.String("b"), Value: cadence.NewInt(2)}
+		c := cadence.KeyValuePair{Key: cadence.String("c"), Value: cadence.NewInt(3)}
+
+		expected, err := json.EncodeCanonical(newValue(a, b, c))
+		require.NoError(t, err)
+
+		for _, value := range []cadence.Value{
+			newValue(c, b, a),
+			newValue(b, a, c),
+			newValue(a, c, b),
+		} {
+			actual, err := json.EncodeCanonical(value)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, actual)
+		}
+
+		require.NoError(t, json.VerifyCanonical(expected))
+	})
+
+	t.Run("no trailing newline", func(t *testing.T) {
+
+		t.Parallel()
+
+		actual, err := json.EncodeCanonical(cadence.NewInt(1))
+		require.NoError(t, err)
+
+		assert.Equal(t, `{"type":"Int","value":"1"}`, string(actual))
+	})
+
+	t.Run("encoder option", func(t *testing.T) {
+
+		t.Parallel()
+
+		var buffer bytes.Buffer
+		encoder := json.NewEncoder(&buffer, json.WithCanonicalEncoding())
+
+		err := encoder.Encode(cadence.Fix64(150000000))
+		require.NoError(t, err)
+
+		assert.Equal(t, `{"type":"Fix64","value":"1.50000000"}`, buffer.String())
+	})
+
+	t.Run("duplicate dictionary keys", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := json.EncodeCanonical(
+			cadence.NewDictionary([]cadence.KeyValuePair{
+				{Key: cadence.String("a"), Value: cadence.NewInt(1)},
+				{Key: cadence.String("a"), Value: cadence.NewInt(2)},
+			}),
+		)
+		require.Error(t, err)
+	})
+
+	t.Run("invalid UTF-8", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := json.EncodeCanonical(cadence.String("\xbd\xb2"))
+		require.Error(t, err)
+	})
+}
+
+func TestVerifyCanonical(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("canonical", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := json.VerifyCanonical([]byte(
+			`{"type":"Dictionary","value":[` +
+				`{"key":{"type":"String","value":"a"},"value":{"type":"UFix64","value":"0.00000001"}},` +
+				`{"key":{"type":"String","value":"b"},"value":{"type":"Optional","value":null}}` +
+				`]}`,
+		))
+		require.NoError(t, err)
+	})
+
+	for name, encoded := range map[string]string{
+		"unsorted dictionary": `{"type":"Dictionary","value":[` +
+			`{"key":{"type":"String","value":"b"},"value":{"type":"Int","value":"2"}},` +
+			`{"key":{"type":"String","value":"a"},"value":{"type":"Int","value":"1"}}` +
+			`]}`,
+		"whitespace":          `{"type": "Int", "value": "1"}`,
+		"trailing newline":    "{\"type\":\"Int\",\"value\":\"1\"}\n",
+		"key order":           `{"value":"1","type":"Int"}`,
+		"leading zero":        `{"type":"UInt8","value":"01"}`,
+		"fractional digits":   `{"type":"UFix64","value":"1.5"}`,
+		"escaped character":   `{"type":"String","value":"\u0061"}`,
+		"trailing data":       `{"type":"Void"}{"type":"Void"}`,
+		"uppercase hex digit": `{"type":"Address","value":"0x000000000000000A"}`,
+	} {
+		encoded := encoded
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			err := json.VerifyCanonical([]byte(encoded))
+			require.ErrorIs(t, err, json.ErrNonCanonicalEncoding)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := json.VerifyCanonical([]byte(`{"type":"Foo"}`))
+		require.Error(t, err)
+		require.NotErrorIs(t, err, json.ErrNonCanonicalEncoding)
+	})
+}
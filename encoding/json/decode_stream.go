@@ -0,0 +1,250 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/onflow/cadence"
+)
+
+// A StreamDecoder decodes a stream of JSON-encoded representations of Cadence values
+// from an io.Reader, one value at a time.
+//
+// The elements of top-level arrays are decoded incrementally:
+// Instead of decoding the whole array, each element is returned as a separate value.
+// This allows decoding large arrays, e.g. of events, without loading them into memory at once.
+//
+// The stream may contain multiple top-level values, e.g. newline-delimited JSON.
+//
+type StreamDecoder struct {
+	dec *json.Decoder
+	// inArray is true if the decoder is inside the value of a top-level array
+	inArray bool
+	// buffered are the already decoded values which are returned next
+	buffered []cadence.Value
+}
+
+// NewStreamDecoder initializes a StreamDecoder that will decode JSON-encoded bytes from the
+// given io.Reader.
+func NewStreamDecoder(r io.Reader) *StreamDecoder {
+	return &StreamDecoder{
+		dec: json.NewDecoder(r),
+	}
+}
+
+// Next reads JSON-encoded bytes from the io.Reader and decodes the next Cadence value.
+//
+// If the next top-level value is an array, the elements of the array are returned
+// by this and subsequent calls, instead of the array itself.
+//
+// This function returns io.EOF if there are no more values in the stream,
+// and an error if the bytes represent JSON that is malformed
+// or does not conform to the JSON Cadence specification.
+//
+func (d *StreamDecoder) Next() (value cadence.Value, err error) {
+
+	// capture panics that occur during decoding
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr, isError := r.(error)
+			if !isError {
+				panic(r)
+			}
+
+			err = fmt.Errorf("failed to decode value: %w", panicErr)
+		}
+	}()
+
+	for {
+		if len(d.buffered) > 0 {
+			value = d.buffered[0]
+			d.buffered = d.buffered[1:]
+			return value, nil
+		}
+
+		if d.inArray {
+			if d.dec.More() {
+				return d.nextArrayElement()
+			}
+
+			err = d.endArray()
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		var done bool
+		value, done, err = d.nextTopLevelValue()
+		if err != nil || done {
+			return nil, err
+		}
+
+		if value != nil {
+			return value, nil
+		}
+	}
+}
+
+// nextArrayElement decodes the next element of the current top-level array.
+//
+func (d *StreamDecoder) nextArrayElement() (cadence.Value, error) {
+	var valueJSON interface{}
+
+	err := d.dec.Decode(&valueJSON)
+	if err != nil {
+		return nil, jsonStructureError(err)
+	}
+
+	return decodeJSON(valueJSON), nil
+}
+
+// endArray consumes the end of the current top-level array:
+// The end of the array's value and the end of the array object.
+//
+func (d *StreamDecoder) endArray() error {
+	err := d.expectDelim(']')
+	if err != nil {
+		return err
+	}
+
+	// the array object should only contain the keys "type" and "value"
+	if d.dec.More() {
+		// TODO: improve error message
+		panic(ErrInvalidJSONCadence)
+	}
+
+	err = d.expectDelim('}')
+	if err != nil {
+		return err
+	}
+
+	d.inArray = false
+
+	return nil
+}
+
+// nextTopLevelValue decodes the next top-level value.
+//
+// If the value is an array whose type is encoded before its value,
+// the decoder starts decoding the array incrementally, and no value is returned.
+//
+func (d *StreamDecoder) nextTopLevelValue() (value cadence.Value, done bool, err error) {
+
+	token, err := d.dec.Token()
+	if err == io.EOF {
+		return nil, true, io.EOF
+	}
+	if err != nil {
+		return nil, false, jsonStructureError(err)
+	}
+
+	if token != json.Delim('{') {
+		// TODO: improve error message
+		panic(ErrInvalidJSONCadence)
+	}
+
+	obj := jsonObject{}
+
+	for d.dec.More() {
+		key, err := d.readKey()
+		if err != nil {
+			return nil, false, err
+		}
+
+		// if the type of an array is known before its value,
+		// the value can be decoded incrementally
+
+		if key == valueKey && len(obj) == 1 && obj[typeKey] == arrayTypeStr {
+			err = d.expectDelim('[')
+			if err != nil {
+				return nil, false, err
+			}
+
+			d.inArray = true
+			return nil, false, nil
+		}
+
+		var valueJSON interface{}
+		err = d.dec.Decode(&valueJSON)
+		if err != nil {
+			return nil, false, jsonStructureError(err)
+		}
+
+		obj[key] = valueJSON
+	}
+
+	err = d.expectDelim('}')
+	if err != nil {
+		return nil, false, err
+	}
+
+	value = decodeJSON(map[string]interface{}(obj))
+
+	// arrays whose value was encoded before their type
+	// could not be decoded incrementally, return their elements one by one
+
+	if array, ok := value.(cadence.Array); ok {
+		d.buffered = array.Values
+		return nil, false, nil
+	}
+
+	return value, false, nil
+}
+
+func (d *StreamDecoder) readKey() (string, error) {
+	token, err := d.dec.Token()
+	if err != nil {
+		return "", jsonStructureError(err)
+	}
+
+	key, ok := token.(string)
+	if !ok {
+		// TODO: improve error message
+		panic(ErrInvalidJSONCadence)
+	}
+
+	return key, nil
+}
+
+func (d *StreamDecoder) expectDelim(delim json.Delim) error {
+	token, err := d.dec.Token()
+	if err != nil {
+		return jsonStructureError(err)
+	}
+
+	if token != delim {
+		// TODO: improve error message
+		panic(ErrInvalidJSONCadence)
+	}
+
+	return nil
+}
+
+func jsonStructureError(err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return fmt.Errorf("json-cdc: failed to decode valid JSON structure: %w", err)
+}
@@ -0,0 +1,221 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2020 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json_test
+
+import (
+	goJSON "encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/encoding/json"
+)
+
+func decodeStream(decoder *json.StreamDecoder) (values []cadence.Value, err error) {
+	for {
+		value, err := decoder.Next()
+		if err == io.EOF {
+			return values, nil
+		}
+		if err != nil {
+			return values, err
+		}
+		values = append(values, value)
+	}
+}
+
+func TestStreamDecoder(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("array", func(t *testing.T) {
+
+		t.Parallel()
+
+		values := []cadence.Value{
+			cadence.NewInt(1),
+			cadence.String("two"),
+			cadence.NewArray([]cadence.Value{
+				cadence.NewInt(3),
+			}),
+		}
+
+		encoded, err := json.Encode(cadence.NewArray(values))
+		require.NoError(t, err)
+
+		decoder := json.NewStreamDecoder(strings.NewReader(string(encoded)))
+
+		actual, err := decodeStream(decoder)
+		require.NoError(t, err)
+
+		assert.Equal(t, values, actual)
+	})
+
+	t.Run("empty array", func(t *testing.T) {
+
+		t.Parallel()
+
+		decoder := json.NewStreamDecoder(strings.NewReader(`{"type":"Array","value":[]}`))
+
+		actual, err := decodeStream(decoder)
+		require.NoError(t, err)
+
+		assert.Empty(t, actual)
+	})
+
+	t.Run("multiple values", func(t *testing.T) {
+
+		t.Parallel()
+
+		decoder := json.NewStreamDecoder(strings.NewReader(`
+          {"type":"Int","value":"1"}
+          {"type":"Array","value":[{"type":"Int","value":"2"},{"type":"Int","value":"3"}]}
+          {"type":"Void"}
+          {"type":"Optional","value":{"type":"Array","value":[]}}
+        `))
+
+		actual, err := decodeStream(decoder)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]cadence.Value{
+				cadence.NewInt(1),
+				cadence.NewInt(2),
+				cadence.NewInt(3),
+				cadence.NewVoid(),
+				cadence.NewOptional(cadence.NewArray([]cadence.Value{})),
+			},
+			actual,
+		)
+	})
+
+	t.Run("array value before type", func(t *testing.T) {
+
+		t.Parallel()
+
+		decoder := json.NewStreamDecoder(strings.NewReader(
+			`{"value":[{"type":"Int","value":"1"},{"type":"Int","value":"2"}],"type":"Array"}`,
+		))
+
+		actual, err := decodeStream(decoder)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]cadence.Value{
+				cadence.NewInt(1),
+				cadence.NewInt(2),
+			},
+			actual,
+		)
+	})
+
+	t.Run("incremental", func(t *testing.T) {
+
+		t.Parallel()
+
+		readErr := errors.New("read failed")
+
+		// the reader fails after the first element,
+		// which must be decoded nonetheless
+
+		decoder := json.NewStreamDecoder(
+			io.MultiReader(
+				strings.NewReader(`{"type":"Array","value":[{"type":"Int","value":"1"},`),
+				&failingReader{err: readErr},
+			),
+		)
+
+		actual, err := decodeStream(decoder)
+		require.ErrorIs(t, err, readErr)
+
+		assert.Equal(t,
+			[]cadence.Value{
+				cadence.NewInt(1),
+			},
+			actual,
+		)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+
+		t.Parallel()
+
+		decoder := json.NewStreamDecoder(strings.NewReader(
+			`{"type":"Array","value":[{"type":"Int","value":"1"}`,
+		))
+
+		actual, err := decodeStream(decoder)
+		var syntaxErr *goJSON.SyntaxError
+		require.ErrorAs(t, err, &syntaxErr)
+
+		assert.Equal(t,
+			[]cadence.Value{
+				cadence.NewInt(1),
+			},
+			actual,
+		)
+	})
+
+	t.Run("invalid element", func(t *testing.T) {
+
+		t.Parallel()
+
+		decoder := json.NewStreamDecoder(strings.NewReader(
+			`{"type":"Array","value":[{"type":"Int","value":"1"},{"type":"Foo","value":"2"}]}`,
+		))
+
+		_, err := decodeStream(decoder)
+		require.ErrorIs(t, err, json.ErrInvalidJSONCadence)
+	})
+
+	t.Run("additional array key", func(t *testing.T) {
+
+		t.Parallel()
+
+		decoder := json.NewStreamDecoder(strings.NewReader(
+			`{"type":"Array","value":[],"foo":"bar"}`,
+		))
+
+		_, err := decodeStream(decoder)
+		require.ErrorIs(t, err, json.ErrInvalidJSONCadence)
+	})
+
+	t.Run("not an object", func(t *testing.T) {
+
+		t.Parallel()
+
+		decoder := json.NewStreamDecoder(strings.NewReader(`[]`))
+
+		_, err := decodeStream(decoder)
+		require.ErrorIs(t, err, json.ErrInvalidJSONCadence)
+	})
+}
+
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read(_ []byte) (int, error) {
+	return 0, r.err
+}
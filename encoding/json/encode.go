@@ -34,15 +34,31 @@ import (
 
 // An Encoder converts Cadence values into JSON-encoded bytes.
 type Encoder struct {
-	enc *json.Encoder
+	enc       *json.Encoder
+	w         io.Writer
+	canonical bool
+}
+
+// EncoderOption is an option for an Encoder.
+type EncoderOption func(*Encoder)
+
+// WithCanonicalEncoding returns an encoder option which enables the canonical encoding mode.
+//
+// In the canonical encoding mode, the encoding of a value is deterministic:
+// Equal values always have byte-for-byte equal encodings. See EncodeCanonical.
+//
+func WithCanonicalEncoding() EncoderOption {
+	return func(encoder *Encoder) {
+		encoder.canonical = true
+	}
 }
 
 // Encode returns the JSON-encoded representation of the given value.
 //
 // This function returns an error if the Cadence value cannot be represented as JSON.
-func Encode(value cadence.Value) ([]byte, error) {
+func Encode(value cadence.Value, options ...EncoderOption) ([]byte, error) {
 	var w bytes.Buffer
-	enc := NewEncoder(&w)
+	enc := NewEncoder(&w, options...)
 
 	err := enc.Encode(value)
 	if err != nil {
@@ -54,8 +70,8 @@ func Encode(value cadence.Value) ([]byte, error) {
 
 // MustEncode returns the JSON-encoded representation of the given value, or panics
 // if the value cannot be represented as JSON.
-func MustEncode(value cadence.Value) []byte {
-	b, err := Encode(value)
+func MustEncode(value cadence.Value, options ...EncoderOption) []byte {
+	b, err := Encode(value, options...)
 	if err != nil {
 		panic(err)
 	}
@@ -64,8 +80,17 @@ func MustEncode(value cadence.Value) []byte {
 
 // NewEncoder initializes an Encoder that will write JSON-encoded bytes to the
 // given io.Writer.
-func NewEncoder(w io.Writer) *Encoder {
-	return &Encoder{enc: json.NewEncoder(w)}
+func NewEncoder(w io.Writer, options ...EncoderOption) *Encoder {
+	encoder := &Encoder{
+		enc: json.NewEncoder(w),
+		w:   w,
+	}
+
+	for _, option := range options {
+		option(encoder)
+	}
+
+	return encoder
 }
 
 // Encode writes the JSON-encoded representation of the given value to this
@@ -92,6 +117,10 @@ func (e *Encoder) Encode(value cadence.Value) (err error) {
 		}
 	}()
 
+	if e.canonical {
+		return e.encodeCanonical(value)
+	}
+
 	preparedValue := Prepare(value)
 
 	return e.enc.Encode(&preparedValue)
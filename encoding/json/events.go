@@ -0,0 +1,213 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/onflow/cadence"
+)
+
+var ErrUnknownEventType = errors.New("json-cdc: unknown event type")
+
+// EventDecoder decodes an event into a client-defined value.
+//
+type EventDecoder func(event cadence.Event) (interface{}, error)
+
+type eventRegistration struct {
+	eventType *cadence.EventType
+	decoder   EventDecoder
+}
+
+// EventRegistry decodes JSON-Cadence encoded event payloads,
+// based on the event types, decoders, and Go struct targets registered for their type IDs.
+//
+// Events of a registered type are reconciled with the registered type before they are decoded:
+// Fields which are not declared in the registered type, e.g. fields added by a contract upgrade, are ignored,
+// and missing optional fields are nil.
+//
+// An EventRegistry is safe for concurrent use.
+//
+type EventRegistry struct {
+	mutex         sync.RWMutex
+	registrations map[string]*eventRegistration
+}
+
+func NewEventRegistry() *EventRegistry {
+	return &EventRegistry{
+		registrations: map[string]*eventRegistration{},
+	}
+}
+
+func (r *EventRegistry) registration(typeID string) *eventRegistration {
+	registration, ok := r.registrations[typeID]
+	if !ok {
+		registration = &eventRegistration{}
+		r.registrations[typeID] = registration
+	}
+	return registration
+}
+
+// RegisterType registers the expected type of events with the type's ID.
+//
+func (r *EventRegistry) RegisterType(eventType *cadence.EventType) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	typeID := eventType.ID()
+
+	registration := r.registration(typeID)
+	if registration.eventType != nil {
+		return fmt.Errorf("event type %s is already registered", typeID)
+	}
+
+	registration.eventType = eventType
+
+	return nil
+}
+
+// RegisterDecoder registers the decoder for events with the given type ID.
+//
+func (r *EventRegistry) RegisterDecoder(typeID string, decoder EventDecoder) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	registration := r.registration(typeID)
+	if registration.decoder != nil {
+		return fmt.Errorf("decoder for event type %s is already registered", typeID)
+	}
+
+	registration.decoder = decoder
+
+	return nil
+}
+
+// RegisterTarget registers a Go struct as the target for events with the given type ID.
+//
+// The target is a struct value or a pointer to a struct, which is only used to determine the struct type.
+// Events are decoded into a pointer to a new struct value using cadence.Unmarshal.
+//
+func (r *EventRegistry) RegisterTarget(typeID string, target interface{}) error {
+	targetType := reflect.TypeOf(target)
+	if targetType != nil && targetType.Kind() == reflect.Ptr {
+		targetType = targetType.Elem()
+	}
+	if targetType == nil || targetType.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot register target of type %T for event type %s: target must be a struct", target, typeID)
+	}
+
+	return r.RegisterDecoder(typeID, func(event cadence.Event) (interface{}, error) {
+		result := reflect.New(targetType)
+		err := cadence.Unmarshal(event, result.Interface())
+		if err != nil {
+			return nil, err
+		}
+		return result.Interface(), nil
+	})
+}
+
+// Decode decodes the given JSON-Cadence encoded event payload.
+//
+// The result is the result of the decoder registered for the event's type ID,
+// or the event itself, if only the event type is registered.
+// If nothing is registered for the event's type ID, the error wraps ErrUnknownEventType.
+//
+func (r *EventRegistry) Decode(payload []byte) (interface{}, error) {
+	value, err := Decode(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	event, ok := value.(cadence.Event)
+	if !ok {
+		return nil, fmt.Errorf("payload is not an event: %T", value)
+	}
+
+	return r.DecodeEvent(event)
+}
+
+// DecodeEvent decodes the given event, like Decode.
+//
+func (r *EventRegistry) DecodeEvent(event cadence.Event) (interface{}, error) {
+	if event.EventType == nil {
+		return nil, fmt.Errorf("event has no type")
+	}
+
+	typeID := event.EventType.ID()
+
+	r.mutex.RLock()
+	registration, ok := r.registrations[typeID]
+	r.mutex.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, typeID)
+	}
+
+	if registration.eventType != nil {
+		var err error
+		event, err = reconcileEvent(event, registration.eventType)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if registration.decoder == nil {
+		return event, nil
+	}
+
+	result, err := registration.decoder(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode event %s: %w", typeID, err)
+	}
+
+	return result, nil
+}
+
+// reconcileEvent returns the given event with the fields of the expected event type.
+//
+func reconcileEvent(event cadence.Event, expectedType *cadence.EventType) (cadence.Event, error) {
+	values := make(map[string]cadence.Value, len(event.Fields))
+	for i, field := range event.EventType.Fields {
+		if i < len(event.Fields) {
+			values[field.Identifier] = event.Fields[i]
+		}
+	}
+
+	fields := make([]cadence.Value, len(expectedType.Fields))
+
+	for i, field := range expectedType.Fields {
+		value, ok := values[field.Identifier]
+		if !ok {
+			if _, ok := field.Type.(cadence.OptionalType); !ok {
+				return cadence.Event{}, fmt.Errorf(
+					"event %s is missing field %s",
+					expectedType.ID(),
+					field.Identifier,
+				)
+			}
+			value = cadence.NewOptional(nil)
+		}
+		fields[i] = value
+	}
+
+	return cadence.NewEvent(fields).WithType(expectedType), nil
+}
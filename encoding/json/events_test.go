@@ -0,0 +1,214 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestEventRegistry(t *testing.T) {
+
+	t.Parallel()
+
+	depositType := &cadence.EventType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "Deposit",
+		Fields: []cadence.Field{
+			{Identifier: "amount", Type: cadence.UFix64Type{}},
+			{Identifier: "to", Type: cadence.OptionalType{Type: cadence.AddressType{}}},
+		},
+	}
+
+	// The upgraded contract added the field `memo`, and removed the field `to`
+
+	upgradedDepositType := &cadence.EventType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "Deposit",
+		Fields: []cadence.Field{
+			{Identifier: "amount", Type: cadence.UFix64Type{}},
+			{Identifier: "memo", Type: cadence.StringType{}},
+		},
+	}
+
+	withdrawType := &cadence.EventType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "Withdraw",
+		Fields: []cadence.Field{
+			{Identifier: "amount", Type: cadence.UFix64Type{}},
+		},
+	}
+
+	encode := func(t *testing.T, event cadence.Event) []byte {
+		payload, err := json.Encode(event)
+		require.NoError(t, err)
+		return payload
+	}
+
+	upgradedDepositPayload := encode(t,
+		cadence.NewEvent([]cadence.Value{
+			cadence.UFix64(100),
+			cadence.String("hello"),
+		}).WithType(upgradedDepositType),
+	)
+
+	t.Run("type", func(t *testing.T) {
+
+		t.Parallel()
+
+		registry := json.NewEventRegistry()
+		require.NoError(t, registry.RegisterType(depositType))
+
+		result, err := registry.Decode(upgradedDepositPayload)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			cadence.NewEvent([]cadence.Value{
+				cadence.UFix64(100),
+				cadence.NewOptional(nil),
+			}).WithType(depositType),
+			result,
+		)
+	})
+
+	t.Run("missing non-optional field", func(t *testing.T) {
+
+		t.Parallel()
+
+		registry := json.NewEventRegistry()
+		require.NoError(t, registry.RegisterType(upgradedDepositType))
+
+		payload := encode(t,
+			cadence.NewEvent([]cadence.Value{
+				cadence.UFix64(100),
+				cadence.NewOptional(nil),
+			}).WithType(depositType),
+		)
+
+		_, err := registry.Decode(payload)
+		require.EqualError(t, err, "event S.test.Deposit is missing field memo")
+	})
+
+	t.Run("target", func(t *testing.T) {
+
+		t.Parallel()
+
+		type deposit struct {
+			Amount cadence.UFix64   `cadence:"amount"`
+			To     *cadence.Address `cadence:"to"`
+		}
+
+		type withdraw struct {
+			Amount cadence.UFix64 `cadence:"amount"`
+		}
+
+		registry := json.NewEventRegistry()
+		require.NoError(t, registry.RegisterTarget(depositType.ID(), deposit{}))
+		require.NoError(t, registry.RegisterTarget(withdrawType.ID(), (*withdraw)(nil)))
+
+		result, err := registry.Decode(upgradedDepositPayload)
+		require.NoError(t, err)
+		assert.Equal(t, &deposit{Amount: 100}, result)
+
+		result, err = registry.Decode(
+			encode(t,
+				cadence.NewEvent([]cadence.Value{
+					cadence.UFix64(200),
+				}).WithType(withdrawType),
+			),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, &withdraw{Amount: 200}, result)
+
+		err = registry.RegisterTarget(depositType.ID(), deposit{})
+		require.EqualError(t, err, "decoder for event type S.test.Deposit is already registered")
+
+		err = registry.RegisterTarget("S.test.Other", 1)
+		require.Error(t, err)
+	})
+
+	t.Run("decoder with type", func(t *testing.T) {
+
+		t.Parallel()
+
+		registry := json.NewEventRegistry()
+		require.NoError(t, registry.RegisterType(depositType))
+		require.NoError(t, registry.RegisterDecoder(
+			depositType.ID(),
+			func(event cadence.Event) (interface{}, error) {
+				// The event has the registered type
+				return len(event.Fields), nil
+			},
+		))
+
+		result, err := registry.Decode(upgradedDepositPayload)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result)
+
+		err = registry.RegisterType(depositType)
+		require.EqualError(t, err, "event type S.test.Deposit is already registered")
+	})
+
+	t.Run("decoder error", func(t *testing.T) {
+
+		t.Parallel()
+
+		decoderErr := errors.New("test")
+
+		registry := json.NewEventRegistry()
+		require.NoError(t, registry.RegisterDecoder(
+			depositType.ID(),
+			func(event cadence.Event) (interface{}, error) {
+				return nil, decoderErr
+			},
+		))
+
+		_, err := registry.Decode(upgradedDepositPayload)
+		require.ErrorIs(t, err, decoderErr)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+
+		t.Parallel()
+
+		registry := json.NewEventRegistry()
+		require.NoError(t, registry.RegisterType(withdrawType))
+
+		_, err := registry.Decode(upgradedDepositPayload)
+		require.ErrorIs(t, err, json.ErrUnknownEventType)
+		require.EqualError(t, err, "json-cdc: unknown event type: S.test.Deposit")
+	})
+
+	t.Run("not an event", func(t *testing.T) {
+
+		t.Parallel()
+
+		registry := json.NewEventRegistry()
+
+		_, err := registry.Decode([]byte(`{"type":"Int","value":"1"}`))
+		require.EqualError(t, err, "payload is not an event: cadence.Int")
+	})
+}
@@ -0,0 +1,508 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package schema exports JSON Schemas for Cadence types,
+// which validate JSON-Cadence encoded values of these types.
+//
+// A contract is exported as a document, which contains the schemas of its public composite types,
+// and the argument schemas of its public functions and of the given scripts and transactions.
+// Composite types are defined once in the document's definitions and referenced by type ID.
+//
+// The schemas validate the structure of encoded values, but not all constraints,
+// e.g. integer values are only validated to be decimal numbers, not to be in range.
+//
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// Draft is the JSON Schema dialect of the exported schemas.
+//
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema.
+//
+type Schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Const       string             `json:"const,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Pattern     string             `json:"pattern,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	PrefixItems []*Schema          `json:"prefixItems,omitempty"`
+	MinItems    *int               `json:"minItems,omitempty"`
+	MaxItems    *int               `json:"maxItems,omitempty"`
+	AnyOf       []*Schema          `json:"anyOf,omitempty"`
+}
+
+// Document is the exported schema of a contract and its entry points.
+//
+// The document itself is a JSON Schema which has no constraints,
+// so that its definitions can be referenced by the schemas of the functions.
+//
+type Document struct {
+	Schema    string               `json:"$schema"`
+	Title     string               `json:"title,omitempty"`
+	Defs      map[string]*Schema   `json:"$defs,omitempty"`
+	Functions map[string]*Function `json:"functions,omitempty"`
+}
+
+// Function is the exported signature of a contract function, script, or transaction.
+//
+type Function struct {
+	Kind        string       `json:"kind"`
+	Description string       `json:"description,omitempty"`
+	Parameters  []*Parameter `json:"parameters"`
+	// Arguments is the schema of the array of the JSON-Cadence encoded arguments
+	Arguments *Schema `json:"arguments"`
+	Return    *Schema `json:"return,omitempty"`
+}
+
+// Parameter is an exported function parameter.
+//
+type Parameter struct {
+	Label  string  `json:"label,omitempty"`
+	Name   string  `json:"name"`
+	Schema *Schema `json:"schema"`
+}
+
+const (
+	FunctionKindFunction    = "function"
+	FunctionKindScript      = "script"
+	FunctionKindTransaction = "transaction"
+)
+
+// EntryPoint is a checked script or transaction which is exported along with the contract.
+//
+type EntryPoint struct {
+	Name    string
+	Checker *sema.Checker
+}
+
+// Export exports the schema document for the given checked contract and entry points.
+// The contract may be nil, in which case only the entry points are exported.
+//
+func Export(contract *sema.Checker, entryPoints ...EntryPoint) (*Document, error) {
+	exporter := NewExporter()
+
+	document := &Document{
+		Schema:    Draft,
+		Defs:      exporter.Defs,
+		Functions: map[string]*Function{},
+	}
+
+	if contract != nil {
+		title, err := exporter.exportContract(contract, document.Functions)
+		if err != nil {
+			return nil, err
+		}
+		document.Title = title
+	}
+
+	for _, entryPoint := range entryPoints {
+		function, err := exporter.exportEntryPoint(entryPoint)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := document.Functions[entryPoint.Name]; ok {
+			return nil, fmt.Errorf("duplicate function %s", entryPoint.Name)
+		}
+		document.Functions[entryPoint.Name] = function
+	}
+
+	return document, nil
+}
+
+// Exporter exports schemas for Cadence types.
+// Schemas of composite types are added to the definitions once,
+// and are referenced from other schemas.
+//
+type Exporter struct {
+	Defs    map[string]*Schema
+	results map[sema.TypeID]cadence.Type
+}
+
+func NewExporter() *Exporter {
+	return &Exporter{
+		Defs:    map[string]*Schema{},
+		results: map[sema.TypeID]cadence.Type{},
+	}
+}
+
+func (e *Exporter) exportContract(checker *sema.Checker, functions map[string]*Function) (string, error) {
+
+	var title string
+
+	contractDeclaration := checker.Program.SoleContractDeclaration()
+
+	var export func(declarations []*ast.CompositeDeclaration)
+	export = func(declarations []*ast.CompositeDeclaration) {
+		for _, declaration := range declarations {
+			if !isPublic(declaration.Access) {
+				continue
+			}
+
+			semaType := checker.Elaboration.CompositeDeclarationTypes[declaration]
+			e.TypeSchema(runtime.ExportType(semaType, e.results))
+
+			typeID := string(semaType.ID())
+			e.Defs[typeID].Description = strings.TrimSpace(declaration.DocString)
+
+			export(declaration.Members.Composites())
+		}
+	}
+
+	export(checker.Program.CompositeDeclarations())
+
+	if contractDeclaration == nil {
+		return title, nil
+	}
+
+	title = contractDeclaration.Identifier.Identifier
+
+	contractType := checker.Elaboration.CompositeDeclarationTypes[contractDeclaration]
+
+	for _, functionDeclaration := range contractDeclaration.Members.Functions() {
+		if !isPublic(functionDeclaration.Access) {
+			continue
+		}
+
+		identifier := functionDeclaration.Identifier.Identifier
+
+		member, ok := contractType.Members.Get(identifier)
+		if !ok {
+			return "", fmt.Errorf("missing member for function %s", identifier)
+		}
+
+		functionType, ok := member.TypeAnnotation.Type.(*sema.FunctionType)
+		if !ok {
+			return "", fmt.Errorf("member %s is not a function", identifier)
+		}
+
+		function := e.exportFunction(FunctionKindFunction, functionType.Parameters, functionType.ReturnTypeAnnotation.Type)
+		function.Description = strings.TrimSpace(functionDeclaration.DocString)
+
+		functions[identifier] = function
+	}
+
+	return title, nil
+}
+
+func isPublic(access ast.Access) bool {
+	return access == ast.AccessPublic || access == ast.AccessPublicSettable
+}
+
+func (e *Exporter) exportEntryPoint(entryPoint EntryPoint) (*Function, error) {
+	checker := entryPoint.Checker
+
+	if transactionDeclaration := checker.Program.SoleTransactionDeclaration(); transactionDeclaration != nil {
+		transactionType := checker.Elaboration.TransactionDeclarationTypes[transactionDeclaration]
+		function := e.exportFunction(FunctionKindTransaction, transactionType.Parameters, nil)
+		function.Description = strings.TrimSpace(transactionDeclaration.DocString)
+		return function, nil
+	}
+
+	if functionDeclaration := sema.FunctionEntryPointDeclaration(checker.Program); functionDeclaration != nil {
+		functionType := checker.Elaboration.FunctionDeclarationFunctionTypes[functionDeclaration]
+		function := e.exportFunction(FunctionKindScript, functionType.Parameters, functionType.ReturnTypeAnnotation.Type)
+		function.Description = strings.TrimSpace(functionDeclaration.DocString)
+		return function, nil
+	}
+
+	return nil, fmt.Errorf("entry point %s is neither a script nor a transaction", entryPoint.Name)
+}
+
+func (e *Exporter) exportFunction(kind string, parameters []*sema.Parameter, returnType sema.Type) *Function {
+	function := &Function{
+		Kind:       kind,
+		Parameters: make([]*Parameter, len(parameters)),
+	}
+
+	argumentSchemas := make([]*Schema, len(parameters))
+
+	for i, parameter := range parameters {
+		parameterSchema := e.TypeSchema(runtime.ExportType(parameter.TypeAnnotation.Type, e.results))
+
+		function.Parameters[i] = &Parameter{
+			Label:  parameter.Label,
+			Name:   parameter.Identifier,
+			Schema: parameterSchema,
+		}
+
+		argumentSchemas[i] = parameterSchema
+	}
+
+	function.Arguments = tupleSchema(argumentSchemas)
+
+	if returnType != nil && returnType != sema.VoidType {
+		function.Return = e.TypeSchema(runtime.ExportType(returnType, e.results))
+	}
+
+	return function
+}
+
+// TypeSchema returns the schema for JSON-Cadence encoded values of the given type.
+//
+func (e *Exporter) TypeSchema(t cadence.Type) *Schema {
+	switch t := t.(type) {
+	case cadence.VoidType:
+		return valueSchema("Void", nil)
+
+	case cadence.OptionalType:
+		return valueSchema("Optional", &Schema{
+			AnyOf: []*Schema{
+				{Type: "null"},
+				e.TypeSchema(t.Type),
+			},
+		})
+
+	case cadence.BoolType:
+		return valueSchema("Bool", &Schema{Type: "boolean"})
+
+	case cadence.StringType:
+		return valueSchema("String", &Schema{Type: "string"})
+
+	case cadence.AddressType:
+		return valueSchema("Address", addressSchema())
+
+	case cadence.IntType, cadence.Int8Type, cadence.Int16Type, cadence.Int32Type, cadence.Int64Type,
+		cadence.Int128Type, cadence.Int256Type:
+
+		return valueSchema(t.ID(), &Schema{
+			Type:    "string",
+			Pattern: "^-?[0-9]+$",
+		})
+
+	case cadence.UIntType, cadence.UInt8Type, cadence.UInt16Type, cadence.UInt32Type, cadence.UInt64Type,
+		cadence.UInt128Type, cadence.UInt256Type,
+		cadence.Word8Type, cadence.Word16Type, cadence.Word32Type, cadence.Word64Type:
+
+		return valueSchema(t.ID(), &Schema{
+			Type:    "string",
+			Pattern: "^[0-9]+$",
+		})
+
+	case cadence.Fix64Type:
+		return valueSchema("Fix64", &Schema{
+			Type:    "string",
+			Pattern: "^-?[0-9]+\\.[0-9]{1,8}$",
+		})
+
+	case cadence.UFix64Type:
+		return valueSchema("UFix64", &Schema{
+			Type:    "string",
+			Pattern: "^[0-9]+\\.[0-9]{1,8}$",
+		})
+
+	case cadence.VariableSizedArrayType:
+		return valueSchema("Array", &Schema{
+			Type:  "array",
+			Items: e.TypeSchema(t.ElementType),
+		})
+
+	case cadence.ConstantSizedArrayType:
+		size := int(t.Size)
+		return valueSchema("Array", &Schema{
+			Type:     "array",
+			Items:    e.TypeSchema(t.ElementType),
+			MinItems: &size,
+			MaxItems: &size,
+		})
+
+	case cadence.DictionaryType:
+		return valueSchema("Dictionary", &Schema{
+			Type: "array",
+			Items: objectSchema(map[string]*Schema{
+				"key":   e.TypeSchema(t.KeyType),
+				"value": e.TypeSchema(t.ElementType),
+			}),
+		})
+
+	case cadence.CompositeType:
+		return e.compositeSchema(t)
+
+	case cadence.PathType, cadence.CapabilityPathType,
+		cadence.StoragePathType, cadence.PublicPathType, cadence.PrivatePathType:
+
+		return pathSchema(t)
+
+	case cadence.MetaType:
+		return valueSchema("Type", objectSchema(map[string]*Schema{
+			"staticType": {Type: "string"},
+		}))
+
+	case cadence.CapabilityType:
+		borrowType := &Schema{Type: "string"}
+		if t.BorrowType != nil {
+			borrowType.Const = t.BorrowType.ID()
+		}
+
+		return valueSchema("Capability", objectSchema(map[string]*Schema{
+			"path":       pathSchema(cadence.PathType{}),
+			"address":    addressSchema(),
+			"borrowType": borrowType,
+		}))
+	}
+
+	// The encoding of all other types, e.g. AnyStruct,
+	// interfaces, and restricted types, depends on the run-time type of the value
+
+	schema := objectSchema(map[string]*Schema{
+		"type": {Type: "string"},
+	})
+	schema.Description = t.ID()
+	return schema
+}
+
+func (e *Exporter) compositeSchema(t cadence.CompositeType) *Schema {
+	typeID := t.ID()
+
+	ref := &Schema{
+		Ref: "#/$defs/" + escapeJSONPointer(typeID),
+	}
+
+	if _, ok := e.Defs[typeID]; ok {
+		return ref
+	}
+
+	var kind string
+	switch t.(type) {
+	case *cadence.StructType:
+		kind = "Struct"
+	case *cadence.ResourceType:
+		kind = "Resource"
+	case *cadence.EventType:
+		kind = "Event"
+	case *cadence.ContractType:
+		kind = "Contract"
+	case *cadence.EnumType:
+		kind = "Enum"
+	}
+
+	// Add the definition before exporting the fields, as they may refer to the composite type
+
+	definition := &Schema{}
+	e.Defs[typeID] = definition
+
+	fields := t.CompositeFields()
+	fieldSchemas := make([]*Schema, len(fields))
+	for i, field := range fields {
+		fieldSchemas[i] = objectSchema(map[string]*Schema{
+			"name":  {Const: field.Identifier},
+			"value": e.TypeSchema(field.Type),
+		})
+	}
+
+	*definition = *valueSchema(kind, objectSchema(map[string]*Schema{
+		"id":     {Const: typeID},
+		"fields": tupleSchema(fieldSchemas),
+	}))
+	definition.Title = t.CompositeTypeQualifiedIdentifier()
+
+	return ref
+}
+
+// addressSchema returns the schema of a hex-encoded address.
+//
+func addressSchema() *Schema {
+	return &Schema{
+		Type:    "string",
+		Pattern: "^0x([0-9a-fA-F]{2}){1,8}$",
+	}
+}
+
+func pathSchema(t cadence.Type) *Schema {
+	domain := &Schema{Type: "string"}
+
+	switch t.(type) {
+	case cadence.StoragePathType:
+		domain.Const = "storage"
+	case cadence.PublicPathType:
+		domain.Const = "public"
+	case cadence.PrivatePathType:
+		domain.Const = "private"
+	case cadence.CapabilityPathType:
+		domain.Enum = []string{"public", "private"}
+	default:
+		domain.Enum = []string{"storage", "public", "private"}
+	}
+
+	return valueSchema("Path", objectSchema(map[string]*Schema{
+		"domain":     domain,
+		"identifier": {Type: "string"},
+	}))
+}
+
+// valueSchema returns the schema of a JSON-Cadence value with the given type name and value schema.
+//
+func valueSchema(typeName string, value *Schema) *Schema {
+	properties := map[string]*Schema{
+		"type": {Const: typeName},
+	}
+	if value != nil {
+		properties["value"] = value
+	}
+	return objectSchema(properties)
+}
+
+// objectSchema returns the schema of an object with the given properties, which are all required.
+//
+func objectSchema(properties map[string]*Schema) *Schema {
+	required := make([]string, 0, len(properties))
+	for name := range properties {
+		required = append(required, name)
+	}
+	sort.Strings(required)
+
+	return &Schema{
+		Type:       "object",
+		Properties: properties,
+		Required:   required,
+	}
+}
+
+// tupleSchema returns the schema of an array with exactly the given items.
+//
+func tupleSchema(items []*Schema) *Schema {
+	size := len(items)
+	return &Schema{
+		Type:        "array",
+		PrefixItems: items,
+		MinItems:    &size,
+		MaxItems:    &size,
+	}
+}
+
+// escapeJSONPointer escapes the given reference token of a JSON Pointer (RFC 6901).
+//
+func escapeJSONPointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
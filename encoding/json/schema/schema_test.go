@@ -0,0 +1,279 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/tests/checker"
+)
+
+func TestExport(t *testing.T) {
+
+	t.Parallel()
+
+	contract, err := checker.ParseAndCheckWithOptions(t,
+		`
+          pub contract Test {
+
+              /// Emitted when a greeting was set
+              pub event Greeted(greeting: String?)
+
+              pub struct Greeting {
+                  pub let message: String
+                  pub let count: [UInt8; 2]
+
+                  init(message: String) {
+                      self.message = message
+                      self.count = [0, 0]
+                  }
+              }
+
+              /// Returns a greeting for the given name
+              pub fun greet(_ name: String): Greeting {
+                  return Greeting(message: name)
+              }
+
+              access(contract) fun internal() {}
+          }
+        `,
+		checker.ParseAndCheckOptions{
+			Location: common.StringLocation("Test"),
+		},
+	)
+	require.NoError(t, err)
+
+	transaction, err := checker.ParseAndCheck(t, `
+      transaction(amount: UFix64, path: StoragePath) {}
+    `)
+	require.NoError(t, err)
+
+	document, err := Export(contract, EntryPoint{Name: "transfer", Checker: transaction})
+	require.NoError(t, err)
+
+	assert.Equal(t, Draft, document.Schema)
+	assert.Equal(t, "Test", document.Title)
+
+	require.Len(t, document.Defs, 3)
+
+	greeted := document.Defs["S.Test.Test.Greeted"]
+	require.NotNil(t, greeted)
+	assert.Equal(t, "Test.Greeted", greeted.Title)
+	assert.Equal(t, "Emitted when a greeting was set", greeted.Description)
+	assert.Equal(t, "Event", greeted.Properties["type"].Const)
+
+	greeting := document.Defs["S.Test.Test.Greeting"]
+	require.NotNil(t, greeting)
+	fields := greeting.Properties["value"].Properties["fields"]
+	require.Len(t, fields.PrefixItems, 2)
+	assert.Equal(t, "message", fields.PrefixItems[0].Properties["name"].Const)
+	assert.Equal(t, "count", fields.PrefixItems[1].Properties["name"].Const)
+
+	require.Len(t, document.Functions, 2)
+	require.NotContains(t, document.Functions, "internal")
+
+	greet := document.Functions["greet"]
+	require.NotNil(t, greet)
+	assert.Equal(t, FunctionKindFunction, greet.Kind)
+	assert.Equal(t, "Returns a greeting for the given name", greet.Description)
+	require.Len(t, greet.Parameters, 1)
+	assert.Equal(t, "_", greet.Parameters[0].Label)
+	assert.Equal(t, "name", greet.Parameters[0].Name)
+	assert.Equal(t, &Schema{Ref: "#/$defs/S.Test.Test.Greeting"}, greet.Return)
+
+	transfer := document.Functions["transfer"]
+	require.NotNil(t, transfer)
+	assert.Equal(t, FunctionKindTransaction, transfer.Kind)
+	assert.Nil(t, transfer.Return)
+	require.Len(t, transfer.Arguments.PrefixItems, 2)
+	assert.Equal(t, 2, *transfer.Arguments.MinItems)
+	assert.Equal(t, 2, *transfer.Arguments.MaxItems)
+	assert.Equal(t,
+		"storage",
+		transfer.Arguments.PrefixItems[1].Properties["value"].Properties["domain"].Const,
+	)
+}
+
+func TestTypeSchema(t *testing.T) {
+
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		typ      cadence.Type
+		expected string
+	}
+
+	testCases := []testCase{
+		{
+			name: "Int",
+			typ:  cadence.IntType{},
+			expected: `
+              {
+                "type": "object",
+                "properties": {
+                  "type": {"const": "Int"},
+                  "value": {"type": "string", "pattern": "^-?[0-9]+$"}
+                },
+                "required": ["type", "value"]
+              }
+            `,
+		},
+		{
+			name: "Void",
+			typ:  cadence.VoidType{},
+			expected: `
+              {
+                "type": "object",
+                "properties": {
+                  "type": {"const": "Void"}
+                },
+                "required": ["type"]
+              }
+            `,
+		},
+		{
+			name: "Bool?",
+			typ:  cadence.OptionalType{Type: cadence.BoolType{}},
+			expected: `
+              {
+                "type": "object",
+                "properties": {
+                  "type": {"const": "Optional"},
+                  "value": {
+                    "anyOf": [
+                      {"type": "null"},
+                      {
+                        "type": "object",
+                        "properties": {
+                          "type": {"const": "Bool"},
+                          "value": {"type": "boolean"}
+                        },
+                        "required": ["type", "value"]
+                      }
+                    ]
+                  }
+                },
+                "required": ["type", "value"]
+              }
+            `,
+		},
+		{
+			name: "{Address: AnyStruct}",
+			typ: cadence.DictionaryType{
+				KeyType:     cadence.AddressType{},
+				ElementType: cadence.AnyStructType{},
+			},
+			expected: `
+              {
+                "type": "object",
+                "properties": {
+                  "type": {"const": "Dictionary"},
+                  "value": {
+                    "type": "array",
+                    "items": {
+                      "type": "object",
+                      "properties": {
+                        "key": {
+                          "type": "object",
+                          "properties": {
+                            "type": {"const": "Address"},
+                            "value": {"type": "string", "pattern": "^0x([0-9a-fA-F]{2}){1,8}$"}
+                          },
+                          "required": ["type", "value"]
+                        },
+                        "value": {
+                          "description": "AnyStruct",
+                          "type": "object",
+                          "properties": {
+                            "type": {"type": "string"}
+                          },
+                          "required": ["type"]
+                        }
+                      },
+                      "required": ["key", "value"]
+                    }
+                  }
+                },
+                "required": ["type", "value"]
+              }
+            `,
+		},
+	}
+
+	for _, testCase := range testCases {
+
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+
+			t.Parallel()
+
+			exporter := NewExporter()
+
+			actual, err := json.Marshal(exporter.TypeSchema(testCase.typ))
+			require.NoError(t, err)
+
+			assert.JSONEq(t, testCase.expected, string(actual))
+			assert.Empty(t, exporter.Defs)
+		})
+	}
+}
+
+func TestTypeSchemaRecursiveComposite(t *testing.T) {
+
+	t.Parallel()
+
+	resourceType := &cadence.ResourceType{
+		Location:            common.StringLocation("test"),
+		QualifiedIdentifier: "Node",
+	}
+	resourceType.Fields = []cadence.Field{
+		{
+			Identifier: "next",
+			Type:       cadence.OptionalType{Type: resourceType},
+		},
+	}
+
+	exporter := NewExporter()
+
+	schema := exporter.TypeSchema(resourceType)
+	assert.Equal(t, &Schema{Ref: "#/$defs/S.test.Node"}, schema)
+
+	require.Len(t, exporter.Defs, 1)
+
+	definition := exporter.Defs["S.test.Node"]
+	require.NotNil(t, definition)
+
+	next := definition.Properties["value"].Properties["fields"].PrefixItems[0].Properties["value"]
+	assert.Equal(t, schema, next.Properties["value"].AnyOf[1])
+}
+
+func TestEscapeJSONPointer(t *testing.T) {
+
+	t.Parallel()
+
+	assert.Equal(t, "S..~1test~1a~0b.C", escapeJSONPointer("S../test/a~b.C"))
+}
@@ -0,0 +1,347 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixedpoint
+
+import (
+	"errors"
+	"math/big"
+)
+
+// This file implements deterministic mathematical functions
+// for fixed-point numbers with a scale of Fix64Scale.
+//
+// All functions operate on the integer representation of the fixed-point number,
+// i.e. the number multiplied by Fix64Factor, and only use integer arithmetic,
+// so the results are identical on all platforms.
+//
+// Intermediate results are computed with a precision of mathScale decimal digits,
+// and the final result is rounded to the nearest representable number,
+// rounding half away from zero.
+
+var (
+	ErrNegativeArgument    = errors.New("argument is negative")
+	ErrNonPositiveArgument = errors.New("argument is not positive")
+	ErrDivisionByZero      = errors.New("division by zero")
+	ErrOverflow            = errors.New("result is too large")
+)
+
+const mathScale = 40
+
+// maxExpPowerOfTwo is the largest power of two a result of exp may be scaled by.
+// Any larger result is outside the range of 64-bit fixed-point numbers.
+//
+const maxExpPowerOfTwo = 64
+
+var fix64FactorBig = big.NewInt(Fix64Factor)
+
+// mathOne is 1 in the internal representation
+//
+var mathOne = new(big.Int).Exp(big.NewInt(10), big.NewInt(mathScale), nil)
+
+// mathFactor converts from the fixed-point representation to the internal representation
+//
+var mathFactor = new(big.Int).Quo(mathOne, fix64FactorBig)
+
+// mathLn2 is ln(2) in the internal representation
+//
+var mathLn2 = lnOfReducedInternal(new(big.Int).Lsh(mathOne, 1))
+
+// toInternal converts the given fixed-point number to the internal representation.
+//
+func toInternal(x *big.Int) *big.Int {
+	return new(big.Int).Mul(x, mathFactor)
+}
+
+// fromInternal converts the given number in the internal representation
+// to a fixed-point number, rounding half away from zero.
+//
+func fromInternal(x *big.Int) *big.Int {
+	return quoRound(x, mathFactor)
+}
+
+// quoRound returns x / y, rounded half away from zero.
+// y must be positive.
+//
+func quoRound(x, y *big.Int) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(x, y, new(big.Int))
+
+	remainder.Abs(remainder)
+	remainder.Lsh(remainder, 1)
+
+	if remainder.Cmp(y) >= 0 {
+		if x.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	return quotient
+}
+
+// mulInternal returns x * y, for x and y in the internal representation.
+//
+func mulInternal(x, y *big.Int) *big.Int {
+	result := new(big.Int).Mul(x, y)
+	return result.Quo(result, mathOne)
+}
+
+// quoInternal returns x / y, for x and y in the internal representation.
+//
+func quoInternal(x, y *big.Int) *big.Int {
+	result := new(big.Int).Mul(x, mathOne)
+	return result.Quo(result, y)
+}
+
+// Sqrt returns the square root of the given fixed-point number.
+//
+func Sqrt(x *big.Int) (*big.Int, error) {
+	if x.Sign() < 0 {
+		return nil, ErrNegativeArgument
+	}
+
+	// sqrt(x / factor) * factor = sqrt(x * factor)
+
+	n := new(big.Int).Mul(x, fix64FactorBig)
+	result := new(big.Int).Sqrt(n)
+
+	// The result is truncated. Round up if n >= result^2 + result + 1,
+	// i.e. if the exact result is at least result + 0.5
+
+	remainder := new(big.Int).Mul(result, result)
+	remainder.Sub(n, remainder)
+	if remainder.Cmp(result) > 0 {
+		result.Add(result, big.NewInt(1))
+	}
+
+	return result, nil
+}
+
+// Ln returns the natural logarithm of the given fixed-point number.
+//
+func Ln(x *big.Int) (*big.Int, error) {
+	if x.Sign() <= 0 {
+		return nil, ErrNonPositiveArgument
+	}
+
+	return fromInternal(lnInternal(toInternal(x))), nil
+}
+
+// Exp returns e raised to the power of the given fixed-point number.
+//
+func Exp(x *big.Int) (*big.Int, error) {
+	result, err := expInternal(toInternal(x))
+	if err != nil {
+		return nil, err
+	}
+
+	return fromInternal(result), nil
+}
+
+// Pow returns the given base raised to the power of the given exponent,
+// both fixed-point numbers.
+//
+// If the exponent is an integer, the base may be negative.
+// Otherwise, the base must not be negative.
+//
+func Pow(base, exponent *big.Int) (*big.Int, error) {
+
+	integerExponent, fractionalExponent :=
+		new(big.Int).QuoRem(exponent, fix64FactorBig, new(big.Int))
+
+	if fractionalExponent.Sign() == 0 {
+		result, err := powIntegerInternal(toInternal(base), integerExponent)
+		if err != nil {
+			return nil, err
+		}
+		return fromInternal(result), nil
+	}
+
+	switch base.Sign() {
+	case -1:
+		return nil, ErrNegativeArgument
+
+	case 0:
+		if exponent.Sign() < 0 {
+			return nil, ErrDivisionByZero
+		}
+		return new(big.Int), nil
+	}
+
+	// base ^ exponent = exp(exponent * ln(base))
+
+	y := new(big.Int).Mul(lnInternal(toInternal(base)), exponent)
+	y.Quo(y, fix64FactorBig)
+
+	result, err := expInternal(y)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromInternal(result), nil
+}
+
+// powIntegerInternal returns the given base in the internal representation
+// raised to the power of the given integer exponent, using exponentiation by squaring.
+//
+func powIntegerInternal(base *big.Int, exponent *big.Int) (*big.Int, error) {
+
+	if exponent.Sign() < 0 {
+		if base.Sign() == 0 {
+			return nil, ErrDivisionByZero
+		}
+
+		// base ^ -n = (1 / base) ^ n
+
+		base = quoInternal(mathOne, base)
+		exponent = new(big.Int).Neg(exponent)
+	}
+
+	// Any result larger than the limit is outside the range of 64-bit fixed-point numbers
+
+	limit := new(big.Int).Lsh(mathFactor, maxExpPowerOfTwo)
+
+	result := new(big.Int).Set(mathOne)
+	power := new(big.Int).Set(base)
+
+	for i := 0; i < exponent.BitLen(); i++ {
+
+		if i > 0 {
+			power = mulInternal(power, power)
+			if new(big.Int).Abs(power).Cmp(limit) > 0 {
+				return nil, ErrOverflow
+			}
+		}
+
+		if exponent.Bit(i) == 1 {
+			result = mulInternal(result, power)
+			if new(big.Int).Abs(result).Cmp(limit) > 0 {
+				return nil, ErrOverflow
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// lnInternal returns the natural logarithm of the given positive number
+// in the internal representation.
+//
+func lnInternal(x *big.Int) *big.Int {
+
+	// Reduce the argument to m in [1, 2), where x = m * 2^k,
+	// so ln(x) = ln(m) + k * ln(2)
+
+	k := x.BitLen() - mathOne.BitLen()
+
+	m := new(big.Int).Set(x)
+	if k > 0 {
+		m.Rsh(m, uint(k))
+	} else if k < 0 {
+		m.Lsh(m, uint(-k))
+	}
+
+	for m.Cmp(mathOne) < 0 {
+		m.Lsh(m, 1)
+		k--
+	}
+
+	twoOne := new(big.Int).Lsh(mathOne, 1)
+	for m.Cmp(twoOne) >= 0 {
+		m.Rsh(m, 1)
+		k++
+	}
+
+	result := lnOfReducedInternal(m)
+
+	return result.Add(
+		result,
+		new(big.Int).Mul(mathLn2, big.NewInt(int64(k))),
+	)
+}
+
+// lnOfReducedInternal returns the natural logarithm of the given number in [1, 2]
+// in the internal representation.
+//
+// It uses the series ln(m) = 2 * atanh(z) = 2 * (z + z^3/3 + z^5/5 + ...),
+// where z = (m - 1) / (m + 1), which converges quickly as z is at most 1/3.
+//
+func lnOfReducedInternal(m *big.Int) *big.Int {
+
+	z := quoInternal(
+		new(big.Int).Sub(m, mathOne),
+		new(big.Int).Add(m, mathOne),
+	)
+	zSquared := mulInternal(z, z)
+
+	sum := new(big.Int)
+	power := z
+
+	for n := int64(1); power.Sign() != 0; n += 2 {
+		sum.Add(sum, new(big.Int).Quo(power, big.NewInt(n)))
+		power = mulInternal(power, zSquared)
+	}
+
+	return sum.Lsh(sum, 1)
+}
+
+// expInternal returns e raised to the power of the given number
+// in the internal representation.
+//
+func expInternal(x *big.Int) (*big.Int, error) {
+
+	// Reduce the argument to r in [-ln(2)/2, ln(2)/2], where x = r + k * ln(2),
+	// so exp(x) = exp(r) * 2^k
+
+	k := quoRound(x, mathLn2)
+
+	if k.Cmp(big.NewInt(maxExpPowerOfTwo)) > 0 {
+		return nil, ErrOverflow
+	}
+
+	// Any result smaller than 2^-(mathScale * log2(10)) is zero
+	// in the internal representation
+
+	if k.Cmp(big.NewInt(-int64(mathOne.BitLen()))) < 0 {
+		return new(big.Int), nil
+	}
+
+	r := new(big.Int).Mul(k, mathLn2)
+	r.Sub(x, r)
+
+	// exp(r) = 1 + r + r^2/2! + r^3/3! + ...
+
+	sum := new(big.Int).Set(mathOne)
+	term := new(big.Int).Set(mathOne)
+
+	for n := int64(1); term.Sign() != 0; n++ {
+		term = mulInternal(term, r)
+		term.Quo(term, big.NewInt(n))
+		sum.Add(sum, term)
+	}
+
+	shift := k.Int64()
+	if shift > 0 {
+		sum.Lsh(sum, uint(shift))
+	} else if shift < 0 {
+		sum.Rsh(sum, uint(-shift))
+	}
+
+	return sum, nil
+}
@@ -0,0 +1,135 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixedpoint
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSqrt(t *testing.T) {
+
+	t.Parallel()
+
+	for input, expected := range map[int64]int64{
+		0:                   0,
+		1:                   10000,
+		25000000:            50000000,
+		100000000:           100000000,
+		200000000:           141421356,
+		50000000:            70710678,
+		10000000000:         1000000000,
+		9223372036854775807: 30370004999760,
+	} {
+		result, err := Sqrt(big.NewInt(input))
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(expected), result, "sqrt(%d)", input)
+	}
+
+	_, err := Sqrt(big.NewInt(-1))
+	require.Equal(t, ErrNegativeArgument, err)
+}
+
+func TestLn(t *testing.T) {
+
+	t.Parallel()
+
+	for input, expected := range map[int64]int64{
+		1:           -1842068074,
+		50000000:    -69314718,
+		100000000:   0,
+		200000000:   69314718,
+		271828183:   100000000,
+		10000000000: 460517019,
+	} {
+		result, err := Ln(big.NewInt(input))
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(expected), result, "ln(%d)", input)
+	}
+
+	_, err := Ln(big.NewInt(0))
+	require.Equal(t, ErrNonPositiveArgument, err)
+
+	_, err = Ln(big.NewInt(-100000000))
+	require.Equal(t, ErrNonPositiveArgument, err)
+}
+
+func TestExp(t *testing.T) {
+
+	t.Parallel()
+
+	for input, expected := range map[int64]int64{
+		0:           100000000,
+		100000000:   271828183,
+		-100000000:  36787944,
+		69314718:    200000000,
+		-2000000000: 0,
+		2500000000:  7200489933738587252,
+	} {
+		result, err := Exp(big.NewInt(input))
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(expected), result, "exp(%d)", input)
+	}
+
+	_, err := Exp(big.NewInt(10000000000))
+	require.Equal(t, ErrOverflow, err)
+}
+
+func TestPow(t *testing.T) {
+
+	t.Parallel()
+
+	type testCase struct {
+		base     int64
+		exponent int64
+		expected int64
+		err      error
+	}
+
+	for _, testCase := range []testCase{
+		{0, 0, 100000000, nil},
+		{0, 50000000, 0, nil},
+		{200000000, 1000000000, 102400000000, nil},
+		{200000000, 50000000, 141421356, nil},
+		{200000000, -200000000, 25000000, nil},
+		{-200000000, 300000000, -800000000, nil},
+		{150000000, 275000000, 304965676, nil},
+		{1000000000, 1000000000, 1000000000000000000, nil},
+		{1000000000, 1200000000, 0, ErrOverflow},
+		{0, -100000000, 0, ErrDivisionByZero},
+		{0, -50000000, 0, ErrDivisionByZero},
+		{-200000000, 50000000, 0, ErrNegativeArgument},
+	} {
+		result, err := Pow(big.NewInt(testCase.base), big.NewInt(testCase.exponent))
+		if testCase.err != nil {
+			require.Equal(t, testCase.err, err)
+			continue
+		}
+
+		require.NoError(t, err)
+		assert.Equal(t,
+			big.NewInt(testCase.expected),
+			result,
+			"pow(%d, %d)", testCase.base, testCase.exponent,
+		)
+	}
+}
@@ -74,9 +74,19 @@ func DeclarationKindToSymbolKind(kind common.DeclarationKind) protocol.SymbolKin
 	case common.DeclarationKindDestructor:
 		return protocol.Function
 
-	case common.DeclarationKindStructure,
-		common.DeclarationKindResource,
-		common.DeclarationKindEvent,
+	case common.DeclarationKindStructure:
+		return protocol.Struct
+
+	case common.DeclarationKindEvent:
+		return protocol.Event
+
+	case common.DeclarationKindEnum:
+		return protocol.Enum
+
+	case common.DeclarationKindEnumCase:
+		return protocol.EnumMember
+
+	case common.DeclarationKindResource,
 		common.DeclarationKindContract,
 		common.DeclarationKindType:
 		return protocol.Class
@@ -98,16 +108,23 @@ func DeclarationKindToSymbolKind(kind common.DeclarationKind) protocol.SymbolKin
 func DeclarationToDocumentSymbol(declaration ast.Declaration) protocol.DocumentSymbol {
 	var children []protocol.DocumentSymbol
 
+	declarationKind := declaration.DeclarationKind()
+
 	declarationMembers := declaration.DeclarationMembers()
 	if declarationMembers != nil {
-		for _, child := range declarationMembers.Declarations() {
-			childSymbol := DeclarationToDocumentSymbol(child)
-			children = append(children, childSymbol)
+		if declarationKind == common.DeclarationKindEvent {
+			// The fields of an event are the parameters of its synthesized initializer
+			for _, initializer := range declarationMembers.Initializers() {
+				children = append(children, parameterListToFieldSymbols(initializer.FunctionDeclaration.ParameterList)...)
+			}
+		} else {
+			for _, child := range declarationMembers.Declarations() {
+				childSymbol := DeclarationToDocumentSymbol(child)
+				children = append(children, childSymbol)
+			}
 		}
 	}
 
-	declarationKind := declaration.DeclarationKind()
-
 	var name string
 	var selectionRange protocol.Range
 
@@ -148,3 +165,31 @@ func DeclarationToDocumentSymbol(declaration ast.Declaration) protocol.DocumentS
 
 	return symbol
 }
+
+func parameterListToFieldSymbols(parameterList *ast.ParameterList) []protocol.DocumentSymbol {
+	if parameterList == nil {
+		return nil
+	}
+
+	symbols := make([]protocol.DocumentSymbol, 0, len(parameterList.Parameters))
+
+	for _, parameter := range parameterList.Parameters {
+		identifier := parameter.Identifier
+		symbols = append(symbols,
+			protocol.DocumentSymbol{
+				Name: identifier.Identifier,
+				Kind: protocol.Field,
+				Range: ASTToProtocolRange(
+					parameter.StartPos,
+					parameter.EndPos,
+				),
+				SelectionRange: ASTToProtocolRange(
+					identifier.StartPosition(),
+					identifier.EndPosition(),
+				),
+			},
+		)
+	}
+
+	return symbols
+}
@@ -147,6 +147,14 @@ func (s *Server) handleDocumentSymbol(req *json.RawMessage) (interface{}, error)
 	return s.Handler.DocumentSymbol(s.conn, &params)
 }
 
+func (s *Server) handleWorkspaceSymbol(req *json.RawMessage) (interface{}, error) {
+	var params WorkspaceSymbolParams
+	if err := json.Unmarshal(*req, &params); err != nil {
+		return nil, err
+	}
+	return s.Handler.WorkspaceSymbol(s.conn, &params)
+}
+
 func (s *Server) handleShutdown(_ *json.RawMessage) (interface{}, error) {
 	err := s.Handler.Shutdown(s.conn)
 	return nil, err
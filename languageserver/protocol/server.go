@@ -92,6 +92,7 @@ type Handler interface {
 	ResolveCompletionItem(conn Conn, item *CompletionItem) (*CompletionItem, error)
 	ExecuteCommand(conn Conn, params *ExecuteCommandParams) (interface{}, error)
 	DocumentSymbol(conn Conn, params *DocumentSymbolParams) ([]*DocumentSymbol, error)
+	WorkspaceSymbol(conn Conn, params *WorkspaceSymbolParams) ([]*SymbolInformation, error)
 	Shutdown(conn Conn) error
 	Exit(conn Conn) error
 }
@@ -151,6 +152,9 @@ func NewServer(handler Handler) *Server {
 	jsonrpc2Server.Methods["textDocument/documentSymbol"] =
 		server.handleDocumentSymbol
 
+	jsonrpc2Server.Methods["workspace/symbol"] =
+		server.handleWorkspaceSymbol
+
 	jsonrpc2Server.Methods["shutdown"] =
 		server.handleShutdown
 
@@ -0,0 +1,360 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/languageserver/protocol"
+)
+
+func TestServer_QuickFixCodeActions(t *testing.T) {
+
+	t.Parallel()
+
+	const uri protocol.DocumentUri = "file:///test/main.cdc"
+
+	// quickFixes opens the given documents, where the first document is the tested one,
+	// and returns the documents which result from applying each quick-fix code action
+	// of the tested document, keyed by the title of the code action
+
+	quickFixes := func(t *testing.T, code string, otherDocuments ...protocol.TextDocumentItem) map[string]string {
+
+		server, err := NewServer()
+		require.NoError(t, err)
+
+		conn := testConn{}
+
+		for _, document := range otherDocuments {
+			err = server.DidOpenTextDocument(
+				conn,
+				&protocol.DidOpenTextDocumentParams{
+					TextDocument: document,
+				},
+			)
+			require.NoError(t, err)
+		}
+
+		server.documents[uri] = Document{Text: code}
+
+		diagnostics, err := server.getDiagnostics(conn, uri, code, 0)
+		require.NoError(t, err)
+
+		for i, diagnostic := range diagnostics {
+			if id, ok := diagnostic.Data.(uuid.UUID); ok {
+				diagnostics[i].Data = id.String()
+			}
+		}
+
+		codeActions, err := server.CodeAction(
+			conn,
+			&protocol.CodeActionParams{
+				TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+				Context: protocol.CodeActionContext{
+					Diagnostics: diagnostics,
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		results := map[string]string{}
+
+		for _, codeAction := range codeActions {
+			require.NotNil(t, codeAction.Edit)
+			require.NotNil(t, codeAction.Edit.Changes)
+
+			textEdits := (*codeAction.Edit.Changes)[string(uri)]
+
+			results[codeAction.Title] = applyTextEdits(Document{Text: code}, textEdits)
+		}
+
+		return results
+	}
+
+	t.Run("destroy lost variable", func(t *testing.T) {
+
+		t.Parallel()
+
+		results := quickFixes(t, `
+resource R {}
+
+fun test() {
+    let r <- create R()
+}
+`)
+
+		assert.Equal(t,
+			`
+resource R {}
+
+fun test() {
+    let r <- create R()
+    destroy r
+}
+`,
+			results["Destroy `r`"],
+		)
+	})
+
+	t.Run("destroy lost variable before return", func(t *testing.T) {
+
+		t.Parallel()
+
+		results := quickFixes(t, `
+resource R {}
+
+fun test(): Int {
+    let r <- create R()
+    return 1
+}
+`)
+
+		assert.Equal(t,
+			`
+resource R {}
+
+fun test(): Int {
+    let r <- create R()
+    destroy r
+    return 1
+}
+`,
+			results["Destroy `r`"],
+		)
+	})
+
+	t.Run("destroy lost parameter", func(t *testing.T) {
+
+		t.Parallel()
+
+		results := quickFixes(t, `
+resource R {}
+
+fun test(r: @R) {}
+`)
+
+		assert.Equal(t,
+			`
+resource R {}
+
+fun test(r: @R) {destroy r; }
+`,
+			results["Destroy `r`"],
+		)
+	})
+
+	t.Run("destroy lost expression", func(t *testing.T) {
+
+		t.Parallel()
+
+		results := quickFixes(t, `
+resource R {}
+
+fun test() {
+    create R()
+}
+`)
+
+		assert.Equal(t,
+			`
+resource R {}
+
+fun test() {
+    destroy create R()
+}
+`,
+			results["Destroy resource"],
+		)
+	})
+
+	t.Run("change constant to variable", func(t *testing.T) {
+
+		t.Parallel()
+
+		results := quickFixes(t, `
+fun test() {
+    let x = 1
+    x = 2
+}
+`)
+
+		assert.Equal(t,
+			`
+fun test() {
+    var x = 1
+    x = 2
+}
+`,
+			results["Change `let` to `var`"],
+		)
+	})
+
+	t.Run("change constant field to variable field", func(t *testing.T) {
+
+		t.Parallel()
+
+		results := quickFixes(t, `
+pub struct S {
+    pub let value: Int
+
+    init() {
+        self.value = 1
+    }
+
+    pub fun update() {
+        self.value = 2
+    }
+}
+`)
+
+		assert.Equal(t,
+			`
+pub struct S {
+    pub var value: Int
+
+    init() {
+        self.value = 1
+    }
+
+    pub fun update() {
+        self.value = 2
+    }
+}
+`,
+			results["Change `let` to `var`"],
+		)
+	})
+
+	t.Run("add missing members", func(t *testing.T) {
+
+		t.Parallel()
+
+		results := quickFixes(t, `
+pub struct interface I {
+    pub fun foo(): Int
+}
+
+pub struct S: I {
+}
+`)
+
+		assert.Contains(t, results, "Add missing members")
+	})
+
+	t.Run("import", func(t *testing.T) {
+
+		t.Parallel()
+
+		library := protocol.TextDocumentItem{
+			URI: "file:///test/library/foo.cdc",
+			Text: `
+pub struct Foo {}
+
+pub struct Bar {}
+`,
+		}
+
+		t.Run("new import declaration", func(t *testing.T) {
+
+			t.Parallel()
+
+			results := quickFixes(t,
+				`pub fun main(): Foo {
+    return Foo()
+}
+`,
+				library,
+			)
+
+			assert.Equal(t,
+				`import Foo from "./library/foo.cdc"
+
+pub fun main(): Foo {
+    return Foo()
+}
+`,
+				results[`Import `+"`Foo`"+` from "./library/foo.cdc"`],
+			)
+		})
+
+		t.Run("existing import declaration", func(t *testing.T) {
+
+			t.Parallel()
+
+			results := quickFixes(t,
+				`import Bar from "./library/foo.cdc"
+
+pub fun main(): Foo {
+    return Foo()
+}
+`,
+				library,
+			)
+
+			assert.Equal(t,
+				`import Bar, Foo from "./library/foo.cdc"
+
+pub fun main(): Foo {
+    return Foo()
+}
+`,
+				results[`Import `+"`Foo`"+` from "./library/foo.cdc"`],
+			)
+		})
+	})
+}
+
+// applyTextEdits returns the text of the document after applying the given text edits
+//
+func applyTextEdits(document Document, textEdits []protocol.TextEdit) string {
+	type offsetEdit struct {
+		start, end int
+		text       string
+	}
+
+	offsetEdits := make([]offsetEdit, 0, len(textEdits))
+	for _, textEdit := range textEdits {
+		offsetEdits = append(offsetEdits,
+			offsetEdit{
+				start: document.Offset(int(textEdit.Range.Start.Line)+1, int(textEdit.Range.Start.Character)),
+				end:   document.Offset(int(textEdit.Range.End.Line)+1, int(textEdit.Range.End.Character)),
+				text:  textEdit.NewText,
+			},
+		)
+	}
+
+	// Apply the edits from the end of the document to the start,
+	// so the offsets of the remaining edits stay valid
+
+	sort.Slice(offsetEdits, func(i, j int) bool {
+		return offsetEdits[i].start > offsetEdits[j].start
+	})
+
+	text := document.Text
+	for _, edit := range offsetEdits {
+		text = text[:edit.start] + edit.text + text[edit.end:]
+	}
+
+	return text
+}
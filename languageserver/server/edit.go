@@ -0,0 +1,226 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/onflow/cadence/runtime/ast"
+
+	"github.com/onflow/cadence/languageserver/conversion"
+	"github.com/onflow/cadence/languageserver/protocol"
+)
+
+// This file provides helpers to synthesize source code edits from the AST,
+// used by the quick-fix code actions.
+
+// insertionTextEdit returns a text edit which inserts the given text at the given position
+//
+func insertionTextEdit(pos ast.Position, text string) protocol.TextEdit {
+	protocolPos := conversion.ASTToProtocolPosition(pos)
+	return protocol.TextEdit{
+		Range: protocol.Range{
+			Start: protocolPos,
+			End:   protocolPos,
+		},
+		NewText: text,
+	}
+}
+
+// replacementTextEdit returns a text edit which replaces the text
+// between the given positions (inclusive) with the given text
+//
+func replacementTextEdit(startPos, endPos ast.Position, text string) protocol.TextEdit {
+	return protocol.TextEdit{
+		Range:   conversion.ASTToProtocolRange(startPos, endPos),
+		NewText: text,
+	}
+}
+
+// quickFixCodeAction returns a quick-fix code action for the given diagnostic,
+// which applies the given text edits to the given document
+//
+func quickFixCodeAction(
+	title string,
+	diagnostic protocol.Diagnostic,
+	uri protocol.DocumentUri,
+	isPreferred bool,
+	textEdits ...protocol.TextEdit,
+) *protocol.CodeAction {
+	return &protocol.CodeAction{
+		Title:       title,
+		Kind:        protocol.QuickFix,
+		Diagnostics: []protocol.Diagnostic{diagnostic},
+		Edit: &protocol.WorkspaceEdit{
+			Changes: &map[string][]protocol.TextEdit{
+				string(uri): textEdits,
+			},
+		},
+		IsPreferred: isPreferred,
+	}
+}
+
+// combineCodeActionsResolvers returns a code actions resolver
+// which returns the code actions of all given resolvers,
+// or nil if all given resolvers are nil
+//
+func combineCodeActionsResolvers(resolvers ...func() []*protocol.CodeAction) func() []*protocol.CodeAction {
+	var nonNilResolvers []func() []*protocol.CodeAction
+	for _, resolver := range resolvers {
+		if resolver != nil {
+			nonNilResolvers = append(nonNilResolvers, resolver)
+		}
+	}
+
+	switch len(nonNilResolvers) {
+	case 0:
+		return nil
+	case 1:
+		return nonNilResolvers[0]
+	}
+
+	return func() []*protocol.CodeAction {
+		var codeActions []*protocol.CodeAction
+		for _, resolver := range nonNilResolvers {
+			codeActions = append(codeActions, resolver()...)
+		}
+		return codeActions
+	}
+}
+
+// lineStartPosition returns the position of the start of the line of the given position
+//
+func lineStartPosition(text string, pos ast.Position) ast.Position {
+	offset := pos.Offset
+	for offset > 0 && text[offset-1] != '\n' {
+		offset--
+	}
+	return ast.Position{
+		Offset: offset,
+		Line:   pos.Line,
+		Column: 0,
+	}
+}
+
+// isFirstOnLine returns true if the given position is only preceded by whitespace on its line
+//
+func isFirstOnLine(text string, pos ast.Position) bool {
+	lineStart := lineStartPosition(text, pos)
+	return strings.TrimSpace(text[lineStart.Offset:pos.Offset]) == ""
+}
+
+// advancePosition returns the position of the given offset,
+// which must not be before the given position
+//
+func advancePosition(text string, pos ast.Position, offset int) ast.Position {
+	for pos.Offset < offset {
+		r, width := utf8.DecodeRuneInString(text[pos.Offset:])
+		pos.Offset += width
+		if r == '\n' {
+			pos.Line++
+			pos.Column = 0
+		} else {
+			pos.Column++
+		}
+	}
+	return pos
+}
+
+// findLastKeyword returns the position of the last occurrence of the given keyword
+// in the text between the given positions, where the keyword is a separate word
+//
+func findLastKeyword(text string, startPos, endPos ast.Position, keyword string) (ast.Position, bool) {
+	if startPos.Offset < 0 || endPos.Offset > len(text) || startPos.Offset > endPos.Offset {
+		return ast.Position{}, false
+	}
+
+	section := text[startPos.Offset:endPos.Offset]
+
+	isIdentifierRune := func(r rune) bool {
+		return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+
+	for index := strings.LastIndex(section, keyword); index >= 0; index = strings.LastIndex(section[:index], keyword) {
+		before, _ := utf8.DecodeLastRuneInString(section[:index])
+		after, _ := utf8.DecodeRuneInString(section[index+len(keyword):])
+		if (index == 0 || !isIdentifierRune(before)) &&
+			(index+len(keyword) == len(section) || !isIdentifierRune(after)) {
+
+			return advancePosition(text, startPos, startPos.Offset+index), true
+		}
+	}
+
+	return ast.Position{}, false
+}
+
+// findElement returns the first element of the program which satisfies the given predicate,
+// and its parent elements, innermost last
+//
+func findElement(program *ast.Program, predicate func(element ast.Element) bool) (ast.Element, []ast.Element) {
+	var found ast.Element
+	var parents []ast.Element
+
+	var stack []ast.Element
+	ast.Inspect(program, func(element ast.Element) bool {
+		if found != nil {
+			return false
+		}
+
+		switch element {
+		case nil:
+			stack = stack[:len(stack)-1]
+			return true
+		}
+
+		if predicate(element) {
+			found = element
+			parents = append([]ast.Element(nil), stack...)
+			return false
+		}
+
+		stack = append(stack, element)
+		return true
+	})
+
+	return found, parents
+}
+
+// relativeImportPath returns the path which imports the given target path
+// from the given base path, e.g. `./Foo.cdc`
+//
+func relativeImportPath(basePath, targetPath string) string {
+	relativePath, err := filepath.Rel(
+		filepath.FromSlash(path.Dir(basePath)),
+		filepath.FromSlash(targetPath),
+	)
+	if err != nil {
+		return targetPath
+	}
+
+	relativePath = filepath.ToSlash(relativePath)
+	if !strings.HasPrefix(relativePath, "../") {
+		relativePath = "./" + relativePath
+	}
+
+	return relativePath
+}
@@ -0,0 +1,265 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/languageserver/protocol"
+)
+
+type testConn struct{}
+
+var _ protocol.Conn = testConn{}
+
+func (testConn) Notify(_ string, _ interface{}) error {
+	return nil
+}
+
+func (testConn) ShowMessage(_ *protocol.ShowMessageParams) {}
+
+func (testConn) LogMessage(_ *protocol.LogMessageParams) {}
+
+func (testConn) PublishDiagnostics(_ *protocol.PublishDiagnosticsParams) error {
+	return nil
+}
+
+func (testConn) RegisterCapability(_ *protocol.RegistrationParams) error {
+	return nil
+}
+
+func TestServer_Rename(t *testing.T) {
+
+	t.Parallel()
+
+	const libraryURI protocol.DocumentUri = "file:///test/library.cdc"
+	const libraryCode = `
+pub struct Foo {
+    pub let bar: Int
+
+    init() {
+        self.bar = 1
+    }
+}
+
+pub fun makeFoo(): Foo {
+    return Foo()
+}
+`
+
+	const mainURI protocol.DocumentUri = "file:///test/main.cdc"
+	const mainCode = `
+import Foo, makeFoo from "./library.cdc"
+
+pub fun main(): Int {
+    let foo: Foo = makeFoo()
+    return foo.bar
+}
+`
+
+	newServer := func(t *testing.T) *Server {
+		server, err := NewServer()
+		require.NoError(t, err)
+
+		conn := testConn{}
+
+		for _, document := range []protocol.TextDocumentItem{
+			{URI: libraryURI, Text: libraryCode},
+			{URI: mainURI, Text: mainCode},
+		} {
+			err = server.DidOpenTextDocument(
+				conn,
+				&protocol.DidOpenTextDocumentParams{
+					TextDocument: document,
+				},
+			)
+			require.NoError(t, err)
+			require.NotNil(t, server.checkerForDocument(document.URI))
+		}
+
+		return server
+	}
+
+	rename := func(
+		t *testing.T,
+		server *Server,
+		uri protocol.DocumentUri,
+		line, character float64,
+	) map[string][]protocol.Range {
+
+		edit, err := server.Rename(
+			testConn{},
+			&protocol.RenameParams{
+				TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+				Position: protocol.Position{
+					Line:      line,
+					Character: character,
+				},
+				NewName: "Baz",
+			},
+		)
+		require.NoError(t, err)
+		require.NotNil(t, edit)
+		require.NotNil(t, edit.Changes)
+
+		ranges := map[string][]protocol.Range{}
+		for uri, textEdits := range *edit.Changes {
+			if len(textEdits) == 0 {
+				continue
+			}
+			for _, textEdit := range textEdits {
+				assert.Equal(t, "Baz", textEdit.NewText)
+				ranges[uri] = append(ranges[uri], textEdit.Range)
+			}
+			sort.Slice(ranges[uri], func(i, j int) bool {
+				a := ranges[uri][i].Start
+				b := ranges[uri][j].Start
+				if a.Line != b.Line {
+					return a.Line < b.Line
+				}
+				return a.Character < b.Character
+			})
+		}
+		return ranges
+	}
+
+	newRange := func(line, startCharacter, endCharacter float64) protocol.Range {
+		return protocol.Range{
+			Start: protocol.Position{Line: line, Character: startCharacter},
+			End:   protocol.Position{Line: line, Character: endCharacter},
+		}
+	}
+
+	expectedTypeRenames := map[string][]protocol.Range{
+		string(libraryURI): {
+			newRange(1, 11, 14),
+			newRange(9, 19, 22),
+			newRange(10, 11, 14),
+		},
+		string(mainURI): {
+			newRange(1, 7, 10),
+			newRange(4, 13, 16),
+		},
+	}
+
+	t.Run("type, from declaring document", func(t *testing.T) {
+
+		t.Parallel()
+
+		server := newServer(t)
+
+		assert.Equal(t,
+			expectedTypeRenames,
+			rename(t, server, libraryURI, 1, 12),
+		)
+	})
+
+	t.Run("type, from importing document", func(t *testing.T) {
+
+		t.Parallel()
+
+		server := newServer(t)
+
+		assert.Equal(t,
+			expectedTypeRenames,
+			rename(t, server, mainURI, 4, 14),
+		)
+	})
+
+	t.Run("function", func(t *testing.T) {
+
+		t.Parallel()
+
+		server := newServer(t)
+
+		assert.Equal(t,
+			map[string][]protocol.Range{
+				string(libraryURI): {
+					newRange(9, 8, 15),
+				},
+				string(mainURI): {
+					newRange(1, 12, 19),
+					newRange(4, 19, 26),
+				},
+			},
+			rename(t, server, libraryURI, 9, 10),
+		)
+	})
+
+	t.Run("field", func(t *testing.T) {
+
+		t.Parallel()
+
+		server := newServer(t)
+
+		assert.Equal(t,
+			map[string][]protocol.Range{
+				string(libraryURI): {
+					newRange(2, 12, 15),
+					newRange(5, 13, 16),
+				},
+				string(mainURI): {
+					newRange(5, 15, 18),
+				},
+			},
+			rename(t, server, mainURI, 5, 16),
+		)
+	})
+
+	t.Run("import declaration", func(t *testing.T) {
+
+		t.Parallel()
+
+		server := newServer(t)
+
+		assert.Equal(t,
+			map[string][]protocol.Range{
+				string(libraryURI): {
+					newRange(9, 8, 15),
+				},
+				string(mainURI): {
+					newRange(1, 12, 19),
+					newRange(4, 19, 26),
+				},
+			},
+			rename(t, server, mainURI, 1, 14),
+		)
+	})
+
+	t.Run("local variable", func(t *testing.T) {
+
+		t.Parallel()
+
+		server := newServer(t)
+
+		assert.Equal(t,
+			map[string][]protocol.Range{
+				string(mainURI): {
+					newRange(4, 8, 11),
+					newRange(5, 11, 14),
+				},
+			},
+			rename(t, server, mainURI, 4, 9),
+		)
+	})
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -157,7 +158,7 @@ type CodeLensProvider func(uri protocol.DocumentUri, version float64, checker *s
 //
 type DiagnosticProvider func(uri protocol.DocumentUri, version float64, checker *sema.Checker) ([]protocol.Diagnostic, error)
 
-// DocumentSymbolProvider
+// DocumentSymbolProvider is a function that is used to provide document symbols for the given checker
 //
 type DocumentSymbolProvider func(uri protocol.DocumentUri, version float64, checker *sema.Checker) ([]*protocol.DocumentSymbol, error)
 
@@ -263,6 +264,16 @@ func WithDiagnosticProvider(provider DiagnosticProvider) Option {
 	}
 }
 
+// WithDocumentSymbolProvider returns a server option that adds the given function
+// as a function that is used to provide document symbols
+//
+func WithDocumentSymbolProvider(provider DocumentSymbolProvider) Option {
+	return func(s *Server) error {
+		s.documentSymbolProviders = append(s.documentSymbolProviders, provider)
+		return nil
+	}
+}
+
 // WithInitializationOptionsHandler returns a server option that adds the given function
 // as a function that is used to handle initialization options sent by the client
 //
@@ -344,6 +355,7 @@ func (s *Server) Initialize(
 			},
 			DocumentHighlightProvider: true,
 			DocumentSymbolProvider:    true,
+			WorkspaceSymbolProvider:   true,
 			RenameProvider:            true,
 			SignatureHelpProvider: &protocol.SignatureHelpOptions{
 				TriggerCharacters: []string{"("},
@@ -785,6 +797,13 @@ func (s *Server) DocumentHighlight(
 	return documentHighlights, nil
 }
 
+// Rename renames the declaration at the given position,
+// and all references to it, in all open documents of the workspace.
+//
+// Each document is checked by its own checker, so the occurrences of a declaration
+// in different documents have different origins. Declarations are therefore identified
+// by the location of the program that declares them and their position in that program.
+//
 func (s *Server) Rename(
 	_ protocol.Conn,
 	params *protocol.RenameParams,
@@ -798,43 +817,321 @@ func (s *Server) Rename(
 		return nil, nil
 	}
 
+	changes := map[string][]protocol.TextEdit{
+		string(uri): {},
+	}
+
 	position := conversion.ProtocolToSemaPosition(params.Position)
-	occurrences := checker.Occurrences.FindAll(position)
-	// If there are no occurrences,
+	key, ok := s.declarationKeyAtPosition(uri, checker, position)
+	// If there is no declaration at the position,
 	// then try the preceding position
-	if len(occurrences) == 0 && position.Column > 0 {
+	if !ok && position.Column > 0 {
 		previousPosition := position
 		previousPosition.Column -= 1
-		occurrences = checker.Occurrences.FindAll(previousPosition)
+		key, ok = s.declarationKeyAtPosition(uri, checker, previousPosition)
 	}
 
-	textEdits := make([]protocol.TextEdit, 0)
+	if ok {
+		for documentURI := range s.documents {
+			documentChecker := s.checkerForDocument(documentURI)
+			if documentChecker == nil {
+				continue
+			}
 
-	for _, occurrence := range occurrences {
+			ranges := s.declarationRanges(documentURI, documentChecker, key)
+			if len(ranges) == 0 {
+				continue
+			}
+
+			textEdits := make([]protocol.TextEdit, 0, len(ranges))
+			for _, occurrenceRange := range ranges {
+				textEdits = append(textEdits,
+					protocol.TextEdit{
+						Range: conversion.ASTToProtocolRange(
+							occurrenceRange.StartPos,
+							occurrenceRange.EndPos,
+						),
+						NewText: params.NewName,
+					},
+				)
+			}
+
+			changes[string(documentURI)] = textEdits
+		}
+	}
+
+	return &protocol.WorkspaceEdit{
+		Changes: &changes,
+	}, nil
+}
+
+// declarationKey identifies a declaration across documents
+//
+type declarationKey struct {
+	locationID common.LocationID
+	position   ast.Position
+}
 
+// declarationKeyAtPosition returns the key of the declaration
+// which is referenced or declared at the given position of the document
+//
+func (s *Server) declarationKeyAtPosition(
+	uri protocol.DocumentUri,
+	checker *sema.Checker,
+	position sema.Position,
+) (
+	declarationKey,
+	bool,
+) {
+	for _, occurrence := range checker.Occurrences.FindAll(position) {
 		origin := occurrence.Origin
-		if origin == nil || origin.StartPos == nil || origin.EndPos == nil {
+
+		// Members of imported types have no origin,
+		// so find the member expression of the occurrence
+
+		if origin == nil {
+			for memberExpression, memberInfo := range checker.Elaboration.MemberExpressionMemberInfos {
+				startPos := sema.ASTToSemaPosition(memberExpression.Identifier.StartPosition())
+				if startPos != occurrence.StartPos {
+					continue
+				}
+
+				if key, ok := memberDeclarationKey(memberInfo.Member); ok {
+					return key, true
+				}
+			}
+			continue
+		}
+
+		if key, ok := s.originDeclarationKey(uri, checker, origin); ok {
+			return key, true
+		}
+	}
+
+	// Identifiers of import declarations are not occurrences
+
+	for _, importDeclaration := range checker.Program.ImportDeclarations() {
+		for _, identifier := range importDeclaration.Identifiers {
+			startPos := sema.ASTToSemaPosition(identifier.StartPosition())
+			endPos := sema.ASTToSemaPosition(identifier.EndPosition())
+			if position.Compare(startPos) < 0 || position.Compare(endPos) > 0 {
+				continue
+			}
+
+			return s.importedDeclarationKey(checker, importDeclaration, identifier.Identifier)
+		}
+	}
+
+	return declarationKey{}, false
+}
+
+// declarationRanges returns the ranges of the given document
+// which declare or reference the declaration with the given key
+//
+func (s *Server) declarationRanges(
+	uri protocol.DocumentUri,
+	checker *sema.Checker,
+	key declarationKey,
+) []ast.Range {
+
+	rangeSet := map[ast.Range]struct{}{}
+
+	seenOrigins := map[*sema.Origin]struct{}{}
+
+	for _, occurrence := range checker.Occurrences.All() {
+		origin := occurrence.Origin
+		if origin == nil {
+			continue
+		}
+
+		if _, ok := seenOrigins[origin]; ok {
+			continue
+		}
+		seenOrigins[origin] = struct{}{}
+
+		originKey, ok := s.originDeclarationKey(uri, checker, origin)
+		if !ok || originKey != key {
 			continue
 		}
 
 		for _, occurrenceRange := range origin.Occurrences {
-			textEdits = append(textEdits,
-				protocol.TextEdit{
-					Range: conversion.ASTToProtocolRange(
-						occurrenceRange.StartPos,
-						occurrenceRange.EndPos,
-					),
-					NewText: params.NewName,
-				},
-			)
+			rangeSet[occurrenceRange] = struct{}{}
 		}
 	}
 
-	return &protocol.WorkspaceEdit{
-		Changes: &map[string][]protocol.TextEdit{
-			string(uri): textEdits,
-		},
-	}, nil
+	for memberExpression, memberInfo := range checker.Elaboration.MemberExpressionMemberInfos {
+		memberKey, ok := memberDeclarationKey(memberInfo.Member)
+		if !ok || memberKey != key {
+			continue
+		}
+
+		rangeSet[ast.NewRangeFromPositioned(memberExpression.Identifier)] = struct{}{}
+	}
+
+	for _, importDeclaration := range checker.Program.ImportDeclarations() {
+		for _, identifier := range importDeclaration.Identifiers {
+			importedKey, ok := s.importedDeclarationKey(checker, importDeclaration, identifier.Identifier)
+			if !ok || importedKey != key {
+				continue
+			}
+
+			rangeSet[ast.NewRangeFromPositioned(identifier)] = struct{}{}
+		}
+	}
+
+	ranges := make([]ast.Range, 0, len(rangeSet))
+	for occurrenceRange := range rangeSet {
+		ranges = append(ranges, occurrenceRange)
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].StartPos.Offset < ranges[j].StartPos.Offset
+	})
+
+	return ranges
+}
+
+// originDeclarationKey returns the key of the declaration of the given origin.
+//
+// Imported declarations have no position in the importing program,
+// so they are resolved using the import declarations of the program.
+//
+func (s *Server) originDeclarationKey(
+	uri protocol.DocumentUri,
+	checker *sema.Checker,
+	origin *sema.Origin,
+) (
+	declarationKey,
+	bool,
+) {
+	if origin.StartPos == nil {
+		return declarationKey{}, false
+	}
+
+	if *origin.StartPos != (ast.Position{}) {
+		return declarationKey{
+			locationID: checker.Location.ID(),
+			position:   *origin.StartPos,
+		}, true
+	}
+
+	if len(origin.Occurrences) == 0 {
+		return declarationKey{}, false
+	}
+
+	document, ok := s.documents[uri]
+	if !ok {
+		return declarationKey{}, false
+	}
+
+	occurrenceRange := origin.Occurrences[0]
+	startOffset := occurrenceRange.StartPos.Offset
+	endOffset := occurrenceRange.EndPos.Offset + 1
+	if startOffset < 0 || endOffset > len(document.Text) || startOffset >= endOffset {
+		return declarationKey{}, false
+	}
+
+	name := document.Text[startOffset:endOffset]
+
+	for _, importDeclaration := range checker.Program.ImportDeclarations() {
+		key, ok := s.importedDeclarationKey(checker, importDeclaration, name)
+		if ok {
+			return key, true
+		}
+	}
+
+	return declarationKey{}, false
+}
+
+// importedDeclarationKey returns the key of the declaration with the given name
+// which is imported by the given import declaration
+//
+func (s *Server) importedDeclarationKey(
+	checker *sema.Checker,
+	importDeclaration *ast.ImportDeclaration,
+	name string,
+) (
+	declarationKey,
+	bool,
+) {
+	if len(importDeclaration.Identifiers) > 0 {
+		imported := false
+		for _, identifier := range importDeclaration.Identifiers {
+			if identifier.Identifier == name {
+				imported = true
+				break
+			}
+		}
+		if !imported {
+			return declarationKey{}, false
+		}
+	}
+
+	importedLocation := importDeclaration.Location
+
+	switch location := importedLocation.(type) {
+	case common.AddressLocation:
+		// Address imports resolve to one location per imported contract,
+		// see the location handler of the checker
+
+		importedLocation = common.AddressLocation{
+			Address: location.Address,
+			Name:    name,
+		}
+
+	default:
+		if isPathLocation(importedLocation) {
+			importedLocation = normalizePathLocation(checker.Location, importedLocation)
+		}
+	}
+
+	importedChecker, ok := s.checkers[importedLocation.ID()]
+	if !ok {
+		return declarationKey{}, false
+	}
+
+	variable, ok := importedChecker.Elaboration.GlobalTypes.Get(name)
+	if !ok {
+		variable, ok = importedChecker.Elaboration.GlobalValues.Get(name)
+		if !ok {
+			return declarationKey{}, false
+		}
+	}
+
+	if variable.Pos == nil {
+		return declarationKey{}, false
+	}
+
+	return declarationKey{
+		locationID: importedChecker.Location.ID(),
+		position:   *variable.Pos,
+	}, true
+}
+
+// memberDeclarationKey returns the key of the declaration of the given member
+//
+func memberDeclarationKey(member *sema.Member) (declarationKey, bool) {
+	if member.Predeclared {
+		return declarationKey{}, false
+	}
+
+	var location common.Location
+
+	switch containerType := member.ContainerType.(type) {
+	case *sema.CompositeType:
+		location = containerType.Location
+	case *sema.InterfaceType:
+		location = containerType.Location
+	}
+
+	if location == nil {
+		return declarationKey{}, false
+	}
+
+	return declarationKey{
+		locationID: location.ID(),
+		position:   member.Identifier.Pos,
+	}, true
 }
 
 func (s *Server) CodeAction(
@@ -1624,9 +1921,107 @@ func (s *Server) DocumentSymbol(
 		symbols = append(symbols, &symbol)
 	}
 
+	version := s.documents[uri].Version
+
+	for _, provider := range s.documentSymbolProviders {
+		var moreSymbols []*protocol.DocumentSymbol
+		moreSymbols, err = provider(uri, version, checker)
+		if err != nil {
+			return
+		}
+
+		symbols = append(symbols, moreSymbols...)
+	}
+
 	return
 }
 
+// WorkspaceSymbol returns the symbols of all open documents
+// which fuzzy-match the query, i.e. which contain all characters of the query
+// in the same order, ignoring case. An empty query matches all symbols.
+//
+func (s *Server) WorkspaceSymbol(
+	conn protocol.Conn,
+	params *protocol.WorkspaceSymbolParams,
+) (
+	symbols []*protocol.SymbolInformation,
+	err error,
+) {
+	// NOTE: Always initialize to an empty slice, i.e DON'T use nil:
+	// The later will be ignored instead of being treated as no items
+	symbols = []*protocol.SymbolInformation{}
+
+	uris := make([]protocol.DocumentUri, 0, len(s.documents))
+	for uri := range s.documents {
+		uris = append(uris, uri)
+	}
+	sort.Slice(uris, func(i, j int) bool {
+		return uris[i] < uris[j]
+	})
+
+	var appendSymbols func(uri protocol.DocumentUri, documentSymbols []protocol.DocumentSymbol, containerName string)
+	appendSymbols = func(uri protocol.DocumentUri, documentSymbols []protocol.DocumentSymbol, containerName string) {
+		for _, documentSymbol := range documentSymbols {
+			if fuzzyMatch(params.Query, documentSymbol.Name) {
+				symbols = append(symbols,
+					&protocol.SymbolInformation{
+						Name: documentSymbol.Name,
+						Kind: documentSymbol.Kind,
+						Location: protocol.Location{
+							URI:   uri,
+							Range: documentSymbol.Range,
+						},
+						ContainerName: containerName,
+					},
+				)
+			}
+
+			appendSymbols(uri, documentSymbol.Children, documentSymbol.Name)
+		}
+	}
+
+	for _, uri := range uris {
+		var documentSymbols []*protocol.DocumentSymbol
+		documentSymbols, err = s.DocumentSymbol(
+			conn,
+			&protocol.DocumentSymbolParams{
+				TextDocument: protocol.TextDocumentIdentifier{
+					URI: uri,
+				},
+			},
+		)
+		if err != nil {
+			return
+		}
+
+		for _, documentSymbol := range documentSymbols {
+			appendSymbols(uri, []protocol.DocumentSymbol{*documentSymbol}, "")
+		}
+	}
+
+	return
+}
+
+// fuzzyMatch returns true if the name contains all characters of the query
+// in the same order, ignoring case
+//
+func fuzzyMatch(query, name string) bool {
+	nameRunes := []rune(strings.ToLower(name))
+
+	i := 0
+	for _, queryRune := range strings.ToLower(query) {
+		for i < len(nameRunes) && nameRunes[i] != queryRune {
+			i++
+		}
+		if i == len(nameRunes) {
+			return false
+		}
+		i++
+	}
+
+	return true
+}
+
 // Shutdown tells the server to stop accepting any new requests. This can only
 // be followed by a call to Exit, which exits the process.
 func (*Server) Shutdown(conn protocol.Conn) error {
@@ -2181,6 +2576,20 @@ func (s *Server) convertError(
 			)
 		}
 
+		codeActionsResolver = combineCodeActionsResolvers(
+			s.maybeAddImportCodeActionsResolver(diagnostic, uri, err.Name),
+			codeActionsResolver,
+		)
+
+	case *sema.ResourceLossError:
+		codeActionsResolver = s.maybeDestroyResourceCodeActionsResolver(diagnostic, uri, err)
+
+	case *sema.AssignmentToConstantError:
+		codeActionsResolver = s.maybeVariableKindChangeCodeActionsResolver(diagnostic, uri, err.StartPos)
+
+	case *sema.AssignmentToConstantMemberError:
+		codeActionsResolver = s.maybeFieldKindChangeCodeActionsResolver(diagnostic, uri, err.StartPos)
+
 	case *sema.NotDeclaredMemberError:
 		var declarationGetter func(elaboration *sema.Elaboration) ast.Declaration
 
@@ -2490,6 +2899,454 @@ func formatNewMember(member *sema.Member, indentation string) string {
 	}
 }
 
+// maybeDestroyResourceCodeActionsResolver returns a resolver for a code action
+// which fixes the loss of a resource by destroying it.
+//
+// If the lost resource is a variable or parameter, it is destroyed at the end of its block,
+// i.e. before a final return statement, or before the end of the block.
+// If the lost resource is the result of an expression statement, the expression is destroyed.
+//
+func (s *Server) maybeDestroyResourceCodeActionsResolver(
+	diagnostic protocol.Diagnostic,
+	uri protocol.DocumentUri,
+	err *sema.ResourceLossError,
+) func() []*protocol.CodeAction {
+
+	document, ok := s.documents[uri]
+	if !ok {
+		return nil
+	}
+
+	checker := s.checkerForDocument(uri)
+	if checker == nil {
+		return nil
+	}
+
+	// Find the declaration of the lost variable or parameter,
+	// or the expression statement of the lost expression
+
+	var name string
+	var block *ast.Block
+
+	element, parents := findElement(checker.Program, func(element ast.Element) bool {
+		switch element := element.(type) {
+		case *ast.VariableDeclaration:
+			if element.Identifier.Pos == err.StartPos {
+				name = element.Identifier.Identifier
+				return true
+			}
+
+		case *ast.ExpressionStatement:
+			return element.Expression.StartPosition() == err.StartPos &&
+				element.Expression.EndPosition() == err.EndPos
+
+		case *ast.FunctionDeclaration:
+			block = parameterFunctionBlock(element.ParameterList, element.FunctionBlock, err.StartPos)
+			if block != nil {
+				name = parameterName(element.ParameterList, err.StartPos)
+				return true
+			}
+
+		case *ast.SpecialFunctionDeclaration:
+			function := element.FunctionDeclaration
+			block = parameterFunctionBlock(function.ParameterList, function.FunctionBlock, err.StartPos)
+			if block != nil {
+				name = parameterName(function.ParameterList, err.StartPos)
+				return true
+			}
+
+		case *ast.FunctionExpression:
+			block = parameterFunctionBlock(element.ParameterList, element.FunctionBlock, err.StartPos)
+			if block != nil {
+				name = parameterName(element.ParameterList, err.StartPos)
+				return true
+			}
+		}
+
+		return false
+	})
+
+	if element == nil {
+		return nil
+	}
+
+	if _, ok := element.(*ast.ExpressionStatement); ok {
+		return func() []*protocol.CodeAction {
+			return []*protocol.CodeAction{
+				quickFixCodeAction(
+					"Destroy resource",
+					diagnostic,
+					uri,
+					true,
+					insertionTextEdit(err.StartPos, "destroy "),
+				),
+			}
+		}
+	}
+
+	if block == nil {
+		for i := len(parents) - 1; i >= 0; i-- {
+			if parentBlock, ok := parents[i].(*ast.Block); ok {
+				block = parentBlock
+				break
+			}
+		}
+	}
+
+	if block == nil {
+		return nil
+	}
+
+	return func() []*protocol.CodeAction {
+
+		text := document.Text
+		destroyStatement := fmt.Sprintf("destroy %s", name)
+
+		// Insert before a final return statement, if any
+
+		anchorPos := block.EndPos
+		statementCount := len(block.Statements)
+		if statementCount > 0 {
+			if returnStatement, ok := block.Statements[statementCount-1].(*ast.ReturnStatement); ok {
+				anchorPos = returnStatement.StartPos
+			}
+		}
+
+		var textEdit protocol.TextEdit
+
+		switch {
+		case !isFirstOnLine(text, anchorPos):
+			textEdit = insertionTextEdit(anchorPos, destroyStatement+"; ")
+
+		case anchorPos != block.EndPos:
+			textEdit = insertionTextEdit(
+				anchorPos,
+				destroyStatement+"\n"+extractIndentation(text, anchorPos),
+			)
+
+		default:
+			var indentation string
+			if statementCount > 0 {
+				indentation = extractIndentation(text, block.Statements[0].StartPosition())
+			} else {
+				indentation = extractIndentation(text, anchorPos) + strings.Repeat(" ", indentationCount)
+			}
+
+			textEdit = insertionTextEdit(
+				lineStartPosition(text, anchorPos),
+				indentation+destroyStatement+"\n",
+			)
+		}
+
+		return []*protocol.CodeAction{
+			quickFixCodeAction(
+				fmt.Sprintf("Destroy `%s`", name),
+				diagnostic,
+				uri,
+				true,
+				textEdit,
+			),
+		}
+	}
+}
+
+// parameterFunctionBlock returns the block of the given function block
+// if the given parameter list has a parameter at the given position
+//
+func parameterFunctionBlock(
+	parameterList *ast.ParameterList,
+	functionBlock *ast.FunctionBlock,
+	pos ast.Position,
+) *ast.Block {
+	if functionBlock == nil || parameterName(parameterList, pos) == "" {
+		return nil
+	}
+	return functionBlock.Block
+}
+
+// parameterName returns the name of the parameter at the given position, if any
+//
+func parameterName(parameterList *ast.ParameterList, pos ast.Position) string {
+	if parameterList == nil {
+		return ""
+	}
+
+	for _, parameter := range parameterList.Parameters {
+		if parameter.Identifier.Pos == pos {
+			return parameter.Identifier.Identifier
+		}
+	}
+
+	return ""
+}
+
+// maybeVariableKindChangeCodeActionsResolver returns a resolver for a code action
+// which fixes the assignment to a constant variable declared in the document
+// by declaring the variable as a variable instead of a constant.
+//
+func (s *Server) maybeVariableKindChangeCodeActionsResolver(
+	diagnostic protocol.Diagnostic,
+	uri protocol.DocumentUri,
+	targetPos ast.Position,
+) func() []*protocol.CodeAction {
+
+	checker := s.checkerForDocument(uri)
+	if checker == nil {
+		return nil
+	}
+
+	occurrence := checker.Occurrences.Find(sema.ASTToSemaPosition(targetPos))
+	if occurrence == nil ||
+		occurrence.Origin == nil ||
+		occurrence.Origin.StartPos == nil {
+
+		return nil
+	}
+
+	declarationPos := *occurrence.Origin.StartPos
+
+	element, _ := findElement(checker.Program, func(element ast.Element) bool {
+		declaration, ok := element.(*ast.VariableDeclaration)
+		return ok &&
+			declaration.IsConstant &&
+			declaration.Identifier.Pos == declarationPos
+	})
+
+	if element == nil {
+		return nil
+	}
+
+	declaration := element.(*ast.VariableDeclaration)
+
+	return s.maybeKeywordChangeCodeActionsResolver(
+		diagnostic,
+		uri,
+		declaration.StartPos,
+		declaration.Identifier,
+	)
+}
+
+// maybeFieldKindChangeCodeActionsResolver returns a resolver for a code action
+// which fixes the assignment to a constant field declared in the document
+// by declaring the field as a variable field instead of a constant field.
+//
+func (s *Server) maybeFieldKindChangeCodeActionsResolver(
+	diagnostic protocol.Diagnostic,
+	uri protocol.DocumentUri,
+	targetPos ast.Position,
+) func() []*protocol.CodeAction {
+
+	checker := s.checkerForDocument(uri)
+	if checker == nil {
+		return nil
+	}
+
+	var member *sema.Member
+	for memberExpression, memberInfo := range checker.Elaboration.MemberExpressionMemberInfos {
+		if memberExpression.Identifier.Pos == targetPos {
+			member = memberInfo.Member
+			break
+		}
+	}
+
+	if member == nil {
+		return nil
+	}
+
+	// Only fields declared in the document can be changed
+
+	key, ok := memberDeclarationKey(member)
+	if !ok || key.locationID != checker.Location.ID() {
+		return nil
+	}
+
+	element, _ := findElement(checker.Program, func(element ast.Element) bool {
+		declaration, ok := element.(*ast.FieldDeclaration)
+		return ok &&
+			declaration.VariableKind == ast.VariableKindConstant &&
+			declaration.Identifier.Pos == member.Identifier.Pos
+	})
+
+	if element == nil {
+		return nil
+	}
+
+	declaration := element.(*ast.FieldDeclaration)
+
+	return s.maybeKeywordChangeCodeActionsResolver(
+		diagnostic,
+		uri,
+		declaration.StartPos,
+		declaration.Identifier,
+	)
+}
+
+// maybeKeywordChangeCodeActionsResolver returns a resolver for a code action
+// which replaces the `let` keyword preceding the given identifier with the `var` keyword
+//
+func (s *Server) maybeKeywordChangeCodeActionsResolver(
+	diagnostic protocol.Diagnostic,
+	uri protocol.DocumentUri,
+	declarationStartPos ast.Position,
+	identifier ast.Identifier,
+) func() []*protocol.CodeAction {
+
+	document, ok := s.documents[uri]
+	if !ok {
+		return nil
+	}
+
+	constantKeyword := ast.VariableKindConstant.Keyword()
+	variableKeyword := ast.VariableKindVariable.Keyword()
+
+	keywordPos, ok := findLastKeyword(
+		document.Text,
+		declarationStartPos,
+		identifier.Pos,
+		constantKeyword,
+	)
+	if !ok {
+		return nil
+	}
+
+	return func() []*protocol.CodeAction {
+		return []*protocol.CodeAction{
+			quickFixCodeAction(
+				fmt.Sprintf(
+					"Change `%s` to `%s`",
+					constantKeyword,
+					variableKeyword,
+				),
+				diagnostic,
+				uri,
+				true,
+				replacementTextEdit(
+					keywordPos,
+					keywordPos.Shifted(len(constantKeyword)-1),
+					variableKeyword,
+				),
+			),
+		}
+	}
+}
+
+// maybeAddImportCodeActionsResolver returns a resolver for code actions
+// which fix an undeclared name by importing it from one of the other open documents
+// that declare it.
+//
+// If the document already imports some declarations from the other document,
+// the name is added to the existing import declaration.
+//
+func (s *Server) maybeAddImportCodeActionsResolver(
+	diagnostic protocol.Diagnostic,
+	uri protocol.DocumentUri,
+	name string,
+) func() []*protocol.CodeAction {
+
+	if !isPathLocation(uriToLocation(uri)) {
+		return nil
+	}
+
+	return func() []*protocol.CodeAction {
+
+		checker := s.checkerForDocument(uri)
+		if checker == nil {
+			return nil
+		}
+
+		location := uriToLocation(uri)
+
+		uris := make([]protocol.DocumentUri, 0, len(s.documents))
+		for otherURI := range s.documents {
+			uris = append(uris, otherURI)
+		}
+		sort.Slice(uris, func(i, j int) bool {
+			return uris[i] < uris[j]
+		})
+
+		var codeActions []*protocol.CodeAction
+
+		for _, otherURI := range uris {
+			if otherURI == uri {
+				continue
+			}
+
+			otherChecker := s.checkerForDocument(otherURI)
+			if otherChecker == nil || !declaresGlobal(otherChecker.Elaboration, name) {
+				continue
+			}
+
+			otherLocation := uriToLocation(otherURI)
+			importPath := relativeImportPath(string(location), string(otherLocation))
+
+			var textEdit protocol.TextEdit
+
+			importDeclarations := checker.Program.ImportDeclarations()
+
+			var existingImportDeclaration *ast.ImportDeclaration
+			for _, importDeclaration := range importDeclarations {
+				importedLocation := normalizePathLocation(location, importDeclaration.Location)
+				if importedLocation == otherLocation && len(importDeclaration.Identifiers) > 0 {
+					existingImportDeclaration = importDeclaration
+					break
+				}
+			}
+
+			switch {
+			case existingImportDeclaration != nil:
+				identifiers := existingImportDeclaration.Identifiers
+				lastIdentifier := identifiers[len(identifiers)-1]
+				textEdit = insertionTextEdit(
+					lastIdentifier.EndPosition().Shifted(1),
+					fmt.Sprintf(", %s", name),
+				)
+
+			case len(importDeclarations) > 0:
+				lastImportDeclaration := importDeclarations[len(importDeclarations)-1]
+				textEdit = insertionTextEdit(
+					lastImportDeclaration.EndPosition().Shifted(1),
+					fmt.Sprintf("\nimport %s from %q", name, importPath),
+				)
+
+			default:
+				textEdit = insertionTextEdit(
+					ast.Position{Line: 1},
+					fmt.Sprintf("import %s from %q\n\n", name, importPath),
+				)
+			}
+
+			codeActions = append(codeActions,
+				quickFixCodeAction(
+					fmt.Sprintf("Import `%s` from %q", name, importPath),
+					diagnostic,
+					uri,
+					false,
+					textEdit,
+				),
+			)
+		}
+
+		return codeActions
+	}
+}
+
+// declaresGlobal returns true if the given elaboration declares a global value or type
+// with the given name, i.e. it is not imported or predeclared
+//
+func declaresGlobal(elaboration *sema.Elaboration, name string) bool {
+	for _, variables := range []*sema.StringVariableOrderedMap{
+		elaboration.GlobalTypes,
+		elaboration.GlobalValues,
+	} {
+		variable, ok := variables.Get(name)
+		if ok && variable.Pos != nil && *variable.Pos != (ast.Position{}) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (s *Server) maybeAddDeclarationActionsResolver(
 	diagnostic protocol.Diagnostic,
 	uri protocol.DocumentUri,
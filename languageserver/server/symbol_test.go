@@ -0,0 +1,158 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/languageserver/protocol"
+)
+
+func TestServer_WorkspaceSymbol(t *testing.T) {
+
+	t.Parallel()
+
+	const contractURI protocol.DocumentUri = "file:///test/contract.cdc"
+	const contractCode = `
+pub contract Market {
+
+    pub event Listed(id: UInt64)
+
+    pub resource Listing {
+        pub let price: UFix64
+
+        init(price: UFix64) {
+            self.price = price
+        }
+    }
+
+    pub fun createListing(price: UFix64): @Listing {
+        return <-create Listing(price: price)
+    }
+}
+`
+
+	const scriptURI protocol.DocumentUri = "file:///test/script.cdc"
+	const scriptCode = `
+pub struct Listing {}
+
+pub fun main() {}
+`
+
+	server, err := NewServer()
+	require.NoError(t, err)
+
+	for _, document := range []protocol.TextDocumentItem{
+		{URI: scriptURI, Text: scriptCode},
+		{URI: contractURI, Text: contractCode},
+	} {
+		err = server.DidOpenTextDocument(
+			testConn{},
+			&protocol.DidOpenTextDocumentParams{
+				TextDocument: document,
+			},
+		)
+		require.NoError(t, err)
+	}
+
+	type symbol struct {
+		name          string
+		kind          protocol.SymbolKind
+		uri           protocol.DocumentUri
+		containerName string
+	}
+
+	workspaceSymbols := func(t *testing.T, query string) []symbol {
+		symbolInformations, err := server.WorkspaceSymbol(
+			testConn{},
+			&protocol.WorkspaceSymbolParams{
+				Query: query,
+			},
+		)
+		require.NoError(t, err)
+
+		symbols := make([]symbol, 0, len(symbolInformations))
+		for _, symbolInformation := range symbolInformations {
+			symbols = append(symbols,
+				symbol{
+					name:          symbolInformation.Name,
+					kind:          symbolInformation.Kind,
+					uri:           symbolInformation.Location.URI,
+					containerName: symbolInformation.ContainerName,
+				},
+			)
+		}
+		return symbols
+	}
+
+	t.Run("all", func(t *testing.T) {
+
+		t.Parallel()
+
+		assert.Equal(t,
+			[]symbol{
+				{"Market", protocol.Class, contractURI, ""},
+				{"Listed", protocol.Event, contractURI, "Market"},
+				{"id", protocol.Field, contractURI, "Listed"},
+				{"Listing", protocol.Class, contractURI, "Market"},
+				{"price", protocol.Field, contractURI, "Listing"},
+				{"init", protocol.Constructor, contractURI, "Listing"},
+				{"createListing", protocol.Function, contractURI, "Market"},
+				{"Listing", protocol.Struct, scriptURI, ""},
+				{"main", protocol.Function, scriptURI, ""},
+			},
+			workspaceSymbols(t, ""),
+		)
+	})
+
+	t.Run("fuzzy", func(t *testing.T) {
+
+		t.Parallel()
+
+		assert.Equal(t,
+			[]symbol{
+				{"Listing", protocol.Class, contractURI, "Market"},
+				{"createListing", protocol.Function, contractURI, "Market"},
+				{"Listing", protocol.Struct, scriptURI, ""},
+			},
+			workspaceSymbols(t, "lstng"),
+		)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+
+		t.Parallel()
+
+		assert.Empty(t, workspaceSymbols(t, "xyz"))
+	})
+}
+
+func TestFuzzyMatch(t *testing.T) {
+
+	t.Parallel()
+
+	assert.True(t, fuzzyMatch("", "Market"))
+	assert.True(t, fuzzyMatch("mkt", "Market"))
+	assert.True(t, fuzzyMatch("MARKET", "Market"))
+	assert.False(t, fuzzyMatch("tkm", "Market"))
+	assert.False(t, fuzzyMatch("Markets", "Market"))
+}
@@ -0,0 +1,833 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cadence
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// marshalTagName is the name of the struct tag which maps a Go struct field to a Cadence composite field.
+//
+// The tag value is the name of the Cadence field, e.g. `cadence:"amount"`.
+// The value "-" excludes the Go field. Untagged exported fields are mapped
+// to the Cadence field with the Go field name, with the first letter lower-cased.
+//
+const marshalTagName = "cadence"
+
+var (
+	valueInterfaceType = reflect.TypeOf((*Value)(nil)).Elem()
+	bigIntType         = reflect.TypeOf(big.Int{})
+	bigIntPointerType  = reflect.TypeOf(&big.Int{})
+)
+
+// Marshal converts the given Go value to a Cadence value of the given target type.
+//
+// Go structs are converted to composites, slices and arrays to arrays,
+// maps to dictionaries, and pointers to optionals, recursively.
+// Go values which already are Cadence values are returned as-is.
+//
+// Integers are range-checked against the target type.
+// Fixed-point values must be given as decimal strings, e.g. "1.5".
+//
+func Marshal(goValue interface{}, targetType Type) (Value, error) {
+	if targetType == nil {
+		return nil, fmt.Errorf("cannot marshal Go value of type %T: missing target type", goValue)
+	}
+
+	return marshalValue(reflect.ValueOf(goValue), targetType)
+}
+
+// MustMarshal converts the given Go value to a Cadence value of the given target type,
+// or panics if the value cannot be converted.
+//
+func MustMarshal(goValue interface{}, targetType Type) Value {
+	value, err := Marshal(goValue, targetType)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+func marshalValue(rv reflect.Value, targetType Type) (Value, error) {
+
+	// Handle optionals first, as nil Go values are only valid for optional target types.
+	// Nil maps and slices are also marshaled as nil
+
+	if optionalType, ok := targetType.(OptionalType); ok {
+		rv = indirect(rv)
+		if rv.IsValid() && rv.Type() == reflect.TypeOf(Optional{}) {
+			return rv.Interface().(Optional), nil
+		}
+		if !rv.IsValid() ||
+			(rv.Kind() == reflect.Map || rv.Kind() == reflect.Slice) && rv.IsNil() {
+
+			return NewOptional(nil), nil
+		}
+
+		value, err := marshalValue(rv, optionalType.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewOptional(value), nil
+	}
+
+	rv = indirect(rv)
+	if !rv.IsValid() {
+		return nil, fmt.Errorf("cannot marshal nil to non-optional Cadence type %s", targetType.ID())
+	}
+
+	if rv.Type().Implements(valueInterfaceType) {
+		return rv.Interface().(Value), nil
+	}
+
+	switch targetType := targetType.(type) {
+	case AnyType, AnyStructType:
+		return marshalInferred(rv)
+
+	case BoolType:
+		if rv.Kind() == reflect.Bool {
+			return NewBool(rv.Bool()), nil
+		}
+
+	case StringType:
+		if rv.Kind() == reflect.String {
+			return NewString(rv.String())
+		}
+
+	case BytesType:
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return NewBytes(rv.Bytes()), nil
+		}
+
+	case AddressType:
+		if rv.Kind() == reflect.Array &&
+			rv.Type().Elem().Kind() == reflect.Uint8 &&
+			rv.Len() == AddressLength {
+
+			var address Address
+			reflect.Copy(reflect.ValueOf(&address).Elem(), rv)
+			return address, nil
+		}
+
+	case Fix64Type:
+		if rv.Kind() == reflect.String {
+			return NewFix64(rv.String())
+		}
+
+	case UFix64Type:
+		if rv.Kind() == reflect.String {
+			return NewUFix64(rv.String())
+		}
+
+	case IntType, Int8Type, Int16Type, Int32Type, Int64Type, Int128Type, Int256Type,
+		UIntType, UInt8Type, UInt16Type, UInt32Type, UInt64Type, UInt128Type, UInt256Type,
+		Word8Type, Word16Type, Word32Type, Word64Type:
+
+		if integer, ok := goBigInt(rv); ok {
+			return marshalInteger(integer, targetType)
+		}
+
+	case VariableSizedArrayType:
+		if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+			return marshalArray(rv, targetType.ElementType)
+		}
+
+	case ConstantSizedArrayType:
+		if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+			if uint(rv.Len()) != targetType.Size {
+				return nil, fmt.Errorf(
+					"cannot marshal Go value of type %s with length %d to Cadence type %s",
+					rv.Type(),
+					rv.Len(),
+					targetType.ID(),
+				)
+			}
+			return marshalArray(rv, targetType.ElementType)
+		}
+
+	case DictionaryType:
+		if rv.Kind() == reflect.Map {
+			return marshalDictionary(rv, targetType)
+		}
+
+	case CompositeType:
+		if rv.Kind() == reflect.Struct {
+			return marshalComposite(rv, targetType)
+		}
+	}
+
+	return nil, fmt.Errorf(
+		"cannot marshal Go value of type %s to Cadence type %s",
+		rv.Type(),
+		targetType.ID(),
+	)
+}
+
+// indirect dereferences the given pointers and interfaces until a non-pointer value is reached.
+// The result is invalid if a nil pointer or nil interface is encountered.
+//
+func indirect(rv reflect.Value) reflect.Value {
+	for rv.IsValid() && (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) {
+		if rv.IsNil() {
+			return reflect.Value{}
+		}
+		rv = rv.Elem()
+	}
+	return rv
+}
+
+// marshalInferred converts the given Go value to a Cadence value
+// for a target type which does not determine the result type, i.e. AnyStruct.
+//
+// The Cadence type is inferred from the Go type, like NewValue does.
+//
+func marshalInferred(rv reflect.Value) (Value, error) {
+	switch rv.Kind() {
+	case reflect.Bool:
+		return NewBool(rv.Bool()), nil
+	case reflect.String:
+		return NewString(rv.String())
+	case reflect.Int:
+		return marshalValue(rv, IntType{})
+	case reflect.Int8:
+		return marshalValue(rv, Int8Type{})
+	case reflect.Int16:
+		return marshalValue(rv, Int16Type{})
+	case reflect.Int32:
+		return marshalValue(rv, Int32Type{})
+	case reflect.Int64:
+		return marshalValue(rv, Int64Type{})
+	case reflect.Uint:
+		return marshalValue(rv, UIntType{})
+	case reflect.Uint8:
+		return marshalValue(rv, UInt8Type{})
+	case reflect.Uint16:
+		return marshalValue(rv, UInt16Type{})
+	case reflect.Uint32:
+		return marshalValue(rv, UInt32Type{})
+	case reflect.Uint64:
+		return marshalValue(rv, UInt64Type{})
+	case reflect.Slice, reflect.Array:
+		return marshalArray(rv, AnyStructType{})
+	case reflect.Map:
+		return marshalDictionary(rv, DictionaryType{
+			KeyType:     AnyStructType{},
+			ElementType: AnyStructType{},
+		})
+	case reflect.Struct:
+		if rv.Type() == bigIntType {
+			return marshalValue(rv, IntType{})
+		}
+	}
+
+	return nil, fmt.Errorf("cannot infer Cadence type for Go value of type %s", rv.Type())
+}
+
+// goBigInt returns the given Go integer value as a big integer.
+//
+func goBigInt(rv reflect.Value) (*big.Int, bool) {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), true
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Int).SetUint64(rv.Uint()), true
+
+	case reflect.Struct:
+		if rv.Type() == bigIntType {
+			integer := rv.Interface().(big.Int)
+			return &integer, true
+		}
+	}
+
+	return nil, false
+}
+
+func marshalInteger(integer *big.Int, targetType Type) (Value, error) {
+
+	checkRange := func(min, max *big.Int) error {
+		if integer.Cmp(min) < 0 || integer.Cmp(max) > 0 {
+			return fmt.Errorf(
+				"cannot marshal integer %s to Cadence type %s: out of range",
+				integer,
+				targetType.ID(),
+			)
+		}
+		return nil
+	}
+
+	switch targetType.(type) {
+	case IntType:
+		return NewIntFromBig(integer), nil
+
+	case Int8Type:
+		if err := checkRange(sema.Int8TypeMinInt, sema.Int8TypeMaxInt); err != nil {
+			return nil, err
+		}
+		return NewInt8(int8(integer.Int64())), nil
+
+	case Int16Type:
+		if err := checkRange(sema.Int16TypeMinInt, sema.Int16TypeMaxInt); err != nil {
+			return nil, err
+		}
+		return NewInt16(int16(integer.Int64())), nil
+
+	case Int32Type:
+		if err := checkRange(sema.Int32TypeMinInt, sema.Int32TypeMaxInt); err != nil {
+			return nil, err
+		}
+		return NewInt32(int32(integer.Int64())), nil
+
+	case Int64Type:
+		if err := checkRange(sema.Int64TypeMinInt, sema.Int64TypeMaxInt); err != nil {
+			return nil, err
+		}
+		return NewInt64(integer.Int64()), nil
+
+	case Int128Type:
+		return NewInt128FromBig(integer)
+
+	case Int256Type:
+		return NewInt256FromBig(integer)
+
+	case UIntType:
+		return NewUIntFromBig(integer)
+
+	case UInt8Type:
+		if err := checkRange(sema.UInt8TypeMinInt, sema.UInt8TypeMaxInt); err != nil {
+			return nil, err
+		}
+		return NewUInt8(uint8(integer.Uint64())), nil
+
+	case UInt16Type:
+		if err := checkRange(sema.UInt16TypeMinInt, sema.UInt16TypeMaxInt); err != nil {
+			return nil, err
+		}
+		return NewUInt16(uint16(integer.Uint64())), nil
+
+	case UInt32Type:
+		if err := checkRange(sema.UInt32TypeMinInt, sema.UInt32TypeMaxInt); err != nil {
+			return nil, err
+		}
+		return NewUInt32(uint32(integer.Uint64())), nil
+
+	case UInt64Type:
+		if err := checkRange(sema.UInt64TypeMinInt, sema.UInt64TypeMaxInt); err != nil {
+			return nil, err
+		}
+		return NewUInt64(integer.Uint64()), nil
+
+	case UInt128Type:
+		return NewUInt128FromBig(integer)
+
+	case UInt256Type:
+		return NewUInt256FromBig(integer)
+
+	case Word8Type:
+		if err := checkRange(sema.Word8TypeMinInt, sema.Word8TypeMaxInt); err != nil {
+			return nil, err
+		}
+		return NewWord8(uint8(integer.Uint64())), nil
+
+	case Word16Type:
+		if err := checkRange(sema.Word16TypeMinInt, sema.Word16TypeMaxInt); err != nil {
+			return nil, err
+		}
+		return NewWord16(uint16(integer.Uint64())), nil
+
+	case Word32Type:
+		if err := checkRange(sema.Word32TypeMinInt, sema.Word32TypeMaxInt); err != nil {
+			return nil, err
+		}
+		return NewWord32(uint32(integer.Uint64())), nil
+
+	case Word64Type:
+		if err := checkRange(sema.Word64TypeMinInt, sema.Word64TypeMaxInt); err != nil {
+			return nil, err
+		}
+		return NewWord64(integer.Uint64()), nil
+	}
+
+	return nil, fmt.Errorf("cannot marshal integer to Cadence type %s", targetType.ID())
+}
+
+func marshalArray(rv reflect.Value, elementType Type) (Value, error) {
+	values := make([]Value, rv.Len())
+
+	for i := 0; i < rv.Len(); i++ {
+		value, err := marshalValue(rv.Index(i), elementType)
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal array element %d: %w", i, err)
+		}
+		values[i] = value
+	}
+
+	return NewArray(values), nil
+}
+
+func marshalDictionary(rv reflect.Value, dictionaryType DictionaryType) (Value, error) {
+	keys := rv.MapKeys()
+	sortMapKeys(keys)
+
+	pairs := make([]KeyValuePair, len(keys))
+
+	for i, key := range keys {
+		keyValue, err := marshalValue(key, dictionaryType.KeyType)
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal dictionary key: %w", err)
+		}
+
+		elementValue, err := marshalValue(rv.MapIndex(key), dictionaryType.ElementType)
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal dictionary value for key %s: %w", keyValue, err)
+		}
+
+		pairs[i] = KeyValuePair{
+			Key:   keyValue,
+			Value: elementValue,
+		}
+	}
+
+	return NewDictionary(pairs), nil
+}
+
+// sortMapKeys sorts the given map keys, so the order of the marshaled dictionary pairs is deterministic.
+//
+func sortMapKeys(keys []reflect.Value) {
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+
+		switch a.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return a.Int() < b.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return a.Uint() < b.Uint()
+		case reflect.String:
+			return a.String() < b.String()
+		default:
+			return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+		}
+	})
+}
+
+func marshalComposite(rv reflect.Value, compositeType CompositeType) (Value, error) {
+	goFields := structFieldsByCadenceName(rv.Type())
+
+	var fields []Value
+
+	for _, field := range compositeType.CompositeFields() {
+		if _, ok := field.Type.(Function); ok {
+			continue
+		}
+
+		index, ok := goFields[field.Identifier]
+		if !ok {
+			return nil, fmt.Errorf(
+				"cannot marshal Go value of type %s to Cadence type %s: missing field %s",
+				rv.Type(),
+				compositeType.ID(),
+				field.Identifier,
+			)
+		}
+
+		value, err := marshalValue(rv.FieldByIndex(index), field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal field %s: %w", field.Identifier, err)
+		}
+
+		fields = append(fields, value)
+	}
+
+	switch compositeType := compositeType.(type) {
+	case *StructType:
+		return NewStruct(fields).WithType(compositeType), nil
+	case *ResourceType:
+		return NewResource(fields).WithType(compositeType), nil
+	case *EventType:
+		return NewEvent(fields).WithType(compositeType), nil
+	case *ContractType:
+		return NewContract(fields).WithType(compositeType), nil
+	case *EnumType:
+		return NewEnum(fields).WithType(compositeType), nil
+	}
+
+	return nil, fmt.Errorf("cannot marshal to unsupported composite type %s", compositeType.ID())
+}
+
+// structFieldsByCadenceName returns the indices of the fields of the given Go struct type,
+// keyed by the name of the Cadence field they are mapped to.
+//
+// Fields of embedded structs are promoted, like in encoding/json.
+//
+func structFieldsByCadenceName(structType reflect.Type) map[string][]int {
+	result := map[string][]int{}
+
+	var collect func(structType reflect.Type, parentIndex []int)
+	collect = func(structType reflect.Type, parentIndex []int) {
+		for i := 0; i < structType.NumField(); i++ {
+			field := structType.Field(i)
+
+			index := make([]int, len(parentIndex), len(parentIndex)+1)
+			copy(index, parentIndex)
+			index = append(index, i)
+
+			tag := field.Tag.Get(marshalTagName)
+			if tag == "-" {
+				continue
+			}
+
+			name := tag
+			if comma := strings.IndexByte(tag, ','); comma >= 0 {
+				name = tag[:comma]
+			}
+
+			if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+				collect(field.Type, index)
+				continue
+			}
+
+			// Unexported fields cannot be set or read
+
+			if field.PkgPath != "" {
+				continue
+			}
+
+			if name == "" {
+				name = defaultCadenceFieldName(field.Name)
+			}
+
+			// Fields of outer structs take precedence over promoted fields
+
+			if _, ok := result[name]; ok && len(parentIndex) > 0 {
+				continue
+			}
+
+			result[name] = index
+		}
+	}
+
+	collect(structType, nil)
+
+	return result
+}
+
+// defaultCadenceFieldName returns the name of the Cadence field an untagged Go field is mapped to,
+// i.e. the Go field name with the first letter lower-cased.
+//
+func defaultCadenceFieldName(name string) string {
+	first, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(first)) + name[size:]
+}
+
+// Unmarshal converts the given Cadence value into the Go value pointed to by target.
+//
+// Composites are converted to Go structs, arrays to slices and arrays,
+// dictionaries to maps, and optionals to pointers, recursively.
+// Targets of type interface{} receive the result of ToGoValue,
+// targets of Cadence value types receive the value itself.
+//
+// Cadence composite fields without a corresponding Go struct field are ignored.
+// Integers are range-checked against the target type.
+// Fixed-point values can only be unmarshaled into strings.
+//
+func Unmarshal(value Value, target interface{}) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("cannot unmarshal into non-pointer or nil target of type %T", target)
+	}
+
+	return unmarshalValue(value, rv.Elem())
+}
+
+func unmarshalValue(value Value, rv reflect.Value) error {
+
+	valueType := reflect.TypeOf(value)
+	targetType := rv.Type()
+
+	// Cadence values are stored as-is
+
+	if valueType == targetType ||
+		targetType.Kind() == reflect.Interface &&
+			targetType.NumMethod() > 0 &&
+			valueType != nil &&
+			valueType.Implements(targetType) {
+
+		rv.Set(reflect.ValueOf(value))
+		return nil
+	}
+
+	if optional, ok := value.(Optional); ok {
+		if optional.Value == nil {
+			switch rv.Kind() {
+			case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+				rv.Set(reflect.Zero(targetType))
+				return nil
+			}
+
+			return fmt.Errorf("cannot unmarshal nil into Go value of type %s", targetType)
+		}
+
+		return unmarshalValue(optional.Value, rv)
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface:
+		if targetType.NumMethod() == 0 {
+			goValue := value.ToGoValue()
+			if goValue != nil {
+				rv.Set(reflect.ValueOf(goValue))
+			}
+			return nil
+		}
+
+	case reflect.Ptr:
+		if targetType == bigIntPointerType {
+			if integer, ok := cadenceBigInt(value); ok {
+				rv.Set(reflect.ValueOf(integer))
+				return nil
+			}
+			break
+		}
+
+		elem := reflect.New(targetType.Elem())
+		if err := unmarshalValue(value, elem.Elem()); err != nil {
+			return err
+		}
+		rv.Set(elem)
+		return nil
+
+	case reflect.Bool:
+		if value, ok := value.(Bool); ok {
+			rv.SetBool(bool(value))
+			return nil
+		}
+
+	case reflect.String:
+		switch value := value.(type) {
+		case String:
+			rv.SetString(string(value))
+			return nil
+		case Fix64:
+			rv.SetString(value.String())
+			return nil
+		case UFix64:
+			rv.SetString(value.String())
+			return nil
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if integer, ok := cadenceBigInt(value); ok {
+			if !integer.IsInt64() || rv.OverflowInt(integer.Int64()) {
+				return fmt.Errorf("cannot unmarshal %s into Go value of type %s: out of range", value, targetType)
+			}
+			rv.SetInt(integer.Int64())
+			return nil
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if integer, ok := cadenceBigInt(value); ok {
+			if !integer.IsUint64() || rv.OverflowUint(integer.Uint64()) {
+				return fmt.Errorf("cannot unmarshal %s into Go value of type %s: out of range", value, targetType)
+			}
+			rv.SetUint(integer.Uint64())
+			return nil
+		}
+
+	case reflect.Slice:
+		switch value := value.(type) {
+		case Bytes:
+			if targetType.Elem().Kind() == reflect.Uint8 {
+				rv.SetBytes(append([]byte{}, value...))
+				return nil
+			}
+
+		case Array:
+			slice := reflect.MakeSlice(targetType, len(value.Values), len(value.Values))
+			for i, element := range value.Values {
+				if err := unmarshalValue(element, slice.Index(i)); err != nil {
+					return fmt.Errorf("cannot unmarshal array element %d: %w", i, err)
+				}
+			}
+			rv.Set(slice)
+			return nil
+		}
+
+	case reflect.Array:
+		switch value := value.(type) {
+		case Address:
+			if targetType.Elem().Kind() == reflect.Uint8 && rv.Len() == AddressLength {
+				reflect.Copy(rv, reflect.ValueOf(value))
+				return nil
+			}
+
+		case Array:
+			if len(value.Values) != rv.Len() {
+				return fmt.Errorf(
+					"cannot unmarshal array of length %d into Go value of type %s",
+					len(value.Values),
+					targetType,
+				)
+			}
+			for i, element := range value.Values {
+				if err := unmarshalValue(element, rv.Index(i)); err != nil {
+					return fmt.Errorf("cannot unmarshal array element %d: %w", i, err)
+				}
+			}
+			return nil
+		}
+
+	case reflect.Map:
+		if value, ok := value.(Dictionary); ok {
+			result := reflect.MakeMapWithSize(targetType, len(value.Pairs))
+			for _, pair := range value.Pairs {
+				key := reflect.New(targetType.Key()).Elem()
+				if err := unmarshalValue(pair.Key, key); err != nil {
+					return fmt.Errorf("cannot unmarshal dictionary key: %w", err)
+				}
+
+				element := reflect.New(targetType.Elem()).Elem()
+				if err := unmarshalValue(pair.Value, element); err != nil {
+					return fmt.Errorf("cannot unmarshal dictionary value for key %s: %w", pair.Key, err)
+				}
+
+				result.SetMapIndex(key, element)
+			}
+			rv.Set(result)
+			return nil
+		}
+
+	case reflect.Struct:
+		if targetType == bigIntType {
+			if integer, ok := cadenceBigInt(value); ok {
+				rv.Set(reflect.ValueOf(*integer))
+				return nil
+			}
+			break
+		}
+
+		if compositeType, fields, ok := compositeTypeAndFields(value); ok {
+			return unmarshalComposite(compositeType, fields, rv)
+		}
+	}
+
+	return fmt.Errorf("cannot unmarshal %T into Go value of type %s", value, targetType)
+}
+
+// cadenceBigInt returns the given Cadence integer value as a big integer.
+//
+func cadenceBigInt(value Value) (*big.Int, bool) {
+	switch value := value.(type) {
+	case Int:
+		return new(big.Int).Set(value.Value), true
+	case Int8:
+		return big.NewInt(int64(value)), true
+	case Int16:
+		return big.NewInt(int64(value)), true
+	case Int32:
+		return big.NewInt(int64(value)), true
+	case Int64:
+		return big.NewInt(int64(value)), true
+	case Int128:
+		return new(big.Int).Set(value.Value), true
+	case Int256:
+		return new(big.Int).Set(value.Value), true
+	case UInt:
+		return new(big.Int).Set(value.Value), true
+	case UInt8:
+		return new(big.Int).SetUint64(uint64(value)), true
+	case UInt16:
+		return new(big.Int).SetUint64(uint64(value)), true
+	case UInt32:
+		return new(big.Int).SetUint64(uint64(value)), true
+	case UInt64:
+		return new(big.Int).SetUint64(uint64(value)), true
+	case UInt128:
+		return new(big.Int).Set(value.Value), true
+	case UInt256:
+		return new(big.Int).Set(value.Value), true
+	case Word8:
+		return new(big.Int).SetUint64(uint64(value)), true
+	case Word16:
+		return new(big.Int).SetUint64(uint64(value)), true
+	case Word32:
+		return new(big.Int).SetUint64(uint64(value)), true
+	case Word64:
+		return new(big.Int).SetUint64(uint64(value)), true
+	}
+
+	return nil, false
+}
+
+func compositeTypeAndFields(value Value) (CompositeType, []Value, bool) {
+	switch value := value.(type) {
+	case Struct:
+		return value.StructType, value.Fields, value.StructType != nil
+	case Resource:
+		return value.ResourceType, value.Fields, value.ResourceType != nil
+	case Event:
+		return value.EventType, value.Fields, value.EventType != nil
+	case Contract:
+		return value.ContractType, value.Fields, value.ContractType != nil
+	case Enum:
+		return value.EnumType, value.Fields, value.EnumType != nil
+	}
+
+	return nil, nil, false
+}
+
+func unmarshalComposite(compositeType CompositeType, fields []Value, rv reflect.Value) error {
+	goFields := structFieldsByCadenceName(rv.Type())
+
+	i := 0
+	for _, field := range compositeType.CompositeFields() {
+		if _, ok := field.Type.(Function); ok {
+			continue
+		}
+
+		if i >= len(fields) {
+			return fmt.Errorf(
+				"cannot unmarshal %s: field count does not match type",
+				compositeType.ID(),
+			)
+		}
+
+		value := fields[i]
+		i++
+
+		index, ok := goFields[field.Identifier]
+		if !ok {
+			continue
+		}
+
+		if err := unmarshalValue(value, rv.FieldByIndex(index)); err != nil {
+			return fmt.Errorf("cannot unmarshal field %s: %w", field.Identifier, err)
+		}
+	}
+
+	return nil
+}
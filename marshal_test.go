@@ -0,0 +1,423 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cadence
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+type marshalTestItem struct {
+	ID   uint64 `cadence:"id"`
+	Name string
+}
+
+type marshalTestCollection struct {
+	Owner    [8]byte                   `cadence:"owner"`
+	Items    []marshalTestItem         `cadence:"items"`
+	Balances map[string]uint64         `cadence:"balances"`
+	Note     *string                   `cadence:"note"`
+	Total    *big.Int                  `cadence:"total"`
+	Price    string                    `cadence:"price"`
+	Cached   int                       `cadence:"-"`
+	Extra    map[uint8]marshalTestItem `cadence:"extra"`
+}
+
+var marshalTestItemType = &StructType{
+	Location:            utils.TestLocation,
+	QualifiedIdentifier: "Item",
+	Fields: []Field{
+		{Identifier: "id", Type: UInt64Type{}},
+		{Identifier: "name", Type: StringType{}},
+	},
+}
+
+var marshalTestCollectionType = &ResourceType{
+	Location:            utils.TestLocation,
+	QualifiedIdentifier: "Collection",
+	Fields: []Field{
+		{Identifier: "uuid", Type: UInt64Type{}},
+		{Identifier: "owner", Type: AddressType{}},
+		{Identifier: "items", Type: VariableSizedArrayType{ElementType: marshalTestItemType}},
+		{
+			Identifier: "balances",
+			Type: DictionaryType{
+				KeyType:     StringType{},
+				ElementType: UInt64Type{},
+			},
+		},
+		{Identifier: "note", Type: OptionalType{Type: StringType{}}},
+		{Identifier: "total", Type: UInt256Type{}},
+		{Identifier: "price", Type: UFix64Type{}},
+		{
+			Identifier: "extra",
+			Type: OptionalType{
+				Type: DictionaryType{
+					KeyType:     UInt8Type{},
+					ElementType: marshalTestItemType,
+				},
+			},
+		},
+	},
+}
+
+func TestMarshal(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("composite", func(t *testing.T) {
+
+		t.Parallel()
+
+		type collection struct {
+			marshalTestCollection
+			UUID uint64 `cadence:"uuid"`
+		}
+
+		note := "hello"
+
+		value, err := Marshal(
+			collection{
+				marshalTestCollection: marshalTestCollection{
+					Owner: [8]byte{0, 0, 0, 0, 0, 0, 0, 1},
+					Items: []marshalTestItem{
+						{ID: 1, Name: "one"},
+						{ID: 2, Name: "two"},
+					},
+					Balances: map[string]uint64{
+						"b": 2,
+						"a": 1,
+					},
+					Note:   &note,
+					Total:  big.NewInt(42),
+					Price:  "1.5",
+					Cached: 3,
+				},
+				UUID: 7,
+			},
+			marshalTestCollectionType,
+		)
+		require.NoError(t, err)
+
+		item := func(id uint64, name string) Value {
+			return NewStruct([]Value{
+				NewUInt64(id),
+				String(name),
+			}).WithType(marshalTestItemType)
+		}
+
+		assert.Equal(t,
+			NewResource([]Value{
+				NewUInt64(7),
+				NewAddress([8]byte{0, 0, 0, 0, 0, 0, 0, 1}),
+				NewArray([]Value{
+					item(1, "one"),
+					item(2, "two"),
+				}),
+				NewDictionary([]KeyValuePair{
+					{Key: String("a"), Value: NewUInt64(1)},
+					{Key: String("b"), Value: NewUInt64(2)},
+				}),
+				NewOptional(String("hello")),
+				NewUInt256(42),
+				UFix64(150_000_000),
+				NewOptional(nil),
+			}).WithType(marshalTestCollectionType),
+			value,
+		)
+	})
+
+	t.Run("missing field", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := Marshal(
+			struct {
+				ID uint64 `cadence:"id"`
+			}{ID: 1},
+			marshalTestItemType,
+		)
+		require.EqualError(t, err, "cannot marshal Go value of type struct { ID uint64 \"cadence:\\\"id\\\"\" } to Cadence type S.test.Item: missing field name")
+	})
+
+	t.Run("integer out of range", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := Marshal(300, UInt8Type{})
+		require.Error(t, err)
+
+		_, err = Marshal(-1, UInt64Type{})
+		require.Error(t, err)
+
+		_, err = Marshal(-1, UInt256Type{})
+		require.Error(t, err)
+
+		value, err := Marshal(uint8(255), Int16Type{})
+		require.NoError(t, err)
+		assert.Equal(t, NewInt16(255), value)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+
+		t.Parallel()
+
+		value, err := Marshal(nil, OptionalType{Type: IntType{}})
+		require.NoError(t, err)
+		assert.Equal(t, NewOptional(nil), value)
+
+		var pointer *int
+		value, err = Marshal(pointer, OptionalType{Type: OptionalType{Type: IntType{}}})
+		require.NoError(t, err)
+		assert.Equal(t, NewOptional(nil), value)
+
+		_, err = Marshal(pointer, IntType{})
+		require.EqualError(t, err, "cannot marshal nil to non-optional Cadence type Int")
+	})
+
+	t.Run("constant-sized array", func(t *testing.T) {
+
+		t.Parallel()
+
+		arrayType := ConstantSizedArrayType{
+			Size:        2,
+			ElementType: BoolType{},
+		}
+
+		value, err := Marshal([]bool{true, false}, arrayType)
+		require.NoError(t, err)
+		assert.Equal(t, NewArray([]Value{NewBool(true), NewBool(false)}), value)
+
+		_, err = Marshal([]bool{true}, arrayType)
+		require.Error(t, err)
+	})
+
+	t.Run("AnyStruct", func(t *testing.T) {
+
+		t.Parallel()
+
+		value, err := Marshal(
+			[]interface{}{1, "two", uint8(3), []int8{4}},
+			VariableSizedArrayType{ElementType: AnyStructType{}},
+		)
+		require.NoError(t, err)
+		assert.Equal(t,
+			NewArray([]Value{
+				NewInt(1),
+				String("two"),
+				NewUInt8(3),
+				NewArray([]Value{NewInt8(4)}),
+			}),
+			value,
+		)
+
+		_, err = Marshal(marshalTestItem{}, AnyStructType{})
+		require.Error(t, err)
+	})
+
+	t.Run("Cadence values", func(t *testing.T) {
+
+		t.Parallel()
+
+		value, err := Marshal(
+			map[string]Value{"a": NewInt(1)},
+			DictionaryType{KeyType: StringType{}, ElementType: AnyStructType{}},
+		)
+		require.NoError(t, err)
+		assert.Equal(t,
+			NewDictionary([]KeyValuePair{
+				{Key: String("a"), Value: NewInt(1)},
+			}),
+			value,
+		)
+
+		price := UFix64(1)
+		value, err = Marshal(&price, OptionalType{Type: UFix64Type{}})
+		require.NoError(t, err)
+		assert.Equal(t, NewOptional(UFix64(1)), value)
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := Marshal("1", IntType{})
+		require.EqualError(t, err, "cannot marshal Go value of type string to Cadence type Int")
+
+		_, err = Marshal(map[string]int{}, VariableSizedArrayType{ElementType: IntType{}})
+		require.Error(t, err)
+	})
+}
+
+func TestUnmarshal(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("composite", func(t *testing.T) {
+
+		t.Parallel()
+
+		item := func(id uint64, name string) Value {
+			return NewStruct([]Value{
+				NewUInt64(id),
+				String(name),
+			}).WithType(marshalTestItemType)
+		}
+
+		value := NewResource([]Value{
+			NewUInt64(7),
+			NewAddress([8]byte{0, 0, 0, 0, 0, 0, 0, 1}),
+			NewArray([]Value{
+				item(1, "one"),
+				item(2, "two"),
+			}),
+			NewDictionary([]KeyValuePair{
+				{Key: String("a"), Value: NewUInt64(1)},
+				{Key: String("b"), Value: NewUInt64(2)},
+			}),
+			NewOptional(String("hello")),
+			NewUInt256(42),
+			UFix64(150_000_000),
+			NewOptional(
+				NewDictionary([]KeyValuePair{
+					{Key: NewUInt8(3), Value: item(3, "three")},
+				}),
+			),
+		}).WithType(marshalTestCollectionType)
+
+		var result marshalTestCollection
+		err := Unmarshal(value, &result)
+		require.NoError(t, err)
+
+		note := "hello"
+
+		assert.Equal(t,
+			marshalTestCollection{
+				Owner: [8]byte{0, 0, 0, 0, 0, 0, 0, 1},
+				Items: []marshalTestItem{
+					{ID: 1, Name: "one"},
+					{ID: 2, Name: "two"},
+				},
+				Balances: map[string]uint64{
+					"a": 1,
+					"b": 2,
+				},
+				Note:  &note,
+				Total: big.NewInt(42),
+				Price: "1.50000000",
+				Extra: map[uint8]marshalTestItem{
+					3: {ID: 3, Name: "three"},
+				},
+			},
+			result,
+		)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+
+		t.Parallel()
+
+		expected := marshalTestItem{ID: 1, Name: "one"}
+
+		value, err := Marshal(expected, marshalTestItemType)
+		require.NoError(t, err)
+
+		var actual marshalTestItem
+		err = Unmarshal(value, &actual)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("nil optional", func(t *testing.T) {
+
+		t.Parallel()
+
+		pointer := new(int)
+		err := Unmarshal(NewOptional(nil), &pointer)
+		require.NoError(t, err)
+		assert.Nil(t, pointer)
+
+		var integer int
+		err = Unmarshal(NewOptional(nil), &integer)
+		require.EqualError(t, err, "cannot unmarshal nil into Go value of type int")
+	})
+
+	t.Run("integer out of range", func(t *testing.T) {
+
+		t.Parallel()
+
+		var small int8
+		err := Unmarshal(NewInt(128), &small)
+		require.EqualError(t, err, "cannot unmarshal 128 into Go value of type int8: out of range")
+
+		var unsigned uint
+		err = Unmarshal(NewInt(-1), &unsigned)
+		require.Error(t, err)
+
+		err = Unmarshal(NewUInt8(255), &unsigned)
+		require.NoError(t, err)
+		assert.Equal(t, uint(255), unsigned)
+	})
+
+	t.Run("interface", func(t *testing.T) {
+
+		t.Parallel()
+
+		var goValue interface{}
+		err := Unmarshal(NewArray([]Value{NewInt(1), String("two")}), &goValue)
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{big.NewInt(1), "two"}, goValue)
+
+		var value Value
+		err = Unmarshal(NewInt(1), &value)
+		require.NoError(t, err)
+		assert.Equal(t, NewInt(1), value)
+	})
+
+	t.Run("non-pointer target", func(t *testing.T) {
+
+		t.Parallel()
+
+		var integer int
+		err := Unmarshal(NewInt(1), integer)
+		require.Error(t, err)
+
+		err = Unmarshal(NewInt(1), nil)
+		require.Error(t, err)
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+
+		t.Parallel()
+
+		var str string
+		err := Unmarshal(NewInt(1), &str)
+		require.EqualError(t, err, "cannot unmarshal cadence.Int into Go value of type string")
+
+		var items [3]marshalTestItem
+		err = Unmarshal(NewArray([]Value{}), &items)
+		require.Error(t, err)
+	})
+}
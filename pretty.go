@@ -0,0 +1,92 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cadence
+
+import (
+	"github.com/onflow/cadence/runtime/format"
+)
+
+// PrettyString returns the multi-line representation of the given value,
+// limited and redacted according to the given options
+//
+func PrettyString(value Value, options format.PrettyOptions) string {
+	return format.Pretty(prettyNode(value), options)
+}
+
+func prettyNode(value Value) format.PrettyNode {
+	switch value := value.(type) {
+	case Optional:
+		if value.Value == nil {
+			return format.PrettyText(format.Nil)
+		}
+		return prettyNode(value.Value)
+
+	case Array:
+		return format.PrettyArray{
+			Count: len(value.Values),
+			Element: func(index int) format.PrettyNode {
+				return prettyNode(value.Values[index])
+			},
+		}
+
+	case Dictionary:
+		return format.PrettyDictionary{
+			Count: len(value.Pairs),
+			Entry: func(index int) (format.PrettyNode, format.PrettyNode) {
+				pair := value.Pairs[index]
+				return prettyNode(pair.Key), prettyNode(pair.Value)
+			},
+		}
+
+	case Struct:
+		return prettyComposite(value.StructType.ID(), value.StructType.Fields, value.Fields)
+
+	case Resource:
+		return prettyComposite(value.ResourceType.ID(), value.ResourceType.Fields, value.Fields)
+
+	case Event:
+		return prettyComposite(value.EventType.ID(), value.EventType.Fields, value.Fields)
+
+	case Contract:
+		return prettyComposite(value.ContractType.ID(), value.ContractType.Fields, value.Fields)
+
+	case Enum:
+		return prettyComposite(value.EnumType.ID(), value.EnumType.Fields, value.Fields)
+
+	default:
+		return format.PrettyText(value.String())
+	}
+}
+
+func prettyComposite(typeID string, fields []Field, values []Value) format.PrettyNode {
+	fieldNames := make([]string, len(fields))
+	fieldValues := make(map[string]Value, len(fields))
+	for i, field := range fields {
+		fieldNames[i] = field.Identifier
+		fieldValues[field.Identifier] = values[i]
+	}
+
+	return format.PrettyComposite{
+		TypeID:     typeID,
+		FieldNames: fieldNames,
+		Field: func(name string) format.PrettyNode {
+			return prettyNode(fieldValues[name])
+		},
+	}
+}
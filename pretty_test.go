@@ -0,0 +1,77 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cadence
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/cadence/runtime/format"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestPrettyString(t *testing.T) {
+
+	t.Parallel()
+
+	value := NewResource([]Value{
+		NewUInt64(1),
+		NewArray([]Value{
+			NewOptional(NewInt(1)),
+			NewOptional(nil),
+		}),
+		NewDictionary([]KeyValuePair{
+			{
+				Key:   String("a"),
+				Value: NewArray([]Value{NewInt(2)}),
+			},
+		}),
+		String("key"),
+	}).WithType(&ResourceType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "Vault",
+		Fields: []Field{
+			{Identifier: "uuid", Type: UInt64Type{}},
+			{Identifier: "values", Type: VariableSizedArrayType{ElementType: OptionalType{Type: IntType{}}}},
+			{Identifier: "metadata", Type: DictionaryType{KeyType: StringType{}, ElementType: VariableSizedArrayType{ElementType: IntType{}}}},
+			{Identifier: "key", Type: StringType{}},
+		},
+	})
+
+	assert.Equal(t,
+		`S.test.Vault(
+    uuid: 1,
+    values: [
+        1,
+        nil
+    ],
+    metadata: {
+        "a": [...]
+    },
+    key: <redacted>
+)`,
+		PrettyString(value, format.PrettyOptions{
+			MaxDepth: 2,
+			Redact: func(typeID string, fieldName string) bool {
+				return typeID == "S.test.Vault" && fieldName == "key"
+			},
+		}),
+	)
+}
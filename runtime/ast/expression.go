@@ -1148,3 +1148,54 @@ func (e *PathExpression) MarshalJSON() ([]byte, error) {
 		Alias: (*Alias)(e),
 	})
 }
+
+// TryExpression
+
+type TryExpression struct {
+	InvocationExpression *InvocationExpression
+	StartPos             Position `json:"-"`
+}
+
+func (*TryExpression) isExpression() {}
+
+func (*TryExpression) isIfStatementTest() {}
+
+func (e *TryExpression) Accept(visitor Visitor) Repr {
+	return e.AcceptExp(visitor)
+}
+
+func (e *TryExpression) Walk(walkChild func(Element)) {
+	walkChild(e.InvocationExpression)
+}
+
+func (e *TryExpression) AcceptExp(visitor ExpressionVisitor) Repr {
+	return visitor.VisitTryExpression(e)
+}
+
+func (e *TryExpression) String() string {
+	return fmt.Sprintf(
+		"(try %s)",
+		e.InvocationExpression,
+	)
+}
+
+func (e *TryExpression) StartPosition() Position {
+	return e.StartPos
+}
+
+func (e *TryExpression) EndPosition() Position {
+	return e.InvocationExpression.EndPos
+}
+
+func (e *TryExpression) MarshalJSON() ([]byte, error) {
+	type Alias TryExpression
+	return json.Marshal(&struct {
+		Type string
+		Range
+		*Alias
+	}{
+		Type:  "TryExpression",
+		Range: NewRangeFromPositioned(e),
+		Alias: (*Alias)(e),
+	})
+}
@@ -108,6 +108,10 @@ type PathExtractor interface {
 	ExtractPath(extractor *ExpressionExtractor, expression *PathExpression) ExpressionExtraction
 }
 
+type TryExtractor interface {
+	ExtractTry(extractor *ExpressionExtractor, expression *TryExpression) ExpressionExtraction
+}
+
 type ExpressionExtractor struct {
 	nextIdentifier       int
 	BoolExtractor        BoolExtractor
@@ -131,6 +135,7 @@ type ExpressionExtractor struct {
 	ReferenceExtractor   ReferenceExtractor
 	ForceExtractor       ForceExtractor
 	PathExtractor        PathExtractor
+	TryExtractor         TryExtractor
 }
 
 func (extractor *ExpressionExtractor) Extract(expression Expression) ExpressionExtraction {
@@ -779,3 +784,31 @@ func (extractor *ExpressionExtractor) ExtractPath(expression *PathExpression) Ex
 		ExtractedExpressions: nil,
 	}
 }
+
+func (extractor *ExpressionExtractor) VisitTryExpression(expression *TryExpression) Repr {
+
+	// delegate to child extractor, if any,
+	// or call default implementation
+
+	if extractor.TryExtractor != nil {
+		return extractor.TryExtractor.ExtractTry(extractor, expression)
+	}
+	return extractor.ExtractTry(expression)
+}
+
+func (extractor *ExpressionExtractor) ExtractTry(expression *TryExpression) ExpressionExtraction {
+
+	// copy the expression
+	newExpression := *expression
+
+	// rewrite the sub-expression
+
+	result := extractor.Extract(newExpression.InvocationExpression)
+
+	newExpression.InvocationExpression = result.RewrittenExpression.(*InvocationExpression)
+
+	return ExpressionExtraction{
+		RewrittenExpression:  &newExpression,
+		ExtractedExpressions: result.ExtractedExpressions,
+	}
+}
@@ -82,6 +82,7 @@ type ExpressionVisitor interface {
 	VisitReferenceExpression(*ReferenceExpression) Repr
 	VisitForceExpression(*ForceExpression) Repr
 	VisitPathExpression(*PathExpression) Repr
+	VisitTryExpression(*TryExpression) Repr
 }
 
 type Visitor interface {
@@ -0,0 +1,277 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package bench implements benchmarks of Cadence scripts, transactions, and contract functions.
+//
+// A benchmark is executed repeatedly against a fixed state of a test harness:
+// the effects of each iteration are rolled back before the next iteration.
+// For each benchmark the wall time, the computation used, the allocations,
+// and the calls of the runtime interface functions are reported.
+//
+// Reports are written in the Go benchmark format, so they can be compared,
+// for example using the benchstat tool.
+//
+package bench
+
+import (
+	"fmt"
+	"io"
+	goRuntime "runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/harness"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// Benchmark is a named execution which is benchmarked
+//
+type Benchmark struct {
+	Name string
+	// Execute performs one execution against the given harness
+	Execute func(h *harness.Harness) error
+}
+
+// Script returns a benchmark which executes the given script with the given arguments
+//
+func Script(name string, code string, arguments ...cadence.Value) Benchmark {
+	return Benchmark{
+		Name: name,
+		Execute: func(h *harness.Harness) error {
+			_, err := h.ExecuteScript(code, arguments...)
+			return err
+		},
+	}
+}
+
+// Transaction returns a benchmark which executes the given transaction with the given arguments,
+// signed by the given accounts
+//
+func Transaction(name string, code string, arguments []cadence.Value, signers ...common.Address) Benchmark {
+	return Benchmark{
+		Name: name,
+		Execute: func(h *harness.Harness) error {
+			_, err := h.ExecuteTransaction(code, arguments, signers...)
+			return err
+		},
+	}
+}
+
+// ContractFunction returns a benchmark which invokes the function with the given name
+// of the contract with the given name in the given account, with the given arguments
+//
+func ContractFunction(
+	name string,
+	address common.Address,
+	contractName string,
+	functionName string,
+	arguments []interpreter.Value,
+	argumentTypes []sema.Type,
+) Benchmark {
+	return Benchmark{
+		Name: name,
+		Execute: func(h *harness.Harness) error {
+			// The runtime replaces converted arguments,
+			// so pass a copy to keep the arguments of all iterations the same
+			iterationArguments := make([]interpreter.Value, len(arguments))
+			copy(iterationArguments, arguments)
+
+			_, err := h.InvokeContractFunction(
+				address,
+				contractName,
+				functionName,
+				iterationArguments,
+				argumentTypes,
+			)
+			return err
+		},
+	}
+}
+
+// Result is the result of a benchmark.
+//
+// All measurements are totals over all iterations
+//
+type Result struct {
+	Name            string
+	Iterations      int
+	Duration        time.Duration
+	ComputationUsed uint64
+	Allocations     uint64
+	AllocatedBytes  uint64
+	HostCalls       harness.HostCalls
+}
+
+// NsPerOp returns the wall time in nanoseconds per iteration
+//
+func (r Result) NsPerOp() int64 {
+	if r.Iterations <= 0 {
+		return 0
+	}
+	return r.Duration.Nanoseconds() / int64(r.Iterations)
+}
+
+// ComputationPerOp returns the computation used per iteration
+//
+func (r Result) ComputationPerOp() uint64 {
+	return r.perOp(r.ComputationUsed)
+}
+
+// AllocsPerOp returns the number of allocations per iteration
+//
+func (r Result) AllocsPerOp() uint64 {
+	return r.perOp(r.Allocations)
+}
+
+// AllocatedBytesPerOp returns the number of allocated bytes per iteration
+//
+func (r Result) AllocatedBytesPerOp() uint64 {
+	return r.perOp(r.AllocatedBytes)
+}
+
+// HostCallsPerOp returns the number of calls of the runtime interface function
+// with the given name per iteration
+//
+func (r Result) HostCallsPerOp(name string) uint64 {
+	return r.perOp(uint64(r.HostCalls[name]))
+}
+
+func (r Result) perOp(total uint64) uint64 {
+	if r.Iterations <= 0 {
+		return 0
+	}
+	return total / uint64(r.Iterations)
+}
+
+// Run executes the given benchmark the given number of times against the current state of the harness.
+//
+// The effects of each iteration are rolled back,
+// so the state of the harness is the same after the benchmark as before
+//
+func Run(h *harness.Harness, benchmark Benchmark, iterations int) (Result, error) {
+	result := Result{
+		Name:      benchmark.Name,
+		HostCalls: harness.HostCalls{},
+	}
+
+	snapshot := h.Snapshot()
+	defer h.Release(snapshot)
+
+	var before, after goRuntime.MemStats
+
+	for i := 0; i < iterations; i++ {
+		goRuntime.ReadMemStats(&before)
+		start := time.Now()
+
+		err := benchmark.Execute(h)
+
+		duration := time.Since(start)
+		goRuntime.ReadMemStats(&after)
+
+		if err != nil {
+			restoreErr := h.Restore(snapshot)
+			if restoreErr != nil {
+				return result, restoreErr
+			}
+			return result, fmt.Errorf("benchmark %s failed in iteration %d: %w", benchmark.Name, i, err)
+		}
+
+		stats := h.LastExecutionStats()
+
+		result.Iterations++
+		result.Duration += duration
+		result.ComputationUsed += stats.ComputationUsed
+		result.Allocations += after.Mallocs - before.Mallocs
+		result.AllocatedBytes += after.TotalAlloc - before.TotalAlloc
+		for name, count := range stats.HostCalls {
+			result.HostCalls[name] += count
+		}
+
+		err = h.Restore(snapshot)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// RunAll executes each of the given benchmarks the given number of times
+// against the current state of the harness
+//
+func RunAll(h *harness.Harness, benchmarks []Benchmark, iterations int) ([]Result, error) {
+	results := make([]Result, 0, len(benchmarks))
+	for _, benchmark := range benchmarks {
+		result, err := Run(h, benchmark, iterations)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+const benchmarkNamePrefix = "Benchmark"
+
+// WriteReport writes the given results in the Go benchmark format.
+//
+// Each result is written on one line, with the measurements per iteration in a fixed order:
+// wall time, computation, allocated bytes, allocations, the total number of host calls,
+// and the number of calls of each host function, sorted by name
+//
+func WriteReport(w io.Writer, results []Result) error {
+	for _, result := range results {
+		var line strings.Builder
+
+		name := result.Name
+		if !strings.HasPrefix(name, benchmarkNamePrefix) {
+			name = benchmarkNamePrefix + name
+		}
+		name = strings.Join(strings.Fields(name), "_")
+
+		fmt.Fprintf(&line, "%s\t%d", name, result.Iterations)
+		fmt.Fprintf(&line, "\t%d ns/op", result.NsPerOp())
+		fmt.Fprintf(&line, "\t%d computation/op", result.ComputationPerOp())
+		fmt.Fprintf(&line, "\t%d B/op", result.AllocatedBytesPerOp())
+		fmt.Fprintf(&line, "\t%d allocs/op", result.AllocsPerOp())
+		fmt.Fprintf(&line, "\t%d host-calls/op", result.perOp(uint64(result.HostCalls.Total())))
+
+		hostCallNames := make([]string, 0, len(result.HostCalls))
+		for hostCallName := range result.HostCalls {
+			hostCallNames = append(hostCallNames, hostCallName)
+		}
+		sort.Strings(hostCallNames)
+
+		for _, hostCallName := range hostCallNames {
+			fmt.Fprintf(&line, "\t%d %s/op", result.HostCallsPerOp(hostCallName), hostCallName)
+		}
+
+		line.WriteByte('\n')
+
+		_, err := io.WriteString(w, line.String())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bench
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/harness"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+const counterContract = `
+  pub contract Counter {
+
+      pub var count: Int
+
+      init() {
+          self.count = 0
+      }
+
+      pub fun increment(_ amount: Int): Int {
+          var i = 0
+          while i < amount {
+              self.count = self.count + 1
+              i = i + 1
+          }
+          return self.count
+      }
+  }
+`
+
+func TestRun(t *testing.T) {
+
+	t.Parallel()
+
+	h := harness.New()
+
+	address := h.CreateAccount()
+
+	_, err := h.DeployContract(address, "Counter", counterContract)
+	require.NoError(t, err)
+
+	countScript := `
+      import Counter from 0x1
+
+      pub fun main(): Int {
+          return Counter.count
+      }
+    `
+
+	t.Run("script", func(t *testing.T) {
+
+		result, err := Run(h, Script("Count", countScript), 3)
+		require.NoError(t, err)
+
+		assert.Equal(t, "Count", result.Name)
+		assert.Equal(t, 3, result.Iterations)
+		assert.NotZero(t, result.ComputationPerOp())
+		assert.NotZero(t, result.AllocsPerOp())
+		assert.NotZero(t, result.HostCallsPerOp("GetAccountContractCode"))
+		assert.Equal(t, uint64(0), result.ComputationUsed%3)
+	})
+
+	t.Run("contract function", func(t *testing.T) {
+
+		small, err := Run(
+			h,
+			ContractFunction(
+				"Increment1",
+				address,
+				"Counter",
+				"increment",
+				[]interpreter.Value{interpreter.NewIntValueFromInt64(1)},
+				[]sema.Type{sema.IntType},
+			),
+			2,
+		)
+		require.NoError(t, err)
+
+		large, err := Run(
+			h,
+			ContractFunction(
+				"Increment10",
+				address,
+				"Counter",
+				"increment",
+				[]interpreter.Value{interpreter.NewIntValueFromInt64(10)},
+				[]sema.Type{sema.IntType},
+			),
+			2,
+		)
+		require.NoError(t, err)
+
+		assert.Greater(t, large.ComputationPerOp(), small.ComputationPerOp())
+
+		// The iterations have no effects
+
+		count, err := h.ExecuteScript(countScript)
+		require.NoError(t, err)
+		assert.Equal(t, cadence.NewInt(0), count)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+
+		_, err := Run(
+			h,
+			Script("Panic", `pub fun main() { panic("test") }`),
+			1,
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "benchmark Panic failed in iteration 0")
+	})
+}
+
+func TestWriteReport(t *testing.T) {
+
+	t.Parallel()
+
+	results := []Result{
+		{
+			Name:            "Transfer",
+			Iterations:      10,
+			Duration:        10 * time.Millisecond,
+			ComputationUsed: 420,
+			Allocations:     2000,
+			AllocatedBytes:  100000,
+			HostCalls: harness.HostCalls{
+				"SetValue": 20,
+				"GetValue": 40,
+			},
+		},
+		{
+			Name:       "BenchmarkMint tokens",
+			Iterations: 1,
+			Duration:   time.Microsecond,
+		},
+	}
+
+	var builder strings.Builder
+	err := WriteReport(&builder, results)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		"BenchmarkTransfer\t10\t1000000 ns/op\t42 computation/op\t10000 B/op\t200 allocs/op\t6 host-calls/op\t4 GetValue/op\t2 SetValue/op\n"+
+			"BenchmarkMint_tokens\t1\t1000 ns/op\t0 computation/op\t0 B/op\t0 allocs/op\t0 host-calls/op\n",
+		builder.String(),
+	)
+}
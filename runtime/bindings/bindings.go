@@ -0,0 +1,670 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package bindings generates Go bindings for Cadence contracts.
+//
+// For each composite type declared in a contract (structures, resources, events, enums, and the contract itself),
+// the generated code contains a Go struct with `cadence` struct tags, a variable holding the Cadence type,
+// and methods to convert between the Go struct and Cadence values using cadence.Marshal and cadence.Unmarshal.
+//
+// For each script and transaction, the generated code contains a function which encodes
+// the typed Go arguments into JSON-Cadence encoded arguments, and for scripts,
+// a function which decodes the script's result.
+//
+// Cadence types are mapped to Go types as follows:
+//
+//   - Bool: bool
+//   - String and Character: string
+//   - Int8, Int16, Int32, Int64: int8, int16, int32, int64
+//   - UInt8, UInt16, UInt32, UInt64, Word8, Word16, Word32, Word64: uint8, uint16, uint32, uint64
+//   - Int, Int128, Int256, UInt, UInt128, UInt256: *big.Int
+//   - Fix64, UFix64, Address: cadence.Fix64, cadence.UFix64, cadence.Address
+//   - [T] and [T; N]: []T and [N]T
+//   - {K: V}: map[K]V, if K maps to a comparable Go type
+//   - T?: *T, or T if T already is a pointer, slice, map, or interface
+//   - composites declared in the contract: the generated Go struct
+//   - all other types: cadence.Value
+//
+package bindings
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// Config is the configuration of the generator.
+//
+type Config struct {
+	// PackageName is the name of the package of the generated Go code.
+	PackageName string
+	// Location optionally replaces the location of the contract's types in the generated code,
+	// e.g. when the contract was checked from a file, but is deployed to an account.
+	Location common.Location
+}
+
+// EntryPoint is a checked script or transaction for which argument encoding functions are generated.
+//
+type EntryPoint struct {
+	// Name is the name of the entry point, used as the prefix of the generated Go functions.
+	Name    string
+	Checker *sema.Checker
+}
+
+type composite struct {
+	goName string
+	typ    cadence.CompositeType
+}
+
+type generator struct {
+	config     Config
+	buf        bytes.Buffer
+	results    map[sema.TypeID]cadence.Type
+	composites []*composite
+	goNames    map[string]*composite
+	typeIDs    map[string]*composite
+	imports    map[string]struct{}
+}
+
+// Generate generates Go bindings for the given checked contract and entry points.
+// The contract may be nil, in which case only the entry point functions are generated.
+//
+func Generate(config Config, contract *sema.Checker, entryPoints ...EntryPoint) ([]byte, error) {
+	if config.PackageName == "" {
+		return nil, fmt.Errorf("missing package name")
+	}
+
+	g := &generator{
+		config:  config,
+		results: map[sema.TypeID]cadence.Type{},
+		goNames: map[string]*composite{},
+		typeIDs: map[string]*composite{},
+		imports: map[string]struct{}{},
+	}
+
+	if contract != nil {
+		err := g.collectComposites(contract)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, composite := range g.composites {
+		g.writeComposite(composite)
+	}
+
+	if len(g.composites) > 0 {
+		g.writeCompositeFields()
+	}
+
+	for _, entryPoint := range entryPoints {
+		err := g.writeEntryPoint(entryPoint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(entryPoints) > 0 {
+		g.writeEncodeArguments()
+	}
+
+	return g.source()
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) use(importPath string) {
+	g.imports[importPath] = struct{}{}
+}
+
+func (g *generator) source() ([]byte, error) {
+	var source bytes.Buffer
+
+	_, _ = fmt.Fprintf(&source, "// Code generated by bindgen. DO NOT EDIT.\n\n")
+	_, _ = fmt.Fprintf(&source, "package %s\n\n", g.config.PackageName)
+
+	if len(g.imports) > 0 {
+		// Standard library packages are grouped separately
+
+		var standardImportPaths, otherImportPaths []string
+		for importPath := range g.imports {
+			if strings.Contains(importPath, ".") {
+				otherImportPaths = append(otherImportPaths, importPath)
+			} else {
+				standardImportPaths = append(standardImportPaths, importPath)
+			}
+		}
+		sort.Strings(standardImportPaths)
+		sort.Strings(otherImportPaths)
+
+		_, _ = fmt.Fprintf(&source, "import (\n")
+		for _, importPath := range standardImportPaths {
+			_, _ = fmt.Fprintf(&source, "\t%q\n", importPath)
+		}
+		if len(standardImportPaths) > 0 && len(otherImportPaths) > 0 {
+			_, _ = fmt.Fprintf(&source, "\n")
+		}
+		for _, importPath := range otherImportPaths {
+			_, _ = fmt.Fprintf(&source, "\t%q\n", importPath)
+		}
+		_, _ = fmt.Fprintf(&source, ")\n\n")
+	}
+
+	_, _ = source.Write(g.buf.Bytes())
+
+	result, err := format.Source(source.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+
+	return result, nil
+}
+
+// collectComposites collects the composite types declared in the given contract,
+// in declaration order, and assigns them Go names.
+//
+func (g *generator) collectComposites(checker *sema.Checker) error {
+	contractDeclaration := checker.Program.SoleContractDeclaration()
+
+	var collect func(declarations []*ast.CompositeDeclaration) error
+	collect = func(declarations []*ast.CompositeDeclaration) error {
+		for _, declaration := range declarations {
+			semaType := checker.Elaboration.CompositeDeclarationTypes[declaration]
+
+			compositeType, ok := runtime.ExportType(semaType, g.results).(cadence.CompositeType)
+			if !ok {
+				return fmt.Errorf("cannot export composite type %s", semaType.QualifiedIdentifier())
+			}
+
+			goName := g.compositeGoName(semaType, contractDeclaration)
+			if _, ok := g.goNames[goName]; ok {
+				return fmt.Errorf("duplicate Go name %s for composite type %s", goName, semaType.QualifiedIdentifier())
+			}
+
+			composite := &composite{
+				goName: goName,
+				typ:    compositeType,
+			}
+
+			g.composites = append(g.composites, composite)
+			g.goNames[goName] = composite
+			g.typeIDs[string(semaType.ID())] = composite
+
+			err := collect(declaration.Members.Composites())
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return collect(checker.Program.CompositeDeclarations())
+}
+
+// compositeGoName returns the Go name for the given composite type.
+// Types nested in the contract are named without the contract name prefix.
+//
+func (g *generator) compositeGoName(compositeType *sema.CompositeType, contractDeclaration *ast.CompositeDeclaration) string {
+	identifiers := strings.Split(compositeType.QualifiedIdentifier(), ".")
+
+	parts := make([]string, 0, len(identifiers))
+	for i, identifier := range identifiers {
+		if i == 0 &&
+			len(identifiers) > 1 &&
+			contractDeclaration != nil &&
+			identifier == contractDeclaration.Identifier.Identifier {
+
+			continue
+		}
+		parts = append(parts, exportedGoName(identifier))
+	}
+
+	return strings.Join(parts, "")
+}
+
+// goInitialisms are the identifiers which are written in upper-case in Go
+//
+var goInitialisms = map[string]struct{}{
+	"id":   {},
+	"uuid": {},
+	"url":  {},
+	"uri":  {},
+	"nft":  {},
+}
+
+// exportedGoName returns the given Cadence identifier as an exported Go identifier.
+//
+func exportedGoName(identifier string) string {
+	if _, ok := goInitialisms[identifier]; ok {
+		return strings.ToUpper(identifier)
+	}
+
+	first, size := utf8.DecodeRuneInString(identifier)
+	return string(unicode.ToUpper(first)) + identifier[size:]
+}
+
+// goParameterName returns the given Cadence identifier as a Go identifier
+// which does not conflict with Go keywords or the packages used by the generated code.
+//
+func goParameterName(identifier string) string {
+	switch identifier {
+	case "big", "cadence", "common", "json", "encodeArguments":
+		return identifier + "_"
+	}
+
+	if token.IsKeyword(identifier) {
+		return identifier + "_"
+	}
+
+	return identifier
+}
+
+func (g *generator) compositeLocation(compositeType cadence.CompositeType) common.Location {
+	if _, ok := g.typeIDs[compositeType.ID()]; ok && g.config.Location != nil {
+		return g.config.Location
+	}
+	return compositeType.CompositeTypeLocation()
+}
+
+func (g *generator) writeComposite(composite *composite) {
+	typ := composite.typ
+
+	g.printf("// %s is the Go representation of the Cadence %s %s.\n", composite.goName, compositeKindName(typ), typ.CompositeTypeQualifiedIdentifier())
+	g.printf("type %s struct {\n", composite.goName)
+	for _, field := range typ.CompositeFields() {
+		g.printf("\t%s %s `cadence:%q`\n", exportedGoName(field.Identifier), g.goType(field.Type), field.Identifier)
+	}
+	g.printf("}\n\n")
+
+	typeVariableName := composite.goName + "Type"
+
+	g.use("github.com/onflow/cadence")
+
+	g.printf("// %s is the Cadence type of %s.\n", typeVariableName, composite.goName)
+	g.printf("var %s = %s\n\n", typeVariableName, g.compositeTypeLiteral(typ))
+
+	g.printf("// ToCadenceValue converts the value to a Cadence value of type %s.\n", typeVariableName)
+	g.printf("func (v %s) ToCadenceValue() (cadence.Value, error) {\n", composite.goName)
+	g.printf("\treturn cadence.Marshal(v, %s)\n", typeVariableName)
+	g.printf("}\n\n")
+
+	g.use("fmt")
+
+	g.printf("// FromCadenceValue sets the value from a Cadence value of type %s.\n", typeVariableName)
+	g.printf("func (v *%s) FromCadenceValue(value cadence.Value) error {\n", composite.goName)
+	g.printf("\tif value.Type() != nil && value.Type().ID() != %s.ID() {\n", typeVariableName)
+	g.printf("\t\treturn fmt.Errorf(\"cannot convert value of type %%s to %s\", value.Type().ID())\n", composite.goName)
+	g.printf("\t}\n")
+	g.printf("\treturn cadence.Unmarshal(value, v)\n")
+	g.printf("}\n\n")
+}
+
+// writeCompositeFields writes the initialization of the fields of the composite types.
+//
+// The fields are initialized separately from the type variables,
+// as the fields of composite types may refer to each other.
+//
+func (g *generator) writeCompositeFields() {
+	g.printf("func init() {\n")
+	for _, composite := range g.composites {
+		g.printf("\t%sType.Fields = %s\n", composite.goName, g.fieldsLiteral(composite.typ.CompositeFields()))
+	}
+	g.printf("}\n\n")
+}
+
+func (g *generator) writeEntryPoint(entryPoint EntryPoint) error {
+	checker := entryPoint.Checker
+
+	var kind string
+	var parameters []*sema.Parameter
+	var returnType sema.Type
+
+	if transactionDeclaration := checker.Program.SoleTransactionDeclaration(); transactionDeclaration != nil {
+		kind = "transaction"
+		parameters = checker.Elaboration.TransactionDeclarationTypes[transactionDeclaration].Parameters
+
+	} else if functionDeclaration := sema.FunctionEntryPointDeclaration(checker.Program); functionDeclaration != nil {
+		kind = "script"
+		functionType := checker.Elaboration.FunctionDeclarationFunctionTypes[functionDeclaration]
+		parameters = functionType.Parameters
+		returnType = functionType.ReturnTypeAnnotation.Type
+
+	} else {
+		return fmt.Errorf("entry point %s is neither a script nor a transaction", entryPoint.Name)
+	}
+
+	name := exportedGoName(entryPoint.Name)
+
+	g.use("github.com/onflow/cadence")
+
+	g.printf("// %sArguments encodes the arguments of the %s %s.\n", name, kind, entryPoint.Name)
+	g.printf("func %sArguments(", name)
+	for i, parameter := range parameters {
+		if i > 0 {
+			g.printf(", ")
+		}
+		parameterType := runtime.ExportType(parameter.TypeAnnotation.Type, g.results)
+		g.printf("%s %s", goParameterName(parameter.Identifier), g.goType(parameterType))
+	}
+	g.printf(") ([][]byte, error) {\n")
+	g.printf("\treturn encodeArguments(\n")
+	g.printf("\t\t[]interface{}{")
+	for i, parameter := range parameters {
+		if i > 0 {
+			g.printf(", ")
+		}
+		g.printf("%s", goParameterName(parameter.Identifier))
+	}
+	g.printf("},\n")
+	g.printf("\t\t[]cadence.Type{")
+	for i, parameter := range parameters {
+		if i > 0 {
+			g.printf(", ")
+		}
+		parameterType := runtime.ExportType(parameter.TypeAnnotation.Type, g.results)
+		g.printf("%s", g.typeLiteral(parameterType))
+	}
+	g.printf("},\n")
+	g.printf("\t)\n")
+	g.printf("}\n\n")
+
+	if returnType != nil && returnType != sema.VoidType {
+		resultType := g.goType(runtime.ExportType(returnType, g.results))
+
+		g.printf("// Decode%sResult decodes the result of the script %s.\n", name, entryPoint.Name)
+		g.printf("func Decode%sResult(value cadence.Value) (result %s, err error) {\n", name, resultType)
+		g.printf("\terr = cadence.Unmarshal(value, &result)\n")
+		g.printf("\treturn\n")
+		g.printf("}\n\n")
+	}
+
+	return nil
+}
+
+func (g *generator) writeEncodeArguments() {
+	g.use("fmt")
+	g.use("github.com/onflow/cadence/encoding/json")
+
+	g.printf("func encodeArguments(values []interface{}, types []cadence.Type) ([][]byte, error) {\n")
+	g.printf("\targuments := make([][]byte, len(values))\n")
+	g.printf("\tfor i, value := range values {\n")
+	g.printf("\t\targument, err := cadence.Marshal(value, types[i])\n")
+	g.printf("\t\tif err != nil {\n")
+	g.printf("\t\t\treturn nil, fmt.Errorf(\"failed to convert argument %%d: %%w\", i, err)\n")
+	g.printf("\t\t}\n")
+	g.printf("\t\targuments[i], err = json.Encode(argument)\n")
+	g.printf("\t\tif err != nil {\n")
+	g.printf("\t\t\treturn nil, fmt.Errorf(\"failed to encode argument %%d: %%w\", i, err)\n")
+	g.printf("\t\t}\n")
+	g.printf("\t}\n")
+	g.printf("\treturn arguments, nil\n")
+	g.printf("}\n")
+}
+
+func compositeKindName(compositeType cadence.CompositeType) string {
+	switch compositeType.(type) {
+	case *cadence.StructType:
+		return "struct"
+	case *cadence.ResourceType:
+		return "resource"
+	case *cadence.EventType:
+		return "event"
+	case *cadence.ContractType:
+		return "contract"
+	case *cadence.EnumType:
+		return "enum"
+	}
+	return "composite"
+}
+
+// goType returns the Go type which represents values of the given Cadence type.
+//
+func (g *generator) goType(t cadence.Type) string {
+	switch t := t.(type) {
+	case cadence.BoolType:
+		return "bool"
+	case cadence.StringType, cadence.CharacterType:
+		return "string"
+	case cadence.Int8Type:
+		return "int8"
+	case cadence.Int16Type:
+		return "int16"
+	case cadence.Int32Type:
+		return "int32"
+	case cadence.Int64Type:
+		return "int64"
+	case cadence.UInt8Type, cadence.Word8Type:
+		return "uint8"
+	case cadence.UInt16Type, cadence.Word16Type:
+		return "uint16"
+	case cadence.UInt32Type, cadence.Word32Type:
+		return "uint32"
+	case cadence.UInt64Type, cadence.Word64Type:
+		return "uint64"
+	case cadence.IntType, cadence.Int128Type, cadence.Int256Type,
+		cadence.UIntType, cadence.UInt128Type, cadence.UInt256Type:
+		g.use("math/big")
+		return "*big.Int"
+	case cadence.Fix64Type:
+		return "cadence.Fix64"
+	case cadence.UFix64Type:
+		return "cadence.UFix64"
+	case cadence.AddressType:
+		return "cadence.Address"
+
+	case cadence.OptionalType:
+		inner := g.goType(t.Type)
+		if isNilableGoType(inner) {
+			return inner
+		}
+		return "*" + inner
+
+	case cadence.VariableSizedArrayType:
+		return "[]" + g.goType(t.ElementType)
+
+	case cadence.ConstantSizedArrayType:
+		return fmt.Sprintf("[%d]%s", t.Size, g.goType(t.ElementType))
+
+	case cadence.DictionaryType:
+		keyType := g.goType(t.KeyType)
+		if isNilableGoType(keyType) {
+			return "cadence.Value"
+		}
+		return fmt.Sprintf("map[%s]%s", keyType, g.goType(t.ElementType))
+
+	case cadence.CompositeType:
+		if composite, ok := g.typeIDs[t.ID()]; ok {
+			return composite.goName
+		}
+	}
+
+	return "cadence.Value"
+}
+
+func isNilableGoType(goType string) bool {
+	return strings.HasPrefix(goType, "*") ||
+		strings.HasPrefix(goType, "[]") ||
+		strings.HasPrefix(goType, "map[") ||
+		goType == "cadence.Value"
+}
+
+// typeLiteral returns a Go expression which constructs the given Cadence type.
+//
+func (g *generator) typeLiteral(t cadence.Type) string {
+	switch t := t.(type) {
+	case cadence.OptionalType:
+		return fmt.Sprintf("cadence.OptionalType{Type: %s}", g.typeLiteral(t.Type))
+
+	case cadence.VariableSizedArrayType:
+		return fmt.Sprintf("cadence.VariableSizedArrayType{ElementType: %s}", g.typeLiteral(t.ElementType))
+
+	case cadence.ConstantSizedArrayType:
+		return fmt.Sprintf(
+			"cadence.ConstantSizedArrayType{Size: %d, ElementType: %s}",
+			t.Size,
+			g.typeLiteral(t.ElementType),
+		)
+
+	case cadence.DictionaryType:
+		return fmt.Sprintf(
+			"cadence.DictionaryType{KeyType: %s, ElementType: %s}",
+			g.typeLiteral(t.KeyType),
+			g.typeLiteral(t.ElementType),
+		)
+
+	case cadence.CompositeType:
+		if composite, ok := g.typeIDs[t.ID()]; ok {
+			return composite.goName + "Type"
+		}
+		// Fields of composite types declared outside of the contract are omitted,
+		// values of these types are represented as cadence.Value
+		return g.compositeTypeLiteral(t)
+
+	case cadence.InterfaceType:
+		return fmt.Sprintf(
+			"&cadence.%s{%sQualifiedIdentifier: %q}",
+			reflect.TypeOf(t).Elem().Name(),
+			g.locationField(t.InterfaceTypeLocation()),
+			t.InterfaceTypeQualifiedIdentifier(),
+		)
+
+	case cadence.ReferenceType:
+		return fmt.Sprintf(
+			"cadence.ReferenceType{Authorized: %t, Type: %s}.WithID(%q)",
+			t.Authorized,
+			g.typeLiteral(t.Type),
+			t.ID(),
+		)
+
+	case cadence.RestrictedType:
+		restrictions := make([]string, len(t.Restrictions))
+		for i, restriction := range t.Restrictions {
+			restrictions[i] = g.typeLiteral(restriction)
+		}
+		return fmt.Sprintf(
+			"cadence.RestrictedType{Type: %s, Restrictions: []cadence.Type{%s}}.WithID(%q)",
+			g.typeLiteral(t.Type),
+			strings.Join(restrictions, ", "),
+			t.ID(),
+		)
+
+	case cadence.CapabilityType:
+		if t.BorrowType == nil {
+			return fmt.Sprintf("cadence.CapabilityType{}.WithID(%q)", t.ID())
+		}
+		return fmt.Sprintf(
+			"cadence.CapabilityType{BorrowType: %s}.WithID(%q)",
+			g.typeLiteral(t.BorrowType),
+			t.ID(),
+		)
+
+	case cadence.Function:
+		parameters := make([]string, len(t.Parameters))
+		for i, parameter := range t.Parameters {
+			parameters[i] = fmt.Sprintf(
+				"{Label: %q, Identifier: %q, Type: %s}",
+				parameter.Label,
+				parameter.Identifier,
+				g.typeLiteral(parameter.Type),
+			)
+		}
+		return fmt.Sprintf(
+			"cadence.Function{Parameters: []cadence.Parameter{%s}, ReturnType: %s}.WithID(%q)",
+			strings.Join(parameters, ", "),
+			g.typeLiteral(t.ReturnType),
+			t.ID(),
+		)
+
+	case cadence.ResourcePointer:
+		return fmt.Sprintf("cadence.ResourcePointer{TypeName: %q}", t.TypeName)
+	}
+
+	// All other types are stateless
+
+	return fmt.Sprintf("%T{}", t)
+}
+
+// compositeTypeLiteral returns a Go expression which constructs the given composite type, without its fields.
+//
+func (g *generator) compositeTypeLiteral(t cadence.CompositeType) string {
+	var rawType string
+	if enumType, ok := t.(*cadence.EnumType); ok && enumType.RawType != nil {
+		rawType = fmt.Sprintf(", RawType: %s", g.typeLiteral(enumType.RawType))
+	}
+
+	return fmt.Sprintf(
+		"&cadence.%s{%sQualifiedIdentifier: %q%s}",
+		reflect.TypeOf(t).Elem().Name(),
+		g.locationField(g.compositeLocation(t)),
+		t.CompositeTypeQualifiedIdentifier(),
+		rawType,
+	)
+}
+
+func (g *generator) locationField(location common.Location) string {
+	if location == nil {
+		return ""
+	}
+	return fmt.Sprintf("Location: %s, ", g.locationLiteral(location))
+}
+
+// locationLiteral returns a Go expression which constructs the given location.
+//
+func (g *generator) locationLiteral(location common.Location) string {
+	g.use("github.com/onflow/cadence/runtime/common")
+
+	switch location := location.(type) {
+	case common.AddressLocation:
+		return fmt.Sprintf(
+			"common.AddressLocation{Address: %#v, Name: %q}",
+			location.Address,
+			location.Name,
+		)
+	case common.StringLocation:
+		return fmt.Sprintf("common.StringLocation(%q)", string(location))
+	case common.IdentifierLocation:
+		return fmt.Sprintf("common.IdentifierLocation(%q)", string(location))
+	}
+
+	return fmt.Sprintf("%#v", location)
+}
+
+func (g *generator) fieldsLiteral(fields []cadence.Field) string {
+	var b strings.Builder
+	b.WriteString("[]cadence.Field{\n")
+	for _, field := range fields {
+		_, _ = fmt.Fprintf(&b, "\t\t{Identifier: %q, Type: %s},\n", field.Identifier, g.typeLiteral(field.Type))
+	}
+	b.WriteString("\t}")
+	return b.String()
+}
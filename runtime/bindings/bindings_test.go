@@ -0,0 +1,250 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bindings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/tests/checker"
+)
+
+const testContract = `
+  pub contract Tokens {
+
+      pub event Deposit(id: UInt64, to: Address?)
+
+      pub enum Kind: UInt8 {
+          pub case fungible
+          pub case nonFungible
+      }
+
+      pub struct Metadata {
+          pub let name: String
+          pub let tags: {String: [String]}
+          pub let total: UInt256
+          pub let price: UFix64?
+
+          init(name: String, tags: {String: [String]}, total: UInt256, price: UFix64?) {
+              self.name = name
+              self.tags = tags
+              self.total = total
+              self.price = price
+          }
+      }
+
+      pub resource Token {
+          pub let kind: Kind
+          pub let metadata: Metadata
+          pub var child: @Token?
+
+          init(kind: Kind, metadata: Metadata) {
+              self.kind = kind
+              self.metadata = metadata
+              self.child <- nil
+          }
+
+          pub fun name(): String {
+              return self.metadata.name
+          }
+
+          destroy() {
+              destroy self.child
+          }
+      }
+
+      pub let count: Int
+
+      init() {
+          self.count = 0
+      }
+  }
+`
+
+const testScript = `
+  import Tokens from "Tokens"
+
+  pub fun main(names: [String], type: Tokens.Kind): Tokens.Metadata? {
+      return nil
+  }
+`
+
+const testTransaction = `
+  transaction(amount: UFix64, recipient: Address) {}
+`
+
+func parseAndCheckTestPrograms(t *testing.T) (*sema.Checker, []EntryPoint) {
+
+	contractLocation := common.StringLocation("Tokens")
+
+	contractChecker, err := checker.ParseAndCheckWithOptions(t,
+		testContract,
+		checker.ParseAndCheckOptions{
+			Location: contractLocation,
+		},
+	)
+	require.NoError(t, err)
+
+	scriptChecker, err := checker.ParseAndCheckWithOptions(t,
+		testScript,
+		checker.ParseAndCheckOptions{
+			Options: []sema.Option{
+				sema.WithImportHandler(
+					func(_ *sema.Checker, _ common.Location, _ ast.Range) (sema.Import, error) {
+						return sema.ElaborationImport{
+							Elaboration: contractChecker.Elaboration,
+						}, nil
+					},
+				),
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	transactionChecker, err := checker.ParseAndCheck(t, testTransaction)
+	require.NoError(t, err)
+
+	return contractChecker, []EntryPoint{
+		{Name: "getMetadata", Checker: scriptChecker},
+		{Name: "transfer", Checker: transactionChecker},
+	}
+}
+
+func TestGenerate(t *testing.T) {
+
+	t.Parallel()
+
+	contract, entryPoints := parseAndCheckTestPrograms(t)
+
+	source, err := Generate(
+		Config{PackageName: "tokens"},
+		contract,
+		entryPoints...,
+	)
+	require.NoError(t, err)
+
+	code := string(source)
+
+	for _, expected := range []string{
+		"package tokens\n",
+
+		`import (
+	"fmt"
+	"math/big"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime/common"
+)`,
+
+		`type Deposit struct {
+	ID uint64           ` + "`cadence:\"id\"`" + `
+	To *cadence.Address ` + "`cadence:\"to\"`" + `
+}`,
+
+		`type Metadata struct {
+	Name  string              ` + "`cadence:\"name\"`" + `
+	Tags  map[string][]string ` + "`cadence:\"tags\"`" + `
+	Total *big.Int            ` + "`cadence:\"total\"`" + `
+	Price *cadence.UFix64     ` + "`cadence:\"price\"`" + `
+}`,
+
+		`type Token struct {
+	UUID     uint64   ` + "`cadence:\"uuid\"`" + `
+	Kind     Kind     ` + "`cadence:\"kind\"`" + `
+	Metadata Metadata ` + "`cadence:\"metadata\"`" + `
+	Child    *Token   ` + "`cadence:\"child\"`" + `
+}`,
+
+		`var KindType = &cadence.EnumType{Location: common.StringLocation("Tokens"), QualifiedIdentifier: "Tokens.Kind", RawType: cadence.UInt8Type{}}`,
+
+		`func (v Token) ToCadenceValue() (cadence.Value, error) {
+	return cadence.Marshal(v, TokenType)
+}`,
+
+		`	TokenType.Fields = []cadence.Field{
+		{Identifier: "uuid", Type: cadence.UInt64Type{}},
+		{Identifier: "kind", Type: KindType},
+		{Identifier: "metadata", Type: MetadataType},
+		{Identifier: "child", Type: cadence.OptionalType{Type: TokenType}},
+	}`,
+
+		`func GetMetadataArguments(names []string, type_ Kind) ([][]byte, error) {
+	return encodeArguments(
+		[]interface{}{names, type_},
+		[]cadence.Type{cadence.VariableSizedArrayType{ElementType: cadence.StringType{}}, KindType},
+	)
+}`,
+
+		`func DecodeGetMetadataResult(value cadence.Value) (result *Metadata, err error) {`,
+
+		`func TransferArguments(amount cadence.UFix64, recipient cadence.Address) ([][]byte, error) {`,
+	} {
+		assert.Contains(t, code, expected)
+	}
+}
+
+func TestGenerateLocation(t *testing.T) {
+
+	t.Parallel()
+
+	contract, _ := parseAndCheckTestPrograms(t)
+
+	source, err := Generate(
+		Config{
+			PackageName: "tokens",
+			Location: common.AddressLocation{
+				Address: common.BytesToAddress([]byte{0x1}),
+				Name:    "Tokens",
+			},
+		},
+		contract,
+	)
+	require.NoError(t, err)
+
+	code := string(source)
+
+	assert.Contains(t,
+		code,
+		`var TokenType = &cadence.ResourceType{Location: common.AddressLocation{Address: common.Address{0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1}, Name: "Tokens"}, QualifiedIdentifier: "Tokens.Token"}`,
+	)
+	assert.NotContains(t, code, "encodeArguments")
+}
+
+func TestGenerateInvalidEntryPoint(t *testing.T) {
+
+	t.Parallel()
+
+	entryPoint, err := checker.ParseAndCheck(t, `
+      pub fun test() {}
+    `)
+	require.NoError(t, err)
+
+	_, err = Generate(
+		Config{PackageName: "tokens"},
+		nil,
+		EntryPoint{Name: "test", Checker: entryPoint},
+	)
+	require.EqualError(t, err, "entry point test is neither a script nor a transaction")
+}
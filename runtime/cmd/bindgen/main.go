@@ -0,0 +1,136 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// bindgen generates Go bindings for a Cadence contract and its scripts and transactions.
+//
+// Usage:
+//
+//	bindgen -package tokens -contract Tokens.cdc [-address 0x1] [-o tokens.go] [script.cdc ...] [transaction.cdc ...]
+//
+// Scripts and transactions must import the contract using the same path as given for the contract.
+// The name of the generated functions for a script or transaction is derived from its file name.
+//
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/bindings"
+	"github.com/onflow/cadence/runtime/cmd"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+var packageFlag = flag.String("package", "", "the name of the package of the generated code")
+var contractFlag = flag.String("contract", "", "the path of the contract")
+var addressFlag = flag.String("address", "", "the address the contract is deployed to, if any")
+var outputFlag = flag.String("o", "", "the path of the output file. defaults to stdout")
+
+func main() {
+	flag.Parse()
+
+	if *packageFlag == "" {
+		cmd.ExitWithError("missing package name")
+	}
+
+	config := bindings.Config{
+		PackageName: *packageFlag,
+	}
+
+	codes := map[common.LocationID]string{}
+
+	var contract *sema.Checker
+	if *contractFlag != "" {
+		contract = check(*contractFlag, codes)
+
+		if *addressFlag != "" {
+			address, err := common.HexToAddress(*addressFlag)
+			if err != nil {
+				cmd.ExitWithError(fmt.Sprintf("invalid address: %s", err))
+			}
+
+			contractDeclaration := contract.Program.SoleContractDeclaration()
+			if contractDeclaration == nil {
+				cmd.ExitWithError("the contract file must declare exactly one contract")
+			}
+
+			config.Location = common.AddressLocation{
+				Address: address,
+				Name:    contractDeclaration.Identifier.Identifier,
+			}
+		}
+	}
+
+	paths := flag.Args()
+
+	entryPoints := make([]bindings.EntryPoint, len(paths))
+	for i, path := range paths {
+		entryPoints[i] = bindings.EntryPoint{
+			Name:    entryPointName(path),
+			Checker: check(path, codes),
+		}
+	}
+
+	source, err := bindings.Generate(config, contract, entryPoints...)
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+
+	if *outputFlag == "" {
+		_, err = os.Stdout.Write(source)
+	} else {
+		err = ioutil.WriteFile(*outputFlag, source, 0644)
+	}
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+}
+
+func check(path string, codes map[common.LocationID]string) *sema.Checker {
+	location := common.StringLocation(path)
+
+	program, must := cmd.PrepareProgramFromFile(location, codes)
+
+	checker, must := cmd.PrepareChecker(program, location, codes, nil, must)
+
+	must(checker.Check())
+
+	return checker
+}
+
+// entryPointName returns the name of the entry point in the given file,
+// e.g. "get_balance.cdc" is named "getBalance".
+//
+func entryPointName(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.' || r == ' '
+	})
+
+	for i := 1; i < len(parts); i++ {
+		parts[i] = strings.Title(parts[i])
+	}
+
+	return strings.Join(parts, "")
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/cmd"
 	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/diagnostics"
 	"github.com/onflow/cadence/runtime/pretty"
 	"github.com/onflow/cadence/runtime/sema"
 )
@@ -91,6 +92,8 @@ type result struct {
 	Bench    *benchResult `json:"bench,omitempty"`
 	BenchStr string       `json:"-"`
 	Error    string       `json:"error,omitempty"`
+	// Diagnostics are the structured errors and hints, only included in the JSON output
+	Diagnostics []diagnostics.Diagnostic `json:"diagnostics,omitempty"`
 }
 
 type output interface {
@@ -245,7 +248,13 @@ func runPath(
 				panic(printErr)
 			}
 			res.Error = builder.String()
+			res.Diagnostics = diagnostics.FromError(err, location)
 		}
+
+		res.Diagnostics = append(
+			res.Diagnostics,
+			diagnostics.FromHints(checker.Hints(), location)...,
+		)
 	}()
 
 	if err != nil {
@@ -1,3 +1,5 @@
+// +build !wasm
+
 /*
  * Cadence - The resource-oriented smart contract programming language
  *
@@ -20,6 +22,7 @@ package execute
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strings"
 
@@ -50,11 +53,11 @@ func RunREPL() {
 			}
 		},
 		func(value interpreter.Value) {
-			if _, isVoid := value.(*interpreter.VoidValue); isVoid || value == nil {
+			if _, isVoid := value.(interpreter.VoidValue); isVoid || value == nil {
 				return
 			}
 
-			println(colorizeResult(value))
+			println(colorizeValue(value))
 		},
 		nil,
 	)
@@ -68,17 +71,16 @@ func RunREPL() {
 			lineNumber++
 		}()
 
-		if code == "" && strings.HasPrefix(line, ".") {
-			handleCommand(line)
-			code = ""
+		if code == "" && isCommand(line) {
+			handleCommand(repl, line)
 			return
 		}
 
 		// Prefix the code with empty lines,
 		// so that error messages match current line number
 
-		for i := 1; i < lineNumber; i++ {
-			code = "\n" + code
+		if code == "" {
+			code = strings.Repeat("\n", lineNumber-1)
 		}
 
 		code += line + "\n"
@@ -127,22 +129,79 @@ func RunREPL() {
 
 const helpMessage = `
 Enter declarations and statements to evaluate them.
-Commands are prefixed with a dot. Valid commands are:
+Input with unclosed parentheses, brackets, or braces is continued on the next line.
+
+Accounts are simulated: Use getAuthAccount(address) to access the storage, keys, and contracts of an account.
+Contracts deployed to an account can be imported, e.g. 'import Foo from 0x1',
+and programs in files can be imported, e.g. 'import Foo from "foo.cdc"'.
 
-.exit     Exit the interpreter
-.help     Print this help message
+Commands are prefixed with a dot or a colon. Valid commands are:
+
+.exit                     Exit the interpreter
+.help                     Print this help message
+.inspect <expression>     Print the structure of the value of the given expression
+.deploy <address> <path>  Deploy the contract in the given file to the account with the given address
 
 Press ^C to abort current expression, ^D to exit
 `
 
 const assistanceMessage = `Type '.help' for assistance.`
 
-func handleCommand(command string) {
+func isCommand(line string) bool {
+	return strings.HasPrefix(line, ".") || strings.HasPrefix(line, ":")
+}
+
+func handleCommand(repl *runtime.REPL, line string) {
+	fields := strings.Fields(line[1:])
+	if len(fields) == 0 {
+		println(colorizeError(fmt.Sprintf("Unknown command. %s", assistanceMessage)))
+		return
+	}
+
+	command := fields[0]
+	arguments := fields[1:]
+
 	switch command {
-	case ".exit":
+	case "exit":
 		os.Exit(0)
-	case ".help":
+
+	case "help":
 		println(helpMessage)
+
+	case "inspect":
+		expression := strings.TrimSpace(strings.TrimPrefix(line[1:], command))
+		if expression == "" {
+			println(colorizeError("Missing expression. Usage: .inspect <expression>"))
+			return
+		}
+
+		description, ok := repl.Inspect(expression)
+		if ok {
+			println(colorizeResult(description))
+		}
+
+	case "deploy":
+		if len(arguments) != 2 {
+			println(colorizeError("Invalid arguments. Usage: .deploy <address> <path>"))
+			return
+		}
+
+		address, err := common.HexToAddress(arguments[0])
+		if err != nil {
+			println(colorizeError(fmt.Sprintf("Invalid address: %s", err)))
+			return
+		}
+
+		code, err := ioutil.ReadFile(arguments[1])
+		if err != nil {
+			println(colorizeError(fmt.Sprintf("Failed to read contract: %s", err)))
+			return
+		}
+
+		if repl.DeployContract(address, code) {
+			println(colorizeResult(fmt.Sprintf("Deployed contract to %s", address.ShortHexWithPrefix())))
+		}
+
 	default:
 		println(colorizeError(fmt.Sprintf("Unknown command. %s", assistanceMessage)))
 	}
@@ -152,9 +211,12 @@ func printWelcome() {
 	fmt.Printf("Welcome to Cadence %s!\n%s\n\n", cadence.Version, assistanceMessage)
 }
 
-func colorizeResult(value interpreter.Value) string {
-	str := value.String()
-	return aurora.Colorize(str, aurora.YellowFg|aurora.BrightFg).String()
+func colorizeValue(value interpreter.Value) string {
+	return colorizeResult(value.String())
+}
+
+func colorizeResult(result string) string {
+	return aurora.Colorize(result, aurora.YellowFg|aurora.BrightFg).String()
 }
 
 func colorizeError(message string) string {
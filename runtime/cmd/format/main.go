@@ -0,0 +1,131 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/formatter"
+	"github.com/onflow/cadence/runtime/pretty"
+)
+
+var writeFlag = flag.Bool("w", false, "write the result to the file instead of printing it")
+var checkFlag = flag.Bool("check", false, "only list the files which are not formatted, and fail if there are any")
+
+// A formatter for Cadence programs.
+//
+// Usage: format [-w | -check] [path ...]
+//
+// If no paths are given, the program is read from standard input.
+//
+func main() {
+	flag.Parse()
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		if *writeFlag {
+			fmt.Fprintln(os.Stderr, "cannot write the result when reading from standard input")
+			os.Exit(2)
+		}
+		paths = []string{""}
+	}
+
+	allSucceeded := true
+
+	for _, path := range paths {
+		if !run(path, *writeFlag, *checkFlag) {
+			allSucceeded = false
+		}
+	}
+
+	if !allSucceeded {
+		os.Exit(1)
+	}
+}
+
+// run formats the program at the given path,
+// and returns false if it is invalid or, in check mode, not formatted
+//
+func run(path string, write bool, check bool) bool {
+	code := read(path)
+
+	formatted, err := formatter.Format(code)
+	if err != nil {
+		location := common.StringLocation(path)
+		printErr := pretty.NewErrorPrettyPrinter(os.Stdout, true).
+			PrettyPrintError(err, location, map[common.LocationID]string{location.ID(): code})
+		if printErr != nil {
+			panic(printErr)
+		}
+		return false
+	}
+
+	switch {
+	case check:
+		if formatted == code {
+			return true
+		}
+
+		name := path
+		if len(name) == 0 {
+			name = "<stdin>"
+		}
+		fmt.Println(name)
+		return false
+
+	case write:
+		if formatted == code {
+			return true
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			panic(err)
+		}
+
+		err = ioutil.WriteFile(path, []byte(formatted), info.Mode().Perm())
+		if err != nil {
+			panic(err)
+		}
+
+	default:
+		fmt.Print(formatted)
+	}
+
+	return true
+}
+
+func read(path string) string {
+	var data []byte
+	var err error
+	if len(path) == 0 {
+		data, err = ioutil.ReadAll(bufio.NewReader(os.Stdin))
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
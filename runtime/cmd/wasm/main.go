@@ -0,0 +1,46 @@
+// +build !wasm
+
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// The wasm command is the WebAssembly build of Cadence, for use in browsers.
+//
+// It is built using `GOARCH=wasm GOOS=js go build ./runtime/cmd/wasm`,
+// and provides global JavaScript functions named `__CADENCE_<name>__`,
+// which are implemented by the jsbinding package:
+//
+//   - `createSession()` returns the ID of a new session
+//   - `releaseSession(id)`
+//   - `parse(id, code)`
+//   - `check(id, code)`
+//   - `createAccount(id)`
+//   - `deployContract(id, address, name, code)`
+//   - `executeTransaction(id, code, arguments, signers)`
+//   - `executeScript(id, code, arguments)`
+//
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	fmt.Fprintln(os.Stderr, "the wasm command must be built with GOARCH=wasm GOOS=js")
+	os.Exit(1)
+}
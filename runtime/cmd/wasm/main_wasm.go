@@ -0,0 +1,121 @@
+// +build wasm
+
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"syscall/js"
+
+	"github.com/onflow/cadence/runtime/jsbinding"
+)
+
+const globalFunctionNamePrefix = "CADENCE"
+
+func globalFunctionName(name string) string {
+	return fmt.Sprintf("__%s_%s__", globalFunctionNamePrefix, name)
+}
+
+// sessions are the sessions created by JavaScript, by ID
+var sessions = map[int]*jsbinding.Session{}
+
+var nextSessionID = 1
+
+func session(args []js.Value) *jsbinding.Session {
+	id := args[0].Int()
+	session, ok := sessions[id]
+	if !ok {
+		panic(fmt.Errorf("unknown session: %d", id))
+	}
+	return session
+}
+
+func stringArgument(args []js.Value, index int) string {
+	if index >= len(args) || args[index].IsUndefined() || args[index].IsNull() {
+		return ""
+	}
+	return args[index].String()
+}
+
+func setGlobalFunction(name string, f func(args []js.Value) interface{}) {
+	js.Global().Set(
+		globalFunctionName(name),
+		js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			return f(args)
+		}),
+	)
+}
+
+func main() {
+
+	log.Println("Cadence")
+
+	done := make(chan struct{}, 0)
+
+	setGlobalFunction("createSession", func(_ []js.Value) interface{} {
+		id := nextSessionID
+		nextSessionID++
+		sessions[id] = jsbinding.NewSession()
+		return id
+	})
+
+	setGlobalFunction("releaseSession", func(args []js.Value) interface{} {
+		delete(sessions, args[0].Int())
+		return nil
+	})
+
+	setGlobalFunction("parse", func(args []js.Value) interface{} {
+		return session(args).Parse(stringArgument(args, 1))
+	})
+
+	setGlobalFunction("check", func(args []js.Value) interface{} {
+		return session(args).Check(stringArgument(args, 1))
+	})
+
+	setGlobalFunction("createAccount", func(args []js.Value) interface{} {
+		return session(args).CreateAccount()
+	})
+
+	setGlobalFunction("deployContract", func(args []js.Value) interface{} {
+		return session(args).DeployContract(
+			stringArgument(args, 1),
+			stringArgument(args, 2),
+			stringArgument(args, 3),
+		)
+	})
+
+	setGlobalFunction("executeTransaction", func(args []js.Value) interface{} {
+		return session(args).ExecuteTransaction(
+			stringArgument(args, 1),
+			stringArgument(args, 2),
+			stringArgument(args, 3),
+		)
+	})
+
+	setGlobalFunction("executeScript", func(args []js.Value) interface{} {
+		return session(args).ExecuteScript(
+			stringArgument(args, 1),
+			stringArgument(args, 2),
+		)
+	})
+
+	<-done
+}
@@ -265,6 +265,11 @@ func (compiler *Compiler) VisitPathExpression(_ *ast.PathExpression) ast.Repr {
 	panic(errors.NewUnreachableError())
 }
 
+func (compiler *Compiler) VisitTryExpression(_ *ast.TryExpression) ast.Repr {
+	// TODO
+	panic(errors.NewUnreachableError())
+}
+
 func (compiler *Compiler) VisitProgram(_ *ast.Program) ast.Repr {
 	// TODO
 	panic(errors.NewUnreachableError())
@@ -0,0 +1,351 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"bytes"
+	goRuntime "runtime"
+	"sort"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// ComputationEstimate is the result of estimating the computation of a transaction,
+// i.e. the computation, the memory, and the storage the transaction would use if it was executed.
+//
+type ComputationEstimate struct {
+	// Computation is the total computation used,
+	// i.e. the number of executed statements, loop iterations, and function invocations,
+	// which is also metered against the computation limit
+	Computation uint64
+	// Functions are the computation and the memory used by each function, sorted by location and function name
+	Functions []FunctionComputation
+	// Storage are the amounts of data read from and written to the storage of each account, sorted by address
+	Storage []StorageUsage
+}
+
+// FunctionComputation is the computation and the memory used by the code of a function.
+//
+// Computation and memory are attributed to the function declaration which contains the metered code:
+// The usage of a function includes the usage of the function expressions it contains,
+// but not the usage of the functions it calls.
+//
+type FunctionComputation struct {
+	Location Location
+	// Function is the qualified name of the function, e.g. `Vault.withdraw`,
+	// or `prepare` and `execute` for the phases of a transaction.
+	// It is empty for code outside of functions, e.g. the initializers of global constants
+	Function    string
+	Computation uint64
+	// MemoryAllocated is the number of bytes allocated while the code of the function was executed.
+	// It is measured on the heap of the process, so it is an approximation,
+	// which also includes the allocations of other goroutines running at the same time
+	MemoryAllocated uint64
+}
+
+// StorageUsage is the amount of data read from and written to the storage of an account.
+//
+type StorageUsage struct {
+	Address      Address
+	BytesRead    uint64
+	BytesWritten uint64
+}
+
+// computationEstimator is a runtime interface which wraps a simulation interface,
+// so that the writes of the transaction are discarded,
+// and which records the computation and the memory used by each function,
+// and the amount of data read from and written to storage.
+//
+// The used computation is not reported to the wrapped interface.
+//
+// The memory is measured whenever the executed code moves from one function to another,
+// and the allocations since the last measurement are attributed to the previous function.
+//
+type computationEstimator struct {
+	*simulationInterface
+	computation uint64
+	locations   map[common.LocationID]*locationComputation
+	storage     map[Address]*StorageUsage
+	// currentFunction is the function which the last computation was attributed to
+	currentFunction *functionUsage
+	// allocated is the total number of bytes allocated by the process at the last measurement
+	allocated uint64
+	memStats  goRuntime.MemStats
+}
+
+var _ Interface = &computationEstimator{}
+
+// locationComputation is the computation and the memory used by the functions of a program
+//
+type locationComputation struct {
+	location  common.Location
+	functions []functionLines
+	used      map[string]*functionUsage
+}
+
+// functionUsage is the computation and the memory used by a function
+//
+type functionUsage struct {
+	computation uint64
+	memory      uint64
+}
+
+// functionLines are the lines of a function declaration
+//
+type functionLines struct {
+	name      string
+	startLine int
+	endLine   int
+}
+
+func newComputationEstimator(runtimeInterface Interface) *computationEstimator {
+	return &computationEstimator{
+		simulationInterface: newSimulationInterface(runtimeInterface),
+		locations:           map[common.LocationID]*locationComputation{},
+		storage:             map[Address]*StorageUsage{},
+	}
+}
+
+// recordComputation records that one unit of computation was used
+// at the given line of the program of the given interpreter.
+//
+func (e *computationEstimator) recordComputation(inter *interpreter.Interpreter, line int) {
+	e.computation++
+
+	location := inter.Location
+	if location == nil {
+		return
+	}
+
+	locationID := location.ID()
+
+	computation, ok := e.locations[locationID]
+	if !ok {
+		computation = &locationComputation{
+			location: location,
+			used:     map[string]*functionUsage{},
+		}
+
+		if inter.Program != nil && inter.Program.Program != nil {
+			computation.functions = programFunctionLines(inter.Program.Program)
+		}
+
+		e.locations[locationID] = computation
+	}
+
+	name := computation.function(line)
+
+	usage, ok := computation.used[name]
+	if !ok {
+		usage = &functionUsage{}
+		computation.used[name] = usage
+	}
+
+	usage.computation++
+
+	if usage != e.currentFunction {
+		e.recordMemory()
+		e.currentFunction = usage
+	}
+}
+
+// recordMemory measures the memory allocated by the process,
+// and attributes the allocations since the last measurement to the current function
+//
+func (e *computationEstimator) recordMemory() {
+	goRuntime.ReadMemStats(&e.memStats)
+	allocated := e.memStats.TotalAlloc
+
+	if e.currentFunction != nil {
+		e.currentFunction.memory += allocated - e.allocated
+	}
+
+	e.allocated = allocated
+}
+
+// function returns the name of the function which contains the given line,
+// or the empty string if no function contains the line
+//
+func (c *locationComputation) function(line int) string {
+	for _, function := range c.functions {
+		if line >= function.startLine && line <= function.endLine {
+			return function.name
+		}
+	}
+	return ""
+}
+
+// programFunctionLines returns the lines of all function declarations of the given program,
+// including the functions of composites, interfaces, and transactions
+//
+func programFunctionLines(program *ast.Program) []functionLines {
+	var result []functionLines
+
+	addFunction := func(name string, declaration ast.HasPosition) {
+		result = append(result, functionLines{
+			name:      name,
+			startLine: declaration.StartPosition().Line,
+			endLine:   declaration.EndPosition().Line,
+		})
+	}
+
+	var addMembers func(prefix string, members *ast.Members)
+	addMembers = func(prefix string, members *ast.Members) {
+		for _, function := range members.Functions() {
+			addFunction(prefix+function.Identifier.Identifier, function)
+		}
+
+		for _, specialFunction := range members.SpecialFunctions() {
+			addFunction(prefix+specialFunction.Kind.Keywords(), specialFunction)
+		}
+
+		for _, composite := range members.Composites() {
+			addMembers(prefix+composite.Identifier.Identifier+".", composite.Members)
+		}
+
+		for _, interfaceDeclaration := range members.Interfaces() {
+			addMembers(prefix+interfaceDeclaration.Identifier.Identifier+".", interfaceDeclaration.Members)
+		}
+	}
+
+	for _, function := range program.FunctionDeclarations() {
+		addFunction(function.Identifier.Identifier, function)
+	}
+
+	for _, composite := range program.CompositeDeclarations() {
+		addMembers(composite.Identifier.Identifier+".", composite.Members)
+	}
+
+	for _, interfaceDeclaration := range program.InterfaceDeclarations() {
+		addMembers(interfaceDeclaration.Identifier.Identifier+".", interfaceDeclaration.Members)
+	}
+
+	for _, transaction := range program.TransactionDeclarations() {
+		if transaction.Prepare != nil {
+			addFunction(transaction.Prepare.Kind.Keywords(), transaction.Prepare)
+		}
+		if transaction.Execute != nil {
+			addFunction(transaction.Execute.Kind.Keywords(), transaction.Execute)
+		}
+	}
+
+	return result
+}
+
+func (e *computationEstimator) storageUsage(address Address) *StorageUsage {
+	usage, ok := e.storage[address]
+	if !ok {
+		usage = &StorageUsage{
+			Address: address,
+		}
+		e.storage[address] = usage
+	}
+	return usage
+}
+
+func (e *computationEstimator) GetValue(owner, key []byte) (value []byte, err error) {
+	value, err = e.simulationInterface.GetValue(owner, key)
+	if err != nil {
+		return nil, err
+	}
+
+	e.storageUsage(common.BytesToAddress(owner)).BytesRead += uint64(len(value))
+
+	return value, nil
+}
+
+func (e *computationEstimator) GetValues(keys []StorageKey) (values [][]byte, err error) {
+	values, err = e.simulationInterface.GetValues(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, value := range values {
+		e.storageUsage(keys[i].Address).BytesRead += uint64(len(value))
+	}
+
+	return values, nil
+}
+
+func (e *computationEstimator) SetValue(owner, key, value []byte) error {
+	e.storageUsage(common.BytesToAddress(owner)).BytesWritten += uint64(len(value))
+
+	return e.simulationInterface.SetValue(owner, key, value)
+}
+
+func (e *computationEstimator) SetValues(writes []StorageWrite) error {
+	for _, write := range writes {
+		e.storageUsage(write.Address).BytesWritten += uint64(len(write.Value))
+	}
+
+	return e.simulationInterface.SetValues(writes)
+}
+
+// estimate returns the recorded computation, memory, and storage usage
+//
+func (e *computationEstimator) estimate() *ComputationEstimate {
+
+	// Attribute the allocations since the last measurement
+	e.recordMemory()
+	e.currentFunction = nil
+
+	var functions []FunctionComputation
+
+	for _, computation := range e.locations { //nolint:maprangecheck
+		for name, used := range computation.used { //nolint:maprangecheck
+			functions = append(functions, FunctionComputation{
+				Location:        computation.location,
+				Function:        name,
+				Computation:     used.computation,
+				MemoryAllocated: used.memory,
+			})
+		}
+	}
+
+	sort.Slice(functions, func(a, b int) bool {
+		functionA := functions[a]
+		functionB := functions[b]
+
+		locationIDA := functionA.Location.ID()
+		locationIDB := functionB.Location.ID()
+		if locationIDA != locationIDB {
+			return locationIDA < locationIDB
+		}
+
+		return functionA.Function < functionB.Function
+	})
+
+	storage := make([]StorageUsage, 0, len(e.storage))
+
+	for _, usage := range e.storage { //nolint:maprangecheck
+		storage = append(storage, *usage)
+	}
+
+	sort.Slice(storage, func(a, b int) bool {
+		return bytes.Compare(storage[a].Address[:], storage[b].Address[:]) < 0
+	})
+
+	return &ComputationEstimate{
+		Computation: e.computation,
+		Functions:   functions,
+		Storage:     storage,
+	}
+}
@@ -0,0 +1,327 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestRuntimeEstimateComputation(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	contract := []byte(`
+      pub contract Counter {
+
+          pub fun count(_ n: Int): Int {
+              var i = 0
+              while i < n {
+                  i = i + 1
+              }
+              return i
+          }
+      }
+    `)
+
+	newRuntimeInterface := func() *testRuntimeInterface {
+		var accountCode []byte
+
+		return &testRuntimeInterface{
+			storage: newTestStorage(nil, nil),
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{address}, nil
+			},
+			resolveLocation: singleIdentifierLocationResolver(t),
+			getAccountContractCode: func(_ Address, _ string) ([]byte, error) {
+				return accountCode, nil
+			},
+			updateAccountContractCode: func(_ Address, _ string, code []byte) error {
+				accountCode = code
+				return nil
+			},
+			emitEvent: func(_ cadence.Event) error {
+				return nil
+			},
+		}
+	}
+
+	deploy := func(t *testing.T, runtime Runtime, runtimeInterface Interface) {
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: utils.DeploymentTransaction("Counter", contract),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.TransactionLocation{0x1},
+			},
+		)
+		require.NoError(t, err)
+	}
+
+	t.Run("functions", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := NewInterpreterRuntime()
+		runtimeInterface := newRuntimeInterface()
+
+		deploy(t, runtime, runtimeInterface)
+
+		estimate, err := runtime.EstimateComputation(
+			Script{
+				Source: []byte(`
+                  import Counter from 0x1
+
+                  transaction {
+                      prepare(signer: AuthAccount) {
+                          Counter.count(3)
+                      }
+
+                      execute {
+                          Counter.count(5)
+                      }
+                  }
+                `),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  utils.TestLocation,
+			},
+		)
+		require.NoError(t, err)
+
+		contractLocation := common.AddressLocation{
+			Address: address,
+			Name:    "Counter",
+		}
+
+		require.Len(t, estimate.Functions, 3)
+
+		assert.Equal(t, contractLocation.ID(), estimate.Functions[0].Location.ID())
+		assert.Equal(t, "Counter.count", estimate.Functions[0].Function)
+
+		assert.Equal(t, utils.TestLocation, estimate.Functions[1].Location)
+		assert.Equal(t, "execute", estimate.Functions[1].Function)
+
+		assert.Equal(t, utils.TestLocation, estimate.Functions[2].Location)
+		assert.Equal(t, "prepare", estimate.Functions[2].Function)
+
+		// The loop in the contract function dominates the computation
+
+		assert.Greater(t, estimate.Functions[0].Computation, estimate.Functions[1].Computation)
+		assert.Greater(t, estimate.Functions[0].Computation, estimate.Functions[2].Computation)
+
+		var total uint64
+		for _, function := range estimate.Functions {
+			total += function.Computation
+		}
+		assert.Equal(t, estimate.Computation, total)
+	})
+
+	t.Run("more work uses more computation", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := NewInterpreterRuntime()
+		runtimeInterface := newRuntimeInterface()
+
+		deploy(t, runtime, runtimeInterface)
+
+		estimateCount := func(n string) uint64 {
+			estimate, err := runtime.EstimateComputation(
+				Script{
+					Source: []byte(`
+                      import Counter from 0x1
+
+                      transaction {
+                          prepare(signer: AuthAccount) {
+                              Counter.count(` + n + `)
+                          }
+                      }
+                    `),
+				},
+				Context{
+					Interface: runtimeInterface,
+					Location:  utils.TestLocation,
+				},
+			)
+			require.NoError(t, err)
+
+			return estimate.Computation
+		}
+
+		assert.Less(t, estimateCount("1"), estimateCount("10"))
+	})
+
+	t.Run("storage", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := NewInterpreterRuntime()
+		runtimeInterface := newRuntimeInterface()
+
+		estimate, err := runtime.EstimateComputation(
+			Script{
+				Source: []byte(`
+                  transaction {
+                      prepare(signer: AuthAccount) {
+                          signer.save("hello", to: /storage/a)
+                      }
+                  }
+                `),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  utils.TestLocation,
+			},
+		)
+		require.NoError(t, err)
+
+		require.Len(t, estimate.Storage, 1)
+		assert.Equal(t, address, estimate.Storage[0].Address)
+		assert.Greater(t, estimate.Storage[0].BytesWritten, uint64(0))
+
+		// The write was discarded
+
+		assert.Empty(t, runtimeInterface.storage.storedValues)
+	})
+
+	t.Run("computation limit", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := NewInterpreterRuntime()
+		runtimeInterface := newRuntimeInterface()
+
+		deploy(t, runtime, runtimeInterface)
+
+		runtimeInterface.computationLimit = 10
+
+		estimate, err := runtime.EstimateComputation(
+			Script{
+				Source: []byte(`
+                  import Counter from 0x1
+
+                  transaction {
+                      prepare(signer: AuthAccount) {
+                          Counter.count(100)
+                      }
+                  }
+                `),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  utils.TestLocation,
+			},
+		)
+		require.Error(t, err)
+
+		var computationLimitErr ComputationLimitExceededError
+		require.ErrorAs(t, err, &computationLimitErr)
+
+		// The computation used until the limit was exceeded is returned
+
+		require.NotNil(t, estimate)
+		assert.GreaterOrEqual(t, estimate.Computation, uint64(10))
+		require.NotEmpty(t, estimate.Functions)
+	})
+
+	t.Run("memory", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := NewInterpreterRuntime()
+		runtimeInterface := newRuntimeInterface()
+
+		estimate, err := runtime.EstimateComputation(
+			Script{
+				Source: []byte(`
+                  pub fun allocate(): [String] {
+                      let strings: [String] = []
+                      var i = 0
+                      while i < 1000 {
+                          strings.append("hello".concat(i.toString()))
+                          i = i + 1
+                      }
+                      return strings
+                  }
+
+                  transaction {
+                      prepare(signer: AuthAccount) {
+                          allocate()
+                      }
+                  }
+                `),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  utils.TestLocation,
+			},
+		)
+		require.NoError(t, err)
+
+		require.Len(t, estimate.Functions, 2)
+
+		assert.Equal(t, "allocate", estimate.Functions[0].Function)
+
+		// Each iteration allocates at least the new string
+		assert.GreaterOrEqual(t, estimate.Functions[0].MemoryAllocated, uint64(1000*len("hello")))
+	})
+
+	t.Run("account creation", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := NewInterpreterRuntime()
+		runtimeInterface := newRuntimeInterface()
+		runtimeInterface.createAccount = func(_ Address) (Address, error) {
+			t.Error("unexpected account creation")
+			return Address{}, nil
+		}
+
+		_, err := runtime.EstimateComputation(
+			Script{
+				Source: []byte(`
+                  transaction {
+                      prepare(signer: AuthAccount) {
+                          let account = AuthAccount(payer: signer)
+                          account.save(1, to: /storage/a)
+                      }
+                  }
+                `),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  utils.TestLocation,
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Empty(t, runtimeInterface.storage.storedValues)
+	})
+}
@@ -27,8 +27,18 @@ type Context struct {
 	Interface         Interface
 	Location          Location
 	PredeclaredValues []ValueDeclaration
-	codes             map[common.LocationID]string
-	programs          map[common.LocationID]*ast.Program
+	// ImportResolver resolves imports and provides the code of imported programs.
+	// If nil, the interface is used.
+	// The code of contracts at address locations is always provided by the interface
+	ImportResolver ImportResolver
+	codes          map[common.LocationID]string
+	programs       map[common.LocationID]*ast.Program
+	// programDependencies collects the dependencies of the program being checked,
+	// if it is going to be stored in the program cache
+	programDependencies programDependencies
+	// computationEstimator records the computation used by the program,
+	// if it is being estimated
+	computationEstimator *computationEstimator
 }
 
 func (c Context) SetCode(location common.Location, code string) {
@@ -39,6 +49,13 @@ func (c Context) SetProgram(location common.Location, program *ast.Program) {
 	c.programs[location.ID()] = program
 }
 
+func (c Context) importResolver() ImportResolver {
+	if c.ImportResolver != nil {
+		return c.ImportResolver
+	}
+	return c.Interface
+}
+
 func (c Context) WithLocation(location common.Location) Context {
 	result := c
 	result.Location = location
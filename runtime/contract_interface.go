@@ -0,0 +1,165 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// ContractInterface is the public interface of a deployed contract or contract interface,
+// i.e. the declarations which are accessible by other programs.
+//
+type ContractInterface struct {
+	Location common.AddressLocation
+	// Kind is either common.DeclarationKindContract or common.DeclarationKindContractInterface
+	Kind      common.DeclarationKind
+	DocString string
+	Fields    []ContractInterfaceField
+	Functions []ContractInterfaceFunction
+	Events    []*cadence.EventType
+	// Types are the nested composite types and interface types, other than events
+	Types []cadence.Type
+}
+
+// ContractInterfaceField is a public field of a contract.
+//
+type ContractInterfaceField struct {
+	Identifier string
+	Type       cadence.Type
+	// Settable is true if the field is declared with `pub(set)` access
+	Settable  bool
+	DocString string
+}
+
+// ContractInterfaceFunction is a public function of a contract.
+//
+type ContractInterfaceFunction struct {
+	Identifier string
+	Parameters []cadence.Parameter
+	ReturnType cadence.Type
+	DocString  string
+}
+
+func newContractInterface(location common.AddressLocation, program *interpreter.Program) (*ContractInterface, error) {
+
+	result := &ContractInterface{
+		Location: location,
+	}
+
+	var members *sema.StringMemberOrderedMap
+	var declarationMembers *ast.Members
+
+	if declaration := program.Program.SoleContractDeclaration(); declaration != nil {
+		result.Kind = common.DeclarationKindContract
+		result.DocString = declaration.DocString
+		members = program.Elaboration.CompositeDeclarationTypes[declaration].Members
+		declarationMembers = declaration.Members
+
+	} else if declaration := program.Program.SoleContractInterfaceDeclaration(); declaration != nil {
+		result.Kind = common.DeclarationKindContractInterface
+		result.DocString = declaration.DocString
+		members = program.Elaboration.InterfaceDeclarationTypes[declaration].Members
+		declarationMembers = declaration.Members
+
+	} else {
+		return nil, &ContractNotDeployedError{
+			Location: location,
+		}
+	}
+
+	results := map[sema.TypeID]cadence.Type{}
+
+	members.Foreach(func(identifier string, member *sema.Member) {
+		if member.Predeclared || !isPublicAccess(member.Access) {
+			return
+		}
+
+		switch member.DeclarationKind {
+		case common.DeclarationKindField:
+			result.Fields = append(
+				result.Fields,
+				ContractInterfaceField{
+					Identifier: identifier,
+					Type:       ExportType(member.TypeAnnotation.Type, results),
+					Settable:   member.Access == ast.AccessPublicSettable,
+					DocString:  member.DocString,
+				},
+			)
+
+		case common.DeclarationKindFunction:
+			functionType, ok := member.TypeAnnotation.Type.(*sema.FunctionType)
+			if !ok {
+				return
+			}
+
+			result.Functions = append(
+				result.Functions,
+				ContractInterfaceFunction{
+					Identifier: identifier,
+					Parameters: exportParameters(functionType.Parameters, results),
+					ReturnType: ExportType(functionType.ReturnTypeAnnotation.Type, results),
+					DocString:  member.DocString,
+				},
+			)
+		}
+	})
+
+	// Nested types are gathered from the declarations, in declaration order.
+	// Type declarations are always public
+
+	for _, declaration := range declarationMembers.Composites() {
+		compositeType := program.Elaboration.CompositeDeclarationTypes[declaration]
+		exportedType := ExportType(compositeType, results)
+
+		if eventType, ok := exportedType.(*cadence.EventType); ok {
+			eventType.Initializer = exportParameters(compositeType.ConstructorParameters, results)
+			result.Events = append(result.Events, eventType)
+		} else {
+			result.Types = append(result.Types, exportedType)
+		}
+	}
+
+	for _, declaration := range declarationMembers.Interfaces() {
+		interfaceType := program.Elaboration.InterfaceDeclarationTypes[declaration]
+		result.Types = append(result.Types, ExportType(interfaceType, results))
+	}
+
+	return result, nil
+}
+
+func isPublicAccess(access ast.Access) bool {
+	return access == ast.AccessPublic ||
+		access == ast.AccessPublicSettable
+}
+
+func exportParameters(parameters []*sema.Parameter, results map[sema.TypeID]cadence.Type) []cadence.Parameter {
+	exported := make([]cadence.Parameter, len(parameters))
+	for i, parameter := range parameters {
+		exported[i] = cadence.Parameter{
+			Label:      parameter.Label,
+			Identifier: parameter.Identifier,
+			Type:       ExportType(parameter.TypeAnnotation.Type, results),
+		}
+	}
+	return exported
+}
@@ -0,0 +1,260 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+func TestRuntimeGetContractInterface(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	getContractInterface := func(name string, code string) (*ContractInterface, error) {
+
+		runtime := NewInterpreterRuntime()
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestStorage(nil, nil),
+			getAccountContractCode: func(_ Address, _ string) ([]byte, error) {
+				return []byte(code), nil
+			},
+		}
+
+		return runtime.GetContractInterface(
+			common.AddressLocation{
+				Address: address,
+				Name:    name,
+			},
+			Context{
+				Interface: runtimeInterface,
+			},
+		)
+	}
+
+	t.Run("contract", func(t *testing.T) {
+
+		t.Parallel()
+
+		contractInterface, err := getContractInterface(
+			"Test",
+			`
+              /// The test contract
+              pub contract Test {
+
+                  /// The total
+                  pub var total: Int
+
+                  pub(set) var name: String
+
+                  priv var secret: Int
+
+                  access(account) let internal: Int
+
+                  pub event Deposit(amount: UInt64, to: Address?)
+
+                  pub struct S {
+                      pub let x: Int
+                      init() {
+                          self.x = 1
+                      }
+                  }
+
+                  pub resource interface Receiver {}
+
+                  init() {
+                      self.total = 0
+                      self.name = ""
+                      self.secret = 0
+                      self.internal = 0
+                  }
+
+                  /// Adds to the total
+                  pub fun add(_ amount: Int, from sender: Address): Int {
+                      self.total = self.total + amount
+                      return self.total
+                  }
+
+                  priv fun helper() {}
+              }
+            `,
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, common.DeclarationKindContract, contractInterface.Kind)
+		assert.Equal(t, " The test contract", contractInterface.DocString)
+
+		assert.Equal(t,
+			[]ContractInterfaceField{
+				{
+					Identifier: "total",
+					Type:       cadence.IntType{},
+					DocString:  " The total",
+				},
+				{
+					Identifier: "name",
+					Type:       cadence.StringType{},
+					Settable:   true,
+				},
+			},
+			contractInterface.Fields,
+		)
+
+		assert.Equal(t,
+			[]ContractInterfaceFunction{
+				{
+					Identifier: "add",
+					Parameters: []cadence.Parameter{
+						{
+							Label:      "_",
+							Identifier: "amount",
+							Type:       cadence.IntType{},
+						},
+						{
+							Label:      "from",
+							Identifier: "sender",
+							Type:       cadence.AddressType{},
+						},
+					},
+					ReturnType: cadence.IntType{},
+					DocString:  " Adds to the total",
+				},
+			},
+			contractInterface.Functions,
+		)
+
+		require.Len(t, contractInterface.Events, 1)
+
+		event := contractInterface.Events[0]
+		assert.Equal(t, "Test.Deposit", event.QualifiedIdentifier)
+		assert.Equal(t,
+			[]cadence.Parameter{
+				{
+					Label:      "",
+					Identifier: "amount",
+					Type:       cadence.UInt64Type{},
+				},
+				{
+					Label:      "",
+					Identifier: "to",
+					Type:       cadence.OptionalType{Type: cadence.AddressType{}},
+				},
+			},
+			event.Initializer,
+		)
+
+		require.Len(t, contractInterface.Types, 2)
+
+		require.IsType(t, &cadence.StructType{}, contractInterface.Types[0])
+		assert.Equal(t,
+			"Test.S",
+			contractInterface.Types[0].(*cadence.StructType).QualifiedIdentifier,
+		)
+
+		require.IsType(t, &cadence.ResourceInterfaceType{}, contractInterface.Types[1])
+		assert.Equal(t,
+			"Test.Receiver",
+			contractInterface.Types[1].(*cadence.ResourceInterfaceType).QualifiedIdentifier,
+		)
+	})
+
+	t.Run("contract interface", func(t *testing.T) {
+
+		t.Parallel()
+
+		contractInterface, err := getContractInterface(
+			"Test",
+			`
+              pub contract interface Test {
+
+                  pub let total: Int
+
+                  pub fun add(_ amount: Int)
+              }
+            `,
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, common.DeclarationKindContractInterface, contractInterface.Kind)
+
+		assert.Equal(t,
+			[]ContractInterfaceField{
+				{
+					Identifier: "total",
+					Type:       cadence.IntType{},
+				},
+			},
+			contractInterface.Fields,
+		)
+
+		assert.Equal(t,
+			[]ContractInterfaceFunction{
+				{
+					Identifier: "add",
+					Parameters: []cadence.Parameter{
+						{
+							Label:      "_",
+							Identifier: "amount",
+							Type:       cadence.IntType{},
+						},
+					},
+					ReturnType: cadence.VoidType{},
+				},
+			},
+			contractInterface.Functions,
+		)
+	})
+
+	t.Run("not deployed", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := getContractInterface("Test", "")
+		require.Error(t, err)
+
+		var notDeployedErr *ContractNotDeployedError
+		require.ErrorAs(t, err, &notDeployedErr)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := getContractInterface(
+			"Test",
+			`
+              pub contract Test {
+                  pub let x: Int
+              }
+            `,
+		)
+		require.Error(t, err)
+
+		var checkingErr *ParsingCheckingError
+		require.ErrorAs(t, err, &checkingErr)
+	})
+}
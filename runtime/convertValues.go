@@ -206,12 +206,11 @@ func exportCompositeValue(
 	fieldNames := t.CompositeFields()
 	fields := make([]cadence.Value, len(fieldNames))
 
-	fieldsMap := v.Fields()
 	for i, field := range fieldNames {
 		fieldName := field.Identifier
-		fieldValue, ok := fieldsMap.Get(fieldName)
+		fieldValue := v.GetField(fieldName)
 
-		if !ok && v.ComputedFields != nil {
+		if fieldValue == nil && v.ComputedFields != nil {
 			if computedField, ok := v.ComputedFields.Get(fieldName); ok {
 				fieldValue = computedField(inter)
 			}
@@ -293,7 +292,7 @@ func exportDictionaryValue(
 
 func exportLinkValue(v interpreter.LinkValue, inter *interpreter.Interpreter) cadence.Link {
 	path := exportPathValue(v.TargetPath)
-	ty := string(inter.ConvertStaticToSemaType(v.Type).ID())
+	ty := string(inter.SemaTypeID(inter.ConvertStaticToSemaType(v.Type)))
 	return cadence.NewLink(path, ty)
 }
 
@@ -308,7 +307,7 @@ func exportTypeValue(v interpreter.TypeValue, inter *interpreter.Interpreter) ca
 	var typeID string
 	staticType := v.Type
 	if staticType != nil {
-		typeID = string(inter.ConvertStaticToSemaType(staticType).ID())
+		typeID = string(inter.SemaTypeID(inter.ConvertStaticToSemaType(staticType)))
 	}
 	return cadence.TypeValue{
 		StaticType: typeID,
@@ -318,7 +317,7 @@ func exportTypeValue(v interpreter.TypeValue, inter *interpreter.Interpreter) ca
 func exportCapabilityValue(v interpreter.CapabilityValue, inter *interpreter.Interpreter) cadence.Capability {
 	var borrowType string
 	if v.BorrowType != nil {
-		borrowType = string(inter.ConvertStaticToSemaType(v.BorrowType).ID())
+		borrowType = string(inter.SemaTypeID(inter.ConvertStaticToSemaType(v.BorrowType)))
 	}
 
 	return cadence.Capability{
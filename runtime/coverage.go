@@ -18,7 +18,12 @@
 
 package runtime
 
-import "github.com/onflow/cadence/runtime/common"
+import (
+	"sort"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+)
 
 // LocationCoverage records coverage information for a location
 //
@@ -30,6 +35,38 @@ func (c *LocationCoverage) AddLineHit(line int) {
 	c.LineHits[line]++
 }
 
+// AddLine records the given line as a line with statements,
+// without recording a hit
+//
+func (c *LocationCoverage) AddLine(line int) {
+	if _, ok := c.LineHits[line]; !ok {
+		c.LineHits[line] = 0
+	}
+}
+
+// Lines returns the recorded lines, in ascending order
+//
+func (c *LocationCoverage) Lines() []int {
+	lines := make([]int, 0, len(c.LineHits))
+	for line := range c.LineHits {
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+	return lines
+}
+
+// CoveredLines returns the number of lines which were hit at least once
+//
+func (c *LocationCoverage) CoveredLines() int {
+	count := 0
+	for _, hits := range c.LineHits {
+		if hits > 0 {
+			count++
+		}
+	}
+	return count
+}
+
 func NewLocationCoverage() *LocationCoverage {
 	return &LocationCoverage{
 		LineHits: map[int]int{},
@@ -42,14 +79,56 @@ type CoverageReport struct {
 	Coverage map[common.LocationID]*LocationCoverage `json:"coverage"`
 }
 
-func (r *CoverageReport) AddLineHit(location common.Location, line int) {
+func (r *CoverageReport) locationCoverage(location common.Location) *LocationCoverage {
 	locationID := location.ID()
 	locationCoverage := r.Coverage[locationID]
 	if locationCoverage == nil {
 		locationCoverage = NewLocationCoverage()
 		r.Coverage[locationID] = locationCoverage
 	}
-	locationCoverage.AddLineHit(line)
+	return locationCoverage
+}
+
+func (r *CoverageReport) AddLineHit(location common.Location, line int) {
+	r.locationCoverage(location).AddLineHit(line)
+}
+
+// InspectProgram records all lines of the given program which contain statements,
+// so that lines which are never executed are reported as not covered
+//
+func (r *CoverageReport) InspectProgram(location common.Location, program *ast.Program) {
+	locationCoverage := r.locationCoverage(location)
+
+	addStatements := func(statements []ast.Statement) {
+		for _, statement := range statements {
+			locationCoverage.AddLine(statement.StartPosition().Line)
+		}
+	}
+
+	ast.Inspect(program, func(element ast.Element) bool {
+		switch element := element.(type) {
+		case *ast.Block:
+			addStatements(element.Statements)
+		case *ast.SwitchStatement:
+			for _, switchCase := range element.Cases {
+				addStatements(switchCase.Statements)
+			}
+		}
+		return true
+	})
+}
+
+// LocationIDs returns the IDs of the locations in the report, in ascending order
+//
+func (r *CoverageReport) LocationIDs() []common.LocationID {
+	locationIDs := make([]common.LocationID, 0, len(r.Coverage))
+	for locationID := range r.Coverage {
+		locationIDs = append(locationIDs, locationID)
+	}
+	sort.Slice(locationIDs, func(i, j int) bool {
+		return locationIDs[i] < locationIDs[j]
+	})
+	return locationIDs
 }
 
 func NewCoverageReport() *CoverageReport {
@@ -0,0 +1,199 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// This file defines exporters for coverage reports,
+// in the formats supported by standard coverage tools.
+
+// SourceFileFunc returns the path of the source file for the given location.
+// A nil function uses the location ID as the path
+//
+type SourceFileFunc func(locationID common.LocationID) string
+
+func sourceFile(sourceFileFunc SourceFileFunc, locationID common.LocationID) string {
+	if sourceFileFunc == nil {
+		return string(locationID)
+	}
+	return sourceFileFunc(locationID)
+}
+
+// WriteLCOV writes the report in the LCOV tracefile format
+//
+func (r *CoverageReport) WriteLCOV(w io.Writer, sourceFileFunc SourceFileFunc) error {
+	for _, locationID := range r.LocationIDs() {
+		locationCoverage := r.Coverage[locationID]
+
+		_, err := fmt.Fprintf(w, "TN:\nSF:%s\n", sourceFile(sourceFileFunc, locationID))
+		if err != nil {
+			return err
+		}
+
+		lines := locationCoverage.Lines()
+
+		for _, line := range lines {
+			_, err = fmt.Fprintf(w, "DA:%d,%d\n", line, locationCoverage.LineHits[line])
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = fmt.Fprintf(
+			w,
+			"LF:%d\nLH:%d\nend_of_record\n",
+			len(lines),
+			locationCoverage.CoveredLines(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+const coberturaDocType = `<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">`
+
+type coberturaCoverage struct {
+	XMLName         xml.Name           `xml:"coverage"`
+	LineRate        string             `xml:"line-rate,attr"`
+	BranchRate      string             `xml:"branch-rate,attr"`
+	LinesCovered    int                `xml:"lines-covered,attr"`
+	LinesValid      int                `xml:"lines-valid,attr"`
+	BranchesCovered int                `xml:"branches-covered,attr"`
+	BranchesValid   int                `xml:"branches-valid,attr"`
+	Complexity      string             `xml:"complexity,attr"`
+	Version         string             `xml:"version,attr"`
+	Timestamp       int64              `xml:"timestamp,attr"`
+	Sources         []string           `xml:"sources>source"`
+	Packages        []coberturaPackage `xml:"packages>package"`
+}
+
+type coberturaPackage struct {
+	Name       string           `xml:"name,attr"`
+	LineRate   string           `xml:"line-rate,attr"`
+	BranchRate string           `xml:"branch-rate,attr"`
+	Complexity string           `xml:"complexity,attr"`
+	Classes    []coberturaClass `xml:"classes>class"`
+}
+
+type coberturaClass struct {
+	Name       string          `xml:"name,attr"`
+	Filename   string          `xml:"filename,attr"`
+	LineRate   string          `xml:"line-rate,attr"`
+	BranchRate string          `xml:"branch-rate,attr"`
+	Complexity string          `xml:"complexity,attr"`
+	Methods    struct{}        `xml:"methods"`
+	Lines      []coberturaLine `xml:"lines>line"`
+}
+
+type coberturaLine struct {
+	Number int `xml:"number,attr"`
+	Hits   int `xml:"hits,attr"`
+}
+
+func coberturaRate(covered, valid int) string {
+	if valid == 0 {
+		return "1"
+	}
+	return strconv.FormatFloat(float64(covered)/float64(valid), 'f', -1, 64)
+}
+
+// WriteCobertura writes the report in the Cobertura XML format.
+//
+// Each location is reported as a package with a single class.
+// Branch coverage is not recorded, so it is always reported as zero
+//
+func (r *CoverageReport) WriteCobertura(
+	w io.Writer,
+	sourceFileFunc SourceFileFunc,
+	timestamp time.Time,
+) error {
+	coverage := coberturaCoverage{
+		BranchRate: "0",
+		Complexity: "0",
+		Timestamp:  timestamp.UnixNano() / int64(time.Millisecond),
+		Sources:    []string{"."},
+	}
+
+	for _, locationID := range r.LocationIDs() {
+		locationCoverage := r.Coverage[locationID]
+
+		lines := locationCoverage.Lines()
+		coveredLines := locationCoverage.CoveredLines()
+		lineRate := coberturaRate(coveredLines, len(lines))
+
+		class := coberturaClass{
+			Name:       string(locationID),
+			Filename:   sourceFile(sourceFileFunc, locationID),
+			LineRate:   lineRate,
+			BranchRate: "0",
+			Complexity: "0",
+			Lines:      make([]coberturaLine, len(lines)),
+		}
+
+		for i, line := range lines {
+			class.Lines[i] = coberturaLine{
+				Number: line,
+				Hits:   locationCoverage.LineHits[line],
+			}
+		}
+
+		coverage.Packages = append(
+			coverage.Packages,
+			coberturaPackage{
+				Name:       string(locationID),
+				LineRate:   lineRate,
+				BranchRate: "0",
+				Complexity: "0",
+				Classes:    []coberturaClass{class},
+			},
+		)
+
+		coverage.LinesCovered += coveredLines
+		coverage.LinesValid += len(lines)
+	}
+
+	coverage.LineRate = coberturaRate(coverage.LinesCovered, coverage.LinesValid)
+
+	_, err := io.WriteString(w, xml.Header+coberturaDocType+"\n")
+	if err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	err = encoder.Encode(coverage)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
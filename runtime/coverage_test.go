@@ -21,7 +21,9 @@ package runtime
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -107,6 +109,7 @@ func TestRuntimeCoverage(t *testing.T) {
               "line_hits": {
                 "5": 1,
                 "6": 1,
+                "7": 0,
                 "9": 1
               }
             }
@@ -116,3 +119,90 @@ func TestRuntimeCoverage(t *testing.T) {
 		string(actual),
 	)
 }
+
+func newTestCoverageReport() *CoverageReport {
+	coverageReport := NewCoverageReport()
+
+	imported := common.StringLocation("imported")
+	coverageReport.AddLineHit(imported, 3)
+	coverageReport.AddLineHit(imported, 5)
+	coverageReport.AddLineHit(imported, 5)
+
+	script := common.ScriptLocation{0x1}
+	coverageReport.locationCoverage(script).AddLine(2)
+	coverageReport.AddLineHit(script, 1)
+
+	return coverageReport
+}
+
+func TestCoverageReportWriteLCOV(t *testing.T) {
+
+	t.Parallel()
+
+	var builder strings.Builder
+	err := newTestCoverageReport().WriteLCOV(
+		&builder,
+		func(locationID common.LocationID) string {
+			return fmt.Sprintf("%s.cdc", locationID)
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		"TN:\n"+
+			"SF:S.imported.cdc\n"+
+			"DA:3,1\n"+
+			"DA:5,2\n"+
+			"LF:2\n"+
+			"LH:2\n"+
+			"end_of_record\n"+
+			"TN:\n"+
+			"SF:s.01.cdc\n"+
+			"DA:1,1\n"+
+			"DA:2,0\n"+
+			"LF:2\n"+
+			"LH:1\n"+
+			"end_of_record\n",
+		builder.String(),
+	)
+}
+
+func TestCoverageReportWriteCobertura(t *testing.T) {
+
+	t.Parallel()
+
+	coverageReport := NewCoverageReport()
+
+	location := common.StringLocation("test")
+	coverageReport.AddLineHit(location, 1)
+	coverageReport.locationCoverage(location).AddLine(2)
+
+	var builder strings.Builder
+	err := coverageReport.WriteCobertura(&builder, nil, time.Unix(1, 0))
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">
+<coverage line-rate="0.5" branch-rate="0" lines-covered="1" lines-valid="2" branches-covered="0" branches-valid="0" complexity="0" version="" timestamp="1000">
+  <sources>
+    <source>.</source>
+  </sources>
+  <packages>
+    <package name="S.test" line-rate="0.5" branch-rate="0" complexity="0">
+      <classes>
+        <class name="S.test" filename="S.test" line-rate="0.5" branch-rate="0" complexity="0">
+          <methods></methods>
+          <lines>
+            <line number="1" hits="1"></line>
+            <line number="2" hits="0"></line>
+          </lines>
+        </class>
+      </classes>
+    </package>
+  </packages>
+</coverage>
+`,
+		builder.String(),
+	)
+}
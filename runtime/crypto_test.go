@@ -19,6 +19,7 @@
 package runtime
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -439,3 +440,264 @@ func TestRuntimeHashAlgorithmImport(t *testing.T) {
 		testHashAlgorithm(algo)
 	}
 }
+
+func TestRuntimeBLS(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := NewInterpreterRuntime()
+
+	executeScript := func(code string, inter Interface) (cadence.Value, error) {
+		return runtime.ExecuteScript(
+			Script{
+				Source: []byte(code),
+			},
+			Context{
+				Interface: inter,
+				Location:  utils.TestLocation,
+			},
+		)
+	}
+
+	t.Run("verifyPoP", func(t *testing.T) {
+
+		t.Parallel()
+
+		script := `
+            pub fun main(): Bool {
+                let publicKey = PublicKey(
+                    publicKey: "0102".decodeHex(),
+                    signatureAlgorithm: SignatureAlgorithm.BLS_BLS12_381
+                )
+
+                return publicKey.verifyPoP("0102030405".decodeHex())
+            }
+        `
+
+		called := false
+
+		runtimeInterface := &testRuntimeInterface{
+			blsVerifyPOP: func(publicKey *PublicKey, signature []byte) (bool, error) {
+				called = true
+				assert.Equal(t, []byte{1, 2}, publicKey.PublicKey)
+				assert.Equal(t, SignatureAlgorithmBLS_BLS12_381, publicKey.SignAlgo)
+				assert.Equal(t, []byte{1, 2, 3, 4, 5}, signature)
+				return true, nil
+			},
+		}
+
+		result, err := executeScript(script, runtimeInterface)
+		require.NoError(t, err)
+
+		assert.Equal(t, cadence.NewBool(true), result)
+		assert.True(t, called)
+	})
+
+	t.Run("aggregateSignatures", func(t *testing.T) {
+
+		t.Parallel()
+
+		script := `
+            pub fun main(): [UInt8]? {
+                let signatures: [[UInt8]] = [
+                    [1, 1, 1, 1, 1],
+                    [2, 2, 2, 2, 2]
+                ]
+                return BLS.aggregateSignatures(signatures)
+            }
+        `
+
+		called := false
+
+		runtimeInterface := &testRuntimeInterface{
+			blsAggregateSignatures: func(signatures [][]byte) ([]byte, error) {
+				called = true
+				assert.Equal(t,
+					[][]byte{
+						{1, 1, 1, 1, 1},
+						{2, 2, 2, 2, 2},
+					},
+					signatures,
+				)
+				return []byte{3, 3, 3, 3, 3}, nil
+			},
+		}
+
+		result, err := executeScript(script, runtimeInterface)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			cadence.NewOptional(
+				cadence.NewArray([]cadence.Value{
+					cadence.NewUInt8(3),
+					cadence.NewUInt8(3),
+					cadence.NewUInt8(3),
+					cadence.NewUInt8(3),
+					cadence.NewUInt8(3),
+				}),
+			),
+			result,
+		)
+		assert.True(t, called)
+	})
+
+	t.Run("aggregateSignatures, invalid", func(t *testing.T) {
+
+		t.Parallel()
+
+		script := `
+            pub fun main(): [UInt8]? {
+                return BLS.aggregateSignatures([])
+            }
+        `
+
+		runtimeInterface := &testRuntimeInterface{
+			blsAggregateSignatures: func(signatures [][]byte) ([]byte, error) {
+				return nil, errors.New("empty signatures")
+			},
+		}
+
+		result, err := executeScript(script, runtimeInterface)
+		require.NoError(t, err)
+
+		assert.Equal(t, cadence.NewOptional(nil), result)
+	})
+
+	t.Run("aggregatePublicKeys", func(t *testing.T) {
+
+		t.Parallel()
+
+		script := `
+            pub fun main(): [UInt8] {
+                let k1 = PublicKey(
+                    publicKey: "0102".decodeHex(),
+                    signatureAlgorithm: SignatureAlgorithm.BLS_BLS12_381
+                )
+                let k2 = PublicKey(
+                    publicKey: "0304".decodeHex(),
+                    signatureAlgorithm: SignatureAlgorithm.BLS_BLS12_381
+                )
+
+                let aggregated = BLS.aggregatePublicKeys([k1, k2])!
+                return aggregated.publicKey
+            }
+        `
+
+		called := false
+
+		runtimeInterface := &testRuntimeInterface{
+			blsAggregatePublicKeys: func(publicKeys []*PublicKey) (*PublicKey, error) {
+				called = true
+				require.Len(t, publicKeys, 2)
+				assert.Equal(t, []byte{1, 2}, publicKeys[0].PublicKey)
+				assert.Equal(t, []byte{3, 4}, publicKeys[1].PublicKey)
+				return &PublicKey{
+					PublicKey: []byte{5, 6},
+					SignAlgo:  SignatureAlgorithmBLS_BLS12_381,
+				}, nil
+			},
+		}
+
+		result, err := executeScript(script, runtimeInterface)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			cadence.NewArray([]cadence.Value{
+				cadence.NewUInt8(5),
+				cadence.NewUInt8(6),
+			}),
+			result,
+		)
+		assert.True(t, called)
+	})
+}
+
+func TestRuntimeECRecover(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := NewInterpreterRuntime()
+
+	executeScript := func(code string, inter Interface) (cadence.Value, error) {
+		return runtime.ExecuteScript(
+			Script{
+				Source: []byte(code),
+			},
+			Context{
+				Interface: inter,
+				Location:  utils.TestLocation,
+			},
+		)
+	}
+
+	t.Run("recovered", func(t *testing.T) {
+
+		t.Parallel()
+
+		script := `
+            pub fun main(): [UInt8] {
+                let publicKey = ecrecover(
+                    signature: "0102".decodeHex(),
+                    signedData: "0304".decodeHex(),
+                    hashAlgorithm: HashAlgorithm.KECCAK_256
+                )!
+
+                assert(publicKey.signatureAlgorithm == SignatureAlgorithm.ECDSA_secp256k1)
+
+                return publicKey.publicKey
+            }
+        `
+
+		called := false
+
+		runtimeInterface := &testRuntimeInterface{
+			recoverPublicKey: func(
+				signature []byte,
+				signedData []byte,
+				signatureAlgorithm SignatureAlgorithm,
+				hashAlgorithm HashAlgorithm,
+			) ([]byte, error) {
+				called = true
+				assert.Equal(t, []byte{1, 2}, signature)
+				assert.Equal(t, []byte{3, 4}, signedData)
+				assert.Equal(t, SignatureAlgorithmECDSA_secp256k1, signatureAlgorithm)
+				assert.Equal(t, HashAlgorithmKECCAK_256, hashAlgorithm)
+				return []byte{5, 6}, nil
+			},
+		}
+
+		result, err := executeScript(script, runtimeInterface)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			cadence.NewArray([]cadence.Value{
+				cadence.NewUInt8(5),
+				cadence.NewUInt8(6),
+			}),
+			result,
+		)
+		assert.True(t, called)
+	})
+
+	t.Run("not recovered", func(t *testing.T) {
+
+		t.Parallel()
+
+		script := `
+            pub fun main(): Bool {
+                let publicKey = ecrecover(
+                    signature: "0102".decodeHex(),
+                    signedData: "0304".decodeHex(),
+                    hashAlgorithm: HashAlgorithm.KECCAK_256
+                )
+
+                return publicKey == nil
+            }
+        `
+
+		result, err := executeScript(script, &testRuntimeInterface{})
+		require.NoError(t, err)
+
+		assert.Equal(t, cadence.NewBool(true), result)
+	})
+}
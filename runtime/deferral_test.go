@@ -21,6 +21,7 @@ package runtime
 import (
 	"encoding/hex"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -1301,6 +1302,145 @@ func TestRuntimeStorageDeferredResourceDictionaryValues_Destruction(t *testing.T
 	)
 }
 
+func TestRuntimeStorageDeferredResourceDictionaryValues_Prefetch(t *testing.T) {
+
+	// Test that deferred values are read in one batch when all values are loaded
+
+	runtime := NewInterpreterRuntime()
+
+	contract := []byte(simpleDeferralContract)
+
+	deployTx := utils.DeploymentTransaction("Test", contract)
+
+	setupTx := []byte(`
+      import Test from 0x1
+
+      transaction {
+
+          prepare(signer: AuthAccount) {
+              let c <- Test.createC()
+              c.rs["a"] <-! Test.createR(1)
+              c.rs["b"] <-! Test.createR(2)
+              c.rs["c"] <-! Test.createR(3)
+              signer.save(<-c, to: /storage/c)
+          }
+      }
+    `)
+
+	testTx := []byte(`
+      import Test from 0x1
+
+      transaction {
+
+         prepare(signer: AuthAccount) {
+             let c <- signer.load<@Test.C>(from: /storage/c)
+             destroy c
+         }
+      }
+    `)
+
+	var accountCode []byte
+	var loggedMessages []string
+
+	signer := common.BytesToAddress([]byte{0x1})
+
+	var singleReads []string
+
+	storage := newTestStorage(
+		func(_, key, _ []byte) {
+			singleReads = append(singleReads, string(key))
+		},
+		nil,
+	)
+
+	var batchReads [][]StorageKey
+
+	storage.getValues = func(keys []StorageKey) (values [][]byte, err error) {
+		batchReads = append(batchReads, keys)
+
+		values = make([][]byte, len(keys))
+		for i, key := range keys {
+			values[i] = storage.storedValues[strings.Join([]string{string(key.Address[:]), key.Key}, "|")]
+		}
+		return values, nil
+	}
+
+	runtimeInterface := &testRuntimeInterface{
+		getCode: func(_ Location) (bytes []byte, err error) {
+			return accountCode, nil
+		},
+		storage: storage,
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{signer}, nil
+		},
+		resolveLocation: singleIdentifierLocationResolver(t),
+		getAccountContractCode: func(_ Address, _ string) (code []byte, err error) {
+			return accountCode, nil
+		},
+		updateAccountContractCode: func(_ Address, _ string, code []byte) error {
+			accountCode = code
+			return nil
+		},
+		emitEvent: func(event cadence.Event) error {
+			return nil
+		},
+		log: func(message string) {
+			loggedMessages = append(loggedMessages, message)
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	for _, tx := range [][]byte{deployTx, setupTx} {
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: tx,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.NoError(t, err)
+	}
+
+	singleReads = nil
+	batchReads = nil
+
+	err := runtime.ExecuteTransaction(
+		Script{
+			Source: testTx,
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		[]string{
+			`"destroying R"`,
+			"1",
+			`"destroying R"`,
+			"2",
+			`"destroying R"`,
+			"3",
+		},
+		loggedMessages,
+	)
+
+	// The deferred values are read in one batch,
+	// and not individually
+
+	require.Len(t, batchReads, 1)
+	require.Len(t, batchReads[0], 3)
+
+	for _, key := range batchReads[0] {
+		assert.NotContains(t, singleReads, key.Key)
+	}
+}
+
 func TestRuntimeStorageDeferredResourceDictionaryValues_Insertion(t *testing.T) {
 
 	// Test that the `insert` function correctly loads the potentially deferred value
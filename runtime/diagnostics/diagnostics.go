@@ -0,0 +1,340 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package diagnostics converts the errors of the parser and the checker,
+// and the hints of the checker, to diagnostics.
+//
+// Diagnostics are structured and can be serialized as JSON,
+// so tools like editors and continuous integration systems can process them
+// without parsing error messages, which may change between releases.
+// The JSON encoding of diagnostics is stable: fields are only ever added.
+//
+package diagnostics
+
+import (
+	goErrors "errors"
+	"reflect"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// Severity is the severity of a diagnostic
+//
+type Severity string
+
+const (
+	// SeverityError is the severity of errors, which prevent the program from being executed
+	SeverityError Severity = "error"
+	// SeverityWarning is the severity of warnings, which indicate likely problems
+	SeverityWarning Severity = "warning"
+	// SeverityHint is the severity of hints, which suggest improvements of the program
+	SeverityHint Severity = "hint"
+)
+
+// Position is a position in a program.
+//
+type Position struct {
+	// Offset is the offset in bytes, starting at 0
+	Offset int `json:"offset"`
+	// Line is the line number, starting at 1
+	Line int `json:"line"`
+	// Column is the column number in bytes, starting at 0
+	Column int `json:"column"`
+}
+
+func newPosition(position ast.Position) Position {
+	return Position{
+		Offset: position.Offset,
+		Line:   position.Line,
+		Column: position.Column,
+	}
+}
+
+// Range is a range of a program.
+// Like ranges of the AST, the end position is inclusive.
+//
+type Range struct {
+	StartPos Position `json:"startPos"`
+	EndPos   Position `json:"endPos"`
+}
+
+func newRange(hasPosition ast.HasPosition) Range {
+	return Range{
+		StartPos: newPosition(hasPosition.StartPosition()),
+		EndPos:   newPosition(hasPosition.EndPosition()),
+	}
+}
+
+// SecondaryRange is a range which is related to a diagnostic,
+// e.g. the previous declaration of a redeclared name
+//
+type SecondaryRange struct {
+	Message string `json:"message"`
+	Range   Range  `json:"range"`
+}
+
+// TextEdit is a change of the code of a program.
+//
+// If the insertion is not empty, it is inserted before the start position of the range.
+// Otherwise, the range is replaced with the replacement, which may be empty to remove the range.
+//
+type TextEdit struct {
+	Replacement string `json:"replacement"`
+	Insertion   string `json:"insertion,omitempty"`
+	Range       Range  `json:"range"`
+}
+
+// SuggestedFix is a suggested change of the program,
+// which resolves the problem reported by a diagnostic.
+//
+type SuggestedFix struct {
+	Message   string     `json:"message"`
+	TextEdits []TextEdit `json:"textEdits"`
+}
+
+// Diagnostic is a problem of a program, e.g. a syntax error, a semantic error, or a hint.
+//
+type Diagnostic struct {
+	// Code is the stable error code, e.g. `E2001`,
+	// or empty if the error has no registered code or the diagnostic is a hint
+	Code string `json:"code,omitempty"`
+	// Name is the name of the error or hint, e.g. `sema.TypeMismatchError`
+	Name     string   `json:"name"`
+	Severity Severity `json:"severity"`
+	// Location is the ID of the location of the program which has the problem
+	Location common.LocationID `json:"location,omitempty"`
+	Message  string            `json:"message"`
+	// SecondaryMessage is the optional explanation of the problem, e.g. the expected and the actual type
+	SecondaryMessage string `json:"secondaryMessage,omitempty"`
+	// Range is the primary range of the problem, if any
+	Range           *Range           `json:"range,omitempty"`
+	SecondaryRanges []SecondaryRange `json:"secondaryRanges,omitempty"`
+	SuggestedFixes  []SuggestedFix   `json:"suggestedFixes,omitempty"`
+}
+
+// FromError returns the diagnostics for the problems contained in the given error,
+// e.g. a parser or checker error, at the given location.
+//
+// The errors contained in parent errors are reported as separate diagnostics.
+// Import locations of errors are respected, so the errors of imported programs
+// are reported at the location of the imported program.
+//
+func FromError(err error, location common.Location) []Diagnostic {
+	var diagnostics []Diagnostic
+	collectErrorDiagnostics(err, location, &diagnostics)
+	return diagnostics
+}
+
+func collectErrorDiagnostics(err error, location common.Location, diagnostics *[]Diagnostic) {
+	if err, ok := err.(common.HasImportLocation); ok {
+		importLocation := err.ImportLocation()
+		if importLocation != nil {
+			location = importLocation
+		}
+	}
+
+	if parentError, ok := err.(errors.ParentError); ok {
+		for _, childError := range parentError.ChildErrors() {
+			collectErrorDiagnostics(childError, location, diagnostics)
+		}
+		return
+	}
+
+	// Errors which wrap the actual error, e.g. the errors returned by the runtime,
+	// are reported as the wrapped error
+
+	if _, ok := err.(ast.HasPosition); !ok {
+		if unwrapped := goErrors.Unwrap(err); unwrapped != nil {
+			collectErrorDiagnostics(unwrapped, location, diagnostics)
+			return
+		}
+	}
+
+	*diagnostics = append(*diagnostics, newErrorDiagnostic(err, location))
+}
+
+func newErrorDiagnostic(err error, location common.Location) Diagnostic {
+	diagnostic := Diagnostic{
+		Name:     typeName(err),
+		Severity: SeverityError,
+		Location: locationID(location),
+		Message:  err.Error(),
+	}
+
+	if code := errors.GetErrorCode(err); code != errors.ErrorCodeUnknown {
+		diagnostic.Code = code.String()
+		diagnostic.Name = code.Name()
+	}
+
+	if secondaryError, ok := err.(errors.SecondaryError); ok {
+		diagnostic.SecondaryMessage = secondaryError.SecondaryError()
+	}
+
+	if hasPosition, ok := err.(ast.HasPosition); ok {
+		errorRange := newRange(hasPosition)
+		diagnostic.Range = &errorRange
+	}
+
+	if errorNotes, ok := err.(errors.ErrorNotes); ok {
+		for _, errorNote := range errorNotes.ErrorNotes() {
+			hasPosition, ok := errorNote.(ast.HasPosition)
+			if !ok {
+				continue
+			}
+
+			diagnostic.SecondaryRanges = append(diagnostic.SecondaryRanges,
+				SecondaryRange{
+					Message: errorNote.Message(),
+					Range:   newRange(hasPosition),
+				},
+			)
+		}
+	}
+
+	diagnostic.SuggestedFixes = errorSuggestedFixes(err)
+
+	return diagnostic
+}
+
+// errorSuggestedFixes returns the suggested fixes for the given error, if any
+//
+func errorSuggestedFixes(err error) []SuggestedFix {
+	switch err := err.(type) {
+	case *sema.MissingMoveOperationError:
+		return []SuggestedFix{
+			{
+				Message: "Insert move operation",
+				TextEdits: []TextEdit{
+					{
+						Insertion: "<-",
+						Range: Range{
+							StartPos: newPosition(err.Pos),
+							EndPos:   newPosition(err.Pos),
+						},
+					},
+				},
+			},
+		}
+
+	case *sema.InvalidMoveOperationError:
+		startPos := err.StartPos
+
+		return []SuggestedFix{
+			{
+				Message: "Remove move operation",
+				TextEdits: []TextEdit{
+					{
+						Range: Range{
+							StartPos: newPosition(startPos),
+							EndPos:   newPosition(startPos.Shifted(len("<-") - 1)),
+						},
+					},
+				},
+			},
+		}
+
+	case *sema.IncorrectTransferOperationError:
+		replacement := err.ExpectedOperation.Operator()
+
+		return []SuggestedFix{
+			{
+				Message: "Replace with `" + replacement + "`",
+				TextEdits: []TextEdit{
+					{
+						Replacement: replacement,
+						Range:       newRange(err),
+					},
+				},
+			},
+		}
+	}
+
+	return nil
+}
+
+// FromHints returns the diagnostics for the given hints of the checker of the program at the given location.
+//
+func FromHints(hints []sema.Hint, location common.Location) []Diagnostic {
+	diagnostics := make([]Diagnostic, 0, len(hints))
+
+	for _, hint := range hints {
+		hintRange := newRange(hint)
+
+		diagnostic := Diagnostic{
+			Name:     typeName(hint),
+			Severity: SeverityHint,
+			Location: locationID(location),
+			Message:  hint.Hint(),
+			Range:    &hintRange,
+		}
+
+		switch hint := hint.(type) {
+		case *sema.ReplacementHint:
+			replacement := hint.Expression.String()
+
+			diagnostic.SuggestedFixes = []SuggestedFix{
+				{
+					Message: "Replace with `" + replacement + "`",
+					TextEdits: []TextEdit{
+						{
+							Replacement: replacement,
+							Range:       hintRange,
+						},
+					},
+				},
+			}
+
+		case *sema.RemovalHint:
+			diagnostic.SuggestedFixes = []SuggestedFix{
+				{
+					Message: "Remove unnecessary code",
+					TextEdits: []TextEdit{
+						{
+							Range: hintRange,
+						},
+					},
+				},
+			}
+		}
+
+		diagnostics = append(diagnostics, diagnostic)
+	}
+
+	return diagnostics
+}
+
+func locationID(location common.Location) common.LocationID {
+	if location == nil {
+		return ""
+	}
+	return location.ID()
+}
+
+// typeName returns the qualified name of the type of the given error or hint,
+// e.g. `sema.ReplacementHint`
+//
+func typeName(value interface{}) string {
+	ty := reflect.TypeOf(value)
+	if ty.Kind() == reflect.Ptr {
+		ty = ty.Elem()
+	}
+	return ty.String()
+}
@@ -0,0 +1,238 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diagnostics
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/parser2"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/tests/checker"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestFromError(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("parser error", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := parser2.ParseProgram(`let x = `)
+		require.Error(t, err)
+
+		diagnostics := FromError(err, utils.TestLocation)
+		require.Len(t, diagnostics, 1)
+
+		diagnostic := diagnostics[0]
+		assert.Equal(t, SeverityError, diagnostic.Severity)
+		assert.Equal(t, utils.TestLocation.ID(), diagnostic.Location)
+		assert.Equal(t, "parser2.SyntaxError", diagnostic.Name)
+		assert.NotEmpty(t, diagnostic.Code)
+		assert.NotEmpty(t, diagnostic.Message)
+		require.NotNil(t, diagnostic.Range)
+		assert.Equal(t, 1, diagnostic.Range.StartPos.Line)
+	})
+
+	t.Run("checker errors", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := checker.ParseAndCheck(t, `
+          fun test() {
+              let x: Int = true
+              let y = 1
+              let y = 2
+          }
+        `)
+		require.Error(t, err)
+
+		diagnostics := FromError(err, utils.TestLocation)
+		require.Len(t, diagnostics, 2)
+
+		typeMismatch := diagnostics[0]
+		assert.Equal(t, "E2007", typeMismatch.Code)
+		assert.Equal(t, "sema.TypeMismatchError", typeMismatch.Name)
+		assert.Equal(t, "mismatched types", typeMismatch.Message)
+		assert.Equal(t, "expected `Int`, got `Bool`", typeMismatch.SecondaryMessage)
+		assert.Equal(t,
+			&Range{
+				StartPos: Position{Offset: 51, Line: 3, Column: 27},
+				EndPos:   Position{Offset: 54, Line: 3, Column: 30},
+			},
+			typeMismatch.Range,
+		)
+
+		redeclaration := diagnostics[1]
+		assert.Equal(t, "sema.RedeclarationError", redeclaration.Name)
+		assert.Equal(t,
+			[]SecondaryRange{
+				{
+					Message: "previously declared here",
+					Range: Range{
+						StartPos: Position{Offset: 74, Line: 4, Column: 18},
+						EndPos:   Position{Offset: 74, Line: 4, Column: 18},
+					},
+				},
+			},
+			redeclaration.SecondaryRanges,
+		)
+	})
+
+	t.Run("suggested fixes", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := checker.ParseAndCheck(t, `
+          resource R {}
+
+          fun consume(_ r: @R) {
+              destroy r
+          }
+
+          fun test() {
+              let r = create R()
+              let r2 <- create R()
+              consume(r2)
+              let x <- 1
+          }
+        `)
+		require.Error(t, err)
+
+		diagnostics := FromError(err, utils.TestLocation)
+
+		var fixes []SuggestedFix
+		for _, diagnostic := range diagnostics {
+			fixes = append(fixes, diagnostic.SuggestedFixes...)
+		}
+
+		require.Len(t, fixes, 3)
+
+		assert.Equal(t, "Replace with `<-`", fixes[0].Message)
+		assert.Equal(t, "<-", fixes[0].TextEdits[0].Replacement)
+
+		assert.Equal(t, "Insert move operation", fixes[1].Message)
+		assert.Equal(t, "<-", fixes[1].TextEdits[0].Insertion)
+
+		assert.Equal(t, "Replace with `=`", fixes[2].Message)
+		assert.Equal(t, "=", fixes[2].TextEdits[0].Replacement)
+	})
+
+	t.Run("imported program error", func(t *testing.T) {
+
+		t.Parallel()
+
+		importedLocation := common.StringLocation("imported")
+
+		err := &sema.CheckerError{
+			Location: utils.TestLocation,
+			Errors: []error{
+				&sema.ImportedProgramError{
+					Location: importedLocation,
+					Err: &sema.CheckerError{
+						Location: importedLocation,
+						Errors: []error{
+							&sema.NotDeclaredError{
+								ExpectedKind: common.DeclarationKindVariable,
+								Name:         "x",
+								Pos:          ast.Position{Offset: 4, Line: 1, Column: 4},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		diagnostics := FromError(err, utils.TestLocation)
+		require.Len(t, diagnostics, 1)
+
+		assert.Equal(t, importedLocation.ID(), diagnostics[0].Location)
+		assert.Equal(t, "sema.NotDeclaredError", diagnostics[0].Name)
+	})
+}
+
+func TestFromHints(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := checker.ParseAndCheck(t, `
+      let x: Int = 1
+      let y = x!
+    `)
+	require.NoError(t, err)
+
+	diagnostics := FromHints(checker.Hints(), utils.TestLocation)
+	require.Len(t, diagnostics, 1)
+
+	diagnostic := diagnostics[0]
+	assert.Equal(t, SeverityHint, diagnostic.Severity)
+	assert.Equal(t, "sema.RemovalHint", diagnostic.Name)
+	assert.Empty(t, diagnostic.Code)
+	require.Len(t, diagnostic.SuggestedFixes, 1)
+	assert.Equal(t,
+		[]TextEdit{
+			{
+				Range: *diagnostic.Range,
+			},
+		},
+		diagnostic.SuggestedFixes[0].TextEdits,
+	)
+}
+
+func TestDiagnosticJSON(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostic := Diagnostic{
+		Code:     "E2001",
+		Name:     "sema.TypeMismatchError",
+		Severity: SeverityError,
+		Location: "S.test",
+		Message:  "mismatched types",
+		Range: &Range{
+			StartPos: Position{Offset: 1, Line: 1, Column: 1},
+			EndPos:   Position{Offset: 2, Line: 1, Column: 2},
+		},
+	}
+
+	encoded, err := json.Marshal(diagnostic)
+	require.NoError(t, err)
+
+	assert.JSONEq(t,
+		`{
+          "code": "E2001",
+          "name": "sema.TypeMismatchError",
+          "severity": "error",
+          "location": "S.test",
+          "message": "mismatched types",
+          "range": {
+            "startPos": {"offset": 1, "line": 1, "column": 1},
+            "endPos": {"offset": 2, "line": 1, "column": 2}
+          }
+        }`,
+		string(encoded),
+	)
+}
@@ -20,13 +20,17 @@ package runtime
 
 import (
 	"encoding/hex"
+	goErrors "errors"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
 	"github.com/onflow/cadence/runtime/sema"
 )
 
@@ -387,3 +391,112 @@ func TestRuntimeError(t *testing.T) {
 
 	})
 }
+
+func TestRuntimeErrorCodes(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := NewInterpreterRuntime()
+
+	executeScript := func(code string, runtimeInterface *testRuntimeInterface) error {
+		_, err := runtime.ExecuteScript(
+			Script{
+				Source: []byte(code),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.ScriptLocation{},
+			},
+		)
+		return err
+	}
+
+	requireErrorCode := func(t *testing.T, err error, expectedName string, expectedCategory errors.ErrorCategory) {
+		require.Error(t, err)
+
+		var codedErr errors.HasErrorCode
+		require.True(t, goErrors.As(err, &codedErr))
+
+		code := codedErr.ErrorCode()
+		assert.Equal(t, expectedName, code.Name())
+		assert.Equal(t, expectedCategory, code.Category())
+		assert.Equal(t, code, errors.GetErrorCode(err))
+	}
+
+	t.Run("parser error", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := executeScript(`X`, &testRuntimeInterface{})
+		requireErrorCode(t, err, "parser2.Error", errors.ErrorCategoryUser)
+	})
+
+	t.Run("checker error", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := executeScript(
+			`pub fun main() { let x: Int = "1" }`,
+			&testRuntimeInterface{},
+		)
+		requireErrorCode(t, err, "sema.CheckerError", errors.ErrorCategoryUser)
+
+		var checkerErr *sema.CheckerError
+		require.True(t, goErrors.As(err, &checkerErr))
+		require.Len(t, checkerErr.Errors, 1)
+
+		assert.Equal(t,
+			"sema.TypeMismatchError",
+			errors.GetErrorCode(checkerErr.Errors[0]).Name(),
+		)
+	})
+
+	t.Run("interpreter error", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := executeScript(
+			`pub fun main() { let x: Int? = nil; x! }`,
+			&testRuntimeInterface{},
+		)
+		requireErrorCode(t, err, "interpreter.ForceNilError", errors.ErrorCategoryUser)
+	})
+
+	t.Run("computation limit", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := executeScript(
+			`pub fun main() { while true {} }`,
+			&testRuntimeInterface{
+				computationLimit: 10,
+			},
+		)
+		requireErrorCode(t, err, "runtime.ComputationLimitExceededError", errors.ErrorCategoryResourceExhaustion)
+	})
+}
+
+func TestRuntimeErrorCodeRanges(t *testing.T) {
+
+	t.Parallel()
+
+	ranges := map[string]errors.ErrorCode{
+		"errors":      1,
+		"parser2":     1000,
+		"sema":        2000,
+		"interpreter": 3000,
+		"runtime":     4000,
+		"stdlib":      5000,
+	}
+
+	for _, code := range errors.RegisteredErrorCodes() {
+		name := code.Name()
+		packageName := name[:strings.Index(name, ".")]
+
+		start, ok := ranges[packageName]
+		require.True(t, ok, name)
+
+		assert.GreaterOrEqual(t, uint16(code), uint16(start), name)
+		assert.Less(t, uint16(code), uint16(start/1000*1000+1000), name)
+	}
+}
@@ -0,0 +1,79 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"github.com/onflow/cadence/runtime/errors"
+)
+
+// The error codes of the errors of this package.
+//
+// NOTE: Codes must never be changed or reused, new errors must be assigned new codes.
+//
+func init() {
+	errors.RegisterErrorCodes(
+		errors.ErrorCategoryUser,
+		map[errors.ErrorCode]error{
+			4002: InvalidTransactionCountError{},
+			4003: InvalidEntryPointParameterCountError{},
+			4004: InvalidTransactionAuthorizerCountError{},
+			4005: &InvalidEntryPointArgumentError{},
+			4006: &InvalidEntryPointArgumentsError{},
+			4007: &MalformedValueError{},
+			4008: &InvalidValueTypeError{},
+			4009: &InvalidScriptReturnTypeError{},
+			4010: &ScriptParameterTypeNotStorableError{},
+			4011: &ScriptParameterTypeNotImportableError{},
+			4012: &ArgumentNotImportableError{},
+			4013: &ParsingCheckingError{},
+			4014: &InvalidContractDeploymentError{},
+			4015: &ContractRemovalError{},
+			4016: &InvalidContractDeploymentOriginError{},
+			4017: &ContractNotDeployedError{},
+			4018: &ContractUpdateError{},
+			4019: &FieldMismatchError{},
+			4020: &TypeMismatchError{},
+			4021: &ExtraneousFieldError{},
+			4022: &ContractNotFoundError{},
+			4023: &InvalidDeclarationKindChangeError{},
+			4024: &ConformanceMismatchError{},
+			4025: &ConformanceCountMismatchError{},
+			4026: &EnumCaseMismatchError{},
+			4027: &MissingEnumCasesError{},
+			4028: &MissingCompositeDeclarationError{},
+			4030: UnsupportedImportLocationError{},
+			4031: ImportCycleError{},
+			4032: ImportParsingError{},
+		},
+	)
+
+	errors.RegisterErrorCodes(
+		errors.ErrorCategoryInternal,
+		map[errors.ErrorCode]error{
+			4029: NonStorableValueWriteError{},
+		},
+	)
+
+	errors.RegisterErrorCodes(
+		errors.ErrorCategoryResourceExhaustion,
+		map[errors.ErrorCode]error{
+			4001: ComputationLimitExceededError{},
+		},
+	)
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
 	"github.com/onflow/cadence/runtime/interpreter"
 	"github.com/onflow/cadence/runtime/pretty"
 	"github.com/onflow/cadence/runtime/sema"
@@ -50,6 +51,12 @@ func (e Error) Unwrap() error {
 	return e.Err
 }
 
+// ErrorCode returns the code of the error, see errors.GetErrorCode.
+//
+func (e Error) ErrorCode() errors.ErrorCode {
+	return errors.GetErrorCode(e.Err)
+}
+
 func (e Error) Error() string {
 	var sb strings.Builder
 	sb.WriteString("Execution failed:\n")
@@ -74,6 +81,12 @@ func (e ComputationLimitExceededError) Error() string {
 	)
 }
 
+// IsUnrecoverable marks the error as unrecoverable:
+// Exceeding the computation limit must always abort the program,
+// even inside a try expression
+//
+func (ComputationLimitExceededError) IsUnrecoverable() {}
+
 // InvalidTransactionCountError
 
 type InvalidTransactionCountError struct {
@@ -140,6 +153,22 @@ func (e *InvalidEntryPointArgumentError) Error() string {
 	)
 }
 
+// InvalidEntryPointArgumentsError is reported when validating arguments,
+// and contains the errors for all invalid arguments,
+// each an InvalidEntryPointArgumentError.
+//
+type InvalidEntryPointArgumentsError struct {
+	Errors []error
+}
+
+func (e *InvalidEntryPointArgumentsError) Error() string {
+	return fmt.Sprintf("%d invalid argument(s)", len(e.Errors))
+}
+
+func (e *InvalidEntryPointArgumentsError) ChildErrors() []error {
+	return e.Errors
+}
+
 // MalformedValueError
 
 type MalformedValueError struct {
@@ -301,6 +330,21 @@ func (*InvalidContractDeploymentOriginError) Error() string {
 	return "cannot deploy invalid contract"
 }
 
+// ContractNotDeployedError is reported when a contract is requested,
+// but no contract or contract interface is deployed at the location.
+//
+type ContractNotDeployedError struct {
+	Location common.AddressLocation
+}
+
+func (e *ContractNotDeployedError) Error() string {
+	return fmt.Sprintf(
+		"cannot find contract or contract interface `%s` in account %s",
+		e.Location.Name,
+		e.Location.Address.ShortHexWithPrefix(),
+	)
+}
+
 // Contract update related errors
 
 // ContractUpdateError is reported upon any invalid update to a contract or contract interface.
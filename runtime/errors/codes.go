@@ -0,0 +1,240 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package errors
+
+import (
+	goErrors "errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=ErrorCategory
+
+// ErrorCategory classifies errors by their cause.
+//
+type ErrorCategory uint8
+
+const (
+	ErrorCategoryUnknown ErrorCategory = iota
+	// ErrorCategoryUser is the category of errors caused by the user,
+	// e.g. by an invalid program, or invalid arguments
+	ErrorCategoryUser
+	// ErrorCategoryInternal is the category of errors caused by a bug in the implementation
+	ErrorCategoryInternal
+	// ErrorCategoryExternal is the category of errors caused by the host environment,
+	// e.g. by a failure of the runtime interface
+	ErrorCategoryExternal
+	// ErrorCategoryResourceExhaustion is the category of errors caused by exceeding a limit,
+	// e.g. the computation limit
+	ErrorCategoryResourceExhaustion
+)
+
+// ErrorCode is a stable, machine-readable code of an error.
+//
+// Codes are never reused or changed once assigned.
+// Each package is assigned a range of codes: general errors (package errors) use 1 to 999,
+// parser errors (package parser2) 1000 to 1999, checker errors (package sema) 2000 to 2999,
+// interpreter errors (package interpreter) 3000 to 3999, runtime errors (package runtime) 4000 to 4999,
+// and standard library errors (package stdlib) 5000 to 5999.
+//
+type ErrorCode uint16
+
+// ErrorCodeUnknown is the code of errors which have no registered code,
+// e.g. errors of the host environment.
+//
+const ErrorCodeUnknown ErrorCode = 0
+
+type errorCodeInfo struct {
+	name     string
+	category ErrorCategory
+}
+
+var errorCodesLock sync.RWMutex
+var errorCodeInfos = map[ErrorCode]errorCodeInfo{}
+var errorTypeCodes = map[reflect.Type]ErrorCode{}
+
+// errorType returns the type with which errors are registered.
+// Errors are used both as values and as pointers,
+// so the type of pointers is the element type.
+//
+func errorType(err error) reflect.Type {
+	ty := reflect.TypeOf(err)
+	if ty.Kind() == reflect.Ptr {
+		ty = ty.Elem()
+	}
+	return ty
+}
+
+// RegisterErrorCodes registers the given errors, with the given category,
+// under the given codes.
+//
+// The errors are only used to determine their type, so they may be empty.
+// Registering a code or an error type twice is a programming error and panics.
+//
+func RegisterErrorCodes(category ErrorCategory, errors map[ErrorCode]error) {
+	errorCodesLock.Lock()
+	defer errorCodesLock.Unlock()
+
+	for code, err := range errors {
+		if code == ErrorCodeUnknown {
+			panic(fmt.Errorf("cannot register error code %d", code))
+		}
+
+		if _, ok := errorCodeInfos[code]; ok {
+			panic(fmt.Errorf("error code %d is already registered", code))
+		}
+
+		ty := errorType(err)
+
+		if _, ok := errorTypeCodes[ty]; ok {
+			panic(fmt.Errorf("error type %s is already registered", ty))
+		}
+
+		errorCodeInfos[code] = errorCodeInfo{
+			name:     ty.String(),
+			category: category,
+		}
+		errorTypeCodes[ty] = code
+	}
+}
+
+// RegisteredErrorCodes returns all registered error codes, in increasing order.
+//
+func RegisteredErrorCodes() []ErrorCode {
+	errorCodesLock.RLock()
+	defer errorCodesLock.RUnlock()
+
+	codes := make([]ErrorCode, 0, len(errorCodeInfos))
+	for code := range errorCodeInfos {
+		codes = append(codes, code)
+	}
+
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i] < codes[j]
+	})
+
+	return codes
+}
+
+// Name returns the name of the error code, i.e. the qualified name of the error type,
+// e.g. `sema.TypeMismatchError`.
+//
+func (c ErrorCode) Name() string {
+	errorCodesLock.RLock()
+	defer errorCodesLock.RUnlock()
+
+	return errorCodeInfos[c].name
+}
+
+// Category returns the category of the errors with the code.
+//
+func (c ErrorCode) Category() ErrorCategory {
+	errorCodesLock.RLock()
+	defer errorCodesLock.RUnlock()
+
+	return errorCodeInfos[c].category
+}
+
+func (c ErrorCode) String() string {
+	return fmt.Sprintf("E%04d", uint16(c))
+}
+
+// HasErrorCode is implemented by errors which provide their error code.
+//
+// All errors which wrap errors with registered codes implement it,
+// so errors.As can be used to get the code of any error returned by the runtime.
+//
+type HasErrorCode interface {
+	error
+	ErrorCode() ErrorCode
+}
+
+// GetErrorCode returns the code of the given error.
+//
+// The code of an error which wraps other errors is the code of the innermost wrapped error
+// which has a registered code, as it is the most specific.
+// Child errors of parent errors, e.g. the errors reported by the checker, are not considered.
+//
+// If neither the error nor any wrapped error has a registered code, ErrorCodeUnknown is returned.
+//
+func GetErrorCode(err error) ErrorCode {
+	errorCodesLock.RLock()
+	defer errorCodesLock.RUnlock()
+
+	code := ErrorCodeUnknown
+
+	for err != nil {
+		if errorCode, ok := errorTypeCodes[errorType(err)]; ok {
+			code = errorCode
+		}
+		err = goErrors.Unwrap(err)
+	}
+
+	return code
+}
+
+// GetErrorCategory returns the category of the given error.
+// See GetErrorCode for how the code of an error is determined.
+//
+func GetErrorCategory(err error) ErrorCategory {
+	return GetErrorCode(err).Category()
+}
+
+// CodedError wraps an error and provides its error code.
+//
+type CodedError struct {
+	Err error
+}
+
+var _ HasErrorCode = CodedError{}
+
+// NewCodedError returns a CodedError wrapping the given error.
+//
+func NewCodedError(err error) CodedError {
+	return CodedError{
+		Err: err,
+	}
+}
+
+func (e CodedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e CodedError) Unwrap() error {
+	return e.Err
+}
+
+func (e CodedError) ErrorCode() ErrorCode {
+	return GetErrorCode(e.Err)
+}
+
+func (e CodedError) Category() ErrorCategory {
+	return e.ErrorCode().Category()
+}
+
+func init() {
+	RegisterErrorCodes(
+		ErrorCategoryInternal,
+		map[ErrorCode]error{
+			1: &UnreachableError{},
+		},
+	)
+}
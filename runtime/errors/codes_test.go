@@ -0,0 +1,112 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package errors
+
+import (
+	goErrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetErrorCode(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("registered", func(t *testing.T) {
+
+		t.Parallel()
+
+		code := GetErrorCode(NewUnreachableError())
+
+		assert.Equal(t, ErrorCode(1), code)
+		assert.Equal(t, "errors.UnreachableError", code.Name())
+		assert.Equal(t, ErrorCategoryInternal, code.Category())
+		assert.Equal(t, "E0001", code.String())
+	})
+
+	t.Run("value and pointer", func(t *testing.T) {
+
+		t.Parallel()
+
+		assert.Equal(t,
+			GetErrorCode(&UnreachableError{}),
+			GetErrorCode(UnreachableError{}),
+		)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+
+		t.Parallel()
+
+		code := GetErrorCode(goErrors.New("test"))
+
+		assert.Equal(t, ErrorCodeUnknown, code)
+		assert.Equal(t, ErrorCategoryUnknown, code.Category())
+	})
+
+	t.Run("wrapped", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := fmt.Errorf("wrapped: %w", NewUnreachableError())
+
+		assert.Equal(t, ErrorCode(1), GetErrorCode(err))
+		assert.Equal(t, ErrorCategoryInternal, GetErrorCategory(err))
+	})
+
+	t.Run("coded error", func(t *testing.T) {
+
+		t.Parallel()
+
+		err := fmt.Errorf("wrapped: %w", NewCodedError(NewUnreachableError()))
+
+		var codedErr HasErrorCode
+		require.True(t, goErrors.As(err, &codedErr))
+
+		assert.Equal(t, ErrorCode(1), codedErr.ErrorCode())
+	})
+}
+
+func TestRegisterErrorCodes(t *testing.T) {
+
+	t.Parallel()
+
+	assert.Contains(t, RegisteredErrorCodes(), ErrorCode(1))
+
+	assert.Panics(t, func() {
+		RegisterErrorCodes(
+			ErrorCategoryUser,
+			map[ErrorCode]error{
+				1: goErrors.New("test"),
+			},
+		)
+	})
+
+	assert.Panics(t, func() {
+		RegisterErrorCodes(
+			ErrorCategoryUser,
+			map[ErrorCode]error{
+				999: &UnreachableError{},
+			},
+		)
+	})
+}
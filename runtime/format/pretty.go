@@ -0,0 +1,278 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package format
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultPrettyIndent is the default indentation of nested values
+//
+const DefaultPrettyIndent = "    "
+
+const prettyRedacted = "<redacted>"
+
+// PrettyOptions are the options for pretty-printing values
+//
+type PrettyOptions struct {
+	// MaxDepth is the maximum nesting depth of printed containers.
+	// Containers nested deeper are elided.
+	// A depth of 0 means there is no limit
+	MaxDepth int
+	// MaxElements is the maximum number of printed elements of arrays and dictionaries.
+	// Further elements are elided.
+	// A maximum of 0 means there is no limit
+	MaxElements int
+	// Indent is the indentation of nested values.
+	// If empty, DefaultPrettyIndent is used
+	Indent string
+	// Redact reports if the field with the given name of the composite with the given type ID
+	// is redacted, i.e. if its value is not printed
+	Redact func(typeID string, fieldName string) bool
+}
+
+// RedactFields returns a redaction function for PrettyOptions
+// which redacts the fields with the given names of all composites
+//
+func RedactFields(fieldNames ...string) func(typeID string, fieldName string) bool {
+	redacted := make(map[string]struct{}, len(fieldNames))
+	for _, fieldName := range fieldNames {
+		redacted[fieldName] = struct{}{}
+	}
+
+	return func(_ string, fieldName string) bool {
+		_, ok := redacted[fieldName]
+		return ok
+	}
+}
+
+// PrettyNode is a value which can be pretty-printed.
+//
+// The elements of containers are only produced when they are printed,
+// so values which exceed the limits are not traversed
+//
+type PrettyNode interface {
+	isPrettyNode()
+}
+
+// PrettyText is a value which is printed as-is
+//
+type PrettyText string
+
+func (PrettyText) isPrettyNode() {}
+
+// PrettyArray is an array value
+//
+type PrettyArray struct {
+	Count   int
+	Element func(index int) PrettyNode
+}
+
+func (PrettyArray) isPrettyNode() {}
+
+// PrettyDictionary is a dictionary value
+//
+type PrettyDictionary struct {
+	Count int
+	Entry func(index int) (key PrettyNode, value PrettyNode)
+}
+
+func (PrettyDictionary) isPrettyNode() {}
+
+// PrettyComposite is a composite value
+//
+type PrettyComposite struct {
+	TypeID     string
+	FieldNames []string
+	Field      func(name string) PrettyNode
+}
+
+func (PrettyComposite) isPrettyNode() {}
+
+// Pretty returns the multi-line representation of the given value.
+//
+// Containers are printed with one element or field per line, indented by their nesting depth.
+// Elided containers are printed with an ellipsis, elided elements with their count,
+// and redacted fields as `<redacted>`
+//
+func Pretty(node PrettyNode, options PrettyOptions) string {
+	if options.Indent == "" {
+		options.Indent = DefaultPrettyIndent
+	}
+
+	printer := prettyPrinter{
+		options: options,
+	}
+	printer.print(node, 0)
+	return printer.builder.String()
+}
+
+type prettyPrinter struct {
+	options PrettyOptions
+	builder strings.Builder
+}
+
+func (p *prettyPrinter) print(node PrettyNode, depth int) {
+	switch node := node.(type) {
+	case PrettyText:
+		p.builder.WriteString(string(node))
+
+	case PrettyArray:
+		p.printArray(node, depth)
+
+	case PrettyDictionary:
+		p.printDictionary(node, depth)
+
+	case PrettyComposite:
+		p.printComposite(node, depth)
+
+	default:
+		panic(fmt.Sprintf("unsupported pretty node: %T", node))
+	}
+}
+
+func (p *prettyPrinter) elided(depth int) bool {
+	return p.options.MaxDepth > 0 && depth >= p.options.MaxDepth
+}
+
+// printedCount returns the number of printed elements of a container with the given number of elements
+//
+func (p *prettyPrinter) printedCount(count int) int {
+	if p.options.MaxElements > 0 && count > p.options.MaxElements {
+		return p.options.MaxElements
+	}
+	return count
+}
+
+func (p *prettyPrinter) writeIndent(depth int) {
+	for i := 0; i < depth; i++ {
+		p.builder.WriteString(p.options.Indent)
+	}
+}
+
+// writeSeparator writes the separator after the element with the given index,
+// of the given number of printed lines
+//
+func (p *prettyPrinter) writeSeparator(index, lines int) {
+	if index < lines-1 {
+		p.builder.WriteRune(',')
+	}
+	p.builder.WriteRune('\n')
+}
+
+func (p *prettyPrinter) writeRemaining(remaining int, depth int) {
+	if remaining <= 0 {
+		return
+	}
+	p.writeIndent(depth)
+	fmt.Fprintf(&p.builder, "... (%d more)\n", remaining)
+}
+
+func (p *prettyPrinter) printArray(array PrettyArray, depth int) {
+	if array.Count == 0 {
+		p.builder.WriteString("[]")
+		return
+	}
+
+	if p.elided(depth) {
+		p.builder.WriteString("[...]")
+		return
+	}
+
+	printed := p.printedCount(array.Count)
+	remaining := array.Count - printed
+	lines := printed
+	if remaining > 0 {
+		lines++
+	}
+
+	p.builder.WriteString("[\n")
+	for i := 0; i < printed; i++ {
+		p.writeIndent(depth + 1)
+		p.print(array.Element(i), depth+1)
+		p.writeSeparator(i, lines)
+	}
+	p.writeRemaining(remaining, depth+1)
+	p.writeIndent(depth)
+	p.builder.WriteRune(']')
+}
+
+func (p *prettyPrinter) printDictionary(dictionary PrettyDictionary, depth int) {
+	if dictionary.Count == 0 {
+		p.builder.WriteString("{}")
+		return
+	}
+
+	if p.elided(depth) {
+		p.builder.WriteString("{...}")
+		return
+	}
+
+	printed := p.printedCount(dictionary.Count)
+	remaining := dictionary.Count - printed
+	lines := printed
+	if remaining > 0 {
+		lines++
+	}
+
+	p.builder.WriteString("{\n")
+	for i := 0; i < printed; i++ {
+		key, value := dictionary.Entry(i)
+		p.writeIndent(depth + 1)
+		p.print(key, depth+1)
+		p.builder.WriteString(": ")
+		p.print(value, depth+1)
+		p.writeSeparator(i, lines)
+	}
+	p.writeRemaining(remaining, depth+1)
+	p.writeIndent(depth)
+	p.builder.WriteRune('}')
+}
+
+func (p *prettyPrinter) printComposite(composite PrettyComposite, depth int) {
+	p.builder.WriteString(composite.TypeID)
+
+	if len(composite.FieldNames) == 0 {
+		p.builder.WriteString("()")
+		return
+	}
+
+	if p.elided(depth) {
+		p.builder.WriteString("(...)")
+		return
+	}
+
+	lines := len(composite.FieldNames)
+
+	p.builder.WriteString("(\n")
+	for i, fieldName := range composite.FieldNames {
+		p.writeIndent(depth + 1)
+		p.builder.WriteString(fieldName)
+		p.builder.WriteString(": ")
+		if p.options.Redact != nil && p.options.Redact(composite.TypeID, fieldName) {
+			p.builder.WriteString(prettyRedacted)
+		} else {
+			p.print(composite.Field(fieldName), depth+1)
+		}
+		p.writeSeparator(i, lines)
+	}
+	p.writeIndent(depth)
+	p.builder.WriteRune(')')
+}
@@ -0,0 +1,208 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package format
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func prettyTestArray(elements ...PrettyNode) PrettyArray {
+	return PrettyArray{
+		Count: len(elements),
+		Element: func(index int) PrettyNode {
+			return elements[index]
+		},
+	}
+}
+
+func prettyTestComposite(typeID string, fields ...struct {
+	Name  string
+	Value PrettyNode
+}) PrettyComposite {
+	fieldNames := make([]string, len(fields))
+	fieldValues := map[string]PrettyNode{}
+	for i, field := range fields {
+		fieldNames[i] = field.Name
+		fieldValues[field.Name] = field.Value
+	}
+	return PrettyComposite{
+		TypeID:     typeID,
+		FieldNames: fieldNames,
+		Field: func(name string) PrettyNode {
+			return fieldValues[name]
+		},
+	}
+}
+
+func TestPretty(t *testing.T) {
+
+	t.Parallel()
+
+	value := prettyTestComposite(
+		"S.test.R",
+		struct {
+			Name  string
+			Value PrettyNode
+		}{
+			Name:  "id",
+			Value: PrettyText("1"),
+		},
+		struct {
+			Name  string
+			Value PrettyNode
+		}{
+			Name: "items",
+			Value: prettyTestArray(
+				PrettyText("1"),
+				prettyTestArray(PrettyText("2")),
+				PrettyText("3"),
+			),
+		},
+		struct {
+			Name  string
+			Value PrettyNode
+		}{
+			Name: "metadata",
+			Value: PrettyDictionary{
+				Count: 1,
+				Entry: func(_ int) (PrettyNode, PrettyNode) {
+					return PrettyText(`"a"`), prettyTestArray()
+				},
+			},
+		},
+		struct {
+			Name  string
+			Value PrettyNode
+		}{
+			Name:  "empty",
+			Value: prettyTestComposite("S.test.E"),
+		},
+	)
+
+	t.Run("no limits", func(t *testing.T) {
+
+		t.Parallel()
+
+		assert.Equal(t,
+			`S.test.R(
+    id: 1,
+    items: [
+        1,
+        [
+            2
+        ],
+        3
+    ],
+    metadata: {
+        "a": []
+    },
+    empty: S.test.E()
+)`,
+			Pretty(value, PrettyOptions{}),
+		)
+	})
+
+	t.Run("max depth", func(t *testing.T) {
+
+		t.Parallel()
+
+		assert.Equal(t,
+			`S.test.R(
+    id: 1,
+    items: [
+        1,
+        [...],
+        3
+    ],
+    metadata: {
+        "a": []
+    },
+    empty: S.test.E()
+)`,
+			Pretty(value, PrettyOptions{MaxDepth: 2}),
+		)
+	})
+
+	t.Run("max elements", func(t *testing.T) {
+
+		t.Parallel()
+
+		assert.Equal(t,
+			`S.test.R(
+  id: 1,
+  items: [
+    1,
+    ... (2 more)
+  ],
+  metadata: {
+    "a": []
+  },
+  empty: S.test.E()
+)`,
+			Pretty(value, PrettyOptions{MaxElements: 1, Indent: "  "}),
+		)
+	})
+
+	t.Run("redaction", func(t *testing.T) {
+
+		t.Parallel()
+
+		assert.Equal(t,
+			`S.test.R(
+    id: 1,
+    items: <redacted>,
+    metadata: {...},
+    empty: S.test.E()
+)`,
+			Pretty(value, PrettyOptions{
+				MaxDepth: 1,
+				Redact:   RedactFields("items"),
+			}),
+		)
+	})
+}
+
+func TestPrettyElidedNotProduced(t *testing.T) {
+
+	t.Parallel()
+
+	produced := 0
+
+	value := PrettyArray{
+		Count: 1000,
+		Element: func(index int) PrettyNode {
+			produced++
+			return PrettyArray{
+				Count: 1,
+				Element: func(_ int) PrettyNode {
+					produced++
+					return PrettyText("x")
+				},
+			}
+		},
+	}
+
+	assert.Equal(t,
+		"[\n    [...],\n    [...],\n    ... (998 more)\n]",
+		Pretty(value, PrettyOptions{MaxDepth: 1, MaxElements: 2}),
+	)
+	assert.Equal(t, 2, produced)
+}
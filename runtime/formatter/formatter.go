@@ -0,0 +1,197 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package formatter implements the canonical formatting of Cadence programs.
+//
+// The formatter only changes the whitespace between the tokens of a program:
+// it re-indents lines, normalizes the spacing between tokens on the same line,
+// removes trailing whitespace, and collapses consecutive blank lines.
+// It never adds or removes line breaks between tokens, as they are significant
+// in some places of the grammar, and it keeps comments as they are.
+//
+package formatter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/parser2"
+	"github.com/onflow/cadence/runtime/parser2/lexer"
+)
+
+// Indentation is the string used for one level of indentation
+//
+const Indentation = "    "
+
+// ErrTokensChanged is returned if formatting would change the tokens of the program.
+// It indicates a bug in the formatter.
+//
+var ErrTokensChanged = errors.New("formatting changed the tokens of the program")
+
+// Format returns the canonical formatting of the given program.
+//
+// The program must be syntactically valid, otherwise the parsing error is returned.
+//
+func Format(code string) (string, error) {
+	_, err := parser2.ParseProgram(code)
+	if err != nil {
+		return "", err
+	}
+
+	items, err := lex(code)
+	if err != nil {
+		return "", err
+	}
+
+	formatted := newFormatter(items).format()
+
+	// Ensure formatting did not change the program
+
+	formattedItems, err := lex(formatted)
+	if err != nil {
+		return "", err
+	}
+
+	if !sameTokens(items, formattedItems) {
+		return "", ErrTokensChanged
+	}
+
+	return formatted, nil
+}
+
+// IsFormatted returns true if the given program is formatted canonically.
+//
+func IsFormatted(code string) (bool, error) {
+	formatted, err := Format(code)
+	if err != nil {
+		return false, err
+	}
+
+	return formatted == code, nil
+}
+
+// item is a token of the program, with the whitespace preceding it.
+// Block comments, including nested block comments, are a single item
+//
+type item struct {
+	tokenType   lexer.TokenType
+	text        string
+	newlines    int
+	spaceBefore bool
+}
+
+func lex(code string) ([]item, error) {
+	ctx, cancelLexer := context.WithCancel(context.Background())
+	defer cancelLexer()
+
+	tokens := lexer.Lex(ctx, code)
+
+	var items []item
+
+	newlines := 0
+	spaceBefore := false
+
+	blockCommentNesting := 0
+	blockCommentStartOffset := 0
+
+	for token := range tokens {
+		switch token.Type {
+		case lexer.TokenEOF:
+			return items, nil
+
+		case lexer.TokenError:
+			err, ok := token.Value.(error)
+			if !ok {
+				err = fmt.Errorf("%v", token.Value)
+			}
+			return nil, err
+
+		case lexer.TokenSpace:
+			if blockCommentNesting == 0 {
+				space := token.Value.(lexer.Space)
+				newlines += strings.Count(space.String, "\n")
+				spaceBefore = true
+			}
+			continue
+
+		case lexer.TokenBlockCommentStart:
+			if blockCommentNesting == 0 {
+				blockCommentStartOffset = token.StartPos.Offset
+			}
+			blockCommentNesting++
+			continue
+
+		case lexer.TokenBlockCommentContent:
+			continue
+
+		case lexer.TokenBlockCommentEnd:
+			blockCommentNesting--
+			if blockCommentNesting > 0 {
+				continue
+			}
+
+			items = append(items, item{
+				tokenType:   lexer.TokenBlockCommentStart,
+				text:        code[blockCommentStartOffset : token.EndPos.Offset+1],
+				newlines:    newlines,
+				spaceBefore: spaceBefore,
+			})
+
+		default:
+			text := code[token.StartPos.Offset : token.EndPos.Offset+1]
+			if token.Type == lexer.TokenLineComment {
+				text = strings.TrimRight(text, " \t\r")
+			}
+
+			items = append(items, item{
+				tokenType:   token.Type,
+				text:        text,
+				newlines:    newlines,
+				spaceBefore: spaceBefore,
+			})
+		}
+
+		newlines = 0
+		spaceBefore = false
+	}
+
+	return items, nil
+}
+
+// sameTokens returns true if the given items have the same tokens,
+// and the same tokens are preceded by line breaks
+//
+func sameTokens(items, otherItems []item) bool {
+	if len(items) != len(otherItems) {
+		return false
+	}
+
+	for i, item := range items {
+		otherItem := otherItems[i]
+		if item.tokenType != otherItem.tokenType ||
+			item.text != otherItem.text ||
+			(i > 0 && (item.newlines > 0) != (otherItem.newlines > 0)) {
+
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,272 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package formatter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		code     string
+		expected string
+	}
+
+	testCases := []testCase{
+		{
+			name:     "empty",
+			code:     "\n\n",
+			expected: "",
+		},
+		{
+			name:     "declaration spacing",
+			code:     "pub  fun test( a : Int ,b:[String] ) : Int? { return a }",
+			expected: "pub fun test(a: Int, b: [String]): Int? { return a }\n",
+		},
+		{
+			name: "indentation",
+			code: `
+  pub resource R {
+ pub var x: Int
+          init(x: Int) {
+  self.x = x
+}
+  }
+`,
+			expected: `pub resource R {
+    pub var x: Int
+    init(x: Int) {
+        self.x = x
+    }
+}
+`,
+		},
+		{
+			name: "blank lines",
+			code: `let x = 1
+
+
+
+let y = 2
+`,
+			expected: `let x = 1
+
+let y = 2
+`,
+		},
+		{
+			name:     "trailing whitespace",
+			code:     "let x = 1   \n// comment   \nlet y = 2\t\n",
+			expected: "let x = 1\n// comment\nlet y = 2\n",
+		},
+		{
+			name: "operators",
+			code: `fun test(a: Int, o: Int?): Int {
+    let b = - a
+    let c = !  true&&false
+    let d = o!+a*2
+    let e = a>0 ? a : -a
+    let f = o ?? 1
+    return b
+}`,
+			expected: `fun test(a: Int, o: Int?): Int {
+    let b = -a
+    let c = !true && false
+    let d = o! + a * 2
+    let e = a>0 ? a : -a
+    let f = o ?? 1
+    return b
+}
+`,
+		},
+		{
+			name: "resources",
+			code: `fun test(): @R {
+    let r <-  create R()
+    let s <-!   r
+    return <- r
+}`,
+			expected: `fun test(): @R {
+    let r <- create R()
+    let s <-! r
+    return <-r
+}
+`,
+		},
+		{
+			name: "type arguments and paths",
+			code: `fun test() {
+    let r = self.account.borrow<&R>( from : /storage/r )
+    let p: PublicPath = /public/r
+}`,
+			expected: `fun test() {
+    let r = self.account.borrow<&R>(from: /storage/r)
+    let p: PublicPath = /public/r
+}
+`,
+		},
+		{
+			name: "switch",
+			code: `fun test(x: Int) {
+switch x {
+case 1:
+log("one")
+default:
+log("other")
+}
+}`,
+			expected: `fun test(x: Int) {
+    switch x {
+        case 1:
+            log("one")
+        default:
+            log("other")
+    }
+}
+`,
+		},
+		{
+			name: "continuation",
+			code: `fun test(): String {
+    let x =
+    1 +
+    2
+    return x
+    .toString()
+}`,
+			expected: `fun test(): String {
+    let x =
+        1 +
+        2
+    return x
+        .toString()
+}
+`,
+		},
+		{
+			name: "multi-line arguments",
+			code: `fun test() {
+    foo(
+    1,
+    bar: [
+    2
+    ]
+    )
+}`,
+			expected: `fun test() {
+    foo(
+        1,
+        bar: [
+            2
+        ]
+    )
+}
+`,
+		},
+		{
+			name: "conditions",
+			code: `fun test(x: Int) {
+    pre {
+        x > 0:
+        "x must be positive"
+    }
+}`,
+			expected: `fun test(x: Int) {
+    pre {
+        x > 0:
+            "x must be positive"
+    }
+}
+`,
+		},
+		{
+			name: "comments",
+			code: `// leading comment
+fun test() {  /* block /* nested */ comment */
+        // line comment
+    let x = 1// trailing comment
+      /*
+   multi-line
+      */
+}`,
+			expected: `// leading comment
+fun test() { /* block /* nested */ comment */
+    // line comment
+    let x = 1 // trailing comment
+    /*
+   multi-line
+      */
+}
+`,
+		},
+		{
+			name:     "dictionary and function expression",
+			code:     "let f = fun(x: Int): {String: Int} { return {\"a\":x} }",
+			expected: "let f = fun (x: Int): {String: Int} { return {\"a\": x} }\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+
+			t.Parallel()
+
+			formatted, err := Format(testCase.code)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, formatted)
+
+			// Formatting is idempotent
+
+			reformatted, err := Format(formatted)
+			require.NoError(t, err)
+			assert.Equal(t, formatted, reformatted)
+		})
+	}
+}
+
+func TestFormatInvalid(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := Format("fun test( {")
+	require.Error(t, err)
+}
+
+func TestIsFormatted(t *testing.T) {
+
+	t.Parallel()
+
+	formatted, err := IsFormatted("let x = 1\n")
+	require.NoError(t, err)
+	assert.True(t, formatted)
+
+	formatted, err = IsFormatted("let x  = 1")
+	require.NoError(t, err)
+	assert.False(t, formatted)
+}
@@ -0,0 +1,484 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package formatter
+
+import (
+	"strings"
+
+	"github.com/onflow/cadence/runtime/parser2/lexer"
+)
+
+// expressionKeywords are the keywords which may be followed by an expression,
+// so a following operator is a prefix operator, and a following parenthesis
+// or bracket is not an invocation or indexing
+//
+var expressionKeywords = map[string]struct{}{
+	"if":     {},
+	"else":   {},
+	"while":  {},
+	"for":    {},
+	"in":     {},
+	"return": {},
+	"case":   {},
+	"switch": {},
+	"as":     {},
+	"from":   {},
+	"fun":    {},
+}
+
+// continuationTokens are the tokens which continue the expression of the preceding line
+// when they end a line or start a line
+//
+var continuationTokens = map[lexer.TokenType]struct{}{
+	lexer.TokenEqual:                  {},
+	lexer.TokenLeftArrow:              {},
+	lexer.TokenLeftArrowExclamation:   {},
+	lexer.TokenSwap:                   {},
+	lexer.TokenAmpersandAmpersand:     {},
+	lexer.TokenVerticalBarVerticalBar: {},
+	lexer.TokenDoubleQuestionMark:     {},
+	lexer.TokenEqualEqual:             {},
+	lexer.TokenNotEqual:               {},
+	lexer.TokenLessEqual:              {},
+	lexer.TokenGreaterEqual:           {},
+	lexer.TokenPlus:                   {},
+	lexer.TokenMinus:                  {},
+	lexer.TokenStar:                   {},
+	lexer.TokenPercent:                {},
+}
+
+// itemKind is the role of an item, as determined from its context
+//
+type itemKind uint8
+
+const (
+	itemKindDefault itemKind = iota
+	// itemKindPrefix is a prefix operator, e.g. `-x`, `!x`, `&x`, `<-x`
+	itemKindPrefix
+	// itemKindPostfix is a postfix operator, e.g. the force-unwrap `x!`
+	// or the optional type `T?`
+	itemKindPostfix
+	// itemKindTernary is the `?` or `:` of a conditional expression
+	itemKindTernary
+)
+
+// scope is an open parenthesis, bracket, or brace
+//
+type scope struct {
+	// indented is true if the lines in the scope are indented
+	indented bool
+	// isSwitch is true if the scope is the block of a switch statement
+	isSwitch bool
+	// inCase is true if a case of the switch statement started
+	inCase bool
+	// ternaries is the number of conditional expressions in the scope
+	// which are still missing the `:`
+	ternaries int
+}
+
+type formatter struct {
+	items  []item
+	kinds  []itemKind
+	scopes []*scope
+	// topLevelScope is the scope of the program, which is never left
+	topLevelScope *scope
+	builder       strings.Builder
+	// switchScopeDepth is the scope depth of the `switch` keyword
+	// which has not been followed by its block yet, or -1
+	switchScopeDepth int
+}
+
+func newFormatter(items []item) *formatter {
+	return &formatter{
+		items:            items,
+		kinds:            make([]itemKind, len(items)),
+		topLevelScope:    &scope{},
+		switchScopeDepth: -1,
+	}
+}
+
+func (f *formatter) format() string {
+	var previous *item
+
+	for i := range f.items {
+		current := &f.items[i]
+
+		f.classify(i, previous)
+
+		// Leave the scope before indenting, so a closing token is indented like its opening line
+
+		switch current.tokenType {
+		case lexer.TokenParenClose,
+			lexer.TokenBracketClose,
+			lexer.TokenBraceClose:
+
+			if len(f.scopes) > 0 {
+				f.scopes = f.scopes[:len(f.scopes)-1]
+			}
+		}
+
+		if previous == nil {
+			f.builder.WriteString(f.indentation(i))
+		} else if current.newlines > 0 {
+			f.builder.WriteString(f.lineBreaks(current))
+			f.builder.WriteString(f.indentation(i))
+		} else if f.needsSpace(i, previous, current) {
+			f.builder.WriteByte(' ')
+		}
+
+		f.builder.WriteString(current.text)
+
+		f.enter(i, current)
+
+		previous = current
+	}
+
+	if previous != nil {
+		f.builder.WriteByte('\n')
+	}
+
+	return f.builder.String()
+}
+
+// classify determines the kind of the item at the given index from its context
+//
+func (f *formatter) classify(index int, previous *item) {
+	current := &f.items[index]
+
+	afterOperand := previous != nil &&
+		current.newlines == 0 &&
+		f.isOperandEnd(index-1)
+
+	kind := itemKindDefault
+
+	switch current.tokenType {
+	case lexer.TokenMinus,
+		lexer.TokenPlus,
+		lexer.TokenAmpersand,
+		lexer.TokenLeftArrow:
+
+		if !afterOperand {
+			kind = itemKindPrefix
+		}
+
+	case lexer.TokenExclamationMark:
+		if afterOperand && !current.spaceBefore {
+			kind = itemKindPostfix
+		} else {
+			kind = itemKindPrefix
+		}
+
+	case lexer.TokenQuestionMark:
+		if current.spaceBefore {
+			kind = itemKindTernary
+			if scope := f.currentScope(); scope != nil {
+				scope.ternaries++
+			}
+		} else {
+			kind = itemKindPostfix
+		}
+
+	case lexer.TokenColon:
+		if scope := f.currentScope(); scope != nil && scope.ternaries > 0 {
+			scope.ternaries--
+			kind = itemKindTernary
+		}
+
+	case lexer.TokenIdentifier:
+		// Keep track of switch statements, so their cases can be indented
+
+		if current.text == "switch" && !f.isMemberAccess(index) {
+			f.switchScopeDepth = len(f.scopes)
+		}
+	}
+
+	f.kinds[index] = kind
+}
+
+// currentScope returns the innermost scope
+//
+func (f *formatter) currentScope() *scope {
+	if len(f.scopes) == 0 {
+		return f.topLevelScope
+	}
+	return f.scopes[len(f.scopes)-1]
+}
+
+// isMemberAccess returns true if the identifier at the given index is accessed as a member
+//
+func (f *formatter) isMemberAccess(index int) bool {
+	if index == 0 {
+		return false
+	}
+
+	switch f.items[index-1].tokenType {
+	case lexer.TokenDot, lexer.TokenQuestionMarkDot:
+		return true
+	}
+
+	return false
+}
+
+// isOperandEnd returns true if the item at the given index ends an operand,
+// i.e. a following operator is an infix or postfix operator
+//
+func (f *formatter) isOperandEnd(index int) bool {
+	item := f.items[index]
+
+	switch item.tokenType {
+	case lexer.TokenIdentifier:
+		if f.isMemberAccess(index) {
+			return true
+		}
+		_, isKeyword := expressionKeywords[item.text]
+		return !isKeyword
+
+	case lexer.TokenBinaryIntegerLiteral,
+		lexer.TokenOctalIntegerLiteral,
+		lexer.TokenDecimalIntegerLiteral,
+		lexer.TokenHexadecimalIntegerLiteral,
+		lexer.TokenUnknownBaseIntegerLiteral,
+		lexer.TokenFixedPointNumberLiteral,
+		lexer.TokenString,
+		lexer.TokenParenClose,
+		lexer.TokenBracketClose:
+
+		return true
+
+	case lexer.TokenExclamationMark,
+		lexer.TokenQuestionMark:
+
+		return f.kinds[index] == itemKindPostfix
+	}
+
+	return false
+}
+
+// enter opens a new scope if the given item is an opening token
+//
+func (f *formatter) enter(index int, current *item) {
+	switch current.tokenType {
+	case lexer.TokenParenOpen, lexer.TokenBracketOpen:
+		// Parentheses and brackets only indent their contents
+		// if the contents start on a new line
+
+		indented := index+1 < len(f.items) &&
+			f.items[index+1].newlines > 0
+
+		f.scopes = append(f.scopes, &scope{
+			indented: indented,
+		})
+
+	case lexer.TokenBraceOpen:
+		isSwitch := f.switchScopeDepth == len(f.scopes)
+		if isSwitch {
+			f.switchScopeDepth = -1
+		}
+
+		f.scopes = append(f.scopes, &scope{
+			indented: true,
+			isSwitch: isSwitch,
+		})
+
+	case lexer.TokenIdentifier:
+		if current.newlines > 0 || index == 0 {
+			if scope := f.currentScope(); scope != nil &&
+				scope.isSwitch &&
+				isCaseKeyword(current.text) {
+
+				scope.inCase = true
+			}
+		}
+	}
+}
+
+func isCaseKeyword(text string) bool {
+	return text == "case" || text == "default"
+}
+
+// lineBreaks returns the line breaks before the given item,
+// which starts a new line. At most one blank line is kept
+//
+func (f *formatter) lineBreaks(current *item) string {
+	if current.newlines > 1 {
+		return "\n\n"
+	}
+	return "\n"
+}
+
+// indentation returns the indentation of the line which starts with the item at the given index
+//
+func (f *formatter) indentation(index int) string {
+	current := &f.items[index]
+
+	level := 0
+
+	for i, scope := range f.scopes {
+		if scope.indented {
+			level++
+		}
+
+		// The contents of cases are indented one more level than the cases
+
+		if scope.isSwitch && scope.inCase {
+			isLast := i == len(f.scopes)-1
+			startsCase := current.tokenType == lexer.TokenIdentifier &&
+				isCaseKeyword(current.text)
+
+			if !isLast || !startsCase {
+				level++
+			}
+		}
+	}
+
+	if f.isContinuation(index) {
+		level++
+	}
+
+	return strings.Repeat(Indentation, level)
+}
+
+// isContinuation returns true if the line which starts with the item at the given index
+// continues the expression of the preceding line
+//
+func (f *formatter) isContinuation(index int) bool {
+	current := f.items[index]
+
+	switch current.tokenType {
+	case lexer.TokenDot,
+		lexer.TokenQuestionMarkDot,
+		lexer.TokenAmpersandAmpersand,
+		lexer.TokenVerticalBarVerticalBar,
+		lexer.TokenDoubleQuestionMark:
+
+		return true
+
+	case lexer.TokenParenClose,
+		lexer.TokenBracketClose,
+		lexer.TokenBraceClose:
+
+		return false
+	}
+
+	if index == 0 {
+		return false
+	}
+
+	previous := f.items[index-1]
+
+	switch previous.tokenType {
+	case lexer.TokenQuestionMark:
+		return f.kinds[index-1] == itemKindTernary
+
+	case lexer.TokenColon:
+		// The colon of a case does not continue the line,
+		// but the colon of e.g. a condition, a dictionary entry,
+		// or a conditional expression does
+		scope := f.currentScope()
+		return scope == nil || !scope.isSwitch || f.kinds[index-1] == itemKindTernary
+	}
+
+	_, ok := continuationTokens[previous.tokenType]
+	return ok
+}
+
+// needsSpace returns true if the given items, which are on the same line,
+// must be separated by a space
+//
+func (f *formatter) needsSpace(index int, previous, current *item) bool {
+	previousKind := f.kinds[index-1]
+	currentKind := f.kinds[index]
+
+	// Keep the spacing around block comments,
+	// and separate line comments from the code they follow
+
+	switch {
+	case previous.tokenType == lexer.TokenBlockCommentStart,
+		current.tokenType == lexer.TokenBlockCommentStart:
+
+		return current.spaceBefore
+
+	case current.tokenType == lexer.TokenLineComment:
+		return true
+	}
+
+	switch previous.tokenType {
+	case lexer.TokenParenOpen,
+		lexer.TokenBracketOpen,
+		lexer.TokenDot,
+		lexer.TokenQuestionMarkDot,
+		lexer.TokenAt,
+		lexer.TokenPragma:
+
+		return false
+
+	case lexer.TokenBraceOpen:
+		// Braces are used both for blocks and dictionaries,
+		// so keep the spacing inside braces on the same line
+		return current.spaceBefore
+
+	case lexer.TokenLess,
+		lexer.TokenGreater,
+		lexer.TokenSlash:
+
+		// The less-than and greater-than tokens are used both as comparison operators
+		// and for type arguments, and the slash token is used both for division and paths,
+		// so keep their spacing
+		return current.spaceBefore
+	}
+
+	if previousKind == itemKindPrefix {
+		return false
+	}
+
+	switch current.tokenType {
+	case lexer.TokenParenClose,
+		lexer.TokenBracketClose,
+		lexer.TokenComma,
+		lexer.TokenSemicolon,
+		lexer.TokenDot,
+		lexer.TokenQuestionMarkDot:
+
+		return false
+
+	case lexer.TokenBraceClose,
+		lexer.TokenLess,
+		lexer.TokenGreater,
+		lexer.TokenSlash:
+
+		return current.spaceBefore
+
+	case lexer.TokenColon,
+		lexer.TokenQuestionMark:
+
+		return currentKind == itemKindTernary
+
+	case lexer.TokenExclamationMark:
+		return currentKind != itemKindPostfix
+
+	case lexer.TokenParenOpen,
+		lexer.TokenBracketOpen:
+
+		// Invocations and indexing expressions are not separated
+		// from the invoked or indexed expression
+		return !f.isOperandEnd(index - 1)
+	}
+
+	return true
+}
@@ -0,0 +1,607 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package harness implements an in-process harness for testing Cadence programs.
+//
+// The harness executes transactions and scripts using the Cadence runtime,
+// against an in-memory implementation of the runtime interface.
+// It allows creating accounts, deploying contracts, advancing the block height and time,
+// and inspecting the emitted events and the stored values, without requiring an emulator.
+//
+// Failed executions have no effects, i.e. the changes to the storage, accounts, and contracts,
+// as well as the events and logs of a failed execution are discarded.
+//
+// The state of the harness can be checkpointed using snapshots,
+// so that multiple scenarios can be run from the same state.
+//
+package harness
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// DefaultBlockInterval is the default duration between two blocks
+//
+const DefaultBlockInterval = time.Second
+
+// DefaultComputationLimit is the default computation limit of executions.
+// It is effectively unlimited, but enables the metering of computation
+//
+const DefaultComputationLimit = math.MaxUint64
+
+// DefaultStorageCapacity is the default storage capacity of accounts, in bytes
+//
+const DefaultStorageCapacity = 100 * 1024 * 1024
+
+// SignatureVerifier verifies signatures, see runtime.Interface.VerifySignature
+//
+type SignatureVerifier func(
+	signature []byte,
+	tag string,
+	signedData []byte,
+	publicKey []byte,
+	signatureAlgorithm runtime.SignatureAlgorithm,
+	hashAlgorithm runtime.HashAlgorithm,
+) (bool, error)
+
+// Option is a harness option
+//
+type Option func(*Harness)
+
+// WithStartTime returns a harness option which sets the time of the first block
+//
+func WithStartTime(startTime time.Time) Option {
+	return func(harness *Harness) {
+		harness.blocks[0].Timestamp = startTime.UnixNano()
+	}
+}
+
+// WithBlockInterval returns a harness option which sets the duration between two blocks
+// when the block height is advanced
+//
+func WithBlockInterval(interval time.Duration) Option {
+	return func(harness *Harness) {
+		harness.blockInterval = interval
+	}
+}
+
+// WithComputationLimit returns a harness option which sets the computation limit of executions.
+// A limit of 0 means there is no limit, and disables the metering of computation
+//
+func WithComputationLimit(limit uint64) Option {
+	return func(harness *Harness) {
+		harness.computationLimit = limit
+	}
+}
+
+// WithStorageCapacity returns a harness option which sets the storage capacity of accounts, in bytes
+//
+func WithStorageCapacity(capacity uint64) Option {
+	return func(harness *Harness) {
+		harness.storageCapacity = capacity
+	}
+}
+
+// WithSignatureVerifier returns a harness option which sets the function used to verify signatures.
+// By default, signature verification is not supported
+//
+func WithSignatureVerifier(verifier SignatureVerifier) Option {
+	return func(harness *Harness) {
+		harness.signatureVerifier = verifier
+	}
+}
+
+// WithRuntimeOptions returns a harness option which sets the options of the runtime
+//
+func WithRuntimeOptions(options ...runtime.Option) Option {
+	return func(harness *Harness) {
+		harness.runtimeOptions = options
+	}
+}
+
+// TransactionResult is the result of a successfully executed transaction
+//
+type TransactionResult struct {
+	Events []cadence.Event
+	Logs   []string
+}
+
+// HostCalls is the number of calls of each function of the runtime interface,
+// by function name
+//
+type HostCalls map[string]int
+
+// Total returns the total number of calls
+//
+func (c HostCalls) Total() int {
+	total := 0
+	for _, count := range c {
+		total += count
+	}
+	return total
+}
+
+// ExecutionStats are statistics about an execution
+//
+type ExecutionStats struct {
+	// ComputationUsed is the computation used by the execution,
+	// if computation is metered
+	ComputationUsed uint64
+	// HostCalls are the calls of runtime interface functions by the execution
+	HostCalls HostCalls
+}
+
+// Harness executes transactions and scripts against an in-memory ledger
+//
+type Harness struct {
+	runtime           runtime.Runtime
+	runtimeOptions    []runtime.Option
+	ledger            *runtime.InMemoryLedger
+	state             *state
+	blocks            []runtime.Block
+	blockInterval     time.Duration
+	computationLimit  uint64
+	storageCapacity   uint64
+	signatureVerifier SignatureVerifier
+	random            *rand.Rand
+	transactionCount  int
+	lastExecution     ExecutionStats
+}
+
+// New returns a new harness with an empty ledger, at block height 0
+//
+func New(options ...Option) *Harness {
+	harness := &Harness{
+		ledger:           runtime.NewInMemoryLedger(),
+		state:            newState(),
+		blockInterval:    DefaultBlockInterval,
+		computationLimit: DefaultComputationLimit,
+		storageCapacity:  DefaultStorageCapacity,
+		random:           rand.New(rand.NewSource(0)),
+		blocks: []runtime.Block{
+			newBlock(0, time.Unix(0, 0).UnixNano()),
+		},
+	}
+
+	for _, option := range options {
+		option(harness)
+	}
+
+	harness.runtime = runtime.NewInterpreterRuntime(harness.runtimeOptions...)
+
+	return harness
+}
+
+func newBlock(height uint64, timestamp int64) runtime.Block {
+	var hash runtime.BlockHash
+	for i := 0; i < 8; i++ {
+		hash[len(hash)-1-i] = byte(height >> (8 * i))
+	}
+
+	return runtime.Block{
+		Height:    height,
+		View:      height,
+		Hash:      hash,
+		Timestamp: timestamp,
+	}
+}
+
+// CreateAccount creates a new account and returns its address
+//
+func (h *Harness) CreateAccount() common.Address {
+	return h.state.createAccount()
+}
+
+func uint64ToBytes(n uint64) []byte {
+	b := make([]byte, 8)
+	for i := 0; i < 8; i++ {
+		b[7-i] = byte(n >> (8 * i))
+	}
+	return b
+}
+
+const deployContractTransaction = `
+  transaction(name: String, code: String) {
+      prepare(signer: AuthAccount) {
+          signer.contracts.add(name: name, code: code.decodeHex())
+      }
+  }
+`
+
+const updateContractTransaction = `
+  transaction(name: String, code: String) {
+      prepare(signer: AuthAccount) {
+          signer.contracts.update__experimental(name: name, code: code.decodeHex())
+      }
+  }
+`
+
+// DeployContract deploys the contract with the given name and code to the given account
+//
+func (h *Harness) DeployContract(address common.Address, name string, code string) (*TransactionResult, error) {
+	return h.ExecuteTransaction(
+		deployContractTransaction,
+		[]cadence.Value{
+			cadence.String(name),
+			cadence.String(hex.EncodeToString([]byte(code))),
+		},
+		address,
+	)
+}
+
+// UpdateContract updates the code of the contract with the given name in the given account
+//
+func (h *Harness) UpdateContract(address common.Address, name string, code string) (*TransactionResult, error) {
+	return h.ExecuteTransaction(
+		updateContractTransaction,
+		[]cadence.Value{
+			cadence.String(name),
+			cadence.String(hex.EncodeToString([]byte(code))),
+		},
+		address,
+	)
+}
+
+// ExecuteTransaction executes the given transaction with the given arguments,
+// signed by the given accounts.
+//
+// If the execution fails, the transaction has no effects
+//
+func (h *Harness) ExecuteTransaction(
+	code string,
+	arguments []cadence.Value,
+	signers ...common.Address,
+) (
+	*TransactionResult,
+	error,
+) {
+	encodedArguments, err := encodeArguments(arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	h.transactionCount++
+	location := common.TransactionLocation(uint64ToBytes(uint64(h.transactionCount)))
+
+	var result *TransactionResult
+
+	err = h.execute(signers, func(runtimeInterface *runtimeInterface) error {
+		err := h.runtime.ExecuteTransaction(
+			runtime.Script{
+				Source:    []byte(code),
+				Arguments: encodedArguments,
+			},
+			runtime.Context{
+				Interface: runtimeInterface,
+				Location:  location,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		result = &TransactionResult{
+			Events: runtimeInterface.events,
+			Logs:   runtimeInterface.logs,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ExecuteScript executes the given script with the given arguments, and returns its result.
+//
+// Scripts have no effects, but their logs are recorded
+//
+func (h *Harness) ExecuteScript(code string, arguments ...cadence.Value) (cadence.Value, error) {
+	encodedArguments, err := encodeArguments(arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	var value cadence.Value
+
+	err = h.execute(nil, func(runtimeInterface *runtimeInterface) error {
+		value, err = h.runtime.ExecuteScript(
+			runtime.Script{
+				Source:    []byte(code),
+				Arguments: encodedArguments,
+			},
+			runtime.Context{
+				Interface: runtimeInterface,
+				Location:  common.ScriptLocation{},
+			},
+		)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// ReadStored returns the value stored at the given path of the given account, if any
+//
+func (h *Harness) ReadStored(address common.Address, path cadence.Path) (cadence.Value, error) {
+	var value cadence.Value
+
+	err := h.execute(nil, func(runtimeInterface *runtimeInterface) (err error) {
+		value, err = h.runtime.ReadStored(
+			address,
+			path,
+			runtime.Context{
+				Interface: runtimeInterface,
+				Location:  common.ScriptLocation{},
+			},
+		)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// ParseAndCheckProgram parses and checks the given program, without executing it.
+// Imports are resolved against the current state
+//
+func (h *Harness) ParseAndCheckProgram(code string) error {
+	return h.execute(nil, func(runtimeInterface *runtimeInterface) error {
+		_, err := h.runtime.ParseAndCheckProgram(
+			[]byte(code),
+			runtime.Context{
+				Interface: runtimeInterface,
+				Location:  common.ScriptLocation{},
+			},
+		)
+		return err
+	})
+}
+
+// InvokeContractFunction invokes the function with the given name of the contract
+// with the given name in the given account, and returns its result.
+//
+// The effects of the invocation are committed if it succeeds
+//
+func (h *Harness) InvokeContractFunction(
+	address common.Address,
+	contractName string,
+	functionName string,
+	arguments []interpreter.Value,
+	argumentTypes []sema.Type,
+) (
+	cadence.Value,
+	error,
+) {
+	location := common.AddressLocation{
+		Address: address,
+		Name:    contractName,
+	}
+
+	var value cadence.Value
+
+	err := h.execute(nil, func(runtimeInterface *runtimeInterface) (err error) {
+		value, err = h.runtime.InvokeContractFunction(
+			location,
+			functionName,
+			arguments,
+			argumentTypes,
+			runtime.Context{
+				Interface: runtimeInterface,
+				Location:  common.ScriptLocation{},
+			},
+		)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// execute runs the given execution against a copy of the current state,
+// and commits the changes if the execution succeeds.
+// If the execution fails, the changes to the storage are rolled back
+//
+func (h *Harness) execute(signers []common.Address, execution func(*runtimeInterface) error) error {
+	runtimeInterface := &runtimeInterface{
+		harness: h,
+		state:   h.state.clone(),
+		signers: signers,
+	}
+
+	snapshot := h.ledger.Snapshot()
+	defer h.ledger.Release(snapshot)
+
+	err := execution(runtimeInterface)
+
+	h.lastExecution = ExecutionStats{
+		ComputationUsed: runtimeInterface.computationUsed,
+		HostCalls:       runtimeInterface.hostCalls,
+	}
+
+	if err != nil {
+		restoreErr := h.ledger.Restore(snapshot)
+		if restoreErr != nil {
+			panic(restoreErr)
+		}
+		return err
+	}
+
+	runtimeInterface.state.events = append(runtimeInterface.state.events, runtimeInterface.events...)
+	runtimeInterface.state.logs = append(runtimeInterface.state.logs, runtimeInterface.logs...)
+
+	h.state = runtimeInterface.state
+
+	return nil
+}
+
+func encodeArguments(arguments []cadence.Value) ([][]byte, error) {
+	encodedArguments := make([][]byte, len(arguments))
+	for i, argument := range arguments {
+		encodedArgument, err := jsoncdc.Encode(argument)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode argument %d: %w", i, err)
+		}
+		encodedArguments[i] = encodedArgument
+	}
+	return encodedArguments, nil
+}
+
+// LastExecutionStats returns the statistics of the last execution,
+// which may have failed
+//
+func (h *Harness) LastExecutionStats() ExecutionStats {
+	return h.lastExecution
+}
+
+// Events returns all events emitted by successful executions, in order
+//
+func (h *Harness) Events() []cadence.Event {
+	return h.state.events
+}
+
+// EventsOfType returns all events of the given type emitted by successful executions, in order.
+// The type is identified by its type ID, e.g. `A.0000000000000001.Test.Deposit`
+//
+func (h *Harness) EventsOfType(typeID string) []cadence.Event {
+	var events []cadence.Event
+	for _, event := range h.state.events {
+		if event.EventType.ID() == typeID {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Logs returns all messages logged by successful executions, in order
+//
+func (h *Harness) Logs() []string {
+	return h.state.logs
+}
+
+// BlockHeight returns the current block height
+//
+func (h *Harness) BlockHeight() uint64 {
+	return h.currentBlock().Height
+}
+
+// BlockTime returns the time of the current block
+//
+func (h *Harness) BlockTime() time.Time {
+	return time.Unix(0, h.currentBlock().Timestamp).UTC()
+}
+
+func (h *Harness) currentBlock() runtime.Block {
+	return h.blocks[len(h.blocks)-1]
+}
+
+// AdvanceBlockHeight adds the given number of blocks,
+// each separated by the block interval
+//
+func (h *Harness) AdvanceBlockHeight(count uint64) {
+	for i := uint64(0); i < count; i++ {
+		h.addBlock(h.blockInterval)
+	}
+}
+
+// AdvanceTime adds a block with a time which is the given duration after the current block
+//
+func (h *Harness) AdvanceTime(duration time.Duration) {
+	h.addBlock(duration)
+}
+
+func (h *Harness) addBlock(duration time.Duration) {
+	current := h.currentBlock()
+	h.blocks = append(
+		h.blocks,
+		newBlock(current.Height+1, current.Timestamp+duration.Nanoseconds()),
+	)
+}
+
+// Snapshot is a checkpoint of the state of a harness,
+// including the storage, accounts, contracts, events, logs, and blocks
+//
+type Snapshot struct {
+	harness     *Harness
+	ledger      runtime.Snapshot
+	state       *state
+	blockHeight uint64
+}
+
+// Snapshot returns a checkpoint of the current state of the harness,
+// which can be restored later, e.g. to run multiple test cases from the same state.
+//
+// Snapshots should be released when they are no longer needed
+//
+func (h *Harness) Snapshot() *Snapshot {
+	return &Snapshot{
+		harness:     h,
+		ledger:      h.ledger.Snapshot(),
+		state:       h.state.clone(),
+		blockHeight: h.BlockHeight(),
+	}
+}
+
+// Restore restores the state of the given snapshot.
+// The snapshot stays valid, but restoring it invalidates all snapshots taken after it
+//
+func (h *Harness) Restore(snapshot *Snapshot) error {
+	if snapshot.harness != h {
+		return runtime.ErrUnknownSnapshot
+	}
+
+	err := h.ledger.Restore(snapshot.ledger)
+	if err != nil {
+		return err
+	}
+
+	h.state = snapshot.state.clone()
+	h.blocks = h.blocks[:snapshot.blockHeight+1]
+
+	return nil
+}
+
+// Release releases the given snapshot, so it can not be restored anymore
+//
+func (h *Harness) Release(snapshot *Snapshot) {
+	if snapshot.harness != h {
+		return
+	}
+
+	h.ledger.Release(snapshot.ledger)
+}
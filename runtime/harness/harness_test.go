@@ -0,0 +1,343 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package harness
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+const testContract = `
+  pub contract Test {
+
+      pub event Incremented(count: Int)
+
+      pub var count: Int
+
+      init() {
+          self.count = 0
+      }
+
+      pub fun increment() {
+          self.count = self.count + 1
+          emit Incremented(count: self.count)
+      }
+  }
+`
+
+func deployTestContract(t *testing.T, harness *Harness) common.Address {
+	address := harness.CreateAccount()
+
+	_, err := harness.DeployContract(address, "Test", testContract)
+	require.NoError(t, err)
+
+	return address
+}
+
+func TestHarnessDeployContract(t *testing.T) {
+
+	t.Parallel()
+
+	harness := New()
+	address := deployTestContract(t, harness)
+
+	value, err := harness.ExecuteScript(fmt.Sprintf(
+		`
+          import Test from %[1]s
+
+          pub fun main(): [String] {
+              return getAccount(%[1]s).contracts.names
+          }
+        `,
+		address.ShortHexWithPrefix(),
+	))
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		cadence.NewArray([]cadence.Value{
+			cadence.String("Test"),
+		}),
+		value,
+	)
+
+	_, err = harness.DeployContract(address, "Test", testContract)
+	require.Error(t, err)
+}
+
+func TestHarnessExecuteTransaction(t *testing.T) {
+
+	t.Parallel()
+
+	harness := New()
+	address := deployTestContract(t, harness)
+
+	result, err := harness.ExecuteTransaction(
+		fmt.Sprintf(
+			`
+              import Test from %s
+
+              transaction(times: Int) {
+                  prepare(signer: AuthAccount) {
+                      var i = 0
+                      while i < times {
+                          Test.increment()
+                          i = i + 1
+                      }
+                      log(signer.address)
+                  }
+              }
+            `,
+			address.ShortHexWithPrefix(),
+		),
+		[]cadence.Value{
+			cadence.NewInt(2),
+		},
+		address,
+	)
+	require.NoError(t, err)
+
+	require.Len(t, result.Events, 2)
+	assert.Equal(t,
+		[]cadence.Value{cadence.NewInt(2)},
+		result.Events[1].Fields,
+	)
+	assert.Equal(t,
+		[]string{address.ShortHexWithPrefix()},
+		result.Logs,
+	)
+
+	typeID := fmt.Sprintf("A.%s.Test.Incremented", address.Hex())
+	assert.Len(t, harness.EventsOfType(typeID), 2)
+	// The deployment emitted a flow.AccountContractAdded event
+	assert.Len(t, harness.Events(), 3)
+	assert.Equal(t, result.Logs, harness.Logs())
+
+	value, err := harness.ExecuteScript(fmt.Sprintf(
+		`
+          import Test from %s
+
+          pub fun main(): Int {
+              return Test.count
+          }
+        `,
+		address.ShortHexWithPrefix(),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, cadence.NewInt(2), value)
+}
+
+func TestHarnessFailedTransaction(t *testing.T) {
+
+	t.Parallel()
+
+	harness := New()
+	address := deployTestContract(t, harness)
+
+	events := harness.Events()
+
+	_, err := harness.ExecuteTransaction(
+		fmt.Sprintf(
+			`
+              import Test from %s
+
+              transaction {
+                  prepare(signer: AuthAccount) {
+                      Test.increment()
+                      signer.save(1, to: /storage/number)
+                      panic("failure")
+                  }
+              }
+            `,
+			address.ShortHexWithPrefix(),
+		),
+		nil,
+		address,
+	)
+	require.Error(t, err)
+
+	assert.Equal(t, events, harness.Events())
+
+	value, err := harness.ReadStored(
+		address,
+		cadence.Path{
+			Domain:     "storage",
+			Identifier: "number",
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, cadence.NewOptional(nil), value)
+}
+
+func TestHarnessStorage(t *testing.T) {
+
+	t.Parallel()
+
+	harness := New()
+	address := harness.CreateAccount()
+
+	_, err := harness.ExecuteTransaction(
+		`
+          transaction(message: String) {
+              prepare(signer: AuthAccount) {
+                  signer.save(message, to: /storage/message)
+              }
+          }
+        `,
+		[]cadence.Value{
+			cadence.String("hello"),
+		},
+		address,
+	)
+	require.NoError(t, err)
+
+	value, err := harness.ReadStored(
+		address,
+		cadence.Path{
+			Domain:     "storage",
+			Identifier: "message",
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, cadence.NewOptional(cadence.String("hello")), value)
+}
+
+func TestHarnessBlocks(t *testing.T) {
+
+	t.Parallel()
+
+	startTime := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	harness := New(
+		WithStartTime(startTime),
+		WithBlockInterval(10*time.Second),
+	)
+
+	assert.Equal(t, uint64(0), harness.BlockHeight())
+
+	harness.AdvanceBlockHeight(3)
+	harness.AdvanceTime(time.Hour)
+
+	assert.Equal(t, uint64(4), harness.BlockHeight())
+	assert.Equal(t, startTime.Add(30*time.Second+time.Hour), harness.BlockTime())
+
+	value, err := harness.ExecuteScript(`
+      pub fun main(): [AnyStruct] {
+          let block = getCurrentBlock()
+          return [block.height, block.timestamp, getBlock(at: 1)!.timestamp]
+      }
+    `)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		cadence.NewArray([]cadence.Value{
+			cadence.NewUInt64(4),
+			cadence.UFix64(uint64(startTime.Add(30*time.Second+time.Hour).Unix()) * 100_000_000),
+			cadence.UFix64(uint64(startTime.Add(10*time.Second).Unix()) * 100_000_000),
+		}),
+		value,
+	)
+}
+
+func TestHarnessSnapshot(t *testing.T) {
+
+	t.Parallel()
+
+	harness := New()
+	address := deployTestContract(t, harness)
+
+	increment := fmt.Sprintf(
+		`
+          import Test from %s
+
+          transaction {
+              prepare(signer: AuthAccount) {
+                  Test.increment()
+                  signer.save(Test.count, to: /storage/count)
+              }
+          }
+        `,
+		address.ShortHexWithPrefix(),
+	)
+
+	countPath := cadence.Path{
+		Domain:     "storage",
+		Identifier: "count",
+	}
+
+	snapshot := harness.Snapshot()
+	defer harness.Release(snapshot)
+
+	for i := 0; i < 2; i++ {
+
+		_, err := harness.ExecuteTransaction(increment, nil, address)
+		require.NoError(t, err)
+
+		harness.AdvanceBlockHeight(1)
+
+		value, err := harness.ReadStored(address, countPath)
+		require.NoError(t, err)
+		assert.Equal(t, cadence.NewOptional(cadence.NewInt(1)), value)
+
+		require.NoError(t, harness.Restore(snapshot))
+
+		value, err = harness.ReadStored(address, countPath)
+		require.NoError(t, err)
+		assert.Equal(t, cadence.NewOptional(nil), value)
+
+		assert.Equal(t, uint64(0), harness.BlockHeight())
+		assert.Len(t, harness.Events(), 1)
+	}
+
+	require.ErrorIs(t, New().Restore(snapshot), runtime.ErrUnknownSnapshot)
+}
+
+func TestHarnessParseAndCheckProgram(t *testing.T) {
+
+	t.Parallel()
+
+	harness := New()
+
+	address := deployTestContract(t, harness)
+
+	err := harness.ParseAndCheckProgram(fmt.Sprintf(`
+      import Test from %s
+
+      pub fun main(): Int {
+          return Test.count
+      }
+    `, address.ShortHexWithPrefix()))
+	require.NoError(t, err)
+
+	err = harness.ParseAndCheckProgram(fmt.Sprintf(`
+      import Test from %s
+
+      pub fun main(): String {
+          return Test.count
+      }
+    `, address.ShortHexWithPrefix()))
+	require.Error(t, err)
+}
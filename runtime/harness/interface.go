@@ -0,0 +1,560 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package harness
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"sort"
+	"time"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// ErrNotSupported is returned by operations which are not supported by the harness,
+// e.g. signature verification, unless a signature verifier is provided
+//
+var ErrNotSupported = errors.New("not supported by the test harness")
+
+// runtimeInterface is the implementation of runtime.Interface for a single execution.
+//
+// The changes of the execution are applied to a copy of the harness state,
+// which replaces the state of the harness if the execution succeeds.
+//
+type runtimeInterface struct {
+	harness         *Harness
+	state           *state
+	signers         []common.Address
+	programs        map[common.LocationID]*interpreter.Program
+	events          []cadence.Event
+	logs            []string
+	computationUsed uint64
+	hostCalls       HostCalls
+}
+
+var _ runtime.Interface = &runtimeInterface{}
+
+func (i *runtimeInterface) recordHostCall(name string) {
+	if i.hostCalls == nil {
+		i.hostCalls = HostCalls{}
+	}
+	i.hostCalls[name]++
+}
+
+func (i *runtimeInterface) ResolveLocation(
+	identifiers []runtime.Identifier,
+	location runtime.Location,
+) (
+	[]runtime.ResolvedLocation,
+	error,
+) {
+	i.recordHostCall("ResolveLocation")
+
+	addressLocation, ok := location.(common.AddressLocation)
+
+	// Only address locations need to be resolved
+
+	if !ok {
+		return []runtime.ResolvedLocation{
+			{
+				Location:    location,
+				Identifiers: identifiers,
+			},
+		}, nil
+	}
+
+	return runtime.ResolveAddressLocation(
+		identifiers,
+		addressLocation,
+		func(address runtime.Address) ([]string, error) {
+			return i.contractNames(address), nil
+		},
+	)
+}
+
+func (i *runtimeInterface) GetCode(location runtime.Location) ([]byte, error) {
+	i.recordHostCall("GetCode")
+
+	addressLocation, ok := location.(common.AddressLocation)
+	if !ok {
+		return nil, fmt.Errorf("cannot import from location %s", location)
+	}
+
+	code, ok := i.state.contracts[contractKey{
+		address: addressLocation.Address,
+		name:    addressLocation.Name,
+	}]
+	if !ok {
+		return nil, fmt.Errorf("unknown contract %s", location)
+	}
+
+	return code, nil
+}
+
+func (i *runtimeInterface) GetProgram(location runtime.Location) (*interpreter.Program, error) {
+	i.recordHostCall("GetProgram")
+
+	return i.programs[location.ID()], nil
+}
+
+func (i *runtimeInterface) SetProgram(location runtime.Location, program *interpreter.Program) error {
+	i.recordHostCall("SetProgram")
+
+	if i.programs == nil {
+		i.programs = map[common.LocationID]*interpreter.Program{}
+	}
+	i.programs[location.ID()] = program
+	return nil
+}
+
+func (i *runtimeInterface) GetValue(owner, key []byte) (value []byte, err error) {
+	i.recordHostCall("GetValue")
+
+	return i.harness.ledger.GetValue(owner, key)
+}
+
+func (i *runtimeInterface) SetValue(owner, key, value []byte) (err error) {
+	i.recordHostCall("SetValue")
+
+	return i.harness.ledger.SetValue(owner, key, value)
+}
+
+func (i *runtimeInterface) GetValues(keys []runtime.StorageKey) (values [][]byte, err error) {
+	i.recordHostCall("GetValues")
+
+	values = make([][]byte, len(keys))
+	for index, key := range keys {
+		values[index], err = i.harness.ledger.GetValue(key.Address[:], []byte(key.Key))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+func (i *runtimeInterface) SetValues(writes []runtime.StorageWrite) (err error) {
+	i.recordHostCall("SetValues")
+
+	for _, write := range writes {
+		err = i.harness.ledger.SetValue(write.Address[:], []byte(write.Key), write.Value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *runtimeInterface) ValueExists(owner, key []byte) (exists bool, err error) {
+	i.recordHostCall("ValueExists")
+
+	return i.harness.ledger.ValueExists(owner, key)
+}
+
+func (i *runtimeInterface) GetStorageKeys(owner, prefix []byte) (keys [][]byte, err error) {
+	i.recordHostCall("GetStorageKeys")
+
+	return i.harness.ledger.GetStorageKeys(owner, prefix)
+}
+
+func (i *runtimeInterface) account(address runtime.Address) (*account, error) {
+	account, ok := i.state.accounts[address]
+	if !ok {
+		return nil, fmt.Errorf("unknown account %s", address)
+	}
+	return account, nil
+}
+
+func (i *runtimeInterface) CreateAccount(_ runtime.Address) (address runtime.Address, err error) {
+	i.recordHostCall("CreateAccount")
+
+	return i.state.createAccount(), nil
+}
+
+func (i *runtimeInterface) AddEncodedAccountKey(address runtime.Address, publicKey []byte) error {
+	i.recordHostCall("AddEncodedAccountKey")
+
+	account, err := i.account(address)
+	if err != nil {
+		return err
+	}
+
+	account.encodedKeys = append(account.encodedKeys, publicKey)
+
+	return nil
+}
+
+func (i *runtimeInterface) RevokeEncodedAccountKey(address runtime.Address, index int) (publicKey []byte, err error) {
+	i.recordHostCall("RevokeEncodedAccountKey")
+
+	account, err := i.account(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if index < 0 || index >= len(account.encodedKeys) {
+		return nil, nil
+	}
+
+	publicKey = account.encodedKeys[index]
+	account.encodedKeys = append(
+		account.encodedKeys[:index:index],
+		account.encodedKeys[index+1:]...,
+	)
+
+	return publicKey, nil
+}
+
+func (i *runtimeInterface) AddAccountKey(
+	address runtime.Address,
+	publicKey *runtime.PublicKey,
+	hashAlgo runtime.HashAlgorithm,
+	weight int,
+) (
+	*runtime.AccountKey,
+	error,
+) {
+	i.recordHostCall("AddAccountKey")
+
+	account, err := i.account(address)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &runtime.AccountKey{
+		KeyIndex:  len(account.keys),
+		PublicKey: publicKey,
+		HashAlgo:  hashAlgo,
+		Weight:    weight,
+	}
+
+	account.keys = append(account.keys, key)
+
+	return key, nil
+}
+
+func (i *runtimeInterface) GetAccountKey(address runtime.Address, index int) (*runtime.AccountKey, error) {
+	i.recordHostCall("GetAccountKey")
+
+	return i.accountKey(address, index)
+}
+
+func (i *runtimeInterface) accountKey(address runtime.Address, index int) (*runtime.AccountKey, error) {
+	account, err := i.account(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if index < 0 || index >= len(account.keys) {
+		return nil, nil
+	}
+
+	return account.keys[index], nil
+}
+
+func (i *runtimeInterface) RevokeAccountKey(address runtime.Address, index int) (*runtime.AccountKey, error) {
+	i.recordHostCall("RevokeAccountKey")
+
+	key, err := i.accountKey(address, index)
+	if err != nil || key == nil {
+		return nil, err
+	}
+
+	key.IsRevoked = true
+
+	return key, nil
+}
+
+func (i *runtimeInterface) UpdateAccountContractCode(address runtime.Address, name string, code []byte) (err error) {
+	i.recordHostCall("UpdateAccountContractCode")
+
+	_, err = i.account(address)
+	if err != nil {
+		return err
+	}
+
+	i.state.contracts[contractKey{
+		address: address,
+		name:    name,
+	}] = code
+
+	return nil
+}
+
+func (i *runtimeInterface) GetAccountContractCode(address runtime.Address, name string) (code []byte, err error) {
+	i.recordHostCall("GetAccountContractCode")
+
+	return i.state.contracts[contractKey{
+		address: address,
+		name:    name,
+	}], nil
+}
+
+func (i *runtimeInterface) RemoveAccountContractCode(address runtime.Address, name string) (err error) {
+	i.recordHostCall("RemoveAccountContractCode")
+
+	delete(i.state.contracts, contractKey{
+		address: address,
+		name:    name,
+	})
+	return nil
+}
+
+func (i *runtimeInterface) contractNames(address runtime.Address) []string {
+	var names []string
+	for key := range i.state.contracts {
+		if key.address == address {
+			names = append(names, key.name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (i *runtimeInterface) GetAccountContractNames(address runtime.Address) ([]string, error) {
+	i.recordHostCall("GetAccountContractNames")
+
+	return i.contractNames(address), nil
+}
+
+func (i *runtimeInterface) GetSigningAccounts() ([]runtime.Address, error) {
+	i.recordHostCall("GetSigningAccounts")
+
+	return i.signers, nil
+}
+
+func (i *runtimeInterface) ProgramLog(message string) error {
+	i.recordHostCall("ProgramLog")
+
+	i.logs = append(i.logs, message)
+	return nil
+}
+
+func (i *runtimeInterface) EmitEvent(event cadence.Event) error {
+	i.recordHostCall("EmitEvent")
+
+	i.events = append(i.events, event)
+	return nil
+}
+
+func (i *runtimeInterface) GenerateUUID() (uint64, error) {
+	i.recordHostCall("GenerateUUID")
+
+	uuid := i.state.uuid
+	i.state.uuid++
+	return uuid, nil
+}
+
+func (i *runtimeInterface) GetComputationLimit() uint64 {
+	i.recordHostCall("GetComputationLimit")
+
+	return i.harness.computationLimit
+}
+
+func (i *runtimeInterface) SetComputationUsed(used uint64) error {
+	i.recordHostCall("SetComputationUsed")
+
+	i.computationUsed = used
+	return nil
+}
+
+func (i *runtimeInterface) DecodeArgument(argument []byte, _ cadence.Type) (cadence.Value, error) {
+	i.recordHostCall("DecodeArgument")
+
+	return jsoncdc.Decode(argument)
+}
+
+func (i *runtimeInterface) GetCurrentBlockHeight() (uint64, error) {
+	i.recordHostCall("GetCurrentBlockHeight")
+
+	return i.harness.BlockHeight(), nil
+}
+
+func (i *runtimeInterface) GetBlockAtHeight(height uint64) (block runtime.Block, exists bool, err error) {
+	i.recordHostCall("GetBlockAtHeight")
+
+	if height >= uint64(len(i.harness.blocks)) {
+		return runtime.Block{}, false, nil
+	}
+	return i.harness.blocks[height], true, nil
+}
+
+func (i *runtimeInterface) UnsafeRandom() (uint64, error) {
+	i.recordHostCall("UnsafeRandom")
+
+	return i.harness.random.Uint64(), nil
+}
+
+func (i *runtimeInterface) ReadRandom(buffer []byte) error {
+	i.recordHostCall("ReadRandom")
+
+	_, err := i.harness.random.Read(buffer)
+	return err
+}
+
+func (i *runtimeInterface) VerifySignature(
+	signature []byte,
+	tag string,
+	signedData []byte,
+	publicKey []byte,
+	signatureAlgorithm runtime.SignatureAlgorithm,
+	hashAlgorithm runtime.HashAlgorithm,
+) (bool, error) {
+	i.recordHostCall("VerifySignature")
+
+	if i.harness.signatureVerifier == nil {
+		return false, ErrNotSupported
+	}
+
+	return i.harness.signatureVerifier(
+		signature,
+		tag,
+		signedData,
+		publicKey,
+		signatureAlgorithm,
+		hashAlgorithm,
+	)
+}
+
+// Hash returns the digest of the given data.
+// The tag is ignored, and KMAC128 is not supported
+//
+func (i *runtimeInterface) Hash(data []byte, _ string, hashAlgorithm runtime.HashAlgorithm) ([]byte, error) {
+	i.recordHostCall("Hash")
+
+	var hasher hash.Hash
+
+	switch hashAlgorithm {
+	case sema.HashAlgorithmSHA2_256:
+		hasher = sha256.New()
+	case sema.HashAlgorithmSHA2_384:
+		hasher = sha512.New384()
+	case sema.HashAlgorithmSHA3_256:
+		hasher = sha3.New256()
+	case sema.HashAlgorithmSHA3_384:
+		hasher = sha3.New384()
+	case sema.HashAlgorithmKECCAK_256:
+		hasher = sha3.NewLegacyKeccak256()
+	default:
+		return nil, fmt.Errorf("hash algorithm %s: %w", hashAlgorithm.Name(), ErrNotSupported)
+	}
+
+	hasher.Write(data)
+
+	return hasher.Sum(nil), nil
+}
+
+func (i *runtimeInterface) GetAccountBalance(_ common.Address) (value uint64, err error) {
+	i.recordHostCall("GetAccountBalance")
+
+	return 0, nil
+}
+
+func (i *runtimeInterface) GetAccountAvailableBalance(_ common.Address) (value uint64, err error) {
+	i.recordHostCall("GetAccountAvailableBalance")
+
+	return 0, nil
+}
+
+func (i *runtimeInterface) GetStorageUsed(address runtime.Address) (value uint64, err error) {
+	i.recordHostCall("GetStorageUsed")
+
+	keys, err := i.harness.ledger.GetStorageKeys(address[:], nil)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, key := range keys {
+		data, err := i.harness.ledger.GetValue(address[:], key)
+		if err != nil {
+			return 0, err
+		}
+		value += uint64(len(key) + len(data))
+	}
+
+	return value, nil
+}
+
+func (i *runtimeInterface) GetStorageCapacity(_ runtime.Address) (value uint64, err error) {
+	i.recordHostCall("GetStorageCapacity")
+
+	return i.harness.storageCapacity, nil
+}
+
+func (i *runtimeInterface) ImplementationDebugLog(_ string) error {
+	i.recordHostCall("ImplementationDebugLog")
+
+	return nil
+}
+
+// ValidatePublicKey considers all public keys valid
+//
+func (i *runtimeInterface) ValidatePublicKey(_ *runtime.PublicKey) (bool, error) {
+	i.recordHostCall("ValidatePublicKey")
+
+	return true, nil
+}
+
+func (i *runtimeInterface) RecoverPublicKey(
+	_ []byte,
+	_ []byte,
+	_ runtime.SignatureAlgorithm,
+	_ runtime.HashAlgorithm,
+) (publicKey []byte, err error) {
+	i.recordHostCall("RecoverPublicKey")
+
+	return nil, ErrNotSupported
+}
+
+func (i *runtimeInterface) BLSVerifyPOP(_ *runtime.PublicKey, _ []byte) (bool, error) {
+	i.recordHostCall("BLSVerifyPOP")
+
+	return false, ErrNotSupported
+}
+
+func (i *runtimeInterface) BLSAggregateSignatures(_ [][]byte) ([]byte, error) {
+	i.recordHostCall("BLSAggregateSignatures")
+
+	return nil, ErrNotSupported
+}
+
+func (i *runtimeInterface) BLSAggregatePublicKeys(_ []*runtime.PublicKey) (*runtime.PublicKey, error) {
+	i.recordHostCall("BLSAggregatePublicKeys")
+
+	return nil, ErrNotSupported
+}
+
+func (i *runtimeInterface) ProgramParsed(_ common.Location, _ time.Duration) {}
+
+func (i *runtimeInterface) ProgramChecked(_ common.Location, _ time.Duration) {}
+
+func (i *runtimeInterface) ProgramInterpreted(_ common.Location, _ time.Duration) {}
+
+func (i *runtimeInterface) ValueEncoded(_ time.Duration) {}
+
+func (i *runtimeInterface) ValueDecoded(_ time.Duration) {}
@@ -0,0 +1,99 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package harness
+
+import (
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+type contractKey struct {
+	address common.Address
+	name    string
+}
+
+type account struct {
+	keys        []*runtime.AccountKey
+	encodedKeys [][]byte
+}
+
+func (a *account) clone() *account {
+	keys := make([]*runtime.AccountKey, len(a.keys))
+	for i, key := range a.keys {
+		keyCopy := *key
+		keys[i] = &keyCopy
+	}
+
+	return &account{
+		keys:        keys,
+		encodedKeys: append([][]byte(nil), a.encodedKeys...),
+	}
+}
+
+// state is the state of the harness which is changed by executions,
+// except for the storage, which is kept in a ledger.
+// It is copied before each execution, so it can be restored if the execution fails
+//
+type state struct {
+	contracts   map[contractKey][]byte
+	accounts    map[common.Address]*account
+	events      []cadence.Event
+	logs        []string
+	uuid        uint64
+	nextAddress uint64
+}
+
+func newState() *state {
+	return &state{
+		contracts:   map[contractKey][]byte{},
+		accounts:    map[common.Address]*account{},
+		nextAddress: 1,
+	}
+}
+
+func (s *state) clone() *state {
+	contracts := make(map[contractKey][]byte, len(s.contracts))
+	for key, code := range s.contracts {
+		contracts[key] = code
+	}
+
+	accounts := make(map[common.Address]*account, len(s.accounts))
+	for address, acc := range s.accounts {
+		accounts[address] = acc.clone()
+	}
+
+	return &state{
+		contracts:   contracts,
+		accounts:    accounts,
+		events:      s.events[:len(s.events):len(s.events)],
+		logs:        s.logs[:len(s.logs):len(s.logs)],
+		uuid:        s.uuid,
+		nextAddress: s.nextAddress,
+	}
+}
+
+func (s *state) createAccount() common.Address {
+	address := common.BytesToAddress(uint64ToBytes(s.nextAddress))
+	s.nextAddress++
+
+	s.accounts[address] = &account{}
+
+	return address
+}
@@ -0,0 +1,591 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"sort"
+	"time"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// HostCall is a call of a function of the runtime interface,
+// i.e. a call across the boundary between the runtime and the host environment.
+//
+type HostCall struct {
+	// Name is the name of the called function of the runtime interface, e.g. `GetValue`
+	Name string
+	// Start is the time the call started
+	Start time.Time
+	// Duration is the time the host environment took to handle the call
+	Duration time.Duration
+	// ArgumentsSize is the total size in bytes of the byte slice, string, address,
+	// and public key arguments of the call
+	ArgumentsSize int
+	// ResultSize is the total size in bytes of the byte slice, string, address,
+	// and public key results of the call
+	ResultSize int
+	// Err is the error returned by the call, if any
+	Err error
+}
+
+// HostCallTrace is the trace of the host calls of an execution, in order.
+//
+type HostCallTrace struct {
+	Calls []HostCall
+}
+
+// HostCallSummary is the summary of all calls of a function of the runtime interface.
+//
+type HostCallSummary struct {
+	Name          string
+	Count         int
+	Duration      time.Duration
+	ArgumentsSize int
+	ResultSize    int
+	Errors        int
+}
+
+// Summary returns the summaries of the calls of each function,
+// sorted by total duration, longest first, and then by name
+//
+func (t *HostCallTrace) Summary() []HostCallSummary {
+	summaries := map[string]*HostCallSummary{}
+
+	for _, call := range t.Calls {
+		summary, ok := summaries[call.Name]
+		if !ok {
+			summary = &HostCallSummary{
+				Name: call.Name,
+			}
+			summaries[call.Name] = summary
+		}
+
+		summary.Count++
+		summary.Duration += call.Duration
+		summary.ArgumentsSize += call.ArgumentsSize
+		summary.ResultSize += call.ResultSize
+		if call.Err != nil {
+			summary.Errors++
+		}
+	}
+
+	result := make([]HostCallSummary, 0, len(summaries))
+	for _, summary := range summaries { //nolint:maprangecheck
+		result = append(result, *summary)
+	}
+
+	sort.Slice(result, func(a, b int) bool {
+		summaryA := result[a]
+		summaryB := result[b]
+
+		if summaryA.Duration != summaryB.Duration {
+			return summaryA.Duration > summaryB.Duration
+		}
+
+		return summaryA.Name < summaryB.Name
+	})
+
+	return result
+}
+
+// HostCallTracer is a runtime interface which wraps another runtime interface,
+// and records every call of the wrapped interface.
+//
+// If the wrapped interface also implements Metrics, the metrics are forwarded to it.
+//
+// Calls which panic are not recorded.
+// A tracer is not safe for concurrent use.
+//
+type HostCallTracer struct {
+	runtimeInterface Interface
+	onHostCall       func(call HostCall)
+	trace            HostCallTrace
+}
+
+var _ Interface = &HostCallTracer{}
+var _ Metrics = &HostCallTracer{}
+
+// NewHostCallTracer returns a tracer for the given runtime interface.
+//
+// The optional function onHostCall is called after each host call,
+// e.g. to export it as an OpenTelemetry span while the execution is still in progress.
+//
+func NewHostCallTracer(runtimeInterface Interface, onHostCall func(call HostCall)) *HostCallTracer {
+	return &HostCallTracer{
+		runtimeInterface: runtimeInterface,
+		onHostCall:       onHostCall,
+	}
+}
+
+// Trace returns the trace of all host calls recorded so far
+//
+func (t *HostCallTracer) Trace() *HostCallTrace {
+	calls := make([]HostCall, len(t.trace.Calls))
+	copy(calls, t.trace.Calls)
+
+	return &HostCallTrace{
+		Calls: calls,
+	}
+}
+
+// Reset discards all recorded host calls
+//
+func (t *HostCallTracer) Reset() {
+	t.trace.Calls = nil
+}
+
+func (t *HostCallTracer) record(name string, start time.Time, argumentsSize, resultSize int, err error) {
+	call := HostCall{
+		Name:          name,
+		Start:         start,
+		Duration:      time.Since(start),
+		ArgumentsSize: argumentsSize,
+		ResultSize:    resultSize,
+		Err:           err,
+	}
+
+	t.trace.Calls = append(t.trace.Calls, call)
+
+	if t.onHostCall != nil {
+		t.onHostCall(call)
+	}
+}
+
+const addressSize = len(common.Address{})
+
+func publicKeySize(publicKey *PublicKey) int {
+	if publicKey == nil {
+		return 0
+	}
+	return len(publicKey.PublicKey)
+}
+
+func accountKeySize(accountKey *AccountKey) int {
+	if accountKey == nil {
+		return 0
+	}
+	return publicKeySize(accountKey.PublicKey)
+}
+
+func identifiersSize(identifiers []Identifier) int {
+	size := 0
+	for _, identifier := range identifiers {
+		size += len(identifier.Identifier)
+	}
+	return size
+}
+
+func (t *HostCallTracer) ResolveLocation(identifiers []Identifier, location Location) ([]ResolvedLocation, error) {
+	start := time.Now()
+	resolvedLocations, err := t.runtimeInterface.ResolveLocation(identifiers, location)
+	resultSize := 0
+	for _, resolvedLocation := range resolvedLocations {
+		resultSize += identifiersSize(resolvedLocation.Identifiers)
+	}
+	t.record("ResolveLocation", start, identifiersSize(identifiers), resultSize, err)
+	return resolvedLocations, err
+}
+
+func (t *HostCallTracer) GetCode(location Location) ([]byte, error) {
+	start := time.Now()
+	code, err := t.runtimeInterface.GetCode(location)
+	t.record("GetCode", start, 0, len(code), err)
+	return code, err
+}
+
+func (t *HostCallTracer) GetProgram(location Location) (*interpreter.Program, error) {
+	start := time.Now()
+	program, err := t.runtimeInterface.GetProgram(location)
+	t.record("GetProgram", start, 0, 0, err)
+	return program, err
+}
+
+func (t *HostCallTracer) SetProgram(location Location, program *interpreter.Program) error {
+	start := time.Now()
+	err := t.runtimeInterface.SetProgram(location, program)
+	t.record("SetProgram", start, 0, 0, err)
+	return err
+}
+
+func (t *HostCallTracer) GetValue(owner, key []byte) (value []byte, err error) {
+	start := time.Now()
+	value, err = t.runtimeInterface.GetValue(owner, key)
+	t.record("GetValue", start, len(owner)+len(key), len(value), err)
+	return value, err
+}
+
+func (t *HostCallTracer) SetValue(owner, key, value []byte) (err error) {
+	start := time.Now()
+	err = t.runtimeInterface.SetValue(owner, key, value)
+	t.record("SetValue", start, len(owner)+len(key)+len(value), 0, err)
+	return err
+}
+
+func (t *HostCallTracer) GetValues(keys []StorageKey) (values [][]byte, err error) {
+	start := time.Now()
+	values, err = t.runtimeInterface.GetValues(keys)
+	argumentsSize := 0
+	for _, key := range keys {
+		argumentsSize += addressSize + len(key.Key)
+	}
+	resultSize := 0
+	for _, value := range values {
+		resultSize += len(value)
+	}
+	t.record("GetValues", start, argumentsSize, resultSize, err)
+	return values, err
+}
+
+func (t *HostCallTracer) SetValues(writes []StorageWrite) (err error) {
+	start := time.Now()
+	err = t.runtimeInterface.SetValues(writes)
+	argumentsSize := 0
+	for _, write := range writes {
+		argumentsSize += addressSize + len(write.Key) + len(write.Value)
+	}
+	t.record("SetValues", start, argumentsSize, 0, err)
+	return err
+}
+
+func (t *HostCallTracer) CreateAccount(payer Address) (address Address, err error) {
+	start := time.Now()
+	address, err = t.runtimeInterface.CreateAccount(payer)
+	t.record("CreateAccount", start, addressSize, addressSize, err)
+	return address, err
+}
+
+func (t *HostCallTracer) AddEncodedAccountKey(address Address, publicKey []byte) error {
+	start := time.Now()
+	err := t.runtimeInterface.AddEncodedAccountKey(address, publicKey)
+	t.record("AddEncodedAccountKey", start, addressSize+len(publicKey), 0, err)
+	return err
+}
+
+func (t *HostCallTracer) RevokeEncodedAccountKey(address Address, index int) (publicKey []byte, err error) {
+	start := time.Now()
+	publicKey, err = t.runtimeInterface.RevokeEncodedAccountKey(address, index)
+	t.record("RevokeEncodedAccountKey", start, addressSize, len(publicKey), err)
+	return publicKey, err
+}
+
+func (t *HostCallTracer) AddAccountKey(
+	address Address,
+	publicKey *PublicKey,
+	hashAlgo HashAlgorithm,
+	weight int,
+) (*AccountKey, error) {
+	start := time.Now()
+	accountKey, err := t.runtimeInterface.AddAccountKey(address, publicKey, hashAlgo, weight)
+	t.record("AddAccountKey", start, addressSize+publicKeySize(publicKey), accountKeySize(accountKey), err)
+	return accountKey, err
+}
+
+func (t *HostCallTracer) GetAccountKey(address Address, index int) (*AccountKey, error) {
+	start := time.Now()
+	accountKey, err := t.runtimeInterface.GetAccountKey(address, index)
+	t.record("GetAccountKey", start, addressSize, accountKeySize(accountKey), err)
+	return accountKey, err
+}
+
+func (t *HostCallTracer) RevokeAccountKey(address Address, index int) (*AccountKey, error) {
+	start := time.Now()
+	accountKey, err := t.runtimeInterface.RevokeAccountKey(address, index)
+	t.record("RevokeAccountKey", start, addressSize, accountKeySize(accountKey), err)
+	return accountKey, err
+}
+
+func (t *HostCallTracer) UpdateAccountContractCode(address Address, name string, code []byte) (err error) {
+	start := time.Now()
+	err = t.runtimeInterface.UpdateAccountContractCode(address, name, code)
+	t.record("UpdateAccountContractCode", start, addressSize+len(name)+len(code), 0, err)
+	return err
+}
+
+func (t *HostCallTracer) GetAccountContractCode(address Address, name string) (code []byte, err error) {
+	start := time.Now()
+	code, err = t.runtimeInterface.GetAccountContractCode(address, name)
+	t.record("GetAccountContractCode", start, addressSize+len(name), len(code), err)
+	return code, err
+}
+
+func (t *HostCallTracer) RemoveAccountContractCode(address Address, name string) (err error) {
+	start := time.Now()
+	err = t.runtimeInterface.RemoveAccountContractCode(address, name)
+	t.record("RemoveAccountContractCode", start, addressSize+len(name), 0, err)
+	return err
+}
+
+func (t *HostCallTracer) GetSigningAccounts() ([]Address, error) {
+	start := time.Now()
+	addresses, err := t.runtimeInterface.GetSigningAccounts()
+	t.record("GetSigningAccounts", start, 0, len(addresses)*addressSize, err)
+	return addresses, err
+}
+
+func (t *HostCallTracer) ProgramLog(message string) error {
+	start := time.Now()
+	err := t.runtimeInterface.ProgramLog(message)
+	t.record("ProgramLog", start, len(message), 0, err)
+	return err
+}
+
+func (t *HostCallTracer) EmitEvent(event cadence.Event) error {
+	start := time.Now()
+	err := t.runtimeInterface.EmitEvent(event)
+	t.record("EmitEvent", start, 0, 0, err)
+	return err
+}
+
+func (t *HostCallTracer) ValueExists(owner, key []byte) (exists bool, err error) {
+	start := time.Now()
+	exists, err = t.runtimeInterface.ValueExists(owner, key)
+	t.record("ValueExists", start, len(owner)+len(key), 0, err)
+	return exists, err
+}
+
+func (t *HostCallTracer) GetStorageKeys(owner, prefix []byte) (keys [][]byte, err error) {
+	start := time.Now()
+	keys, err = t.runtimeInterface.GetStorageKeys(owner, prefix)
+	resultSize := 0
+	for _, key := range keys {
+		resultSize += len(key)
+	}
+	t.record("GetStorageKeys", start, len(owner)+len(prefix), resultSize, err)
+	return keys, err
+}
+
+func (t *HostCallTracer) GenerateUUID() (uint64, error) {
+	start := time.Now()
+	uuid, err := t.runtimeInterface.GenerateUUID()
+	t.record("GenerateUUID", start, 0, 0, err)
+	return uuid, err
+}
+
+func (t *HostCallTracer) GetComputationLimit() uint64 {
+	start := time.Now()
+	limit := t.runtimeInterface.GetComputationLimit()
+	t.record("GetComputationLimit", start, 0, 0, nil)
+	return limit
+}
+
+func (t *HostCallTracer) SetComputationUsed(used uint64) error {
+	start := time.Now()
+	err := t.runtimeInterface.SetComputationUsed(used)
+	t.record("SetComputationUsed", start, 0, 0, err)
+	return err
+}
+
+func (t *HostCallTracer) DecodeArgument(argument []byte, argumentType cadence.Type) (cadence.Value, error) {
+	start := time.Now()
+	value, err := t.runtimeInterface.DecodeArgument(argument, argumentType)
+	t.record("DecodeArgument", start, len(argument), 0, err)
+	return value, err
+}
+
+func (t *HostCallTracer) GetCurrentBlockHeight() (uint64, error) {
+	start := time.Now()
+	height, err := t.runtimeInterface.GetCurrentBlockHeight()
+	t.record("GetCurrentBlockHeight", start, 0, 0, err)
+	return height, err
+}
+
+func (t *HostCallTracer) GetBlockAtHeight(height uint64) (block Block, exists bool, err error) {
+	start := time.Now()
+	block, exists, err = t.runtimeInterface.GetBlockAtHeight(height)
+	t.record("GetBlockAtHeight", start, 0, 0, err)
+	return block, exists, err
+}
+
+func (t *HostCallTracer) UnsafeRandom() (uint64, error) {
+	start := time.Now()
+	random, err := t.runtimeInterface.UnsafeRandom()
+	t.record("UnsafeRandom", start, 0, 0, err)
+	return random, err
+}
+
+func (t *HostCallTracer) ReadRandom(buffer []byte) error {
+	start := time.Now()
+	err := t.runtimeInterface.ReadRandom(buffer)
+	t.record("ReadRandom", start, 0, len(buffer), err)
+	return err
+}
+
+func (t *HostCallTracer) VerifySignature(
+	signature []byte,
+	tag string,
+	signedData []byte,
+	publicKey []byte,
+	signatureAlgorithm SignatureAlgorithm,
+	hashAlgorithm HashAlgorithm,
+) (bool, error) {
+	start := time.Now()
+	valid, err := t.runtimeInterface.VerifySignature(
+		signature,
+		tag,
+		signedData,
+		publicKey,
+		signatureAlgorithm,
+		hashAlgorithm,
+	)
+	argumentsSize := len(signature) + len(tag) + len(signedData) + len(publicKey)
+	t.record("VerifySignature", start, argumentsSize, 0, err)
+	return valid, err
+}
+
+func (t *HostCallTracer) Hash(data []byte, tag string, hashAlgorithm HashAlgorithm) ([]byte, error) {
+	start := time.Now()
+	digest, err := t.runtimeInterface.Hash(data, tag, hashAlgorithm)
+	t.record("Hash", start, len(data)+len(tag), len(digest), err)
+	return digest, err
+}
+
+func (t *HostCallTracer) GetAccountBalance(address common.Address) (value uint64, err error) {
+	start := time.Now()
+	value, err = t.runtimeInterface.GetAccountBalance(address)
+	t.record("GetAccountBalance", start, addressSize, 0, err)
+	return value, err
+}
+
+func (t *HostCallTracer) GetAccountAvailableBalance(address common.Address) (value uint64, err error) {
+	start := time.Now()
+	value, err = t.runtimeInterface.GetAccountAvailableBalance(address)
+	t.record("GetAccountAvailableBalance", start, addressSize, 0, err)
+	return value, err
+}
+
+func (t *HostCallTracer) GetStorageUsed(address Address) (value uint64, err error) {
+	start := time.Now()
+	value, err = t.runtimeInterface.GetStorageUsed(address)
+	t.record("GetStorageUsed", start, addressSize, 0, err)
+	return value, err
+}
+
+func (t *HostCallTracer) GetStorageCapacity(address Address) (value uint64, err error) {
+	start := time.Now()
+	value, err = t.runtimeInterface.GetStorageCapacity(address)
+	t.record("GetStorageCapacity", start, addressSize, 0, err)
+	return value, err
+}
+
+func (t *HostCallTracer) ImplementationDebugLog(message string) error {
+	start := time.Now()
+	err := t.runtimeInterface.ImplementationDebugLog(message)
+	t.record("ImplementationDebugLog", start, len(message), 0, err)
+	return err
+}
+
+func (t *HostCallTracer) ValidatePublicKey(key *PublicKey) (bool, error) {
+	start := time.Now()
+	valid, err := t.runtimeInterface.ValidatePublicKey(key)
+	t.record("ValidatePublicKey", start, publicKeySize(key), 0, err)
+	return valid, err
+}
+
+func (t *HostCallTracer) GetAccountContractNames(address Address) ([]string, error) {
+	start := time.Now()
+	names, err := t.runtimeInterface.GetAccountContractNames(address)
+	resultSize := 0
+	for _, name := range names {
+		resultSize += len(name)
+	}
+	t.record("GetAccountContractNames", start, addressSize, resultSize, err)
+	return names, err
+}
+
+func (t *HostCallTracer) RecoverPublicKey(
+	signature []byte,
+	signedData []byte,
+	signatureAlgorithm SignatureAlgorithm,
+	hashAlgorithm HashAlgorithm,
+) (publicKey []byte, err error) {
+	start := time.Now()
+	publicKey, err = t.runtimeInterface.RecoverPublicKey(
+		signature,
+		signedData,
+		signatureAlgorithm,
+		hashAlgorithm,
+	)
+	t.record("RecoverPublicKey", start, len(signature)+len(signedData), len(publicKey), err)
+	return publicKey, err
+}
+
+func (t *HostCallTracer) BLSVerifyPOP(publicKey *PublicKey, signature []byte) (bool, error) {
+	start := time.Now()
+	valid, err := t.runtimeInterface.BLSVerifyPOP(publicKey, signature)
+	t.record("BLSVerifyPOP", start, publicKeySize(publicKey)+len(signature), 0, err)
+	return valid, err
+}
+
+func (t *HostCallTracer) BLSAggregateSignatures(signatures [][]byte) ([]byte, error) {
+	start := time.Now()
+	signature, err := t.runtimeInterface.BLSAggregateSignatures(signatures)
+	argumentsSize := 0
+	for _, signature := range signatures {
+		argumentsSize += len(signature)
+	}
+	t.record("BLSAggregateSignatures", start, argumentsSize, len(signature), err)
+	return signature, err
+}
+
+func (t *HostCallTracer) BLSAggregatePublicKeys(publicKeys []*PublicKey) (*PublicKey, error) {
+	start := time.Now()
+	publicKey, err := t.runtimeInterface.BLSAggregatePublicKeys(publicKeys)
+	argumentsSize := 0
+	for _, publicKey := range publicKeys {
+		argumentsSize += publicKeySize(publicKey)
+	}
+	t.record("BLSAggregatePublicKeys", start, argumentsSize, publicKeySize(publicKey), err)
+	return publicKey, err
+}
+
+// The metrics are reported by the runtime, instead of being requested from the host environment,
+// so they are forwarded without being recorded
+
+func (t *HostCallTracer) ProgramParsed(location common.Location, duration time.Duration) {
+	if metrics, ok := t.runtimeInterface.(Metrics); ok {
+		metrics.ProgramParsed(location, duration)
+	}
+}
+
+func (t *HostCallTracer) ProgramChecked(location common.Location, duration time.Duration) {
+	if metrics, ok := t.runtimeInterface.(Metrics); ok {
+		metrics.ProgramChecked(location, duration)
+	}
+}
+
+func (t *HostCallTracer) ProgramInterpreted(location common.Location, duration time.Duration) {
+	if metrics, ok := t.runtimeInterface.(Metrics); ok {
+		metrics.ProgramInterpreted(location, duration)
+	}
+}
+
+func (t *HostCallTracer) ValueEncoded(duration time.Duration) {
+	if metrics, ok := t.runtimeInterface.(Metrics); ok {
+		metrics.ValueEncoded(duration)
+	}
+}
+
+func (t *HostCallTracer) ValueDecoded(duration time.Duration) {
+	if metrics, ok := t.runtimeInterface.(Metrics); ok {
+		metrics.ValueDecoded(duration)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// This file defines exporters for host call traces,
+// as OpenTelemetry spans and as a structured trace dump.
+
+const (
+	// HostCallSpanArgumentsSizeAttribute is the span attribute key for the arguments size of a host call
+	HostCallSpanArgumentsSizeAttribute = "cadence.host_call.arguments_size"
+	// HostCallSpanResultSizeAttribute is the span attribute key for the result size of a host call
+	HostCallSpanResultSizeAttribute = "cadence.host_call.result_size"
+)
+
+// HostCallSpan is a host call in the form of an OpenTelemetry span.
+//
+// The runtime does not depend on OpenTelemetry,
+// so the host environment creates the actual spans from these,
+// e.g. by starting a span with the start time as the timestamp,
+// setting the attributes as integer attributes,
+// recording the error and setting the error status, if any,
+// and ending the span with the end time as the timestamp.
+//
+type HostCallSpan struct {
+	Name       string
+	StartTime  time.Time
+	EndTime    time.Time
+	Attributes []HostCallSpanAttribute
+	Err        error
+}
+
+// HostCallSpanAttribute is an integer attribute of a host call span.
+//
+type HostCallSpanAttribute struct {
+	Key   string
+	Value int64
+}
+
+// Span returns the host call in the form of an OpenTelemetry span
+//
+func (c HostCall) Span() HostCallSpan {
+	return HostCallSpan{
+		Name:      c.Name,
+		StartTime: c.Start,
+		EndTime:   c.Start.Add(c.Duration),
+		Attributes: []HostCallSpanAttribute{
+			{
+				Key:   HostCallSpanArgumentsSizeAttribute,
+				Value: int64(c.ArgumentsSize),
+			},
+			{
+				Key:   HostCallSpanResultSizeAttribute,
+				Value: int64(c.ResultSize),
+			},
+		},
+		Err: c.Err,
+	}
+}
+
+// Spans returns the host calls of the trace in the form of OpenTelemetry spans, in order
+//
+func (t *HostCallTrace) Spans() []HostCallSpan {
+	spans := make([]HostCallSpan, len(t.Calls))
+	for i, call := range t.Calls {
+		spans[i] = call.Span()
+	}
+	return spans
+}
+
+type jsonHostCallTrace struct {
+	Calls   []jsonHostCall        `json:"calls"`
+	Summary []jsonHostCallSummary `json:"summary"`
+}
+
+type jsonHostCall struct {
+	Name          string `json:"name"`
+	Start         string `json:"start"`
+	DurationNanos int64  `json:"duration_nanos"`
+	ArgumentsSize int    `json:"arguments_size"`
+	ResultSize    int    `json:"result_size"`
+	Error         string `json:"error,omitempty"`
+}
+
+type jsonHostCallSummary struct {
+	Name          string `json:"name"`
+	Count         int    `json:"count"`
+	DurationNanos int64  `json:"duration_nanos"`
+	ArgumentsSize int    `json:"arguments_size"`
+	ResultSize    int    `json:"result_size"`
+	Errors        int    `json:"errors"`
+}
+
+// WriteJSON writes the trace as a JSON object,
+// with the host calls in order, and the summary of the calls of each function
+//
+func (t *HostCallTrace) WriteJSON(w io.Writer) error {
+	calls := make([]jsonHostCall, len(t.Calls))
+	for i, call := range t.Calls {
+		var errorMessage string
+		if call.Err != nil {
+			errorMessage = call.Err.Error()
+		}
+
+		calls[i] = jsonHostCall{
+			Name:          call.Name,
+			Start:         call.Start.UTC().Format(time.RFC3339Nano),
+			DurationNanos: call.Duration.Nanoseconds(),
+			ArgumentsSize: call.ArgumentsSize,
+			ResultSize:    call.ResultSize,
+			Error:         errorMessage,
+		}
+	}
+
+	summaries := t.Summary()
+	jsonSummaries := make([]jsonHostCallSummary, len(summaries))
+	for i, summary := range summaries {
+		jsonSummaries[i] = jsonHostCallSummary{
+			Name:          summary.Name,
+			Count:         summary.Count,
+			DurationNanos: summary.Duration.Nanoseconds(),
+			ArgumentsSize: summary.ArgumentsSize,
+			ResultSize:    summary.ResultSize,
+			Errors:        summary.Errors,
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(jsonHostCallTrace{
+		Calls:   calls,
+		Summary: jsonSummaries,
+	})
+}
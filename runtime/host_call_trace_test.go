@@ -0,0 +1,241 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestRuntimeHostCallTracer(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	var loggedMessages []string
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestStorage(nil, nil),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{address}, nil
+		},
+		log: func(message string) {
+			loggedMessages = append(loggedMessages, message)
+		},
+	}
+
+	var observedCalls []HostCall
+
+	tracer := NewHostCallTracer(
+		runtimeInterface,
+		func(call HostCall) {
+			observedCalls = append(observedCalls, call)
+		},
+	)
+
+	runtime := NewInterpreterRuntime()
+
+	err := runtime.ExecuteTransaction(
+		Script{
+			Source: []byte(`
+              transaction {
+                  prepare(signer: AuthAccount) {
+                      signer.save("hello", to: /storage/greeting)
+                      log("hello")
+                  }
+              }
+            `),
+		},
+		Context{
+			Interface: tracer,
+			Location:  utils.TestLocation,
+		},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{`"hello"`}, loggedMessages)
+
+	trace := tracer.Trace()
+
+	// All calls were observed while the execution was in progress
+
+	assert.Equal(t, trace.Calls, observedCalls)
+
+	callsByName := map[string][]HostCall{}
+	for _, call := range trace.Calls {
+		callsByName[call.Name] = append(callsByName[call.Name], call)
+	}
+
+	require.Len(t, callsByName["GetSigningAccounts"], 1)
+	assert.Equal(t, addressSize, callsByName["GetSigningAccounts"][0].ResultSize)
+
+	require.Len(t, callsByName["ProgramLog"], 1)
+	assert.Equal(t, len(`"hello"`), callsByName["ProgramLog"][0].ArgumentsSize)
+
+	require.NotEmpty(t, callsByName["SetValues"])
+	assert.Greater(t, callsByName["SetValues"][0].ArgumentsSize, 0)
+
+	// The calls are recorded in order
+
+	for i := 1; i < len(trace.Calls); i++ {
+		assert.False(t, trace.Calls[i].Start.Before(trace.Calls[i-1].Start))
+	}
+
+	summaries := trace.Summary()
+	require.Len(t, summaries, len(callsByName))
+	for _, summary := range summaries {
+		assert.Len(t, callsByName[summary.Name], summary.Count)
+	}
+
+	tracer.Reset()
+	assert.Empty(t, tracer.Trace().Calls)
+}
+
+func TestRuntimeHostCallTraceExport(t *testing.T) {
+
+	t.Parallel()
+
+	start := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	trace := &HostCallTrace{
+		Calls: []HostCall{
+			{
+				Name:          "GetValue",
+				Start:         start,
+				Duration:      2 * time.Microsecond,
+				ArgumentsSize: 10,
+				ResultSize:    100,
+			},
+			{
+				Name:          "ProgramLog",
+				Start:         start.Add(5 * time.Microsecond),
+				Duration:      10 * time.Microsecond,
+				ArgumentsSize: 5,
+				Err:           errors.New("log failed"),
+			},
+			{
+				Name:          "GetValue",
+				Start:         start.Add(20 * time.Microsecond),
+				Duration:      3 * time.Microsecond,
+				ArgumentsSize: 10,
+				ResultSize:    50,
+			},
+		},
+	}
+
+	t.Run("summary", func(t *testing.T) {
+
+		t.Parallel()
+
+		assert.Equal(t,
+			[]HostCallSummary{
+				{
+					Name:          "ProgramLog",
+					Count:         1,
+					Duration:      10 * time.Microsecond,
+					ArgumentsSize: 5,
+					Errors:        1,
+				},
+				{
+					Name:          "GetValue",
+					Count:         2,
+					Duration:      5 * time.Microsecond,
+					ArgumentsSize: 20,
+					ResultSize:    150,
+				},
+			},
+			trace.Summary(),
+		)
+	})
+
+	t.Run("spans", func(t *testing.T) {
+
+		t.Parallel()
+
+		spans := trace.Spans()
+		require.Len(t, spans, 3)
+
+		assert.Equal(t,
+			HostCallSpan{
+				Name:      "GetValue",
+				StartTime: start,
+				EndTime:   start.Add(2 * time.Microsecond),
+				Attributes: []HostCallSpanAttribute{
+					{
+						Key:   HostCallSpanArgumentsSizeAttribute,
+						Value: 10,
+					},
+					{
+						Key:   HostCallSpanResultSizeAttribute,
+						Value: 100,
+					},
+				},
+			},
+			spans[0],
+		)
+
+		assert.EqualError(t, spans[1].Err, "log failed")
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+
+		t.Parallel()
+
+		var buffer bytes.Buffer
+		err := trace.WriteJSON(&buffer)
+		require.NoError(t, err)
+
+		var result struct {
+			Calls []struct {
+				Name          string `json:"name"`
+				Start         string `json:"start"`
+				DurationNanos int64  `json:"duration_nanos"`
+				Error         string `json:"error"`
+			} `json:"calls"`
+			Summary []struct {
+				Name  string `json:"name"`
+				Count int    `json:"count"`
+			} `json:"summary"`
+		}
+
+		err = json.Unmarshal(buffer.Bytes(), &result)
+		require.NoError(t, err)
+
+		require.Len(t, result.Calls, 3)
+		assert.Equal(t, "GetValue", result.Calls[0].Name)
+		assert.Equal(t, "2021-06-01T12:00:00Z", result.Calls[0].Start)
+		assert.Equal(t, int64(2000), result.Calls[0].DurationNanos)
+		assert.Empty(t, result.Calls[0].Error)
+		assert.Equal(t, "log failed", result.Calls[1].Error)
+
+		require.Len(t, result.Summary, 2)
+		assert.Equal(t, "ProgramLog", result.Summary[0].Name)
+		assert.Equal(t, 2, result.Summary[1].Count)
+	})
+}
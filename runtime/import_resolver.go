@@ -0,0 +1,532 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/parser2"
+)
+
+// ImportResolver resolves import locations and provides the code of imported programs.
+//
+// Interface implements ImportResolver, and resolves the imports of programs executed by the runtime,
+// unless an import resolver is provided in the context
+//
+type ImportResolver interface {
+	// ResolveLocation resolves an import location.
+	ResolveLocation(identifiers []Identifier, location Location) ([]ResolvedLocation, error)
+	// GetCode returns the code at a given location
+	GetCode(location Location) ([]byte, error)
+}
+
+var _ ImportResolver = Interface(nil)
+
+// UnsupportedImportLocationError is returned by import resolvers
+// for locations which they can not resolve
+//
+type UnsupportedImportLocationError struct {
+	Location Location
+}
+
+func (e UnsupportedImportLocationError) Error() string {
+	return fmt.Sprintf("unsupported import location: %s", e.Location)
+}
+
+// identityLocationResolution resolves the given location to itself
+//
+func identityLocationResolution(identifiers []Identifier, location Location) []ResolvedLocation {
+	return []ResolvedLocation{
+		{
+			Location:    location,
+			Identifiers: identifiers,
+		},
+	}
+}
+
+// ResolveAddressLocation resolves the given address import location.
+//
+// Each imported identifier is resolved as a separate address location.
+// If no specific identifiers are imported, all contracts of the account are imported,
+// as determined by the given function
+//
+func ResolveAddressLocation(
+	identifiers []Identifier,
+	location common.AddressLocation,
+	getContractNames func(address Address) ([]string, error),
+) (
+	[]ResolvedLocation,
+	error,
+) {
+	if len(identifiers) == 0 {
+		names, err := getContractNames(location.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(names) == 0 {
+			return nil, fmt.Errorf("no contracts deployed in account %s", location.Address)
+		}
+
+		identifiers = make([]Identifier, len(names))
+		for index, name := range names {
+			identifiers[index] = ast.Identifier{
+				Identifier: name,
+			}
+		}
+	}
+
+	result := make([]ResolvedLocation, len(identifiers))
+	for index, identifier := range identifiers {
+		result[index] = ResolvedLocation{
+			Location: common.AddressLocation{
+				Address: location.Address,
+				Name:    identifier.Identifier,
+			},
+			Identifiers: []ast.Identifier{
+				identifier,
+			},
+		}
+	}
+
+	return result, nil
+}
+
+// AddressImportResolver resolves address locations using the given functions,
+// e.g. by querying the accounts of a network
+//
+type AddressImportResolver struct {
+	GetContractNames func(address Address) ([]string, error)
+	GetContractCode  func(address Address, name string) ([]byte, error)
+}
+
+var _ ImportResolver = AddressImportResolver{}
+
+func (r AddressImportResolver) ResolveLocation(identifiers []Identifier, location Location) ([]ResolvedLocation, error) {
+	addressLocation, ok := location.(common.AddressLocation)
+	if !ok {
+		return nil, UnsupportedImportLocationError{Location: location}
+	}
+
+	return ResolveAddressLocation(identifiers, addressLocation, r.GetContractNames)
+}
+
+func (r AddressImportResolver) GetCode(location Location) ([]byte, error) {
+	addressLocation, ok := location.(common.AddressLocation)
+	if !ok {
+		return nil, UnsupportedImportLocationError{Location: location}
+	}
+
+	code, err := r.GetContractCode(addressLocation.Address, addressLocation.Name)
+	if err != nil {
+		return nil, err
+	}
+	if code == nil {
+		return nil, fmt.Errorf("no contract %s deployed in account %s", addressLocation.Name, addressLocation.Address)
+	}
+
+	return code, nil
+}
+
+// FileImportResolver resolves string locations as paths of files,
+// relative to the given directory.
+//
+// Imports are confined to the directory:
+// Absolute paths, and paths which refer to a file outside of the directory, are rejected
+//
+type FileImportResolver struct {
+	Directory string
+}
+
+var _ ImportResolver = FileImportResolver{}
+
+func (r FileImportResolver) ResolveLocation(identifiers []Identifier, location Location) ([]ResolvedLocation, error) {
+	if _, ok := location.(common.StringLocation); !ok {
+		return nil, UnsupportedImportLocationError{Location: location}
+	}
+
+	return identityLocationResolution(identifiers, location), nil
+}
+
+func (r FileImportResolver) GetCode(location Location) ([]byte, error) {
+	stringLocation, ok := location.(common.StringLocation)
+	if !ok {
+		return nil, UnsupportedImportLocationError{Location: location}
+	}
+
+	path := filepath.FromSlash(string(stringLocation))
+	if filepath.IsAbs(path) {
+		return nil, fmt.Errorf("cannot import absolute path %s", stringLocation)
+	}
+
+	path = filepath.Clean(path)
+	if path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("cannot import %s: path is outside of the directory", stringLocation)
+	}
+
+	return ioutil.ReadFile(filepath.Join(r.Directory, path))
+}
+
+// HTTPImportResolver resolves string locations as URLs, and fetches the code using the given client.
+//
+// If no client is given, a client with a timeout of defaultHTTPImportTimeout is used.
+// If no maximum code size is given, the code may be at most defaultHTTPImportMaxCodeSize bytes long
+//
+type HTTPImportResolver struct {
+	Client      *http.Client
+	MaxCodeSize int64
+}
+
+const defaultHTTPImportTimeout = 30 * time.Second
+
+const defaultHTTPImportMaxCodeSize = 10 * 1024 * 1024
+
+var defaultHTTPImportClient = &http.Client{
+	Timeout: defaultHTTPImportTimeout,
+}
+
+var _ ImportResolver = HTTPImportResolver{}
+
+func (r HTTPImportResolver) ResolveLocation(identifiers []Identifier, location Location) ([]ResolvedLocation, error) {
+	if _, ok := location.(common.StringLocation); !ok {
+		return nil, UnsupportedImportLocationError{Location: location}
+	}
+
+	return identityLocationResolution(identifiers, location), nil
+}
+
+func (r HTTPImportResolver) GetCode(location Location) ([]byte, error) {
+	stringLocation, ok := location.(common.StringLocation)
+	if !ok {
+		return nil, UnsupportedImportLocationError{Location: location}
+	}
+
+	client := r.Client
+	if client == nil {
+		client = defaultHTTPImportClient
+	}
+
+	maxCodeSize := r.MaxCodeSize
+	if maxCodeSize <= 0 {
+		maxCodeSize = defaultHTTPImportMaxCodeSize
+	}
+
+	response, err := client.Get(string(stringLocation))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", stringLocation, response.Status)
+	}
+
+	// Read at most one byte more than allowed, to detect code which is too large
+
+	code, err := ioutil.ReadAll(io.LimitReader(response.Body, maxCodeSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(code)) > maxCodeSize {
+		return nil, fmt.Errorf("failed to fetch %s: code exceeds the maximum size of %d bytes", stringLocation, maxCodeSize)
+	}
+
+	return code, nil
+}
+
+// RoutingImportResolver delegates to other import resolvers, based on the kind of the location.
+//
+// String locations of the form `scheme://...` or `scheme:...` are resolved
+// by the resolver registered for the scheme, if any.
+// Locations which have no resolver result in an UnsupportedImportLocationError
+//
+type RoutingImportResolver struct {
+	// Address resolves address locations
+	Address ImportResolver
+	// String resolves string locations which have no scheme, or a scheme without a resolver
+	String ImportResolver
+	// Schemes resolve string locations with a scheme, by scheme, e.g. `https`
+	Schemes map[string]ImportResolver
+	// Default resolves all other locations, e.g. identifier locations
+	Default ImportResolver
+}
+
+var _ ImportResolver = RoutingImportResolver{}
+
+func (r RoutingImportResolver) resolver(location Location) (ImportResolver, error) {
+	var resolver ImportResolver
+
+	switch location := location.(type) {
+	case common.AddressLocation:
+		resolver = r.Address
+
+	case common.StringLocation:
+		resolver = r.String
+
+		scheme := locationScheme(string(location))
+		if schemeResolver, ok := r.Schemes[scheme]; ok && scheme != "" {
+			resolver = schemeResolver
+		}
+
+	default:
+		resolver = r.Default
+	}
+
+	if resolver == nil {
+		return nil, UnsupportedImportLocationError{Location: location}
+	}
+
+	return resolver, nil
+}
+
+// locationScheme returns the scheme of the given string location, if any.
+//
+// Schemes start with a letter, and consist of at least two characters,
+// so Windows paths with drive letters are not considered to have a scheme
+//
+func locationScheme(location string) string {
+	index := strings.IndexByte(location, ':')
+	if index < 2 {
+		return ""
+	}
+
+	scheme := location[:index]
+	for i, r := range scheme {
+		switch {
+		case 'a' <= r && r <= 'z',
+			'A' <= r && r <= 'Z':
+			continue
+		case i > 0 && ('0' <= r && r <= '9' || r == '+' || r == '-' || r == '.'):
+			continue
+		default:
+			return ""
+		}
+	}
+
+	return strings.ToLower(scheme)
+}
+
+func (r RoutingImportResolver) ResolveLocation(identifiers []Identifier, location Location) ([]ResolvedLocation, error) {
+	resolver, err := r.resolver(location)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolver.ResolveLocation(identifiers, location)
+}
+
+func (r RoutingImportResolver) GetCode(location Location) ([]byte, error) {
+	resolver, err := r.resolver(location)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolver.GetCode(location)
+}
+
+// CachingImportResolver caches the code provided by another import resolver.
+// Only successfully loaded code is cached.
+//
+// It is safe for concurrent use
+//
+type CachingImportResolver struct {
+	resolver ImportResolver
+	mutex    sync.Mutex
+	codes    map[common.LocationID][]byte
+}
+
+var _ ImportResolver = &CachingImportResolver{}
+
+// NewCachingImportResolver returns a new import resolver which caches the code provided by the given resolver
+//
+func NewCachingImportResolver(resolver ImportResolver) *CachingImportResolver {
+	return &CachingImportResolver{
+		resolver: resolver,
+		codes:    map[common.LocationID][]byte{},
+	}
+}
+
+func (r *CachingImportResolver) ResolveLocation(identifiers []Identifier, location Location) ([]ResolvedLocation, error) {
+	return r.resolver.ResolveLocation(identifiers, location)
+}
+
+func (r *CachingImportResolver) GetCode(location Location) ([]byte, error) {
+	locationID := location.ID()
+
+	r.mutex.Lock()
+	code, ok := r.codes[locationID]
+	r.mutex.Unlock()
+
+	if ok {
+		return code, nil
+	}
+
+	code, err := r.resolver.GetCode(location)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	r.codes[locationID] = code
+	r.mutex.Unlock()
+
+	return code, nil
+}
+
+// Invalidate removes the cached code for the given location, if any
+//
+func (r *CachingImportResolver) Invalidate(location Location) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.codes, location.ID())
+}
+
+// ImportCycleError is returned by ResolveImports when imports are cyclic.
+//
+// The locations are the import path of the cycle,
+// starting and ending with the location which imports itself, directly or indirectly
+//
+type ImportCycleError struct {
+	Locations []Location
+}
+
+func (e ImportCycleError) Error() string {
+	var builder strings.Builder
+	builder.WriteString("cyclic import: ")
+	for i, location := range e.Locations {
+		if i > 0 {
+			builder.WriteString(" -> ")
+		}
+		builder.WriteString(location.String())
+	}
+	return builder.String()
+}
+
+// ImportParsingError is returned by ResolveImports when the code of a location cannot be parsed
+//
+type ImportParsingError struct {
+	Location Location
+	Err      error
+}
+
+func (e ImportParsingError) Error() string {
+	return fmt.Sprintf("failed to parse %s: %s", e.Location, e.Err)
+}
+
+func (e ImportParsingError) Unwrap() error {
+	return e.Err
+}
+
+// ResolveImports resolves the imports of the program at the given location, transitively,
+// using the given resolver.
+//
+// It returns the locations of all imported programs, and the given location,
+// in dependency order, i.e. each location is preceded by the locations it imports.
+// Cyclic imports result in an ImportCycleError
+//
+func ResolveImports(resolver ImportResolver, location Location) ([]Location, error) {
+	walker := importWalker{
+		resolver: resolver,
+		visited:  map[common.LocationID]bool{},
+	}
+
+	err := walker.walk(location)
+	if err != nil {
+		return nil, err
+	}
+
+	return walker.order, nil
+}
+
+type importWalker struct {
+	resolver ImportResolver
+	// visited is the set of locations which are completely resolved
+	visited map[common.LocationID]bool
+	// path is the import path to the location which is currently resolved
+	path  []Location
+	order []Location
+}
+
+func (w *importWalker) walk(location Location) error {
+	locationID := location.ID()
+
+	if w.visited[locationID] {
+		return nil
+	}
+
+	for i, pathLocation := range w.path {
+		if pathLocation.ID() == locationID {
+			cycle := make([]Location, 0, len(w.path)-i+1)
+			cycle = append(cycle, w.path[i:]...)
+			cycle = append(cycle, location)
+			return ImportCycleError{Locations: cycle}
+		}
+	}
+
+	w.path = append(w.path, location)
+	defer func() {
+		w.path = w.path[:len(w.path)-1]
+	}()
+
+	code, err := w.resolver.GetCode(location)
+	if err != nil {
+		return err
+	}
+
+	program, err := parser2.ParseProgram(string(code))
+	if err != nil {
+		return ImportParsingError{
+			Location: location,
+			Err:      err,
+		}
+	}
+
+	for _, declaration := range program.ImportDeclarations() {
+		resolvedLocations, err := w.resolver.ResolveLocation(
+			declaration.Identifiers,
+			declaration.Location,
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, resolvedLocation := range resolvedLocations {
+			err = w.walk(resolvedLocation.Location)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	w.visited[locationID] = true
+	w.order = append(w.order, location)
+
+	return nil
+}
@@ -0,0 +1,417 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// testImportResolver resolves locations to themselves,
+// and provides the code of locations from a map
+//
+type testImportResolver struct {
+	codes         map[common.LocationID]string
+	getCodeCounts map[common.LocationID]int
+}
+
+var _ ImportResolver = &testImportResolver{}
+
+func newTestImportResolver(codes map[Location]string) *testImportResolver {
+	resolver := &testImportResolver{
+		codes:         map[common.LocationID]string{},
+		getCodeCounts: map[common.LocationID]int{},
+	}
+	for location, code := range codes {
+		resolver.codes[location.ID()] = code
+	}
+	return resolver
+}
+
+func (r *testImportResolver) ResolveLocation(identifiers []Identifier, location Location) ([]ResolvedLocation, error) {
+	return identityLocationResolution(identifiers, location), nil
+}
+
+func (r *testImportResolver) GetCode(location Location) ([]byte, error) {
+	r.getCodeCounts[location.ID()]++
+
+	code, ok := r.codes[location.ID()]
+	if !ok {
+		return nil, fmt.Errorf("unknown location: %s", location)
+	}
+	return []byte(code), nil
+}
+
+func TestRoutingImportResolver(t *testing.T) {
+
+	t.Parallel()
+
+	addressLocation := common.AddressLocation{
+		Address: common.BytesToAddress([]byte{0x1}),
+		Name:    "A",
+	}
+
+	httpsLocation := common.StringLocation("https://example.com/b.cdc")
+	registryLocation := common.StringLocation("Registry:c")
+	fileLocation := common.StringLocation("./d.cdc")
+	identifierLocation := common.IdentifierLocation("e")
+
+	addressResolver := newTestImportResolver(map[Location]string{addressLocation: "a"})
+	httpsResolver := newTestImportResolver(map[Location]string{httpsLocation: "b"})
+	registryResolver := newTestImportResolver(map[Location]string{registryLocation: "c"})
+	stringResolver := newTestImportResolver(map[Location]string{fileLocation: "d"})
+
+	resolver := RoutingImportResolver{
+		Address: addressResolver,
+		String:  stringResolver,
+		Schemes: map[string]ImportResolver{
+			"https":    httpsResolver,
+			"registry": registryResolver,
+		},
+	}
+
+	for location, expected := range map[Location]string{
+		addressLocation:  "a",
+		httpsLocation:    "b",
+		registryLocation: "c",
+		fileLocation:     "d",
+	} {
+		code, err := resolver.GetCode(location)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(code))
+	}
+
+	// Without a default resolver, other locations are not supported
+
+	_, err := resolver.GetCode(identifierLocation)
+	require.Equal(t, UnsupportedImportLocationError{Location: identifierLocation}, err)
+
+	_, err = resolver.ResolveLocation(nil, identifierLocation)
+	require.Equal(t, UnsupportedImportLocationError{Location: identifierLocation}, err)
+}
+
+func TestLocationScheme(t *testing.T) {
+
+	t.Parallel()
+
+	for location, expected := range map[string]string{
+		"https://example.com/a.cdc": "https",
+		"IPFS:Qm":                   "ipfs",
+		"git+ssh:repo":              "git+ssh",
+		"./a.cdc":                   "",
+		"a.cdc":                     "",
+		":a":                        "",
+		"C:\\a.cdc":                 "",
+		"1a:b":                      "",
+		"a/b:c":                     "",
+	} {
+		assert.Equal(t, expected, locationScheme(location), location)
+	}
+}
+
+func TestAddressImportResolver(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	resolver := AddressImportResolver{
+		GetContractNames: func(_ Address) ([]string, error) {
+			return []string{"A", "B"}, nil
+		},
+		GetContractCode: func(_ Address, name string) ([]byte, error) {
+			if name == "A" {
+				return []byte("a"), nil
+			}
+			return nil, nil
+		},
+	}
+
+	resolvedLocations, err := resolver.ResolveLocation(nil, common.AddressLocation{Address: address})
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		[]ResolvedLocation{
+			{
+				Location:    common.AddressLocation{Address: address, Name: "A"},
+				Identifiers: []ast.Identifier{{Identifier: "A"}},
+			},
+			{
+				Location:    common.AddressLocation{Address: address, Name: "B"},
+				Identifiers: []ast.Identifier{{Identifier: "B"}},
+			},
+		},
+		resolvedLocations,
+	)
+
+	code, err := resolver.GetCode(common.AddressLocation{Address: address, Name: "A"})
+	require.NoError(t, err)
+	assert.Equal(t, "a", string(code))
+
+	_, err = resolver.GetCode(common.AddressLocation{Address: address, Name: "B"})
+	require.EqualError(t, err, "no contract B deployed in account 0000000000000001")
+}
+
+func TestFileImportResolver(t *testing.T) {
+
+	t.Parallel()
+
+	directory := t.TempDir()
+
+	err := ioutil.WriteFile(filepath.Join(directory, "a.cdc"), []byte("a"), 0600)
+	require.NoError(t, err)
+
+	resolver := FileImportResolver{Directory: directory}
+
+	code, err := resolver.GetCode(common.StringLocation("./a.cdc"))
+	require.NoError(t, err)
+	assert.Equal(t, "a", string(code))
+
+	_, err = resolver.GetCode(common.StringLocation("./b.cdc"))
+	require.Error(t, err)
+
+	for _, path := range []string{
+		filepath.ToSlash(filepath.Join(directory, "a.cdc")),
+		"../a.cdc",
+		"./b/../../a.cdc",
+	} {
+		_, err = resolver.GetCode(common.StringLocation(path))
+		require.Error(t, err)
+	}
+
+	code, err = resolver.GetCode(common.StringLocation("./b/../a.cdc"))
+	require.NoError(t, err)
+	assert.Equal(t, "a", string(code))
+}
+
+func TestHTTPImportResolver(t *testing.T) {
+
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a.cdc":
+			_, _ = w.Write([]byte("a"))
+		case "/ab.cdc":
+			_, _ = w.Write([]byte("ab"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	resolver := HTTPImportResolver{Client: server.Client()}
+
+	code, err := resolver.GetCode(common.StringLocation(server.URL + "/a.cdc"))
+	require.NoError(t, err)
+	assert.Equal(t, "a", string(code))
+
+	_, err = resolver.GetCode(common.StringLocation(server.URL + "/b.cdc"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+
+	limitedResolver := HTTPImportResolver{
+		Client:      server.Client(),
+		MaxCodeSize: 1,
+	}
+
+	code, err = limitedResolver.GetCode(common.StringLocation(server.URL + "/a.cdc"))
+	require.NoError(t, err)
+	assert.Equal(t, "a", string(code))
+
+	_, err = limitedResolver.GetCode(common.StringLocation(server.URL + "/ab.cdc"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "maximum size")
+}
+
+func TestCachingImportResolver(t *testing.T) {
+
+	t.Parallel()
+
+	location := common.StringLocation("a")
+
+	resolver := newTestImportResolver(map[Location]string{location: "a"})
+
+	cachingResolver := NewCachingImportResolver(resolver)
+
+	for i := 0; i < 2; i++ {
+		code, err := cachingResolver.GetCode(location)
+		require.NoError(t, err)
+		assert.Equal(t, "a", string(code))
+	}
+
+	assert.Equal(t, 1, resolver.getCodeCounts[location.ID()])
+
+	cachingResolver.Invalidate(location)
+
+	_, err := cachingResolver.GetCode(location)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, resolver.getCodeCounts[location.ID()])
+
+	// Failures are not cached
+
+	for i := 0; i < 2; i++ {
+		_, err := cachingResolver.GetCode(common.StringLocation("b"))
+		require.Error(t, err)
+	}
+
+	assert.Equal(t, 2, resolver.getCodeCounts[common.StringLocation("b").ID()])
+}
+
+func TestResolveImports(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("dependency order", func(t *testing.T) {
+
+		t.Parallel()
+
+		resolver := newTestImportResolver(map[Location]string{
+			common.StringLocation("main"): `
+              import "a"
+              import "b"
+            `,
+			common.StringLocation("a"): `
+              import "c"
+            `,
+			common.StringLocation("b"): `
+              import "c"
+            `,
+			common.StringLocation("c"): ``,
+		})
+
+		locations, err := ResolveImports(resolver, common.StringLocation("main"))
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]Location{
+				common.StringLocation("c"),
+				common.StringLocation("a"),
+				common.StringLocation("b"),
+				common.StringLocation("main"),
+			},
+			locations,
+		)
+
+		assert.Equal(t, 1, resolver.getCodeCounts[common.StringLocation("c").ID()])
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+
+		t.Parallel()
+
+		resolver := newTestImportResolver(map[Location]string{
+			common.StringLocation("main"): `
+              import "a"
+            `,
+			common.StringLocation("a"): `
+              import "b"
+            `,
+			common.StringLocation("b"): `
+              import "a"
+            `,
+		})
+
+		_, err := ResolveImports(resolver, common.StringLocation("main"))
+		require.Equal(t,
+			ImportCycleError{
+				Locations: []Location{
+					common.StringLocation("a"),
+					common.StringLocation("b"),
+					common.StringLocation("a"),
+				},
+			},
+			err,
+		)
+		assert.EqualError(t, err, "cyclic import: a -> b -> a")
+	})
+
+	t.Run("parsing error", func(t *testing.T) {
+
+		t.Parallel()
+
+		resolver := newTestImportResolver(map[Location]string{
+			common.StringLocation("main"): `
+              import "a"
+            `,
+			common.StringLocation("a"): `
+              pub fun
+            `,
+		})
+
+		_, err := ResolveImports(resolver, common.StringLocation("main"))
+		require.IsType(t, ImportParsingError{}, err)
+		assert.Equal(t, common.StringLocation("a"), err.(ImportParsingError).Location)
+	})
+}
+
+func TestRuntimeContextImportResolver(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := NewInterpreterRuntime()
+
+	resolver := newTestImportResolver(map[Location]string{
+		common.StringLocation("https://example.com/answer.cdc"): `
+          pub fun answer(): Int {
+              return 42
+          }
+        `,
+	})
+
+	script := []byte(`
+      import "https://example.com/answer.cdc"
+
+      pub fun main(): Int {
+          return answer()
+      }
+    `)
+
+	runtimeInterface := &testRuntimeInterface{
+		getCode: func(location Location) ([]byte, error) {
+			return nil, fmt.Errorf("unexpected import of %s", location)
+		},
+	}
+
+	result, err := runtime.ExecuteScript(
+		Script{
+			Source: script,
+		},
+		Context{
+			Interface:      runtimeInterface,
+			Location:       common.ScriptLocation{},
+			ImportResolver: resolver,
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, cadence.NewInt(42), result)
+}
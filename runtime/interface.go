@@ -50,6 +50,11 @@ type Interface interface {
 	GetValue(owner, key []byte) (value []byte, err error)
 	// SetValue sets a value for the given key in the storage, owned by the given account.
 	SetValue(owner, key, value []byte) (err error)
+	// GetValues gets the values for the given keys in the storage.
+	// The values are returned in the same order as the keys.
+	GetValues(keys []StorageKey) (values [][]byte, err error)
+	// SetValues sets the values for the given keys in the storage, in the given order.
+	SetValues(writes []StorageWrite) (err error)
 	// CreateAccount creates a new account.
 	CreateAccount(payer Address) (address Address, err error)
 	// AddEncodedAccountKey appends an encoded key to an account.
@@ -76,6 +81,9 @@ type Interface interface {
 	EmitEvent(cadence.Event) error
 	// ValueExists returns true if the given key exists in the storage, owned by the given account.
 	ValueExists(owner, key []byte) (exists bool, err error)
+	// GetStorageKeys returns the keys of all values in the storage, owned by the given account,
+	// which start with the given prefix.
+	GetStorageKeys(owner, prefix []byte) (keys [][]byte, err error)
 	// GenerateUUID is called to generate a UUID.
 	GenerateUUID() (uint64, error)
 	// GetComputationLimit returns the computation limit. A value <= 0 means there is no limit
@@ -91,6 +99,11 @@ type Interface interface {
 	// UnsafeRandom returns a random uint64, where the process of random number derivation is not cryptographically
 	// secure.
 	UnsafeRandom() (uint64, error)
+	// ReadRandom reads random bytes into the given buffer.
+	// The random bytes must be the same for all executions of the same transaction,
+	// and must not be predictable before the transaction is executed,
+	// e.g. they may be derived from the random source of the block and the transaction ID.
+	ReadRandom(buffer []byte) error
 	// VerifySignature returns true if the given signature was produced by signing the given tag + data
 	// using the given public key, signature algorithm, and hash algorithm.
 	VerifySignature(
@@ -117,6 +130,21 @@ type Interface interface {
 	ValidatePublicKey(key *PublicKey) (bool, error)
 	// GetAccountContractNames returns the names of all contracts deployed in an account.
 	GetAccountContractNames(address Address) ([]string, error)
+	// RecoverPublicKey recovers the raw public key which produced the given signature
+	// by signing the given data using the given signature algorithm and hash algorithm.
+	// If the public key cannot be recovered, nil is returned.
+	RecoverPublicKey(
+		signature []byte,
+		signedData []byte,
+		signatureAlgorithm SignatureAlgorithm,
+		hashAlgorithm HashAlgorithm,
+	) (publicKey []byte, err error)
+	// BLSVerifyPOP verifies a proof of possession (PoP) for the receiver public key.
+	BLSVerifyPOP(publicKey *PublicKey, signature []byte) (bool, error)
+	// BLSAggregateSignatures aggregate multiple BLS signatures into one.
+	BLSAggregateSignatures(signatures [][]byte) ([]byte, error)
+	// BLSAggregatePublicKeys aggregate multiple BLS public keys into one.
+	BLSAggregatePublicKeys(publicKeys []*PublicKey) (*PublicKey, error)
 }
 
 type Metrics interface {
@@ -167,6 +195,10 @@ func (i *emptyRuntimeInterface) ValueExists(_, _ []byte) (exists bool, err error
 	return false, nil
 }
 
+func (i *emptyRuntimeInterface) GetStorageKeys(_, _ []byte) (keys [][]byte, err error) {
+	return nil, nil
+}
+
 func (i *emptyRuntimeInterface) GetValue(_, _ []byte) (value []byte, err error) {
 	return nil, nil
 }
@@ -175,6 +207,14 @@ func (i *emptyRuntimeInterface) SetValue(_, _, _ []byte) error {
 	return nil
 }
 
+func (i *emptyRuntimeInterface) GetValues(keys []StorageKey) (values [][]byte, err error) {
+	return make([][]byte, len(keys)), nil
+}
+
+func (i *emptyRuntimeInterface) SetValues(_ []StorageWrite) error {
+	return nil
+}
+
 func (i *emptyRuntimeInterface) CreateAccount(_ Address) (address Address, err error) {
 	return Address{}, nil
 }
@@ -251,6 +291,10 @@ func (i *emptyRuntimeInterface) UnsafeRandom() (uint64, error) {
 	return 0, nil
 }
 
+func (i *emptyRuntimeInterface) ReadRandom(_ []byte) error {
+	return nil
+}
+
 func (i *emptyRuntimeInterface) ImplementationDebugLog(_ string) error {
 	return nil
 }
@@ -297,3 +341,24 @@ func (i *emptyRuntimeInterface) ValidatePublicKey(_ *PublicKey) (bool, error) {
 func (i *emptyRuntimeInterface) GetAccountContractNames(_ Address) ([]string, error) {
 	return nil, nil
 }
+
+func (i *emptyRuntimeInterface) RecoverPublicKey(
+	_ []byte,
+	_ []byte,
+	_ SignatureAlgorithm,
+	_ HashAlgorithm,
+) ([]byte, error) {
+	return nil, nil
+}
+
+func (i *emptyRuntimeInterface) BLSVerifyPOP(_ *PublicKey, _ []byte) (bool, error) {
+	return false, nil
+}
+
+func (i *emptyRuntimeInterface) BLSAggregateSignatures(_ [][]byte) ([]byte, error) {
+	return nil, nil
+}
+
+func (i *emptyRuntimeInterface) BLSAggregatePublicKeys(_ []*PublicKey) (*PublicKey, error) {
+	return nil, nil
+}
@@ -26,6 +26,13 @@ import (
 
 // AuthAccountContractsValue
 
+var authAccountContractsFieldLayout = newCompositeFieldLayout([]string{
+	sema.AuthAccountContractsTypeAddFunctionName,
+	sema.AuthAccountContractsTypeGetFunctionName,
+	sema.AuthAccountContractsTypeRemoveFunctionName,
+	sema.AuthAccountContractsTypeUpdateExperimentalFunctionName,
+})
+
 func NewAuthAccountContractsValue(
 	address AddressValue,
 	addFunction FunctionValue,
@@ -34,11 +41,11 @@ func NewAuthAccountContractsValue(
 	removeFunction FunctionValue,
 	namesGet func() *ArrayValue,
 ) *CompositeValue {
-	fields := NewStringValueOrderedMap()
-	fields.Set(sema.AuthAccountContractsTypeAddFunctionName, addFunction)
-	fields.Set(sema.AuthAccountContractsTypeGetFunctionName, getFunction)
-	fields.Set(sema.AuthAccountContractsTypeRemoveFunctionName, removeFunction)
-	fields.Set(sema.AuthAccountContractsTypeUpdateExperimentalFunctionName, updateFunction)
+	fields := newCompositeFields(authAccountContractsFieldLayout)
+	fields.set(sema.AuthAccountContractsTypeAddFunctionName, addFunction)
+	fields.set(sema.AuthAccountContractsTypeGetFunctionName, getFunction)
+	fields.set(sema.AuthAccountContractsTypeRemoveFunctionName, removeFunction)
+	fields.set(sema.AuthAccountContractsTypeUpdateExperimentalFunctionName, updateFunction)
 
 	computedFields := NewStringComputedFieldOrderedMap()
 
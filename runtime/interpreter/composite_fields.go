@@ -0,0 +1,508 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// compositeFieldLayoutIndexThreshold is the number of fields above which
+// a layout has an index table. Layouts with fewer fields look up fields by name linearly,
+// which is faster than a table lookup for small layouts.
+//
+const compositeFieldLayoutIndexThreshold = 8
+
+// compositeFieldLayout is the layout of the fields of composite values,
+// i.e. the names of the fields, and the index of each field.
+//
+// Layouts are immutable, so they are shared by all values of a composite type.
+//
+type compositeFieldLayout struct {
+	names   []string
+	indices map[string]int
+}
+
+func newCompositeFieldLayout(names []string) *compositeFieldLayout {
+	layout := &compositeFieldLayout{
+		names: names,
+	}
+
+	if len(names) > compositeFieldLayoutIndexThreshold {
+		layout.indices = make(map[string]int, len(names))
+		for i, name := range names {
+			layout.indices[name] = i
+		}
+	}
+
+	return layout
+}
+
+// newDeclaredCompositeFieldLayout returns the layout of the fields of values of the given composite type,
+// i.e. of the fields in the order of the slots which the checker assigned to them
+//
+func newDeclaredCompositeFieldLayout(compositeType *sema.CompositeType) *compositeFieldLayout {
+	return newCompositeFieldLayout(compositeType.FieldSlots())
+}
+
+// hasNames returns true if the layout has exactly the given names, in the given order
+//
+func (l *compositeFieldLayout) hasNames(names []string) bool {
+	if l == nil {
+		return len(names) == 0
+	}
+	return stringsEqual(l.names, names)
+}
+
+func (l *compositeFieldLayout) len() int {
+	if l == nil {
+		return 0
+	}
+	return len(l.names)
+}
+
+// index returns the index of the field with the given name
+//
+func (l *compositeFieldLayout) index(name string) (int, bool) {
+	if l == nil {
+		return 0, false
+	}
+
+	if l.indices != nil {
+		index, ok := l.indices[name]
+		return index, ok
+	}
+
+	for i, fieldName := range l.names {
+		if fieldName == name {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// withField returns a new layout which has an additional field with the given name, at the end
+//
+func (l *compositeFieldLayout) withField(name string) *compositeFieldLayout {
+	count := l.len()
+
+	names := make([]string, count, count+1)
+	if l != nil {
+		copy(names, l.names)
+	}
+	names = append(names, name)
+
+	return newCompositeFieldLayout(names)
+}
+
+// compositeTypeFields are the fields of a composite type:
+// The layout of the declared fields, which is shared by all values of the type,
+// and the indices of the fields of the last decoded field order,
+// so the fields of decoded values are only resolved when the order changes.
+//
+// The interpreter holds the fields of each composite type it declares in the code of the type.
+// An interpreter and its sub-interpreters are not used concurrently,
+// so the decoded field order is not synchronized.
+//
+type compositeTypeFields struct {
+	layout *compositeFieldLayout
+	// decodedNames are the names of the fields of the last decoded value, in encoded order
+	decodedNames []string
+	// decodedIndices are the indices of the decoded names in the layout
+	decodedIndices []int
+	// decodedInOrder is true if the decoded indices are increasing
+	decodedInOrder bool
+}
+
+func newCompositeTypeFields(compositeType *sema.CompositeType) *compositeTypeFields {
+	return &compositeTypeFields{
+		layout: newDeclaredCompositeFieldLayout(compositeType),
+	}
+}
+
+// decodedFields returns the fields with the given decoded names and values, in their order,
+// which use the layout of the type, if possible.
+//
+// If there are no type fields, e.g. because the type is not declared in the interpreter,
+// the fields have a layout of their own
+//
+func (t *compositeTypeFields) decodedFields(names []string, values []Value) compositeFields {
+
+	if t == nil {
+		return newCompositeFieldsFromNamesAndValues(names, values)
+	}
+
+	if !stringsEqual(t.decodedNames, names) {
+
+		// Resolve the indices of the decoded names in the layout
+
+		indices, inOrder, ok := resolveCompositeFieldIndices(t.layout, names)
+		if !ok {
+			// The decoded names do not fit the layout,
+			// e.g. because the value was encoded before the type was updated
+			return newCompositeFieldsFromNamesAndValues(names, values)
+		}
+
+		t.decodedNames = names
+		t.decodedIndices = indices
+		t.decodedInOrder = inOrder
+	}
+
+	fields := compositeFields{
+		layout:    t.layout,
+		values:    make([]Value, t.layout.len()),
+		count:     len(values),
+		lastIndex: -1,
+	}
+
+	for i, index := range t.decodedIndices {
+		fields.values[index] = values[i]
+		if index > fields.lastIndex {
+			fields.lastIndex = index
+		}
+	}
+
+	if !t.decodedInOrder {
+		fields.order = make([]int, len(t.decodedIndices))
+		copy(fields.order, t.decodedIndices)
+	}
+
+	return fields
+}
+
+// resolveCompositeFieldIndices returns the indices of the given names in the given layout,
+// and if the indices are increasing.
+// The names are not resolved if a name is not in the layout, or if a name is given multiple times
+//
+func resolveCompositeFieldIndices(layout *compositeFieldLayout, names []string) (
+	indices []int,
+	inOrder bool,
+	ok bool,
+) {
+	indices = make([]int, len(names))
+	seen := make([]bool, layout.len())
+	inOrder = true
+
+	for i, name := range names {
+		index, ok := layout.index(name)
+		if !ok || seen[index] {
+			return nil, false, false
+		}
+		seen[index] = true
+
+		if i > 0 && index < indices[i-1] {
+			inOrder = false
+		}
+		indices[i] = index
+	}
+
+	return indices, inOrder, true
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, element := range a {
+		if b[i] != element {
+			return false
+		}
+	}
+	return true
+}
+
+// compositeFields are the fields of a composite value.
+//
+// The values are stored in a slice, at the index of the field in the layout,
+// so values of the same type share the names of their fields.
+// The value of a field which is not set is nil.
+//
+// Fields are iterated in the order in which they were set, like they were in an ordered map,
+// so the encoding of values does not depend on the layout.
+// Usually fields are set in the order of the layout, so the insertion order is only recorded
+// if a field is set before a field which precedes it in the layout.
+//
+// Once the fields are requested as an ordered map, the map is the storage of the fields,
+// so changes to the map are changes to the fields.
+//
+type compositeFields struct {
+	layout *compositeFieldLayout
+	values []Value
+	// order are the indices of the set fields, in insertion order,
+	// or nil if the fields were set in the order of the layout
+	order []int
+	count int
+	// lastIndex is the index of the set field with the greatest index
+	lastIndex int
+	// orderedMap is the storage of the fields, once the fields were requested as an ordered map
+	orderedMap *StringValueOrderedMap
+}
+
+func newCompositeFields(layout *compositeFieldLayout) compositeFields {
+	return compositeFields{
+		layout:    layout,
+		values:    make([]Value, layout.len()),
+		lastIndex: -1,
+	}
+}
+
+// newCompositeFieldsFromOrderedMap returns the fields with the entries of the given map,
+// in the order of the map
+//
+func newCompositeFieldsFromOrderedMap(m *StringValueOrderedMap) compositeFields {
+	if m == nil {
+		return newCompositeFields(nil)
+	}
+
+	names := make([]string, 0, m.Len())
+	values := make([]Value, 0, m.Len())
+
+	m.Foreach(func(name string, value Value) {
+		names = append(names, name)
+		values = append(values, value)
+	})
+
+	return newCompositeFieldsFromNamesAndValues(names, values)
+}
+
+// newCompositeFieldsFromNamesAndValues returns the fields with the given names and values, in their order.
+//
+// Like for an ordered map, the value of a duplicate name replaces the value of the first occurrence
+//
+func newCompositeFieldsFromNamesAndValues(names []string, values []Value) compositeFields {
+	layout := newCompositeFieldLayout(names)
+
+	for i, name := range names {
+		index, _ := layout.index(name)
+		if index == i {
+			continue
+		}
+
+		// The names are not unique
+
+		fields := newCompositeFields(nil)
+		for i, name := range names {
+			fields.set(name, values[i])
+		}
+		return fields
+	}
+
+	return compositeFields{
+		layout:    layout,
+		values:    values,
+		count:     len(values),
+		lastIndex: len(values) - 1,
+	}
+}
+
+func (f *compositeFields) len() int {
+	if f.orderedMap != nil {
+		return f.orderedMap.Len()
+	}
+
+	return f.count
+}
+
+func (f *compositeFields) get(name string) (Value, bool) {
+	if f.orderedMap != nil {
+		return f.orderedMap.Get(name)
+	}
+
+	index, ok := f.layout.index(name)
+	if !ok {
+		return nil, false
+	}
+
+	value := f.values[index]
+	return value, value != nil
+}
+
+// getAt returns the value of the field with the given name, which has the given slot.
+//
+// If the field is not at the slot in the layout, e.g. because the value was decoded
+// and has a layout of its own, the field is looked up by name
+//
+func (f *compositeFields) getAt(slot int, name string) (Value, bool) {
+	if f.orderedMap == nil &&
+		f.layout != nil &&
+		slot >= 0 &&
+		slot < len(f.layout.names) &&
+		f.layout.names[slot] == name {
+
+		value := f.values[slot]
+		return value, value != nil
+	}
+
+	return f.get(name)
+}
+
+func (f *compositeFields) set(name string, value Value) {
+	if f.orderedMap != nil {
+		f.orderedMap.Set(name, value)
+		return
+	}
+
+	index, ok := f.layout.index(name)
+	if !ok {
+		// The field is not part of the layout, e.g. because the value was decoded,
+		// or the type of the value has no declared layout.
+		// Extend the layout, privately for this value.
+
+		f.layout = f.layout.withField(name)
+		index = len(f.values)
+		f.values = append(f.values, nil)
+	}
+
+	f.setIndex(index, value)
+}
+
+// setAt sets the value of the field with the given name, which has the given slot.
+//
+// If the field is not at the slot in the layout, the field is set by name
+//
+func (f *compositeFields) setAt(slot int, name string, value Value) {
+	if f.orderedMap == nil &&
+		f.layout != nil &&
+		slot >= 0 &&
+		slot < len(f.layout.names) &&
+		f.layout.names[slot] == name {
+
+		f.setIndex(slot, value)
+		return
+	}
+
+	f.set(name, value)
+}
+
+func (f *compositeFields) setIndex(index int, value Value) {
+	if f.values[index] != nil {
+		f.values[index] = value
+		return
+	}
+
+	f.values[index] = value
+	f.count++
+
+	if f.order != nil {
+		f.order = append(f.order, index)
+	} else if index < f.lastIndex {
+		// The field precedes a field which was set previously:
+		// Record the insertion order
+
+		order := make([]int, 0, len(f.values))
+		for i, value := range f.values {
+			if value != nil && i != index {
+				order = append(order, i)
+			}
+		}
+		f.order = append(order, index)
+	}
+
+	if index > f.lastIndex {
+		f.lastIndex = index
+	}
+}
+
+// foreach calls the given function for each field, in insertion order
+//
+func (f *compositeFields) foreach(function func(name string, value Value)) {
+	if f.orderedMap != nil {
+		f.orderedMap.Foreach(function)
+		return
+	}
+
+	if f.order != nil {
+		for _, index := range f.order {
+			function(f.layout.names[index], f.values[index])
+		}
+		return
+	}
+
+	for index, value := range f.values {
+		if value == nil {
+			continue
+		}
+		function(f.layout.names[index], value)
+	}
+}
+
+// copy returns a copy of the fields, which shares the layout.
+// The copy of fields stored in an ordered map is stored in a new ordered map
+//
+func (f *compositeFields) copy(copyValue func(Value) Value) compositeFields {
+	if f.orderedMap != nil {
+		orderedMap := NewStringValueOrderedMap()
+		f.orderedMap.Foreach(func(name string, value Value) {
+			if copyValue != nil {
+				value = copyValue(value)
+			}
+			orderedMap.Set(name, value)
+		})
+
+		return compositeFields{
+			lastIndex:  -1,
+			orderedMap: orderedMap,
+		}
+	}
+
+	values := make([]Value, len(f.values))
+	for i, value := range f.values {
+		if value == nil || copyValue == nil {
+			values[i] = value
+			continue
+		}
+		values[i] = copyValue(value)
+	}
+
+	var order []int
+	if f.order != nil {
+		order = make([]int, len(f.order))
+		copy(order, f.order)
+	}
+
+	return compositeFields{
+		layout:    f.layout,
+		values:    values,
+		order:     order,
+		count:     f.count,
+		lastIndex: f.lastIndex,
+	}
+}
+
+// toOrderedMap returns the fields as an ordered map, in insertion order.
+//
+// The map becomes the storage of the fields, so changes to the map are changes to the fields
+//
+func (f *compositeFields) toOrderedMap() *StringValueOrderedMap {
+	if f.orderedMap != nil {
+		return f.orderedMap
+	}
+
+	orderedMap := NewStringValueOrderedMap()
+	f.foreach(func(name string, value Value) {
+		orderedMap.Set(name, value)
+	})
+
+	*f = compositeFields{
+		lastIndex:  -1,
+		orderedMap: orderedMap,
+	}
+
+	return orderedMap
+}
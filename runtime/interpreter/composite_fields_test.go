@@ -0,0 +1,441 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+func compositeFieldNames(fields *compositeFields) []string {
+	var names []string
+	fields.foreach(func(name string, _ Value) {
+		names = append(names, name)
+	})
+	return names
+}
+
+func TestCompositeFields(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("declared layout", func(t *testing.T) {
+
+		t.Parallel()
+
+		members := sema.NewStringMemberOrderedMap()
+		members.Set(sema.ResourceOwnerFieldName, &sema.Member{
+			IgnoreInSerialization: true,
+		})
+
+		compositeType := &sema.CompositeType{
+			Kind: common.CompositeKindResource,
+			Fields: []string{
+				sema.ResourceOwnerFieldName,
+				sema.ResourceUUIDFieldName,
+				"a",
+				"b",
+			},
+			Members: members,
+		}
+
+		layout := newDeclaredCompositeFieldLayout(compositeType)
+
+		// The owner is not a field of resource values
+
+		assert.Equal(t,
+			[]string{sema.ResourceUUIDFieldName, "a", "b"},
+			layout.names,
+		)
+	})
+
+	t.Run("set in layout order", func(t *testing.T) {
+
+		t.Parallel()
+
+		layout := newCompositeFieldLayout([]string{"a", "b", "c"})
+		fields := newCompositeFields(layout)
+
+		fields.set("a", NewIntValueFromInt64(1))
+		fields.set("c", NewIntValueFromInt64(3))
+
+		assert.Equal(t, 2, fields.len())
+		assert.Nil(t, fields.order)
+		assert.Equal(t, []string{"a", "c"}, compositeFieldNames(&fields))
+
+		value, ok := fields.get("c")
+		require.True(t, ok)
+		assert.Equal(t, NewIntValueFromInt64(3), value)
+
+		_, ok = fields.get("b")
+		assert.False(t, ok)
+
+		_, ok = fields.get("d")
+		assert.False(t, ok)
+	})
+
+	t.Run("set out of layout order", func(t *testing.T) {
+
+		t.Parallel()
+
+		layout := newCompositeFieldLayout([]string{"a", "b", "c"})
+		fields := newCompositeFields(layout)
+
+		fields.set("c", NewIntValueFromInt64(3))
+		fields.set("a", NewIntValueFromInt64(1))
+		fields.set("b", NewIntValueFromInt64(2))
+		fields.set("c", NewIntValueFromInt64(4))
+
+		assert.Equal(t, 3, fields.len())
+		assert.Equal(t, []string{"c", "a", "b"}, compositeFieldNames(&fields))
+
+		value, ok := fields.get("c")
+		require.True(t, ok)
+		assert.Equal(t, NewIntValueFromInt64(4), value)
+	})
+
+	t.Run("set undeclared field", func(t *testing.T) {
+
+		t.Parallel()
+
+		layout := newCompositeFieldLayout([]string{"a"})
+		fields := newCompositeFields(layout)
+
+		fields.set("b", NewIntValueFromInt64(2))
+		fields.set("a", NewIntValueFromInt64(1))
+
+		assert.Equal(t, []string{"b", "a"}, compositeFieldNames(&fields))
+
+		// The shared layout must not be modified
+
+		assert.Equal(t, []string{"a"}, layout.names)
+
+		otherFields := newCompositeFields(layout)
+		_, ok := otherFields.get("b")
+		assert.False(t, ok)
+	})
+
+	t.Run("large layout", func(t *testing.T) {
+
+		t.Parallel()
+
+		names := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+		require.Greater(t, len(names), compositeFieldLayoutIndexThreshold)
+
+		layout := newCompositeFieldLayout(names)
+		require.NotNil(t, layout.indices)
+
+		fields := newCompositeFields(layout)
+		for i, name := range names {
+			fields.set(name, NewIntValueFromInt64(int64(i)))
+		}
+
+		for i, name := range names {
+			value, ok := fields.get(name)
+			require.True(t, ok)
+			assert.Equal(t, NewIntValueFromInt64(int64(i)), value)
+		}
+	})
+
+	t.Run("duplicate names", func(t *testing.T) {
+
+		t.Parallel()
+
+		fields := newCompositeFieldsFromNamesAndValues(
+			[]string{"a", "b", "a"},
+			[]Value{
+				NewIntValueFromInt64(1),
+				NewIntValueFromInt64(2),
+				NewIntValueFromInt64(3),
+			},
+		)
+
+		assert.Equal(t, 2, fields.len())
+		assert.Equal(t, []string{"a", "b"}, compositeFieldNames(&fields))
+
+		value, ok := fields.get("a")
+		require.True(t, ok)
+		assert.Equal(t, NewIntValueFromInt64(3), value)
+	})
+
+	t.Run("copy", func(t *testing.T) {
+
+		t.Parallel()
+
+		layout := newCompositeFieldLayout([]string{"a", "b"})
+		fields := newCompositeFields(layout)
+		fields.set("b", NewIntValueFromInt64(2))
+		fields.set("a", NewIntValueFromInt64(1))
+
+		fieldsCopy := fields.copy(nil)
+		fieldsCopy.set("a", NewIntValueFromInt64(3))
+
+		value, ok := fields.get("a")
+		require.True(t, ok)
+		assert.Equal(t, NewIntValueFromInt64(1), value)
+
+		assert.Equal(t, []string{"b", "a"}, compositeFieldNames(&fieldsCopy))
+	})
+}
+
+func TestCompositeFieldSlots(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("slot in layout", func(t *testing.T) {
+
+		t.Parallel()
+
+		layout := newCompositeFieldLayout([]string{"a", "b"})
+		fields := newCompositeFields(layout)
+
+		fields.setAt(1, "b", NewIntValueFromInt64(2))
+		fields.setAt(0, "a", NewIntValueFromInt64(1))
+
+		value, ok := fields.getAt(1, "b")
+		require.True(t, ok)
+		assert.Equal(t, NewIntValueFromInt64(2), value)
+
+		assert.Equal(t, []string{"b", "a"}, compositeFieldNames(&fields))
+	})
+
+	t.Run("slot not in layout", func(t *testing.T) {
+
+		t.Parallel()
+
+		// The fields have a layout of their own, e.g. because they were decoded
+
+		layout := newCompositeFieldLayout([]string{"b", "a"})
+		fields := newCompositeFields(layout)
+
+		fields.setAt(0, "a", NewIntValueFromInt64(1))
+		fields.setAt(2, "c", NewIntValueFromInt64(3))
+
+		value, ok := fields.getAt(0, "a")
+		require.True(t, ok)
+		assert.Equal(t, NewIntValueFromInt64(1), value)
+
+		value, ok = fields.getAt(2, "c")
+		require.True(t, ok)
+		assert.Equal(t, NewIntValueFromInt64(3), value)
+
+		_, ok = fields.getAt(0, "b")
+		assert.False(t, ok)
+
+		assert.Equal(t, []string{"a", "c"}, compositeFieldNames(&fields))
+	})
+}
+
+func TestCompositeFieldsOrderedMap(t *testing.T) {
+
+	t.Parallel()
+
+	layout := newCompositeFieldLayout([]string{"a", "b"})
+	fields := newCompositeFields(layout)
+	fields.set("b", NewIntValueFromInt64(2))
+	fields.set("a", NewIntValueFromInt64(1))
+
+	orderedMap := fields.toOrderedMap()
+	assert.Same(t, orderedMap, fields.toOrderedMap())
+
+	assert.Equal(t, 2, orderedMap.Len())
+	assert.Equal(t, []string{"b", "a"}, compositeFieldNames(&fields))
+
+	// Changes to the map are changes to the fields
+
+	orderedMap.Set("c", NewIntValueFromInt64(3))
+	orderedMap.Delete("b")
+
+	assert.Equal(t, 2, fields.len())
+	assert.Equal(t, []string{"a", "c"}, compositeFieldNames(&fields))
+
+	// Changes to the fields are changes to the map
+
+	fields.setAt(1, "b", NewIntValueFromInt64(4))
+
+	value, ok := orderedMap.Get("b")
+	require.True(t, ok)
+	assert.Equal(t, NewIntValueFromInt64(4), value)
+
+	// The copy has a map of its own
+
+	fieldsCopy := fields.copy(nil)
+	fieldsCopy.set("d", NewIntValueFromInt64(5))
+
+	_, ok = orderedMap.Get("d")
+	assert.False(t, ok)
+}
+
+func TestCompositeTypeFields(t *testing.T) {
+
+	t.Parallel()
+
+	newValues := func(count int) []Value {
+		values := make([]Value, count)
+		for i := range values {
+			values[i] = NewIntValueFromInt64(int64(i))
+		}
+		return values
+	}
+
+	newTypeFields := func() *compositeTypeFields {
+		return newCompositeTypeFields(&sema.CompositeType{
+			Kind:   common.CompositeKindStructure,
+			Fields: []string{"a", "b", "c"},
+		})
+	}
+
+	t.Run("decoded values use declared layout", func(t *testing.T) {
+
+		t.Parallel()
+
+		typeFields := newTypeFields()
+
+		fields := typeFields.decodedFields([]string{"c", "a"}, newValues(2))
+
+		assert.Same(t, typeFields.layout, fields.layout)
+		assert.Equal(t, 2, fields.len())
+		assert.Equal(t, []string{"c", "a"}, compositeFieldNames(&fields))
+
+		value, ok := fields.get("a")
+		require.True(t, ok)
+		assert.Equal(t, NewIntValueFromInt64(1), value)
+
+		_, ok = fields.get("b")
+		require.False(t, ok)
+
+		// Setting a field of one value does not affect the other
+
+		fields.set("b", NewIntValueFromInt64(2))
+
+		otherFields := typeFields.decodedFields([]string{"c", "a"}, newValues(2))
+
+		assert.Same(t, typeFields.layout, otherFields.layout)
+		assert.Equal(t, []string{"c", "a"}, compositeFieldNames(&otherFields))
+	})
+
+	t.Run("decoded fields not in declared layout", func(t *testing.T) {
+
+		t.Parallel()
+
+		typeFields := newTypeFields()
+
+		fields := typeFields.decodedFields([]string{"a", "d"}, newValues(2))
+
+		assert.NotSame(t, typeFields.layout, fields.layout)
+		assert.Equal(t, []string{"a", "d"}, compositeFieldNames(&fields))
+	})
+
+	t.Run("no type fields", func(t *testing.T) {
+
+		t.Parallel()
+
+		var typeFields *compositeTypeFields
+
+		fields := typeFields.decodedFields([]string{"a", "b"}, newValues(2))
+
+		assert.Equal(t, []string{"a", "b"}, compositeFieldNames(&fields))
+	})
+}
+
+func TestDecodedCompositeValuesShareLayout(t *testing.T) {
+
+	t.Parallel()
+
+	value := newTestCompositeValue(common.Address{})
+	value.SetField("a", NewIntValueFromInt64(1))
+	value.SetField("b", BoolValue(true))
+
+	encoded, _, err := EncodeValue(value, nil, true, nil)
+	require.NoError(t, err)
+
+	decode := func() *CompositeValue {
+		decoded, err := DecodeValue(encoded, &testOwner, nil, CurrentEncodingVersion, nil)
+		require.NoError(t, err)
+
+		require.IsType(t, &CompositeValue{}, decoded)
+		return decoded.(*CompositeValue)
+	}
+
+	typeFields := newCompositeTypeFields(&sema.CompositeType{
+		Kind:   common.CompositeKindStructure,
+		Fields: []string{"a", "b"},
+	})
+
+	inter := &Interpreter{
+		typeCodes: TypeCodes{
+			CompositeCodes: map[sema.TypeID]CompositeTypeCode{
+				value.TypeID(): {
+					fields: typeFields,
+				},
+			},
+		},
+	}
+
+	// Values decoded for an interpreter which declares the type share the layout of the type
+
+	first := decode()
+	first.ensureFieldsLoadedWithTypeFields(inter)
+
+	second := decode()
+	second.ensureFieldsLoadedWithTypeFields(inter)
+
+	assert.Same(t, typeFields.layout, first.fields.layout)
+	assert.Same(t, typeFields.layout, second.fields.layout)
+
+	// Values decoded without an interpreter have a layout of their own
+
+	third := decode()
+	require.Equal(t, 2, third.FieldCount())
+
+	assert.NotSame(t, typeFields.layout, third.fields.layout)
+	assert.Equal(t, BoolValue(true), third.GetField("b"))
+}
+
+func TestCompositeValueFields(t *testing.T) {
+
+	t.Parallel()
+
+	value := newTestCompositeValue(common.Address{})
+	value.SetField("a", NewIntValueFromInt64(1))
+
+	fields := value.Fields()
+
+	fieldValue, ok := fields.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, NewIntValueFromInt64(1), fieldValue)
+
+	// Changes to the map are changes to the fields of the value
+
+	fields.Set("b", BoolValue(true))
+
+	assert.Equal(t, BoolValue(true), value.GetField("b"))
+	assert.Equal(t, 2, value.FieldCount())
+
+	value.SetField("a", NewIntValueFromInt64(2))
+
+	fieldValue, ok = fields.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, NewIntValueFromInt64(2), fieldValue)
+}
@@ -1643,8 +1643,9 @@ func decodeCompositeMetaInfo(v *CompositeValue, content []byte) error {
 }
 
 // decodeCompositeFields decodes fields from the byte content and updates the composite value.
+// If the type fields are given, the fields use the layout of the type, if possible.
 //
-func decodeCompositeFields(v *CompositeValue, content []byte) error {
+func decodeCompositeFields(v *CompositeValue, content []byte, typeFields *compositeTypeFields) error {
 	d, err := NewByteDecoder(content, v.Owner, v.encodingVersion, v.decodeCallback)
 	if err != nil {
 		return err
@@ -1671,7 +1672,9 @@ func decodeCompositeFields(v *CompositeValue, content []byte) error {
 		)
 	}
 
-	fields := NewStringValueOrderedMap()
+	fieldCount := int(fieldsSize / 2)
+	fieldNames := make([]string, 0, fieldCount)
+	fieldValues := make([]Value, 0, fieldCount)
 
 	// Pre-allocate and reuse valuePath.
 	//nolint:gocritic
@@ -1709,10 +1712,11 @@ func decodeCompositeFields(v *CompositeValue, content []byte) error {
 			)
 		}
 
-		fields.Set(fieldName, decodedValue)
+		fieldNames = append(fieldNames, fieldName)
+		fieldValues = append(fieldValues, decodedValue)
 	}
 
-	v.fields = fields
+	v.fields = typeFields.decodedFields(fieldNames, fieldValues)
 
 	return nil
 }
@@ -78,16 +78,10 @@ func TestCompositeDeferredDecoding(t *testing.T) {
 
 		// Check all the fields using getters
 
-		decodedFields := compositeValue.Fields()
-		require.Equal(t, 2, decodedFields.Len())
+		require.Equal(t, 2, compositeValue.FieldCount())
 
-		decodeFieldValue, contains := decodedFields.Get("a")
-		assert.True(t, contains)
-		assert.Equal(t, NewStringValue("hello"), decodeFieldValue)
-
-		decodeFieldValue, contains = decodedFields.Get("b")
-		assert.True(t, contains)
-		assert.Equal(t, BoolValue(true), decodeFieldValue)
+		assert.Equal(t, NewStringValue("hello"), compositeValue.GetField("a"))
+		assert.Equal(t, BoolValue(true), compositeValue.GetField("b"))
 
 		// Once all the fields are loaded, the fields raw content must be cleared
 		assert.Nil(t, compositeValue.fieldsContent)
@@ -105,8 +99,7 @@ func TestCompositeDeferredDecoding(t *testing.T) {
 		require.IsType(t, &CompositeValue{}, decoded)
 		compositeValue := decoded.(*CompositeValue)
 
-		address, ok := compositeValue.Fields().Get("address")
-		assert.True(t, ok)
+		address := compositeValue.GetField("address")
 
 		require.IsType(t, &CompositeValue{}, address)
 		nestedCompositeValue := address.(*CompositeValue)
@@ -134,9 +127,7 @@ func TestCompositeDeferredDecoding(t *testing.T) {
 		assert.Nil(t, compositeValue.content)
 
 		// check updated value
-		fieldValue, contains := compositeValue.Fields().Get("status")
-		assert.True(t, contains)
-		assert.Equal(t, newValue, fieldValue)
+		assert.Equal(t, newValue, compositeValue.GetField("status"))
 	})
 
 	t.Run("Round trip - without loading", func(t *testing.T) {
@@ -185,16 +176,10 @@ func TestCompositeDeferredDecoding(t *testing.T) {
 
 		// Check the fields
 
-		decodedFields := compositeValue.Fields()
-		require.Equal(t, 2, decodedFields.Len())
-
-		decodeFieldValue, contains := decodedFields.Get("a")
-		assert.True(t, contains)
-		assert.Equal(t, NewStringValue("hello"), decodeFieldValue)
+		require.Equal(t, 2, compositeValue.FieldCount())
 
-		decodeFieldValue, contains = decodedFields.Get("b")
-		assert.True(t, contains)
-		assert.Equal(t, BoolValue(true), decodeFieldValue)
+		assert.Equal(t, NewStringValue("hello"), compositeValue.GetField("a"))
+		assert.Equal(t, BoolValue(true), compositeValue.GetField("b"))
 	})
 
 	t.Run("Round trip - partially loaded", func(t *testing.T) {
@@ -249,16 +234,10 @@ func TestCompositeDeferredDecoding(t *testing.T) {
 
 		// Check the fields
 
-		decodedFields := compositeValue.Fields()
-		require.Equal(t, 2, decodedFields.Len())
+		require.Equal(t, 2, compositeValue.FieldCount())
 
-		decodeFieldValue, contains := decodedFields.Get("a")
-		assert.True(t, contains)
-		assert.Equal(t, NewStringValue("hello"), decodeFieldValue)
-
-		decodeFieldValue, contains = decodedFields.Get("b")
-		assert.True(t, contains)
-		assert.Equal(t, BoolValue(true), decodeFieldValue)
+		assert.Equal(t, NewStringValue("hello"), compositeValue.GetField("a"))
+		assert.Equal(t, BoolValue(true), compositeValue.GetField("b"))
 	})
 
 	t.Run("callback", func(t *testing.T) {
@@ -317,7 +296,7 @@ func TestCompositeDeferredDecoding(t *testing.T) {
 		assert.Equal(t, []string{}, decodeCallbacks[0].path)
 
 		// Load fields
-		compositeValue.Fields()
+		compositeValue.FieldCount()
 
 		// Callback must now have all three values
 		require.Len(t, decodeCallbacks, 3)
@@ -417,7 +396,7 @@ func TestCompositeDeferredDecoding(t *testing.T) {
 		assert.True(t, compositeValue.IsStorable())
 
 		// fields must not be loaded
-		assert.Nil(t, compositeValue.fields)
+		assert.Nil(t, compositeValue.fields.values)
 		assert.Nil(t, compositeValue.content)
 		assert.NotNil(t, compositeValue.fieldsContent)
 	})
@@ -460,8 +439,7 @@ func BenchmarkCompositeDeferredDecoding(b *testing.B) {
 			require.NoError(b, err)
 
 			composite := decoded.(*CompositeValue)
-			_, ok := composite.Fields().Get("fname")
-			require.True(b, ok)
+			require.NotNil(b, composite.GetField("fname"))
 		}
 	})
 
@@ -599,12 +577,9 @@ func TestArrayDeferredDecoding(t *testing.T) {
 			require.IsType(t, &CompositeValue{}, element)
 			elementVal := element.(*CompositeValue)
 
-			decodeFieldValue, contains := elementVal.Fields().Get("fname")
-			assert.True(t, contains)
-
 			expected := NewStringValue(fmt.Sprintf("John%d", i))
 
-			assert.Equal(t, expected, decodeFieldValue)
+			assert.Equal(t, expected, elementVal.GetField("fname"))
 		}
 
 	})
@@ -1065,8 +1065,10 @@ func (e *Encoder) encodeCompositeValue(
 			return err
 		}
 	} else {
-		fields := v.Fields()
-		err = e.enc.EncodeArrayHead(uint64(fields.Len() * 2))
+		v.ensureFieldsLoaded()
+		fields := &v.fields
+
+		err = e.enc.EncodeArrayHead(uint64(fields.len() * 2))
 		if err != nil {
 			return err
 		}
@@ -1077,24 +1079,24 @@ func (e *Encoder) encodeCompositeValue(
 
 		lastValuePathIndex := len(path)
 
-		for pair := fields.Oldest(); pair != nil; pair = pair.Next() {
-			fieldName := pair.Key
+		fields.foreach(func(fieldName string, value Value) {
+			if err != nil {
+				return
+			}
 
 			// Encode field name as fields array element
-			err := e.enc.EncodeString(fieldName)
+			err = e.enc.EncodeString(fieldName)
 			if err != nil {
-				return err
+				return
 			}
 
-			value := pair.Value
-
 			valuePath[lastValuePathIndex] = fieldName
 
 			// Encode value as fields array element
 			err = e.Encode(value, valuePath, deferrals)
-			if err != nil {
-				return err
-			}
+		})
+		if err != nil {
+			return err
 		}
 	}
 
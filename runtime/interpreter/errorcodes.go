@@ -0,0 +1,79 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"github.com/onflow/cadence/runtime/errors"
+)
+
+// The error codes of the errors of this package.
+//
+// NOTE: Codes must never be changed or reused, new errors must be assigned new codes.
+//
+func init() {
+	errors.RegisterErrorCodes(
+		errors.ErrorCategoryUser,
+		map[errors.ErrorCode]error{
+			3002: NotDeclaredError{},
+			3003: NotInvokableError{},
+			3004: ArgumentCountError{},
+			3005: TransactionNotDeclaredError{},
+			3006: ConditionError{},
+			3007: RedeclarationError{},
+			3008: DereferenceError{},
+			3009: OverflowError{},
+			3010: UnderflowError{},
+			3011: DivisionByZeroError{},
+			3012: DestroyedCompositeError{},
+			3013: ForceAssignmentToNonNilResourceError{},
+			3014: ForceNilError{},
+			3015: TypeMismatchError{},
+			3016: InvalidPathDomainError{},
+			3017: OverwriteError{},
+			3018: CyclicLinkError{},
+			3019: ArrayIndexOutOfBoundsError{},
+			3020: StringIndexOutOfBoundsError{},
+			3021: EventEmissionUnavailableError{},
+			3022: UUIDUnavailableError{},
+			3025: MissingMemberValueError{},
+			3026: InvocationArgumentTypeError{},
+			3027: ValueTransferTypeError{},
+			3028: ResourceConstructionError{},
+			3029: InvalidBase64StringError{},
+			3030: InvalidFormatStringError{},
+			3031: ContainerMutatedDuringIterationError{},
+			3032: IrreversibleSavepointRollbackError{},
+		},
+	)
+
+	errors.RegisterErrorCodes(
+		errors.ErrorCategoryInternal,
+		map[errors.ErrorCode]error{
+			3023: TypeLoadingError{},
+			3024: EncodingUnsupportedValueError{},
+		},
+	)
+
+	errors.RegisterErrorCodes(
+		errors.ErrorCategoryExternal,
+		map[errors.ErrorCode]error{
+			3001: ExternalError{},
+		},
+	)
+}
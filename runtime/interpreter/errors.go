@@ -19,11 +19,14 @@
 package interpreter
 
 import (
+	goErrors "errors"
 	"fmt"
+	goRuntime "runtime"
 	"strings"
 
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
 	"github.com/onflow/cadence/runtime/sema"
 )
 
@@ -61,6 +64,10 @@ func (e Error) ChildErrors() []error {
 	return []error{e.Err}
 }
 
+func (e Error) ErrorCode() errors.ErrorCode {
+	return errors.GetErrorCode(e.Err)
+}
+
 func (e Error) ImportLocation() common.Location {
 	return e.Location
 }
@@ -80,6 +87,10 @@ func (e PositionedError) Error() string {
 	return e.Err.Error()
 }
 
+func (e PositionedError) ErrorCode() errors.ErrorCode {
+	return errors.GetErrorCode(e.Err)
+}
+
 // ExternalError is an error that occurred externally.
 // It contains the recovered value.
 //
@@ -91,6 +102,46 @@ func (e ExternalError) Error() string {
 	return fmt.Sprint(e.Recovered)
 }
 
+// UnrecoverableError is an error which always aborts the program,
+// even when it occurs during the evaluation of a try expression,
+// e.g. when the computation limit is exceeded.
+//
+type UnrecoverableError interface {
+	error
+	IsUnrecoverable()
+}
+
+// isRecoverableError returns true if the given recovered panic value
+// is an error which the program can recover from in a try expression.
+//
+// Go runtime errors, external errors, internal errors,
+// and unrecoverable errors cannot be recovered from.
+//
+func isRecoverableError(recovered interface{}) bool {
+	err, ok := recovered.(error)
+	if !ok {
+		return false
+	}
+
+	var goRuntimeError goRuntime.Error
+	if goErrors.As(err, &goRuntimeError) {
+		return false
+	}
+
+	var externalError ExternalError
+	if goErrors.As(err, &externalError) {
+		return false
+	}
+
+	var unreachableError *errors.UnreachableError
+	if goErrors.As(err, &unreachableError) {
+		return false
+	}
+
+	var unrecoverableError UnrecoverableError
+	return !goErrors.As(err, &unrecoverableError)
+}
+
 // NotDeclaredError
 
 type NotDeclaredError struct {
@@ -452,3 +503,53 @@ func (e ResourceConstructionError) Error() string {
 		e.CompositeType.Location.String(),
 	)
 }
+
+// InvalidBase64StringError
+//
+type InvalidBase64StringError struct {
+	Err error
+	LocationRange
+}
+
+func (e InvalidBase64StringError) Unwrap() error {
+	return e.Err
+}
+
+func (e InvalidBase64StringError) Error() string {
+	return fmt.Sprintf("invalid Base64 string: %s", e.Err.Error())
+}
+
+// InvalidFormatStringError
+//
+type InvalidFormatStringError struct {
+	Err error
+	LocationRange
+}
+
+func (e InvalidFormatStringError) Unwrap() error {
+	return e.Err
+}
+
+func (e InvalidFormatStringError) Error() string {
+	return fmt.Sprintf("invalid format string: %s", e.Err.Error())
+}
+
+// ContainerMutatedDuringIterationError
+//
+type ContainerMutatedDuringIterationError struct {
+	LocationRange
+}
+
+func (ContainerMutatedDuringIterationError) Error() string {
+	return "container was mutated during iteration"
+}
+
+// IrreversibleSavepointRollbackError
+//
+type IrreversibleSavepointRollbackError struct {
+	LocationRange
+}
+
+func (IrreversibleSavepointRollbackError) Error() string {
+	return "cannot roll back savepoint: an effect occurred which cannot be reverted"
+}
@@ -39,7 +39,7 @@ func TestInspectValue(t *testing.T) {
 	optionalValue := NewSomeValueOwningNonCopying(arrayValue)
 
 	compositeValue := newTestCompositeValue(common.Address{})
-	compositeValue.Fields().Set("value", optionalValue)
+	compositeValue.SetField("value", optionalValue)
 
 	t.Run("dict", func(t *testing.T) {
 
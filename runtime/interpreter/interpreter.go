@@ -19,6 +19,7 @@
 package interpreter
 
 import (
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"math"
@@ -125,6 +126,25 @@ type StorageWriteHandlerFunc func(
 	value OptionalValue,
 )
 
+// StoragePrefetchHandlerFunc is a function that handles the announcement
+// that the values for the given keys will be read soon,
+// e.g. so that the values can be read from storage at once.
+//
+type StoragePrefetchHandlerFunc func(
+	inter *Interpreter,
+	storageAddress common.Address,
+	keys []string,
+)
+
+// StoredPathsHandlerFunc is a function that returns the paths of all values
+// stored in the given domain of an account, in order.
+//
+type StoredPathsHandlerFunc func(
+	inter *Interpreter,
+	storageAddress common.Address,
+	domain common.PathDomain,
+) []PathValue
+
 // InjectedCompositeFieldsHandlerFunc is a function that handles storage reads.
 //
 type InjectedCompositeFieldsHandlerFunc func(
@@ -179,6 +199,30 @@ type HashHandlerFunc func(
 	hashAlgorithm *CompositeValue,
 ) *ArrayValue
 
+// PublicKeyRecoveryHandlerFunc is a function that recovers the public key
+// which produced the given signature for the given data.
+type PublicKeyRecoveryHandlerFunc func(
+	signature *ArrayValue,
+	signedData *ArrayValue,
+	hashAlgorithm *CompositeValue,
+) OptionalValue
+
+// BLSVerifyPoPHandlerFunc is a function that verifies a BLS proof of possession.
+type BLSVerifyPoPHandlerFunc func(
+	publicKey *CompositeValue,
+	signature *ArrayValue,
+) BoolValue
+
+// BLSAggregateSignaturesHandlerFunc is a function that aggregates multiple BLS signatures.
+type BLSAggregateSignaturesHandlerFunc func(
+	signatures *ArrayValue,
+) OptionalValue
+
+// BLSAggregatePublicKeysHandlerFunc is a function that aggregates multiple BLS public keys.
+type BLSAggregatePublicKeysHandlerFunc func(
+	publicKeys *ArrayValue,
+) OptionalValue
+
 // ExitHandlerFunc is a function that is called at the end of execution
 type ExitHandlerFunc func() error
 
@@ -192,6 +236,8 @@ type ExitHandlerFunc func() error
 type CompositeTypeCode struct {
 	CompositeFunctions map[string]FunctionValue
 	DestructorFunction FunctionValue
+	// fields are the fields of the composite type, shared by all values of the type
+	fields *compositeTypeFields
 }
 
 type FunctionWrapper = func(inner FunctionValue) FunctionValue
@@ -252,6 +298,8 @@ type Interpreter struct {
 	storageExistenceHandler        StorageExistenceHandlerFunc
 	storageReadHandler             StorageReadHandlerFunc
 	storageWriteHandler            StorageWriteHandlerFunc
+	storedPathsHandler             StoredPathsHandlerFunc
+	storagePrefetchHandler         StoragePrefetchHandlerFunc
 	injectedCompositeFieldsHandler InjectedCompositeFieldsHandlerFunc
 	contractValueHandler           ContractValueHandlerFunc
 	importLocationHandler          ImportLocationHandlerFunc
@@ -260,9 +308,15 @@ type Interpreter struct {
 	PublicKeyValidationHandler     PublicKeyValidationHandlerFunc
 	SignatureVerificationHandler   SignatureVerificationHandlerFunc
 	HashHandler                    HashHandlerFunc
+	PublicKeyRecoveryHandler       PublicKeyRecoveryHandlerFunc
+	BLSVerifyPoPHandler            BLSVerifyPoPHandlerFunc
+	BLSAggregateSignaturesHandler  BLSAggregateSignaturesHandlerFunc
+	BLSAggregatePublicKeysHandler  BLSAggregatePublicKeysHandlerFunc
 	ExitHandler                    ExitHandlerFunc
 	interpreted                    bool
 	statement                      ast.Statement
+	journal                        *journal
+	typeInterner                   *sema.TypeInterner
 }
 
 type Option func(*Interpreter) error
@@ -348,6 +402,26 @@ func WithStorageReadHandler(handler StorageReadHandlerFunc) Option {
 	}
 }
 
+// WithStoragePrefetchHandler returns an interpreter option which sets the given function
+// as the function that is used when the values for multiple keys are about to be read.
+//
+func WithStoragePrefetchHandler(handler StoragePrefetchHandlerFunc) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetStoragePrefetchHandler(handler)
+		return nil
+	}
+}
+
+// WithStoredPathsHandler returns an interpreter option which sets the given function
+// as the function that is used when the stored paths of an account are iterated.
+//
+func WithStoredPathsHandler(handler StoredPathsHandlerFunc) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetStoredPathsHandler(handler)
+		return nil
+	}
+}
+
 // WithStorageWriteHandler returns an interpreter option which sets the given function
 // as the function that is used when a stored value is written.
 //
@@ -438,6 +512,46 @@ func WithHashHandler(handler HashHandlerFunc) Option {
 	}
 }
 
+// WithPublicKeyRecoveryHandler returns an interpreter option which sets the given
+// function as the function that is used to recover public keys from signatures.
+//
+func WithPublicKeyRecoveryHandler(handler PublicKeyRecoveryHandlerFunc) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetPublicKeyRecoveryHandler(handler)
+		return nil
+	}
+}
+
+// WithBLSVerifyPoPHandler returns an interpreter option which sets the given
+// function as the function that is used to verify BLS proofs of possession.
+//
+func WithBLSVerifyPoPHandler(handler BLSVerifyPoPHandlerFunc) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetBLSVerifyPoPHandler(handler)
+		return nil
+	}
+}
+
+// WithBLSAggregateSignaturesHandler returns an interpreter option which sets the given
+// function as the function that is used to aggregate BLS signatures.
+//
+func WithBLSAggregateSignaturesHandler(handler BLSAggregateSignaturesHandlerFunc) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetBLSAggregateSignaturesHandler(handler)
+		return nil
+	}
+}
+
+// WithBLSAggregatePublicKeysHandler returns an interpreter option which sets the given
+// function as the function that is used to aggregate BLS public keys.
+//
+func WithBLSAggregatePublicKeysHandler(handler BLSAggregatePublicKeysHandlerFunc) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetBLSAggregatePublicKeysHandler(handler)
+		return nil
+	}
+}
+
 // WithExitHandler returns an interpreter option which sets the given
 // function as the function that is used when execution is complete.
 //
@@ -458,6 +572,17 @@ func WithAllInterpreters(allInterpreters map[common.LocationID]*Interpreter) Opt
 	}
 }
 
+// compositeTypeFields returns the fields of the composite type with the given ID,
+// or nil if the type is not declared
+//
+func (interpreter *Interpreter) compositeTypeFields(typeID sema.TypeID) *compositeTypeFields {
+	if interpreter == nil {
+		return nil
+	}
+
+	return interpreter.typeCodes.CompositeCodes[typeID].fields
+}
+
 // withTypeCodes returns an interpreter option which sets the type codes.
 //
 func withTypeCodes(typeCodes TypeCodes) Option {
@@ -467,6 +592,25 @@ func withTypeCodes(typeCodes TypeCodes) Option {
 	}
 }
 
+// withJournal returns an interpreter option which sets the journal.
+//
+func withJournal(journal *journal) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.setJournal(journal)
+		return nil
+	}
+}
+
+// WithTypeInterner returns an interpreter option which sets
+// the given interner as the interner for types converted from static types.
+//
+func WithTypeInterner(interner *sema.TypeInterner) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.typeInterner = interner
+		return nil
+	}
+}
+
 // Create a base-activation so that it can be reused across all interpreters.
 //
 var baseActivation = func() *VariableActivation {
@@ -496,6 +640,7 @@ func NewInterpreter(program *Program, location common.Location, options ...Optio
 			InterfaceCodes:       map[sema.TypeID]WrapperCode{},
 			TypeRequirementCodes: map[sema.TypeID]WrapperCode{},
 		}),
+		withJournal(&journal{}),
 	}
 
 	for _, option := range defaultOptions {
@@ -557,6 +702,18 @@ func (interpreter *Interpreter) SetStorageWriteHandler(function StorageWriteHand
 	interpreter.storageWriteHandler = function
 }
 
+// SetStoragePrefetchHandler sets the function that is used when the values for multiple keys are about to be read.
+//
+func (interpreter *Interpreter) SetStoragePrefetchHandler(function StoragePrefetchHandlerFunc) {
+	interpreter.storagePrefetchHandler = function
+}
+
+// SetStoredPathsHandler sets the function that is used when the stored paths of an account are iterated.
+//
+func (interpreter *Interpreter) SetStoredPathsHandler(function StoredPathsHandlerFunc) {
+	interpreter.storedPathsHandler = function
+}
+
 // SetInjectedCompositeFieldsHandler sets the function that is used to initialize
 // new composite values' fields
 //
@@ -606,6 +763,30 @@ func (interpreter *Interpreter) SetHashHandler(function HashHandlerFunc) {
 	interpreter.HashHandler = function
 }
 
+// SetPublicKeyRecoveryHandler sets the function that is used to recover public keys from signatures.
+//
+func (interpreter *Interpreter) SetPublicKeyRecoveryHandler(function PublicKeyRecoveryHandlerFunc) {
+	interpreter.PublicKeyRecoveryHandler = function
+}
+
+// SetBLSVerifyPoPHandler sets the function that is used to verify BLS proofs of possession.
+//
+func (interpreter *Interpreter) SetBLSVerifyPoPHandler(function BLSVerifyPoPHandlerFunc) {
+	interpreter.BLSVerifyPoPHandler = function
+}
+
+// SetBLSAggregateSignaturesHandler sets the function that is used to aggregate BLS signatures.
+//
+func (interpreter *Interpreter) SetBLSAggregateSignaturesHandler(function BLSAggregateSignaturesHandlerFunc) {
+	interpreter.BLSAggregateSignaturesHandler = function
+}
+
+// SetBLSAggregatePublicKeysHandler sets the function that is used to aggregate BLS public keys.
+//
+func (interpreter *Interpreter) SetBLSAggregatePublicKeysHandler(function BLSAggregatePublicKeysHandlerFunc) {
+	interpreter.BLSAggregatePublicKeysHandler = function
+}
+
 // SetExitHandler sets the function that is used to handle end of execution.
 //
 func (interpreter *Interpreter) SetExitHandler(function ExitHandlerFunc) {
@@ -630,6 +811,10 @@ func (interpreter *Interpreter) setTypeCodes(typeCodes TypeCodes) {
 	interpreter.typeCodes = typeCodes
 }
 
+func (interpreter *Interpreter) setJournal(journal *journal) {
+	interpreter.journal = journal
+}
+
 // locationRangeGetter returns a function that returns the location range
 // for the given location and positioned element.
 //
@@ -1307,7 +1492,7 @@ func (interpreter *Interpreter) declareNonEnumCompositeValue(
 			func(invocation Invocation) Value {
 				for i, argument := range invocation.Arguments {
 					parameter := compositeType.ConstructorParameters[i]
-					invocation.Self.Fields().Set(parameter.Identifier, argument)
+					invocation.Self.SetField(parameter.Identifier, argument)
 				}
 				return nil
 			},
@@ -1379,9 +1564,16 @@ func (interpreter *Interpreter) declareNonEnumCompositeValue(
 		wrapFunctions(interpreter.typeCodes.TypeRequirementCodes[typeRequirement.ID()])
 	}
 
+	// All values of the composite type share the layout of the declared fields,
+	// including the values which are decoded
+
+	typeFields := newCompositeTypeFields(compositeType)
+	fieldLayout := typeFields.layout
+
 	interpreter.typeCodes.CompositeCodes[compositeType.ID()] = CompositeTypeCode{
 		DestructorFunction: destructorFunction,
 		CompositeFunctions: functions,
+		fields:             typeFields,
 	}
 
 	location := interpreter.Location
@@ -1414,7 +1606,7 @@ func (interpreter *Interpreter) declareNonEnumCompositeValue(
 				)
 			}
 
-			fields := NewStringValueOrderedMap()
+			fields := newCompositeFields(fieldLayout)
 
 			if declaration.CompositeKind == common.CompositeKindResource {
 
@@ -1429,7 +1621,7 @@ func (interpreter *Interpreter) declareNonEnumCompositeValue(
 					panic(err)
 				}
 
-				fields.Set(sema.ResourceUUIDFieldName, UInt64Value(uuid))
+				fields.set(sema.ResourceUUIDFieldName, UInt64Value(uuid))
 			}
 
 			value := &CompositeValue{
@@ -1525,8 +1717,8 @@ func (interpreter *Interpreter) declareEnumConstructor(
 			compositeType.EnumRawType,
 		)
 
-		caseValueFields := NewStringValueOrderedMap()
-		caseValueFields.Set(sema.EnumRawValueFieldName, rawValue)
+		caseValueFields := newCompositeFields(enumCaseFieldLayout)
+		caseValueFields.set(sema.EnumRawValueFieldName, rawValue)
 
 		caseValue := &CompositeValue{
 			location:            location,
@@ -1561,8 +1753,8 @@ func EnumConstructorFunction(
 	lookupTable := make(map[string]*CompositeValue)
 
 	for _, caseValue := range caseValues {
-		rawValue, ok := caseValue.Fields().Get(sema.EnumRawValueFieldName)
-		if !ok {
+		rawValue := caseValue.GetField(sema.EnumRawValueFieldName)
+		if rawValue == nil {
 			panic(errors.NewUnreachableError())
 		}
 		rawValueBigEndianBytes := rawValue.(IntegerValue).ToBigEndianBytes()
@@ -2286,16 +2478,24 @@ func (interpreter *Interpreter) NewSubInterpreter(
 		WithStorageExistenceHandler(interpreter.storageExistenceHandler),
 		WithStorageReadHandler(interpreter.storageReadHandler),
 		WithStorageWriteHandler(interpreter.storageWriteHandler),
+		WithStoredPathsHandler(interpreter.storedPathsHandler),
+		WithStoragePrefetchHandler(interpreter.storagePrefetchHandler),
 		WithInjectedCompositeFieldsHandler(interpreter.injectedCompositeFieldsHandler),
 		WithContractValueHandler(interpreter.contractValueHandler),
 		WithImportLocationHandler(interpreter.importLocationHandler),
 		WithUUIDHandler(interpreter.uuidHandler),
 		WithAllInterpreters(interpreter.allInterpreters),
 		withTypeCodes(interpreter.typeCodes),
+		withJournal(interpreter.journal),
+		WithTypeInterner(interpreter.typeInterner),
 		WithAccountHandlerFunc(interpreter.accountHandler),
 		WithPublicKeyValidationHandler(interpreter.PublicKeyValidationHandler),
 		WithSignatureVerificationHandler(interpreter.SignatureVerificationHandler),
 		WithHashHandler(interpreter.HashHandler),
+		WithPublicKeyRecoveryHandler(interpreter.PublicKeyRecoveryHandler),
+		WithBLSVerifyPoPHandler(interpreter.BLSVerifyPoPHandler),
+		WithBLSAggregateSignaturesHandler(interpreter.BLSAggregateSignaturesHandler),
+		WithBLSAggregatePublicKeysHandler(interpreter.BLSAggregatePublicKeysHandler),
 	}
 
 	return NewInterpreter(
@@ -2316,7 +2516,25 @@ func (interpreter *Interpreter) ReadStored(storageAddress common.Address, key st
 	return interpreter.storageReadHandler(interpreter, storageAddress, key, deferred)
 }
 
+// prefetchStored announces that the values for the given keys will be read soon.
+// Prefetching is optional, so it is a no-op if no handler is set.
+//
+func (interpreter *Interpreter) prefetchStored(storageAddress common.Address, keys []string) {
+	if interpreter.storagePrefetchHandler == nil || len(keys) == 0 {
+		return
+	}
+	interpreter.storagePrefetchHandler(interpreter, storageAddress, keys)
+}
+
+func (interpreter *Interpreter) storedPaths(storageAddress common.Address, domain common.PathDomain) []PathValue {
+	return interpreter.storedPathsHandler(interpreter, storageAddress, domain)
+}
+
 func (interpreter *Interpreter) writeStored(storageAddress common.Address, key string, value OptionalValue) {
+	if value, ok := value.(*SomeValue); ok {
+		interpreter.recordMutation(value.Value)
+	}
+
 	value.SetOwner(&storageAddress)
 
 	interpreter.storageWriteHandler(interpreter, storageAddress, key, value)
@@ -2635,6 +2853,74 @@ var stringFunction = func() Value {
 		),
 	)
 
+	addMember(
+		sema.StringTypeDecodeHexFunctionName,
+		NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				argument := invocation.Arguments[0].(*StringValue)
+				return argument.DecodeHex()
+			},
+		),
+	)
+
+	addMember(
+		sema.StringTypeEncodeBase64FunctionName,
+		NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				argument := invocation.Arguments[0].(*ArrayValue)
+				bytes, _ := ByteArrayValueToByteSlice(argument)
+				return NewStringValue(base64.StdEncoding.EncodeToString(bytes))
+			},
+		),
+	)
+
+	addMember(
+		sema.StringTypeDecodeBase64FunctionName,
+		NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				argument := invocation.Arguments[0].(*StringValue)
+
+				bytes, err := base64.StdEncoding.DecodeString(argument.Str)
+				if err != nil {
+					panic(InvalidBase64StringError{
+						Err:           err,
+						LocationRange: invocation.GetLocationRange(),
+					})
+				}
+
+				return ByteSliceToByteArrayValue(bytes)
+			},
+		),
+	)
+
+	addMember(
+		sema.StringTypeFormatFunctionName,
+		NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				format := invocation.Arguments[0].(*StringValue)
+				arguments := invocation.Arguments[1].(*ArrayValue)
+
+				locationRange := invocation.GetLocationRange()
+
+				formatted, err := formatString(
+					format.Str,
+					arguments.Elements(),
+					func() {
+						invocation.Interpreter.reportLoopIteration(locationRange)
+					},
+				)
+				if err != nil {
+					panic(InvalidFormatStringError{
+						Err:           err,
+						LocationRange: locationRange,
+					})
+				}
+
+				return NewStringValue(formatted)
+			},
+		),
+	)
+
 	return functionValue
 }()
 
@@ -2987,6 +3273,63 @@ func (interpreter *Interpreter) authAccountBorrowFunction(addressValue AddressVa
 	})
 }
 
+func (interpreter *Interpreter) authAccountTypeFunction(addressValue AddressValue) *HostFunctionValue {
+	return NewHostFunctionValue(func(invocation Invocation) Value {
+
+		address := addressValue.ToAddress()
+
+		path := invocation.Arguments[0].(PathValue)
+
+		value := interpreter.ReadStored(address, StorageKey(path), false)
+
+		return interpreter.storedValueType(value)
+	})
+}
+
+func (interpreter *Interpreter) authAccountCheckFunction(addressValue AddressValue) *HostFunctionValue {
+	return NewHostFunctionValue(func(invocation Invocation) Value {
+
+		address := addressValue.ToAddress()
+
+		path := invocation.Arguments[0].(PathValue)
+
+		typeParameterPair := invocation.TypeParameterTypes.Oldest()
+		if typeParameterPair == nil {
+			panic(errors.NewUnreachableError())
+		}
+
+		ty := typeParameterPair.Value
+
+		someValue, ok := interpreter.ReadStored(address, StorageKey(path), false).(*SomeValue)
+		if !ok {
+			return BoolValue(false)
+		}
+
+		dynamicType := someValue.Value.DynamicType(interpreter, SeenReferences{})
+
+		return BoolValue(IsSubType(dynamicType, ty))
+	})
+}
+
+// storedValueType returns the run-time type of the given stored value, if any
+//
+func (interpreter *Interpreter) storedValueType(value OptionalValue) OptionalValue {
+	switch value := value.(type) {
+	case NilValue:
+		return value
+
+	case *SomeValue:
+		return NewSomeValueOwningNonCopying(
+			TypeValue{
+				Type: value.Value.StaticType(),
+			},
+		)
+
+	default:
+		panic(errors.NewUnreachableError())
+	}
+}
+
 func (interpreter *Interpreter) authAccountLinkFunction(addressValue AddressValue) *HostFunctionValue {
 	return NewHostFunctionValue(func(invocation Invocation) Value {
 
@@ -3065,6 +3408,93 @@ func (interpreter *Interpreter) accountGetLinkTargetFunction(addressValue Addres
 	})
 }
 
+func (interpreter *Interpreter) authAccountForEachStoredFunction(addressValue AddressValue) *HostFunctionValue {
+	return interpreter.accountStorageIterationFunction(
+		addressValue,
+		common.PathDomainStorage,
+		func(value Value) (StaticType, bool) {
+			return value.StaticType(), true
+		},
+	)
+}
+
+func (interpreter *Interpreter) accountForEachPublicFunction(addressValue AddressValue) *HostFunctionValue {
+	return interpreter.accountStorageIterationFunction(
+		addressValue,
+		common.PathDomainPublic,
+		func(value Value) (StaticType, bool) {
+			link, ok := value.(LinkValue)
+			if !ok {
+				return nil, false
+			}
+			return link.Type, true
+		},
+	)
+}
+
+// accountStorageIterationFunction returns a function which invokes its function argument
+// with the path and the type of each value stored in the given domain of the account,
+// until the function argument returns false.
+//
+// The paths are determined before the iteration starts,
+// so values which are stored during the iteration are not visited.
+// Values which are removed during the iteration are skipped.
+//
+// The type of a stored value is its static type, like the result of `getType`,
+// so the stored value is not traversed. Array and dictionary values do not have a static type yet.
+//
+// Each iteration is reported as a loop iteration, so it is metered.
+//
+func (interpreter *Interpreter) accountStorageIterationFunction(
+	addressValue AddressValue,
+	domain common.PathDomain,
+	valueType func(Value) (StaticType, bool),
+) *HostFunctionValue {
+	return NewHostFunctionValue(func(invocation Invocation) Value {
+
+		address := addressValue.ToAddress()
+
+		function := invocation.Arguments[0].(FunctionValue)
+
+		locationRange := invocation.GetLocationRange()
+
+		paths := interpreter.storedPaths(address, domain)
+
+		keys := make([]string, len(paths))
+		for i, path := range paths {
+			keys[i] = StorageKey(path)
+		}
+		interpreter.prefetchStored(address, keys)
+
+		for _, path := range paths {
+
+			interpreter.reportLoopIteration(locationRange)
+
+			value := interpreter.ReadStored(address, StorageKey(path), false)
+
+			someValue, ok := value.(*SomeValue)
+			if !ok {
+				continue
+			}
+
+			staticType, ok := valueType(someValue.Value)
+			if !ok {
+				continue
+			}
+
+			typeValue := TypeValue{
+				Type: staticType,
+			}
+
+			if !invokeIterationFunction(invocation, function, path, typeValue) {
+				break
+			}
+		}
+
+		return VoidValue{}
+	})
+}
+
 func (interpreter *Interpreter) authAccountUnlinkFunction(addressValue AddressValue) *HostFunctionValue {
 	return NewHostFunctionValue(func(invocation Invocation) Value {
 
@@ -3085,6 +3515,126 @@ func (interpreter *Interpreter) authAccountUnlinkFunction(addressValue AddressVa
 	})
 }
 
+func (interpreter *Interpreter) authAccountForEachLinkFunction(addressValue AddressValue) *HostFunctionValue {
+	return NewHostFunctionValue(func(invocation Invocation) Value {
+
+		address := addressValue.ToAddress()
+
+		function := invocation.Arguments[0].(FunctionValue)
+
+		locationRange := invocation.GetLocationRange()
+
+		for _, path := range interpreter.storedLinkPaths(address) {
+
+			interpreter.reportLoopIteration(locationRange)
+
+			link, ok := interpreter.readLink(address, path)
+			if !ok {
+				continue
+			}
+
+			typeValue := TypeValue{
+				Type: link.Type,
+			}
+
+			if !invokeIterationFunction(invocation, function, path, typeValue, link.TargetPath) {
+				break
+			}
+		}
+
+		return VoidValue{}
+	})
+}
+
+func (interpreter *Interpreter) authAccountUnlinkAllFunction(addressValue AddressValue) *HostFunctionValue {
+	return NewHostFunctionValue(func(invocation Invocation) Value {
+
+		address := addressValue.ToAddress()
+
+		targetPath := invocation.Arguments[0].(PathValue)
+
+		locationRange := invocation.GetLocationRange()
+
+		// Determine all links which target the path before removing any of them,
+		// as removing a link breaks the chains of links through it
+
+		var removedPaths []Value
+
+		for _, path := range interpreter.storedLinkPaths(address) {
+			if interpreter.linkTargets(address, path, targetPath, locationRange) {
+				removedPaths = append(removedPaths, path)
+			}
+		}
+
+		for _, path := range removedPaths {
+			interpreter.writeStored(
+				address,
+				StorageKey(path.(PathValue)),
+				NilValue{},
+			)
+		}
+
+		return NewArrayValueUnownedNonCopying(removedPaths...)
+	})
+}
+
+// storedLinkPaths returns the public and private paths of the account,
+// public paths first, each in order
+//
+func (interpreter *Interpreter) storedLinkPaths(address common.Address) []PathValue {
+	return append(
+		interpreter.storedPaths(address, common.PathDomainPublic),
+		interpreter.storedPaths(address, common.PathDomainPrivate)...,
+	)
+}
+
+// readLink returns the link stored at the given path of the account, if any
+//
+func (interpreter *Interpreter) readLink(address common.Address, path PathValue) (LinkValue, bool) {
+	someValue, ok := interpreter.ReadStored(address, StorageKey(path), false).(*SomeValue)
+	if !ok {
+		return LinkValue{}, false
+	}
+
+	link, ok := someValue.Value.(LinkValue)
+	return link, ok
+}
+
+// linkTargets returns true if the link at the given path of the account
+// targets the given target path, either directly, or through other links.
+//
+// Each followed link is reported as a loop iteration, so it is metered.
+// Cyclic links only target the paths of the cycle.
+//
+func (interpreter *Interpreter) linkTargets(
+	address common.Address,
+	path PathValue,
+	targetPath PathValue,
+	locationRange LocationRange,
+) bool {
+	seenPaths := map[PathValue]struct{}{}
+
+	for {
+		if _, ok := seenPaths[path]; ok {
+			return false
+		}
+		seenPaths[path] = struct{}{}
+
+		interpreter.reportLoopIteration(locationRange)
+
+		link, ok := interpreter.readLink(address, path)
+		if !ok {
+			return false
+		}
+
+		if link.TargetPath == targetPath {
+			return true
+		}
+
+		path = link.TargetPath
+	}
+}
+
 func (interpreter *Interpreter) capabilityBorrowFunction(
 	addressValue AddressValue,
 	pathValue PathValue,
@@ -3202,6 +3752,53 @@ func (interpreter *Interpreter) capabilityCheckFunction(
 	)
 }
 
+func (interpreter *Interpreter) capabilityTargetTypeFunction(
+	addressValue AddressValue,
+	pathValue PathValue,
+) *HostFunctionValue {
+
+	return NewHostFunctionValue(
+		func(invocation Invocation) Value {
+
+			address := addressValue.ToAddress()
+
+			value := interpreter.readLinkedValue(address, pathValue)
+
+			return interpreter.storedValueType(value)
+		},
+	)
+}
+
+// readLinkedValue returns the value which the links starting at the given path lead to, if any.
+//
+// Unlike when borrowing a capability, the borrow types of the links are not checked.
+// Cyclic links lead to no value.
+//
+func (interpreter *Interpreter) readLinkedValue(address common.Address, path PathValue) OptionalValue {
+	seenPaths := map[PathValue]struct{}{}
+
+	for {
+		if _, ok := seenPaths[path]; ok {
+			return NilValue{}
+		}
+		seenPaths[path] = struct{}{}
+
+		value := interpreter.ReadStored(address, StorageKey(path), false)
+
+		someValue, ok := value.(*SomeValue)
+		if !ok {
+			return value
+		}
+
+		link, ok := someValue.Value.(LinkValue)
+		if !ok {
+			return value
+		}
+
+		path = link.TargetPath
+	}
+}
+
 func (interpreter *Interpreter) GetCapabilityFinalTargetStorageKey(
 	address common.Address,
 	path PathValue,
@@ -3263,7 +3860,7 @@ func (interpreter *Interpreter) GetCapabilityFinalTargetStorageKey(
 }
 
 func (interpreter *Interpreter) ConvertStaticToSemaType(staticType StaticType) sema.Type {
-	return ConvertStaticToSemaType(
+	semaType := ConvertStaticToSemaType(
 		staticType,
 		func(location common.Location, qualifiedIdentifier string) *sema.InterfaceType {
 			return interpreter.getInterfaceType(location, qualifiedIdentifier)
@@ -3272,6 +3869,23 @@ func (interpreter *Interpreter) ConvertStaticToSemaType(staticType StaticType) s
 			return interpreter.getCompositeType(location, qualifiedIdentifier)
 		},
 	)
+
+	// NOTE: the interpreter might be nil, e.g. when exporting values of primitive types
+
+	if interpreter != nil && interpreter.typeInterner != nil {
+		semaType = interpreter.typeInterner.Intern(semaType)
+	}
+	return semaType
+}
+
+// SemaTypeID returns the type ID of the given type.
+// If the interpreter has a type interner, the type ID of interned types is only computed once.
+//
+func (interpreter *Interpreter) SemaTypeID(semaType sema.Type) sema.TypeID {
+	if interpreter != nil && interpreter.typeInterner != nil {
+		return interpreter.typeInterner.ID(semaType)
+	}
+	return semaType.ID()
 }
 
 func (interpreter *Interpreter) getElaboration(location common.Location) *sema.Elaboration {
@@ -3384,14 +3998,30 @@ func (interpreter *Interpreter) reportFunctionInvocation(pos ast.HasPosition) {
 // getMember gets the member value by the given identifier from the given Value depending on its type.
 // May return nil if the member does not exist.
 func (interpreter *Interpreter) getMember(self Value, getLocationRange func() LocationRange, identifier string) Value {
+	return interpreter.getMemberAt(self, getLocationRange, -1, identifier)
+}
+
+// getMemberAt returns the member with the given identifier.
+// If the member is a field of a composite value, the checker assigned the given slot to it,
+// or the slot is negative if it is unknown
+//
+func (interpreter *Interpreter) getMemberAt(
+	self Value,
+	getLocationRange func() LocationRange,
+	slot int,
+	identifier string,
+) Value {
 	var result Value
 	// When the accessed value has a type that supports the declaration of members
 	// or is a built-in type that has members (`MemberAccessibleValue`),
 	// then try to get the member for the given identifier.
 	// For example, the built-in type `String` has a member "length",
 	// and composite declarations may contain member declarations
-	if memberAccessibleValue, ok := self.(MemberAccessibleValue); ok {
-		result = memberAccessibleValue.GetMember(interpreter, getLocationRange, identifier)
+	switch typedSelf := self.(type) {
+	case *CompositeValue:
+		result = typedSelf.getMemberAt(interpreter, getLocationRange, slot, identifier)
+	case MemberAccessibleValue:
+		result = typedSelf.GetMember(interpreter, getLocationRange, identifier)
 	}
 	if result == nil {
 		switch identifier {
@@ -3441,5 +4071,37 @@ func (interpreter *Interpreter) getTypeFunction(self Value) *HostFunctionValue {
 }
 
 func (interpreter *Interpreter) setMember(self Value, getLocationRange func() LocationRange, identifier string, value Value) {
+	interpreter.setMemberAt(self, getLocationRange, -1, identifier, value)
+}
+
+// setMemberAt sets the member with the given identifier.
+// If the member is a field of a composite value, the checker assigned the given slot to it,
+// or the slot is negative if it is unknown
+//
+func (interpreter *Interpreter) setMemberAt(
+	self Value,
+	getLocationRange func() LocationRange,
+	slot int,
+	identifier string,
+	value Value,
+) {
+	if compositeValue, ok := self.(*CompositeValue); ok {
+		compositeValue.setMemberAt(interpreter, getLocationRange, slot, identifier, value)
+		return
+	}
+
 	self.(MemberAccessibleValue).SetMember(interpreter, getLocationRange, identifier, value)
 }
+
+// memberExpressionFieldSlot returns the slot which the checker assigned
+// to the field accessed by the given member expression,
+// or -1 if the accessed member is not a field of a composite value
+//
+func (interpreter *Interpreter) memberExpressionFieldSlot(expression *ast.MemberExpression) int {
+	slot, ok := interpreter.Program.Elaboration.MemberExpressionFieldSlots[expression]
+	if !ok {
+		return -1
+	}
+
+	return slot
+}
@@ -56,6 +56,7 @@ func (interpreter *Interpreter) identifierExpressionGetterSetter(identifierExpre
 			return variable.GetValue()
 		},
 		set: func(value Value) {
+			interpreter.recordVariableMutation(variable)
 			variable.SetValue(value)
 		},
 	}
@@ -85,12 +86,13 @@ func (interpreter *Interpreter) memberExpressionGetterSetter(memberExpression *a
 	target := interpreter.evalExpression(memberExpression.Expression)
 	getLocationRange := locationRangeGetter(interpreter.Location, memberExpression)
 	identifier := memberExpression.Identifier.Identifier
+	slot := interpreter.memberExpressionFieldSlot(memberExpression)
 	return getterSetter{
 		get: func() Value {
-			return interpreter.getMember(target, getLocationRange, identifier)
+			return interpreter.getMemberAt(target, getLocationRange, slot, identifier)
 		},
 		set: func(value Value) {
-			interpreter.setMember(target, getLocationRange, identifier, value)
+			interpreter.setMemberAt(target, getLocationRange, slot, identifier, value)
 		},
 	}
 }
@@ -451,7 +453,8 @@ func (interpreter *Interpreter) VisitMemberExpression(expression *ast.MemberExpr
 
 	getLocationRange := locationRangeGetter(interpreter.Location, expression)
 	identifier := expression.Identifier.Identifier
-	resultValue := interpreter.getMember(result, getLocationRange, identifier)
+	slot := interpreter.memberExpressionFieldSlot(expression)
+	resultValue := interpreter.getMemberAt(result, getLocationRange, slot, identifier)
 	if resultValue == nil {
 		panic(MissingMemberValueError{
 			Name:          identifier,
@@ -726,6 +729,68 @@ func (interpreter *Interpreter) VisitPathExpression(expression *ast.PathExpressi
 	}
 }
 
+func (interpreter *Interpreter) VisitTryExpression(expression *ast.TryExpression) ast.Repr {
+	result, ok := interpreter.evalTry(expression.InvocationExpression)
+	if !ok {
+		return NilValue{}
+	}
+
+	return NewSomeValueOwningNonCopying(result)
+}
+
+// evalTry evaluates the given expression in a new journal level.
+//
+// If the evaluation fails with a recoverable error,
+// all state changes which occurred during the evaluation are reverted,
+// all events emitted during the evaluation are discarded,
+// and false is returned.
+//
+// If the evaluation fails with an unrecoverable error,
+// or if an irreversible effect occurred during the evaluation,
+// the failure is propagated.
+//
+func (interpreter *Interpreter) evalTry(expression ast.Expression) (result Value, ok bool) {
+	journal := interpreter.journal
+	statement := interpreter.statement
+
+	journal.begin()
+
+	defer func() {
+		if ok {
+			return
+		}
+
+		r := recover()
+
+		irreversible := journal.rollback()
+
+		if r == nil {
+			return
+		}
+
+		if irreversible || !isRecoverableError(r) {
+			panic(r)
+		}
+
+		interpreter.statement = statement
+	}()
+
+	result = interpreter.evalExpression(expression)
+
+	events := journal.commit()
+
+	ok = true
+
+	// Emit the events of the outermost try expression,
+	// now that the evaluation succeeded and the state changes are final
+
+	for _, emit := range events {
+		emit()
+	}
+
+	return result, ok
+}
+
 func (interpreter *Interpreter) evalPotentialResourceMoveIndexExpression(expression ast.Expression) Value {
 	resourceMoveIndexExpression := interpreter.resourceMoveIndexExpression(expression)
 	if resourceMoveIndexExpression == nil {
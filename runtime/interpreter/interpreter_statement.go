@@ -282,9 +282,19 @@ func (interpreter *Interpreter) VisitEmitStatement(statement *ast.EmitStatement)
 		})
 	}
 
-	err := interpreter.onEventEmitted(interpreter, event, eventType)
-	if err != nil {
-		panic(err)
+	emit := func() {
+		err := interpreter.onEventEmitted(interpreter, event, eventType)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	// Inside a try expression, emit the event only once the evaluation succeeded
+
+	if interpreter.journal.active() {
+		interpreter.journal.recordEvent(emit)
+	} else {
+		emit()
 	}
 
 	return nil
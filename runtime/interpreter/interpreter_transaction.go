@@ -53,7 +53,6 @@ func (interpreter *Interpreter) declareTransactionEntryPoint(declaration *ast.Tr
 
 	self := &CompositeValue{
 		location: interpreter.Location,
-		fields:   NewStringValueOrderedMap(),
 		modified: true,
 	}
 
@@ -0,0 +1,384 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/common/orderedmap"
+)
+
+// journal records the state changes of a program while a try expression is evaluated,
+// or while a savepoint is active, so that the state changes can be reverted
+// if the evaluation fails, or if the savepoint is rolled back.
+//
+// The journal consists of nested levels, one for each try expression that is currently evaluated,
+// and one for each savepoint that is currently active.
+// Committing a level merges its records into the enclosing level.
+// Rolling back a level reverts the state changes recorded in the level.
+//
+// The journal is shared by an interpreter and all its sub-interpreters.
+//
+type journal struct {
+	levels []*journalLevel
+}
+
+type journalLevel struct {
+	// undos are the functions which revert the recorded state changes,
+	// in the order in which the state changes occurred
+	undos []func()
+
+	// recorded are the values and variables for which the state
+	// before their first change in this level was already recorded
+	recorded map[interface{}]struct{}
+
+	// events are the functions which emit the events emitted in this level,
+	// in the order in which the events were emitted
+	events []func()
+
+	// irreversible is true if an effect occurred in this level
+	// which cannot be reverted, e.g. an account was created
+	irreversible bool
+}
+
+func (j *journal) active() bool {
+	return j != nil && len(j.levels) > 0
+}
+
+func (j *journal) currentLevel() *journalLevel {
+	if !j.active() {
+		return nil
+	}
+	return j.levels[len(j.levels)-1]
+}
+
+// begin starts a new level
+//
+func (j *journal) begin() {
+	j.levels = append(
+		j.levels,
+		&journalLevel{
+			recorded: map[interface{}]struct{}{},
+		},
+	)
+}
+
+func (j *journal) pop() *journalLevel {
+	lastIndex := len(j.levels) - 1
+	level := j.levels[lastIndex]
+	j.levels = j.levels[:lastIndex]
+	return level
+}
+
+// commit ends the current level and keeps its state changes.
+//
+// If the level is nested, its records are merged into the enclosing level.
+// Otherwise, the state changes are final, and the events emitted in the level
+// are returned, so they can be emitted.
+//
+func (j *journal) commit() (events []func()) {
+	level := j.pop()
+
+	parent := j.currentLevel()
+	if parent == nil {
+		return level.events
+	}
+
+	parent.undos = append(parent.undos, level.undos...)
+	for key := range level.recorded { //nolint:maprangecheck
+		parent.recorded[key] = struct{}{}
+	}
+	parent.events = append(parent.events, level.events...)
+	parent.irreversible = parent.irreversible || level.irreversible
+
+	return nil
+}
+
+// rollback ends the current level, reverts its state changes,
+// and discards the events emitted in the level.
+//
+// It returns true if an effect occurred in the level which could not be reverted.
+//
+func (j *journal) rollback() (irreversible bool) {
+	level := j.pop()
+
+	for i := len(level.undos) - 1; i >= 0; i-- {
+		level.undos[i]()
+	}
+
+	if level.irreversible {
+		// The enclosing levels cannot be rolled back either
+		for _, parent := range j.levels {
+			parent.irreversible = true
+		}
+	}
+
+	return level.irreversible
+}
+
+// record records the given undo function,
+// if no undo function was recorded for the given key in the current level yet.
+//
+func (j *journal) record(key interface{}, undo func() func()) {
+	level := j.currentLevel()
+	if level == nil {
+		return
+	}
+
+	if key != nil {
+		if _, ok := level.recorded[key]; ok {
+			return
+		}
+		level.recorded[key] = struct{}{}
+	}
+
+	level.undos = append(level.undos, undo())
+}
+
+func (j *journal) recordEvent(emit func()) {
+	level := j.currentLevel()
+	level.events = append(level.events, emit)
+}
+
+func (j *journal) recordIrreversibleEffect() {
+	level := j.currentLevel()
+	if level == nil {
+		return
+	}
+	level.irreversible = true
+}
+
+// recordMutation records the state of the given value before it is mutated
+//
+func (interpreter *Interpreter) recordMutation(value Value) {
+	if interpreter == nil || !interpreter.journal.active() {
+		return
+	}
+
+	switch value := value.(type) {
+	case *ArrayValue:
+		interpreter.journal.record(value, value.snapshot)
+
+	case *DictionaryValue:
+		interpreter.journal.record(value, value.snapshot)
+
+	case *CompositeValue:
+		interpreter.journal.record(value, value.snapshot)
+	}
+}
+
+// recordVariableMutation records the value of the given variable before it is assigned
+//
+func (interpreter *Interpreter) recordVariableMutation(variable *Variable) {
+	if !interpreter.journal.active() {
+		return
+	}
+
+	interpreter.journal.record(variable, func() func() {
+		value := variable.value
+		getter := variable.getter
+
+		return func() {
+			variable.value = value
+			variable.getter = getter
+		}
+	})
+}
+
+// RecordUndo records a function which reverts a state change of the host environment,
+// e.g. a write to storage, if the try expression that is currently evaluated fails,
+// or if the savepoint that is currently active is rolled back.
+// Outside of try expressions and savepoints, the function has no effect.
+//
+func (interpreter *Interpreter) RecordUndo(undo func()) {
+	if !interpreter.journal.active() {
+		return
+	}
+
+	interpreter.journal.record(nil, func() func() {
+		return undo
+	})
+}
+
+// RecordIrreversibleEffect records that an effect occurred which cannot be reverted,
+// e.g. an account was created.
+//
+// If the try expression that is currently evaluated fails after such an effect,
+// the failure cannot be recovered from and aborts the program.
+// Likewise, the savepoint that is currently active cannot be rolled back anymore.
+//
+func (interpreter *Interpreter) RecordIrreversibleEffect() {
+	interpreter.journal.recordIrreversibleEffect()
+}
+
+// Savepoint calls the given function in a new journal level,
+// and returns whether the state changes of the call were kept.
+//
+// If the function returns true, the state changes which occurred during the call are kept.
+// If the function returns false, all state changes which occurred during the call are reverted,
+// and all events emitted during the call are discarded.
+// If an irreversible effect occurred during the call, the state changes cannot be reverted,
+// and the program is aborted.
+//
+// If the function fails, the state changes which occurred during the call are reverted,
+// and the failure is propagated.
+//
+func (interpreter *Interpreter) Savepoint(
+	getLocationRange func() LocationRange,
+	function func() bool,
+) (committed bool) {
+	journal := interpreter.journal
+
+	journal.begin()
+
+	finished := false
+
+	defer func() {
+		if finished {
+			return
+		}
+
+		// The function failed. Revert its state changes,
+		// so the journal level is ended before the failure is propagated
+
+		journal.rollback()
+	}()
+
+	committed = function()
+
+	finished = true
+
+	if !committed {
+		irreversible := journal.rollback()
+		if irreversible {
+			panic(IrreversibleSavepointRollbackError{
+				LocationRange: getLocationRange(),
+			})
+		}
+
+		return false
+	}
+
+	// Emit the events of the outermost savepoint or try expression,
+	// now that the state changes are final
+
+	for _, emit := range journal.commit() {
+		emit()
+	}
+
+	return true
+}
+
+// snapshot returns a function which restores the current state of the array
+//
+func (v *ArrayValue) snapshot() func() {
+	values := make([]Value, len(v.Elements()))
+	copy(values, v.values)
+
+	owner := v.Owner
+	modified := v.modified
+
+	return func() {
+		v.values = values
+		v.modified = modified
+		v.restoreOwner(owner)
+	}
+}
+
+func (v *ArrayValue) restoreOwner(owner *common.Address) {
+	v.Owner = owner
+	for _, value := range v.values {
+		value.SetOwner(owner)
+	}
+}
+
+// snapshot returns a function which restores the current state of the dictionary
+//
+func (v *DictionaryValue) snapshot() func() {
+	v.ensureLoaded()
+
+	restoreKeys := v.keys.snapshot()
+	entries := copyStringValueOrderedMap(v.entries)
+	deferredKeys := copyStringStructOrderedMap(v.deferredKeys)
+	prevDeferredKeys := copyStringStructOrderedMap(v.prevDeferredKeys)
+	deferredOwner := v.deferredOwner
+
+	owner := v.Owner
+	modified := v.modified
+
+	return func() {
+		restoreKeys()
+		v.entries = entries
+		v.deferredKeys = deferredKeys
+		v.prevDeferredKeys = prevDeferredKeys
+		v.deferredOwner = deferredOwner
+		v.modified = modified
+
+		v.Owner = owner
+		v.keys.restoreOwner(owner)
+		v.entries.Foreach(func(_ string, value Value) {
+			value.SetOwner(owner)
+		})
+	}
+}
+
+// snapshot returns a function which restores the current state of the composite
+//
+func (v *CompositeValue) snapshot() func() {
+	v.ensureFieldsLoaded()
+	fields := v.fields.copy(nil)
+
+	owner := v.Owner
+	modified := v.modified
+	destroyed := v.destroyed
+
+	return func() {
+		v.fields = fields
+		v.modified = modified
+		v.destroyed = destroyed
+
+		v.Owner = owner
+		v.fields.foreach(func(_ string, value Value) {
+			value.SetOwner(owner)
+		})
+	}
+}
+
+func copyStringValueOrderedMap(m *StringValueOrderedMap) *StringValueOrderedMap {
+	if m == nil {
+		return nil
+	}
+
+	result := NewStringValueOrderedMap()
+	m.Foreach(func(key string, value Value) {
+		result.Set(key, value)
+	})
+	return result
+}
+
+func copyStringStructOrderedMap(m *orderedmap.StringStructOrderedMap) *orderedmap.StringStructOrderedMap {
+	if m == nil {
+		return nil
+	}
+
+	result := orderedmap.NewStringStructOrderedMap()
+	m.Foreach(func(key string, value struct{}) {
+		result.Set(key, value)
+	})
+	return result
+}
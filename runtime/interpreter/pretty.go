@@ -0,0 +1,126 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"github.com/onflow/cadence/runtime/format"
+)
+
+// PrettyString returns the multi-line representation of the given value,
+// limited and redacted according to the given options
+//
+func PrettyString(value Value, options format.PrettyOptions) string {
+	return format.Pretty(prettyNode(value, nil), options)
+}
+
+// prettySeenReferences is the chain of references which are currently printed.
+//
+// The elements of containers are only produced when they are printed,
+// after the node of the reference has been returned,
+// so the chain is immutable, instead of a SeenReferences set
+//
+type prettySeenReferences struct {
+	reference *EphemeralReferenceValue
+	parent    *prettySeenReferences
+}
+
+func (s *prettySeenReferences) contains(reference *EphemeralReferenceValue) bool {
+	for ; s != nil; s = s.parent {
+		if s.reference == reference {
+			return true
+		}
+	}
+	return false
+}
+
+func prettyNode(value Value, seenReferences *prettySeenReferences) format.PrettyNode {
+	switch value := value.(type) {
+	case *ArrayValue:
+		elements := value.Elements()
+		return format.PrettyArray{
+			Count: len(elements),
+			Element: func(index int) format.PrettyNode {
+				return prettyNode(elements[index], seenReferences)
+			},
+		}
+
+	case *DictionaryValue:
+		value.ensureLoaded()
+		keys := value.keys.Elements()
+		return format.PrettyDictionary{
+			Count: len(keys),
+			Entry: func(index int) (format.PrettyNode, format.PrettyNode) {
+				keyValue := keys[index]
+				entryValue, _ := value.entries.Get(dictionaryKey(keyValue))
+
+				// Value is potentially deferred,
+				// so might be nil
+
+				var entryNode format.PrettyNode
+				if entryValue == nil {
+					entryNode = format.PrettyText("...")
+				} else {
+					entryNode = prettyNode(entryValue, seenReferences)
+				}
+
+				return prettyNode(keyValue, seenReferences), entryNode
+			},
+		}
+
+	case *CompositeValue:
+		// Composites with a custom string representation,
+		// e.g. accounts, are not printed field by field
+
+		if value.stringer != nil {
+			return format.PrettyText(value.String())
+		}
+
+		fieldNames := make([]string, 0, value.FieldCount())
+		value.ForEachField(func(fieldName string, _ Value) {
+			fieldNames = append(fieldNames, fieldName)
+		})
+
+		return format.PrettyComposite{
+			TypeID:     string(value.TypeID()),
+			FieldNames: fieldNames,
+			Field: func(name string) format.PrettyNode {
+				return prettyNode(value.GetField(name), seenReferences)
+			},
+		}
+
+	case *SomeValue:
+		return prettyNode(value.Value, seenReferences)
+
+	case *EphemeralReferenceValue:
+		if seenReferences.contains(value) {
+			return format.PrettyText("...")
+		}
+
+		return prettyNode(
+			value.Value,
+			&prettySeenReferences{
+				reference: value,
+				parent:    seenReferences,
+			},
+		)
+
+	default:
+		return format.PrettyText(value.String())
+	}
+}
@@ -0,0 +1,93 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/format"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestPrettyString(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("composite", func(t *testing.T) {
+
+		t.Parallel()
+
+		fields := NewStringValueOrderedMap()
+		fields.Set("name", NewStringValue("test"))
+		fields.Set("secret", NewStringValue("password"))
+		fields.Set("tags", NewArrayValueUnownedNonCopying(
+			NewStringValue("a"),
+			NewStringValue("b"),
+			NewStringValue("c"),
+		))
+		fields.Set("balances", NewDictionaryValueUnownedNonCopying(
+			NewStringValue("x"), NewSomeValueOwningNonCopying(UInt64Value(1)),
+		))
+		fields.Set("parent", NilValue{})
+
+		value := NewCompositeValue(
+			utils.TestLocation,
+			"Test",
+			common.CompositeKindStructure,
+			fields,
+			nil,
+		)
+
+		assert.Equal(t,
+			`S.test.Test(
+    name: "test",
+    secret: <redacted>,
+    tags: [
+        "a",
+        "b",
+        ... (1 more)
+    ],
+    balances: {
+        "x": 1
+    },
+    parent: nil
+)`,
+			PrettyString(value, format.PrettyOptions{
+				MaxElements: 2,
+				Redact:      format.RedactFields("secret"),
+			}),
+		)
+	})
+
+	t.Run("cyclic reference", func(t *testing.T) {
+
+		t.Parallel()
+
+		array := NewArrayValueUnownedNonCopying()
+		array.Append(&EphemeralReferenceValue{Value: array})
+
+		assert.Equal(t,
+			"[\n    [\n        ...\n    ]\n]",
+			PrettyString(array, format.PrettyOptions{}),
+		)
+	})
+}
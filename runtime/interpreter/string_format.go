@@ -0,0 +1,271 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// formatString formats the given arguments according to the given format string.
+//
+// Placeholders have the form `{index}` or `{index:spec}`,
+// where the index refers to the position of the argument,
+// and the optional spec has the form `[-][0][width][.precision]`:
+//
+//   - `-` aligns the value to the left instead of to the right
+//   - `0` pads numbers with zeros instead of spaces
+//   - `width` is the minimum number of characters
+//   - `precision` is the number of fractional digits of a fixed-point number.
+//     Additional digits are truncated.
+//
+// Literal braces are written as `{{` and `}}`.
+//
+// The width and the precision are limited to maxFormatSpecNumber,
+// and the given report function is called for every formatStringMeteringUnit bytes of the result,
+// so the length of the result is metered while it is built.
+//
+func formatString(format string, arguments []Value, report func()) (string, error) {
+	var builder strings.Builder
+	var metered int
+
+	for i := 0; i < len(format); i++ {
+		for ; metered < builder.Len(); metered += formatStringMeteringUnit {
+			report()
+		}
+
+		c := format[i]
+
+		switch c {
+		case '{':
+			if i+1 < len(format) && format[i+1] == '{' {
+				builder.WriteByte('{')
+				i++
+				continue
+			}
+
+			end := strings.IndexByte(format[i:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated placeholder at offset %d", i)
+			}
+
+			placeholder := format[i+1 : i+end]
+
+			formatted, err := formatPlaceholder(placeholder, arguments)
+			if err != nil {
+				return "", err
+			}
+
+			builder.WriteString(formatted)
+			i += end
+
+		case '}':
+			if i+1 < len(format) && format[i+1] == '}' {
+				builder.WriteByte('}')
+				i++
+				continue
+			}
+
+			return "", fmt.Errorf("unmatched '}' at offset %d", i)
+
+		default:
+			builder.WriteByte(c)
+		}
+	}
+
+	for ; metered < builder.Len(); metered += formatStringMeteringUnit {
+		report()
+	}
+
+	return builder.String(), nil
+}
+
+// maxFormatSpecNumber is the maximum width and the maximum precision of a placeholder
+//
+const maxFormatSpecNumber = 1024
+
+// formatStringMeteringUnit is the number of bytes of a formatted string
+// for which the computation is reported once
+//
+const formatStringMeteringUnit = 64
+
+type formatSpec struct {
+	leftAlign    bool
+	zeroPad      bool
+	width        int
+	precision    int
+	hasPrecision bool
+}
+
+func parseFormatSpec(spec string) (result formatSpec, err error) {
+	if strings.HasPrefix(spec, "-") {
+		result.leftAlign = true
+		spec = spec[1:]
+	}
+
+	if strings.HasPrefix(spec, "0") {
+		result.zeroPad = true
+		spec = spec[1:]
+	}
+
+	width := spec
+	if dot := strings.IndexByte(spec, '.'); dot >= 0 {
+		width = spec[:dot]
+
+		result.precision, err = parseFormatNumber(spec[dot+1:])
+		if err != nil {
+			return formatSpec{}, fmt.Errorf("invalid precision %q", spec[dot+1:])
+		}
+		if result.precision > maxFormatSpecNumber {
+			return formatSpec{}, fmt.Errorf(
+				"precision %d exceeds the maximum of %d",
+				result.precision,
+				maxFormatSpecNumber,
+			)
+		}
+		result.hasPrecision = true
+	}
+
+	if width != "" {
+		result.width, err = parseFormatNumber(width)
+		if err != nil {
+			return formatSpec{}, fmt.Errorf("invalid width %q", width)
+		}
+		if result.width > maxFormatSpecNumber {
+			return formatSpec{}, fmt.Errorf(
+				"width %d exceeds the maximum of %d",
+				result.width,
+				maxFormatSpecNumber,
+			)
+		}
+	}
+
+	return result, nil
+}
+
+func parseFormatNumber(s string) (int, error) {
+	if s == "" || s[0] == '+' || s[0] == '-' {
+		return 0, strconv.ErrSyntax
+	}
+	return strconv.Atoi(s)
+}
+
+func formatPlaceholder(placeholder string, arguments []Value) (string, error) {
+	index := placeholder
+	var spec formatSpec
+
+	if colon := strings.IndexByte(placeholder, ':'); colon >= 0 {
+		index = placeholder[:colon]
+
+		var err error
+		spec, err = parseFormatSpec(placeholder[colon+1:])
+		if err != nil {
+			return "", err
+		}
+	}
+
+	argumentIndex, err := parseFormatNumber(index)
+	if err != nil {
+		return "", fmt.Errorf("invalid placeholder index %q", index)
+	}
+
+	if argumentIndex >= len(arguments) {
+		return "", fmt.Errorf(
+			"placeholder index %d out of bounds: %d arguments given",
+			argumentIndex,
+			len(arguments),
+		)
+	}
+
+	argument := arguments[argumentIndex]
+
+	var formatted string
+	_, isNumber := argument.(NumberValue)
+
+	switch argument := argument.(type) {
+	case *StringValue:
+		formatted = argument.Str
+	default:
+		formatted = argument.String()
+	}
+
+	if spec.hasPrecision {
+		switch argument.(type) {
+		case Fix64Value, UFix64Value:
+			formatted = truncateFractionalDigits(formatted, spec.precision)
+		default:
+			return "", fmt.Errorf(
+				"precision is only supported for fixed-point values, got %s",
+				formatted,
+			)
+		}
+	}
+
+	if spec.zeroPad && !isNumber {
+		return "", fmt.Errorf(
+			"zero padding is only supported for numbers, got %s",
+			formatted,
+		)
+	}
+
+	return padFormatted(formatted, spec), nil
+}
+
+func truncateFractionalDigits(formatted string, precision int) string {
+	dot := strings.IndexByte(formatted, '.')
+	if dot < 0 {
+		return formatted
+	}
+
+	if precision == 0 {
+		return formatted[:dot]
+	}
+
+	end := dot + 1 + precision
+	if end >= len(formatted) {
+		return formatted
+	}
+
+	return formatted[:end]
+}
+
+func padFormatted(formatted string, spec formatSpec) string {
+	padding := spec.width - utf8.RuneCountInString(formatted)
+	if padding <= 0 {
+		return formatted
+	}
+
+	switch {
+	case spec.leftAlign:
+		return formatted + strings.Repeat(" ", padding)
+
+	case spec.zeroPad:
+		sign := ""
+		if strings.HasPrefix(formatted, "-") {
+			sign = "-"
+			formatted = formatted[1:]
+		}
+		return sign + strings.Repeat("0", padding) + formatted
+
+	default:
+		return strings.Repeat(" ", padding) + formatted
+	}
+}
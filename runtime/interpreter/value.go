@@ -203,7 +203,7 @@ func (v TypeValue) GetMember(inter *Interpreter, _ func() LocationRange, name st
 		var typeID string
 		staticType := v.Type
 		if staticType != nil {
-			typeID = string(inter.ConvertStaticToSemaType(staticType).ID())
+			typeID = string(inter.SemaTypeID(inter.ConvertStaticToSemaType(staticType)))
 		}
 		return NewStringValue(typeID)
 	}
@@ -810,7 +810,9 @@ func (v *ArrayValue) Get(_ *Interpreter, getLocationRange func() LocationRange,
 	return v.Elements()[index]
 }
 
-func (v *ArrayValue) Set(_ *Interpreter, getLocationRange func() LocationRange, key Value, value Value) {
+func (v *ArrayValue) Set(inter *Interpreter, getLocationRange func() LocationRange, key Value, value Value) {
+	inter.recordMutation(v)
+
 	index := key.(NumberValue).ToInt()
 	v.SetIndex(index, value, getLocationRange)
 }
@@ -961,6 +963,96 @@ func (v *ArrayValue) Contains(needleValue Value) BoolValue {
 	return false
 }
 
+// Map returns a new array which contains the results of invoking the given function
+// with each of the elements of the array
+//
+func (v *ArrayValue) Map(invocation Invocation, transformFunction FunctionValue) *ArrayValue {
+	elementType := invocation.ArgumentTypes[0].(*sema.FunctionType).
+		Parameters[0].TypeAnnotation.Type
+
+	elements := v.Elements()
+	values := make([]Value, len(elements))
+
+	for i, element := range elements {
+		values[i] = v.invokeElementFunction(invocation, transformFunction, elementType, element)
+	}
+
+	return NewArrayValueUnownedNonCopying(values...)
+}
+
+// Filter returns a new array which contains the elements of the array
+// for which the given function returns true
+//
+func (v *ArrayValue) Filter(invocation Invocation, predicateFunction FunctionValue) *ArrayValue {
+	elementType := invocation.ArgumentTypes[0].(*sema.FunctionType).
+		Parameters[0].TypeAnnotation.Type
+
+	values := make([]Value, 0)
+
+	for _, element := range v.Elements() {
+		result := v.invokeElementFunction(invocation, predicateFunction, elementType, element)
+		if result.(BoolValue) {
+			values = append(values, element.Copy())
+		}
+	}
+
+	return NewArrayValueUnownedNonCopying(values...)
+}
+
+// Reduce combines the elements of the array using the given function,
+// starting with the given initial value
+//
+func (v *ArrayValue) Reduce(invocation Invocation, initial Value, combineFunction FunctionValue) Value {
+	combineFunctionType := invocation.ArgumentTypes[1].(*sema.FunctionType)
+	resultType := combineFunctionType.Parameters[0].TypeAnnotation.Type
+	elementType := combineFunctionType.Parameters[1].TypeAnnotation.Type
+
+	interpreter := invocation.Interpreter
+	getLocationRange := invocation.GetLocationRange
+
+	result := initial
+
+	for _, element := range v.Elements() {
+		combineInvocation := Invocation{
+			Arguments: []Value{
+				interpreter.copyAndConvert(result, nil, resultType, getLocationRange),
+				interpreter.copyAndConvert(element, nil, elementType, getLocationRange),
+			},
+			ArgumentTypes:    []sema.Type{resultType, elementType},
+			GetLocationRange: getLocationRange,
+			Interpreter:      interpreter,
+		}
+
+		result = combineFunction.Invoke(combineInvocation)
+	}
+
+	return result
+}
+
+// invokeElementFunction invokes the given function with a copy of the given element,
+// converted to the parameter type of the function
+//
+func (v *ArrayValue) invokeElementFunction(
+	invocation Invocation,
+	function FunctionValue,
+	elementType sema.Type,
+	element Value,
+) Value {
+	interpreter := invocation.Interpreter
+	getLocationRange := invocation.GetLocationRange
+
+	elementInvocation := Invocation{
+		Arguments: []Value{
+			interpreter.copyAndConvert(element, nil, elementType, getLocationRange),
+		},
+		ArgumentTypes:    []sema.Type{elementType},
+		GetLocationRange: getLocationRange,
+		Interpreter:      interpreter,
+	}
+
+	return function.Invoke(elementInvocation)
+}
+
 func (v *ArrayValue) GetMember(_ *Interpreter, _ func() LocationRange, name string) Value {
 	switch name {
 	case "length":
@@ -969,6 +1061,7 @@ func (v *ArrayValue) GetMember(_ *Interpreter, _ func() LocationRange, name stri
 	case "append":
 		return NewHostFunctionValue(
 			func(invocation Invocation) Value {
+				invocation.Interpreter.recordMutation(v)
 				v.Append(invocation.Arguments[0])
 				return VoidValue{}
 			},
@@ -978,6 +1071,7 @@ func (v *ArrayValue) GetMember(_ *Interpreter, _ func() LocationRange, name stri
 		return NewHostFunctionValue(
 			func(invocation Invocation) Value {
 				otherArray := invocation.Arguments[0].(AllAppendableValue)
+				invocation.Interpreter.recordMutation(v)
 				v.AppendAll(otherArray)
 				return VoidValue{}
 			},
@@ -996,6 +1090,7 @@ func (v *ArrayValue) GetMember(_ *Interpreter, _ func() LocationRange, name stri
 			func(invocation Invocation) Value {
 				index := invocation.Arguments[0].(NumberValue).ToInt()
 				element := invocation.Arguments[1]
+				invocation.Interpreter.recordMutation(v)
 				v.Insert(index, element, invocation.GetLocationRange)
 				return VoidValue{}
 			},
@@ -1005,6 +1100,7 @@ func (v *ArrayValue) GetMember(_ *Interpreter, _ func() LocationRange, name stri
 		return NewHostFunctionValue(
 			func(invocation Invocation) Value {
 				i := invocation.Arguments[0].(NumberValue).ToInt()
+				invocation.Interpreter.recordMutation(v)
 				return v.Remove(i, invocation.GetLocationRange)
 			},
 		)
@@ -1012,6 +1108,7 @@ func (v *ArrayValue) GetMember(_ *Interpreter, _ func() LocationRange, name stri
 	case "removeFirst":
 		return NewHostFunctionValue(
 			func(invocation Invocation) Value {
+				invocation.Interpreter.recordMutation(v)
 				return v.RemoveFirst(invocation.GetLocationRange)
 			},
 		)
@@ -1019,6 +1116,7 @@ func (v *ArrayValue) GetMember(_ *Interpreter, _ func() LocationRange, name stri
 	case "removeLast":
 		return NewHostFunctionValue(
 			func(invocation Invocation) Value {
+				invocation.Interpreter.recordMutation(v)
 				return v.RemoveLast(invocation.GetLocationRange)
 			},
 		)
@@ -1030,6 +1128,31 @@ func (v *ArrayValue) GetMember(_ *Interpreter, _ func() LocationRange, name stri
 			},
 		)
 
+	case "map":
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				transformFunction := invocation.Arguments[0].(FunctionValue)
+				return v.Map(invocation, transformFunction)
+			},
+		)
+
+	case "filter":
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				predicateFunction := invocation.Arguments[0].(FunctionValue)
+				return v.Filter(invocation, predicateFunction)
+			},
+		)
+
+	case "reduce":
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				initial := invocation.Arguments[0]
+				combineFunction := invocation.Arguments[1].(FunctionValue)
+				return v.Reduce(invocation, initial, combineFunction)
+			},
+		)
+
 	}
 
 	return nil
@@ -6612,7 +6735,7 @@ type CompositeValue struct {
 	location            common.Location
 	qualifiedIdentifier string
 	kind                common.CompositeKind
-	fields              *StringValueOrderedMap
+	fields              compositeFields
 
 	InjectedFields  *StringValueOrderedMap
 	ComputedFields  *StringComputedFieldOrderedMap
@@ -6655,16 +6778,11 @@ func NewCompositeValue(
 	fields *StringValueOrderedMap,
 	owner *common.Address,
 ) *CompositeValue {
-	// TODO: only allocate when setting a field
-	if fields == nil {
-		fields = NewStringValueOrderedMap()
-	}
-
 	return &CompositeValue{
 		location:            location,
 		qualifiedIdentifier: qualifiedIdentifier,
 		kind:                kind,
-		fields:              fields,
+		fields:              newCompositeFieldsFromOrderedMap(fields),
 		Owner:               owner,
 		modified:            true,
 	}
@@ -6690,6 +6808,8 @@ func NewDeferredCompositeValue(
 func (v *CompositeValue) Destroy(interpreter *Interpreter, getLocationRange func() LocationRange) {
 	interpreter = v.getInterpreter(interpreter)
 
+	interpreter.recordMutation(v)
+
 	// if composite was deserialized, dynamically link in the destructor
 	if v.Destructor == nil {
 		v.Destructor = interpreter.typeCodes.CompositeCodes[v.TypeID()].DestructorFunction
@@ -6721,13 +6841,13 @@ func (v *CompositeValue) Accept(interpreter *Interpreter, visitor Visitor) {
 		return
 	}
 
-	v.Fields().Foreach(func(_ string, value Value) {
+	v.ForEachField(func(_ string, value Value) {
 		value.Accept(interpreter, visitor)
 	})
 }
 
 func (v *CompositeValue) Walk(walkChild func(Value)) {
-	v.Fields().Foreach(func(_ string, value Value) {
+	v.ForEachField(func(_ string, value Value) {
 		walkChild(value)
 	})
 }
@@ -6763,7 +6883,6 @@ func (v *CompositeValue) Copy() Value {
 			location:            v.Location(),
 			qualifiedIdentifier: v.QualifiedIdentifier(),
 			kind:                v.Kind(),
-			fields:              nil,
 			InjectedFields:      v.InjectedFields,
 			ComputedFields:      v.ComputedFields,
 			NestedVariables:     v.NestedVariables,
@@ -6782,9 +6901,10 @@ func (v *CompositeValue) Copy() Value {
 		}
 	}
 
-	newFields := NewStringValueOrderedMap()
-	v.Fields().Foreach(func(fieldName string, value Value) {
-		newFields.Set(fieldName, value.Copy())
+	v.ensureFieldsLoaded()
+
+	newFields := v.fields.copy(func(value Value) Value {
+		return value.Copy()
 	})
 
 	// NOTE: not copying functions or destructor – they are linked in
@@ -6832,7 +6952,7 @@ func (v *CompositeValue) SetOwner(owner *common.Address) {
 
 	v.Owner = owner
 
-	v.Fields().Foreach(func(_ string, value Value) {
+	v.ForEachField(func(_ string, value Value) {
 		value.SetOwner(owner)
 	})
 }
@@ -6863,13 +6983,14 @@ func (v *CompositeValue) IsModified() bool {
 		return false
 	}
 
-	for pair := v.fields.Oldest(); pair != nil; pair = pair.Next() {
-		if pair.Value.IsModified() {
-			return true
+	modified := false
+	v.fields.foreach(func(_ string, value Value) {
+		if !modified && value.IsModified() {
+			modified = true
 		}
-	}
+	})
 
-	return false
+	return modified
 }
 
 func (v *CompositeValue) SetModified(modified bool) {
@@ -6877,6 +6998,19 @@ func (v *CompositeValue) SetModified(modified bool) {
 }
 
 func (v *CompositeValue) GetMember(interpreter *Interpreter, getLocationRange func() LocationRange, name string) Value {
+	return v.getMemberAt(interpreter, getLocationRange, -1, name)
+}
+
+// getMemberAt returns the member with the given name.
+// If the member is a field, the checker assigned the given slot to it,
+// or the slot is negative if it is unknown
+//
+func (v *CompositeValue) getMemberAt(
+	interpreter *Interpreter,
+	getLocationRange func() LocationRange,
+	slot int,
+	name string,
+) Value {
 	v.checkStatus(getLocationRange)
 
 	if v.Kind() == common.CompositeKindResource &&
@@ -6885,7 +7019,9 @@ func (v *CompositeValue) GetMember(interpreter *Interpreter, getLocationRange fu
 		return v.OwnerValue(interpreter)
 	}
 
-	value, ok := v.Fields().Get(name)
+	v.ensureFieldsLoadedWithTypeFields(interpreter)
+
+	value, ok := v.fields.getAt(slot, name)
 	if ok {
 		return value
 	}
@@ -6982,14 +7118,30 @@ func (v *CompositeValue) OwnerValue(interpreter *Interpreter) OptionalValue {
 	return NewSomeValueOwningNonCopying(ownerAccount)
 }
 
-func (v *CompositeValue) SetMember(_ *Interpreter, getLocationRange func() LocationRange, name string, value Value) {
+func (v *CompositeValue) SetMember(interpreter *Interpreter, getLocationRange func() LocationRange, name string, value Value) {
+	v.setMemberAt(interpreter, getLocationRange, -1, name, value)
+}
+
+// setMemberAt sets the field with the given name, which the checker assigned the given slot to,
+// or the slot is negative if it is unknown
+//
+func (v *CompositeValue) setMemberAt(
+	interpreter *Interpreter,
+	getLocationRange func() LocationRange,
+	slot int,
+	name string,
+	value Value,
+) {
 	v.checkStatus(getLocationRange)
 
+	interpreter.recordMutation(v)
+
 	v.modified = true
 
 	value.SetOwner(v.Owner)
 
-	v.Fields().Set(name, value)
+	v.ensureFieldsLoadedWithTypeFields(interpreter)
+	v.fields.setAt(slot, name, value)
 }
 
 func (v *CompositeValue) String() string {
@@ -7001,16 +7153,18 @@ func (v *CompositeValue) RecursiveString(seenReferences SeenReferences) string {
 		return v.stringer(seenReferences)
 	}
 
-	return formatComposite(string(v.TypeID()), v.Fields(), seenReferences)
+	v.ensureFieldsLoaded()
+
+	return formatComposite(string(v.TypeID()), &v.fields, seenReferences)
 }
 
-func formatComposite(typeId string, fields *StringValueOrderedMap, seenReferences SeenReferences) string {
+func formatComposite(typeId string, fields *compositeFields, seenReferences SeenReferences) string {
 	preparedFields := make([]struct {
 		Name  string
 		Value string
-	}, 0, fields.Len())
+	}, 0, fields.len())
 
-	fields.Foreach(func(fieldName string, value Value) {
+	fields.foreach(func(fieldName string, value Value) {
 		preparedFields = append(preparedFields,
 			struct {
 				Name  string
@@ -7025,48 +7179,97 @@ func formatComposite(typeId string, fields *StringValueOrderedMap, seenReference
 	return format.Composite(typeId, preparedFields)
 }
 
+// Fields returns the fields of the composite value, in the order in which they were set.
+//
+// The map is the storage of the fields, so changes to the map are changes to the fields.
+// Accessing the fields through the map is slower than accessing them with GetField and SetField
+//
+func (v *CompositeValue) Fields() *StringValueOrderedMap {
+	v.ensureFieldsLoaded()
+
+	return v.fields.toOrderedMap()
+}
+
+// GetField returns the value of the field with the given name,
+// or nil if the field is not set
+//
 func (v *CompositeValue) GetField(name string) Value {
-	value, _ := v.Fields().Get(name)
+	v.ensureFieldsLoaded()
+
+	value, _ := v.fields.get(name)
 	return value
 }
 
+// SetField sets the value of the field with the given name.
+//
+// Unlike SetMember, the owner of the value is not updated,
+// and the mutation is not recorded
+//
+func (v *CompositeValue) SetField(name string, value Value) {
+	v.ensureFieldsLoaded()
+
+	v.fields.set(name, value)
+}
+
+// FieldCount returns the number of set fields
+//
+func (v *CompositeValue) FieldCount() int {
+	v.ensureFieldsLoaded()
+
+	return v.fields.len()
+}
+
+// ForEachField calls the given function for each set field, in the order in which the fields were set
+//
+func (v *CompositeValue) ForEachField(f func(name string, value Value)) {
+	v.ensureFieldsLoaded()
+
+	v.fields.foreach(f)
+}
+
 func (v *CompositeValue) Equal(other Value, interpreter *Interpreter, loadDeferred bool) bool {
 	otherComposite, ok := other.(*CompositeValue)
 	if !ok {
 		return false
 	}
 
-	fields := v.Fields()
-	otherFields := otherComposite.Fields()
+	v.ensureFieldsLoaded()
+	otherComposite.ensureFieldsLoaded()
+
+	fields := &v.fields
+	otherFields := &otherComposite.fields
 
 	if !v.StaticType().Equal(otherComposite.StaticType()) ||
 		v.Kind() != otherComposite.Kind() ||
-		fields.Len() != otherFields.Len() {
+		fields.len() != otherFields.len() {
 
 		return false
 	}
 
-	for pair := fields.Oldest(); pair != nil; pair = pair.Next() {
-		key := pair.Key
-		value := pair.Value
+	equal := true
+	fields.foreach(func(name string, value Value) {
+		if !equal {
+			return
+		}
 
-		otherValue, ok := otherFields.Get(key)
+		otherValue, ok := otherFields.get(name)
 		if !ok {
-			return false
+			equal = false
+			return
 		}
 
 		equatableValue, ok := value.(EquatableValue)
 		if !ok || !equatableValue.Equal(otherValue, interpreter, loadDeferred) {
-			return false
+			equal = false
 		}
-	}
+	})
 
-	return true
+	return equal
 }
 
 func (v *CompositeValue) KeyString() string {
 	if v.Kind() == common.CompositeKindEnum {
-		rawValue, _ := v.Fields().Get(sema.EnumRawValueFieldName)
+		rawValue := v.GetField(sema.EnumRawValueFieldName)
 		return rawValue.String()
 	}
 
@@ -7100,9 +7303,11 @@ func (v *CompositeValue) ConformsToDynamicType(
 		return false
 	}
 
-	fields := v.Fields()
+	v.ensureFieldsLoaded()
+
+	fields := &v.fields
 
-	fieldsLen := fields.Len()
+	fieldsLen := fields.len()
 	if v.ComputedFields != nil {
 		fieldsLen += v.ComputedFields.Len()
 	}
@@ -7112,7 +7317,7 @@ func (v *CompositeValue) ConformsToDynamicType(
 	}
 
 	for _, fieldName := range compositeType.Fields {
-		field, ok := fields.Get(fieldName)
+		field, ok := fields.get(fieldName)
 		if !ok {
 			if v.ComputedFields == nil {
 				return false
@@ -7180,18 +7385,14 @@ func (v *CompositeValue) IsStorable() bool {
 		return true
 	}
 
-	for pair := v.fields.Oldest(); pair != nil; pair = pair.Next() {
-		if !pair.Value.IsStorable() {
-			return false
+	storable := true
+	v.fields.foreach(func(_ string, value Value) {
+		if storable && !value.IsStorable() {
+			storable = false
 		}
-	}
+	})
 
-	return true
-}
-
-func (v *CompositeValue) Fields() *StringValueOrderedMap {
-	v.ensureFieldsLoaded()
-	return v.fields
+	return storable
 }
 
 func (v *CompositeValue) Location() common.Location {
@@ -7237,6 +7438,26 @@ func (v *CompositeValue) ensureMetaInfoLoaded() {
 // Otherwise, the fields are decoded form the cached raw-fields-content.
 //
 func (v *CompositeValue) ensureFieldsLoaded() {
+	v.loadFields(nil)
+}
+
+// ensureFieldsLoadedWithTypeFields ensures loading the fields of this composite value,
+// like ensureFieldsLoaded.
+// If the type of the value is declared in the given interpreter,
+// the fields are decoded into the layout of the type
+//
+func (v *CompositeValue) ensureFieldsLoadedWithTypeFields(interpreter *Interpreter) {
+	// First ensure the fields content is extracted out.
+	v.ensureMetaInfoLoaded()
+
+	if v.fieldsContent == nil {
+		return
+	}
+
+	v.loadFields(interpreter.compositeTypeFields(v.TypeID()))
+}
+
+func (v *CompositeValue) loadFields(typeFields *compositeTypeFields) {
 	// First ensure the fields content is extracted out.
 	v.ensureMetaInfoLoaded()
 
@@ -7244,7 +7465,7 @@ func (v *CompositeValue) ensureFieldsLoaded() {
 		return
 	}
 
-	err := decodeCompositeFields(v, v.fieldsContent)
+	err := decodeCompositeFields(v, v.fieldsContent, typeFields)
 	if err != nil {
 		panic(err)
 	}
@@ -7257,14 +7478,18 @@ func (v *CompositeValue) ensureFieldsLoaded() {
 	v.encodingVersion = 0
 }
 
+var enumCaseFieldLayout = newCompositeFieldLayout([]string{
+	sema.EnumRawValueFieldName,
+})
+
 func NewEnumCaseValue(
 	enumType *sema.CompositeType,
 	rawValue NumberValue,
 	functions map[string]FunctionValue,
 ) *CompositeValue {
 
-	fields := NewStringValueOrderedMap()
-	fields.Set(sema.EnumRawValueFieldName, rawValue)
+	fields := newCompositeFields(enumCaseFieldLayout)
+	fields.set(sema.EnumRawValueFieldName, rawValue)
 
 	return &CompositeValue{
 		location:            enumType.Location,
@@ -7319,6 +7544,11 @@ type DictionaryValue struct {
 	// Encoding version of the raw content of the entries of this value.
 	// Only available for decoded values who's entries are not loaded yet.
 	encodingVersion uint16
+
+	// iterationCount is the number of iterations over the entries
+	// which are currently in progress. The dictionary may not be mutated
+	// while it is iterated over.
+	iterationCount int
 }
 
 func NewDictionaryValueUnownedNonCopying(keysAndValues ...Value) *DictionaryValue {
@@ -7416,6 +7646,9 @@ func (v *DictionaryValue) Walk(walkChild func(Value)) {
 
 func (v *DictionaryValue) DynamicType(interpreter *Interpreter, seenReferences SeenReferences) DynamicType {
 	keys := v.Keys().Elements()
+
+	v.prefetchDeferredValues(interpreter)
+
 	entryTypes := make([]struct{ KeyType, ValueType DynamicType }, len(keys))
 
 	for i, key := range keys {
@@ -7543,6 +7776,8 @@ func maybeDestroy(inter *Interpreter, getLocationRange func() LocationRange, val
 func (v *DictionaryValue) Destroy(inter *Interpreter, getLocationRange func() LocationRange) {
 	v.ensureLoaded()
 
+	v.prefetchDeferredValues(inter)
+
 	for _, keyValue := range v.keys.Elements() {
 		// Don't use `Entries` here: the value might be deferred and needs to be loaded
 		value := v.Get(inter, getLocationRange, keyValue)
@@ -7609,6 +7844,24 @@ func (v *DictionaryValue) Get(inter *Interpreter, _ func() LocationRange, keyVal
 
 }
 
+// prefetchDeferredValues requests all deferred values of the dictionary from storage at once,
+// so loading them individually does not require a separate storage read for each value
+//
+func (v *DictionaryValue) prefetchDeferredValues(inter *Interpreter) {
+	v.ensureLoaded()
+
+	if v.deferredKeys == nil || v.deferredKeys.Len() == 0 {
+		return
+	}
+
+	storageKeys := make([]string, 0, v.deferredKeys.Len())
+	for pair := v.deferredKeys.Oldest(); pair != nil; pair = pair.Next() {
+		storageKeys = append(storageKeys, joinPathElements(v.deferredStorageKeyBase, pair.Key))
+	}
+
+	inter.prefetchStored(*v.deferredOwner, storageKeys)
+}
+
 func dictionaryKey(keyValue Value) string {
 	hasKeyString, ok := keyValue.(HasKeyString)
 	if !ok {
@@ -7618,6 +7871,8 @@ func dictionaryKey(keyValue Value) string {
 }
 
 func (v *DictionaryValue) Set(inter *Interpreter, getLocationRange func() LocationRange, keyValue Value, value Value) {
+	inter.recordMutation(v)
+
 	v.modified = true
 
 	switch typedValue := value.(type) {
@@ -7683,6 +7938,8 @@ func (v *DictionaryValue) GetMember(interpreter *Interpreter, getLocationRange f
 
 	// TODO: is returning copies correct?
 	case "values":
+		v.prefetchDeferredValues(interpreter)
+
 		dictionaryValues := make([]Value, v.Count())
 		i := 0
 		for _, keyValue := range v.Keys().Elements() {
@@ -7727,11 +7984,137 @@ func (v *DictionaryValue) GetMember(interpreter *Interpreter, getLocationRange f
 			},
 		)
 
+	case "forEachKey":
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				function := invocation.Arguments[0].(FunctionValue)
+
+				v.ForEachKey(func(key Value) bool {
+					return invokeIterationFunction(invocation, function, key)
+				})
+
+				return VoidValue{}
+			},
+		)
+
+	case "forEachValue":
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				function := invocation.Arguments[0].(FunctionValue)
+
+				v.ForEach(
+					invocation.Interpreter,
+					invocation.GetLocationRange,
+					func(_ Value, value Value) bool {
+						return invokeIterationFunction(invocation, function, value)
+					},
+				)
+
+				return VoidValue{}
+			},
+		)
+
+	case "forEach":
+		return NewHostFunctionValue(
+			func(invocation Invocation) Value {
+				function := invocation.Arguments[0].(FunctionValue)
+
+				v.ForEach(
+					invocation.Interpreter,
+					invocation.GetLocationRange,
+					func(key Value, value Value) bool {
+						return invokeIterationFunction(invocation, function, key, value)
+					},
+				)
+
+				return VoidValue{}
+			},
+		)
+
 	}
 
 	return nil
 }
 
+// ForEachKey invokes the given function for each key of the dictionary, in order,
+// until the function returns false.
+//
+// Deferred values are not loaded from storage.
+//
+func (v *DictionaryValue) ForEachKey(function func(key Value) bool) {
+	v.iterationCount++
+	defer func() {
+		v.iterationCount--
+	}()
+
+	for _, keyValue := range v.Keys().Elements() {
+		if !function(keyValue) {
+			return
+		}
+	}
+}
+
+// ForEach invokes the given function for each entry of the dictionary, in order,
+// until the function returns false.
+//
+// The given function is invoked with the key and the value of each entry.
+// The value is loaded from storage if it is deferred.
+//
+func (v *DictionaryValue) ForEach(
+	inter *Interpreter,
+	getLocationRange func() LocationRange,
+	function func(key Value, value Value) bool,
+) {
+	v.prefetchDeferredValues(inter)
+
+	v.ForEachKey(func(keyValue Value) bool {
+		value := v.Get(inter, getLocationRange, keyValue).(*SomeValue).Value
+		return function(keyValue, value)
+	})
+}
+
+// checkMutation panics if the dictionary is currently iterated over
+//
+func (v *DictionaryValue) checkMutation(getLocationRange func() LocationRange) {
+	if v.iterationCount > 0 {
+		panic(ContainerMutatedDuringIterationError{
+			LocationRange: getLocationRange(),
+		})
+	}
+}
+
+// invokeIterationFunction invokes the given function with copies of the given arguments,
+// converted to the parameter types of the function, and returns whether to continue iterating
+//
+func invokeIterationFunction(
+	invocation Invocation,
+	function FunctionValue,
+	arguments ...Value,
+) bool {
+	interpreter := invocation.Interpreter
+	getLocationRange := invocation.GetLocationRange
+
+	functionType := invocation.ArgumentTypes[0].(*sema.FunctionType)
+
+	argumentTypes := make([]sema.Type, len(arguments))
+	argumentCopies := make([]Value, len(arguments))
+
+	for i, argument := range arguments {
+		parameterType := functionType.Parameters[i].TypeAnnotation.Type
+		argumentTypes[i] = parameterType
+		argumentCopies[i] = interpreter.copyAndConvert(argument, nil, parameterType, getLocationRange)
+	}
+
+	result := function.Invoke(Invocation{
+		Arguments:        argumentCopies,
+		ArgumentTypes:    argumentTypes,
+		GetLocationRange: getLocationRange,
+		Interpreter:      interpreter,
+	})
+
+	return bool(result.(BoolValue))
+}
+
 func (v *DictionaryValue) SetMember(_ *Interpreter, _ func() LocationRange, _ string, _ Value) {
 	// Dictionaries have no settable members (fields / functions)
 	panic(errors.NewUnreachableError())
@@ -7743,6 +8126,10 @@ func (v *DictionaryValue) Count() int {
 
 // TODO: unset owner?
 func (v *DictionaryValue) Remove(inter *Interpreter, getLocationRange func() LocationRange, keyValue Value) OptionalValue {
+	v.checkMutation(getLocationRange)
+
+	inter.recordMutation(v)
+
 	v.modified = true
 
 	// Don't use `Entries` here: the value might be deferred and needs to be loaded
@@ -7788,6 +8175,10 @@ func (v *DictionaryValue) Remove(inter *Interpreter, getLocationRange func() Loc
 }
 
 func (v *DictionaryValue) Insert(inter *Interpreter, locationRangeGetter func() LocationRange, keyValue, value Value) OptionalValue {
+	v.checkMutation(locationRangeGetter)
+
+	inter.recordMutation(v)
+
 	v.modified = true
 
 	v.ensureLoaded()
@@ -8764,6 +9155,15 @@ func (AddressValue) IsStorable() bool {
 	return true
 }
 
+var authAccountFieldLayout = newCompositeFieldLayout([]string{
+	sema.AuthAccountAddressField,
+	sema.AuthAccountAddPublicKeyField,
+	sema.AuthAccountRemovePublicKeyField,
+	sema.AuthAccountGetCapabilityField,
+	sema.AuthAccountContractsField,
+	sema.AuthAccountKeysField,
+})
+
 // NewAuthAccountValue constructs an auth account value.
 func NewAuthAccountValue(
 	address AddressValue,
@@ -8777,13 +9177,13 @@ func NewAuthAccountValue(
 	keys *CompositeValue,
 ) *CompositeValue {
 
-	fields := NewStringValueOrderedMap()
-	fields.Set(sema.AuthAccountAddressField, address)
-	fields.Set(sema.AuthAccountAddPublicKeyField, addPublicKeyFunction)
-	fields.Set(sema.AuthAccountRemovePublicKeyField, removePublicKeyFunction)
-	fields.Set(sema.AuthAccountGetCapabilityField, accountGetCapabilityFunction(address, sema.CapabilityPathType))
-	fields.Set(sema.AuthAccountContractsField, contracts)
-	fields.Set(sema.AuthAccountKeysField, keys)
+	fields := newCompositeFields(authAccountFieldLayout)
+	fields.set(sema.AuthAccountAddressField, address)
+	fields.set(sema.AuthAccountAddPublicKeyField, addPublicKeyFunction)
+	fields.set(sema.AuthAccountRemovePublicKeyField, removePublicKeyFunction)
+	fields.set(sema.AuthAccountGetCapabilityField, accountGetCapabilityFunction(address, sema.CapabilityPathType))
+	fields.set(sema.AuthAccountContractsField, contracts)
+	fields.set(sema.AuthAccountKeysField, keys)
 
 	// Computed fields
 	computedFields := NewStringComputedFieldOrderedMap()
@@ -8820,6 +9220,14 @@ func NewAuthAccountValue(
 		return inter.authAccountBorrowFunction(address)
 	})
 
+	computedFields.Set(sema.AuthAccountTypeField, func(inter *Interpreter) Value {
+		return inter.authAccountTypeFunction(address)
+	})
+
+	computedFields.Set(sema.AuthAccountCheckField, func(inter *Interpreter) Value {
+		return inter.authAccountCheckFunction(address)
+	})
+
 	computedFields.Set(sema.AuthAccountLinkField, func(inter *Interpreter) Value {
 		return inter.authAccountLinkFunction(address)
 	})
@@ -8832,6 +9240,22 @@ func NewAuthAccountValue(
 		return inter.accountGetLinkTargetFunction(address)
 	})
 
+	computedFields.Set(sema.AuthAccountForEachStoredField, func(inter *Interpreter) Value {
+		return inter.authAccountForEachStoredFunction(address)
+	})
+
+	computedFields.Set(sema.AuthAccountForEachPublicField, func(inter *Interpreter) Value {
+		return inter.accountForEachPublicFunction(address)
+	})
+
+	computedFields.Set(sema.AuthAccountForEachLinkField, func(inter *Interpreter) Value {
+		return inter.authAccountForEachLinkFunction(address)
+	})
+
+	computedFields.Set(sema.AuthAccountUnlinkAllField, func(inter *Interpreter) Value {
+		return inter.authAccountUnlinkAllFunction(address)
+	})
+
 	stringer := func(_ SeenReferences) string {
 		return fmt.Sprintf("AuthAccount(%s)", address)
 	}
@@ -8881,6 +9305,13 @@ func accountGetCapabilityFunction(addressValue AddressValue, pathType sema.Type)
 	)
 }
 
+var publicAccountFieldLayout = newCompositeFieldLayout([]string{
+	sema.PublicAccountAddressField,
+	sema.PublicAccountGetCapabilityField,
+	sema.PublicAccountKeysField,
+	sema.PublicAccountContractsField,
+})
+
 // NewPublicAccountValue constructs a public account value.
 func NewPublicAccountValue(
 	address AddressValue,
@@ -8892,11 +9323,11 @@ func NewPublicAccountValue(
 	contracts *CompositeValue,
 ) *CompositeValue {
 
-	fields := NewStringValueOrderedMap()
-	fields.Set(sema.PublicAccountAddressField, address)
-	fields.Set(sema.PublicAccountGetCapabilityField, accountGetCapabilityFunction(address, sema.PublicPathType))
-	fields.Set(sema.PublicAccountKeysField, keys)
-	fields.Set(sema.PublicAccountContractsField, contracts)
+	fields := newCompositeFields(publicAccountFieldLayout)
+	fields.set(sema.PublicAccountAddressField, address)
+	fields.set(sema.PublicAccountGetCapabilityField, accountGetCapabilityFunction(address, sema.PublicPathType))
+	fields.set(sema.PublicAccountKeysField, keys)
+	fields.set(sema.PublicAccountContractsField, contracts)
 
 	// Computed fields
 	computedFields := NewStringComputedFieldOrderedMap()
@@ -8921,6 +9352,10 @@ func NewPublicAccountValue(
 		return inter.accountGetLinkTargetFunction(address)
 	})
 
+	computedFields.Set(sema.PublicAccountForEachPublicField, func(inter *Interpreter) Value {
+		return inter.accountForEachPublicFunction(address)
+	})
+
 	// Stringer function
 	stringer := func(_ SeenReferences) string {
 		return fmt.Sprintf("PublicAccount(%s)", address)
@@ -9146,6 +9581,9 @@ func (v CapabilityValue) GetMember(inter *Interpreter, _ func() LocationRange, n
 		}
 		return inter.capabilityCheckFunction(v.Address, v.Path, borrowType)
 
+	case "targetType":
+		return inter.capabilityTargetTypeFunction(v.Address, v.Path)
+
 	case "address":
 		return v.Address
 	}
@@ -9268,6 +9706,14 @@ func (LinkValue) IsStorable() bool {
 	return true
 }
 
+var accountKeyFieldLayout = newCompositeFieldLayout([]string{
+	sema.AccountKeyKeyIndexField,
+	sema.AccountKeyPublicKeyField,
+	sema.AccountKeyHashAlgoField,
+	sema.AccountKeyWeightField,
+	sema.AccountKeyIsRevokedField,
+})
+
 // NewAccountKeyValue constructs an AccountKey value.
 func NewAccountKeyValue(
 	keyIndex IntValue,
@@ -9276,12 +9722,12 @@ func NewAccountKeyValue(
 	weight UFix64Value,
 	isRevoked BoolValue,
 ) *CompositeValue {
-	fields := NewStringValueOrderedMap()
-	fields.Set(sema.AccountKeyKeyIndexField, keyIndex)
-	fields.Set(sema.AccountKeyPublicKeyField, publicKey)
-	fields.Set(sema.AccountKeyHashAlgoField, hashAlgo)
-	fields.Set(sema.AccountKeyWeightField, weight)
-	fields.Set(sema.AccountKeyIsRevokedField, isRevoked)
+	fields := newCompositeFields(accountKeyFieldLayout)
+	fields.set(sema.AccountKeyKeyIndexField, keyIndex)
+	fields.set(sema.AccountKeyPublicKeyField, publicKey)
+	fields.set(sema.AccountKeyHashAlgoField, hashAlgo)
+	fields.set(sema.AccountKeyWeightField, weight)
+	fields.set(sema.AccountKeyIsRevokedField, isRevoked)
 
 	return &CompositeValue{
 		qualifiedIdentifier: sema.AccountKeyType.QualifiedIdentifier(),
@@ -9290,6 +9736,11 @@ func NewAccountKeyValue(
 	}
 }
 
+var publicKeyFieldLayout = newCompositeFieldLayout([]string{
+	sema.PublicKeySignAlgoField,
+	sema.PublicKeyIsValidField,
+})
+
 // NewPublicKeyValue constructs a PublicKey value.
 func NewPublicKeyValue(
 	publicKey *ArrayValue,
@@ -9297,8 +9748,8 @@ func NewPublicKeyValue(
 	validatePublicKey PublicKeyValidationHandlerFunc,
 ) *CompositeValue {
 
-	fields := NewStringValueOrderedMap()
-	fields.Set(sema.PublicKeySignAlgoField, signAlgo)
+	fields := newCompositeFields(publicKeyFieldLayout)
+	fields.set(sema.PublicKeySignAlgoField, signAlgo)
 
 	computedFields := NewStringComputedFieldOrderedMap()
 	computedFields.Set(
@@ -9309,7 +9760,8 @@ func NewPublicKeyValue(
 	)
 
 	functions := map[string]FunctionValue{
-		sema.PublicKeyVerifyFunction: publicKeyVerifyFunction,
+		sema.PublicKeyVerifyFunction:    publicKeyVerifyFunction,
+		sema.PublicKeyVerifyPoPFunction: publicKeyVerifyPoPFunction,
 	}
 
 	publicKeyValue := &CompositeValue{
@@ -9322,20 +9774,21 @@ func NewPublicKeyValue(
 
 	// Validate the public key, and initialize 'isValid' field.
 
-	publicKeyValue.fields.Set(
+	publicKeyValue.fields.set(
 		sema.PublicKeyIsValidField,
 		validatePublicKey(publicKeyValue),
 	)
 
 	// Public key value to string should include the key even though it is a computed field
-	var stringerFields *StringValueOrderedMap
+	var stringerFields *compositeFields
 	publicKeyValue.stringer = func(seenReferences SeenReferences) string {
 		if stringerFields == nil {
-			stringerFields = NewStringValueOrderedMap()
-			stringerFields.Set(sema.PublicKeyPublicKeyField, publicKey.Copy())
-			publicKeyValue.Fields().Foreach(func(key string, value Value) {
-				stringerFields.Set(key, value)
+			fields := newCompositeFields(nil)
+			fields.set(sema.PublicKeyPublicKeyField, publicKey.Copy())
+			publicKeyValue.ForEachField(func(key string, value Value) {
+				fields.set(key, value)
 			})
+			stringerFields = &fields
 		}
 		return formatComposite(
 			string(publicKeyValue.TypeID()),
@@ -9365,12 +9818,30 @@ var publicKeyVerifyFunction = NewHostFunctionValue(
 	},
 )
 
+var publicKeyVerifyPoPFunction = NewHostFunctionValue(
+	func(invocation Invocation) Value {
+		signatureValue := invocation.Arguments[0].(*ArrayValue)
+		publicKey := invocation.Self
+
+		return invocation.Interpreter.BLSVerifyPoPHandler(
+			publicKey,
+			signatureValue,
+		)
+	},
+)
+
+var authAccountKeysFieldLayout = newCompositeFieldLayout([]string{
+	sema.AccountKeysAddFunctionName,
+	sema.AccountKeysGetFunctionName,
+	sema.AccountKeysRevokeFunctionName,
+})
+
 // NewAuthAccountKeysValue constructs a AuthAccount.Keys value.
 func NewAuthAccountKeysValue(addFunction FunctionValue, getFunction FunctionValue, revokeFunction FunctionValue) *CompositeValue {
-	fields := NewStringValueOrderedMap()
-	fields.Set(sema.AccountKeysAddFunctionName, addFunction)
-	fields.Set(sema.AccountKeysGetFunctionName, getFunction)
-	fields.Set(sema.AccountKeysRevokeFunctionName, revokeFunction)
+	fields := newCompositeFields(authAccountKeysFieldLayout)
+	fields.set(sema.AccountKeysAddFunctionName, addFunction)
+	fields.set(sema.AccountKeysGetFunctionName, getFunction)
+	fields.set(sema.AccountKeysRevokeFunctionName, revokeFunction)
 
 	return &CompositeValue{
 		qualifiedIdentifier: sema.AuthAccountKeysType.QualifiedIdentifier(),
@@ -9379,10 +9850,14 @@ func NewAuthAccountKeysValue(addFunction FunctionValue, getFunction FunctionValu
 	}
 }
 
+var publicAccountKeysFieldLayout = newCompositeFieldLayout([]string{
+	sema.AccountKeysGetFunctionName,
+})
+
 // NewPublicAccountKeysValue constructs a PublicAccount.Keys value.
 func NewPublicAccountKeysValue(getFunction FunctionValue) *CompositeValue {
-	fields := NewStringValueOrderedMap()
-	fields.Set(sema.AccountKeysGetFunctionName, getFunction)
+	fields := newCompositeFields(publicAccountKeysFieldLayout)
+	fields.set(sema.AccountKeysGetFunctionName, getFunction)
 
 	return &CompositeValue{
 		qualifiedIdentifier: sema.PublicAccountKeysType.QualifiedIdentifier(),
@@ -9393,14 +9868,18 @@ func NewPublicAccountKeysValue(getFunction FunctionValue) *CompositeValue {
 
 // PublicAccountContractsValue
 
+var publicAccountContractsFieldLayout = newCompositeFieldLayout([]string{
+	sema.PublicAccountContractsTypeGetFunctionName,
+})
+
 func NewPublicAccountContractsValue(
 	address AddressValue,
 	getFunction FunctionValue,
 	namesGet func() *ArrayValue,
 ) *CompositeValue {
 
-	fields := NewStringValueOrderedMap()
-	fields.Set(sema.PublicAccountContractsTypeGetFunctionName, getFunction)
+	fields := newCompositeFields(publicAccountContractsFieldLayout)
+	fields.set(sema.PublicAccountContractsTypeGetFunctionName, getFunction)
 
 	computedFields := NewStringComputedFieldOrderedMap()
 	computedFields.Set(sema.PublicAccountContractsTypeNamesField, func(*Interpreter) Value {
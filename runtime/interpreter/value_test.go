@@ -350,7 +350,7 @@ func TestSetOwnerComposite(t *testing.T) {
 
 	const fieldName = "test"
 
-	composite.fields.Set(fieldName, value)
+	composite.fields.set(fieldName, value)
 
 	composite.SetOwner(&newOwner)
 
@@ -369,13 +369,13 @@ func TestSetOwnerCompositeCopy(t *testing.T) {
 
 	const fieldName = "test"
 
-	composite.fields.Set(fieldName, value)
+	composite.fields.set(fieldName, value)
 	composite.stringer = func(_ SeenReferences) string {
 		return "random string"
 	}
 
 	compositeCopy := composite.Copy().(*CompositeValue)
-	valueCopy, _ := compositeCopy.fields.Get(fieldName)
+	valueCopy, _ := compositeCopy.fields.get(fieldName)
 
 	assert.Nil(t, compositeCopy.GetOwner())
 	assert.Nil(t, valueCopy.GetOwner())
@@ -2124,7 +2124,7 @@ func TestPublicKeyValue(t *testing.T) {
 			return &CompositeValue{
 				qualifiedIdentifier: sema.SignatureAlgorithmType.QualifiedIdentifier(),
 				kind:                sema.SignatureAlgorithmType.Kind,
-				fields:              fields,
+				fields:              newCompositeFieldsFromOrderedMap(fields),
 			}
 		}
 
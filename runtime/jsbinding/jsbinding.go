@@ -0,0 +1,465 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package jsbinding implements the functionality exposed to JavaScript
+// by the WebAssembly build of Cadence (see runtime/cmd/wasm).
+//
+// A session parses, checks, and executes programs against an in-memory ledger.
+// All functions take and return strings, and results are encoded as JSON,
+// so they can be passed across the JavaScript boundary without conversions.
+// Values are encoded in the JSON-Cadence Data Interchange Format.
+//
+// To keep the memory use of the browser bounded, code size and computation are limited.
+//
+package jsbinding
+
+import (
+	"encoding/json"
+	goErrors "errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/harness"
+	"github.com/onflow/cadence/runtime/parser2"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// DefaultMaxCodeSize is the default maximum size of code, in bytes
+//
+const DefaultMaxCodeSize = 1 << 20
+
+// DefaultComputationLimit is the default computation limit of executions
+//
+const DefaultComputationLimit = 1_000_000
+
+// Option is a session option
+//
+type Option func(*Session)
+
+// WithMaxCodeSize returns a session option which sets the maximum size of code, in bytes
+//
+func WithMaxCodeSize(maxCodeSize int) Option {
+	return func(session *Session) {
+		session.maxCodeSize = maxCodeSize
+	}
+}
+
+// WithComputationLimit returns a session option which sets the computation limit of executions
+//
+func WithComputationLimit(limit uint64) Option {
+	return func(session *Session) {
+		session.computationLimit = limit
+	}
+}
+
+// Session is a sequence of parses, checks, and executions against the same ledger
+//
+type Session struct {
+	harness          *harness.Harness
+	maxCodeSize      int
+	computationLimit uint64
+}
+
+// NewSession returns a new session with an empty ledger
+//
+func NewSession(options ...Option) *Session {
+	session := &Session{
+		maxCodeSize:      DefaultMaxCodeSize,
+		computationLimit: DefaultComputationLimit,
+	}
+
+	for _, option := range options {
+		option(session)
+	}
+
+	session.harness = harness.New(
+		harness.WithComputationLimit(session.computationLimit),
+	)
+
+	return session
+}
+
+// Diagnostic is a single error, e.g. a syntax error or a semantic error of a program
+//
+type Diagnostic struct {
+	Message  string        `json:"message"`
+	StartPos *ast.Position `json:"startPos,omitempty"`
+	EndPos   *ast.Position `json:"endPos,omitempty"`
+}
+
+// Error is the error of a failed function
+//
+type Error struct {
+	Message     string       `json:"message"`
+	Diagnostics []Diagnostic `json:"diagnostics,omitempty"`
+}
+
+func newError(err error) *Error {
+	var diagnostics []Diagnostic
+	collectDiagnostics(err, &diagnostics)
+
+	return &Error{
+		Message:     err.Error(),
+		Diagnostics: diagnostics,
+	}
+}
+
+// collectDiagnostics collects the errors contained in the given error.
+//
+// Errors of imported programs are reported as a single diagnostic at the import,
+// as the positions of their child errors refer to the imported program
+//
+func collectDiagnostics(err error, diagnostics *[]Diagnostic) {
+	if _, ok := err.(*sema.ImportedProgramError); !ok {
+
+		if parentError, ok := err.(errors.ParentError); ok {
+			for _, childError := range parentError.ChildErrors() {
+				collectDiagnostics(childError, diagnostics)
+			}
+			return
+		}
+
+		if _, ok := err.(ast.HasPosition); !ok {
+			if unwrapped := goErrors.Unwrap(err); unwrapped != nil {
+				collectDiagnostics(unwrapped, diagnostics)
+				return
+			}
+		}
+	}
+
+	diagnostic := Diagnostic{
+		Message: err.Error(),
+	}
+
+	if hasPosition, ok := err.(ast.HasPosition); ok {
+		startPos := hasPosition.StartPosition()
+		endPos := hasPosition.EndPosition()
+		diagnostic.StartPos = &startPos
+		diagnostic.EndPos = &endPos
+	}
+
+	*diagnostics = append(*diagnostics, diagnostic)
+}
+
+// encodeResult encodes the given result as JSON.
+//
+// Panics while producing the result are reported as errors,
+// as they would otherwise terminate the WebAssembly program
+//
+func encodeResult(f func() (interface{}, error)) string {
+	var result interface{}
+	var err error
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%s\n%s", r, debug.Stack())
+			}
+		}()
+
+		result, err = f()
+	}()
+
+	if err != nil {
+		result = struct {
+			Error *Error `json:"error"`
+		}{
+			Error: newError(err),
+		}
+	}
+
+	serialized, err := json.Marshal(result)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(serialized)
+}
+
+// CodeTooLargeError is reported when code exceeds the maximum code size
+//
+type CodeTooLargeError struct {
+	Size    int
+	MaxSize int
+}
+
+func (e CodeTooLargeError) Error() string {
+	return fmt.Sprintf("code is too large: %d bytes, maximum is %d bytes", e.Size, e.MaxSize)
+}
+
+func (s *Session) checkCodeSize(code string) error {
+	if s.maxCodeSize > 0 && len(code) > s.maxCodeSize {
+		return CodeTooLargeError{
+			Size:    len(code),
+			MaxSize: s.maxCodeSize,
+		}
+	}
+	return nil
+}
+
+func decodeArguments(encodedArguments string) ([]cadence.Value, error) {
+	if encodedArguments == "" {
+		return nil, nil
+	}
+
+	var rawArguments []json.RawMessage
+	err := json.Unmarshal([]byte(encodedArguments), &rawArguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode arguments: %w", err)
+	}
+
+	arguments := make([]cadence.Value, len(rawArguments))
+	for i, rawArgument := range rawArguments {
+		arguments[i], err = jsoncdc.Decode(rawArgument)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode argument %d: %w", i, err)
+		}
+	}
+
+	return arguments, nil
+}
+
+func decodeAddresses(encodedAddresses string) ([]common.Address, error) {
+	if encodedAddresses == "" {
+		return nil, nil
+	}
+
+	var hexAddresses []string
+	err := json.Unmarshal([]byte(encodedAddresses), &hexAddresses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode addresses: %w", err)
+	}
+
+	addresses := make([]common.Address, len(hexAddresses))
+	for i, hexAddress := range hexAddresses {
+		addresses[i], err = common.HexToAddress(hexAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode address %d: %w", i, err)
+		}
+	}
+
+	return addresses, nil
+}
+
+func encodeEvents(events []cadence.Event) ([]json.RawMessage, error) {
+	encodedEvents := make([]json.RawMessage, len(events))
+	for i, event := range events {
+		encodedEvent, err := jsoncdc.Encode(event)
+		if err != nil {
+			return nil, err
+		}
+		encodedEvents[i] = encodedEvent
+	}
+	return encodedEvents, nil
+}
+
+// Parse parses the given program.
+//
+// The result has the form `{"program": ...}`, where the program is the JSON representation of the AST
+//
+func (s *Session) Parse(code string) string {
+	return encodeResult(func() (interface{}, error) {
+		err := s.checkCodeSize(code)
+		if err != nil {
+			return nil, err
+		}
+
+		program, err := parser2.ParseProgram(code)
+		if err != nil {
+			return nil, err
+		}
+
+		return struct {
+			Program *ast.Program `json:"program"`
+		}{
+			Program: program,
+		}, nil
+	})
+}
+
+// Check parses and checks the given program.
+// Imports of contracts deployed in the session are supported.
+//
+// The result has the form `{"valid": true}`
+//
+func (s *Session) Check(code string) string {
+	return encodeResult(func() (interface{}, error) {
+		err := s.checkCodeSize(code)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.harness.ParseAndCheckProgram(code)
+		if err != nil {
+			return nil, err
+		}
+
+		return struct {
+			Valid bool `json:"valid"`
+		}{
+			Valid: true,
+		}, nil
+	})
+}
+
+// CreateAccount creates a new account.
+//
+// The result has the form `{"address": "0x1"}`
+//
+func (s *Session) CreateAccount() string {
+	return encodeResult(func() (interface{}, error) {
+		address := s.harness.CreateAccount()
+
+		return struct {
+			Address string `json:"address"`
+		}{
+			Address: address.ShortHexWithPrefix(),
+		}, nil
+	})
+}
+
+type transactionResult struct {
+	Events []json.RawMessage `json:"events"`
+	Logs   []string          `json:"logs"`
+}
+
+func newTransactionResult(result *harness.TransactionResult) (*transactionResult, error) {
+	events, err := encodeEvents(result.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := result.Logs
+	if logs == nil {
+		logs = []string{}
+	}
+
+	return &transactionResult{
+		Events: events,
+		Logs:   logs,
+	}, nil
+}
+
+// DeployContract deploys the contract with the given name and code to the account with the given address.
+//
+// The result has the form `{"events": [...], "logs": [...]}`
+//
+func (s *Session) DeployContract(address string, name string, code string) string {
+	return encodeResult(func() (interface{}, error) {
+		err := s.checkCodeSize(code)
+		if err != nil {
+			return nil, err
+		}
+
+		accountAddress, err := common.HexToAddress(address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode address: %w", err)
+		}
+
+		result, err := s.harness.DeployContract(accountAddress, name, code)
+		if err != nil {
+			return nil, err
+		}
+
+		return newTransactionResult(result)
+	})
+}
+
+// ExecuteTransaction executes the given transaction.
+//
+// The arguments are a JSON array of JSON-Cadence encoded values,
+// and the signers are a JSON array of addresses. Both may be empty.
+//
+// The result has the form `{"events": [...], "logs": [...]}`
+//
+func (s *Session) ExecuteTransaction(code string, encodedArguments string, encodedSigners string) string {
+	return encodeResult(func() (interface{}, error) {
+		err := s.checkCodeSize(code)
+		if err != nil {
+			return nil, err
+		}
+
+		arguments, err := decodeArguments(encodedArguments)
+		if err != nil {
+			return nil, err
+		}
+
+		signers, err := decodeAddresses(encodedSigners)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := s.harness.ExecuteTransaction(code, arguments, signers...)
+		if err != nil {
+			return nil, err
+		}
+
+		return newTransactionResult(result)
+	})
+}
+
+// ExecuteScript executes the given script.
+//
+// The arguments are a JSON array of JSON-Cadence encoded values, and may be empty.
+//
+// The result has the form `{"value": ..., "logs": [...]}`,
+// where the value is the JSON-Cadence encoded result of the script
+//
+func (s *Session) ExecuteScript(code string, encodedArguments string) string {
+	return encodeResult(func() (interface{}, error) {
+		err := s.checkCodeSize(code)
+		if err != nil {
+			return nil, err
+		}
+
+		arguments, err := decodeArguments(encodedArguments)
+		if err != nil {
+			return nil, err
+		}
+
+		logCount := len(s.harness.Logs())
+
+		value, err := s.harness.ExecuteScript(code, arguments...)
+		if err != nil {
+			return nil, err
+		}
+
+		encodedValue, err := jsoncdc.Encode(value)
+		if err != nil {
+			return nil, err
+		}
+
+		logs := s.harness.Logs()[logCount:]
+		if len(logs) == 0 {
+			logs = []string{}
+		}
+
+		return struct {
+			Value json.RawMessage `json:"value"`
+			Logs  []string        `json:"logs"`
+		}{
+			Value: encodedValue,
+			Logs:  logs,
+		}, nil
+	})
+}
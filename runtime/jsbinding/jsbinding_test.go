@@ -0,0 +1,197 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jsbinding
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime"
+)
+
+func decodeResult(t *testing.T, result string) map[string]interface{} {
+	var decoded map[string]interface{}
+	err := json.Unmarshal([]byte(result), &decoded)
+	require.NoError(t, err)
+	return decoded
+}
+
+func TestSessionParse(t *testing.T) {
+
+	t.Parallel()
+
+	session := NewSession()
+
+	result := decodeResult(t, session.Parse(`pub fun test() {}`))
+	require.NotContains(t, result, "error")
+	require.Contains(t, result, "program")
+
+	result = decodeResult(t, session.Parse(`pub fun test( {}`))
+	require.Contains(t, result, "error")
+
+	diagnostics := result["error"].(map[string]interface{})["diagnostics"].([]interface{})
+	require.Len(t, diagnostics, 1)
+	assert.Contains(t, diagnostics[0], "startPos")
+}
+
+func TestSessionCheck(t *testing.T) {
+
+	t.Parallel()
+
+	session := NewSession()
+
+	assert.JSONEq(t,
+		`{"valid": true}`,
+		session.Check(`pub fun test(): Int { return 1 }`),
+	)
+
+	result := decodeResult(t, session.Check(`
+      pub fun test(): Int {
+          return "1"
+      }
+
+      pub fun test2(): Int {
+          return x
+      }
+    `))
+
+	diagnostics := result["error"].(map[string]interface{})["diagnostics"].([]interface{})
+	require.Len(t, diagnostics, 2)
+
+	firstDiagnostic := diagnostics[0].(map[string]interface{})
+	assert.Contains(t, firstDiagnostic["message"], "mismatched types")
+	assert.Equal(t, float64(3), firstDiagnostic["startPos"].(map[string]interface{})["Line"])
+}
+
+func TestSessionExecution(t *testing.T) {
+
+	t.Parallel()
+
+	session := NewSession()
+
+	var account struct {
+		Address string `json:"address"`
+	}
+	err := json.Unmarshal([]byte(session.CreateAccount()), &account)
+	require.NoError(t, err)
+	require.Equal(t, "0x1", account.Address)
+
+	result := decodeResult(t, session.DeployContract(account.Address, "Test", `
+      pub contract Test {
+          pub var values: [String]
+
+          init() {
+              self.values = []
+          }
+
+          pub fun add(_ value: String) {
+              self.values.append(value)
+          }
+      }
+    `))
+	require.NotContains(t, result, "error")
+
+	// Imports of deployed contracts can be checked
+
+	assert.JSONEq(t,
+		`{"valid": true}`,
+		session.Check(`
+          import Test from 0x1
+
+          pub fun main(): [String] {
+              return Test.values
+          }
+        `),
+	)
+
+	result = decodeResult(t, session.ExecuteTransaction(
+		`
+          import Test from 0x1
+
+          transaction(value: String) {
+              prepare(signer: AuthAccount) {
+                  log(signer.address)
+                  Test.add(value)
+              }
+          }
+        `,
+		`[{"type": "String", "value": "a"}]`,
+		`["0x1"]`,
+	))
+	require.NotContains(t, result, "error")
+	assert.Equal(t, []interface{}{"0x1"}, result["logs"])
+
+	assert.JSONEq(t,
+		`{
+          "value": {"type": "Array", "value": [{"type": "String", "value": "a"}]},
+          "logs": ["\"test\""]
+        }`,
+		session.ExecuteScript(
+			`
+              import Test from 0x1
+
+              pub fun main(): [String] {
+                  log("test")
+                  return Test.values
+              }
+            `,
+			"",
+		),
+	)
+}
+
+func TestSessionLimits(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("code size", func(t *testing.T) {
+
+		t.Parallel()
+
+		session := NewSession(WithMaxCodeSize(100))
+
+		result := decodeResult(t, session.Parse(strings.Repeat(" ", 101)))
+		assert.Equal(t,
+			CodeTooLargeError{Size: 101, MaxSize: 100}.Error(),
+			result["error"].(map[string]interface{})["message"],
+		)
+	})
+
+	t.Run("computation", func(t *testing.T) {
+
+		t.Parallel()
+
+		session := NewSession(WithComputationLimit(100))
+
+		result := decodeResult(t, session.ExecuteScript(
+			`
+              pub fun main() {
+                  while true {}
+              }
+            `,
+			"",
+		))
+		message := result["error"].(map[string]interface{})["message"]
+		assert.Contains(t, message, runtime.ComputationLimitExceededError{Limit: 100}.Error())
+	})
+}
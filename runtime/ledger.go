@@ -0,0 +1,208 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// Ledger is a key-value store for the storage of accounts.
+// It provides the storage functions of the runtime interface,
+// so implementations of the runtime interface may delegate to it.
+//
+type Ledger interface {
+	// GetValue gets a value for the given key in the storage, owned by the given account.
+	GetValue(owner, key []byte) (value []byte, err error)
+	// SetValue sets a value for the given key in the storage, owned by the given account.
+	// Setting an empty value removes the key.
+	SetValue(owner, key, value []byte) (err error)
+	// ValueExists returns true if the given key exists in the storage, owned by the given account.
+	ValueExists(owner, key []byte) (exists bool, err error)
+	// GetStorageKeys returns the keys of all values in the storage, owned by the given account,
+	// which start with the given prefix, in lexicographic order.
+	GetStorageKeys(owner, prefix []byte) (keys [][]byte, err error)
+}
+
+// Snapshot identifies a state of a SnapshotLedger
+//
+type Snapshot uint64
+
+// ErrUnknownSnapshot is returned when restoring a snapshot which was released,
+// or which was invalidated by restoring an earlier snapshot
+//
+var ErrUnknownSnapshot = errors.New("unknown snapshot")
+
+// SnapshotLedger is a ledger which can be checkpointed and rolled back.
+//
+// Restoring a snapshot discards all changes made after the snapshot was taken,
+// and invalidates all snapshots taken after it.
+//
+type SnapshotLedger interface {
+	Ledger
+	// Snapshot returns a snapshot of the current state.
+	Snapshot() Snapshot
+	// Restore restores the state of the given snapshot.
+	// The snapshot stays valid, so it can be restored again.
+	Restore(snapshot Snapshot) error
+	// Release releases the given snapshot, i.e. it can not be restored anymore.
+	Release(snapshot Snapshot)
+}
+
+type ledgerKey struct {
+	owner common.Address
+	key   string
+}
+
+type ledgerJournalEntry struct {
+	key     ledgerKey
+	value   []byte
+	existed bool
+}
+
+// InMemoryLedger is a SnapshotLedger which stores all values in memory.
+//
+// Snapshots are cheap: Changes are recorded in a journal while snapshots exist,
+// and restoring a snapshot undoes the changes recorded after it
+//
+type InMemoryLedger struct {
+	values       map[ledgerKey][]byte
+	journal      []ledgerJournalEntry
+	snapshots    map[Snapshot]int
+	nextSnapshot Snapshot
+}
+
+var _ SnapshotLedger = &InMemoryLedger{}
+
+func NewInMemoryLedger() *InMemoryLedger {
+	return &InMemoryLedger{
+		values:    map[ledgerKey][]byte{},
+		snapshots: map[Snapshot]int{},
+	}
+}
+
+func newLedgerKey(owner, key []byte) ledgerKey {
+	return ledgerKey{
+		owner: common.BytesToAddress(owner),
+		key:   string(key),
+	}
+}
+
+func (l *InMemoryLedger) GetValue(owner, key []byte) (value []byte, err error) {
+	return l.values[newLedgerKey(owner, key)], nil
+}
+
+func (l *InMemoryLedger) SetValue(owner, key, value []byte) (err error) {
+	ledgerKey := newLedgerKey(owner, key)
+
+	if len(l.snapshots) > 0 {
+		previous, existed := l.values[ledgerKey]
+		l.journal = append(l.journal, ledgerJournalEntry{
+			key:     ledgerKey,
+			value:   previous,
+			existed: existed,
+		})
+	}
+
+	if len(value) == 0 {
+		delete(l.values, ledgerKey)
+	} else {
+		l.values[ledgerKey] = value
+	}
+
+	return nil
+}
+
+func (l *InMemoryLedger) ValueExists(owner, key []byte) (exists bool, err error) {
+	_, exists = l.values[newLedgerKey(owner, key)]
+	return exists, nil
+}
+
+func (l *InMemoryLedger) GetStorageKeys(owner, prefix []byte) (keys [][]byte, err error) {
+	address := common.BytesToAddress(owner)
+
+	var sortedKeys []string
+	for ledgerKey := range l.values { //nolint:maprangecheck
+		if ledgerKey.owner == address && strings.HasPrefix(ledgerKey.key, string(prefix)) {
+			sortedKeys = append(sortedKeys, ledgerKey.key)
+		}
+	}
+
+	sort.Strings(sortedKeys)
+
+	keys = make([][]byte, len(sortedKeys))
+	for i, key := range sortedKeys {
+		keys[i] = []byte(key)
+	}
+
+	return keys, nil
+}
+
+func (l *InMemoryLedger) Snapshot() Snapshot {
+	snapshot := l.nextSnapshot
+	l.nextSnapshot++
+
+	l.snapshots[snapshot] = len(l.journal)
+
+	return snapshot
+}
+
+func (l *InMemoryLedger) Restore(snapshot Snapshot) error {
+	journalLength, ok := l.snapshots[snapshot]
+	if !ok {
+		return ErrUnknownSnapshot
+	}
+
+	// Undo the changes recorded after the snapshot, in reverse order
+
+	for i := len(l.journal) - 1; i >= journalLength; i-- {
+		entry := l.journal[i]
+		if entry.existed {
+			l.values[entry.key] = entry.value
+		} else {
+			delete(l.values, entry.key)
+		}
+	}
+
+	l.journal = l.journal[:journalLength]
+
+	// Invalidate the snapshots taken after the restored snapshot.
+	// Snapshots of the same state stay valid
+
+	for other, otherJournalLength := range l.snapshots { //nolint:maprangecheck
+		if otherJournalLength > journalLength {
+			delete(l.snapshots, other)
+		}
+	}
+
+	return nil
+}
+
+func (l *InMemoryLedger) Release(snapshot Snapshot) {
+	delete(l.snapshots, snapshot)
+
+	// The journal is only needed while snapshots exist
+
+	if len(l.snapshots) == 0 {
+		l.journal = nil
+	}
+}
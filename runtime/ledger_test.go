@@ -0,0 +1,110 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryLedger(t *testing.T) {
+
+	t.Parallel()
+
+	owner := []byte{0x1}
+
+	t.Run("get, set, and remove", func(t *testing.T) {
+
+		t.Parallel()
+
+		ledger := NewInMemoryLedger()
+
+		require.NoError(t, ledger.SetValue(owner, []byte("b"), []byte{2}))
+		require.NoError(t, ledger.SetValue(owner, []byte("a"), []byte{1}))
+		require.NoError(t, ledger.SetValue([]byte{0x2}, []byte("a"), []byte{3}))
+
+		value, err := ledger.GetValue(owner, []byte("a"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte{1}, value)
+
+		keys, err := ledger.GetStorageKeys(owner, nil)
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte("a"), []byte("b")}, keys)
+
+		require.NoError(t, ledger.SetValue(owner, []byte("a"), nil))
+
+		exists, err := ledger.ValueExists(owner, []byte("a"))
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("snapshot and restore", func(t *testing.T) {
+
+		t.Parallel()
+
+		ledger := NewInMemoryLedger()
+
+		require.NoError(t, ledger.SetValue(owner, []byte("a"), []byte{1}))
+
+		first := ledger.Snapshot()
+
+		require.NoError(t, ledger.SetValue(owner, []byte("a"), []byte{2}))
+		require.NoError(t, ledger.SetValue(owner, []byte("b"), []byte{3}))
+
+		second := ledger.Snapshot()
+
+		require.NoError(t, ledger.SetValue(owner, []byte("a"), nil))
+
+		require.NoError(t, ledger.Restore(second))
+
+		value, err := ledger.GetValue(owner, []byte("a"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte{2}, value)
+
+		require.NoError(t, ledger.Restore(first))
+
+		value, err = ledger.GetValue(owner, []byte("a"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte{1}, value)
+
+		exists, err := ledger.ValueExists(owner, []byte("b"))
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		// Restoring the first snapshot invalidated the second
+
+		require.ErrorIs(t, ledger.Restore(second), ErrUnknownSnapshot)
+
+		// The first snapshot can be restored again
+
+		require.NoError(t, ledger.SetValue(owner, []byte("c"), []byte{4}))
+		require.NoError(t, ledger.Restore(first))
+
+		keys, err := ledger.GetStorageKeys(owner, nil)
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte("a")}, keys)
+
+		ledger.Release(first)
+
+		require.ErrorIs(t, ledger.Restore(first), ErrUnknownSnapshot)
+		assert.Empty(t, ledger.journal)
+	})
+}
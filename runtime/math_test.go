@@ -0,0 +1,227 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestRuntimeMath(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := NewInterpreterRuntime()
+
+	executeScript := func(code string) (cadence.Value, error) {
+		return runtime.ExecuteScript(
+			Script{
+				Source: []byte(code),
+			},
+			Context{
+				Interface: &testRuntimeInterface{},
+				Location:  utils.TestLocation,
+			},
+		)
+	}
+
+	t.Run("sqrt", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, err := executeScript(`
+          pub fun main(): [AnyStruct] {
+              return [
+                  Math.sqrt(2.0),
+                  Math.sqrt(6.25 as Fix64)
+              ]
+          }
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			cadence.NewArray([]cadence.Value{
+				cadence.UFix64(141421356),
+				cadence.Fix64(250000000),
+			}),
+			result,
+		)
+	})
+
+	t.Run("sqrt of negative number", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := executeScript(`
+          pub fun main(): Fix64 {
+              return Math.sqrt(-1.0)
+          }
+        `)
+		require.Error(t, err)
+		require.ErrorAs(t, err, &stdlib.MathError{})
+	})
+
+	t.Run("sqrt of integer", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := executeScript(`
+          pub fun main(): Int {
+              return Math.sqrt(4)
+          }
+        `)
+		require.Error(t, err)
+
+		var checkerErr *sema.CheckerError
+		require.ErrorAs(t, err, &checkerErr)
+
+		errs := checkerErr.Errors
+		require.Len(t, errs, 1)
+		require.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("ln and exp", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, err := executeScript(`
+          pub fun main(): [AnyStruct] {
+              return [
+                  Math.ln(0.5),
+                  Math.exp(1.0),
+                  Math.exp(-1.0)
+              ]
+          }
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			cadence.NewArray([]cadence.Value{
+				cadence.Fix64(-69314718),
+				cadence.UFix64(271828183),
+				cadence.UFix64(36787944),
+			}),
+			result,
+		)
+	})
+
+	t.Run("pow", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, err := executeScript(`
+          pub fun main(): [AnyStruct] {
+              return [
+                  Math.pow(2.0, 10.0),
+                  Math.pow(2.0, 0.5),
+                  Math.pow(-2.0, 3.0),
+                  Math.pow(4.0, -0.5)
+              ]
+          }
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			cadence.NewArray([]cadence.Value{
+				cadence.UFix64(102400000000),
+				cadence.UFix64(141421356),
+				cadence.Fix64(-800000000),
+				cadence.UFix64(50000000),
+			}),
+			result,
+		)
+	})
+
+	t.Run("pow overflow", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := executeScript(`
+          pub fun main(): UFix64 {
+              return Math.pow(10.0, 12.0)
+          }
+        `)
+		require.Error(t, err)
+		require.ErrorAs(t, err, &interpreter.OverflowError{})
+	})
+
+	t.Run("powInteger", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, err := executeScript(`
+          pub fun main(): [AnyStruct] {
+              return [
+                  Math.powInteger(2, 100),
+                  Math.powInteger(3 as UInt8, 5),
+                  Math.powInteger(-2 as Int8, 7),
+                  Math.powInteger(2 as Word8, 9),
+                  Math.powInteger(7 as UInt64, 0)
+              ]
+          }
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			cadence.NewArray([]cadence.Value{
+				cadence.NewIntFromBig(new(big.Int).Lsh(big.NewInt(1), 100)),
+				cadence.NewUInt8(243),
+				cadence.NewInt8(-128),
+				cadence.NewWord8(0),
+				cadence.NewUInt64(1),
+			}),
+			result,
+		)
+	})
+
+	t.Run("powInteger overflow", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := executeScript(`
+          pub fun main(): UInt8 {
+              return Math.powInteger(2 as UInt8, 8)
+          }
+        `)
+		require.Error(t, err)
+		require.ErrorAs(t, err, &interpreter.OverflowError{})
+	})
+
+	t.Run("powInteger negative exponent", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := executeScript(`
+          pub fun main(): Int {
+              return Math.powInteger(2, -1)
+          }
+        `)
+		require.Error(t, err)
+		require.ErrorAs(t, err, &stdlib.MathError{})
+	})
+}
@@ -0,0 +1,332 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package optimizer
+
+import (
+	"math/big"
+
+	"github.com/onflow/cadence/fixedpoint"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// fold folds the constant subexpressions of the given expression, bottom-up,
+// and returns the folded expression.
+//
+// Constant expressions are evaluated using the values of the interpreter,
+// so the result is the same as when the expression is interpreted.
+// Expressions which fail, e.g. due to an overflow or a division by zero,
+// are not folded, so they still fail when interpreted.
+//
+func (o *optimizer) fold(expression ast.Expression, scope *scope) ast.Expression {
+
+	if functionExpression, ok := expression.(*ast.FunctionExpression); ok {
+		o.optimizeFunction(
+			functionExpression.ParameterList,
+			o.elaboration.FunctionExpressionFunctionType[functionExpression],
+			functionExpression.FunctionBlock,
+		)
+		return expression
+	}
+
+	o.rewriteSubexpressions(expression, scope, o.fold)
+
+	var value interpreter.Value
+	var valueType sema.Type
+
+	switch expression := expression.(type) {
+	case *ast.BinaryExpression:
+		value, valueType = o.foldBinaryExpression(expression)
+
+	case *ast.UnaryExpression:
+		value, valueType = o.foldUnaryExpression(expression)
+	}
+
+	if value == nil {
+		return expression
+	}
+
+	return o.literal(value, valueType, expression)
+}
+
+// constant returns the value and type of the given expression,
+// if it is a literal of a supported type.
+//
+func (o *optimizer) constant(expression ast.Expression) (interpreter.Value, sema.Type) {
+	switch expression := expression.(type) {
+	case *ast.BoolExpression:
+		return interpreter.BoolValue(expression.Value), sema.BoolType
+
+	case *ast.IntegerExpression:
+		integerType := o.elaboration.IntegerExpressionType[expression]
+		if !isFoldableIntegerType(integerType) {
+			return nil, nil
+		}
+		return interpreter.NewIntValue(expression.Value, integerType), integerType
+
+	case *ast.FixedPointExpression:
+		fixedPointType := o.elaboration.FixedPointExpression[expression]
+
+		value := fixedpoint.ConvertToFixedPointBigInt(
+			expression.Negative,
+			expression.UnsignedInteger,
+			expression.Fractional,
+			expression.Scale,
+			sema.Fix64Scale,
+		)
+
+		switch fixedPointType {
+		case sema.Fix64Type:
+			return interpreter.Fix64Value(value.Int64()), fixedPointType
+		case sema.UFix64Type:
+			return interpreter.UFix64Value(value.Uint64()), fixedPointType
+		}
+	}
+
+	return nil, nil
+}
+
+func isFoldableIntegerType(ty sema.Type) bool {
+	switch ty {
+	case nil, sema.IntegerType, sema.SignedIntegerType:
+		return false
+	}
+
+	return sema.IsSubType(ty, sema.IntegerType)
+}
+
+func (o *optimizer) foldBinaryExpression(
+	expression *ast.BinaryExpression,
+) (
+	result interpreter.Value,
+	resultType sema.Type,
+) {
+	left, leftType := o.constant(expression.Left)
+	if left == nil {
+		return nil, nil
+	}
+
+	right, rightType := o.constant(expression.Right)
+	if right == nil || leftType != rightType {
+		return nil, nil
+	}
+
+	// Operations which fail are not folded
+
+	defer func() {
+		if recover() != nil {
+			result = nil
+			resultType = nil
+		}
+	}()
+
+	switch left := left.(type) {
+	case interpreter.BoolValue:
+		right := right.(interpreter.BoolValue)
+
+		switch expression.Operation {
+		case ast.OperationOr:
+			return left || right, sema.BoolType
+		case ast.OperationAnd:
+			return left && right, sema.BoolType
+		case ast.OperationEqual:
+			return interpreter.BoolValue(left == right), sema.BoolType
+		case ast.OperationNotEqual:
+			return interpreter.BoolValue(left != right), sema.BoolType
+		}
+
+	case interpreter.NumberValue:
+		right := right.(interpreter.NumberValue)
+
+		switch expression.Operation {
+		case ast.OperationPlus:
+			return left.Plus(right), leftType
+		case ast.OperationMinus:
+			return left.Minus(right), leftType
+		case ast.OperationMul:
+			return left.Mul(right), leftType
+		case ast.OperationDiv:
+			return left.Div(right), leftType
+		case ast.OperationMod:
+			return left.Mod(right), leftType
+		case ast.OperationLess:
+			return left.Less(right), sema.BoolType
+		case ast.OperationLessEqual:
+			return left.LessEqual(right), sema.BoolType
+		case ast.OperationGreater:
+			return left.Greater(right), sema.BoolType
+		case ast.OperationGreaterEqual:
+			return left.GreaterEqual(right), sema.BoolType
+		case ast.OperationEqual:
+			return interpreter.BoolValue(left.Equal(right, nil, false)), sema.BoolType
+		case ast.OperationNotEqual:
+			return interpreter.BoolValue(!left.Equal(right, nil, false)), sema.BoolType
+		}
+
+		leftInteger, ok := left.(interpreter.IntegerValue)
+		if !ok {
+			return nil, nil
+		}
+		rightInteger := right.(interpreter.IntegerValue)
+
+		switch expression.Operation {
+		case ast.OperationBitwiseOr:
+			return leftInteger.BitwiseOr(rightInteger), leftType
+		case ast.OperationBitwiseXor:
+			return leftInteger.BitwiseXor(rightInteger), leftType
+		case ast.OperationBitwiseAnd:
+			return leftInteger.BitwiseAnd(rightInteger), leftType
+		case ast.OperationBitwiseLeftShift:
+			return leftInteger.BitwiseLeftShift(rightInteger), leftType
+		case ast.OperationBitwiseRightShift:
+			return leftInteger.BitwiseRightShift(rightInteger), leftType
+		}
+	}
+
+	return nil, nil
+}
+
+func (o *optimizer) foldUnaryExpression(
+	expression *ast.UnaryExpression,
+) (
+	result interpreter.Value,
+	resultType sema.Type,
+) {
+	value, valueType := o.constant(expression.Expression)
+	if value == nil {
+		return nil, nil
+	}
+
+	// Operations which fail are not folded
+
+	defer func() {
+		if recover() != nil {
+			result = nil
+			resultType = nil
+		}
+	}()
+
+	switch expression.Operation {
+	case ast.OperationNegate:
+		if value, ok := value.(interpreter.BoolValue); ok {
+			return !value, sema.BoolType
+		}
+
+	case ast.OperationMinus:
+		if value, ok := value.(interpreter.NumberValue); ok {
+			return value.Negate(), valueType
+		}
+	}
+
+	return nil, nil
+}
+
+// literal returns the literal expression for the given value,
+// which replaces the given expression.
+//
+func (o *optimizer) literal(value interpreter.Value, valueType sema.Type, expression ast.Expression) ast.Expression {
+
+	literalRange := ast.Range{
+		StartPos: expression.StartPosition(),
+		EndPos:   expression.EndPosition(),
+	}
+
+	switch value := value.(type) {
+	case interpreter.BoolValue:
+		return &ast.BoolExpression{
+			Value: bool(value),
+			Range: literalRange,
+		}
+
+	case interpreter.Fix64Value:
+		return o.fixedPointLiteral(big.NewInt(int64(value)), valueType, literalRange)
+
+	case interpreter.UFix64Value:
+		return o.fixedPointLiteral(new(big.Int).SetUint64(uint64(value)), valueType, literalRange)
+
+	case interpreter.IntegerValue:
+		integerExpression := &ast.IntegerExpression{
+			Value: integerValueToBigInt(value),
+			Base:  10,
+			Range: literalRange,
+		}
+		o.elaboration.IntegerExpressionType[integerExpression] = valueType
+		return integerExpression
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
+func (o *optimizer) fixedPointLiteral(value *big.Int, valueType sema.Type, literalRange ast.Range) ast.Expression {
+	negative := value.Sign() < 0
+
+	unsignedInteger, fractional := new(big.Int).QuoRem(
+		new(big.Int).Abs(value),
+		sema.Fix64FactorBig,
+		new(big.Int),
+	)
+
+	fixedPointExpression := &ast.FixedPointExpression{
+		Negative:        negative,
+		UnsignedInteger: unsignedInteger,
+		Fractional:      fractional,
+		Scale:           sema.Fix64Scale,
+		Range:           literalRange,
+	}
+	o.elaboration.FixedPointExpression[fixedPointExpression] = valueType
+	return fixedPointExpression
+}
+
+func integerValueToBigInt(value interpreter.IntegerValue) *big.Int {
+	switch value := value.(type) {
+	case interpreter.BigNumberValue:
+		return value.ToBigInt()
+
+	case interpreter.Int8Value:
+		return big.NewInt(int64(value))
+	case interpreter.Int16Value:
+		return big.NewInt(int64(value))
+	case interpreter.Int32Value:
+		return big.NewInt(int64(value))
+	case interpreter.Int64Value:
+		return big.NewInt(int64(value))
+
+	case interpreter.UInt8Value:
+		return big.NewInt(int64(value))
+	case interpreter.UInt16Value:
+		return big.NewInt(int64(value))
+	case interpreter.UInt32Value:
+		return big.NewInt(int64(value))
+	case interpreter.UInt64Value:
+		return new(big.Int).SetUint64(uint64(value))
+
+	case interpreter.Word8Value:
+		return big.NewInt(int64(value))
+	case interpreter.Word16Value:
+		return big.NewInt(int64(value))
+	case interpreter.Word32Value:
+		return big.NewInt(int64(value))
+	case interpreter.Word64Value:
+		return new(big.Int).SetUint64(uint64(value))
+	}
+
+	panic(errors.NewUnreachableError())
+}
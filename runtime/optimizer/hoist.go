@@ -0,0 +1,302 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package optimizer
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// hoistStatements hoists the invariant computations of the loops in the given statements
+// and returns the resulting statements.
+//
+// The invariant computations of a loop are declared as constants before the loop,
+// and replaced in the loop by references to the constants.
+//
+// Only computations which always succeed and have no side effects are hoisted,
+// so the program behaves the same, even if the loop is never executed.
+//
+func (o *optimizer) hoistStatements(statements []ast.Statement, scope *scope, loopDepth int) []ast.Statement {
+
+	// The resulting statements are only allocated if any computations are hoisted
+
+	var result []ast.Statement
+
+	for i, statement := range statements {
+
+		switch statement.(type) {
+		case *ast.WhileStatement, *ast.ForStatement:
+			declarations := o.hoistLoop(statement, scope, loopDepth+1)
+			if len(declarations) > 0 {
+				if result == nil {
+					result = make([]ast.Statement, 0, len(statements)+len(declarations))
+					result = append(result, statements[:i]...)
+				}
+
+				for _, declaration := range declarations {
+					result = append(result, declaration)
+					scope = o.declareVariable(declaration, scope, loopDepth)
+				}
+			}
+		}
+
+		o.hoistNestedStatements(statement, scope, loopDepth)
+
+		if result != nil {
+			result = append(result, statement)
+		}
+
+		if declaration, ok := statement.(*ast.VariableDeclaration); ok {
+			scope = o.declareVariable(declaration, scope, loopDepth)
+		}
+	}
+
+	if result == nil {
+		return statements
+	}
+
+	return result
+}
+
+// hoistNestedStatements hoists the invariant computations of the loops
+// in the blocks of the given statement.
+//
+func (o *optimizer) hoistNestedStatements(statement ast.Statement, scope *scope, loopDepth int) {
+	switch statement := statement.(type) {
+	case *ast.IfStatement:
+		thenScope := scope
+		if declaration, ok := statement.Test.(*ast.VariableDeclaration); ok {
+			thenScope = o.declareVariable(declaration, scope, loopDepth)
+		}
+
+		statement.Then.Statements = o.hoistStatements(statement.Then.Statements, thenScope, loopDepth)
+
+		if statement.Else != nil {
+			statement.Else.Statements = o.hoistStatements(statement.Else.Statements, scope, loopDepth)
+		}
+
+	case *ast.WhileStatement:
+		block := statement.Block
+		block.Statements = o.hoistStatements(block.Statements, scope, loopDepth+1)
+
+	case *ast.ForStatement:
+		blockScope := scope.declare(statement.Identifier.Identifier, nil, loopDepth+1)
+
+		block := statement.Block
+		block.Statements = o.hoistStatements(block.Statements, blockScope, loopDepth+1)
+
+	case *ast.SwitchStatement:
+		for _, switchCase := range statement.Cases {
+			switchCase.Statements = o.hoistStatements(switchCase.Statements, scope, loopDepth)
+		}
+	}
+}
+
+// hoistLoop replaces the invariant computations in the given loop
+// and returns the declarations of the hoisted computations.
+//
+func (o *optimizer) hoistLoop(loop ast.Statement, loopScope *scope, loopDepth int) []*ast.VariableDeclaration {
+
+	var declarations []*ast.VariableDeclaration
+
+	var hoist rewriteFunc
+	hoist = func(expression ast.Expression, scope *scope) ast.Expression {
+
+		switch expression.(type) {
+		case *ast.BinaryExpression, *ast.UnaryExpression:
+			invariantType := o.invariantType(expression, scope, loopDepth)
+			if invariantType != nil {
+				declaration := o.newHoistedDeclaration(expression, invariantType)
+				declarations = append(declarations, declaration)
+
+				return &ast.IdentifierExpression{
+					Identifier: declaration.Identifier,
+				}
+			}
+		}
+
+		o.rewriteSubexpressions(expression, scope, hoist)
+
+		return expression
+	}
+
+	switch loop := loop.(type) {
+	case *ast.WhileStatement:
+		loop.Test = hoist(loop.Test, loopScope)
+
+		o.rewriteStatements(loop.Block.Statements, loopScope, loopDepth, hoist)
+
+	case *ast.ForStatement:
+		// NOTE: the value is only evaluated once
+		blockScope := loopScope.declare(loop.Identifier.Identifier, nil, loopDepth)
+
+		o.rewriteStatements(loop.Block.Statements, blockScope, loopDepth, hoist)
+	}
+
+	return declarations
+}
+
+func (o *optimizer) newHoistedDeclaration(expression ast.Expression, ty sema.Type) *ast.VariableDeclaration {
+
+	// NOTE: the name is not a valid identifier, so it cannot clash with declarations of the program
+
+	name := fmt.Sprintf("$hoisted%d", o.hoistedCount)
+	o.hoistedCount++
+
+	startPos := expression.StartPosition()
+
+	declaration := &ast.VariableDeclaration{
+		IsConstant: true,
+		Identifier: ast.Identifier{
+			Identifier: name,
+			Pos:        startPos,
+		},
+		Value: expression,
+		Transfer: &ast.Transfer{
+			Operation: ast.TransferOperationCopy,
+			Pos:       startPos,
+		},
+		StartPos: startPos,
+	}
+
+	o.elaboration.VariableDeclarationTargetTypes[declaration] = ty
+	o.elaboration.VariableDeclarationValueTypes[declaration] = ty
+
+	return declaration
+}
+
+// invariantType returns the type of the given expression, if it is invariant in a loop
+// at the given loop depth, and evaluating it always succeeds.
+// Otherwise, it returns nil.
+//
+// Invariant expressions are literals, constants declared outside of the loop,
+// and total operations on invariant expressions.
+//
+func (o *optimizer) invariantType(expression ast.Expression, scope *scope, loopDepth int) sema.Type {
+	switch expression := expression.(type) {
+	case *ast.BoolExpression:
+		return sema.BoolType
+
+	case *ast.IntegerExpression:
+		return o.elaboration.IntegerExpressionType[expression]
+
+	case *ast.FixedPointExpression:
+		return o.elaboration.FixedPointExpression[expression]
+
+	case *ast.IdentifierExpression:
+		declaration := scope.find(expression.Identifier.Identifier)
+		if declaration == nil || declaration.loopDepth >= loopDepth {
+			return nil
+		}
+		return declaration.ty
+
+	case *ast.UnaryExpression:
+		operandType := o.invariantType(expression.Expression, scope, loopDepth)
+		if operandType == nil {
+			return nil
+		}
+
+		switch expression.Operation {
+		case ast.OperationNegate:
+			if operandType == sema.BoolType {
+				return sema.BoolType
+			}
+
+		case ast.OperationMinus:
+			// Only the negation of arbitrary precision integers cannot overflow
+			if operandType == sema.IntType {
+				return sema.IntType
+			}
+		}
+
+	case *ast.BinaryExpression:
+		leftType := o.invariantType(expression.Left, scope, loopDepth)
+		if leftType == nil {
+			return nil
+		}
+
+		rightType := o.invariantType(expression.Right, scope, loopDepth)
+		if rightType != leftType {
+			return nil
+		}
+
+		switch expression.Operation {
+		case ast.OperationPlus, ast.OperationMinus, ast.OperationMul:
+			if isTotalArithmeticType(leftType) {
+				return leftType
+			}
+
+		case ast.OperationLess,
+			ast.OperationLessEqual,
+			ast.OperationGreater,
+			ast.OperationGreaterEqual:
+
+			if isNumberType(leftType) {
+				return sema.BoolType
+			}
+
+		case ast.OperationEqual, ast.OperationNotEqual:
+			if leftType == sema.BoolType || isNumberType(leftType) {
+				return sema.BoolType
+			}
+
+		case ast.OperationOr, ast.OperationAnd:
+			if leftType == sema.BoolType {
+				return sema.BoolType
+			}
+		}
+	}
+
+	return nil
+}
+
+// isTotalArithmeticType returns true if the addition, subtraction, and multiplication
+// of values of the given type cannot fail: Arbitrary precision integers cannot overflow,
+// and word types wrap around.
+//
+func isTotalArithmeticType(ty sema.Type) bool {
+	switch ty {
+	case sema.IntType,
+		sema.Word8Type,
+		sema.Word16Type,
+		sema.Word32Type,
+		sema.Word64Type:
+
+		return true
+	}
+
+	return false
+}
+
+func isNumberType(ty sema.Type) bool {
+	switch ty {
+	case sema.NumberType,
+		sema.SignedNumberType,
+		sema.IntegerType,
+		sema.SignedIntegerType,
+		sema.FixedPointType,
+		sema.SignedFixedPointType:
+
+		return false
+	}
+
+	return sema.IsSubType(ty, sema.NumberType)
+}
@@ -0,0 +1,366 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package optimizer provides an optimization pass over checked programs,
+// which is applied before the programs are interpreted.
+//
+// The pass folds constant expressions, pre-resolves the member indices of declarations,
+// and hoists invariant computations out of loops.
+//
+package optimizer
+
+import (
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+type optimizer struct {
+	elaboration  *sema.Elaboration
+	hoistedCount int
+}
+
+// Optimize optimizes the given checked program in place.
+//
+// The elaboration of the program is updated for the rewritten expressions and statements,
+// so the program can be interpreted as usual afterwards.
+// Pre-conditions and post-conditions are not optimized.
+//
+func Optimize(program *ast.Program, elaboration *sema.Elaboration) {
+	o := &optimizer{
+		elaboration: elaboration,
+	}
+
+	// Pre-resolve the indices of the program
+
+	_ = program.ImportDeclarations()
+
+	for _, declaration := range program.Declarations() {
+		o.optimizeDeclaration(declaration)
+	}
+}
+
+func (o *optimizer) optimizeDeclaration(declaration ast.Declaration) {
+	switch declaration := declaration.(type) {
+	case *ast.VariableDeclaration:
+		o.rewriteStatement(declaration, nil, 0, o.fold)
+
+	case *ast.FunctionDeclaration:
+		o.optimizeFunctionDeclaration(
+			declaration,
+			o.elaboration.FunctionDeclarationFunctionTypes[declaration],
+		)
+
+	case *ast.CompositeDeclaration:
+		o.optimizeMembers(declaration.Members)
+
+		for _, specialFunction := range declaration.Members.SpecialFunctions() {
+			var functionType *sema.FunctionType
+			if constructorType, ok := o.elaboration.ConstructorFunctionTypes[specialFunction]; ok {
+				functionType = constructorType.FunctionType
+			}
+			o.optimizeFunctionDeclaration(specialFunction.FunctionDeclaration, functionType)
+		}
+
+	case *ast.InterfaceDeclaration:
+		o.optimizeMembers(declaration.Members)
+
+	case *ast.TransactionDeclaration:
+		if declaration.Prepare != nil {
+			o.optimizeFunctionDeclaration(declaration.Prepare.FunctionDeclaration, nil)
+		}
+		if declaration.Execute != nil {
+			o.optimizeFunctionDeclaration(declaration.Execute.FunctionDeclaration, nil)
+		}
+	}
+}
+
+// optimizeMembers pre-resolves the indices of the given members,
+// and optimizes the functions and nested declarations.
+//
+func (o *optimizer) optimizeMembers(members *ast.Members) {
+
+	// Accessing any index resolves all indices
+
+	_ = members.FieldsByIdentifier()
+
+	for _, function := range members.Functions() {
+		o.optimizeDeclaration(function)
+	}
+
+	for _, composite := range members.Composites() {
+		o.optimizeDeclaration(composite)
+	}
+
+	for _, interfaceDeclaration := range members.Interfaces() {
+		o.optimizeDeclaration(interfaceDeclaration)
+	}
+}
+
+func (o *optimizer) optimizeFunctionDeclaration(
+	declaration *ast.FunctionDeclaration,
+	functionType *sema.FunctionType,
+) {
+	o.optimizeFunction(declaration.ParameterList, functionType, declaration.FunctionBlock)
+}
+
+// optimizeFunction optimizes the body of a function.
+// The function type may be nil, in which case the types of the parameters are unknown.
+//
+func (o *optimizer) optimizeFunction(
+	parameterList *ast.ParameterList,
+	functionType *sema.FunctionType,
+	functionBlock *ast.FunctionBlock,
+) {
+	if functionBlock == nil || functionBlock.Block == nil {
+		return
+	}
+
+	var parameterScope *scope
+	if parameterList != nil {
+		for i, parameter := range parameterList.Parameters {
+			var parameterType sema.Type
+			if functionType != nil && i < len(functionType.Parameters) {
+				parameterType = functionType.Parameters[i].TypeAnnotation.Type
+			}
+
+			// Parameters are constants
+
+			parameterScope = parameterScope.declare(parameter.Identifier.Identifier, parameterType, 0)
+		}
+	}
+
+	block := functionBlock.Block
+
+	o.rewriteStatements(block.Statements, nil, 0, o.fold)
+
+	block.Statements = o.hoistStatements(block.Statements, parameterScope, 0)
+}
+
+// scope is a linked list of the variables declared in a function.
+//
+// The type is nil for variables, i.e. non-constant declarations,
+// and for constants of unknown type.
+//
+// The loop depth is the number of loops the declaration is nested in.
+//
+type scope struct {
+	parent    *scope
+	name      string
+	ty        sema.Type
+	loopDepth int
+}
+
+func (s *scope) declare(name string, ty sema.Type, loopDepth int) *scope {
+	return &scope{
+		parent:    s,
+		name:      name,
+		ty:        ty,
+		loopDepth: loopDepth,
+	}
+}
+
+// find returns the innermost declaration with the given name, if any.
+//
+func (s *scope) find(name string) *scope {
+	for current := s; current != nil; current = current.parent {
+		if current.name == name {
+			return current
+		}
+	}
+	return nil
+}
+
+// rewriteFunc returns the rewritten form of the given expression
+//
+type rewriteFunc func(expression ast.Expression, scope *scope) ast.Expression
+
+// rewriteStatements rewrites the expressions of the given statements using the given function.
+// Declarations are declared in a new scope, at the given loop depth.
+//
+func (o *optimizer) rewriteStatements(
+	statements []ast.Statement,
+	scope *scope,
+	loopDepth int,
+	rewrite rewriteFunc,
+) {
+	for _, statement := range statements {
+		scope = o.rewriteStatement(statement, scope, loopDepth, rewrite)
+	}
+}
+
+// rewriteStatement rewrites the expressions of the given statement using the given function,
+// and returns the scope after the statement.
+//
+func (o *optimizer) rewriteStatement(
+	statement ast.Statement,
+	scope *scope,
+	loopDepth int,
+	rewrite rewriteFunc,
+) *scope {
+
+	switch statement := statement.(type) {
+	case *ast.ReturnStatement:
+		if statement.Expression != nil {
+			statement.Expression = rewrite(statement.Expression, scope)
+		}
+
+	case *ast.IfStatement:
+		thenScope := scope
+
+		switch test := statement.Test.(type) {
+		case ast.Expression:
+			statement.Test = rewrite(test, scope)
+
+		case *ast.VariableDeclaration:
+			thenScope = o.rewriteStatement(test, scope, loopDepth, rewrite)
+		}
+
+		o.rewriteStatements(statement.Then.Statements, thenScope, loopDepth, rewrite)
+
+		if statement.Else != nil {
+			o.rewriteStatements(statement.Else.Statements, scope, loopDepth, rewrite)
+		}
+
+	case *ast.WhileStatement:
+		// The test is evaluated in each iteration
+		statement.Test = rewrite(statement.Test, scope)
+
+		o.rewriteStatements(statement.Block.Statements, scope, loopDepth+1, rewrite)
+
+	case *ast.ForStatement:
+		statement.Value = rewrite(statement.Value, scope)
+
+		blockScope := scope.declare(statement.Identifier.Identifier, nil, loopDepth+1)
+		o.rewriteStatements(statement.Block.Statements, blockScope, loopDepth+1, rewrite)
+
+	case *ast.EmitStatement:
+		o.rewriteSubexpressions(statement.InvocationExpression, scope, rewrite)
+
+	case *ast.AssignmentStatement:
+		// NOTE: the target itself must be kept
+		o.rewriteSubexpressions(statement.Target, scope, rewrite)
+		statement.Value = rewrite(statement.Value, scope)
+
+	case *ast.SwapStatement:
+		// NOTE: the targets themselves must be kept
+		o.rewriteSubexpressions(statement.Left, scope, rewrite)
+		o.rewriteSubexpressions(statement.Right, scope, rewrite)
+
+	case *ast.ExpressionStatement:
+		statement.Expression = rewrite(statement.Expression, scope)
+
+	case *ast.SwitchStatement:
+		statement.Expression = rewrite(statement.Expression, scope)
+
+		for _, switchCase := range statement.Cases {
+			if switchCase.Expression != nil {
+				switchCase.Expression = rewrite(switchCase.Expression, scope)
+			}
+			o.rewriteStatements(switchCase.Statements, scope, loopDepth, rewrite)
+		}
+
+	case *ast.VariableDeclaration:
+		statement.Value = rewrite(statement.Value, scope)
+		if statement.SecondValue != nil {
+			statement.SecondValue = rewrite(statement.SecondValue, scope)
+		}
+
+		return o.declareVariable(statement, scope, loopDepth)
+	}
+
+	return scope
+}
+
+// declareVariable declares the variable of the given declaration
+// and returns the new scope.
+//
+func (o *optimizer) declareVariable(
+	declaration *ast.VariableDeclaration,
+	scope *scope,
+	loopDepth int,
+) *scope {
+	var ty sema.Type
+	if declaration.IsConstant {
+		ty = o.elaboration.VariableDeclarationTargetTypes[declaration]
+	}
+	return scope.declare(declaration.Identifier.Identifier, ty, loopDepth)
+}
+
+// rewriteSubexpressions rewrites the direct subexpressions of the given expression
+// using the given function.
+//
+// The bodies of function expressions are not rewritten,
+// as they are optimized separately.
+//
+func (o *optimizer) rewriteSubexpressions(expression ast.Expression, scope *scope, rewrite rewriteFunc) {
+	switch expression := expression.(type) {
+	case *ast.ArrayExpression:
+		for i, value := range expression.Values {
+			expression.Values[i] = rewrite(value, scope)
+		}
+
+	case *ast.DictionaryExpression:
+		for i, entry := range expression.Entries {
+			expression.Entries[i].Key = rewrite(entry.Key, scope)
+			expression.Entries[i].Value = rewrite(entry.Value, scope)
+		}
+
+	case *ast.InvocationExpression:
+		expression.InvokedExpression = rewrite(expression.InvokedExpression, scope)
+		for _, argument := range expression.Arguments {
+			argument.Expression = rewrite(argument.Expression, scope)
+		}
+
+	case *ast.MemberExpression:
+		expression.Expression = rewrite(expression.Expression, scope)
+
+	case *ast.IndexExpression:
+		expression.TargetExpression = rewrite(expression.TargetExpression, scope)
+		expression.IndexingExpression = rewrite(expression.IndexingExpression, scope)
+
+	case *ast.ConditionalExpression:
+		expression.Test = rewrite(expression.Test, scope)
+		expression.Then = rewrite(expression.Then, scope)
+		expression.Else = rewrite(expression.Else, scope)
+
+	case *ast.UnaryExpression:
+		expression.Expression = rewrite(expression.Expression, scope)
+
+	case *ast.BinaryExpression:
+		expression.Left = rewrite(expression.Left, scope)
+		expression.Right = rewrite(expression.Right, scope)
+
+	case *ast.CastingExpression:
+		expression.Expression = rewrite(expression.Expression, scope)
+
+	case *ast.CreateExpression:
+		o.rewriteSubexpressions(expression.InvocationExpression, scope, rewrite)
+
+	case *ast.DestroyExpression:
+		expression.Expression = rewrite(expression.Expression, scope)
+
+	case *ast.ReferenceExpression:
+		expression.Expression = rewrite(expression.Expression, scope)
+
+	case *ast.ForceExpression:
+		expression.Expression = rewrite(expression.Expression, scope)
+
+	case *ast.TryExpression:
+		o.rewriteSubexpressions(expression.InvocationExpression, scope, rewrite)
+	}
+}
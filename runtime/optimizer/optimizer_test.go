@@ -0,0 +1,440 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package optimizer_test
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/optimizer"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/tests/checker"
+)
+
+func parseCheckAndOptimize(t testing.TB, code string) *sema.Checker {
+	checker, err := checker.ParseAndCheckWithOptions(t, code, checker.ParseAndCheckOptions{})
+	require.NoError(t, err)
+
+	optimizer.Optimize(checker.Program, checker.Elaboration)
+
+	return checker
+}
+
+func parseCheckOptimizeAndInterpret(t testing.TB, code string) (*sema.Checker, *interpreter.Interpreter) {
+	checker := parseCheckAndOptimize(t, code)
+
+	inter, err := interpreter.NewInterpreter(
+		interpreter.ProgramFromChecker(checker),
+		checker.Location,
+	)
+	require.NoError(t, err)
+
+	err = inter.Interpret()
+	require.NoError(t, err)
+
+	return checker, inter
+}
+
+func functionStatements(t *testing.T, checker *sema.Checker, name string) []ast.Statement {
+	for _, declaration := range checker.Program.FunctionDeclarations() {
+		if declaration.Identifier.Identifier == name {
+			return declaration.FunctionBlock.Block.Statements
+		}
+	}
+
+	require.FailNow(t, "missing function", name)
+	return nil
+}
+
+func returnedExpression(t *testing.T, checker *sema.Checker, name string) ast.Expression {
+	statements := functionStatements(t, checker, name)
+	returnStatement, ok := statements[len(statements)-1].(*ast.ReturnStatement)
+	require.True(t, ok)
+
+	return returnStatement.Expression
+}
+
+func TestOptimizeConstantFolding(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("integers", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, inter := parseCheckOptimizeAndInterpret(t, `
+          fun test(): UInt8 {
+              return ((1 + 2) * 3) << 1
+          }
+        `)
+
+		expression := returnedExpression(t, checker, "test")
+		require.IsType(t, &ast.IntegerExpression{}, expression)
+		integerExpression := expression.(*ast.IntegerExpression)
+
+		assert.Equal(t, big.NewInt(18), integerExpression.Value)
+		assert.Equal(t, sema.UInt8Type, checker.Elaboration.IntegerExpressionType[integerExpression])
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+		assert.Equal(t, interpreter.UInt8Value(18), result)
+	})
+
+	t.Run("negation", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, inter := parseCheckOptimizeAndInterpret(t, `
+          fun test(): Int {
+              return -(2 - 5)
+          }
+        `)
+
+		require.IsType(t, &ast.IntegerExpression{}, returnedExpression(t, checker, "test"))
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+		assert.Equal(t, interpreter.NewIntValueFromInt64(3), result)
+	})
+
+	t.Run("fixed-point", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, inter := parseCheckOptimizeAndInterpret(t, `
+          fun test(): Fix64 {
+              return 1.5 * -2.25
+          }
+        `)
+
+		require.IsType(t, &ast.FixedPointExpression{}, returnedExpression(t, checker, "test"))
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+		assert.Equal(t, interpreter.Fix64Value(-337_500_000), result)
+	})
+
+	t.Run("comparisons and booleans", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, inter := parseCheckOptimizeAndInterpret(t, `
+          fun test(): Bool {
+              return !(1 < 2 && 3 == 4) || false
+          }
+        `)
+
+		expression := returnedExpression(t, checker, "test")
+		require.IsType(t, &ast.BoolExpression{}, expression)
+		assert.True(t, expression.(*ast.BoolExpression).Value)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+		assert.Equal(t, interpreter.BoolValue(true), result)
+	})
+
+	t.Run("nested functions", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, inter := parseCheckOptimizeAndInterpret(t, `
+          struct S {
+              fun test(): Int {
+                  let f = fun (): Int {
+                      return 2 * 21
+                  }
+                  return f()
+              }
+          }
+
+          fun test(): Int {
+              return S().test()
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+		assert.Equal(t, interpreter.NewIntValueFromInt64(42), result)
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, inter := parseCheckOptimizeAndInterpret(t, `
+          fun test(): UInt8 {
+              return 200 + 100
+          }
+        `)
+
+		require.IsType(t, &ast.BinaryExpression{}, returnedExpression(t, checker, "test"))
+
+		_, err := inter.Invoke("test")
+		require.Error(t, err)
+		require.ErrorAs(t, err, &interpreter.OverflowError{})
+	})
+
+	t.Run("division by zero", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, inter := parseCheckOptimizeAndInterpret(t, `
+          fun test(): Int {
+              return 1 / 0
+          }
+        `)
+
+		require.IsType(t, &ast.BinaryExpression{}, returnedExpression(t, checker, "test"))
+
+		_, err := inter.Invoke("test")
+		require.Error(t, err)
+		require.ErrorAs(t, err, &interpreter.DivisionByZeroError{})
+	})
+
+	t.Run("optional", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, inter := parseCheckOptimizeAndInterpret(t, `
+          fun test(): Int {
+              let x: Int? = nil
+              return x ?? (1 + 2)
+          }
+        `)
+
+		expression := returnedExpression(t, checker, "test")
+		require.IsType(t, &ast.BinaryExpression{}, expression)
+		require.IsType(t, &ast.IntegerExpression{}, expression.(*ast.BinaryExpression).Right)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+		assert.Equal(t, interpreter.NewIntValueFromInt64(3), result)
+	})
+}
+
+func TestOptimizeLoopInvariantHoisting(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("while", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, inter := parseCheckOptimizeAndInterpret(t, `
+          fun test(n: Int): Int {
+              let a = 3
+              var i = 0
+              var sum = 0
+              while i < n * 2 {
+                  sum = sum + a * n
+                  i = i + 1
+              }
+              return sum
+          }
+        `)
+
+		statements := functionStatements(t, checker, "test")
+		require.Len(t, statements, 7)
+
+		for _, statement := range statements[3:5] {
+			require.IsType(t, &ast.VariableDeclaration{}, statement)
+			declaration := statement.(*ast.VariableDeclaration)
+			assert.True(t, declaration.IsConstant)
+			assert.Equal(t, sema.IntType, checker.Elaboration.VariableDeclarationTargetTypes[declaration])
+		}
+
+		loop := statements[5].(*ast.WhileStatement)
+		require.IsType(t, &ast.BinaryExpression{}, loop.Test)
+		require.IsType(t, &ast.IdentifierExpression{}, loop.Test.(*ast.BinaryExpression).Right)
+
+		result, err := inter.Invoke("test", interpreter.NewIntValueFromInt64(4))
+		require.NoError(t, err)
+		assert.Equal(t, interpreter.NewIntValueFromInt64(96), result)
+	})
+
+	t.Run("nested loops", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, inter := parseCheckOptimizeAndInterpret(t, `
+          fun test(xs: [Int]): Int {
+              var sum = 0
+              for x in xs {
+                  let y = x * 2
+                  for z in xs {
+                      sum = sum + y * 3 + z
+                  }
+              }
+              return sum
+          }
+        `)
+
+		statements := functionStatements(t, checker, "test")
+		require.Len(t, statements, 3)
+
+		outerLoop := statements[1].(*ast.ForStatement)
+		innerStatements := outerLoop.Block.Statements
+		require.Len(t, innerStatements, 3)
+		require.IsType(t, &ast.VariableDeclaration{}, innerStatements[1])
+
+		result, err := inter.Invoke(
+			"test",
+			interpreter.NewArrayValueUnownedNonCopying(
+				interpreter.NewIntValueFromInt64(1),
+				interpreter.NewIntValueFromInt64(2),
+			),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, interpreter.NewIntValueFromInt64(42), result)
+	})
+
+	t.Run("variant", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, inter := parseCheckOptimizeAndInterpret(t, `
+          fun test(n: UInt8): UInt8 {
+              var a: UInt8 = 1
+              var sum: UInt8 = 0
+              var i = 0
+              while i < 3 {
+                  let b = i * 2
+                  sum = sum + a + (n - 1)
+                  a = a + UInt8(b)
+                  i = i + 1
+              }
+              return sum
+          }
+        `)
+
+		statements := functionStatements(t, checker, "test")
+		require.Len(t, statements, 5)
+
+		result, err := inter.Invoke("test", interpreter.UInt8Value(2))
+		require.NoError(t, err)
+		assert.Equal(t, interpreter.UInt8Value(8), result)
+	})
+
+	t.Run("loop never executed", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, inter := parseCheckOptimizeAndInterpret(t, `
+          fun test(n: Int): Int {
+              let a = 1
+              var i = 0
+              while i < 0 {
+                  i = i + (a - n)
+              }
+              return i
+          }
+        `)
+
+		result, err := inter.Invoke("test", interpreter.NewIntValueFromInt64(1))
+		require.NoError(t, err)
+		assert.Equal(t, interpreter.NewIntValueFromInt64(0), result)
+	})
+}
+
+func TestOptimizeMemberIndices(t *testing.T) {
+
+	t.Parallel()
+
+	checker := parseCheckAndOptimize(t, `
+      struct S {
+          let x: Int
+
+          init() {
+              self.x = 1 + 2
+          }
+
+          fun test(): Int {
+              return self.x
+          }
+      }
+    `)
+
+	compositeDeclarations := checker.Program.CompositeDeclarations()
+	require.Len(t, compositeDeclarations, 1)
+
+	members := compositeDeclarations[0].Members
+	assert.Contains(t, members.FieldsByIdentifier(), "x")
+	assert.Contains(t, members.FunctionsByIdentifier(), "test")
+
+	initializer := members.Initializers()[0].FunctionDeclaration
+	assignment := initializer.FunctionBlock.Block.Statements[0].(*ast.AssignmentStatement)
+	assert.IsType(t, &ast.IntegerExpression{}, assignment.Value)
+}
+
+func BenchmarkOptimize(b *testing.B) {
+
+	const code = `
+      fun test(n: Int): Int {
+          let a = 3
+          let b = 4
+          var i = 0
+          var sum = 0
+          while i < n {
+              sum = sum + (a * b + 2 * 3) * (a - b)
+              i = i + 1
+          }
+          return sum
+      }
+    `
+
+	for _, optimize := range []bool{false, true} {
+
+		b.Run(fmt.Sprintf("optimized=%t", optimize), func(b *testing.B) {
+
+			checker, err := checker.ParseAndCheckWithOptions(b, code, checker.ParseAndCheckOptions{})
+			require.NoError(b, err)
+
+			if optimize {
+				optimizer.Optimize(checker.Program, checker.Elaboration)
+			}
+
+			inter, err := interpreter.NewInterpreter(
+				interpreter.ProgramFromChecker(checker),
+				checker.Location,
+			)
+			require.NoError(b, err)
+
+			err = inter.Interpret()
+			require.NoError(b, err)
+
+			argument := interpreter.NewIntValueFromInt64(1000)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_, err = inter.Invoke("test", argument)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser2
+
+import (
+	"github.com/onflow/cadence/runtime/errors"
+)
+
+// The error codes of the errors of this package.
+//
+// NOTE: Codes must never be changed or reused, new errors must be assigned new codes.
+//
+func init() {
+	errors.RegisterErrorCodes(
+		errors.ErrorCategoryUser,
+		map[errors.ErrorCode]error{
+			1001: Error{},
+			1002: &SyntaxError{},
+			1003: &JuxtaposedUnaryOperatorsError{},
+			1004: &InvalidIntegerLiteralError{},
+		},
+	)
+}
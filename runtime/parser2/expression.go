@@ -766,6 +766,18 @@ func defineIdentifierExpression() {
 			case keywordFun:
 				return parseFunctionExpression(p, token)
 
+			case keywordTry:
+				// `try` is only a keyword if an invocation follows,
+				// so it can still be used as an identifier
+
+				if isTryExpression(p, token) {
+					return parseTryExpressionRemainder(p, token)
+				}
+
+				return &ast.IdentifierExpression{
+					Identifier: tokenToIdentifier(token),
+				}
+
 			default:
 				return &ast.IdentifierExpression{
 					Identifier: tokenToIdentifier(token),
@@ -851,6 +863,39 @@ func parseCreateExpressionRemainder(p *parser, token lexer.Token) *ast.CreateExp
 	}
 }
 
+// isTryExpression returns true if the given `try` keyword token starts a try expression,
+// i.e. it is followed by an identifier on the same line, which is not the casting operator.
+//
+// Otherwise, `try` is an identifier, e.g. in `try.foo` or `try(1)`
+//
+func isTryExpression(p *parser, token lexer.Token) bool {
+	return p.current.Is(lexer.TokenIdentifier) &&
+		p.current.Value != keywordAs &&
+		p.current.StartPos.Line == token.EndPos.Line
+}
+
+// parseTryExpressionRemainder parses a try expression.
+//
+//     tryExpression : 'try' invocation
+//
+func parseTryExpressionRemainder(p *parser, token lexer.Token) *ast.TryExpression {
+	expression := parseExpression(p, exprLeftBindingPowerUnaryPrefix)
+
+	invocation, ok := expression.(*ast.InvocationExpression)
+	if !ok {
+		panic(fmt.Errorf(
+			"expected invocation after %s, got %s",
+			keywordTry,
+			expression,
+		))
+	}
+
+	return &ast.TryExpression{
+		InvocationExpression: invocation,
+		StartPos:             token.StartPos,
+	}
+}
+
 // Invocation Expression Grammar:
 //
 //     invocation : '(' ( argument ( ',' argument )* )? ')'
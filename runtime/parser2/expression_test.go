@@ -2205,6 +2205,191 @@ func TestParseDestroy(t *testing.T) {
 	})
 }
 
+func TestParseTry(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("simple", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseExpression("try f()")
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			&ast.TryExpression{
+				InvocationExpression: &ast.InvocationExpression{
+					InvokedExpression: &ast.IdentifierExpression{
+						Identifier: ast.Identifier{
+							Identifier: "f",
+							Pos:        ast.Position{Line: 1, Column: 4, Offset: 4},
+						},
+					},
+					ArgumentsStartPos: ast.Position{Line: 1, Column: 5, Offset: 5},
+					EndPos:            ast.Position{Line: 1, Column: 6, Offset: 6},
+				},
+				StartPos: ast.Position{Line: 1, Column: 0, Offset: 0},
+			},
+			result,
+		)
+	})
+
+	t.Run("nil-coalescing", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseExpression("try a.f() ?? 1")
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			&ast.BinaryExpression{
+				Operation: ast.OperationNilCoalesce,
+				Left: &ast.TryExpression{
+					InvocationExpression: &ast.InvocationExpression{
+						InvokedExpression: &ast.MemberExpression{
+							Expression: &ast.IdentifierExpression{
+								Identifier: ast.Identifier{
+									Identifier: "a",
+									Pos:        ast.Position{Line: 1, Column: 4, Offset: 4},
+								},
+							},
+							AccessPos: ast.Position{Line: 1, Column: 5, Offset: 5},
+							Identifier: ast.Identifier{
+								Identifier: "f",
+								Pos:        ast.Position{Line: 1, Column: 6, Offset: 6},
+							},
+						},
+						ArgumentsStartPos: ast.Position{Line: 1, Column: 7, Offset: 7},
+						EndPos:            ast.Position{Line: 1, Column: 8, Offset: 8},
+					},
+					StartPos: ast.Position{Line: 1, Column: 0, Offset: 0},
+				},
+				Right: &ast.IntegerExpression{
+					Value: big.NewInt(1),
+					Base:  10,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 13, Offset: 13},
+						EndPos:   ast.Position{Line: 1, Column: 13, Offset: 13},
+					},
+				},
+			},
+			result,
+		)
+	})
+
+	t.Run("no invocation", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, errs := ParseExpression("try x")
+		require.Len(t, errs, 1)
+	})
+
+	t.Run("identifier, declaration", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseDeclarations("let try = 1")
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			[]ast.Declaration{
+				&ast.VariableDeclaration{
+					IsConstant: true,
+					Identifier: ast.Identifier{
+						Identifier: "try",
+						Pos:        ast.Position{Line: 1, Column: 4, Offset: 4},
+					},
+					Value: &ast.IntegerExpression{
+						Value: big.NewInt(1),
+						Base:  10,
+						Range: ast.Range{
+							StartPos: ast.Position{Line: 1, Column: 10, Offset: 10},
+							EndPos:   ast.Position{Line: 1, Column: 10, Offset: 10},
+						},
+					},
+					Transfer: &ast.Transfer{
+						Operation: ast.TransferOperationCopy,
+						Pos:       ast.Position{Line: 1, Column: 8, Offset: 8},
+					},
+					StartPos: ast.Position{Line: 1, Column: 0, Offset: 0},
+				},
+			},
+			result,
+		)
+	})
+
+	t.Run("identifier, member access", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseExpression("try.foo")
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			&ast.MemberExpression{
+				Expression: &ast.IdentifierExpression{
+					Identifier: ast.Identifier{
+						Identifier: "try",
+						Pos:        ast.Position{Line: 1, Column: 0, Offset: 0},
+					},
+				},
+				AccessPos: ast.Position{Line: 1, Column: 3, Offset: 3},
+				Identifier: ast.Identifier{
+					Identifier: "foo",
+					Pos:        ast.Position{Line: 1, Column: 4, Offset: 4},
+				},
+			},
+			result,
+		)
+	})
+
+	t.Run("identifier, invocation", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseExpression("try(1)")
+		require.Empty(t, errs)
+
+		require.IsType(t, &ast.InvocationExpression{}, result)
+		assert.Equal(t,
+			&ast.IdentifierExpression{
+				Identifier: ast.Identifier{
+					Identifier: "try",
+					Pos:        ast.Position{Line: 1, Column: 0, Offset: 0},
+				},
+			},
+			result.(*ast.InvocationExpression).InvokedExpression,
+		)
+	})
+
+	t.Run("identifier, casting", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseExpression("try as Int")
+		require.Empty(t, errs)
+
+		require.IsType(t, &ast.CastingExpression{}, result)
+	})
+
+	t.Run("identifier, followed by invocation on next line", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseStatements("let x = try\nf()")
+		require.Empty(t, errs)
+
+		require.Len(t, result, 2)
+		require.IsType(t, &ast.VariableDeclaration{}, result[0])
+		assert.IsType(t,
+			&ast.IdentifierExpression{},
+			result[0].(*ast.VariableDeclaration).Value,
+		)
+		assert.IsType(t, &ast.ExpressionStatement{}, result[1])
+	})
+}
+
 func TestParseLineComment(t *testing.T) {
 
 	t.Parallel()
@@ -21,6 +21,8 @@ package lexer
 import (
 	"context"
 	"errors"
+	"os"
+	goRuntime "runtime"
 	"testing"
 
 	"go.uber.org/goleak"
@@ -30,6 +32,12 @@ import (
 )
 
 func TestMain(m *testing.M) {
+	// The js/wasm runtime has an event handler goroutine,
+	// which would be reported as a leak
+	if goRuntime.GOOS == "js" {
+		os.Exit(m.Run())
+	}
+
 	goleak.VerifyTestMain(m)
 }
 
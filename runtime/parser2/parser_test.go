@@ -21,6 +21,8 @@ package parser2
 import (
 	"fmt"
 	"math/big"
+	"os"
+	goRuntime "runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -33,6 +35,12 @@ import (
 )
 
 func TestMain(m *testing.M) {
+	// The js/wasm runtime has an event handler goroutine,
+	// which would be reported as a leak
+	if goRuntime.GOOS == "js" {
+		os.Exit(m.Run())
+	}
+
 	goleak.VerifyTestMain(m)
 }
 
@@ -0,0 +1,243 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/binary"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// ProgramCacheKey identifies a parsed and checked program
+// by its location, the hash of its source code,
+// and the hash of the environment it was checked in.
+//
+type ProgramCacheKey struct {
+	Location common.Location
+	CodeHash [32]byte
+	// EnvironmentHash is the hash of the values which were predeclared for the program
+	EnvironmentHash [32]byte
+}
+
+func newProgramCacheKey(location common.Location, code []byte, environmentHash [32]byte) ProgramCacheKey {
+	return ProgramCacheKey{
+		Location:        location,
+		CodeHash:        sha3.Sum256(code),
+		EnvironmentHash: environmentHash,
+	}
+}
+
+// programEnvironmentHash returns the hash of the environment in which a program at the given location is checked,
+// i.e. of the given value declarations which are available in the location.
+//
+// The hash covers the name, kind, type, and argument labels of each declaration, in order
+//
+func programEnvironmentHash(location common.Location, valueDeclarations []sema.ValueDeclaration) [32]byte {
+	hasher := sha3.New256()
+
+	writeString := func(s string) {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(s)))
+		_, _ = hasher.Write(length[:])
+		_, _ = hasher.Write([]byte(s))
+	}
+
+	for _, declaration := range valueDeclarations {
+		if !declaration.ValueDeclarationAvailable(location) {
+			continue
+		}
+
+		writeString(declaration.ValueDeclarationName())
+		writeString(declaration.ValueDeclarationKind().Name())
+
+		if declaration.ValueDeclarationIsConstant() {
+			writeString("constant")
+		} else {
+			writeString("variable")
+		}
+
+		var typeID sema.TypeID
+		if ty := declaration.ValueDeclarationType(); ty != nil {
+			typeID = ty.ID()
+		}
+		writeString(string(typeID))
+
+		argumentLabels := declaration.ValueDeclarationArgumentLabels()
+		writeString(strings.Join(argumentLabels, ","))
+	}
+
+	var hash [32]byte
+	copy(hash[:], hasher.Sum(nil))
+	return hash
+}
+
+// ProgramCacheEntry is a parsed and checked program stored in a program cache.
+//
+// Dependencies are the keys of all programs the program imported when it was checked,
+// directly or indirectly. The entry is only used if the code of all dependencies is unchanged.
+//
+type ProgramCacheEntry struct {
+	Program      *interpreter.Program
+	Dependencies []ProgramCacheKey
+}
+
+// ProgramCache is the backing store of the runtime's program cache.
+//
+// Implementations must be safe for concurrent use,
+// as a runtime may be used by multiple goroutines,
+// and a cache may be shared by multiple runtimes.
+//
+// Cached programs are shared, and must not be modified.
+// Implementations which persist entries must be able to restore the program's elaboration;
+// an implementation may also choose to only persist across workers in the same process.
+//
+type ProgramCache interface {
+	// GetProgram returns the cache entry for the given key, if any.
+	GetProgram(key ProgramCacheKey) (entry *ProgramCacheEntry, ok bool)
+	// SetProgram stores the given cache entry for the given key.
+	SetProgram(key ProgramCacheKey, entry *ProgramCacheEntry)
+}
+
+// InMemoryProgramCache is a ProgramCache which keeps the entries in memory.
+// If the capacity is reached, the least recently used entry is evicted.
+//
+type InMemoryProgramCache struct {
+	mutex    sync.Mutex
+	capacity int
+	entries  map[ProgramCacheKey]*list.Element
+	order    *list.List
+}
+
+var _ ProgramCache = &InMemoryProgramCache{}
+
+type inMemoryProgramCacheItem struct {
+	key   ProgramCacheKey
+	entry *ProgramCacheEntry
+}
+
+// NewInMemoryProgramCache returns a new in-memory program cache
+// holding at most the given number of entries.
+// A capacity of zero or less means the cache is unbounded.
+//
+func NewInMemoryProgramCache(capacity int) *InMemoryProgramCache {
+	return &InMemoryProgramCache{
+		capacity: capacity,
+		entries:  map[ProgramCacheKey]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+func (c *InMemoryProgramCache) GetProgram(key ProgramCacheKey) (*ProgramCacheEntry, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+
+	return element.Value.(inMemoryProgramCacheItem).entry, true
+}
+
+func (c *InMemoryProgramCache) SetProgram(key ProgramCacheKey, entry *ProgramCacheEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	item := inMemoryProgramCacheItem{
+		key:   key,
+		entry: entry,
+	}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = item
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(item)
+
+	if c.capacity > 0 && c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(inMemoryProgramCacheItem).key)
+	}
+}
+
+// Len returns the number of entries in the cache.
+//
+func (c *InMemoryProgramCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.order.Len()
+}
+
+// programDependencies collects the dependencies of a program while it is checked.
+//
+type programDependencies map[ProgramCacheKey]struct{}
+
+func (d programDependencies) add(key ProgramCacheKey, dependencies []ProgramCacheKey) {
+	d[key] = struct{}{}
+	for _, dependency := range dependencies {
+		d[dependency] = struct{}{}
+	}
+}
+
+// keys returns the keys of the dependencies, sorted by location and hashes
+//
+func (d programDependencies) keys() []ProgramCacheKey {
+	keys := make([]ProgramCacheKey, 0, len(d))
+
+	// Iterating over the map in a non-deterministic way is OK,
+	// the keys are sorted
+
+	for key := range d { //nolint:maprangecheck
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		a := keys[i]
+		b := keys[j]
+
+		aLocationID := a.Location.ID()
+		bLocationID := b.Location.ID()
+		if aLocationID != bLocationID {
+			return aLocationID < bLocationID
+		}
+
+		if c := bytes.Compare(a.CodeHash[:], b.CodeHash[:]); c != 0 {
+			return c < 0
+		}
+
+		return bytes.Compare(a.EnvironmentHash[:], b.EnvironmentHash[:]) < 0
+	})
+
+	return keys
+}
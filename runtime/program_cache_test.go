@@ -0,0 +1,378 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestRuntimeProgramCache(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	fooLocation := common.AddressLocation{Address: address, Name: "Foo"}
+	barLocation := common.AddressLocation{Address: address, Name: "Bar"}
+
+	script := []byte(`
+      import Foo from 0x1
+
+      pub fun main(): Int {
+          let foo: Foo.S? = nil
+          return 1
+      }
+    `)
+
+	fooCode := `
+      import Bar from 0x1
+
+      pub contract Foo {
+          pub struct S {
+              pub let bar: Bar.T
+              init(bar: Bar.T) {
+                  self.bar = bar
+              }
+          }
+      }
+    `
+
+	barCode := `
+      pub contract Bar {
+          pub struct T {}
+      }
+    `
+
+	type execution struct {
+		lock              sync.Mutex
+		contract          map[string]string
+		checked           map[common.LocationID]int
+		predeclaredValues []ValueDeclaration
+	}
+
+	newExecution := func() *execution {
+		return &execution{
+			contract: map[string]string{
+				"Foo": fooCode,
+				"Bar": barCode,
+			},
+			checked: map[common.LocationID]int{},
+		}
+	}
+
+	executeScript := func(runtime Runtime, e *execution) (cadence.Value, error) {
+
+		storage := newTestStorage(nil, nil)
+
+		runtimeInterface := &testRuntimeInterface{
+			storage:         storage,
+			resolveLocation: singleIdentifierLocationResolver(t),
+			getAccountContractCode: func(_ Address, name string) ([]byte, error) {
+				e.lock.Lock()
+				defer e.lock.Unlock()
+
+				return []byte(e.contract[name]), nil
+			},
+			programChecked: func(location common.Location, _ time.Duration) {
+				e.lock.Lock()
+				defer e.lock.Unlock()
+
+				e.checked[location.ID()]++
+			},
+		}
+
+		return runtime.ExecuteScript(
+			Script{
+				Source: script,
+			},
+			Context{
+				Interface:         runtimeInterface,
+				Location:          utils.TestLocation,
+				PredeclaredValues: e.predeclaredValues,
+			},
+		)
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := NewInterpreterRuntime()
+
+		e := newExecution()
+
+		for i := 0; i < 3; i++ {
+			_, err := executeScript(runtime, e)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 3, e.checked[fooLocation.ID()])
+		assert.Equal(t, 3, e.checked[barLocation.ID()])
+	})
+
+	t.Run("hit", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := NewInterpreterRuntime(
+			WithProgramCache(NewInMemoryProgramCache(0)),
+		)
+
+		e := newExecution()
+
+		for i := 0; i < 3; i++ {
+			result, err := executeScript(runtime, e)
+			require.NoError(t, err)
+			assert.Equal(t, cadence.NewInt(1), result)
+		}
+
+		assert.Equal(t, 1, e.checked[fooLocation.ID()])
+		assert.Equal(t, 1, e.checked[barLocation.ID()])
+	})
+
+	t.Run("shared", func(t *testing.T) {
+
+		t.Parallel()
+
+		cache := NewInMemoryProgramCache(0)
+
+		e := newExecution()
+
+		for i := 0; i < 3; i++ {
+			runtime := NewInterpreterRuntime(WithProgramCache(cache))
+
+			_, err := executeScript(runtime, e)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 1, e.checked[fooLocation.ID()])
+		assert.Equal(t, 1, e.checked[barLocation.ID()])
+		assert.Equal(t, 2, cache.Len())
+	})
+
+	t.Run("code changed", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := NewInterpreterRuntime(
+			WithProgramCache(NewInMemoryProgramCache(0)),
+		)
+
+		e := newExecution()
+
+		_, err := executeScript(runtime, e)
+		require.NoError(t, err)
+
+		e.contract["Foo"] = fooCode + "\n// changed"
+
+		_, err = executeScript(runtime, e)
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, e.checked[fooLocation.ID()])
+		assert.Equal(t, 1, e.checked[barLocation.ID()])
+	})
+
+	t.Run("dependency changed", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := NewInterpreterRuntime(
+			WithProgramCache(NewInMemoryProgramCache(0)),
+		)
+
+		e := newExecution()
+
+		_, err := executeScript(runtime, e)
+		require.NoError(t, err)
+
+		// Remove the type Foo depends on
+
+		e.contract["Bar"] = `pub contract Bar {}`
+
+		_, err = executeScript(runtime, e)
+		require.Error(t, err)
+
+		assert.Equal(t, 2, e.checked[fooLocation.ID()])
+		assert.Equal(t, 2, e.checked[barLocation.ID()])
+	})
+
+	t.Run("environment changed", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := NewInterpreterRuntime(
+			WithProgramCache(NewInMemoryProgramCache(0)),
+		)
+
+		e := newExecution()
+
+		_, err := executeScript(runtime, e)
+		require.NoError(t, err)
+
+		// The programs were checked without the predeclared value,
+		// so they must be checked again
+
+		e.predeclaredValues = []ValueDeclaration{
+			{
+				Name:       "foo",
+				Type:       sema.IntType,
+				Kind:       common.DeclarationKindConstant,
+				IsConstant: true,
+				Value:      interpreter.NewIntValueFromInt64(1),
+			},
+		}
+
+		for i := 0; i < 2; i++ {
+			_, err = executeScript(runtime, e)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 2, e.checked[fooLocation.ID()])
+		assert.Equal(t, 2, e.checked[barLocation.ID()])
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+
+		t.Parallel()
+
+		const workers = 10
+
+		cache := NewInMemoryProgramCache(0)
+
+		e := newExecution()
+
+		var wg sync.WaitGroup
+		wg.Add(workers)
+
+		errs := make([]error, workers)
+
+		for i := 0; i < workers; i++ {
+			go func(i int) {
+				defer wg.Done()
+
+				runtime := NewInterpreterRuntime(WithProgramCache(cache))
+				_, errs[i] = executeScript(runtime, e)
+			}(i)
+		}
+
+		wg.Wait()
+
+		for _, err := range errs {
+			require.NoError(t, err)
+		}
+
+		_, err := executeScript(NewInterpreterRuntime(WithProgramCache(cache)), e)
+		require.NoError(t, err)
+
+		assert.LessOrEqual(t, e.checked[fooLocation.ID()], workers)
+		assert.Equal(t, 2, cache.Len())
+	})
+}
+
+func TestInMemoryProgramCache(t *testing.T) {
+
+	t.Parallel()
+
+	newKey := func(name string) ProgramCacheKey {
+		return newProgramCacheKey(utils.TestLocation, []byte(name), [32]byte{})
+	}
+
+	cache := NewInMemoryProgramCache(2)
+
+	a := &ProgramCacheEntry{}
+	b := &ProgramCacheEntry{}
+	c := &ProgramCacheEntry{}
+
+	cache.SetProgram(newKey("a"), a)
+	cache.SetProgram(newKey("b"), b)
+
+	// Use a, so b is the least recently used entry
+
+	entry, ok := cache.GetProgram(newKey("a"))
+	require.True(t, ok)
+	assert.Same(t, a, entry)
+
+	cache.SetProgram(newKey("c"), c)
+
+	assert.Equal(t, 2, cache.Len())
+
+	_, ok = cache.GetProgram(newKey("b"))
+	assert.False(t, ok)
+
+	entry, ok = cache.GetProgram(newKey("a"))
+	require.True(t, ok)
+	assert.Same(t, a, entry)
+
+	entry, ok = cache.GetProgram(newKey("c"))
+	require.True(t, ok)
+	assert.Same(t, c, entry)
+}
+
+func TestProgramDependenciesKeys(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	newKey := func(name string, code string) ProgramCacheKey {
+		return newProgramCacheKey(
+			common.AddressLocation{Address: address, Name: name},
+			[]byte(code),
+			[32]byte{},
+		)
+	}
+
+	dependencies := programDependencies{}
+	dependencies.add(
+		newKey("C", "c"),
+		[]ProgramCacheKey{
+			newKey("A", "a"),
+			newKey("B", "b"),
+			newKey("A", "a"),
+		},
+	)
+
+	keys := dependencies.keys()
+
+	require.Len(t, keys, 3)
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, keys, dependencies.keys())
+	}
+
+	assert.Equal(t,
+		[]string{"A", "B", "C"},
+		[]string{
+			keys[0].Location.(common.AddressLocation).Name,
+			keys[1].Location.(common.AddressLocation).Name,
+			keys[2].Location.(common.AddressLocation).Name,
+		},
+	)
+}
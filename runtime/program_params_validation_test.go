@@ -969,3 +969,170 @@ func TestTransactionParameterTypeValidation(t *testing.T) {
 		assert.Contains(t, err.Error(), "cannot import value of type PublicAccount.Keys")
 	})
 }
+
+func TestRuntimeValidateArguments(t *testing.T) {
+
+	t.Parallel()
+
+	validateArguments := func(t *testing.T, code string, args ...cadence.Value) ([]cadence.Value, error) {
+
+		encodedArgs := make([][]byte, len(args))
+		for i, arg := range args {
+			encodedArg, err := json.Encode(arg)
+			require.NoError(t, err)
+			encodedArgs[i] = encodedArg
+		}
+
+		rt := NewInterpreterRuntime()
+
+		runtimeInterface := &testRuntimeInterface{
+			decodeArgument: func(b []byte, t cadence.Type) (value cadence.Value, err error) {
+				return json.Decode(b)
+			},
+			getSigningAccounts: func() ([]Address, error) {
+				require.Fail(t, "authorizers should not be requested")
+				return nil, nil
+			},
+		}
+
+		return rt.ValidateArguments(
+			Script{
+				Source:    []byte(code),
+				Arguments: encodedArgs,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  utils.TestLocation,
+			},
+		)
+	}
+
+	fooStruct := cadence.Struct{
+		StructType: &cadence.StructType{
+			Location:            utils.TestLocation,
+			QualifiedIdentifier: "Foo",
+			Fields:              []cadence.Field{},
+		},
+		Fields: []cadence.Value{},
+	}
+
+	t.Run("script", func(t *testing.T) {
+		t.Parallel()
+
+		values, err := validateArguments(t,
+			`
+              pub struct Foo {}
+
+              pub fun main(a: Int, b: String, c: Foo) {
+                  panic("should not be executed")
+              }
+            `,
+			cadence.NewInt(1),
+			cadence.String("2"),
+			fooStruct,
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]cadence.Value{
+				cadence.NewInt(1),
+				cadence.String("2"),
+				fooStruct,
+			},
+			values,
+		)
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		t.Parallel()
+
+		values, err := validateArguments(t,
+			`
+              transaction(a: Int) {
+                  prepare(signer: AuthAccount) {
+                      panic("should not be executed")
+                  }
+              }
+            `,
+			cadence.NewInt(1),
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]cadence.Value{
+				cadence.NewInt(1),
+			},
+			values,
+		)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := validateArguments(t,
+			`
+              pub fun main(a: Int, b: String, c: Bool, d: Bool?) {}
+            `,
+			cadence.String("1"),
+			cadence.String("2"),
+			cadence.NewInt(3),
+			cadence.NewOptional(cadence.NewBool(true)),
+		)
+		require.Error(t, err)
+
+		require.IsType(t, Error{}, err)
+		runtimeErr := err.(Error)
+
+		require.IsType(t, &InvalidEntryPointArgumentsError{}, runtimeErr.Err)
+		argumentErrors := runtimeErr.Err.(*InvalidEntryPointArgumentsError).ChildErrors()
+
+		require.Len(t, argumentErrors, 2)
+
+		for i, expectedIndex := range []int{0, 2} {
+			require.IsType(t, &InvalidEntryPointArgumentError{}, argumentErrors[i])
+			argumentErr := argumentErrors[i].(*InvalidEntryPointArgumentError)
+
+			assert.Equal(t, expectedIndex, argumentErr.Index)
+			assert.IsType(t, &InvalidValueTypeError{}, argumentErr.Err)
+		}
+	})
+
+	t.Run("invalid argument count", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := validateArguments(t,
+			`
+              pub fun main(a: Int) {}
+            `,
+		)
+		require.Error(t, err)
+
+		require.IsType(t, Error{}, err)
+		runtimeErr := err.(Error)
+
+		assert.Equal(t,
+			InvalidEntryPointParameterCountError{
+				Expected: 1,
+				Actual:   0,
+			},
+			runtimeErr.Err,
+		)
+	})
+
+	t.Run("invalid program", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := validateArguments(t,
+			`
+              pub fun main(a: Int) {
+                  let x: String = a
+              }
+            `,
+			cadence.NewInt(1),
+		)
+		require.Error(t, err)
+
+		var checkingErr *ParsingCheckingError
+		require.ErrorAs(t, err, &checkingErr)
+	})
+}
@@ -0,0 +1,271 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/stdlib"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestRuntimeRandom(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := NewInterpreterRuntime()
+
+	executeScript := func(code string, readRandom func(buffer []byte) error) (cadence.Value, error) {
+		return runtime.ExecuteScript(
+			Script{
+				Source: []byte(code),
+			},
+			Context{
+				Interface: &testRuntimeInterface{
+					readRandom: readRandom,
+				},
+				Location: utils.TestLocation,
+			},
+		)
+	}
+
+	newSeededSource := func(seed int64) func(buffer []byte) error {
+		random := rand.New(rand.NewSource(seed))
+		return func(buffer []byte) error {
+			_, err := random.Read(buffer)
+			return err
+		}
+	}
+
+	t.Run("uint64", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, err := executeScript(
+			`
+              pub fun main(): UInt64 {
+                  return Random.uint64()
+              }
+            `,
+			func(buffer []byte) error {
+				require.Len(t, buffer, 8)
+				copy(buffer, []byte{1, 2, 3, 4, 5, 6, 7, 8})
+				return nil
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, cadence.UInt64(0x0102030405060708), result)
+	})
+
+	t.Run("range", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, err := executeScript(
+			`
+              pub fun main(): [Int8] {
+                  let results: [Int8] = []
+                  var i = 0
+                  while i < 1000 {
+                      results.append(Random.range(from: -3 as Int8, upTo: 3))
+                      i = i + 1
+                  }
+                  return results
+              }
+            `,
+			newSeededSource(1),
+		)
+		require.NoError(t, err)
+
+		counts := map[cadence.Int8]int{}
+		for _, value := range result.(cadence.Array).Values {
+			counts[value.(cadence.Int8)]++
+		}
+
+		// All integers in the range, and only those, are returned
+
+		require.Len(t, counts, 6)
+		for value := cadence.Int8(-3); value < 3; value++ {
+			assert.Greater(t, counts[value], 100)
+		}
+	})
+
+	t.Run("range of large integers", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, err := executeScript(
+			`
+              pub fun main(): [AnyStruct] {
+                  return [
+                      Random.range(from: 0 as UInt64, upTo: UInt64.max),
+                      Random.range(from: 10 as UInt256, upTo: 11),
+                      Random.range(from: -0x10000000000000000000000000, upTo: -0xfffffffffffffffffffffffff)
+                  ]
+              }
+            `,
+			newSeededSource(1),
+		)
+		require.NoError(t, err)
+
+		values := result.(cadence.Array).Values
+		require.Len(t, values, 3)
+		assert.IsType(t, cadence.UInt64(0), values[0])
+		assert.Equal(t, cadence.NewUInt256(10), values[1])
+		assert.Equal(t,
+			cadence.NewIntFromBig(new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 100))),
+			values[2],
+		)
+	})
+
+	t.Run("range rejects out-of-range numbers", func(t *testing.T) {
+
+		t.Parallel()
+
+		var reads int
+
+		result, err := executeScript(
+			`
+              pub fun main(): Int {
+                  return Random.range(from: 10, upTo: 15)
+              }
+            `,
+			func(buffer []byte) error {
+				reads++
+
+				// The first random number is out of range
+
+				if reads == 1 {
+					buffer[0] = 0xff
+				} else {
+					buffer[0] = 0x02
+				}
+				return nil
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, cadence.NewInt(12), result)
+		assert.Equal(t, 2, reads)
+	})
+
+	t.Run("empty range", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := executeScript(
+			`
+              pub fun main(): Int {
+                  return Random.range(from: 1, upTo: 1)
+              }
+            `,
+			newSeededSource(1),
+		)
+		require.Error(t, err)
+		require.ErrorAs(t, err, &stdlib.RandomError{})
+	})
+
+	t.Run("range not fitting into type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := executeScript(
+			`
+              pub fun main(): UInt8 {
+                  return Random.range(from: 250 as UInt8, upTo: 1000)
+              }
+            `,
+			newSeededSource(1),
+		)
+		require.Error(t, err)
+		require.ErrorAs(t, err, &interpreter.OverflowError{})
+	})
+
+	t.Run("shuffle", func(t *testing.T) {
+
+		t.Parallel()
+
+		const code = `
+          pub fun main(): [[String]] {
+              let array = ["a", "b", "c", "d", "e", "f", "g", "h"]
+              let shuffled = Random.shuffle(array)
+              return [array, shuffled]
+          }
+        `
+
+		result, err := executeScript(code, newSeededSource(1))
+		require.NoError(t, err)
+
+		arrays := result.(cadence.Array).Values
+		original := arrays[0].(cadence.Array).Values
+		shuffled := arrays[1].(cadence.Array).Values
+
+		// The given array is not modified
+
+		assert.Equal(t,
+			[]cadence.Value{
+				cadence.String("a"),
+				cadence.String("b"),
+				cadence.String("c"),
+				cadence.String("d"),
+				cadence.String("e"),
+				cadence.String("f"),
+				cadence.String("g"),
+				cadence.String("h"),
+			},
+			original,
+		)
+
+		assert.ElementsMatch(t, original, shuffled)
+		assert.NotEqual(t, original, shuffled)
+
+		// The same random source results in the same order
+
+		otherResult, err := executeScript(code, newSeededSource(1))
+		require.NoError(t, err)
+
+		assert.Equal(t, result, otherResult)
+	})
+
+	t.Run("error", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := executeScript(
+			`
+              pub fun main(): UInt64 {
+                  return Random.uint64()
+              }
+            `,
+			func(_ []byte) error {
+				return assert.AnError
+			},
+		)
+		require.Error(t, err)
+		require.ErrorIs(t, err, assert.AnError)
+	})
+}
@@ -19,25 +19,61 @@
 package runtime
 
 import (
+	goContext "context"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/onflow/cadence/runtime/ast"
-	"github.com/onflow/cadence/runtime/cmd"
 	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/errors"
 	"github.com/onflow/cadence/runtime/interpreter"
 	"github.com/onflow/cadence/runtime/parser2"
+	"github.com/onflow/cadence/runtime/parser2/lexer"
 	"github.com/onflow/cadence/runtime/sema"
 	"github.com/onflow/cadence/runtime/stdlib"
 )
 
+// REPL evaluates declarations and statements interactively.
+//
+// Accounts are simulated in memory: The function `getAuthAccount` provides access
+// to the storage, keys, and contracts of any account.
+// Contracts deployed to accounts can be imported using address imports,
+// and programs in files can be imported using string imports.
+//
 type REPL struct {
-	checker  *sema.Checker
-	inter    *interpreter.Interpreter
-	onError  func(err error, location common.Location, codes map[common.LocationID]string)
-	onResult func(interpreter.Value)
-	codes    map[common.LocationID]string
+	runtime            *interpreterRuntime
+	context            Context
+	storage            *runtimeStorage
+	checkerOptions     []sema.Option
+	interpreterOptions []interpreter.Option
+	checker            *sema.Checker
+	inter              *interpreter.Interpreter
+	onError            func(err error, location common.Location, codes map[common.LocationID]string)
+	onResult           func(interpreter.Value)
+	codes              map[common.LocationID]string
+}
+
+const replGetAuthAccountFunctionName = "getAuthAccount"
+
+const replGetAuthAccountFunctionDocString = `
+Returns the account for the given address, which provides full access to the storage, keys, and contracts.
+Only available in the REPL
+`
+
+var replGetAuthAccountFunctionType = &sema.FunctionType{
+	Parameters: []*sema.Parameter{
+		{
+			Label:      sema.ArgumentLabelNotRequired,
+			Identifier: "address",
+			TypeAnnotation: sema.NewTypeAnnotation(
+				&sema.AddressType{},
+			),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		sema.AuthAccountType,
+	),
 }
 
 func NewREPL(
@@ -46,112 +82,199 @@ func NewREPL(
 	checkerOptions []sema.Option,
 ) (*REPL, error) {
 
-	valueDeclarations := append(
-		stdlib.FlowBuiltInFunctions(stdlib.DefaultFlowBuiltinImpls()),
-		stdlib.BuiltinFunctions...,
-	)
-
-	checkers := map[common.LocationID]*sema.Checker{}
-	codes := map[common.LocationID]string{}
+	context := Context{
+		Interface: newREPLRuntimeInterface(),
+		Location:  common.REPLLocation{},
+	}
+	context.InitializeCodesAndPrograms()
 
-	var newChecker func(program *ast.Program, location common.Location) (*sema.Checker, error)
+	repl := &REPL{
+		runtime:  NewInterpreterRuntime().(*interpreterRuntime),
+		context:  context,
+		storage:  newRuntimeStorage(context.Interface),
+		onError:  onError,
+		onResult: onResult,
+		codes:    context.codes,
+	}
 
-	checkerOptions = append(
+	repl.checkerOptions = append(
 		[]sema.Option{
-			sema.WithPredeclaredValues(valueDeclarations.ToSemaValueDeclarations()),
-			sema.WithPredeclaredTypes(typeDeclarations),
 			sema.WithAccessCheckMode(sema.AccessCheckModeNotSpecifiedUnrestricted),
-			sema.WithImportHandler(
-				func(checker *sema.Checker, importedLocation common.Location, _ ast.Range) (sema.Import, error) {
-					stringLocation, ok := importedLocation.(common.StringLocation)
-
-					if !ok {
-						return nil, &sema.CheckerError{
-							Location: checker.Location,
-							Codes:    codes,
-							Errors: []error{
-								fmt.Errorf("cannot import `%s`. only files are supported", importedLocation),
-							},
-						}
-					}
-
-					importedChecker, ok := checkers[importedLocation.ID()]
-					if !ok {
-						importedProgram, _ := cmd.PrepareProgramFromFile(stringLocation, codes)
-						importedChecker, _ = newChecker(importedProgram, importedLocation)
-						checkers[importedLocation.ID()] = importedChecker
-					}
-
-					return sema.ElaborationImport{
-						Elaboration: importedChecker.Elaboration,
-					}, nil
-				},
-			),
 		},
 		checkerOptions...,
 	)
 
-	newChecker = func(program *ast.Program, location common.Location) (*sema.Checker, error) {
-		return sema.NewChecker(
-			program,
-			common.REPLLocation{},
-			checkerOptions...,
-		)
+	repl.interpreterOptions = []interpreter.Option{
+		interpreter.WithContractValueHandler(repl.contractValue),
+		interpreter.WithInjectedCompositeFieldsHandler(repl.injectedCompositeFields),
 	}
 
-	checker, err := newChecker(nil, common.REPLLocation{})
+	functions := append(
+		repl.runtime.standardLibraryFunctions(
+			context,
+			repl.storage,
+			repl.interpreterOptions,
+			repl.checkerOptions,
+		),
+		stdlib.NewStandardLibraryFunction(
+			replGetAuthAccountFunctionName,
+			replGetAuthAccountFunctionType,
+			replGetAuthAccountFunctionDocString,
+			func(invocation interpreter.Invocation) interpreter.Value {
+				address := invocation.Arguments[0].(interpreter.AddressValue)
+				return repl.authAccount(address)
+			},
+		),
+	)
+
+	values := repl.runtime.standardLibraryValues(context)
+
+	checker, err := sema.NewChecker(
+		nil,
+		context.Location,
+		repl.runtime.checkerOptions(
+			context,
+			functions,
+			values,
+			repl.checkerOptions,
+			importResolutionResults{},
+		)...,
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	values := valueDeclarations.ToInterpreterValueDeclarations()
-
-	var uuid uint64
-
-	inter, err := interpreter.NewInterpreter(
+	inter, err := repl.runtime.newInterpreter(
 		interpreter.ProgramFromChecker(checker),
-		checker.Location,
-		interpreter.WithPredeclaredValues(values),
-		interpreter.WithUUIDHandler(func() (uint64, error) {
-			defer func() { uuid++ }()
-			return uuid, nil
-		}),
+		context,
+		functions,
+		values,
+		repl.storage,
+		repl.interpreterOptions,
+		repl.checkerOptions,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	repl := &REPL{
-		checker:  checker,
-		inter:    inter,
-		onError:  onError,
-		onResult: onResult,
-		codes:    codes,
-	}
+	repl.checker = checker
+	repl.inter = inter
+
 	return repl, nil
 }
 
+func (r *REPL) authAccount(address interpreter.AddressValue) *interpreter.CompositeValue {
+	return r.runtime.newAuthAccountValue(
+		address,
+		r.context,
+		r.storage,
+		r.interpreterOptions,
+		r.checkerOptions,
+	)
+}
+
+// contractValue returns the value for the contract with the given type.
+// Contracts deployed to accounts are loaded from storage,
+// all other contracts, e.g. contracts imported from files, are instantiated
+//
+func (r *REPL) contractValue(
+	inter *interpreter.Interpreter,
+	compositeType *sema.CompositeType,
+	constructor interpreter.FunctionValue,
+	invocationRange ast.Range,
+) *interpreter.CompositeValue {
+
+	if _, ok := compositeType.Location.(common.AddressLocation); ok {
+		return r.runtime.loadContract(inter, compositeType, constructor, invocationRange, r.storage)
+	}
+
+	value, err := inter.InvokeFunctionValue(
+		constructor,
+		nil,
+		nil,
+		nil,
+		invocationRange,
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	return value.(*interpreter.CompositeValue)
+}
+
+// injectedCompositeFields returns the fields injected into the composite with the given type.
+// Only contracts deployed to accounts have injected fields
+//
+func (r *REPL) injectedCompositeFields(
+	inter *interpreter.Interpreter,
+	location common.Location,
+	qualifiedIdentifier string,
+	compositeKind common.CompositeKind,
+) *interpreter.StringValueOrderedMap {
+
+	if _, ok := location.(common.AddressLocation); !ok {
+		return nil
+	}
+
+	handler := r.runtime.injectedCompositeFieldsHandler(
+		r.context,
+		r.storage,
+		r.interpreterOptions,
+		r.checkerOptions,
+	)
+
+	return handler(inter, location, qualifiedIdentifier, compositeKind)
+}
+
+func (r *REPL) reportError(err error) {
+	if r.onError != nil {
+		r.onError(err, r.checker.Location, r.codes)
+	}
+}
+
 func (r *REPL) handleCheckerError() bool {
 	err := r.checker.CheckerError()
 	if err == nil {
 		return true
 	}
-	if r.onError != nil {
-		r.onError(err, r.checker.Location, r.codes)
-	}
+	r.reportError(err)
 	return false
 }
 
-func (r *REPL) execute(element ast.Element) {
-	result := element.Accept(r.inter)
+// evaluate evaluates the given element and writes all changes to storage
+//
+func (r *REPL) evaluate(element ast.Element) (result ast.Repr, err error) {
+
+	defer r.inter.RecoverErrors(func(internalErr error) {
+		err = internalErr
+	})
+
+	result = element.Accept(r.inter)
+
+	err = r.storage.writeCached(r.inter)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *REPL) execute(element ast.Element) bool {
+	result, err := r.evaluate(element)
+	if err != nil {
+		r.reportError(err)
+		return false
+	}
+
 	expStatementRes, ok := result.(interpreter.ExpressionStatementResult)
 	if !ok {
-		return
+		return true
 	}
 	if r.onResult == nil {
-		return
+		return true
 	}
 	r.onResult(expStatementRes.Value)
+	return true
 }
 
 func (r *REPL) check(element ast.Element, code string) bool {
@@ -160,27 +283,25 @@ func (r *REPL) check(element ast.Element, code string) bool {
 	return r.handleCheckerError()
 }
 
+// Accept parses, checks, and evaluates the given code.
+//
+// If the code is incomplete, e.g. because it has unclosed braces,
+// nothing is evaluated and false is returned,
+// so that the input can be continued
+//
 func (r *REPL) Accept(code string) (inputIsComplete bool) {
 
-	// TODO: detect if the input is complete
-	inputIsComplete = true
-
-	var err error
 	result, errs := parser2.ParseStatements(code)
 	if len(errs) > 0 {
-		err = parser2.Error{
-			Code:   code,
-			Errors: errs,
+		if !isInputComplete(code) {
+			return false
 		}
-	}
-
-	if !inputIsComplete {
-		return
-	}
 
-	if err != nil {
-		r.onError(err, r.checker.Location, r.codes)
-		return
+		r.reportError(parser2.Error{
+			Code:   code,
+			Errors: errs,
+		})
+		return true
 	}
 
 	r.checker.ResetErrors()
@@ -193,26 +314,269 @@ func (r *REPL) Accept(code string) (inputIsComplete bool) {
 			program := ast.NewProgram([]ast.Declaration{typedElement})
 
 			if !r.check(program, code) {
-				return
+				return true
 			}
 
-			r.execute(typedElement)
+			if !r.execute(typedElement) {
+				return true
+			}
 
 		case ast.Statement:
 			r.checker.Program = nil
 
 			if !r.check(typedElement, code) {
-				return
+				return true
 			}
 
-			r.execute(typedElement)
+			if !r.execute(typedElement) {
+				return true
+			}
 
 		default:
 			panic(errors.NewUnreachableError())
 		}
 	}
 
-	return
+	return true
+}
+
+// isInputComplete returns false if the given code is obviously incomplete,
+// i.e. if it has unclosed parentheses, brackets, braces, or block comments
+//
+func isInputComplete(code string) bool {
+	ctx, cancelLexer := goContext.WithCancel(goContext.Background())
+	defer cancelLexer()
+
+	depth := 0
+
+	for token := range lexer.Lex(ctx, code) {
+		switch token.Type {
+		case lexer.TokenEOF:
+			return depth <= 0
+
+		case lexer.TokenParenOpen,
+			lexer.TokenBracketOpen,
+			lexer.TokenBraceOpen,
+			lexer.TokenBlockCommentStart:
+
+			depth++
+
+		case lexer.TokenParenClose,
+			lexer.TokenBracketClose,
+			lexer.TokenBraceClose,
+			lexer.TokenBlockCommentEnd:
+
+			depth--
+		}
+	}
+
+	return depth <= 0
+}
+
+// DeployContract deploys the contract with the given code to the given account,
+// so that it can be imported
+//
+func (r *REPL) DeployContract(address common.Address, code []byte) bool {
+
+	program, err := parser2.ParseProgram(string(code))
+	if err != nil {
+		r.reportError(err)
+		return false
+	}
+
+	var names []string
+
+	for _, declaration := range program.CompositeDeclarations() {
+		if declaration.CompositeKind == common.CompositeKindContract {
+			names = append(names, declaration.Identifier.Identifier)
+		}
+	}
+
+	for _, declaration := range program.InterfaceDeclarations() {
+		if declaration.CompositeKind == common.CompositeKindContract {
+			names = append(names, declaration.Identifier.Identifier)
+		}
+	}
+
+	if len(names) != 1 {
+		r.reportError(fmt.Errorf("code must declare exactly one contract or contract interface"))
+		return false
+	}
+
+	addFunction := r.runtime.newAuthAccountContractsChangeFunction(
+		interpreter.AddressValue(address),
+		r.context,
+		r.storage,
+		r.interpreterOptions,
+		r.checkerOptions,
+		false,
+	)
+
+	_, err = r.inter.InvokeFunctionValue(
+		addFunction,
+		[]interpreter.Value{
+			interpreter.NewStringValue(names[0]),
+			interpreter.ByteSliceToByteArrayValue(code),
+		},
+		[]sema.Type{
+			sema.StringType,
+			&sema.VariableSizedType{Type: sema.UInt8Type},
+		},
+		[]sema.Type{
+			sema.StringType,
+			&sema.VariableSizedType{Type: sema.UInt8Type},
+		},
+		ast.Range{},
+	)
+	if err == nil {
+		err = r.storage.writeCached(r.inter)
+	}
+	if err != nil {
+		r.reportError(err)
+		return false
+	}
+
+	return true
+}
+
+// Inspect evaluates the given expression,
+// and returns a description of the structure of the resulting value
+//
+func (r *REPL) Inspect(code string) (description string, ok bool) {
+
+	expression, errs := parser2.ParseExpression(code)
+	if len(errs) > 0 {
+		r.reportError(parser2.Error{
+			Code:   code,
+			Errors: errs,
+		})
+		return "", false
+	}
+
+	statement := &ast.ExpressionStatement{
+		Expression: expression,
+	}
+
+	r.checker.ResetErrors()
+	r.checker.ResetHints()
+	r.checker.Program = nil
+
+	if !r.check(statement, code) {
+		return "", false
+	}
+
+	result, err := r.evaluate(statement)
+	if err != nil {
+		r.reportError(err)
+		return "", false
+	}
+
+	value := result.(interpreter.ExpressionStatementResult).Value
+
+	var builder strings.Builder
+	r.inspectValue(&builder, value, 0, map[interpreter.Value]struct{}{})
+	return builder.String(), true
+}
+
+const replInspectIndentation = "    "
+
+func (r *REPL) inspectValue(
+	builder *strings.Builder,
+	value interpreter.Value,
+	depth int,
+	seenReferences map[interpreter.Value]struct{},
+) {
+	indentation := strings.Repeat(replInspectIndentation, depth)
+
+	writeMember := func(name string, member interpreter.Value) {
+		builder.WriteString(indentation)
+		builder.WriteString(replInspectIndentation)
+		builder.WriteString(name)
+		builder.WriteString(": ")
+		r.inspectValue(builder, member, depth+1, seenReferences)
+		builder.WriteString("\n")
+	}
+
+	switch value := value.(type) {
+	case *interpreter.CompositeValue:
+		fmt.Fprintf(builder, "%s %s {\n", value.Kind().Keyword(), value.TypeID())
+		value.ForEachField(writeMember)
+		builder.WriteString(indentation)
+		builder.WriteString("}")
+
+	case *interpreter.ArrayValue:
+		writeStaticType(builder, value.StaticType())
+		builder.WriteString("[")
+		if value.Count() > 0 {
+			builder.WriteString("\n")
+			for i, element := range value.Elements() {
+				writeMember(fmt.Sprint(i), element)
+			}
+			builder.WriteString(indentation)
+		}
+		builder.WriteString("]")
+
+	case *interpreter.DictionaryValue:
+		writeStaticType(builder, value.StaticType())
+		builder.WriteString("{")
+		if value.Count() > 0 {
+			builder.WriteString("\n")
+			for _, key := range value.Keys().Elements() {
+				entry := value.Get(r.inter, nil, key).(*interpreter.SomeValue)
+				writeMember(key.String(), entry.Value)
+			}
+			builder.WriteString(indentation)
+		}
+		builder.WriteString("}")
+
+	case *interpreter.SomeValue:
+		r.inspectValue(builder, value.Value, depth, seenReferences)
+
+	case *interpreter.EphemeralReferenceValue:
+		r.inspectReferencedValue(builder, value, value.ReferencedValue(), depth, seenReferences)
+
+	case *interpreter.StorageReferenceValue:
+		r.inspectReferencedValue(builder, value, value.ReferencedValue(r.inter), depth, seenReferences)
+
+	default:
+		builder.WriteString(value.String())
+	}
+}
+
+// writeStaticType writes the given static type, if any.
+// Static types are only available for values with an explicit type, e.g. stored values
+//
+func writeStaticType(builder *strings.Builder, staticType interpreter.StaticType) {
+	if staticType == nil {
+		return
+	}
+	builder.WriteString(staticType.String())
+	builder.WriteString(" ")
+}
+
+func (r *REPL) inspectReferencedValue(
+	builder *strings.Builder,
+	reference interpreter.Value,
+	referencedValue *interpreter.Value,
+	depth int,
+	seenReferences map[interpreter.Value]struct{},
+) {
+	builder.WriteString("&")
+
+	if referencedValue == nil {
+		builder.WriteString("nil")
+		return
+	}
+
+	if _, ok := seenReferences[reference]; ok {
+		builder.WriteString("...")
+		return
+	}
+
+	seenReferences[reference] = struct{}{}
+	defer delete(seenReferences, reference)
+
+	r.inspectValue(builder, *referencedValue, depth, seenReferences)
 }
 
 type REPLSuggestion struct {
@@ -0,0 +1,227 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// replRuntimeInterface is the runtime interface used by the REPL.
+//
+// Account storage and contracts are simulated in memory,
+// and imports of string locations are resolved to files.
+//
+type replRuntimeInterface struct {
+	*emptyRuntimeInterface
+	storage   map[StorageKey][]byte
+	contracts map[common.AddressLocation][]byte
+	uuid      uint64
+}
+
+var _ Interface = &replRuntimeInterface{}
+
+func newREPLRuntimeInterface() *replRuntimeInterface {
+	return &replRuntimeInterface{
+		emptyRuntimeInterface: &emptyRuntimeInterface{
+			programs: map[common.LocationID]*interpreter.Program{},
+		},
+		storage:   map[StorageKey][]byte{},
+		contracts: map[common.AddressLocation][]byte{},
+	}
+}
+
+func (i *replRuntimeInterface) ResolveLocation(identifiers []Identifier, location Location) ([]ResolvedLocation, error) {
+	addressLocation, ok := location.(common.AddressLocation)
+
+	// Only address locations need to be resolved
+
+	if !ok {
+		return i.emptyRuntimeInterface.ResolveLocation(identifiers, location)
+	}
+
+	return ResolveAddressLocation(identifiers, addressLocation, i.GetAccountContractNames)
+}
+
+func (i *replRuntimeInterface) GetCode(location Location) ([]byte, error) {
+	stringLocation, ok := location.(common.StringLocation)
+	if !ok {
+		return nil, fmt.Errorf("cannot import `%s`. only files and deployed contracts are supported", location)
+	}
+
+	return ioutil.ReadFile(string(stringLocation))
+}
+
+func (i *replRuntimeInterface) GetValue(owner, key []byte) (value []byte, err error) {
+	return i.storage[StorageKey{
+		Address: common.BytesToAddress(owner),
+		Key:     string(key),
+	}], nil
+}
+
+func (i *replRuntimeInterface) SetValue(owner, key, value []byte) error {
+	storageKey := StorageKey{
+		Address: common.BytesToAddress(owner),
+		Key:     string(key),
+	}
+
+	if len(value) == 0 {
+		delete(i.storage, storageKey)
+	} else {
+		i.storage[storageKey] = value
+	}
+
+	return nil
+}
+
+func (i *replRuntimeInterface) GetValues(keys []StorageKey) (values [][]byte, err error) {
+	values = make([][]byte, len(keys))
+	for index, key := range keys {
+		values[index] = i.storage[key]
+	}
+	return values, nil
+}
+
+func (i *replRuntimeInterface) SetValues(writes []StorageWrite) error {
+	for _, write := range writes {
+		err := i.SetValue(write.Address[:], []byte(write.Key), write.Value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *replRuntimeInterface) ValueExists(owner, key []byte) (exists bool, err error) {
+	value, err := i.GetValue(owner, key)
+	return len(value) > 0, err
+}
+
+func (i *replRuntimeInterface) GetStorageKeys(owner, prefix []byte) (keys [][]byte, err error) {
+	address := common.BytesToAddress(owner)
+
+	var sortedKeys []string
+	for storageKey := range i.storage { //nolint:maprangecheck
+		if storageKey.Address == address && strings.HasPrefix(storageKey.Key, string(prefix)) {
+			sortedKeys = append(sortedKeys, storageKey.Key)
+		}
+	}
+
+	sort.Strings(sortedKeys)
+
+	keys = make([][]byte, len(sortedKeys))
+	for index, key := range sortedKeys {
+		keys[index] = []byte(key)
+	}
+
+	return keys, nil
+}
+
+func (i *replRuntimeInterface) GetStorageUsed(address Address) (value uint64, err error) {
+	for key, data := range i.storage { //nolint:maprangecheck
+		if key.Address == address {
+			value += uint64(len(key.Key) + len(data))
+		}
+	}
+	return value, nil
+}
+
+func (i *replRuntimeInterface) UpdateAccountContractCode(address Address, name string, code []byte) error {
+	location := common.AddressLocation{
+		Address: address,
+		Name:    name,
+	}
+
+	i.contracts[location] = code
+
+	// Programs of the previous code must not be used anymore
+
+	delete(i.programs, location.ID())
+
+	return nil
+}
+
+func (i *replRuntimeInterface) GetAccountContractCode(address Address, name string) (code []byte, err error) {
+	return i.contracts[common.AddressLocation{
+		Address: address,
+		Name:    name,
+	}], nil
+}
+
+func (i *replRuntimeInterface) RemoveAccountContractCode(address Address, name string) error {
+	location := common.AddressLocation{
+		Address: address,
+		Name:    name,
+	}
+
+	delete(i.contracts, location)
+	delete(i.programs, location.ID())
+
+	return nil
+}
+
+func (i *replRuntimeInterface) GetAccountContractNames(address Address) ([]string, error) {
+	var names []string
+	for location := range i.contracts { //nolint:maprangecheck
+		if location.Address == address {
+			names = append(names, location.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (i *replRuntimeInterface) ProgramLog(message string) error {
+	fmt.Println(message)
+	return nil
+}
+
+func (i *replRuntimeInterface) GenerateUUID() (uint64, error) {
+	uuid := i.uuid
+	i.uuid++
+	return uuid, nil
+}
+
+func (i *replRuntimeInterface) ValidatePublicKey(_ *PublicKey) (bool, error) {
+	return true, nil
+}
+
+// GetBlockAtHeight returns an empty block for height 0,
+// the only block of the REPL
+//
+func (i *replRuntimeInterface) GetBlockAtHeight(height uint64) (block Block, exists bool, err error) {
+	if height != 0 {
+		return Block{}, false, nil
+	}
+	return Block{}, true, nil
+}
+
+// ReadRandom reads cryptographically secure random bytes.
+// Unlike on chain, the random bytes of the REPL are not deterministic
+//
+func (i *replRuntimeInterface) ReadRandom(buffer []byte) error {
+	_, err := rand.Read(buffer)
+	return err
+}
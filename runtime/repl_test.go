@@ -0,0 +1,210 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+func newTestREPL(t *testing.T) (*REPL, *[]error, *[]string) {
+
+	var errs []error
+	var results []string
+
+	repl, err := NewREPL(
+		func(err error, _ common.Location, _ map[common.LocationID]string) {
+			errs = append(errs, err)
+		},
+		func(value interpreter.Value) {
+			if _, isVoid := value.(interpreter.VoidValue); isVoid {
+				return
+			}
+			results = append(results, value.String())
+		},
+		nil,
+	)
+	require.NoError(t, err)
+
+	return repl, &errs, &results
+}
+
+func TestREPL(t *testing.T) {
+
+	t.Parallel()
+
+	repl, errs, results := newTestREPL(t)
+
+	require.True(t, repl.Accept("let x = 1"))
+	require.True(t, repl.Accept("x + 2"))
+
+	require.Empty(t, *errs)
+	assert.Equal(t, []string{"3"}, *results)
+
+	require.True(t, repl.Accept("y"))
+	require.Len(t, *errs, 1)
+}
+
+func TestREPLContinuation(t *testing.T) {
+
+	t.Parallel()
+
+	repl, errs, results := newTestREPL(t)
+
+	code := "fun test(): Int {\n"
+	require.False(t, repl.Accept(code))
+
+	code += "    return 42\n"
+	require.False(t, repl.Accept(code))
+
+	code += "}\n"
+	require.True(t, repl.Accept(code))
+
+	require.True(t, repl.Accept("test()"))
+
+	require.Empty(t, *errs)
+	assert.Equal(t, []string{"42"}, *results)
+
+	// Invalid input which is complete is reported
+
+	require.True(t, repl.Accept("let = }"))
+	require.Len(t, *errs, 1)
+}
+
+func TestREPLStorage(t *testing.T) {
+
+	t.Parallel()
+
+	repl, errs, results := newTestREPL(t)
+
+	require.True(t, repl.Accept(`getAuthAccount(0x1).save("hello", to: /storage/message)`))
+	require.True(t, repl.Accept(`getAuthAccount(0x1).load<String>(from: /storage/message)`))
+	require.True(t, repl.Accept(`getAuthAccount(0x1).load<String>(from: /storage/message)`))
+
+	require.Empty(t, *errs)
+	assert.Equal(t, []string{`"hello"`, "nil"}, *results)
+}
+
+func TestREPLDeployContract(t *testing.T) {
+
+	t.Parallel()
+
+	repl, errs, results := newTestREPL(t)
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	require.True(t,
+		repl.DeployContract(
+			address,
+			[]byte(`
+              pub contract Test {
+                  pub fun answer(): Int {
+                      return 42
+                  }
+              }
+            `),
+		),
+	)
+
+	require.True(t, repl.Accept("import Test from 0x1"))
+	require.True(t, repl.Accept("Test.answer()"))
+	require.True(t, repl.Accept("getAccount(0x1).contracts.names"))
+
+	require.Empty(t, *errs)
+	assert.Equal(t, []string{"42", `["Test"]`}, *results)
+
+	// Deploying code without a contract is reported
+
+	require.False(t, repl.DeployContract(address, []byte("pub fun test() {}")))
+	require.Len(t, *errs, 1)
+}
+
+func TestREPLImportFile(t *testing.T) {
+
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "test.cdc")
+
+	err := ioutil.WriteFile(
+		path,
+		[]byte(`
+          pub contract Test {
+              pub fun answer(): Int {
+                  return 42
+              }
+          }
+        `),
+		0600,
+	)
+	require.NoError(t, err)
+
+	repl, errs, results := newTestREPL(t)
+
+	require.True(t, repl.Accept(fmt.Sprintf("import Test from %q", path)))
+	require.True(t, repl.Accept("Test.answer()"))
+
+	require.Empty(t, *errs)
+	assert.Equal(t, []string{"42"}, *results)
+}
+
+func TestREPLInspect(t *testing.T) {
+
+	t.Parallel()
+
+	repl, errs, _ := newTestREPL(t)
+
+	require.True(t, repl.Accept(`
+      pub struct S {
+          pub let values: {String: [Int]}
+
+          init() {
+              self.values = {"a": [1, 2], "b": []}
+          }
+      }
+    `))
+
+	description, ok := repl.Inspect("S()")
+	require.True(t, ok)
+	require.Empty(t, *errs)
+
+	assert.Equal(t,
+		`struct REPL.S {
+    values: {
+        "a": [
+            0: 1
+            1: 2
+        ]
+        "b": []
+    }
+}`,
+		description,
+	)
+
+	_, ok = repl.Inspect("unknown")
+	require.False(t, ok)
+	require.Len(t, *errs, 1)
+}
@@ -0,0 +1,128 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/stdlib"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestRuntimeRLP(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := NewInterpreterRuntime()
+
+	executeScript := func(code string) (cadence.Value, error) {
+		return runtime.ExecuteScript(
+			Script{
+				Source: []byte(code),
+			},
+			Context{
+				Interface: &testRuntimeInterface{},
+				Location:  utils.TestLocation,
+			},
+		)
+	}
+
+	t.Run("decodeString", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, err := executeScript(`
+          pub fun main(): [UInt8] {
+              return RLP.decodeString("83646f67".decodeHex())
+          }
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			cadence.NewArray([]cadence.Value{
+				cadence.NewUInt8('d'),
+				cadence.NewUInt8('o'),
+				cadence.NewUInt8('g'),
+			}),
+			result,
+		)
+	})
+
+	t.Run("decodeList", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, err := executeScript(`
+          pub fun main(): [[UInt8]] {
+              let items = RLP.decodeList("c88363617483646f67".decodeHex())
+              return [
+                  RLP.decodeString(items[0]),
+                  RLP.decodeString(items[1])
+              ]
+          }
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			cadence.NewArray([]cadence.Value{
+				cadence.NewArray([]cadence.Value{
+					cadence.NewUInt8('c'),
+					cadence.NewUInt8('a'),
+					cadence.NewUInt8('t'),
+				}),
+				cadence.NewArray([]cadence.Value{
+					cadence.NewUInt8('d'),
+					cadence.NewUInt8('o'),
+					cadence.NewUInt8('g'),
+				}),
+			}),
+			result,
+		)
+	})
+
+	t.Run("trailing data", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := executeScript(`
+          pub fun main(): [UInt8] {
+              return RLP.decodeString("83646f6701".decodeHex())
+          }
+        `)
+		require.Error(t, err)
+		require.ErrorAs(t, err, &stdlib.RLPDecodeStringError{})
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := executeScript(`
+          pub fun main(): [[UInt8]] {
+              return RLP.decodeList("83646f67".decodeHex())
+          }
+        `)
+		require.Error(t, err)
+		require.ErrorAs(t, err, &stdlib.RLPDecodeListError{})
+	})
+}
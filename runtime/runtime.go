@@ -31,7 +31,9 @@ import (
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/common"
 	runtimeErrors "github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/format"
 	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/optimizer"
 	"github.com/onflow/cadence/runtime/parser2"
 	"github.com/onflow/cadence/runtime/sema"
 	"github.com/onflow/cadence/runtime/stdlib"
@@ -58,6 +60,28 @@ type Runtime interface {
 	// or if the execution fails.
 	ExecuteTransaction(Script, Context) error
 
+	// SimulateTransaction executes the given transaction, but does not commit its effects.
+	// Instead, all writes through the interface of the given context are recorded and returned,
+	// i.e. the storage changes, emitted events, logs, and account changes.
+	//
+	// Accounts and UUIDs are not allocated through the interface, but by the simulation,
+	// so the created accounts have placeholder addresses, and UUIDs may differ from an actual execution.
+	//
+	// This function returns an error if the program has errors (e.g syntax errors, type errors),
+	// or if the execution fails.
+	SimulateTransaction(Script, Context) (*TransactionSimulation, error)
+
+	// EstimateComputation executes the given transaction like SimulateTransaction,
+	// i.e. its effects are not committed, and returns the computation and the memory it used,
+	// broken down by function, and the amount of data it read from and wrote to storage.
+	//
+	// The computation limit of the interface of the given context still applies.
+	//
+	// This function returns an error if the program has errors (e.g syntax errors, type errors),
+	// or if the execution fails, e.g. because the computation limit is exceeded.
+	// If the execution fails, the usage until the failure is returned together with the error.
+	EstimateComputation(Script, Context) (*ComputationEstimate, error)
+
 	// InvokeContractFunction invokes a contract function with the given arguments.
 	//
 	// This function returns an error if the execution fails.
@@ -71,11 +95,30 @@ type Runtime interface {
 		context Context,
 	) (cadence.Value, error)
 
+	// ValidateArguments decodes the arguments of the given script or transaction
+	// and validates them against the parameter types of its entry point,
+	// without executing the program. It returns the decoded arguments.
+	//
+	// This function returns an error if the program has errors (e.g syntax errors, type errors),
+	// or if the number of arguments does not match the number of parameters.
+	// If any arguments are invalid, an InvalidEntryPointArgumentsError is returned,
+	// which contains an error for each invalid argument.
+	//
+	// The authorizers of a transaction are not validated.
+	ValidateArguments(Script, Context) ([]cadence.Value, error)
+
 	// ParseAndCheckProgram parses and checks the given code without executing the program.
 	//
 	// This function returns an error if the program contains any syntax or semantic errors.
 	ParseAndCheckProgram(source []byte, context Context) (*interpreter.Program, error)
 
+	// GetContractInterface returns the public interface of the contract or contract interface
+	// deployed at the given location, i.e. its public fields, functions, events, and nested types.
+	//
+	// This function returns an error if no contract is deployed at the location,
+	// or if the contract has errors.
+	GetContractInterface(location common.AddressLocation, context Context) (*ContractInterface, error)
+
 	// SetCoverageReport activates reporting coverage in the given report.
 	// Passing nil disables coverage reporting (default).
 	//
@@ -85,6 +128,31 @@ type Runtime interface {
 	//
 	SetContractUpdateValidationEnabled(enabled bool)
 
+	// SetProgramCache configures the cache for parsed and checked programs
+	// of contracts, which is consulted when the interface does not provide a program.
+	// Passing nil disables the cache (default).
+	//
+	SetProgramCache(cache ProgramCache)
+
+	// SetLogPrettyOptions configures the pretty-printing of logged values,
+	// e.g. to limit the size of log messages of large values.
+	// Passing nil logs values in their single-line representation (default).
+	//
+	SetLogPrettyOptions(options *format.PrettyOptions)
+
+	// SetTypeInterner configures the interner for the types of checked programs
+	// and the types converted during execution.
+	// The number of retained types is bounded by the limit of the interner.
+	// Passing nil disables interning (default).
+	//
+	SetTypeInterner(interner *sema.TypeInterner)
+
+	// SetOptimizationsEnabled configures if checked programs are optimized before they are interpreted,
+	// e.g. by folding constant expressions and hoisting invariant computations out of loops.
+	// Optimizations are disabled by default.
+	//
+	SetOptimizationsEnabled(enabled bool)
+
 	// ReadStored reads the value stored at the given path
 	//
 	ReadStored(address common.Address, path cadence.Path, context Context) (cadence.Value, error)
@@ -92,6 +160,15 @@ type Runtime interface {
 	// ReadLinked dereferences the path and returns the value stored at the target
 	//
 	ReadLinked(address common.Address, path cadence.Path, context Context) (cadence.Value, error)
+
+	// CheckStorageHealth checks the values stored in the given accounts:
+	// It verifies that all stored values can be decoded, that they are owned by the account,
+	// and that all deferred values of dictionaries are stored, and referenced.
+	//
+	// If repair is true, undecodable values and orphaned deferred values are removed.
+	// Missing deferred values and owner mismatches are only reported.
+	//
+	CheckStorageHealth(accounts AccountIterator, repair bool, context Context) (*StorageHealthReport, error)
 }
 
 var typeDeclarations = append(
@@ -99,8 +176,6 @@ var typeDeclarations = append(
 	stdlib.BuiltinTypes...,
 ).ToTypeDeclarations()
 
-type ImportResolver = func(location common.Location) (program *ast.Program, e error)
-
 var validTopLevelDeclarationsInTransaction = []common.DeclarationKind{
 	common.DeclarationKindImport,
 	common.DeclarationKindFunction,
@@ -147,6 +222,10 @@ func reportMetric(
 type interpreterRuntime struct {
 	coverageReport                  *CoverageReport
 	contractUpdateValidationEnabled bool
+	programCache                    ProgramCache
+	logPrettyOptions                *format.PrettyOptions
+	typeInterner                    *sema.TypeInterner
+	optimizationsEnabled            bool
 }
 
 type Option func(Runtime)
@@ -160,6 +239,42 @@ func WithContractUpdateValidationEnabled(enabled bool) Option {
 	}
 }
 
+// WithProgramCache returns a runtime option
+// that configures the program cache.
+//
+func WithProgramCache(cache ProgramCache) Option {
+	return func(runtime Runtime) {
+		runtime.SetProgramCache(cache)
+	}
+}
+
+// WithLogPrettyOptions returns a runtime option
+// that configures the pretty-printing of logged values.
+//
+func WithLogPrettyOptions(options format.PrettyOptions) Option {
+	return func(runtime Runtime) {
+		runtime.SetLogPrettyOptions(&options)
+	}
+}
+
+// WithTypeInterner returns a runtime option
+// that configures the type interner.
+//
+func WithTypeInterner(interner *sema.TypeInterner) Option {
+	return func(runtime Runtime) {
+		runtime.SetTypeInterner(interner)
+	}
+}
+
+// WithOptimizationsEnabled returns a runtime option
+// that configures if checked programs are optimized.
+//
+func WithOptimizationsEnabled(enabled bool) Option {
+	return func(runtime Runtime) {
+		runtime.SetOptimizationsEnabled(enabled)
+	}
+}
+
 // NewInterpreterRuntime returns a interpreter-based version of the Flow runtime.
 func NewInterpreterRuntime(options ...Option) Runtime {
 	runtime := &interpreterRuntime{}
@@ -177,6 +292,22 @@ func (r *interpreterRuntime) SetContractUpdateValidationEnabled(enabled bool) {
 	r.contractUpdateValidationEnabled = enabled
 }
 
+func (r *interpreterRuntime) SetProgramCache(cache ProgramCache) {
+	r.programCache = cache
+}
+
+func (r *interpreterRuntime) SetLogPrettyOptions(options *format.PrettyOptions) {
+	r.logPrettyOptions = options
+}
+
+func (r *interpreterRuntime) SetTypeInterner(interner *sema.TypeInterner) {
+	r.typeInterner = interner
+}
+
+func (r *interpreterRuntime) SetOptimizationsEnabled(enabled bool) {
+	r.optimizationsEnabled = enabled
+}
+
 func (r *interpreterRuntime) ExecuteScript(script Script, context Context) (cadence.Value, error) {
 	context.InitializeCodesAndPrograms()
 
@@ -196,7 +327,7 @@ func (r *interpreterRuntime) ExecuteScript(script Script, context Context) (cade
 		script.Source,
 		context,
 		functions,
-		stdlib.BuiltinValues(),
+		r.standardLibraryValues(context),
 		checkerOptions,
 		true,
 		importResolutionResults{},
@@ -241,7 +372,7 @@ func (r *interpreterRuntime) ExecuteScript(script Script, context Context) (cade
 		context,
 		runtimeStorage,
 		functions,
-		stdlib.BuiltinValues(),
+		r.standardLibraryValues(context),
 		interpreterOptions,
 		checkerOptions,
 		interpret,
@@ -404,7 +535,7 @@ func (r *interpreterRuntime) InvokeContractFunction(
 		context,
 		runtimeStorage,
 		functions,
-		stdlib.BuiltinValues(),
+		r.standardLibraryValues(context),
 		interpreterOptions,
 		checkerOptions,
 		nil,
@@ -413,6 +544,10 @@ func (r *interpreterRuntime) InvokeContractFunction(
 		return nil, newError(err, context)
 	}
 
+	// The exit handler is only set for the top-level interpreter,
+	// not for the sub-interpreter of the contract
+	exitHandler := inter.ExitHandler
+
 	// ensure the contract is loaded
 	inter = inter.EnsureLoaded(contractLocation)
 
@@ -468,6 +603,14 @@ func (r *interpreterRuntime) InvokeContractFunction(
 		return nil, newError(err, context)
 	}
 
+	// Report the computation used, including the invocation
+	if exitHandler != nil {
+		err = exitHandler()
+		if err != nil {
+			return nil, newError(err, context)
+		}
+	}
+
 	return ExportValue(value, inter)
 }
 
@@ -495,6 +638,28 @@ func (r *interpreterRuntime) convertArgument(
 	return argument
 }
 
+func (r *interpreterRuntime) SimulateTransaction(script Script, context Context) (*TransactionSimulation, error) {
+	simulationInterface := newSimulationInterface(context.Interface)
+	context.Interface = simulationInterface
+
+	err := r.ExecuteTransaction(script, context)
+	if err != nil {
+		return nil, err
+	}
+
+	return simulationInterface.simulation()
+}
+
+func (r *interpreterRuntime) EstimateComputation(script Script, context Context) (*ComputationEstimate, error) {
+	estimator := newComputationEstimator(context.Interface)
+	context.Interface = estimator
+	context.computationEstimator = estimator
+
+	err := r.ExecuteTransaction(script, context)
+
+	return estimator.estimate(), err
+}
+
 func (r *interpreterRuntime) ExecuteTransaction(script Script, context Context) error {
 	context.InitializeCodesAndPrograms()
 
@@ -514,7 +679,7 @@ func (r *interpreterRuntime) ExecuteTransaction(script Script, context Context)
 		script.Source,
 		context,
 		functions,
-		stdlib.BuiltinValues(),
+		r.standardLibraryValues(context),
 		checkerOptions,
 		true,
 		importResolutionResults{},
@@ -583,7 +748,7 @@ func (r *interpreterRuntime) ExecuteTransaction(script Script, context Context)
 		context,
 		runtimeStorage,
 		functions,
-		stdlib.BuiltinValues(),
+		r.standardLibraryValues(context),
 		interpreterOptions,
 		checkerOptions,
 		r.transactionExecutionFunction(
@@ -679,69 +844,240 @@ func validateArgumentParams(
 
 	// Decode arguments against parameter types
 	for i, parameter := range parameters {
-		parameterType := parameter.TypeAnnotation.Type
-		argument := arguments[i]
+		_, arg, err := validateArgumentParam(inter, runtimeInterface, i, arguments[i], parameter)
+		if err != nil {
+			return nil, err
+		}
 
-		exportedParameterType := ExportType(parameterType, map[sema.TypeID]cadence.Type{})
-		var value cadence.Value
-		var err error
+		argumentValues[i] = arg
+	}
 
-		wrapPanic(func() {
-			value, err = runtimeInterface.DecodeArgument(
-				argument,
-				exportedParameterType,
-			)
-		})
+	return argumentValues, nil
+}
 
-		if err != nil {
-			return nil, &InvalidEntryPointArgumentError{
-				Index: i,
-				Err:   err,
-			}
+// validateArgumentParam decodes the given argument against the type of the given parameter,
+// and validates the decoded value.
+// It returns both the decoded value and the imported value.
+//
+func validateArgumentParam(
+	inter *interpreter.Interpreter,
+	runtimeInterface Interface,
+	index int,
+	argument []byte,
+	parameter *sema.Parameter,
+) (
+	cadence.Value,
+	interpreter.Value,
+	error,
+) {
+	parameterType := parameter.TypeAnnotation.Type
+
+	exportedParameterType := ExportType(parameterType, map[sema.TypeID]cadence.Type{})
+	var value cadence.Value
+	var err error
+
+	wrapPanic(func() {
+		value, err = runtimeInterface.DecodeArgument(
+			argument,
+			exportedParameterType,
+		)
+	})
+
+	if err != nil {
+		return nil, nil, &InvalidEntryPointArgumentError{
+			Index: index,
+			Err:   err,
 		}
+	}
 
-		arg, err := importValue(inter, value)
-		if err != nil {
-			return nil, &InvalidEntryPointArgumentError{
-				Index: i,
-				Err:   err,
-			}
+	arg, err := importValue(inter, value)
+	if err != nil {
+		return nil, nil, &InvalidEntryPointArgumentError{
+			Index: index,
+			Err:   err,
 		}
+	}
 
-		dynamicType := arg.DynamicType(inter, interpreter.SeenReferences{})
+	dynamicType := arg.DynamicType(inter, interpreter.SeenReferences{})
 
-		// Ensure the argument is of an importable type
-		if !dynamicType.IsImportable() {
-			return nil, &ArgumentNotImportableError{
-				Type: dynamicType,
-			}
+	// Ensure the argument is of an importable type
+	if !dynamicType.IsImportable() {
+		return nil, nil, &ArgumentNotImportableError{
+			Type: dynamicType,
 		}
+	}
 
-		// Check that decoded value is a subtype of static parameter type
-		if !interpreter.IsSubType(dynamicType, parameterType) {
-			return nil, &InvalidEntryPointArgumentError{
-				Index: i,
-				Err: &InvalidValueTypeError{
-					ExpectedType: parameterType,
-				},
+	// Check that decoded value is a subtype of static parameter type
+	if !interpreter.IsSubType(dynamicType, parameterType) {
+		return nil, nil, &InvalidEntryPointArgumentError{
+			Index: index,
+			Err: &InvalidValueTypeError{
+				ExpectedType: parameterType,
+			},
+		}
+	}
+
+	// Check whether the decoded value conforms to the type associated with the value
+	conformanceResults := interpreter.TypeConformanceResults{}
+	if !arg.ConformsToDynamicType(inter, dynamicType, conformanceResults) {
+		return nil, nil, &InvalidEntryPointArgumentError{
+			Index: index,
+			Err: &MalformedValueError{
+				ExpectedType: parameterType,
+			},
+		}
+	}
+
+	return value, arg, nil
+}
+
+func (r *interpreterRuntime) ValidateArguments(script Script, context Context) ([]cadence.Value, error) {
+	context.InitializeCodesAndPrograms()
+
+	runtimeStorage := newRuntimeStorage(context.Interface)
+
+	var interpreterOptions []interpreter.Option
+	var checkerOptions []sema.Option
+
+	functions := r.standardLibraryFunctions(
+		context,
+		runtimeStorage,
+		interpreterOptions,
+		checkerOptions,
+	)
+
+	program, err := r.parseAndCheckProgram(
+		script.Source,
+		context,
+		functions,
+		r.standardLibraryValues(context),
+		checkerOptions,
+		true,
+		importResolutionResults{},
+	)
+	if err != nil {
+		return nil, newError(err, context)
+	}
+
+	parameters, err := entryPointParameters(program)
+	if err != nil {
+		return nil, newError(err, context)
+	}
+
+	argumentCount := len(script.Arguments)
+	parameterCount := len(parameters)
+
+	if argumentCount != parameterCount {
+		err = InvalidEntryPointParameterCountError{
+			Expected: parameterCount,
+			Actual:   argumentCount,
+		}
+		return nil, newError(err, context)
+	}
+
+	// The program itself is not interpreted:
+	// The interpreter is only needed to import the arguments
+
+	inter, err := r.newInterpreter(
+		program,
+		context,
+		functions,
+		r.standardLibraryValues(context),
+		runtimeStorage,
+		interpreterOptions,
+		checkerOptions,
+	)
+	if err != nil {
+		return nil, newError(err, context)
+	}
+
+	values, err := validateArguments(inter, context.Interface, script.Arguments, parameters)
+	if err != nil {
+		return nil, newError(err, context)
+	}
+
+	return values, nil
+}
+
+// validateArguments decodes and validates all given arguments against the given parameters.
+// Unlike validateArgumentParams, it does not stop at the first invalid argument,
+// but reports the errors for all invalid arguments.
+//
+func validateArguments(
+	inter *interpreter.Interpreter,
+	runtimeInterface Interface,
+	arguments [][]byte,
+	parameters []*sema.Parameter,
+) (
+	values []cadence.Value,
+	err error,
+) {
+	// Recover internal panics and return them as an error.
+	// For example, the argument validation might attempt to
+	// load contract code for non-existing types
+
+	defer inter.RecoverErrors(func(internalErr error) {
+		values = nil
+		err = internalErr
+	})
+
+	values = make([]cadence.Value, len(arguments))
+
+	var argumentErrors []error
+
+	for i, parameter := range parameters {
+		value, _, err := validateArgumentParam(
+			inter,
+			runtimeInterface,
+			i,
+			arguments[i],
+			parameter,
+		)
+		if err != nil {
+			if _, ok := err.(*InvalidEntryPointArgumentError); !ok {
+				err = &InvalidEntryPointArgumentError{
+					Index: i,
+					Err:   err,
+				}
 			}
+			argumentErrors = append(argumentErrors, err)
+			continue
 		}
 
-		// Check whether the decoded value conforms to the type associated with the value
-		conformanceResults := interpreter.TypeConformanceResults{}
-		if !arg.ConformsToDynamicType(inter, dynamicType, conformanceResults) {
-			return nil, &InvalidEntryPointArgumentError{
-				Index: i,
-				Err: &MalformedValueError{
-					ExpectedType: parameterType,
-				},
+		values[i] = value
+	}
+
+	if len(argumentErrors) > 0 {
+		return nil, &InvalidEntryPointArgumentsError{
+			Errors: argumentErrors,
+		}
+	}
+
+	return values, nil
+}
+
+// entryPointParameters returns the parameters of the entry point of the given program,
+// i.e. the parameters of the transaction, or the parameters of the main function.
+//
+func entryPointParameters(program *interpreter.Program) ([]*sema.Parameter, error) {
+	if len(program.Program.TransactionDeclarations()) > 0 {
+		transactions := program.Elaboration.TransactionTypes
+		transactionCount := len(transactions)
+		if transactionCount != 1 {
+			return nil, InvalidTransactionCountError{
+				Count: transactionCount,
 			}
 		}
 
-		argumentValues[i] = arg
+		return transactions[0].Parameters, nil
 	}
 
-	return argumentValues, nil
+	functionEntryPointType, err := program.Elaboration.FunctionEntryPointType()
+	if err != nil {
+		return nil, err
+	}
+
+	return functionEntryPointType.Parameters, nil
 }
 
 // ParseAndCheckProgram parses the given code and checks it.
@@ -766,7 +1102,7 @@ func (r *interpreterRuntime) ParseAndCheckProgram(code []byte, context Context)
 		code,
 		context,
 		functions,
-		stdlib.BuiltinValues(),
+		r.standardLibraryValues(context),
 		checkerOptions,
 		true,
 		importResolutionResults{},
@@ -778,6 +1114,47 @@ func (r *interpreterRuntime) ParseAndCheckProgram(code []byte, context Context)
 	return program, nil
 }
 
+func (r *interpreterRuntime) GetContractInterface(
+	location common.AddressLocation,
+	context Context,
+) (*ContractInterface, error) {
+	context.InitializeCodesAndPrograms()
+
+	context = context.WithLocation(location)
+
+	runtimeStorage := newRuntimeStorage(context.Interface)
+
+	var interpreterOptions []interpreter.Option
+	var checkerOptions []sema.Option
+
+	functions := r.standardLibraryFunctions(
+		context,
+		runtimeStorage,
+		interpreterOptions,
+		checkerOptions,
+	)
+
+	program, err := r.getProgram(
+		context,
+		functions,
+		r.standardLibraryValues(context),
+		checkerOptions,
+		importResolutionResults{
+			location.ID(): true,
+		},
+	)
+	if err != nil {
+		return nil, newError(err, context)
+	}
+
+	contractInterface, err := newContractInterface(location, program)
+	if err != nil {
+		return nil, newError(err, context)
+	}
+
+	return contractInterface, nil
+}
+
 func (r *interpreterRuntime) parseAndCheckProgram(
 	code []byte,
 	context Context,
@@ -821,6 +1198,10 @@ func (r *interpreterRuntime) parseAndCheckProgram(
 		context.SetProgram(context.Location, parse)
 	}
 
+	if r.coverageReport != nil {
+		r.coverageReport.InspectProgram(context.Location, parse)
+	}
+
 	// Check
 
 	elaboration, err := r.check(parse, context, functions, values, checkerOptions, checkedImports)
@@ -828,6 +1209,12 @@ func (r *interpreterRuntime) parseAndCheckProgram(
 		return nil, wrapError(err)
 	}
 
+	// Optimize
+
+	if r.optimizationsEnabled {
+		optimizer.Optimize(parse, elaboration)
+	}
+
 	// Return
 
 	program = &interpreter.Program{
@@ -859,76 +1246,10 @@ func (r *interpreterRuntime) check(
 	err error,
 ) {
 
-	valueDeclarations := functions.ToSemaValueDeclarations()
-	valueDeclarations = append(valueDeclarations, values.ToSemaValueDeclarations()...)
-
-	for _, predeclaredValue := range startContext.PredeclaredValues {
-		valueDeclarations = append(valueDeclarations, predeclaredValue)
-	}
-
 	checker, err := sema.NewChecker(
 		program,
 		startContext.Location,
-		append(
-			[]sema.Option{
-				sema.WithPredeclaredValues(valueDeclarations),
-				sema.WithPredeclaredTypes(typeDeclarations),
-				sema.WithValidTopLevelDeclarationsHandler(validTopLevelDeclarations),
-				sema.WithLocationHandler(
-					func(identifiers []Identifier, location Location) (res []ResolvedLocation, err error) {
-						wrapPanic(func() {
-							res, err = startContext.Interface.ResolveLocation(identifiers, location)
-						})
-						return
-					},
-				),
-				sema.WithImportHandler(
-					func(checker *sema.Checker, importedLocation common.Location, importRange ast.Range) (sema.Import, error) {
-
-						var elaboration *sema.Elaboration
-						switch importedLocation {
-						case stdlib.CryptoChecker.Location:
-							elaboration = stdlib.CryptoChecker.Elaboration
-
-						default:
-							context := startContext.WithLocation(importedLocation)
-
-							// Check for cyclic imports
-							if checkedImports[importedLocation.ID()] {
-								return nil, &sema.CyclicImportsError{
-									Location: importedLocation,
-									Range:    importRange,
-								}
-							} else {
-								checkedImports[importedLocation.ID()] = true
-								defer delete(checkedImports, importedLocation.ID())
-							}
-
-							program, err := r.getProgram(context, functions, values, checkerOptions, checkedImports)
-							if err != nil {
-								return nil, err
-							}
-
-							elaboration = program.Elaboration
-						}
-
-						return sema.ElaborationImport{
-							Elaboration: elaboration,
-						}, nil
-					},
-				),
-				sema.WithCheckHandler(func(location common.Location, check func()) {
-					reportMetric(
-						check,
-						startContext.Interface,
-						func(metrics Metrics, duration time.Duration) {
-							metrics.ProgramChecked(location, duration)
-						},
-					)
-				}),
-			},
-			checkerOptions...,
-		)...,
+		r.checkerOptions(startContext, functions, values, checkerOptions, checkedImports)...,
 	)
 	if err != nil {
 		return nil, err
@@ -944,6 +1265,85 @@ func (r *interpreterRuntime) check(
 	return elaboration, nil
 }
 
+// checkerOptions returns the options for checkers of programs in the given context,
+// followed by the given additional options
+//
+func (r *interpreterRuntime) checkerOptions(
+	startContext Context,
+	functions stdlib.StandardLibraryFunctions,
+	values stdlib.StandardLibraryValues,
+	checkerOptions []sema.Option,
+	checkedImports importResolutionResults,
+) []sema.Option {
+
+	valueDeclarations := predeclaredValueDeclarations(startContext, functions, values)
+
+	return append(
+		[]sema.Option{
+			sema.WithPredeclaredValues(valueDeclarations),
+			sema.WithPredeclaredTypes(typeDeclarations),
+			sema.WithValidTopLevelDeclarationsHandler(validTopLevelDeclarations),
+			sema.WithTypeInterner(r.typeInterner),
+			sema.WithLocationHandler(
+				func(identifiers []Identifier, location Location) (res []ResolvedLocation, err error) {
+					wrapPanic(func() {
+						res, err = startContext.importResolver().ResolveLocation(identifiers, location)
+					})
+					return
+				},
+			),
+			sema.WithImportHandler(
+				func(checker *sema.Checker, importedLocation common.Location, importRange ast.Range) (sema.Import, error) {
+
+					var elaboration *sema.Elaboration
+					switch importedLocation {
+					case stdlib.CryptoChecker.Location:
+						elaboration = stdlib.CryptoChecker.Elaboration
+
+					case stdlib.TimeChecker.Location:
+						elaboration = stdlib.TimeChecker.Elaboration
+
+					default:
+						context := startContext.WithLocation(importedLocation)
+
+						// Check for cyclic imports
+						if checkedImports[importedLocation.ID()] {
+							return nil, &sema.CyclicImportsError{
+								Location: importedLocation,
+								Range:    importRange,
+							}
+						} else {
+							checkedImports[importedLocation.ID()] = true
+							defer delete(checkedImports, importedLocation.ID())
+						}
+
+						program, err := r.getProgram(context, functions, values, checkerOptions, checkedImports)
+						if err != nil {
+							return nil, err
+						}
+
+						elaboration = program.Elaboration
+					}
+
+					return sema.ElaborationImport{
+						Elaboration: elaboration,
+					}, nil
+				},
+			),
+			sema.WithCheckHandler(func(location common.Location, check func()) {
+				reportMetric(
+					check,
+					startContext.Interface,
+					func(metrics Metrics, duration time.Duration) {
+						metrics.ProgramChecked(location, duration)
+					},
+				)
+			}),
+		},
+		checkerOptions...,
+	)
+}
+
 func (r *interpreterRuntime) newInterpreter(
 	program *interpreter.Program,
 	context Context,
@@ -963,6 +1363,7 @@ func (r *interpreterRuntime) newInterpreter(
 
 	defaultOptions := []interpreter.Option{
 		interpreter.WithPredeclaredValues(preDeclaredValues),
+		interpreter.WithTypeInterner(r.typeInterner),
 		interpreter.WithOnEventEmittedHandler(
 			func(
 				inter *interpreter.Interpreter,
@@ -1046,6 +1447,48 @@ func (r *interpreterRuntime) newInterpreter(
 				)
 			},
 		),
+		interpreter.WithPublicKeyRecoveryHandler(
+			func(
+				signature *interpreter.ArrayValue,
+				signedData *interpreter.ArrayValue,
+				hashAlgorithm *interpreter.CompositeValue,
+			) interpreter.OptionalValue {
+				return recoverPublicKey(
+					signature,
+					signedData,
+					hashAlgorithm,
+					context.Interface,
+				)
+			},
+		),
+		interpreter.WithBLSVerifyPoPHandler(
+			func(
+				publicKey *interpreter.CompositeValue,
+				signature *interpreter.ArrayValue,
+			) interpreter.BoolValue {
+				return blsVerifyPoP(
+					publicKey,
+					signature,
+					context.Interface,
+				)
+			},
+		),
+		interpreter.WithBLSAggregateSignaturesHandler(
+			func(signatures *interpreter.ArrayValue) interpreter.OptionalValue {
+				return blsAggregateSignatures(
+					signatures,
+					context.Interface,
+				)
+			},
+		),
+		interpreter.WithBLSAggregatePublicKeysHandler(
+			func(publicKeys *interpreter.ArrayValue) interpreter.OptionalValue {
+				return blsAggregatePublicKeys(
+					publicKeys,
+					context.Interface,
+				)
+			},
+		),
 	}
 
 	defaultOptions = append(defaultOptions,
@@ -1053,7 +1496,7 @@ func (r *interpreterRuntime) newInterpreter(
 	)
 
 	defaultOptions = append(defaultOptions,
-		r.meteringInterpreterOptions(context.Interface)...,
+		r.meteringInterpreterOptions(context)...,
 	)
 
 	return interpreter.NewInterpreter(
@@ -1073,10 +1516,20 @@ func (r *interpreterRuntime) importLocationHandler(
 	checkerOptions []sema.Option,
 ) interpreter.ImportLocationHandlerFunc {
 
-	return func(inter *interpreter.Interpreter, location common.Location) interpreter.Import {
-		switch location {
-		case stdlib.CryptoChecker.Location:
-			program := interpreter.ProgramFromChecker(stdlib.CryptoChecker)
+	return func(inter *interpreter.Interpreter, location common.Location) interpreter.Import {
+		switch location {
+		case stdlib.CryptoChecker.Location:
+			program := interpreter.ProgramFromChecker(stdlib.CryptoChecker)
+			subInterpreter, err := inter.NewSubInterpreter(program, location)
+			if err != nil {
+				panic(err)
+			}
+			return interpreter.InterpreterImport{
+				Interpreter: subInterpreter,
+			}
+
+		case stdlib.TimeChecker.Location:
+			program := interpreter.ProgramFromChecker(stdlib.TimeChecker)
 			subInterpreter, err := inter.NewSubInterpreter(program, location)
 			if err != nil {
 				panic(err)
@@ -1104,9 +1557,31 @@ func (r *interpreterRuntime) importLocationHandler(
 	}
 }
 
+// predeclaredValueDeclarations returns the values which are predeclared for checkers of programs in the given context
+//
+func predeclaredValueDeclarations(
+	context Context,
+	functions stdlib.StandardLibraryFunctions,
+	values stdlib.StandardLibraryValues,
+) []sema.ValueDeclaration {
+
+	valueDeclarations := functions.ToSemaValueDeclarations()
+	valueDeclarations = append(valueDeclarations, values.ToSemaValueDeclarations()...)
+
+	for _, predeclaredValue := range context.PredeclaredValues {
+		valueDeclarations = append(valueDeclarations, predeclaredValue)
+	}
+
+	return valueDeclarations
+}
+
 // getProgram returns the existing program at the given location, if available.
 // If it is not available, it loads the code, and then parses and checks it.
 //
+// Programs are cached by their location, their code, and the predeclared values.
+// Programs which are checked with additional checker options are not cached,
+// as the options cannot be compared.
+//
 func (r *interpreterRuntime) getProgram(
 	context Context,
 	functions stdlib.StandardLibraryFunctions,
@@ -1125,6 +1600,22 @@ func (r *interpreterRuntime) getProgram(
 		return nil, err
 	}
 
+	// If the importing program is going to be stored in the program cache,
+	// the program must be recorded as one of its dependencies
+
+	importerDependencies := context.programDependencies
+
+	var cacheKey ProgramCacheKey
+	var dependencies []ProgramCacheKey
+
+	newCacheKey := func(code []byte) ProgramCacheKey {
+		environmentHash := programEnvironmentHash(
+			context.Location,
+			predeclaredValueDeclarations(context, functions, values),
+		)
+		return newProgramCacheKey(context.Location, code, environmentHash)
+	}
+
 	if program == nil {
 
 		var code []byte
@@ -1133,18 +1624,88 @@ func (r *interpreterRuntime) getProgram(
 			return nil, err
 		}
 
-		program, err = r.parseAndCheckProgram(
-			code,
-			context,
-			functions,
-			values,
-			checkerOptions,
-			true,
-			checkedImports,
-		)
+		_, cacheable := context.Location.(common.AddressLocation)
+		cacheable = cacheable &&
+			r.programCache != nil &&
+			len(checkerOptions) == 0
+
+		if cacheable || importerDependencies != nil {
+			cacheKey = newCacheKey(code)
+		}
+
+		var entry *ProgramCacheEntry
+		if cacheable {
+			entry, err = r.getCachedProgram(context, cacheKey)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if entry != nil {
+			program = entry.Program
+			dependencies = entry.Dependencies
+
+			context.SetCode(context.Location, string(code))
+
+			wrapPanic(func() {
+				err = context.Interface.SetProgram(context.Location, program)
+			})
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			var checkedDependencies programDependencies
+			if cacheable {
+				checkedDependencies = programDependencies{}
+				context.programDependencies = checkedDependencies
+			}
+
+			program, err = r.parseAndCheckProgram(
+				code,
+				context,
+				functions,
+				values,
+				checkerOptions,
+				true,
+				checkedImports,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			if cacheable {
+				dependencies = checkedDependencies.keys()
+
+				r.programCache.SetProgram(
+					cacheKey,
+					&ProgramCacheEntry{
+						Program:      program,
+						Dependencies: dependencies,
+					},
+				)
+			}
+		}
+
+	} else if importerDependencies != nil {
+
+		// The program was provided by the interface,
+		// so its code hash and dependencies are only known if it is also cached
+
+		var code []byte
+		code, err = r.getCode(context)
 		if err != nil {
 			return nil, err
 		}
+
+		cacheKey = newCacheKey(code)
+
+		if entry, ok := r.programCache.GetProgram(cacheKey); ok {
+			dependencies = entry.Dependencies
+		}
+	}
+
+	if importerDependencies != nil {
+		importerDependencies.add(cacheKey, dependencies)
 	}
 
 	context.SetProgram(context.Location, program.Program)
@@ -1152,6 +1713,29 @@ func (r *interpreterRuntime) getProgram(
 	return program, nil
 }
 
+// getCachedProgram returns the entry of the program cache for the given key,
+// if the code of all dependencies of the cached program is unchanged.
+//
+func (r *interpreterRuntime) getCachedProgram(context Context, key ProgramCacheKey) (*ProgramCacheEntry, error) {
+	entry, ok := r.programCache.GetProgram(key)
+	if !ok {
+		return nil, nil
+	}
+
+	for _, dependency := range entry.Dependencies {
+		code, err := r.getCode(context.WithLocation(dependency.Location))
+		if err != nil {
+			return nil, err
+		}
+
+		if sha3.Sum256(code) != dependency.CodeHash {
+			return nil, nil
+		}
+	}
+
+	return entry, nil
+}
+
 func (r *interpreterRuntime) injectedCompositeFieldsHandler(
 	context Context,
 	runtimeStorage *runtimeStorage,
@@ -1166,7 +1750,12 @@ func (r *interpreterRuntime) injectedCompositeFieldsHandler(
 	) *interpreter.StringValueOrderedMap {
 
 		switch location {
-		case stdlib.CryptoChecker.Location:
+		case stdlib.CryptoChecker.Location, stdlib.TimeChecker.Location:
+			return nil
+
+		case nil:
+			// Standard library values have no location
+			// and no injected fields
 			return nil
 
 		default:
@@ -1215,20 +1804,37 @@ func (r *interpreterRuntime) storageInterpreterOptions(runtimeStorage *runtimeSt
 			},
 		),
 		interpreter.WithStorageWriteHandler(
-			func(_ *interpreter.Interpreter, address common.Address, key string, value interpreter.OptionalValue) {
-				runtimeStorage.writeValue(address, key, value)
+			func(inter *interpreter.Interpreter, address common.Address, key string, value interpreter.OptionalValue) {
+				runtimeStorage.writeValue(inter, address, key, value)
+			},
+		),
+		interpreter.WithStoragePrefetchHandler(
+			func(_ *interpreter.Interpreter, address common.Address, keys []string) {
+				runtimeStorage.prefetch(address, keys)
+			},
+		),
+		interpreter.WithStoredPathsHandler(
+			func(_ *interpreter.Interpreter, address common.Address, domain common.PathDomain) []interpreter.PathValue {
+				return runtimeStorage.storedPaths(address, domain)
 			},
 		),
 	}
 }
 
-func (r *interpreterRuntime) meteringInterpreterOptions(runtimeInterface Interface) []interpreter.Option {
+func (r *interpreterRuntime) meteringInterpreterOptions(context Context) []interpreter.Option {
+	runtimeInterface := context.Interface
+	estimator := context.computationEstimator
+
 	var limit uint64
 	wrapPanic(func() {
 		limit = runtimeInterface.GetComputationLimit()
 	})
 	if limit == 0 {
-		return nil
+		// Without a limit, computation is only metered if it is estimated
+		if estimator == nil {
+			return nil
+		}
+		limit = math.MaxUint64
 	}
 
 	if limit == math.MaxUint64 {
@@ -1257,20 +1863,28 @@ func (r *interpreterRuntime) meteringInterpreterOptions(runtimeInterface Interfa
 		})
 	}
 
+	meter := func(inter *interpreter.Interpreter, line int) {
+		if estimator != nil {
+			estimator.recordComputation(inter, line)
+		}
+
+		checkLimit()
+	}
+
 	return []interpreter.Option{
 		interpreter.WithOnStatementHandler(
-			func(_ *interpreter.Interpreter, _ ast.Statement) {
-				checkLimit()
+			func(inter *interpreter.Interpreter, statement ast.Statement) {
+				meter(inter, statement.StartPosition().Line)
 			},
 		),
 		interpreter.WithOnLoopIterationHandler(
-			func(_ *interpreter.Interpreter, _ int) {
-				checkLimit()
+			func(inter *interpreter.Interpreter, line int) {
+				meter(inter, line)
 			},
 		),
 		interpreter.WithOnFunctionInvocationHandler(
-			func(_ *interpreter.Interpreter, _ int) {
-				checkLimit()
+			func(inter *interpreter.Interpreter, line int) {
+				meter(inter, line)
 			},
 		),
 		interpreter.WithExitHandler(
@@ -1300,6 +1914,13 @@ func (r *interpreterRuntime) standardLibraryFunctions(
 	)
 }
 
+func (r *interpreterRuntime) standardLibraryValues(context Context) stdlib.StandardLibraryValues {
+	return append(
+		stdlib.BuiltinValues(),
+		stdlib.NewRandomContract(r.newRandomSource(context.Interface)),
+	)
+}
+
 func (r *interpreterRuntime) getCode(context Context) (code []byte, err error) {
 	if addressLocation, ok := context.Location.(common.AddressLocation); ok {
 		wrapPanic(func() {
@@ -1310,7 +1931,7 @@ func (r *interpreterRuntime) getCode(context Context) (code []byte, err error) {
 		})
 	} else {
 		wrapPanic(func() {
-			code, err = context.Interface.GetCode(context.Location)
+			code, err = context.importResolver().GetCode(context.Location)
 		})
 	}
 	if err != nil {
@@ -1329,9 +1950,8 @@ func (r *interpreterRuntime) emitEvent(
 ) error {
 	fields := make([]exportableValue, len(eventType.ConstructorParameters))
 
-	eventFields := event.Fields()
 	for i, parameter := range eventType.ConstructorParameters {
-		value, _ := eventFields.Get(parameter.Identifier)
+		value := event.GetField(parameter.Identifier)
 		fields[i] = newExportableValue(value, inter)
 	}
 
@@ -1406,8 +2026,8 @@ func (r *interpreterRuntime) newCreateAccountFunction(
 			))
 		}
 
-		payerAddressValue, ok := payer.Fields().Get(sema.AuthAccountAddressField)
-		if !ok {
+		payerAddressValue := payer.GetField(sema.AuthAccountAddressField)
+		if payerAddressValue == nil {
 			panic("address is not set")
 		}
 
@@ -1415,6 +2035,8 @@ func (r *interpreterRuntime) newCreateAccountFunction(
 
 		var address Address
 		var err error
+		invocation.Interpreter.RecordIrreversibleEffect()
+
 		wrapPanic(func() {
 			address, err = context.Interface.CreateAccount(payerAddress)
 		})
@@ -1532,6 +2154,8 @@ func (r *interpreterRuntime) newAddPublicKeyFunction(
 				panic("addPublicKey requires the first argument to be a byte array")
 			}
 
+			invocation.Interpreter.RecordIrreversibleEffect()
+
 			wrapPanic(func() {
 				err = runtimeInterface.AddEncodedAccountKey(addressValue.ToAddress(), publicKey)
 			})
@@ -1563,6 +2187,8 @@ func (r *interpreterRuntime) newRemovePublicKeyFunction(
 
 			var publicKey []byte
 			var err error
+			invocation.Interpreter.RecordIrreversibleEffect()
+
 			wrapPanic(func() {
 				publicKey, err = runtimeInterface.RevokeEncodedAccountKey(addressValue.ToAddress(), index.ToInt())
 			})
@@ -1629,6 +2255,17 @@ func (r *interpreterRuntime) loadContract(
 		}
 		return contract
 
+	case stdlib.TimeChecker.Location:
+		contract, err := stdlib.NewTimeContract(
+			inter,
+			constructor,
+			invocationRange,
+		)
+		if err != nil {
+			panic(err)
+		}
+		return contract
+
 	default:
 
 		var storedValue interpreter.OptionalValue = interpreter.NilValue{}
@@ -1819,7 +2456,13 @@ func (r *interpreterRuntime) getPublicAccount(
 
 func (r *interpreterRuntime) newLogFunction(runtimeInterface Interface) interpreter.HostFunction {
 	return func(invocation interpreter.Invocation) interpreter.Value {
-		message := invocation.Arguments[0].String()
+		var message string
+		if r.logPrettyOptions != nil {
+			message = interpreter.PrettyString(invocation.Arguments[0], *r.logPrettyOptions)
+		} else {
+			message = invocation.Arguments[0].String()
+		}
+
 		var err error
 		wrapPanic(func() {
 			err = runtimeInterface.ProgramLog(message)
@@ -1908,6 +2551,18 @@ func (r *interpreterRuntime) newUnsafeRandomFunction(runtimeInterface Interface)
 	}
 }
 
+func (r *interpreterRuntime) newRandomSource(runtimeInterface Interface) stdlib.RandomSource {
+	return func(buffer []byte) {
+		var err error
+		wrapPanic(func() {
+			err = runtimeInterface.ReadRandom(buffer)
+		})
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
 func (r *interpreterRuntime) newAuthAccountContracts(
 	addressValue interpreter.AddressValue,
 	context Context,
@@ -2090,7 +2745,7 @@ func (r *interpreterRuntime) newAuthAccountContractsChangeFunction(
 				code,
 				context,
 				functions,
-				stdlib.BuiltinValues(),
+				r.standardLibraryValues(context),
 				checkerOptions,
 				storeProgram,
 				importResolutionResults{},
@@ -2196,6 +2851,8 @@ func (r *interpreterRuntime) newAuthAccountContractsChangeFunction(
 				handleContractUpdateError(err)
 			}
 
+			invocation.Interpreter.RecordIrreversibleEffect()
+
 			err = r.updateAccountContractCode(
 				program,
 				context,
@@ -2296,7 +2953,7 @@ func (r *interpreterRuntime) updateAccountContractCode(
 	if createContract {
 
 		functions := r.standardLibraryFunctions(context, runtimeStorage, interpreterOptions, checkerOptions)
-		values := stdlib.BuiltinValues()
+		values := r.standardLibraryValues(context)
 
 		contractValue, err = r.instantiateContract(
 			program,
@@ -2424,6 +3081,8 @@ func (r *interpreterRuntime) newAuthAccountContractsRemoveFunction(
 					}
 				}
 
+				invocation.Interpreter.RecordIrreversibleEffect()
+
 				wrapPanic(func() {
 					err = runtimeInterface.RemoveAccountContractCode(address, nameArgument)
 				})
@@ -2567,6 +3226,22 @@ func (r *interpreterRuntime) ReadLinked(address common.Address, path cadence.Pat
 	)
 }
 
+func (r *interpreterRuntime) CheckStorageHealth(
+	accounts AccountIterator,
+	repair bool,
+	context Context,
+) (
+	*StorageHealthReport,
+	error,
+) {
+	report, err := checkStorageHealth(context.Interface, accounts, repair)
+	if err != nil {
+		return nil, newError(err, context)
+	}
+
+	return report, nil
+}
+
 func NewBlockValue(block Block) interpreter.BlockValue {
 
 	// height
@@ -2612,6 +3287,8 @@ func (r *interpreterRuntime) newAccountKeysAddFunction(
 			weight := invocation.Arguments[2].(interpreter.UFix64Value).ToInt()
 
 			var accountKey *AccountKey
+			invocation.Interpreter.RecordIrreversibleEffect()
+
 			wrapPanic(func() {
 				accountKey, err = runtimeInterface.AddAccountKey(address, publicKey, hashAlgo, weight)
 			})
@@ -2684,6 +3361,8 @@ func (r *interpreterRuntime) newAccountKeysRevokeFunction(
 
 			var err error
 			var accountKey *AccountKey
+			invocation.Interpreter.RecordIrreversibleEffect()
+
 			wrapPanic(func() {
 				accountKey, err = runtimeInterface.RevokeAccountKey(address, index)
 			})
@@ -2745,8 +3424,6 @@ func (r *interpreterRuntime) newPublicAccountContracts(
 
 func NewPublicKeyFromValue(publicKey *interpreter.CompositeValue) (*PublicKey, error) {
 
-	fields := publicKey.Fields()
-
 	// publicKey field
 	publicKeyFieldGetter, ok := publicKey.ComputedFields.Get(sema.PublicKeyPublicKeyField)
 	if !ok {
@@ -2763,8 +3440,8 @@ func NewPublicKeyFromValue(publicKey *interpreter.CompositeValue) (*PublicKey, e
 	}
 
 	// sign algo field
-	signAlgoField, ok := fields.Get(sema.PublicKeySignAlgoField)
-	if !ok {
+	signAlgoField := publicKey.GetField(sema.PublicKeySignAlgoField)
+	if signAlgoField == nil {
 		return nil, errors.New("sign algorithm is not set")
 	}
 
@@ -2776,8 +3453,8 @@ func NewPublicKeyFromValue(publicKey *interpreter.CompositeValue) (*PublicKey, e
 		)
 	}
 
-	rawValue, ok := signAlgoValue.Fields().Get(sema.EnumRawValueFieldName)
-	if !ok {
+	rawValue := signAlgoValue.GetField(sema.EnumRawValueFieldName)
+	if rawValue == nil {
 		return nil, errors.New("cannot find sign algorithm raw value")
 	}
 
@@ -2791,7 +3468,8 @@ func NewPublicKeyFromValue(publicKey *interpreter.CompositeValue) (*PublicKey, e
 
 	// `valid` and `validated` fields
 	var valid, validated bool
-	validField, validated := fields.Get(sema.PublicKeyIsValidField)
+	validField := publicKey.GetField(sema.PublicKeyIsValidField)
+	validated = validField != nil
 	if validated {
 		valid = bool(validField.(interpreter.BoolValue))
 	}
@@ -2841,8 +3519,8 @@ func NewAccountKeyValue(
 func NewHashAlgorithmFromValue(value interpreter.Value) HashAlgorithm {
 	hashAlgoValue := value.(*interpreter.CompositeValue)
 
-	rawValue, ok := hashAlgoValue.Fields().Get(sema.EnumRawValueFieldName)
-	if !ok {
+	rawValue := hashAlgoValue.GetField(sema.EnumRawValueFieldName)
+	if rawValue == nil {
 		panic("cannot find hash algorithm raw value")
 	}
 
@@ -2945,3 +3623,157 @@ func hash(
 
 	return interpreter.ByteSliceToByteArrayValue(result)
 }
+
+func recoverPublicKey(
+	signatureValue *interpreter.ArrayValue,
+	signedDataValue *interpreter.ArrayValue,
+	hashAlgorithmValue *interpreter.CompositeValue,
+	runtimeInterface Interface,
+) interpreter.OptionalValue {
+
+	signature, err := interpreter.ByteArrayValueToByteSlice(signatureValue)
+	if err != nil {
+		panic(fmt.Errorf("failed to get signature. %w", err))
+	}
+
+	signedData, err := interpreter.ByteArrayValueToByteSlice(signedDataValue)
+	if err != nil {
+		panic(fmt.Errorf("failed to get signed data. %w", err))
+	}
+
+	hashAlgorithm := NewHashAlgorithmFromValue(hashAlgorithmValue)
+
+	const signatureAlgorithm = SignatureAlgorithmECDSA_secp256k1
+
+	var publicKey []byte
+	wrapPanic(func() {
+		publicKey, err = runtimeInterface.RecoverPublicKey(
+			signature,
+			signedData,
+			signatureAlgorithm,
+			hashAlgorithm,
+		)
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	if publicKey == nil {
+		return interpreter.NilValue{}
+	}
+
+	return interpreter.NewSomeValueOwningNonCopying(
+		NewPublicKeyValue(
+			&PublicKey{
+				PublicKey: publicKey,
+				SignAlgo:  signatureAlgorithm,
+			},
+			func(publicKeyValue *interpreter.CompositeValue) interpreter.BoolValue {
+				return validatePublicKey(publicKeyValue, runtimeInterface)
+			},
+		),
+	)
+}
+
+func blsVerifyPoP(
+	publicKeyValue *interpreter.CompositeValue,
+	signatureValue *interpreter.ArrayValue,
+	runtimeInterface Interface,
+) interpreter.BoolValue {
+
+	publicKey, err := NewPublicKeyFromValue(publicKeyValue)
+	if err != nil {
+		return false
+	}
+
+	signature, err := interpreter.ByteArrayValueToByteSlice(signatureValue)
+	if err != nil {
+		panic(fmt.Errorf("failed to get signature. %w", err))
+	}
+
+	var valid bool
+	wrapPanic(func() {
+		valid, err = runtimeInterface.BLSVerifyPOP(publicKey, signature)
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return interpreter.BoolValue(valid)
+}
+
+func blsAggregateSignatures(
+	signaturesValue *interpreter.ArrayValue,
+	runtimeInterface Interface,
+) interpreter.OptionalValue {
+
+	elements := signaturesValue.Elements()
+	signatures := make([][]byte, len(elements))
+
+	for i, element := range elements {
+		signature, err := interpreter.ByteArrayValueToByteSlice(element)
+		if err != nil {
+			panic(fmt.Errorf("failed to get signature. %w", err))
+		}
+
+		signatures[i] = signature
+	}
+
+	var aggregatedSignature []byte
+	var err error
+	wrapPanic(func() {
+		aggregatedSignature, err = runtimeInterface.BLSAggregateSignatures(signatures)
+	})
+
+	// If the crypto layer produces an error, we have invalid input, return nil
+	if err != nil {
+		return interpreter.NilValue{}
+	}
+
+	return interpreter.NewSomeValueOwningNonCopying(
+		interpreter.ByteSliceToByteArrayValue(aggregatedSignature),
+	)
+}
+
+func blsAggregatePublicKeys(
+	publicKeysValue *interpreter.ArrayValue,
+	runtimeInterface Interface,
+) interpreter.OptionalValue {
+
+	elements := publicKeysValue.Elements()
+	publicKeys := make([]*PublicKey, len(elements))
+
+	for i, element := range elements {
+		publicKeyValue, ok := element.(*interpreter.CompositeValue)
+		if !ok {
+			panic(runtimeErrors.NewUnreachableError())
+		}
+
+		publicKey, err := NewPublicKeyFromValue(publicKeyValue)
+		if err != nil {
+			panic(err)
+		}
+
+		publicKeys[i] = publicKey
+	}
+
+	var aggregatedPublicKey *PublicKey
+	var err error
+	wrapPanic(func() {
+		aggregatedPublicKey, err = runtimeInterface.BLSAggregatePublicKeys(publicKeys)
+	})
+
+	// If the crypto layer produces an error, we have invalid input, return nil
+	if err != nil || aggregatedPublicKey == nil {
+		return interpreter.NilValue{}
+	}
+
+	return interpreter.NewSomeValueOwningNonCopying(
+		NewPublicKeyValue(
+			aggregatedPublicKey,
+			func(publicKeyValue *interpreter.CompositeValue) interpreter.BoolValue {
+				return validatePublicKey(publicKeyValue, runtimeInterface)
+			},
+		),
+	)
+}
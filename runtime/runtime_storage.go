@@ -22,6 +22,7 @@ import (
 	"bytes"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/onflow/cadence/runtime/common"
@@ -36,6 +37,14 @@ type StorageKey struct {
 	Key     string
 }
 
+// StorageWrite is a write of a value for a key in storage.
+// A nil or empty value deletes the stored value.
+//
+type StorageWrite struct {
+	StorageKey
+	Value []byte
+}
+
 type Cache map[StorageKey]CacheEntry
 
 type CacheEntry struct {
@@ -51,6 +60,11 @@ type runtimeStorage struct {
 	runtimeInterface Interface
 	cache            Cache
 	contractUpdates  ContractUpdates
+	// prefetched is the data which was read through the runtime interface in a batch,
+	// but not yet decoded
+	prefetched map[StorageKey][]byte
+	// writes are the writes which are not yet performed through the runtime interface
+	writes []StorageWrite
 }
 
 func newRuntimeStorage(runtimeInterface Interface) *runtimeStorage {
@@ -58,6 +72,7 @@ func newRuntimeStorage(runtimeInterface Interface) *runtimeStorage {
 		runtimeInterface: runtimeInterface,
 		cache:            Cache{},
 		contractUpdates:  ContractUpdates{},
+		prefetched:       map[StorageKey][]byte{},
 	}
 }
 
@@ -85,6 +100,10 @@ func (s *runtimeStorage) valueExists(
 		return entry.Value != nil
 	}
 
+	if data, ok := s.prefetched[fullKey]; ok {
+		return len(data) > 0
+	}
+
 	// Cache miss: Ask interface
 
 	var exists bool
@@ -138,15 +157,9 @@ func (s *runtimeStorage) readValue(
 	// Cache miss: Load and deserialize the stored value (if any)
 	// through the runtime interface
 
-	var storedData []byte
-	var err error
-	wrapPanic(func() {
-		storedData, err = s.runtimeInterface.GetValue(address[:], []byte(key))
-	})
-	if err != nil {
-		panic(err)
-	}
+	storedData := s.readData(fullKey)
 
+	var err error
 	var version uint16
 	storedData, version = interpreter.StripMagic(storedData)
 
@@ -189,6 +202,176 @@ func (s *runtimeStorage) readValue(
 	return interpreter.NewSomeValueOwningNonCopying(storedValue)
 }
 
+// readData returns the stored data for the given key.
+//
+// The data is taken from the prefetched data, if available,
+// otherwise it is read through the runtime interface.
+//
+func (s *runtimeStorage) readData(fullKey StorageKey) []byte {
+	if data, ok := s.prefetched[fullKey]; ok {
+		return data
+	}
+
+	var data []byte
+	var err error
+	wrapPanic(func() {
+		data, err = s.runtimeInterface.GetValue(fullKey.Address[:], []byte(fullKey.Key))
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return data
+}
+
+// prefetch is the StoragePrefetchHandlerFunc for the interpreter.
+//
+// It reads the data for all given keys which are neither cached nor already prefetched
+// through the runtime interface in one batch,
+// so that reading the values later does not require a separate read for each key.
+//
+// The data is only decoded when the value is read.
+//
+func (s *runtimeStorage) prefetch(
+	address common.Address,
+	keys []string,
+) {
+	var batch []StorageKey
+
+	for _, key := range keys {
+		fullKey := StorageKey{
+			Address: address,
+			Key:     key,
+		}
+
+		if _, ok := s.cache[fullKey]; ok {
+			continue
+		}
+
+		if _, ok := s.prefetched[fullKey]; ok {
+			continue
+		}
+
+		batch = append(batch, fullKey)
+	}
+
+	if len(batch) == 0 {
+		return
+	}
+
+	var values [][]byte
+	var err error
+	wrapPanic(func() {
+		values, err = s.runtimeInterface.GetValues(batch)
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	if len(values) != len(batch) {
+		panic(fmt.Errorf(
+			"invalid number of values read from storage: expected %d, got %d",
+			len(batch),
+			len(values),
+		))
+	}
+
+	for i, fullKey := range batch {
+		s.prefetched[fullKey] = values[i]
+	}
+}
+
+// storedPaths is the StoredPathsHandlerFunc for the interpreter.
+//
+// It returns the paths of all values stored in the given domain of the account, sorted by identifier.
+//
+// The keys in storage are requested through the runtime interface,
+// and are merged with the cache, which contains values that were written,
+// but not yet written back through the runtime interface.
+//
+// Keys of deferred values, e.g. nested values of dictionaries, are not paths and are ignored.
+//
+func (s *runtimeStorage) storedPaths(
+	address common.Address,
+	domain common.PathDomain,
+) []interpreter.PathValue {
+
+	prefix := domain.Identifier() + "\x1F"
+
+	var keys [][]byte
+	var err error
+	wrapPanic(func() {
+		keys, err = s.runtimeInterface.GetStorageKeys(address[:], []byte(prefix))
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	identifiers := map[string]struct{}{}
+
+	pathIdentifier := func(key string) (string, bool) {
+		if !strings.HasPrefix(key, prefix) {
+			return "", false
+		}
+		identifier := key[len(prefix):]
+		if strings.Contains(identifier, "\x1F") {
+			return "", false
+		}
+		return identifier, true
+	}
+
+	for _, key := range keys {
+		if identifier, ok := pathIdentifier(string(key)); ok {
+			identifiers[identifier] = struct{}{}
+		}
+	}
+
+	// Merge the cached values in a deterministic order
+
+	var cachedKeys []string
+	for fullKey := range s.cache { //nolint:maprangecheck
+		if fullKey.Address != address {
+			continue
+		}
+
+		if _, ok := pathIdentifier(fullKey.Key); ok {
+			cachedKeys = append(cachedKeys, fullKey.Key)
+		}
+	}
+	sort.Strings(cachedKeys)
+
+	for _, key := range cachedKeys {
+		identifier, _ := pathIdentifier(key)
+
+		entry := s.cache[StorageKey{
+			Address: address,
+			Key:     key,
+		}]
+
+		if entry.Value == nil {
+			delete(identifiers, identifier)
+		} else {
+			identifiers[identifier] = struct{}{}
+		}
+	}
+
+	sortedIdentifiers := make([]string, 0, len(identifiers))
+	for identifier := range identifiers { //nolint:maprangecheck
+		sortedIdentifiers = append(sortedIdentifiers, identifier)
+	}
+	sort.Strings(sortedIdentifiers)
+
+	paths := make([]interpreter.PathValue, len(sortedIdentifiers))
+	for i, identifier := range sortedIdentifiers {
+		paths[i] = interpreter.PathValue{
+			Domain:     domain,
+			Identifier: identifier,
+		}
+	}
+
+	return paths
+}
+
 // writeValue is the StorageWriteHandlerFunc for the interpreter.
 //
 // It only places the written value in the cache.
@@ -197,6 +380,7 @@ func (s *runtimeStorage) readValue(
 // (The Cache is finally written back through the runtime interface in `writeCached`.)
 //
 func (s *runtimeStorage) writeValue(
+	inter *interpreter.Interpreter,
 	address common.Address,
 	key string,
 	value interpreter.OptionalValue,
@@ -206,6 +390,24 @@ func (s *runtimeStorage) writeValue(
 		Key:     key,
 	}
 
+	// If the write occurs in a try expression,
+	// record how to restore the cache entry in case the evaluation fails
+
+	previousEntry, hadPreviousEntry := s.cache[fullKey]
+
+	inter.RecordUndo(func() {
+		if !hadPreviousEntry {
+			delete(s.cache, fullKey)
+			return
+		}
+
+		if previousEntry.Value != nil {
+			previousEntry.Value.SetOwner(&fullKey.Address)
+		}
+
+		s.cache[fullKey] = previousEntry
+	})
+
 	// Only write the value to the cache.
 	// The Cache is finally written back through the runtime interface in `writeCached`
 
@@ -338,7 +540,7 @@ func (s *runtimeStorage) writeCached(inter *interpreter.Interpreter) error {
 		batch = newBatch
 	}
 
-	return nil
+	return s.flushWrites()
 }
 
 type encodedResult struct {
@@ -455,18 +657,40 @@ func (s *runtimeStorage) processEncodedItem(item writeItem, encoded *encodedResu
 		newData = interpreter.PrependMagic(encoded.newData, interpreter.CurrentEncodingVersion)
 	}
 
+	s.bufferWrite(item.storageKey, newData)
+
+	return newItems, nil
+}
+
+// bufferWrite records a write of the given data for the given key.
+// The write is performed through the runtime interface in `flushWrites`.
+//
+func (s *runtimeStorage) bufferWrite(fullKey StorageKey, data []byte) {
+	s.writes = append(s.writes, StorageWrite{
+		StorageKey: fullKey,
+		Value:      data,
+	})
+
+	// The prefetched data for the key is outdated
+
+	delete(s.prefetched, fullKey)
+}
+
+// flushWrites performs all buffered writes through the runtime interface in one batch.
+//
+func (s *runtimeStorage) flushWrites() error {
+	if len(s.writes) == 0 {
+		return nil
+	}
+
+	writes := s.writes
+	s.writes = nil
+
 	var err error
 	wrapPanic(func() {
-		err = s.runtimeInterface.SetValue(
-			item.storageKey.Address[:],
-			[]byte(item.storageKey.Key),
-			newData,
-		)
+		err = s.runtimeInterface.SetValues(writes)
 	})
-	if err != nil {
-		return nil, err
-	}
-	return newItems, nil
+	return err
 }
 
 func (s *runtimeStorage) encodeValue(
@@ -498,19 +722,29 @@ func (s *runtimeStorage) move(
 	oldOwner common.Address, oldKey string,
 	newOwner common.Address, newKey string,
 ) {
-	data, err := s.runtimeInterface.GetValue(oldOwner[:], []byte(oldKey))
+	// The moved data might have been written in the current batch,
+	// so perform the buffered writes before reading it
+
+	err := s.flushWrites()
 	if err != nil {
 		panic(err)
 	}
 
-	err = s.runtimeInterface.SetValue(oldOwner[:], []byte(oldKey), nil)
-	if err != nil {
-		panic(err)
+	oldStorageKey := StorageKey{
+		Address: oldOwner,
+		Key:     oldKey,
 	}
 
+	data := s.readData(oldStorageKey)
+
+	s.bufferWrite(oldStorageKey, nil)
+
 	// NOTE: not prefix with magic, as data is moved, so might already have it
-	err = s.runtimeInterface.SetValue(newOwner[:], []byte(newKey), data)
-	if err != nil {
-		panic(err)
-	}
+	s.bufferWrite(
+		StorageKey{
+			Address: newOwner,
+			Key:     newKey,
+		},
+		data,
+	)
 }
@@ -37,6 +37,7 @@ import (
 	jsoncdc "github.com/onflow/cadence/encoding/json"
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/format"
 	"github.com/onflow/cadence/runtime/interpreter"
 	"github.com/onflow/cadence/runtime/sema"
 	"github.com/onflow/cadence/runtime/stdlib"
@@ -49,6 +50,9 @@ type testRuntimeInterfaceStorage struct {
 	valueExists  func(owner, key []byte) (exists bool, err error)
 	getValue     func(owner, key []byte) (value []byte, err error)
 	setValue     func(owner, key, value []byte) (err error)
+	getKeys      func(owner, prefix []byte) (keys [][]byte, err error)
+	getValues    func(keys []StorageKey) (values [][]byte, err error)
+	setValues    func(writes []StorageWrite) (err error)
 }
 
 func newTestStorage(
@@ -82,6 +86,17 @@ func newTestStorage(
 			}
 			return nil
 		},
+		getKeys: func(owner, prefix []byte) (keys [][]byte, err error) {
+			ownerPrefix := storageKey(string(owner), string(prefix))
+			for storedKey, value := range storedValues {
+				if len(value) == 0 || !strings.HasPrefix(storedKey, ownerPrefix) {
+					continue
+				}
+				key := storedKey[len(owner)+1:]
+				keys = append(keys, []byte(key))
+			}
+			return keys, nil
+		},
 	}
 
 	return storage
@@ -107,6 +122,7 @@ type testRuntimeInterface struct {
 	emitEvent                 func(cadence.Event) error
 	generateUUID              func() (uint64, error)
 	computationLimit          uint64
+	setComputationUsed        func(used uint64) error
 	decodeArgument            func(b []byte, t cadence.Type) (cadence.Value, error)
 	programParsed             func(location common.Location, duration time.Duration)
 	programChecked            func(location common.Location, duration time.Duration)
@@ -114,6 +130,7 @@ type testRuntimeInterface struct {
 	valueEncoded              func(duration time.Duration)
 	valueDecoded              func(duration time.Duration)
 	unsafeRandom              func() (uint64, error)
+	readRandom                func(buffer []byte) error
 	verifySignature           func(
 		signature []byte,
 		tag string,
@@ -132,6 +149,15 @@ type testRuntimeInterface struct {
 	implementationDebugLog     func(message string) error
 	validatePublicKey          func(publicKey *PublicKey) (bool, error)
 	getAccountContractNames    func(address Address) ([]string, error)
+	recoverPublicKey           func(
+		signature []byte,
+		signedData []byte,
+		signatureAlgorithm SignatureAlgorithm,
+		hashAlgorithm HashAlgorithm,
+	) ([]byte, error)
+	blsVerifyPOP           func(publicKey *PublicKey, signature []byte) (bool, error)
+	blsAggregateSignatures func(signatures [][]byte) ([]byte, error)
+	blsAggregatePublicKeys func(publicKeys []*PublicKey) (*PublicKey, error)
 }
 
 // testRuntimeInterface should implement Interface
@@ -183,6 +209,13 @@ func (i *testRuntimeInterface) ValueExists(owner, key []byte) (exists bool, err
 	return i.storage.valueExists(owner, key)
 }
 
+func (i *testRuntimeInterface) GetStorageKeys(owner, prefix []byte) (keys [][]byte, err error) {
+	if i.storage.getKeys == nil {
+		return nil, nil
+	}
+	return i.storage.getKeys(owner, prefix)
+}
+
 func (i *testRuntimeInterface) GetValue(owner, key []byte) (value []byte, err error) {
 	return i.storage.getValue(owner, key)
 }
@@ -191,6 +224,35 @@ func (i *testRuntimeInterface) SetValue(owner, key, value []byte) (err error) {
 	return i.storage.setValue(owner, key, value)
 }
 
+func (i *testRuntimeInterface) GetValues(keys []StorageKey) (values [][]byte, err error) {
+	if i.storage.getValues != nil {
+		return i.storage.getValues(keys)
+	}
+
+	values = make([][]byte, len(keys))
+	for index, key := range keys {
+		values[index], err = i.storage.getValue(key.Address[:], []byte(key.Key))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+func (i *testRuntimeInterface) SetValues(writes []StorageWrite) (err error) {
+	if i.storage.setValues != nil {
+		return i.storage.setValues(writes)
+	}
+
+	for _, write := range writes {
+		err = i.storage.setValue(write.Address[:], []byte(write.Key), write.Value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (i *testRuntimeInterface) CreateAccount(payer Address) (address Address, err error) {
 	return i.createAccount(payer)
 }
@@ -254,8 +316,11 @@ func (i *testRuntimeInterface) GetComputationLimit() uint64 {
 	return i.computationLimit
 }
 
-func (i *testRuntimeInterface) SetComputationUsed(uint64) error {
-	return nil
+func (i *testRuntimeInterface) SetComputationUsed(used uint64) error {
+	if i.setComputationUsed == nil {
+		return nil
+	}
+	return i.setComputationUsed(used)
 }
 
 func (i *testRuntimeInterface) DecodeArgument(b []byte, t cadence.Type) (cadence.Value, error) {
@@ -329,6 +394,13 @@ func (i *testRuntimeInterface) UnsafeRandom() (uint64, error) {
 	return i.unsafeRandom()
 }
 
+func (i *testRuntimeInterface) ReadRandom(buffer []byte) error {
+	if i.readRandom == nil {
+		return nil
+	}
+	return i.readRandom(buffer)
+}
+
 func (i *testRuntimeInterface) VerifySignature(
 	signature []byte,
 	tag string,
@@ -400,6 +472,44 @@ func (i *testRuntimeInterface) GetAccountContractNames(address Address) ([]strin
 	return i.getAccountContractNames(address)
 }
 
+func (i *testRuntimeInterface) RecoverPublicKey(
+	signature []byte,
+	signedData []byte,
+	signatureAlgorithm SignatureAlgorithm,
+	hashAlgorithm HashAlgorithm,
+) ([]byte, error) {
+	if i.recoverPublicKey == nil {
+		return nil, nil
+	}
+	return i.recoverPublicKey(
+		signature,
+		signedData,
+		signatureAlgorithm,
+		hashAlgorithm,
+	)
+}
+
+func (i *testRuntimeInterface) BLSVerifyPOP(publicKey *PublicKey, signature []byte) (bool, error) {
+	if i.blsVerifyPOP == nil {
+		return false, nil
+	}
+	return i.blsVerifyPOP(publicKey, signature)
+}
+
+func (i *testRuntimeInterface) BLSAggregateSignatures(signatures [][]byte) ([]byte, error) {
+	if i.blsAggregateSignatures == nil {
+		return []byte{}, nil
+	}
+	return i.blsAggregateSignatures(signatures)
+}
+
+func (i *testRuntimeInterface) BLSAggregatePublicKeys(publicKeys []*PublicKey) (*PublicKey, error) {
+	if i.blsAggregatePublicKeys == nil {
+		return nil, nil
+	}
+	return i.blsAggregatePublicKeys(publicKeys)
+}
+
 func TestRuntimeImport(t *testing.T) {
 
 	t.Parallel()
@@ -6543,3 +6653,172 @@ func TestRuntimeGetCapability(t *testing.T) {
 		)
 	})
 }
+
+func TestRuntimeLogPrettyOptions(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := NewInterpreterRuntime(
+		WithLogPrettyOptions(format.PrettyOptions{
+			MaxElements: 2,
+			Redact:      format.RedactFields("secret"),
+		}),
+	)
+
+	script := []byte(`
+      pub struct S {
+          pub let values: [Int]
+          pub let secret: String
+
+          init() {
+              self.values = [1, 2, 3]
+              self.secret = "password"
+          }
+      }
+
+      pub fun main() {
+          log(S())
+      }
+    `)
+
+	var loggedMessages []string
+
+	runtimeInterface := &testRuntimeInterface{
+		log: func(message string) {
+			loggedMessages = append(loggedMessages, message)
+		},
+	}
+
+	_, err := runtime.ExecuteScript(
+		Script{
+			Source: script,
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  common.ScriptLocation{0x1},
+		},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t,
+		[]string{
+			`s.01.S(
+    values: [
+        1,
+        2,
+        ... (1 more)
+    ],
+    secret: <redacted>
+)`,
+		},
+		loggedMessages,
+	)
+}
+
+func TestRuntimeTypeInterner(t *testing.T) {
+
+	t.Parallel()
+
+	interner := sema.NewTypeInterner()
+
+	runtime := NewInterpreterRuntime(
+		WithTypeInterner(interner),
+	)
+
+	script := []byte(`
+      pub fun main(): Type {
+          let value: AnyStruct = {"a": [1]}
+          let array = value as! {String: [Int]}
+          return Type<{String: [Int]}>()
+      }
+    `)
+
+	runtimeInterface := &testRuntimeInterface{}
+
+	for i := 0; i < 2; i++ {
+		result, err := runtime.ExecuteScript(
+			Script{
+				Source: script,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.ScriptLocation{0x1},
+			},
+		)
+		require.NoError(t, err)
+
+		require.Equal(t,
+			cadence.TypeValue{StaticType: "{String:[Int]}"},
+			result,
+		)
+	}
+
+	// The types of both executions are shared
+
+	require.Equal(t, 2, interner.Len())
+}
+
+func TestRuntimeOptimizations(t *testing.T) {
+
+	t.Parallel()
+
+	script := []byte(`
+      pub fun main(): Int {
+          let a = 2
+          var i = 0
+          var sum = 0
+          while i < 10 {
+              sum = sum + a * (3 + 4)
+              i = i + 1
+          }
+          return sum
+      }
+    `)
+
+	for _, enabled := range []bool{false, true} {
+
+		enabled := enabled
+
+		t.Run(fmt.Sprintf("enabled=%t", enabled), func(t *testing.T) {
+
+			t.Parallel()
+
+			runtime := NewInterpreterRuntime(
+				WithOptimizationsEnabled(enabled),
+			)
+
+			var program *interpreter.Program
+
+			runtimeInterface := &testRuntimeInterface{
+				setProgram: func(_ Location, p *interpreter.Program) error {
+					program = p
+					return nil
+				},
+			}
+
+			result, err := runtime.ExecuteScript(
+				Script{
+					Source: script,
+				},
+				Context{
+					Interface: runtimeInterface,
+					Location:  common.ScriptLocation{0x1},
+				},
+			)
+			require.NoError(t, err)
+
+			require.Equal(t, cadence.NewInt(140), result)
+
+			// The invariant computation is only hoisted out of the loop if optimizations are enabled
+
+			require.NotNil(t, program)
+			statements := program.Program.FunctionDeclarations()[0].FunctionBlock.Block.Statements
+
+			if enabled {
+				require.Len(t, statements, 6)
+			} else {
+				require.Len(t, statements, 5)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+func TestRuntimeSavepoint(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("storage writes are rolled back", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := NewInterpreterRuntime()
+
+		script := []byte(`
+          pub fun save(_ signer: AuthAccount, amount: Int, to path: StoragePath): Bool {
+              return savepoint(fun (): Bool {
+                  signer.save(amount, to: path)
+                  return amount > 0
+              })
+          }
+
+          transaction {
+              prepare(signer: AuthAccount) {
+                  log(save(signer, amount: 0, to: /storage/zero))
+                  log(save(signer, amount: 1, to: /storage/one))
+                  log(signer.copy<Int>(from: /storage/zero))
+                  log(signer.copy<Int>(from: /storage/one))
+              }
+          }
+        `)
+
+		var loggedMessages []string
+		var writtenKeys []string
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestStorage(
+				nil,
+				func(_, key, value []byte) {
+					if len(value) > 0 {
+						writtenKeys = append(writtenKeys, string(key))
+					}
+				},
+			),
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{{42}}, nil
+			},
+			log: func(message string) {
+				loggedMessages = append(loggedMessages, message)
+			},
+		}
+
+		nextTransactionLocation := newTransactionLocationGenerator()
+
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: script,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]string{"false", "true", "nil", "1"},
+			loggedMessages,
+		)
+
+		assert.Equal(t,
+			[]string{"storage\x1fone"},
+			writtenKeys,
+		)
+	})
+
+	t.Run("irreversible effect", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := NewInterpreterRuntime()
+
+		script := []byte(`
+          transaction {
+              prepare(signer: AuthAccount) {
+                  savepoint(fun (): Bool {
+                      AuthAccount(payer: signer)
+                      return false
+                  })
+              }
+          }
+        `)
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestStorage(nil, nil),
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{{42}}, nil
+			},
+			createAccount: func(payer Address) (address Address, err error) {
+				return Address{43}, nil
+			},
+			emitEvent: func(event cadence.Event) error {
+				return nil
+			},
+		}
+
+		nextTransactionLocation := newTransactionLocationGenerator()
+
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: script,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.Error(t, err)
+
+		require.ErrorAs(t, err, &interpreter.IrreversibleSavepointRollbackError{})
+	})
+}
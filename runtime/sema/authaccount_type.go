@@ -34,10 +34,16 @@ const AuthAccountSaveField = "save"
 const AuthAccountLoadField = "load"
 const AuthAccountCopyField = "copy"
 const AuthAccountBorrowField = "borrow"
+const AuthAccountTypeField = "type"
+const AuthAccountCheckField = "check"
 const AuthAccountLinkField = "link"
 const AuthAccountUnlinkField = "unlink"
 const AuthAccountGetCapabilityField = "getCapability"
 const AuthAccountGetLinkTargetField = "getLinkTarget"
+const AuthAccountForEachStoredField = "forEachStored"
+const AuthAccountForEachPublicField = "forEachPublic"
+const AuthAccountForEachLinkField = "forEachLink"
+const AuthAccountUnlinkAllField = "unlinkAll"
 const AuthAccountContractsField = "contracts"
 const AuthAccountKeysField = "keys"
 
@@ -128,6 +134,18 @@ var AuthAccountType = func() *CompositeType {
 			authAccountTypeBorrowFunctionType,
 			authAccountTypeBorrowFunctionDocString,
 		),
+		NewPublicFunctionMember(
+			authAccountType,
+			AuthAccountTypeField,
+			authAccountTypeTypeFunctionType,
+			authAccountTypeTypeFunctionDocString,
+		),
+		NewPublicFunctionMember(
+			authAccountType,
+			AuthAccountCheckField,
+			authAccountTypeCheckFunctionType,
+			authAccountTypeCheckFunctionDocString,
+		),
 		NewPublicFunctionMember(
 			authAccountType,
 			AuthAccountLinkField,
@@ -152,6 +170,30 @@ var AuthAccountType = func() *CompositeType {
 			accountTypeGetLinkTargetFunctionType,
 			accountTypeGetLinkTargetFunctionDocString,
 		),
+		NewPublicFunctionMember(
+			authAccountType,
+			AuthAccountForEachStoredField,
+			authAccountTypeForEachStoredFunctionType,
+			authAccountTypeForEachStoredFunctionDocString,
+		),
+		NewPublicFunctionMember(
+			authAccountType,
+			AuthAccountForEachPublicField,
+			accountTypeForEachPublicFunctionType,
+			accountTypeForEachPublicFunctionDocString,
+		),
+		NewPublicFunctionMember(
+			authAccountType,
+			AuthAccountForEachLinkField,
+			authAccountTypeForEachLinkFunctionType,
+			authAccountTypeForEachLinkFunctionDocString,
+		),
+		NewPublicFunctionMember(
+			authAccountType,
+			AuthAccountUnlinkAllField,
+			authAccountTypeUnlinkAllFunctionType,
+			authAccountTypeUnlinkAllFunctionDocString,
+		),
 		NewPublicConstantFieldMember(
 			authAccountType,
 			AuthAccountContractsField,
@@ -379,6 +421,59 @@ The given type must not necessarily be exactly the same as the type of the borro
 The path must be a storage path, i.e., only the domain ` + "`storage`" + ` is allowed
 `
 
+var authAccountTypeTypeFunctionType = &FunctionType{
+	Parameters: []*Parameter{
+		{
+			Label:          "at",
+			Identifier:     "path",
+			TypeAnnotation: NewTypeAnnotation(StoragePathType),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(
+		&OptionalType{
+			Type: MetaType,
+		},
+	),
+}
+
+const authAccountTypeTypeFunctionDocString = `
+Returns the run-time type of the object stored in account storage under the given path, or nil if no object is stored under the given path.
+
+The object is neither removed from storage nor borrowed.
+
+The path must be a storage path, i.e., only the domain ` + "`storage`" + ` is allowed
+`
+
+var authAccountTypeCheckFunctionType = func() *FunctionType {
+
+	typeParameter := &TypeParameter{
+		Name:      "T",
+		TypeBound: AnyType,
+	}
+
+	return &FunctionType{
+		TypeParameters: []*TypeParameter{
+			typeParameter,
+		},
+		Parameters: []*Parameter{
+			{
+				Label:          "from",
+				Identifier:     "path",
+				TypeAnnotation: NewTypeAnnotation(StoragePathType),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(BoolType),
+	}
+}()
+
+const authAccountTypeCheckFunctionDocString = `
+Returns true if an object is stored in account storage under the given path and the given type is a supertype of the type of the stored object, or false otherwise.
+
+The object is neither removed from storage nor borrowed.
+
+The path must be a storage path, i.e., only the domain ` + "`storage`" + ` is allowed
+`
+
 var authAccountTypeLinkFunctionType = func() *FunctionType {
 
 	typeParameter := &TypeParameter{
@@ -493,6 +588,129 @@ var accountTypeGetLinkTargetFunctionType = &FunctionType{
 	),
 }
 
+// accountStorageIterationFunctionType returns the type of a function
+// which iterates over the paths of the given path type,
+// e.g. `fun forEachStored(_ function: ((StoragePath, Type): Bool))`
+//
+func accountStorageIterationFunctionType(pathType Type) *FunctionType {
+	return &FunctionType{
+		Parameters: []*Parameter{
+			{
+				Label:      ArgumentLabelNotRequired,
+				Identifier: "function",
+				TypeAnnotation: NewTypeAnnotation(
+					&FunctionType{
+						Parameters: []*Parameter{
+							{
+								Label:          ArgumentLabelNotRequired,
+								Identifier:     "path",
+								TypeAnnotation: NewTypeAnnotation(pathType),
+							},
+							{
+								Label:          ArgumentLabelNotRequired,
+								Identifier:     "type",
+								TypeAnnotation: NewTypeAnnotation(MetaType),
+							},
+						},
+						ReturnTypeAnnotation: NewTypeAnnotation(
+							BoolType,
+						),
+					},
+				),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(
+			VoidType,
+		),
+	}
+}
+
+var authAccountTypeForEachStoredFunctionType = accountStorageIterationFunctionType(StoragePathType)
+
+const authAccountTypeForEachStoredFunctionDocString = `
+Iterates over all values stored in the account's storage, in the order of their paths.
+
+The given function is called with the storage path and the run-time type of each stored value.
+Iteration stops early when the function returns false.
+Values saved while iterating are not visited, and values removed while iterating are skipped.
+`
+
+var accountTypeForEachPublicFunctionType = accountStorageIterationFunctionType(PublicPathType)
+
+const accountTypeForEachPublicFunctionDocString = `
+Iterates over all public links of the account, in the order of their paths.
+
+The given function is called with the public path and the borrow type of each link.
+Iteration stops early when the function returns false.
+Links created while iterating are not visited, and links removed while iterating are skipped.
+`
+
+var authAccountTypeForEachLinkFunctionType = &FunctionType{
+	Parameters: []*Parameter{
+		{
+			Label:      ArgumentLabelNotRequired,
+			Identifier: "function",
+			TypeAnnotation: NewTypeAnnotation(
+				&FunctionType{
+					Parameters: []*Parameter{
+						{
+							Label:          ArgumentLabelNotRequired,
+							Identifier:     "path",
+							TypeAnnotation: NewTypeAnnotation(CapabilityPathType),
+						},
+						{
+							Label:          ArgumentLabelNotRequired,
+							Identifier:     "type",
+							TypeAnnotation: NewTypeAnnotation(MetaType),
+						},
+						{
+							Label:          ArgumentLabelNotRequired,
+							Identifier:     "target",
+							TypeAnnotation: NewTypeAnnotation(PathType),
+						},
+					},
+					ReturnTypeAnnotation: NewTypeAnnotation(
+						BoolType,
+					),
+				},
+			),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(
+		VoidType,
+	),
+}
+
+const authAccountTypeForEachLinkFunctionDocString = `
+Iterates over all public and private links of the account, public links first, each in the order of their paths.
+
+The given function is called with the capability path, the borrow type, and the target path of each link.
+Iteration stops early when the function returns false.
+Links created while iterating are not visited, and links removed while iterating are skipped.
+`
+
+var authAccountTypeUnlinkAllFunctionType = &FunctionType{
+	Parameters: []*Parameter{
+		{
+			Label:          "targeting",
+			Identifier:     "target",
+			TypeAnnotation: NewTypeAnnotation(PathType),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(
+		&VariableSizedType{
+			Type: CapabilityPathType,
+		},
+	),
+}
+
+const authAccountTypeUnlinkAllFunctionDocString = `
+Removes all public and private links of the account which target the given path,
+either directly, or through other links.
+
+Returns the paths of the removed links, public links first, each in the order of their paths.
+`
+
 // AuthAccountKeysType represents the keys associated with an auth account.
 var AuthAccountKeysType = func() *CompositeType {
 
@@ -60,11 +60,14 @@ func (checker *Checker) VisitBinaryExpression(expression *ast.BinaryExpression)
 	leftIsInvalid := leftType.IsInvalidType()
 
 	unsupportedOperation := func() Type {
-		panic(&unsupportedOperation{
-			kind:      common.OperationKindBinary,
-			operation: operation,
-			Range:     ast.NewRangeFromPositioned(expression),
-		})
+		checker.report(
+			&UnsupportedOperationError{
+				Kind:      common.OperationKindBinary,
+				Operation: operation,
+				Range:     ast.NewRangeFromPositioned(expression),
+			},
+		)
+		return InvalidType
 	}
 
 	switch operationKind {
@@ -95,6 +95,27 @@ func (checker *Checker) VisitMemberExpression(expression *ast.MemberExpression)
 	return memberType
 }
 
+// recordMemberExpressionFieldSlot records the slot of the accessed field
+// of a composite value, so the interpreter can access the field by index
+//
+func (checker *Checker) recordMemberExpressionFieldSlot(expression *ast.MemberExpression, member *Member) {
+	if member == nil || member.DeclarationKind != common.DeclarationKindField {
+		return
+	}
+
+	compositeType, ok := member.ContainerType.(*CompositeType)
+	if !ok {
+		return
+	}
+
+	slot, ok := compositeType.FieldSlot(member.Identifier.Identifier)
+	if !ok {
+		return
+	}
+
+	checker.Elaboration.MemberExpressionFieldSlots[expression] = slot
+}
+
 func (checker *Checker) visitMember(expression *ast.MemberExpression) (accessedType Type, member *Member, isOptional bool) {
 	memberInfo, ok := checker.Elaboration.MemberExpressionMemberInfos[expression]
 	if ok {
@@ -108,6 +129,8 @@ func (checker *Checker) visitMember(expression *ast.MemberExpression) (accessedT
 				Member:       member,
 				IsOptional:   isOptional,
 			}
+
+		checker.recordMemberExpressionFieldSlot(expression, member)
 	}()
 
 	accessedExpression := expression.Expression
@@ -0,0 +1,61 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"github.com/onflow/cadence/runtime/ast"
+)
+
+func (checker *Checker) VisitTryExpression(expression *ast.TryExpression) ast.Repr {
+
+	// Expected type of the invocation is the inner type of the expected type of current context.
+	// i.e: if `try f()` is `String?`, then `f()` is expected to be `String`.
+
+	var expectedType Type
+	if optionalType, ok := checker.expectedType.(*OptionalType); ok {
+		expectedType = optionalType.Type
+	}
+
+	invocation := expression.InvocationExpression
+
+	ty := checker.VisitExpression(invocation, expectedType)
+
+	if ty.IsInvalidType() {
+		return ty
+	}
+
+	// Resources can't be passed as arguments:
+	// If the invocation fails, the resources would be lost
+
+	argumentTypes := checker.Elaboration.InvocationExpressionArgumentTypes[invocation]
+
+	for i, argumentType := range argumentTypes {
+		if !argumentType.IsResourceType() {
+			continue
+		}
+
+		checker.report(
+			&InvalidTryResourceArgumentError{
+				Range: ast.NewRangeFromPositioned(invocation.Arguments[i].Expression),
+			},
+		)
+	}
+
+	return &OptionalType{Type: ty}
+}
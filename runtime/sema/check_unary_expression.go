@@ -74,9 +74,13 @@ func (checker *Checker) VisitUnaryExpression(expression *ast.UnaryExpression) as
 		return valueType
 	}
 
-	panic(&unsupportedOperation{
-		kind:      common.OperationKindUnary,
-		operation: expression.Operation,
-		Range:     ast.NewRangeFromPositioned(expression),
-	})
+	checker.report(
+		&UnsupportedOperationError{
+			Kind:      common.OperationKindUnary,
+			Operation: expression.Operation,
+			Range:     ast.NewRangeFromPositioned(expression),
+		},
+	)
+
+	return InvalidType
 }
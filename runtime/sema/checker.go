@@ -115,6 +115,7 @@ type Checker struct {
 	checkHandler                       CheckHandlerFunc
 	expectedType                       Type
 	memberAccountAccessHandler         MemberAccountAccessHandlerFunc
+	typeInterner                       *TypeInterner
 }
 
 type Option func(*Checker) error
@@ -214,6 +215,16 @@ func WithMemberAccountAccessHandler(handler MemberAccountAccessHandlerFunc) Opti
 	}
 }
 
+// WithTypeInterner returns a checker option which sets
+// the given interner as the interner for converted types.
+//
+func WithTypeInterner(interner *TypeInterner) Option {
+	return func(checker *Checker) error {
+		checker.typeInterner = interner
+		return nil
+	}
+}
+
 // WithPositionInfoEnabled returns a checker option which enables/disables
 // if position info recoding is enabled.
 //
@@ -892,8 +903,19 @@ func (checker *Checker) findAndCheckValueVariable(identifierExpression *ast.Iden
 	return variable
 }
 
-// ConvertType converts an AST type representation to a sema type
+// ConvertType converts an AST type representation to a sema type.
+//
+// If the checker has a type interner, the canonical instance of the type is returned.
+//
 func (checker *Checker) ConvertType(t ast.Type) Type {
+	ty := checker.convertType(t)
+	if checker.typeInterner != nil {
+		ty = checker.typeInterner.Intern(ty)
+	}
+	return ty
+}
+
+func (checker *Checker) convertType(t ast.Type) Type {
 	switch t := t.(type) {
 	case *ast.NominalType:
 		return checker.convertNominalType(t)
@@ -927,7 +949,14 @@ func (checker *Checker) ConvertType(t ast.Type) Type {
 		return InvalidType
 	}
 
-	panic(&astTypeConversionError{invalidASTType: t})
+	checker.report(
+		&UnsupportedTypeError{
+			Type:  t,
+			Range: ast.NewRangeFromPositioned(t),
+		},
+	)
+
+	return InvalidType
 }
 
 func (checker *Checker) convertRestrictedType(t *ast.RestrictedType) Type {
@@ -38,6 +38,7 @@ var HashAlgorithms = []CryptoAlgorithm{
 	HashAlgorithmSHA3_256,
 	HashAlgorithmSHA3_384,
 	HashAlgorithmKMAC128_BLS_BLS12_381,
+	HashAlgorithmKECCAK_256,
 }
 
 var SignatureAlgorithmType = newNativeEnumType(
@@ -192,6 +193,7 @@ const (
 	HashAlgorithmSHA3_256
 	HashAlgorithmSHA3_384
 	HashAlgorithmKMAC128_BLS_BLS12_381
+	HashAlgorithmKECCAK_256
 )
 
 func (algo HashAlgorithm) Name() string {
@@ -208,6 +210,8 @@ func (algo HashAlgorithm) Name() string {
 		return "SHA3_384"
 	case HashAlgorithmKMAC128_BLS_BLS12_381:
 		return "KMAC128_BLS_BLS12_381"
+	case HashAlgorithmKECCAK_256:
+		return "KECCAK_256"
 	}
 
 	panic(errors.NewUnreachableError())
@@ -232,6 +236,8 @@ func (algo HashAlgorithm) RawValue() uint8 {
 		return 4
 	case HashAlgorithmKMAC128_BLS_BLS12_381:
 		return 5
+	case HashAlgorithmKECCAK_256:
+		return 6
 	}
 
 	panic(errors.NewUnreachableError())
@@ -251,6 +257,8 @@ func (algo HashAlgorithm) DocString() string {
 		return HashAlgorithmDocStringSHA3_384
 	case HashAlgorithmKMAC128_BLS_BLS12_381:
 		return HashAlgorithmDocStringKMAC128_BLS_BLS12_381
+	case HashAlgorithmKECCAK_256:
+		return HashAlgorithmDocStringKECCAK_256
 	}
 
 	panic(errors.NewUnreachableError())
@@ -332,3 +340,8 @@ that can be used as the hashing algorithm for BLS signature scheme on the curve
 This is a customized version of KMAC128 that is compatible with the hashing to curve 
 used in BLS signatures.
 `
+
+const HashAlgorithmDocStringKECCAK_256 = `
+KECCAK_256 is the legacy Keccak algorithm with a 256-bit digest, as per the original submission to the NIST SHA3 competition.
+KECCAK_256 is different than SHA3 and is used by Ethereum
+`
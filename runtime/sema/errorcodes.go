@@ -0,0 +1,176 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"github.com/onflow/cadence/runtime/errors"
+)
+
+// The error codes of the errors of this package.
+//
+// NOTE: Codes must never be changed or reused, new errors must be assigned new codes.
+//
+func init() {
+	errors.RegisterErrorCodes(
+		errors.ErrorCategoryUser,
+		map[errors.ErrorCode]error{
+			2001: &InvalidPragmaError{},
+			2002: &MissingLocationError{},
+			2003: CheckerError{},
+			2004: &RedeclarationError{},
+			2005: &NotDeclaredError{},
+			2006: &AssignmentToConstantError{},
+			2007: &TypeMismatchError{},
+			2008: &TypeMismatchWithDescriptionError{},
+			2009: &NotIndexableTypeError{},
+			2010: &NotIndexingAssignableTypeError{},
+			2011: &NotEquatableTypeError{},
+			2012: &NotCallableError{},
+			2013: &ArgumentCountError{},
+			2014: &MissingArgumentLabelError{},
+			2015: &IncorrectArgumentLabelError{},
+			2016: &InvalidUnaryOperandError{},
+			2017: &InvalidBinaryOperandError{},
+			2018: &InvalidBinaryOperandsError{},
+			2019: &InvalidNilCoalescingRightResourceOperandError{},
+			2020: &ControlStatementError{},
+			2021: &InvalidAccessModifierError{},
+			2022: &MissingAccessModifierError{},
+			2023: &InvalidNameError{},
+			2024: &UnknownSpecialFunctionError{},
+			2025: &InvalidVariableKindError{},
+			2026: &InvalidDeclarationError{},
+			2027: &MissingInitializerError{},
+			2028: &NotDeclaredMemberError{},
+			2029: &AssignmentToConstantMemberError{},
+			2030: &FieldUninitializedError{},
+			2031: &FieldTypeNotStorableError{},
+			2032: &FunctionExpressionInConditionError{},
+			2033: &MissingReturnValueError{},
+			2034: &InvalidImplementationError{},
+			2035: &InvalidConformanceError{},
+			2036: &InvalidEnumRawTypeError{},
+			2037: &MissingEnumRawTypeError{},
+			2038: &InvalidEnumConformancesError{},
+			2039: &ConformanceError{},
+			2040: &DuplicateConformanceError{},
+			2041: &MissingConformanceError{},
+			2042: &UnresolvedImportError{},
+			2043: &NotExportedError{},
+			2044: &ImportedProgramError{},
+			2045: &AlwaysFailingNonResourceCastingTypeError{},
+			2046: &AlwaysFailingResourceCastingTypeError{},
+			2047: &UnsupportedOverloadingError{},
+			2048: &CompositeKindMismatchError{},
+			2049: &InvalidIntegerLiteralRangeError{},
+			2050: &InvalidAddressLiteralError{},
+			2051: &InvalidFixedPointLiteralRangeError{},
+			2052: &InvalidFixedPointLiteralScaleError{},
+			2053: &MissingReturnStatementError{},
+			2054: &UnsupportedOptionalChainingAssignmentError{},
+			2055: &MissingResourceAnnotationError{},
+			2056: &InvalidNestedResourceMoveError{},
+			2057: &InvalidResourceAnnotationError{},
+			2058: &InvalidInterfaceTypeError{},
+			2059: &InvalidInterfaceDeclarationError{},
+			2060: &IncorrectTransferOperationError{},
+			2061: &InvalidConstructionError{},
+			2062: &InvalidDestructionError{},
+			2063: &InvalidTryResourceArgumentError{},
+			2064: &ResourceLossError{},
+			2065: &ResourceUseAfterInvalidationError{},
+			2066: &MissingCreateError{},
+			2067: &MissingMoveOperationError{},
+			2068: &InvalidMoveOperationError{},
+			2069: &ResourceCapturingError{},
+			2070: &InvalidResourceFieldError{},
+			2071: &InvalidIndexingError{},
+			2072: &InvalidSwapExpressionError{},
+			2073: &InvalidEventParameterTypeError{},
+			2074: &InvalidEventUsageError{},
+			2075: &EmitNonEventError{},
+			2076: &EmitImportedEventError{},
+			2077: &InvalidResourceAssignmentError{},
+			2078: &InvalidDestructorError{},
+			2079: &MissingDestructorError{},
+			2080: &InvalidDestructorParametersError{},
+			2081: &ResourceFieldNotInvalidatedError{},
+			2082: &UninitializedFieldAccessError{},
+			2083: &UnreachableStatementError{},
+			2084: &UninitializedUseError{},
+			2085: &InvalidResourceArrayMemberError{},
+			2086: &InvalidResourceDictionaryMemberError{},
+			2087: &InvalidResourceOptionalMemberError{},
+			2088: &NonReferenceTypeReferenceError{},
+			2089: &OptionalTypeReferenceError{},
+			2090: &InvalidResourceCreationError{},
+			2091: &NonResourceTypeError{},
+			2092: &InvalidAssignmentTargetError{},
+			2093: &ResourceMethodBindingError{},
+			2094: &InvalidDictionaryKeyTypeError{},
+			2095: &MissingFunctionBodyError{},
+			2096: &InvalidOptionalChainingError{},
+			2097: &InvalidAccessError{},
+			2098: &InvalidAssignmentAccessError{},
+			2099: &InvalidCharacterLiteralError{},
+			2100: &InvalidFailableResourceDowncastOutsideOptionalBindingError{},
+			2101: &InvalidNonIdentifierFailableResourceDowncast{},
+			2102: &ReadOnlyTargetAssignmentError{},
+			2103: &InvalidTransactionBlockError{},
+			2104: &TransactionMissingPrepareError{},
+			2105: &InvalidResourceTransactionParameterError{},
+			2106: &InvalidNonImportableTransactionParameterTypeError{},
+			2107: &InvalidTransactionFieldAccessModifierError{},
+			2108: &InvalidTransactionPrepareParameterTypeError{},
+			2109: &InvalidNestedDeclarationError{},
+			2110: &InvalidNestedTypeError{},
+			2111: &InvalidEnumCaseError{},
+			2112: &InvalidNonEnumCaseError{},
+			2113: &DeclarationKindMismatchError{},
+			2114: &InvalidTopLevelDeclarationError{},
+			2115: &InvalidSelfInvalidationError{},
+			2116: &InvalidMoveError{},
+			2117: &ConstantSizedArrayLiteralSizeError{},
+			2118: &InvalidRestrictedTypeError{},
+			2119: &InvalidRestrictionTypeError{},
+			2120: &RestrictionCompositeKindMismatchError{},
+			2121: &InvalidRestrictionTypeDuplicateError{},
+			2122: &InvalidNonConformanceRestrictionError{},
+			2123: &InvalidRestrictedTypeMemberAccessError{},
+			2124: &RestrictionMemberClashError{},
+			2125: &AmbiguousRestrictedTypeError{},
+			2126: &InvalidPathDomainError{},
+			2127: &InvalidTypeArgumentCountError{},
+			2128: &TypeParameterTypeInferenceError{},
+			2129: &InvalidConstantSizedTypeBaseError{},
+			2130: &InvalidConstantSizedTypeSizeError{},
+			2131: &UnsupportedResourceForLoopError{},
+			2132: &TypeParameterTypeMismatchError{},
+			2133: &UnparameterizedTypeInstantiationError{},
+			2134: &TypeAnnotationRequiredError{},
+			2135: &CyclicImportsError{},
+			2136: &SwitchDefaultPositionError{},
+			2137: &MissingSwitchCaseStatementsError{},
+			2138: &MissingEntryPointError{},
+			2139: &InvalidEntryPointTypeError{},
+			2140: &UnsupportedTypeError{},
+			2141: &UnsupportedOperationError{},
+		},
+	)
+}
@@ -29,32 +29,37 @@ import (
 	"github.com/onflow/cadence/runtime/pretty"
 )
 
-// astTypeConversionError
+// UnsupportedTypeError
 
-type astTypeConversionError struct {
-	invalidASTType ast.Type
+type UnsupportedTypeError struct {
+	Type ast.Type
+	ast.Range
 }
 
-func (e *astTypeConversionError) Error() string {
-	return fmt.Sprintf("cannot convert unsupported AST type: %#+v", e.invalidASTType)
+func (e *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("cannot check unsupported type: %#+v", e.Type)
 }
 
-// unsupportedOperation
+func (*UnsupportedTypeError) isSemanticError() {}
+
+// UnsupportedOperationError
 
-type unsupportedOperation struct {
-	kind      common.OperationKind
-	operation ast.Operation
+type UnsupportedOperationError struct {
+	Kind      common.OperationKind
+	Operation ast.Operation
 	ast.Range
 }
 
-func (e *unsupportedOperation) Error() string {
+func (e *UnsupportedOperationError) Error() string {
 	return fmt.Sprintf(
 		"cannot check unsupported %s operation: `%s`",
-		e.kind.Name(),
-		e.operation.Symbol(),
+		e.Kind.Name(),
+		e.Operation.Symbol(),
 	)
 }
 
+func (*UnsupportedOperationError) isSemanticError() {}
+
 // InvalidPragmaError
 
 type InvalidPragmaError struct {
@@ -107,6 +112,47 @@ func (e CheckerError) ImportLocation() common.Location {
 	return e.Location
 }
 
+// LocatedError is an error reported by the checker,
+// together with the location of the program in which the error occurred.
+//
+type LocatedError struct {
+	Err      error
+	Location common.Location
+}
+
+// LocatedErrors returns all errors reported by the checker, including the errors in imported programs,
+// each with the location of the program in which the error occurred.
+//
+// Errors which only wrap the errors of other programs, e.g. `ImportedProgramError`,
+// are replaced by the errors they wrap.
+//
+func (e CheckerError) LocatedErrors() []LocatedError {
+	var result []LocatedError
+	collectLocatedErrors(e, e.Location, &result)
+	return result
+}
+
+func collectLocatedErrors(err error, location common.Location, result *[]LocatedError) {
+	if err, ok := err.(common.HasImportLocation); ok {
+		importLocation := err.ImportLocation()
+		if importLocation != nil {
+			location = importLocation
+		}
+	}
+
+	if parentError, ok := err.(errors.ParentError); ok {
+		for _, childError := range parentError.ChildErrors() {
+			collectLocatedErrors(childError, location, result)
+		}
+		return
+	}
+
+	*result = append(*result, LocatedError{
+		Err:      err,
+		Location: location,
+	})
+}
+
 // SemanticError
 
 type SemanticError interface {
@@ -1427,6 +1473,22 @@ func (e *InvalidDestructionError) Error() string {
 
 func (*InvalidDestructionError) isSemanticError() {}
 
+// InvalidTryResourceArgumentError
+
+type InvalidTryResourceArgumentError struct {
+	ast.Range
+}
+
+func (e *InvalidTryResourceArgumentError) Error() string {
+	return "cannot pass resource to invocation in try expression"
+}
+
+func (e *InvalidTryResourceArgumentError) SecondaryError() string {
+	return "the resource would be lost if the invocation fails"
+}
+
+func (*InvalidTryResourceArgumentError) isSemanticError() {}
+
 // ResourceLossError
 
 type ResourceLossError struct {
@@ -30,6 +30,7 @@ const PublicAccountStorageUsedField = "storageUsed"
 const PublicAccountStorageCapacityField = "storageCapacity"
 const PublicAccountGetCapabilityField = "getCapability"
 const PublicAccountGetTargetLinkField = "getLinkTarget"
+const PublicAccountForEachPublicField = "forEachPublic"
 const PublicAccountKeysField = "keys"
 const PublicAccountContractsField = "contracts"
 
@@ -94,6 +95,12 @@ var PublicAccountType = func() *CompositeType {
 			accountTypeGetLinkTargetFunctionType,
 			accountTypeGetLinkTargetFunctionDocString,
 		),
+		NewPublicFunctionMember(
+			publicAccountType,
+			PublicAccountForEachPublicField,
+			accountTypeForEachPublicFunctionType,
+			accountTypeForEachPublicFunctionDocString,
+		),
 		NewPublicConstantFieldMember(
 			publicAccountType,
 			PublicAccountKeysField,
@@ -28,6 +28,40 @@ const StringTypeEncodeHexFunctionDocString = `
 Returns a hexadecimal string for the given byte array
 `
 
+const StringTypeDecodeHexFunctionName = "decodeHex"
+const StringTypeDecodeHexFunctionDocString = `
+Returns an array containing the bytes represented by the given hexadecimal string.
+
+The given string must only contain hexadecimal characters and must have an even length.
+If the string is malformed, the program aborts
+`
+
+const StringTypeEncodeBase64FunctionName = "encodeBase64"
+const StringTypeEncodeBase64FunctionDocString = `
+Returns a Base64 string for the given byte array, using the standard, padded encoding defined in RFC 4648
+`
+
+const StringTypeDecodeBase64FunctionName = "decodeBase64"
+const StringTypeDecodeBase64FunctionDocString = `
+Returns an array containing the bytes represented by the given Base64 string.
+
+The given string must be encoded using the standard, padded encoding defined in RFC 4648.
+If the string is malformed, the program aborts
+`
+
+const StringTypeFormatFunctionName = "format"
+const StringTypeFormatFunctionDocString = `
+Returns a string where the placeholders in the given format string are replaced by the given arguments.
+
+A placeholder has the form ` + "`{index}`" + ` or ` + "`{index:spec}`" + `, where the index is the position of the argument.
+The spec has the form ` + "`[-][0][width][.precision]`" + `:
+` + "`-`" + ` aligns left, ` + "`0`" + ` pads numbers with zeros, the width is the minimum length,
+and the precision is the number of fractional digits of a fixed-point number.
+Literal braces are written as ` + "`{{`" + ` and ` + "`}}`" + `.
+
+If the format string is malformed, the program aborts
+`
+
 // StringType represents the string type
 //
 var StringType = &SimpleType{
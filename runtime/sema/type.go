@@ -474,6 +474,10 @@ func (t *OptionalType) Equal(other Type) bool {
 	if !ok {
 		return false
 	}
+
+	if otherOptional == t {
+		return true
+	}
 	return t.Type.Equal(otherOptional.Type)
 }
 
@@ -1500,6 +1504,28 @@ Removes the last element from the array and returns it.
 The array must not be empty. If the array is empty, the program aborts
 `
 
+const arrayTypeMapFunctionDocString = `
+Returns a new array which contains the results of calling the given function
+with each element of the array, in order.
+
+The original array is not modified
+`
+
+const arrayTypeFilterFunctionDocString = `
+Returns a new array which contains only the elements of the array
+for which the given function returns true, in order.
+
+The original array is not modified
+`
+
+const arrayTypeReduceFunctionDocString = `
+Returns the result of combining all elements of the array using the given function.
+
+The function is called with the initial value and the first element,
+then with the result and the second element, and so on.
+If the array is empty, the initial value is returned
+`
+
 func getArrayMembers(arrayType ArrayType) map[string]MemberResolver {
 
 	members := map[string]MemberResolver{
@@ -1563,6 +1589,194 @@ func getArrayMembers(arrayType ArrayType) map[string]MemberResolver {
 				)
 			},
 		},
+		"map": {
+			Kind: common.DeclarationKindFunction,
+			Resolve: func(identifier string, targetRange ast.Range, report func(error)) *Member {
+
+				elementType := arrayType.ElementType(false)
+
+				// It is invalid for an array of resources to have a `map` function:
+				// the elements would have to be moved out of the array
+
+				if elementType.IsResourceType() {
+					report(
+						&InvalidResourceArrayMemberError{
+							Name:            identifier,
+							DeclarationKind: common.DeclarationKindFunction,
+							Range:           targetRange,
+						},
+					)
+				}
+
+				typeParameter := &TypeParameter{
+					Name: "T",
+				}
+
+				resultType := &GenericType{
+					TypeParameter: typeParameter,
+				}
+
+				return NewPublicFunctionMember(
+					arrayType,
+					identifier,
+					&FunctionType{
+						TypeParameters: []*TypeParameter{
+							typeParameter,
+						},
+						Parameters: []*Parameter{
+							{
+								Label:      ArgumentLabelNotRequired,
+								Identifier: "transform",
+								TypeAnnotation: NewTypeAnnotation(
+									&FunctionType{
+										Parameters: []*Parameter{
+											{
+												Label:          ArgumentLabelNotRequired,
+												Identifier:     "element",
+												TypeAnnotation: NewTypeAnnotation(elementType),
+											},
+										},
+										ReturnTypeAnnotation: NewTypeAnnotation(
+											resultType,
+										),
+									},
+								),
+							},
+						},
+						ReturnTypeAnnotation: NewTypeAnnotation(
+							&VariableSizedType{
+								Type: resultType,
+							},
+						),
+					},
+					arrayTypeMapFunctionDocString,
+				)
+			},
+		},
+		"filter": {
+			Kind: common.DeclarationKindFunction,
+			Resolve: func(identifier string, targetRange ast.Range, report func(error)) *Member {
+
+				elementType := arrayType.ElementType(false)
+
+				// It is invalid for an array of resources to have a `filter` function:
+				// the elements would have to be moved out of the array
+
+				if elementType.IsResourceType() {
+					report(
+						&InvalidResourceArrayMemberError{
+							Name:            identifier,
+							DeclarationKind: common.DeclarationKindFunction,
+							Range:           targetRange,
+						},
+					)
+				}
+
+				return NewPublicFunctionMember(
+					arrayType,
+					identifier,
+					&FunctionType{
+						Parameters: []*Parameter{
+							{
+								Label:      ArgumentLabelNotRequired,
+								Identifier: "predicate",
+								TypeAnnotation: NewTypeAnnotation(
+									&FunctionType{
+										Parameters: []*Parameter{
+											{
+												Label:          ArgumentLabelNotRequired,
+												Identifier:     "element",
+												TypeAnnotation: NewTypeAnnotation(elementType),
+											},
+										},
+										ReturnTypeAnnotation: NewTypeAnnotation(
+											BoolType,
+										),
+									},
+								),
+							},
+						},
+						ReturnTypeAnnotation: NewTypeAnnotation(
+							&VariableSizedType{
+								Type: elementType,
+							},
+						),
+					},
+					arrayTypeFilterFunctionDocString,
+				)
+			},
+		},
+		"reduce": {
+			Kind: common.DeclarationKindFunction,
+			Resolve: func(identifier string, targetRange ast.Range, report func(error)) *Member {
+
+				elementType := arrayType.ElementType(false)
+
+				// It is invalid for an array of resources to have a `reduce` function:
+				// the elements would have to be moved out of the array
+
+				if elementType.IsResourceType() {
+					report(
+						&InvalidResourceArrayMemberError{
+							Name:            identifier,
+							DeclarationKind: common.DeclarationKindFunction,
+							Range:           targetRange,
+						},
+					)
+				}
+
+				typeParameter := &TypeParameter{
+					Name: "T",
+				}
+
+				resultType := &GenericType{
+					TypeParameter: typeParameter,
+				}
+
+				return NewPublicFunctionMember(
+					arrayType,
+					identifier,
+					&FunctionType{
+						TypeParameters: []*TypeParameter{
+							typeParameter,
+						},
+						Parameters: []*Parameter{
+							{
+								Identifier:     "initial",
+								TypeAnnotation: NewTypeAnnotation(resultType),
+							},
+							{
+								Label:      ArgumentLabelNotRequired,
+								Identifier: "combine",
+								TypeAnnotation: NewTypeAnnotation(
+									&FunctionType{
+										Parameters: []*Parameter{
+											{
+												Label:          ArgumentLabelNotRequired,
+												Identifier:     "result",
+												TypeAnnotation: NewTypeAnnotation(resultType),
+											},
+											{
+												Label:          ArgumentLabelNotRequired,
+												Identifier:     "element",
+												TypeAnnotation: NewTypeAnnotation(elementType),
+											},
+										},
+										ReturnTypeAnnotation: NewTypeAnnotation(
+											resultType,
+										),
+									},
+								),
+							},
+						},
+						ReturnTypeAnnotation: NewTypeAnnotation(
+							resultType,
+						),
+					},
+					arrayTypeReduceFunctionDocString,
+				)
+			},
+		},
 	}
 
 	// TODO: maybe still return members but report a helpful error?
@@ -1792,6 +2006,10 @@ func (t *VariableSizedType) Equal(other Type) bool {
 		return false
 	}
 
+	if otherArray == t {
+		return true
+	}
+
 	return t.Type.Equal(otherArray.Type)
 }
 
@@ -1918,6 +2136,10 @@ func (t *ConstantSizedType) Equal(other Type) bool {
 		return false
 	}
 
+	if otherArray == t {
+		return true
+	}
+
 	return t.Type.Equal(otherArray.Type) &&
 		t.Size == otherArray.Size
 }
@@ -3055,6 +3277,95 @@ func init() {
 		StringTypeEncodeHexFunctionDocString,
 	))
 
+	addMember(NewPublicFunctionMember(
+		functionType,
+		StringTypeDecodeHexFunctionName,
+		&FunctionType{
+			Parameters: []*Parameter{
+				{
+					Label:          ArgumentLabelNotRequired,
+					Identifier:     "string",
+					TypeAnnotation: NewTypeAnnotation(StringType),
+				},
+			},
+			ReturnTypeAnnotation: NewTypeAnnotation(
+				&VariableSizedType{
+					Type: UInt8Type,
+				},
+			),
+		},
+		StringTypeDecodeHexFunctionDocString,
+	))
+
+	addMember(NewPublicFunctionMember(
+		functionType,
+		StringTypeEncodeBase64FunctionName,
+		&FunctionType{
+			Parameters: []*Parameter{
+				{
+					Label:      ArgumentLabelNotRequired,
+					Identifier: "data",
+					TypeAnnotation: NewTypeAnnotation(
+						&VariableSizedType{
+							Type: UInt8Type,
+						},
+					),
+				},
+			},
+			ReturnTypeAnnotation: NewTypeAnnotation(
+				StringType,
+			),
+		},
+		StringTypeEncodeBase64FunctionDocString,
+	))
+
+	addMember(NewPublicFunctionMember(
+		functionType,
+		StringTypeDecodeBase64FunctionName,
+		&FunctionType{
+			Parameters: []*Parameter{
+				{
+					Label:          ArgumentLabelNotRequired,
+					Identifier:     "string",
+					TypeAnnotation: NewTypeAnnotation(StringType),
+				},
+			},
+			ReturnTypeAnnotation: NewTypeAnnotation(
+				&VariableSizedType{
+					Type: UInt8Type,
+				},
+			),
+		},
+		StringTypeDecodeBase64FunctionDocString,
+	))
+
+	addMember(NewPublicFunctionMember(
+		functionType,
+		StringTypeFormatFunctionName,
+		&FunctionType{
+			Parameters: []*Parameter{
+				{
+					Label:          ArgumentLabelNotRequired,
+					Identifier:     "format",
+					TypeAnnotation: NewTypeAnnotation(StringType),
+				},
+				{
+					Label:      ArgumentLabelNotRequired,
+					Identifier: "arguments",
+					TypeAnnotation: NewTypeAnnotation(
+						&VariableSizedType{
+							Type: AnyStructType,
+						},
+					),
+				},
+			},
+			ReturnTypeAnnotation: NewTypeAnnotation(
+				StringType,
+			),
+		},
+		StringTypeFormatFunctionDocString,
+	))
+
 	BaseValueActivation.Set(
 		typeName,
 		baseFunctionVariable(
@@ -3348,10 +3659,61 @@ func (t *CompositeType) Equal(other Type) bool {
 		return false
 	}
 
+	if otherStructure == t {
+		return true
+	}
+
 	return otherStructure.Kind == t.Kind &&
 		otherStructure.ID() == t.ID()
 }
 
+// FieldSlots returns the names of the fields of values of the composite type, by slot index.
+//
+// The checker assigns the slots to the fields in the order of their declaration.
+// Predeclared fields which are ignored in serialization, like the owner of a resource,
+// are not fields of the values, so they have no slot.
+//
+func (t *CompositeType) FieldSlots() []string {
+	slots := make([]string, 0, len(t.Fields))
+
+	for _, name := range t.Fields {
+		if t.isIgnoredInSerialization(name) {
+			continue
+		}
+		slots = append(slots, name)
+	}
+
+	return slots
+}
+
+// FieldSlot returns the slot index of the field with the given name,
+// i.e. the index of the name in the result of FieldSlots
+//
+func (t *CompositeType) FieldSlot(name string) (int, bool) {
+	slot := 0
+
+	for _, fieldName := range t.Fields {
+		if t.isIgnoredInSerialization(fieldName) {
+			continue
+		}
+		if fieldName == name {
+			return slot, true
+		}
+		slot++
+	}
+
+	return 0, false
+}
+
+func (t *CompositeType) isIgnoredInSerialization(name string) bool {
+	if t.Members == nil {
+		return false
+	}
+
+	member, ok := t.Members.Get(name)
+	return ok && member.IgnoreInSerialization
+}
+
 func (t *CompositeType) GetMembers() map[string]MemberResolver {
 	t.initializeMemberResolvers()
 	return t.memberResolvers
@@ -3780,6 +4142,10 @@ func (t *InterfaceType) Equal(other Type) bool {
 		return false
 	}
 
+	if otherInterface == t {
+		return true
+	}
+
 	return otherInterface.CompositeKind == t.CompositeKind &&
 		otherInterface.ID() == t.ID()
 }
@@ -3953,6 +4319,10 @@ func (t *DictionaryType) Equal(other Type) bool {
 		return false
 	}
 
+	if otherDictionary == t {
+		return true
+	}
+
 	return otherDictionary.KeyType.Equal(t.KeyType) &&
 		otherDictionary.ValueType.Equal(t.ValueType)
 }
@@ -4043,6 +4413,27 @@ Removes the value for the given key from the dictionary.
 Returns the value as an optional if the dictionary contained the key, or nil if the dictionary did not contain the key
 `
 
+const dictionaryTypeForEachKeyFunctionDocString = `
+Calls the given function for each key of the dictionary, in order, without creating an array of the keys.
+
+The iteration stops when the function returns false.
+The dictionary must not be modified during the iteration. If it is, the program aborts
+`
+
+const dictionaryTypeForEachValueFunctionDocString = `
+Calls the given function for each value of the dictionary, in order, without creating an array of the values.
+
+The iteration stops when the function returns false.
+The dictionary must not be modified during the iteration. If it is, the program aborts
+`
+
+const dictionaryTypeForEachFunctionDocString = `
+Calls the given function for each key and value of the dictionary, in order.
+
+The iteration stops when the function returns false.
+The dictionary must not be modified during the iteration. If it is, the program aborts
+`
+
 func (t *DictionaryType) GetMembers() map[string]MemberResolver {
 	t.initializeMemberResolvers()
 	return t.memberResolvers
@@ -4132,6 +4523,143 @@ func (t *DictionaryType) initializeMemberResolvers() {
 					)
 				},
 			},
+			"forEachKey": {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(identifier string, targetRange ast.Range, report func(error)) *Member {
+
+					return NewPublicFunctionMember(
+						t,
+						identifier,
+						&FunctionType{
+							Parameters: []*Parameter{
+								{
+									Label:      ArgumentLabelNotRequired,
+									Identifier: "function",
+									TypeAnnotation: NewTypeAnnotation(
+										&FunctionType{
+											Parameters: []*Parameter{
+												{
+													Label:          ArgumentLabelNotRequired,
+													Identifier:     "key",
+													TypeAnnotation: NewTypeAnnotation(t.KeyType),
+												},
+											},
+											ReturnTypeAnnotation: NewTypeAnnotation(
+												BoolType,
+											),
+										},
+									),
+								},
+							},
+							ReturnTypeAnnotation: NewTypeAnnotation(
+								VoidType,
+							),
+						},
+						dictionaryTypeForEachKeyFunctionDocString,
+					)
+				},
+			},
+			"forEachValue": {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(identifier string, targetRange ast.Range, report func(error)) *Member {
+
+					// The values of a resource dictionary cannot be passed to the function
+
+					if t.ValueType.IsResourceType() {
+						report(
+							&InvalidResourceDictionaryMemberError{
+								Name:            identifier,
+								DeclarationKind: common.DeclarationKindFunction,
+								Range:           targetRange,
+							},
+						)
+					}
+
+					return NewPublicFunctionMember(
+						t,
+						identifier,
+						&FunctionType{
+							Parameters: []*Parameter{
+								{
+									Label:      ArgumentLabelNotRequired,
+									Identifier: "function",
+									TypeAnnotation: NewTypeAnnotation(
+										&FunctionType{
+											Parameters: []*Parameter{
+												{
+													Label:          ArgumentLabelNotRequired,
+													Identifier:     "value",
+													TypeAnnotation: NewTypeAnnotation(t.ValueType),
+												},
+											},
+											ReturnTypeAnnotation: NewTypeAnnotation(
+												BoolType,
+											),
+										},
+									),
+								},
+							},
+							ReturnTypeAnnotation: NewTypeAnnotation(
+								VoidType,
+							),
+						},
+						dictionaryTypeForEachValueFunctionDocString,
+					)
+				},
+			},
+			"forEach": {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(identifier string, targetRange ast.Range, report func(error)) *Member {
+
+					// The values of a resource dictionary cannot be passed to the function
+
+					if t.ValueType.IsResourceType() {
+						report(
+							&InvalidResourceDictionaryMemberError{
+								Name:            identifier,
+								DeclarationKind: common.DeclarationKindFunction,
+								Range:           targetRange,
+							},
+						)
+					}
+
+					return NewPublicFunctionMember(
+						t,
+						identifier,
+						&FunctionType{
+							Parameters: []*Parameter{
+								{
+									Label:      ArgumentLabelNotRequired,
+									Identifier: "function",
+									TypeAnnotation: NewTypeAnnotation(
+										&FunctionType{
+											Parameters: []*Parameter{
+												{
+													Label:          ArgumentLabelNotRequired,
+													Identifier:     "key",
+													TypeAnnotation: NewTypeAnnotation(t.KeyType),
+												},
+												{
+													Label:          ArgumentLabelNotRequired,
+													Identifier:     "value",
+													TypeAnnotation: NewTypeAnnotation(t.ValueType),
+												},
+											},
+											ReturnTypeAnnotation: NewTypeAnnotation(
+												BoolType,
+											),
+										},
+									),
+								},
+							},
+							ReturnTypeAnnotation: NewTypeAnnotation(
+								VoidType,
+							),
+						},
+						dictionaryTypeForEachFunctionDocString,
+					)
+				},
+			},
 			"insert": {
 				Kind: common.DeclarationKindFunction,
 				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
@@ -4287,6 +4815,10 @@ func (t *ReferenceType) Equal(other Type) bool {
 		return false
 	}
 
+	if otherReference == t {
+		return true
+	}
+
 	if t.Authorized != otherReference.Authorized {
 		return false
 	}
@@ -4489,6 +5021,10 @@ func (t *AddressType) GetMembers() map[string]MemberResolver {
 //
 func IsSubType(subType Type, superType Type) bool {
 
+	if subType == superType {
+		return true
+	}
+
 	if subType.Equal(superType) {
 		return true
 	}
@@ -4503,6 +5039,10 @@ func IsSubType(subType Type, superType Type) bool {
 //
 func IsProperSubType(subType Type, superType Type) bool {
 
+	if subType == superType {
+		return false
+	}
+
 	if subType.Equal(superType) {
 		return false
 	}
@@ -5320,6 +5860,10 @@ func (t *RestrictedType) Equal(other Type) bool {
 		return false
 	}
 
+	if otherRestrictedType == t {
+		return true
+	}
+
 	if !otherRestrictedType.Type.Equal(t.Type) {
 		return false
 	}
@@ -5519,6 +6063,10 @@ func (t *CapabilityType) Equal(other Type) bool {
 	if !ok {
 		return false
 	}
+
+	if otherCapability == t {
+		return true
+	}
 	if otherCapability.BorrowType == nil {
 		return t.BorrowType == nil
 	}
@@ -5684,6 +6232,14 @@ func capabilityTypeCheckFunctionType(borrowType Type) *FunctionType {
 	}
 }
 
+var capabilityTypeTargetTypeFunctionType = &FunctionType{
+	ReturnTypeAnnotation: NewTypeAnnotation(
+		&OptionalType{
+			Type: MetaType,
+		},
+	),
+}
+
 const capabilityTypeBorrowFunctionDocString = `
 Returns a reference to the object targeted by the capability, provided it can be borrowed using the given type
 `
@@ -5692,6 +6248,11 @@ const capabilityTypeCheckFunctionDocString = `
 Returns true if the capability currently targets an object that satisfies the given type, i.e. could be borrowed using the given type
 `
 
+const capabilityTypeTargetTypeFunctionDocString = `
+Returns the run-time type of the object currently targeted by the capability, or nil if the capability currently targets no object.
+The object is not borrowed, so the type is returned regardless of the types the links of the capability can be borrowed as
+`
+
 const addressTypeCheckFunctionDocString = `
 The address of the capability
 `
@@ -5726,6 +6287,17 @@ func (t *CapabilityType) initializeMemberResolvers() {
 					)
 				},
 			},
+			"targetType": {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicFunctionMember(
+						t,
+						identifier,
+						capabilityTypeTargetTypeFunctionType,
+						capabilityTypeTargetTypeFunctionDocString,
+					)
+				},
+			},
 			"address": {
 				Kind: common.DeclarationKindField,
 				Resolve: func(identifier string, _ ast.Range, _ func(error)) *Member {
@@ -5825,6 +6397,7 @@ const PublicKeyPublicKeyField = "publicKey"
 const PublicKeySignAlgoField = "signatureAlgorithm"
 const PublicKeyIsValidField = "isValid"
 const PublicKeyVerifyFunction = "verify"
+const PublicKeyVerifyPoPFunction = "verifyPoP"
 
 const publicKeyKeyFieldDocString = `
 The public key
@@ -5843,6 +6416,11 @@ Verifies a signature. Checks whether the signature was produced by signing
 the given tag and data, using this public key and the given hash algorithm
 `
 
+const publicKeyVerifyPoPFunctionDocString = `
+Verifies the proof of possession of the private key for this BLS public key.
+Returns false if the public key is not a BLS key
+`
+
 // PublicKeyType represents the public key associated with an account key.
 var PublicKeyType = func() *CompositeType {
 
@@ -5878,6 +6456,12 @@ var PublicKeyType = func() *CompositeType {
 			publicKeyVerifyFunctionType,
 			publicKeyVerifyFunctionDocString,
 		),
+		NewPublicFunctionMember(
+			publicKeyType,
+			PublicKeyVerifyPoPFunction,
+			publicKeyVerifyPoPFunctionType,
+			publicKeyVerifyPoPFunctionDocString,
+		),
 	}
 
 	publicKeyType.Members = GetMembersAsMap(members)
@@ -5917,6 +6501,22 @@ var publicKeyVerifyFunctionType = &FunctionType{
 	ReturnTypeAnnotation: NewTypeAnnotation(BoolType),
 }
 
+var publicKeyVerifyPoPFunctionType = &FunctionType{
+	TypeParameters: []*TypeParameter{},
+	Parameters: []*Parameter{
+		{
+			Label:      ArgumentLabelNotRequired,
+			Identifier: "proof",
+			TypeAnnotation: NewTypeAnnotation(
+				&VariableSizedType{
+					Type: UInt8Type,
+				},
+			),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(BoolType),
+}
+
 type CryptoAlgorithm interface {
 	RawValue() uint8
 	Name() string
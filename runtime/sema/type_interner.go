@@ -0,0 +1,284 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"sync"
+
+	"github.com/onflow/cadence/runtime/errors"
+)
+
+// TypeInterner canonicalizes types:
+// Structurally equal types are interned to the same instance,
+// so interned types can be compared by pointer,
+// and their type IDs are only computed once.
+// The Equal functions of types first compare the instances,
+// so comparing interned types is fast.
+//
+// Nominal types, like composite types and interface types,
+// and the simple types are their own canonical instance.
+// Restricted types and function types are not interned,
+// as they may refer to interface types of another check of the same program,
+// or carry members and argument checks.
+//
+// Derived types are keyed by the instances of their contained types,
+// so types referring to composite types or interface types are only shared
+// by checks which share those types, e.g. through imports.
+//
+// The number of interned types is bounded by the limit of the interner:
+// When the limit is reached, all interned types are released, and interning starts over.
+// Interning is only an optimization, as types are equal if they are structurally equal,
+// so types which were interned before still work as before.
+// It is safe to use an interner concurrently.
+//
+type TypeInterner struct {
+	lock  sync.RWMutex
+	limit int
+	types map[internedTypeKey]Type
+	ids   map[Type]TypeID
+}
+
+// DefaultTypeInternerLimit is the limit of the number of interned types
+// of an interner created with NewTypeInterner
+//
+const DefaultTypeInternerLimit = 100_000
+
+type internedTypeKind uint8
+
+const (
+	internedTypeKindUnknown internedTypeKind = iota
+	internedTypeKindOptional
+	internedTypeKindVariableSized
+	internedTypeKindConstantSized
+	internedTypeKindDictionary
+	internedTypeKindReference
+	internedTypeKindCapability
+)
+
+// internedTypeKey is the structural key of a derived type.
+// The contained types are canonical instances,
+// so the key can be compared without computing type IDs.
+//
+type internedTypeKey struct {
+	kind       internedTypeKind
+	first      Type
+	second     Type
+	size       int64
+	authorized bool
+}
+
+func NewTypeInterner() *TypeInterner {
+	return NewTypeInternerWithLimit(DefaultTypeInternerLimit)
+}
+
+// NewTypeInternerWithLimit returns a new interner,
+// which releases all interned types when the given number of types is interned
+//
+func NewTypeInternerWithLimit(limit int) *TypeInterner {
+	return &TypeInterner{
+		limit: limit,
+		types: map[internedTypeKey]Type{},
+		ids:   map[Type]TypeID{},
+	}
+}
+
+// Intern returns the canonical instance of the given type.
+//
+// The given type may become the canonical instance,
+// so it must not be modified afterwards.
+//
+func (i *TypeInterner) Intern(ty Type) Type {
+	i.lock.RLock()
+	interned, ok := i.canonical(ty, false)
+	i.lock.RUnlock()
+
+	if ok {
+		return interned
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if len(i.types) >= i.limit {
+		i.types = map[internedTypeKey]Type{}
+		i.ids = map[Type]TypeID{}
+	}
+
+	interned, _ = i.canonical(ty, true)
+	return interned
+}
+
+// canonical returns the canonical instance of the given type.
+//
+// If there is none yet and add is true, the type becomes the canonical instance.
+// If there is none yet and add is false, ok is false.
+//
+// The caller must hold the lock, and must hold it for writing if add is true.
+//
+func (i *TypeInterner) canonical(ty Type, add bool) (_ Type, ok bool) {
+
+	// The type might already be the canonical instance,
+	// e.g. when an interned type is contained in a type to intern
+
+	if _, ok = i.ids[ty]; ok {
+		return ty, true
+	}
+
+	var key internedTypeKey
+
+	switch ty := ty.(type) {
+	case *OptionalType:
+		key.kind = internedTypeKindOptional
+		key.first, ok = i.canonical(ty.Type, add)
+
+	case *VariableSizedType:
+		key.kind = internedTypeKindVariableSized
+		key.first, ok = i.canonical(ty.Type, add)
+
+	case *ConstantSizedType:
+		key.kind = internedTypeKindConstantSized
+		key.first, ok = i.canonical(ty.Type, add)
+		key.size = ty.Size
+
+	case *DictionaryType:
+		key.kind = internedTypeKindDictionary
+		key.first, ok = i.canonical(ty.KeyType, add)
+		if ok {
+			key.second, ok = i.canonical(ty.ValueType, add)
+		}
+
+	case *ReferenceType:
+		key.kind = internedTypeKindReference
+		key.first, ok = i.canonical(ty.Type, add)
+		key.authorized = ty.Authorized
+
+	case *CapabilityType:
+		key.kind = internedTypeKindCapability
+		key.first, ok = i.canonical(ty.BorrowType, add)
+
+	default:
+		return ty, true
+	}
+
+	if !ok {
+		return nil, false
+	}
+
+	interned, ok := i.types[key]
+	if ok || !add {
+		return interned, ok
+	}
+
+	interned = newInternedType(ty, key)
+	i.types[key] = interned
+
+	// The type ID is computed on first use, see ID
+
+	i.ids[interned] = ""
+
+	return interned, true
+}
+
+// newInternedType returns the canonical instance for the given type and its key:
+// The type itself, if its contained types are canonical, or a copy otherwise.
+//
+func newInternedType(ty Type, key internedTypeKey) Type {
+	switch ty := ty.(type) {
+	case *OptionalType:
+		if ty.Type == key.first {
+			return ty
+		}
+		return &OptionalType{Type: key.first}
+
+	case *VariableSizedType:
+		if ty.Type == key.first {
+			return ty
+		}
+		return &VariableSizedType{Type: key.first}
+
+	case *ConstantSizedType:
+		if ty.Type == key.first {
+			return ty
+		}
+		return &ConstantSizedType{
+			Type: key.first,
+			Size: key.size,
+		}
+
+	case *DictionaryType:
+		if ty.KeyType == key.first && ty.ValueType == key.second {
+			return ty
+		}
+		return &DictionaryType{
+			KeyType:   key.first,
+			ValueType: key.second,
+		}
+
+	case *ReferenceType:
+		if ty.Type == key.first {
+			return ty
+		}
+		return &ReferenceType{
+			Authorized: key.authorized,
+			Type:       key.first,
+		}
+
+	case *CapabilityType:
+		if ty.BorrowType == key.first {
+			return ty
+		}
+		return &CapabilityType{BorrowType: key.first}
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
+// ID returns the type ID of the given type.
+//
+// The type IDs of interned types are only computed once.
+// The type IDs of other types are computed on each call.
+//
+func (i *TypeInterner) ID(ty Type) TypeID {
+	i.lock.RLock()
+	id, interned := i.ids[ty]
+	i.lock.RUnlock()
+
+	if id != "" {
+		return id
+	}
+
+	id = ty.ID()
+
+	if interned {
+		i.lock.Lock()
+		i.ids[ty] = id
+		i.lock.Unlock()
+	}
+
+	return id
+}
+
+// Len returns the number of interned types.
+//
+func (i *TypeInterner) Len() int {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	return len(i.types)
+}
@@ -0,0 +1,292 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+)
+
+func TestTypeInterner(t *testing.T) {
+
+	t.Parallel()
+
+	compositeType := &CompositeType{
+		Location:   common.StringLocation("a"),
+		Identifier: "S",
+		Kind:       common.CompositeKindStructure,
+	}
+
+	interfaceType := &InterfaceType{
+		Location:      common.StringLocation("a"),
+		Identifier:    "I",
+		CompositeKind: common.CompositeKindStructure,
+	}
+
+	newTypes := func() []Type {
+		return []Type{
+			&OptionalType{Type: IntType},
+			&VariableSizedType{Type: &OptionalType{Type: StringType}},
+			&ConstantSizedType{Type: compositeType, Size: 2},
+			&ConstantSizedType{Type: compositeType, Size: 3},
+			&DictionaryType{KeyType: StringType, ValueType: compositeType},
+			&ReferenceType{Type: compositeType},
+			&ReferenceType{Type: compositeType, Authorized: true},
+			&CapabilityType{},
+			&CapabilityType{BorrowType: &ReferenceType{Type: AnyStructType}},
+		}
+	}
+
+	t.Run("structurally equal types", func(t *testing.T) {
+
+		t.Parallel()
+
+		interner := NewTypeInterner()
+
+		types := newTypes()
+		otherTypes := newTypes()
+
+		for i, ty := range types {
+			interned := interner.Intern(ty)
+			otherInterned := interner.Intern(otherTypes[i])
+
+			assert.Same(t, interned, otherInterned)
+			assert.True(t, ty.Equal(interned))
+			assert.Equal(t, ty.ID(), interner.ID(interned))
+		}
+
+		assert.Equal(t, len(types), countDistinctTypes(types, interner))
+	})
+
+	t.Run("limit", func(t *testing.T) {
+
+		t.Parallel()
+
+		interner := NewTypeInternerWithLimit(2)
+
+		first := interner.Intern(&OptionalType{Type: IntType})
+		interner.Intern(&OptionalType{Type: StringType})
+		assert.Equal(t, 2, interner.Len())
+
+		// The limit is reached, so all interned types are released
+
+		interner.Intern(&OptionalType{Type: BoolType})
+		assert.Equal(t, 1, interner.Len())
+
+		again := interner.Intern(&OptionalType{Type: IntType})
+		assert.NotSame(t, first, again)
+		assert.True(t, first.Equal(again))
+	})
+
+	t.Run("inner types", func(t *testing.T) {
+
+		t.Parallel()
+
+		interner := NewTypeInterner()
+
+		optionalType := interner.Intern(&OptionalType{Type: IntType})
+
+		arrayType := interner.Intern(
+			&VariableSizedType{
+				Type: &OptionalType{Type: IntType},
+			},
+		).(*VariableSizedType)
+
+		assert.Same(t, optionalType, arrayType.Type)
+	})
+
+	t.Run("canonical instance", func(t *testing.T) {
+
+		t.Parallel()
+
+		interner := NewTypeInterner()
+
+		ty := &OptionalType{Type: IntType}
+		assert.Same(t, ty, interner.Intern(ty))
+
+		interned := interner.Intern(ty)
+		assert.Same(t, interned, interner.Intern(interned))
+	})
+
+	t.Run("nominal and simple types", func(t *testing.T) {
+
+		t.Parallel()
+
+		interner := NewTypeInterner()
+
+		assert.Same(t, compositeType, interner.Intern(compositeType))
+		assert.Same(t, interfaceType, interner.Intern(interfaceType))
+		assert.Same(t, IntType, interner.Intern(IntType))
+		assert.Nil(t, interner.Intern(nil))
+
+		assert.Equal(t, 0, interner.Len())
+	})
+
+	t.Run("restricted and function types", func(t *testing.T) {
+
+		t.Parallel()
+
+		interner := NewTypeInterner()
+
+		restrictedType := &RestrictedType{
+			Type:         AnyStructType,
+			Restrictions: []*InterfaceType{interfaceType},
+		}
+
+		assert.Same(t, restrictedType, interner.Intern(restrictedType))
+		assert.Equal(t, restrictedType.ID(), interner.ID(restrictedType))
+
+		functionType := &FunctionType{
+			ReturnTypeAnnotation: NewTypeAnnotation(VoidType),
+		}
+
+		assert.Same(t, functionType, interner.Intern(functionType))
+		assert.Equal(t, functionType.ID(), interner.ID(functionType))
+
+		assert.Equal(t, 0, interner.Len())
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+
+		t.Parallel()
+
+		interner := NewTypeInterner()
+
+		const goroutines = 10
+
+		results := make([]Type, goroutines)
+
+		var wg sync.WaitGroup
+		wg.Add(goroutines)
+
+		for i := 0; i < goroutines; i++ {
+			go func(i int) {
+				defer wg.Done()
+				results[i] = interner.Intern(
+					&DictionaryType{
+						KeyType:   StringType,
+						ValueType: &VariableSizedType{Type: IntType},
+					},
+				)
+			}(i)
+		}
+
+		wg.Wait()
+
+		for _, result := range results {
+			require.Same(t, results[0], result)
+		}
+
+		assert.Equal(t, 2, interner.Len())
+	})
+}
+
+func countDistinctTypes(types []Type, interner *TypeInterner) int {
+	distinct := map[Type]struct{}{}
+	for _, ty := range types {
+		distinct[interner.Intern(ty)] = struct{}{}
+	}
+	return len(distinct)
+}
+
+func TestIsSubTypeInterned(t *testing.T) {
+
+	t.Parallel()
+
+	interner := NewTypeInterner()
+
+	subType := interner.Intern(&ReferenceType{Type: IntType})
+	superType := interner.Intern(&ReferenceType{Type: IntType})
+
+	assert.True(t, IsSubType(subType, superType))
+	assert.False(t, IsProperSubType(subType, superType))
+}
+
+func BenchmarkTypeInterner(b *testing.B) {
+
+	compositeType := &CompositeType{
+		Location:   common.StringLocation("a"),
+		Identifier: "S",
+		Kind:       common.CompositeKindStructure,
+	}
+
+	newType := func() Type {
+		return &OptionalType{
+			Type: &DictionaryType{
+				KeyType: StringType,
+				ValueType: &VariableSizedType{
+					Type: &ReferenceType{Type: compositeType},
+				},
+			},
+		}
+	}
+
+	b.Run("ID", func(b *testing.B) {
+		ty := newType()
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			_ = ty.ID()
+		}
+	})
+
+	b.Run("ID, interned", func(b *testing.B) {
+		interner := NewTypeInterner()
+		ty := interner.Intern(newType())
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			_ = interner.ID(ty)
+		}
+	})
+
+	b.Run("IsSubType", func(b *testing.B) {
+		subType := newType()
+		superType := newType()
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			_ = IsSubType(subType, superType)
+		}
+	})
+
+	b.Run("IsSubType, interned", func(b *testing.B) {
+		interner := NewTypeInterner()
+		subType := interner.Intern(newType())
+		superType := interner.Intern(newType())
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			_ = IsSubType(subType, superType)
+		}
+	})
+}
@@ -0,0 +1,710 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// TransactionSimulation is the result of simulating a transaction,
+// i.e. the effects the transaction would have if it was executed.
+//
+type TransactionSimulation struct {
+	// StorageChanges are the changes of stored values, sorted by storage key
+	StorageChanges []StorageChange
+	// Events are the emitted events, in order
+	Events []cadence.Event
+	// Logs are the logged messages, in order
+	Logs []string
+	// CreatedAccounts are the addresses of the created accounts, in order
+	CreatedAccounts []Address
+	// AccountKeyChanges are the additions and revocations of account keys, in order
+	AccountKeyChanges []AccountKeyChange
+	// ContractChanges are the deployments, updates, and removals of contracts, sorted by address and name
+	ContractChanges []ContractChange
+}
+
+// StorageChange is the change of the data stored for a key.
+// If there was no data before, Before is nil.
+// If the data is removed, After is nil.
+//
+type StorageChange struct {
+	StorageKey
+	Before []byte
+	After  []byte
+}
+
+// AccountKeyChange is the addition or revocation of an account key.
+//
+// Keys added with the deprecated encoded key API only have an encoded key.
+// The encoded key of a key revoked with the deprecated encoded key API is not known.
+//
+type AccountKeyChange struct {
+	Address    Address
+	Revoked    bool
+	KeyIndex   int
+	Key        *AccountKey
+	EncodedKey []byte
+}
+
+// ContractChange is the deployment, update, or removal of a contract.
+// If the contract is removed, Code is nil.
+//
+type ContractChange struct {
+	Address Address
+	Name    string
+	Code    []byte
+}
+
+// simulationInterface is a runtime interface which wraps another runtime interface,
+// and which records all writes, instead of delegating them.
+//
+// Reads are answered from the recorded writes, if any,
+// otherwise they are delegated to the wrapped interface.
+//
+// Accounts and UUIDs are allocated by the simulation, and not by the wrapped interface,
+// so that the simulation does not change the state of the wrapped interface.
+// The created accounts have placeholder addresses, see simulatedAccountAddress,
+// and their data is never read from the wrapped interface.
+//
+// Random numbers are generated by the simulation, so they differ from those of an execution.
+// Metrics, computation usage, and implementation debug logs are not reported.
+//
+type simulationInterface struct {
+	Interface
+	storage           map[StorageKey][]byte
+	programs          map[common.LocationID]*interpreter.Program
+	contracts         map[common.AddressLocation][]byte
+	nextKeyIndices    map[Address]int
+	revokedKeys       map[Address]map[int]struct{}
+	events            []cadence.Event
+	logs              []string
+	createdAccounts   []Address
+	accountKeyChanges []AccountKeyChange
+	nextUUID          uint64
+}
+
+// simulatedUUIDStart is the first UUID generated by the simulation.
+// It is far from the UUIDs generated so far by the wrapped interface,
+// so the UUIDs of created resources are unlikely to collide with the UUIDs of existing resources.
+//
+const simulatedUUIDStart = 1 << 63
+
+// simulatedAccountAddress returns the placeholder address of the account
+// created at the given index in a simulation.
+//
+// The addresses are allocated downwards from the greatest address,
+// so they are unlikely to be addresses of existing accounts.
+//
+func simulatedAccountAddress(index int) Address {
+	var address Address
+	binary.BigEndian.PutUint64(address[:], math.MaxUint64-uint64(index))
+	return address
+}
+
+var _ Interface = &simulationInterface{}
+
+func newSimulationInterface(runtimeInterface Interface) *simulationInterface {
+	return &simulationInterface{
+		Interface:      runtimeInterface,
+		storage:        map[StorageKey][]byte{},
+		programs:       map[common.LocationID]*interpreter.Program{},
+		contracts:      map[common.AddressLocation][]byte{},
+		nextKeyIndices: map[Address]int{},
+		revokedKeys:    map[Address]map[int]struct{}{},
+		nextUUID:       simulatedUUIDStart,
+	}
+}
+
+// isCreatedAccount returns true if the account with the given address was created in the simulation.
+//
+func (i *simulationInterface) isCreatedAccount(address Address) bool {
+	for _, createdAccount := range i.createdAccounts {
+		if createdAccount == address {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *simulationInterface) GetValue(owner, key []byte) (value []byte, err error) {
+	storageKey := StorageKey{
+		Address: common.BytesToAddress(owner),
+		Key:     string(key),
+	}
+
+	if value, ok := i.storage[storageKey]; ok {
+		return value, nil
+	}
+
+	if i.isCreatedAccount(storageKey.Address) {
+		return nil, nil
+	}
+
+	return i.Interface.GetValue(owner, key)
+}
+
+func (i *simulationInterface) SetValue(owner, key, value []byte) error {
+	storageKey := StorageKey{
+		Address: common.BytesToAddress(owner),
+		Key:     string(key),
+	}
+
+	i.storage[storageKey] = value
+
+	return nil
+}
+
+func (i *simulationInterface) GetValues(keys []StorageKey) (values [][]byte, err error) {
+	values = make([][]byte, len(keys))
+
+	// Only request the keys which were not written
+
+	var missingIndices []int
+	var missingKeys []StorageKey
+
+	for index, key := range keys {
+		if value, ok := i.storage[key]; ok {
+			values[index] = value
+			continue
+		}
+
+		if i.isCreatedAccount(key.Address) {
+			continue
+		}
+
+		missingIndices = append(missingIndices, index)
+		missingKeys = append(missingKeys, key)
+	}
+
+	if len(missingKeys) == 0 {
+		return values, nil
+	}
+
+	missingValues, err := i.Interface.GetValues(missingKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	for j, index := range missingIndices {
+		values[index] = missingValues[j]
+	}
+
+	return values, nil
+}
+
+func (i *simulationInterface) SetValues(writes []StorageWrite) error {
+	for _, write := range writes {
+		i.storage[write.StorageKey] = write.Value
+	}
+
+	return nil
+}
+
+func (i *simulationInterface) ValueExists(owner, key []byte) (exists bool, err error) {
+	storageKey := StorageKey{
+		Address: common.BytesToAddress(owner),
+		Key:     string(key),
+	}
+
+	if value, ok := i.storage[storageKey]; ok {
+		return len(value) > 0, nil
+	}
+
+	if i.isCreatedAccount(storageKey.Address) {
+		return false, nil
+	}
+
+	return i.Interface.ValueExists(owner, key)
+}
+
+func (i *simulationInterface) GetStorageKeys(owner, prefix []byte) (keys [][]byte, err error) {
+	address := common.BytesToAddress(owner)
+
+	var storedKeys [][]byte
+	if !i.isCreatedAccount(address) {
+		storedKeys, err = i.Interface.GetStorageKeys(owner, prefix)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, key := range storedKeys {
+		storageKey := StorageKey{
+			Address: address,
+			Key:     string(key),
+		}
+
+		// Keys which were written are added below
+
+		if _, ok := i.storage[storageKey]; ok {
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	for storageKey, value := range i.storage { //nolint:maprangecheck
+		if storageKey.Address != address ||
+			len(value) == 0 ||
+			!strings.HasPrefix(storageKey.Key, string(prefix)) {
+
+			continue
+		}
+
+		keys = append(keys, []byte(storageKey.Key))
+	}
+
+	return keys, nil
+}
+
+func (i *simulationInterface) GetProgram(location Location) (*interpreter.Program, error) {
+	if program, ok := i.programs[location.ID()]; ok {
+		return program, nil
+	}
+
+	// The program of a contract which was changed in the simulation
+	// must not be taken from the wrapped interface
+
+	if addressLocation, ok := location.(common.AddressLocation); ok {
+		if _, ok := i.contracts[addressLocation]; ok {
+			return nil, nil
+		}
+
+		if i.isCreatedAccount(addressLocation.Address) {
+			return nil, nil
+		}
+	}
+
+	return i.Interface.GetProgram(location)
+}
+
+func (i *simulationInterface) SetProgram(location Location, program *interpreter.Program) error {
+	i.programs[location.ID()] = program
+	return nil
+}
+
+func (i *simulationInterface) GetCode(location Location) ([]byte, error) {
+	if addressLocation, ok := location.(common.AddressLocation); ok {
+		if code, ok := i.contracts[addressLocation]; ok {
+			return code, nil
+		}
+
+		if i.isCreatedAccount(addressLocation.Address) {
+			return nil, nil
+		}
+	}
+
+	return i.Interface.GetCode(location)
+}
+
+func (i *simulationInterface) GetAccountContractCode(address Address, name string) (code []byte, err error) {
+	location := common.AddressLocation{
+		Address: address,
+		Name:    name,
+	}
+
+	if code, ok := i.contracts[location]; ok {
+		return code, nil
+	}
+
+	if i.isCreatedAccount(address) {
+		return nil, nil
+	}
+
+	return i.Interface.GetAccountContractCode(address, name)
+}
+
+func (i *simulationInterface) UpdateAccountContractCode(address Address, name string, code []byte) error {
+	location := common.AddressLocation{
+		Address: address,
+		Name:    name,
+	}
+
+	i.contracts[location] = code
+
+	return nil
+}
+
+func (i *simulationInterface) RemoveAccountContractCode(address Address, name string) error {
+	location := common.AddressLocation{
+		Address: address,
+		Name:    name,
+	}
+
+	i.contracts[location] = nil
+
+	return nil
+}
+
+func (i *simulationInterface) GetAccountContractNames(address Address) ([]string, error) {
+	var storedNames []string
+	if !i.isCreatedAccount(address) {
+		var err error
+		storedNames, err = i.Interface.GetAccountContractNames(address)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	names := map[string]struct{}{}
+	for _, name := range storedNames {
+		names[name] = struct{}{}
+	}
+
+	for location, code := range i.contracts { //nolint:maprangecheck
+		if location.Address != address {
+			continue
+		}
+
+		if code == nil {
+			delete(names, location.Name)
+		} else {
+			names[location.Name] = struct{}{}
+		}
+	}
+
+	result := make([]string, 0, len(names))
+	for name := range names { //nolint:maprangecheck
+		result = append(result, name)
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
+
+func (i *simulationInterface) CreateAccount(_ Address) (address Address, err error) {
+	address = simulatedAccountAddress(len(i.createdAccounts))
+
+	i.createdAccounts = append(i.createdAccounts, address)
+
+	return address, nil
+}
+
+func (i *simulationInterface) GenerateUUID() (uint64, error) {
+	uuid := i.nextUUID
+	i.nextUUID++
+	return uuid, nil
+}
+
+func (i *simulationInterface) GetAccountBalance(address Address) (uint64, error) {
+	if i.isCreatedAccount(address) {
+		return 0, nil
+	}
+	return i.Interface.GetAccountBalance(address)
+}
+
+func (i *simulationInterface) GetAccountAvailableBalance(address Address) (uint64, error) {
+	if i.isCreatedAccount(address) {
+		return 0, nil
+	}
+	return i.Interface.GetAccountAvailableBalance(address)
+}
+
+func (i *simulationInterface) GetStorageUsed(address Address) (uint64, error) {
+	if i.isCreatedAccount(address) {
+		return 0, nil
+	}
+	return i.Interface.GetStorageUsed(address)
+}
+
+func (i *simulationInterface) GetStorageCapacity(address Address) (uint64, error) {
+	if i.isCreatedAccount(address) {
+		return 0, nil
+	}
+	return i.Interface.GetStorageCapacity(address)
+}
+
+// nextKeyIndex returns the index of the next key added to the given account.
+//
+func (i *simulationInterface) nextKeyIndex(address Address) (int, error) {
+	if index, ok := i.nextKeyIndices[address]; ok {
+		i.nextKeyIndices[address] = index + 1
+		return index, nil
+	}
+
+	// Determine the number of existing keys
+
+	index := 0
+	for !i.isCreatedAccount(address) {
+		key, err := i.Interface.GetAccountKey(address, index)
+		if err != nil {
+			return 0, err
+		}
+		if key == nil {
+			break
+		}
+		index++
+	}
+
+	i.nextKeyIndices[address] = index + 1
+
+	return index, nil
+}
+
+func (i *simulationInterface) AddEncodedAccountKey(address Address, publicKey []byte) error {
+	index, err := i.nextKeyIndex(address)
+	if err != nil {
+		return err
+	}
+
+	i.accountKeyChanges = append(i.accountKeyChanges, AccountKeyChange{
+		Address:    address,
+		KeyIndex:   index,
+		EncodedKey: publicKey,
+	})
+
+	return nil
+}
+
+func (i *simulationInterface) RevokeEncodedAccountKey(address Address, index int) (publicKey []byte, err error) {
+	i.revokeKey(address, index)
+
+	i.accountKeyChanges = append(i.accountKeyChanges, AccountKeyChange{
+		Address:  address,
+		Revoked:  true,
+		KeyIndex: index,
+	})
+
+	return nil, nil
+}
+
+func (i *simulationInterface) AddAccountKey(
+	address Address,
+	publicKey *PublicKey,
+	hashAlgo HashAlgorithm,
+	weight int,
+) (
+	*AccountKey,
+	error,
+) {
+	index, err := i.nextKeyIndex(address)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &AccountKey{
+		KeyIndex:  index,
+		PublicKey: publicKey,
+		HashAlgo:  hashAlgo,
+		Weight:    weight,
+	}
+
+	i.accountKeyChanges = append(i.accountKeyChanges, AccountKeyChange{
+		Address:  address,
+		KeyIndex: index,
+		Key:      key,
+	})
+
+	return key, nil
+}
+
+func (i *simulationInterface) GetAccountKey(address Address, index int) (*AccountKey, error) {
+
+	// Keys added in the simulation
+
+	for _, change := range i.accountKeyChanges {
+		if change.Revoked ||
+			change.Address != address ||
+			change.KeyIndex != index ||
+			change.Key == nil {
+
+			continue
+		}
+
+		key := *change.Key
+		_, key.IsRevoked = i.revokedKeys[address][index]
+		return &key, nil
+	}
+
+	if i.isCreatedAccount(address) {
+		return nil, nil
+	}
+
+	key, err := i.Interface.GetAccountKey(address, index)
+	if err != nil || key == nil {
+		return key, err
+	}
+
+	if _, ok := i.revokedKeys[address][index]; ok {
+		revokedKey := *key
+		revokedKey.IsRevoked = true
+		key = &revokedKey
+	}
+
+	return key, nil
+}
+
+func (i *simulationInterface) RevokeAccountKey(address Address, index int) (*AccountKey, error) {
+	key, err := i.GetAccountKey(address, index)
+	if err != nil || key == nil {
+		return key, err
+	}
+
+	i.revokeKey(address, index)
+
+	revokedKey := *key
+	revokedKey.IsRevoked = true
+
+	i.accountKeyChanges = append(i.accountKeyChanges, AccountKeyChange{
+		Address:  address,
+		Revoked:  true,
+		KeyIndex: index,
+		Key:      &revokedKey,
+	})
+
+	return &revokedKey, nil
+}
+
+func (i *simulationInterface) revokeKey(address Address, index int) {
+	revokedKeys, ok := i.revokedKeys[address]
+	if !ok {
+		revokedKeys = map[int]struct{}{}
+		i.revokedKeys[address] = revokedKeys
+	}
+	revokedKeys[index] = struct{}{}
+}
+
+func (i *simulationInterface) EmitEvent(event cadence.Event) error {
+	i.events = append(i.events, event)
+	return nil
+}
+
+func (i *simulationInterface) ProgramLog(message string) error {
+	i.logs = append(i.logs, message)
+	return nil
+}
+
+func (i *simulationInterface) ImplementationDebugLog(_ string) error {
+	return nil
+}
+
+func (i *simulationInterface) SetComputationUsed(_ uint64) error {
+	return nil
+}
+
+func (i *simulationInterface) UnsafeRandom() (uint64, error) {
+	var buffer [8]byte
+	err := i.ReadRandom(buffer[:])
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(buffer[:]), nil
+}
+
+func (i *simulationInterface) ReadRandom(buffer []byte) error {
+	_, err := rand.Read(buffer)
+	return err
+}
+
+// simulation returns the recorded effects.
+//
+// The data stored before is read from the wrapped interface.
+// Writes which do not change the stored data are omitted.
+//
+func (i *simulationInterface) simulation() (*TransactionSimulation, error) {
+
+	storageChanges := make([]StorageChange, 0, len(i.storage))
+
+	for storageKey, after := range i.storage { //nolint:maprangecheck
+		var before []byte
+		if !i.isCreatedAccount(storageKey.Address) {
+			var err error
+			before, err = i.Interface.GetValue(storageKey.Address[:], []byte(storageKey.Key))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if bytes.Equal(before, after) {
+			continue
+		}
+
+		if len(before) == 0 {
+			before = nil
+		}
+
+		if len(after) == 0 {
+			after = nil
+		}
+
+		storageChanges = append(storageChanges, StorageChange{
+			StorageKey: storageKey,
+			Before:     before,
+			After:      after,
+		})
+	}
+
+	sort.Slice(storageChanges, func(a, b int) bool {
+		return storageKeyLess(storageChanges[a].StorageKey, storageChanges[b].StorageKey)
+	})
+
+	contractChanges := make([]ContractChange, 0, len(i.contracts))
+
+	for location, code := range i.contracts { //nolint:maprangecheck
+		contractChanges = append(contractChanges, ContractChange{
+			Address: location.Address,
+			Name:    location.Name,
+			Code:    code,
+		})
+	}
+
+	sort.Slice(contractChanges, func(a, b int) bool {
+		changeA := contractChanges[a]
+		changeB := contractChanges[b]
+
+		switch bytes.Compare(changeA.Address[:], changeB.Address[:]) {
+		case -1:
+			return true
+		case 1:
+			return false
+		default:
+			return changeA.Name < changeB.Name
+		}
+	})
+
+	return &TransactionSimulation{
+		StorageChanges:    storageChanges,
+		Events:            i.events,
+		Logs:              i.logs,
+		CreatedAccounts:   i.createdAccounts,
+		AccountKeyChanges: i.accountKeyChanges,
+		ContractChanges:   contractChanges,
+	}, nil
+}
+
+func storageKeyLess(a, b StorageKey) bool {
+	switch bytes.Compare(a.Address[:], b.Address[:]) {
+	case -1:
+		return true
+	case 1:
+		return false
+	default:
+		return a.Key < b.Key
+	}
+}
@@ -0,0 +1,423 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/stdlib"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestRuntimeSimulateTransaction(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	existingKey := &AccountKey{
+		KeyIndex: 0,
+		PublicKey: &PublicKey{
+			PublicKey: []byte{1, 2, 3},
+			SignAlgo:  SignatureAlgorithmECDSA_P256,
+		},
+		HashAlgo: HashAlgorithmSHA3_256,
+		Weight:   1000,
+	}
+
+	newRuntimeInterface := func(t *testing.T) *testRuntimeInterface {
+		return &testRuntimeInterface{
+			storage: newTestStorage(nil, nil),
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{address}, nil
+			},
+			getAccountKey: func(_ Address, index int) (*AccountKey, error) {
+				if index == 0 {
+					return existingKey, nil
+				}
+				return nil, nil
+			},
+			addAccountKey: func(_ Address, _ *PublicKey, _ HashAlgorithm, _ int) (*AccountKey, error) {
+				t.Error("unexpected key addition")
+				return nil, nil
+			},
+			removeAccountKey: func(_ Address, _ int) (*AccountKey, error) {
+				t.Error("unexpected key revocation")
+				return nil, nil
+			},
+			updateAccountContractCode: func(_ Address, _ string, _ []byte) error {
+				t.Error("unexpected contract update")
+				return nil
+			},
+			getAccountContractCode: func(_ Address, _ string) ([]byte, error) {
+				return nil, nil
+			},
+			emitEvent: func(event cadence.Event) error {
+				t.Error("unexpected event")
+				return nil
+			},
+			log: func(message string) {
+				t.Error("unexpected log")
+			},
+		}
+	}
+
+	t.Run("storage", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtimeInterface := newRuntimeInterface(t)
+
+		runtime := NewInterpreterRuntime()
+
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: []byte(`
+                  transaction {
+                      prepare(signer: AuthAccount) {
+                          signer.save(1, to: /storage/a)
+                          signer.save(2, to: /storage/b)
+                      }
+                  }
+                `),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  utils.TestLocation,
+			},
+		)
+		require.NoError(t, err)
+
+		storedValues := map[string][]byte{}
+		for key, value := range runtimeInterface.storage.storedValues {
+			storedValues[key] = value
+		}
+
+		simulation, err := runtime.SimulateTransaction(
+			Script{
+				Source: []byte(`
+                  transaction {
+                      prepare(signer: AuthAccount) {
+                          signer.load<Int>(from: /storage/a)
+                          signer.save(3, to: /storage/c)
+
+                          let b = signer.load<Int>(from: /storage/b)!
+                          signer.save(b, to: /storage/b)
+
+                          log("done")
+                      }
+                  }
+                `),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  utils.TestLocation,
+			},
+		)
+		require.NoError(t, err)
+
+		// The storage was not modified
+
+		assert.Equal(t, storedValues, runtimeInterface.storage.storedValues)
+
+		// The unchanged value is not reported
+
+		require.Len(t, simulation.StorageChanges, 2)
+
+		removal := simulation.StorageChanges[0]
+		assert.Equal(t, address, removal.Address)
+		assert.Equal(t, "storage\x1Fa", removal.Key)
+		assert.NotNil(t, removal.Before)
+		assert.Nil(t, removal.After)
+
+		addition := simulation.StorageChanges[1]
+		assert.Equal(t, address, addition.Address)
+		assert.Equal(t, "storage\x1Fc", addition.Key)
+		assert.Nil(t, addition.Before)
+		assert.NotNil(t, addition.After)
+
+		assert.Equal(t, []string{`"done"`}, simulation.Logs)
+	})
+
+	t.Run("account keys", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtimeInterface := newRuntimeInterface(t)
+
+		simulation, err := NewInterpreterRuntime().SimulateTransaction(
+			Script{
+				Source: []byte(`
+                  transaction {
+                      prepare(signer: AuthAccount) {
+                          let key = signer.keys.add(
+                              publicKey: PublicKey(
+                                  publicKey: "040506".decodeHex(),
+                                  signatureAlgorithm: SignatureAlgorithm.ECDSA_P256
+                              ),
+                              hashAlgorithm: HashAlgorithm.SHA3_256,
+                              weight: 100.0
+                          )
+                          log(key.keyIndex)
+
+                          let revokedKey = signer.keys.revoke(keyIndex: 0)!
+                          log(revokedKey.isRevoked)
+                          log(signer.keys.get(keyIndex: 0)!.isRevoked)
+                      }
+                  }
+                `),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  utils.TestLocation,
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"1", "true", "true"}, simulation.Logs)
+
+		require.Len(t, simulation.AccountKeyChanges, 2)
+
+		addition := simulation.AccountKeyChanges[0]
+		assert.Equal(t, address, addition.Address)
+		assert.False(t, addition.Revoked)
+		assert.Equal(t, 1, addition.KeyIndex)
+		assert.Equal(t, []byte{4, 5, 6}, addition.Key.PublicKey.PublicKey)
+
+		revocation := simulation.AccountKeyChanges[1]
+		assert.Equal(t, address, revocation.Address)
+		assert.True(t, revocation.Revoked)
+		assert.Equal(t, 0, revocation.KeyIndex)
+
+		eventTypeIDs := make([]string, len(simulation.Events))
+		for i, event := range simulation.Events {
+			eventTypeIDs[i] = event.EventType.ID()
+		}
+
+		assert.Equal(t,
+			[]string{
+				string(stdlib.AccountKeyAddedEventType.ID()),
+				string(stdlib.AccountKeyRemovedEventType.ID()),
+			},
+			eventTypeIDs,
+		)
+	})
+
+	t.Run("contract deployment", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtimeInterface := newRuntimeInterface(t)
+
+		contract := []byte(`
+          pub contract Test {
+              pub event Deployed()
+
+              init() {
+                  emit Deployed()
+              }
+          }
+        `)
+
+		simulation, err := NewInterpreterRuntime().SimulateTransaction(
+			Script{
+				Source: utils.DeploymentTransaction("Test", contract),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  utils.TestLocation,
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]ContractChange{
+				{
+					Address: address,
+					Name:    "Test",
+					Code:    contract,
+				},
+			},
+			simulation.ContractChanges,
+		)
+
+		require.Len(t, simulation.StorageChanges, 1)
+		assert.Equal(t, "contract\x1FTest", simulation.StorageChanges[0].Key)
+
+		eventTypeIDs := make([]string, len(simulation.Events))
+		for i, event := range simulation.Events {
+			eventTypeIDs[i] = event.EventType.ID()
+		}
+
+		assert.Equal(t,
+			[]string{
+				"A.0000000000000001.Test.Deployed",
+				string(stdlib.AccountContractAddedEventType.ID()),
+			},
+			eventTypeIDs,
+		)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtimeInterface := newRuntimeInterface(t)
+
+		simulation, err := NewInterpreterRuntime().SimulateTransaction(
+			Script{
+				Source: []byte(`
+                  transaction {
+                      prepare(signer: AuthAccount) {
+                          signer.save(1, to: /storage/a)
+                          panic("failed")
+                      }
+                  }
+                `),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  utils.TestLocation,
+			},
+		)
+		require.Error(t, err)
+		require.Nil(t, simulation)
+
+		assert.Empty(t, runtimeInterface.storage.storedValues)
+	})
+
+	t.Run("account creation", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtimeInterface := newRuntimeInterface(t)
+		runtimeInterface.createAccount = func(_ Address) (Address, error) {
+			t.Error("unexpected account creation")
+			return Address{}, nil
+		}
+		runtimeInterface.getStorageUsed = func(_ Address) (uint64, error) {
+			t.Error("unexpected storage used read")
+			return 0, nil
+		}
+
+		simulation, err := NewInterpreterRuntime().SimulateTransaction(
+			Script{
+				Source: []byte(`
+                  transaction {
+                      prepare(signer: AuthAccount) {
+                          let account = AuthAccount(payer: signer)
+                          account.save(1, to: /storage/a)
+                          log(account.storageUsed)
+                          log(account.copy<Int>(from: /storage/a))
+                      }
+                  }
+                `),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  utils.TestLocation,
+			},
+		)
+		require.NoError(t, err)
+
+		createdAddress := simulatedAccountAddress(0)
+
+		assert.Equal(t, []Address{createdAddress}, simulation.CreatedAccounts)
+		assert.Equal(t, []string{"0", "1"}, simulation.Logs)
+
+		require.Len(t, simulation.StorageChanges, 1)
+		assert.Equal(t, createdAddress, simulation.StorageChanges[0].Address)
+		assert.Nil(t, simulation.StorageChanges[0].Before)
+
+		assert.Empty(t, runtimeInterface.storage.storedValues)
+	})
+
+	t.Run("UUIDs", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtimeInterface := newRuntimeInterface(t)
+		runtimeInterface.generateUUID = func() (uint64, error) {
+			t.Error("unexpected UUID generation")
+			return 0, nil
+		}
+
+		simulationInterface := newSimulationInterface(runtimeInterface)
+
+		uuid, err := simulationInterface.GenerateUUID()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(simulatedUUIDStart), uuid)
+
+		uuid, err = simulationInterface.GenerateUUID()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(simulatedUUIDStart+1), uuid)
+	})
+
+	t.Run("host state", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtimeInterface := newRuntimeInterface(t)
+		runtimeInterface.computationLimit = 1000
+		runtimeInterface.setComputationUsed = func(_ uint64) error {
+			t.Error("unexpected computation usage report")
+			return nil
+		}
+		runtimeInterface.unsafeRandom = func() (uint64, error) {
+			t.Error("unexpected unsafe random read")
+			return 0, nil
+		}
+		runtimeInterface.readRandom = func(_ []byte) error {
+			t.Error("unexpected random read")
+			return nil
+		}
+		runtimeInterface.implementationDebugLog = func(_ string) error {
+			t.Error("unexpected implementation debug log")
+			return nil
+		}
+
+		_, err := NewInterpreterRuntime().SimulateTransaction(
+			Script{
+				Source: []byte(`
+                  transaction {
+                      prepare(signer: AuthAccount) {
+                          unsafeRandom()
+                          Random.uint64()
+                      }
+                  }
+                `),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  utils.TestLocation,
+			},
+		)
+		require.NoError(t, err)
+
+		// The runtime does not log implementation debug logs itself
+
+		err = newSimulationInterface(runtimeInterface).ImplementationDebugLog("debug")
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,150 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stdlib
+
+import (
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// This file defines the BLS functions built-in to Cadence.
+
+const blsContractTypeName = "BLS"
+
+const blsContractDocString = `
+Provides functions to aggregate BLS signatures and BLS public keys
+`
+
+const blsAggregateSignaturesFunctionName = "aggregateSignatures"
+
+const blsAggregateSignaturesFunctionDocString = `
+Aggregates multiple BLS signatures into one,
+considering the proof of possession as a defense against rogue attacks.
+
+Signatures could be generated from the same or distinct messages,
+they could also be the aggregation of other signatures.
+The order of the signatures in the slice does not matter since the aggregation is commutative.
+No subgroup membership check is performed on the input signatures.
+The function returns nil if the array is empty or if decoding one of the signatures fails.
+`
+
+var blsAggregateSignaturesFunctionType = &sema.FunctionType{
+	Parameters: []*sema.Parameter{
+		{
+			Label:      sema.ArgumentLabelNotRequired,
+			Identifier: "signatures",
+			TypeAnnotation: sema.NewTypeAnnotation(
+				&sema.VariableSizedType{
+					Type: &sema.VariableSizedType{Type: sema.UInt8Type},
+				},
+			),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		&sema.OptionalType{
+			Type: &sema.VariableSizedType{Type: sema.UInt8Type},
+		},
+	),
+}
+
+const blsAggregatePublicKeysFunctionName = "aggregatePublicKeys"
+
+const blsAggregatePublicKeysFunctionDocString = `
+Aggregates multiple BLS public keys into one.
+
+The order of the public keys in the slice does not matter since the aggregation is commutative.
+No subgroup membership check is performed on the input keys.
+The function returns nil if the array is empty or any of the input keys is not a BLS key.
+`
+
+var blsAggregatePublicKeysFunctionType = &sema.FunctionType{
+	Parameters: []*sema.Parameter{
+		{
+			Label:      sema.ArgumentLabelNotRequired,
+			Identifier: "keys",
+			TypeAnnotation: sema.NewTypeAnnotation(
+				&sema.VariableSizedType{Type: sema.PublicKeyType},
+			),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		&sema.OptionalType{
+			Type: sema.PublicKeyType,
+		},
+	),
+}
+
+var blsContractType = func() *sema.CompositeType {
+
+	ty := &sema.CompositeType{
+		Identifier: blsContractTypeName,
+		Kind:       common.CompositeKindContract,
+	}
+
+	ty.Members = sema.GetMembersAsMap([]*sema.Member{
+		sema.NewPublicFunctionMember(
+			ty,
+			blsAggregateSignaturesFunctionName,
+			blsAggregateSignaturesFunctionType,
+			blsAggregateSignaturesFunctionDocString,
+		),
+		sema.NewPublicFunctionMember(
+			ty,
+			blsAggregatePublicKeysFunctionName,
+			blsAggregatePublicKeysFunctionType,
+			blsAggregatePublicKeysFunctionDocString,
+		),
+	})
+
+	return ty
+}()
+
+var blsAggregateSignaturesFunction = interpreter.NewHostFunctionValue(
+	func(invocation interpreter.Invocation) interpreter.Value {
+		signatures := invocation.Arguments[0].(*interpreter.ArrayValue)
+
+		return invocation.Interpreter.BLSAggregateSignaturesHandler(signatures)
+	},
+)
+
+var blsAggregatePublicKeysFunction = interpreter.NewHostFunctionValue(
+	func(invocation interpreter.Invocation) interpreter.Value {
+		publicKeys := invocation.Arguments[0].(*interpreter.ArrayValue)
+
+		return invocation.Interpreter.BLSAggregatePublicKeysHandler(publicKeys)
+	},
+)
+
+var blsContractValue = newBuiltinContractValue(
+	blsContractType,
+	map[string]interpreter.FunctionValue{
+		blsAggregateSignaturesFunctionName: blsAggregateSignaturesFunction,
+		blsAggregatePublicKeysFunctionName: blsAggregatePublicKeysFunction,
+	},
+)
+
+// BLSContract is the standard library value which provides BLS aggregation.
+var BLSContract = StandardLibraryValue{
+	Name:      blsContractTypeName,
+	Type:      blsContractType,
+	DocString: blsContractDocString,
+	Value:     blsContractValue,
+	Kind:      common.DeclarationKindContract,
+}
@@ -113,12 +113,65 @@ var PanicFunction = NewStandardLibraryFunction(
 	},
 )
 
+// SavepointFunction
+
+const savepointFunctionDocString = `
+Calls the given function, and rolls back all state changes of the call if the function returns false.
+
+The rolled back state changes include changes to variables, arrays, dictionaries, composites, and storage, and events emitted by the call are discarded.
+The enclosing program continues to run after the rollback.
+
+Returns true if the state changes were kept, and false if they were rolled back.
+`
+
+var SavepointFunction = NewStandardLibraryFunction(
+	"savepoint",
+	&sema.FunctionType{
+		Parameters: []*sema.Parameter{
+			{
+				Label:      sema.ArgumentLabelNotRequired,
+				Identifier: "function",
+				TypeAnnotation: sema.NewTypeAnnotation(
+					&sema.FunctionType{
+						ReturnTypeAnnotation: sema.NewTypeAnnotation(
+							sema.BoolType,
+						),
+					},
+				),
+			},
+		},
+		ReturnTypeAnnotation: sema.NewTypeAnnotation(
+			sema.BoolType,
+		),
+	},
+	savepointFunctionDocString,
+	func(invocation interpreter.Invocation) interpreter.Value {
+		function := invocation.Arguments[0].(interpreter.FunctionValue)
+
+		committed := invocation.Interpreter.Savepoint(
+			invocation.GetLocationRange,
+			func() bool {
+				result := function.Invoke(interpreter.Invocation{
+					GetLocationRange: invocation.GetLocationRange,
+					Interpreter:      invocation.Interpreter,
+				})
+
+				return bool(result.(interpreter.BoolValue))
+			},
+		)
+
+		return interpreter.BoolValue(committed)
+	},
+)
+
 // BuiltinFunctions
 
 var BuiltinFunctions = StandardLibraryFunctions{
 	AssertFunction,
 	PanicFunction,
+	SavepointFunction,
 	CreatePublicKeyFunction,
+	ECRecoverFunction,
 }
 
 // LogFunction
@@ -177,6 +230,51 @@ var CreatePublicKeyFunction = NewStandardLibraryFunction(
 	},
 )
 
+const ecrecoverFunctionDocString = `
+Recovers the ECDSA_secp256k1 public key which produced the given signature for the given data,
+hashed with the given hash algorithm.
+
+The signature is in Ethereum's format, i.e. the concatenation r || s || v, where v is the recovery identifier.
+Returns nil if the public key cannot be recovered
+`
+
+var ECRecoverFunction = NewStandardLibraryFunction(
+	"ecrecover",
+	&sema.FunctionType{
+		Parameters: []*sema.Parameter{
+			{
+				Identifier:     "signature",
+				TypeAnnotation: sema.NewTypeAnnotation(&sema.VariableSizedType{Type: sema.UInt8Type}),
+			},
+			{
+				Identifier:     "signedData",
+				TypeAnnotation: sema.NewTypeAnnotation(&sema.VariableSizedType{Type: sema.UInt8Type}),
+			},
+			{
+				Identifier:     "hashAlgorithm",
+				TypeAnnotation: sema.NewTypeAnnotation(sema.HashAlgorithmType),
+			},
+		},
+		ReturnTypeAnnotation: sema.NewTypeAnnotation(
+			&sema.OptionalType{
+				Type: sema.PublicKeyType,
+			},
+		),
+	},
+	ecrecoverFunctionDocString,
+	func(invocation interpreter.Invocation) interpreter.Value {
+		signature := invocation.Arguments[0].(*interpreter.ArrayValue)
+		signedData := invocation.Arguments[1].(*interpreter.ArrayValue)
+		hashAlgorithm := invocation.Arguments[2].(*interpreter.CompositeValue)
+
+		return invocation.Interpreter.PublicKeyRecoveryHandler(
+			signature,
+			signedData,
+			hashAlgorithm,
+		)
+	},
+)
+
 // BuiltinValues
 
 func BuiltinValues() StandardLibraryValues {
@@ -209,6 +307,9 @@ func BuiltinValues() StandardLibraryValues {
 	return StandardLibraryValues{
 		signatureAlgorithmValue,
 		hashAlgorithmValue,
+		RLPContract,
+		BLSContract,
+		MathContract,
 	}
 }
 
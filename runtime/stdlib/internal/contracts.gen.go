@@ -1,6 +1,7 @@
 // Code generated by go-bindata. DO NOT EDIT.
 // sources:
 // contracts/crypto.cdc (4.419kB)
+// contracts/time.cdc (4.267kB)
 
 package internal
 
@@ -70,7 +71,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _contractsCryptoCdc = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\x58\x5d\x6f\xdb\x36\x17\xbe\xf7\xaf\x38\x6f\xaf\x12\xbc\xae\xe2\x01\xc3\x30\x08\x50\x83\x6e\x6b\xb7\x20\x1d\x36\x24\xcd\x72\x61\x18\x2d\x63\x1d\x4b\x84\x55\xca\x20\x8f\xec\x08\x81\xff\xfb\x40\x51\x1f\xfc\x90\x32\x67\xc8\x74\x13\xc9\x7c\xce\x27\x9f\xe7\x48\xcc\x6c\x57\x3d\xc0\xba\x14\x24\xd9\x9a\xe0\x67\x59\xef\xa8\x84\xa7\xd9\x0c\x00\x40\x2f\x6d\x2a\x01\x39\x53\xf9\xd9\x17\x48\x19\xb1\x18\x96\x77\x57\x82\x7e\x5c\xcd\x81\x15\x59\x29\x39\xe5\xdf\x62\xf8\x8d\xa9\xfc\x7d\xf7\x78\xde\x63\xe0\xa9\x71\xa3\x2f\x89\x54\x49\x31\xd8\x44\x8d\x4f\xed\xf1\xbc\xc1\x1c\xc3\x88\xf7\x9c\xf2\xcf\x2c\x0b\x03\x13\xcb\x62\xb8\x25\xc9\x45\xf6\x1a\x59\x74\x71\x74\x94\xd6\x39\xb1\x2c\x48\x4b\x91\xac\xd6\x04\xd7\x58\x7f\xe2\x8a\x3e\x08\x92\xb5\xe5\x59\x23\x0a\x24\xd8\x62\x7d\x25\x52\x7c\x8c\xe1\x4a\x50\xb0\xba\xab\x1e\x0a\xbe\xbe\xc6\x3a\x86\x3f\xbb\xdb\x00\x94\xdb\x65\x78\x55\x05\xe0\x03\xf2\x2c\xa7\x18\xee\x3e\xf2\xc7\x1f\xbe\x0f\x96\xb9\xba\xc1\x7d\xb9\xc5\x34\x86\x9f\xca\xb2\x98\xf5\x00\x2e\x38\x9d\xf5\x4f\xfa\x72\x32\x9f\x3b\x4b\x63\x69\xbb\x88\xe7\x72\x76\x91\x6e\xc2\xee\x9a\x9f\x6d\xf7\xfb\xb9\xd5\x68\x7d\x29\x2c\x36\x51\x97\x2f\x24\x7d\xea\x21\xa8\xcf\x1c\x92\xa1\x8a\x10\xe6\xa4\x0f\x89\x5b\x4e\x08\x37\x35\x40\xd2\x16\x13\x02\xfa\x42\x20\x19\x8a\xea\x61\xc7\xe7\xa9\xd5\xa9\xaf\x59\x95\x7c\xdf\x6c\x24\x0a\x92\x1c\x55\x0c\x4b\x9b\x80\x2b\x6f\x43\x47\x1b\xd5\x9a\x42\x02\xcb\x95\x95\x43\x7f\x7b\x71\x71\x01\xef\xd3\x54\x01\x03\x81\x07\xdd\x4c\x38\x70\xca\x81\x72\x84\x8c\xef\x51\xf8\x65\x76\x22\x65\x69\xea\x52\xe8\xcb\x7f\xc8\x94\x81\x0c\xb1\xaf\x41\xc7\xca\x16\x21\x24\x4e\x07\xa2\x02\x45\x46\x79\x00\xc7\xc6\x4f\xe2\xb8\x75\x0b\x03\x47\x1f\xdd\xdd\x3c\xc0\x58\xe5\xef\xc6\xcb\x1f\x69\x41\x3e\xdd\x02\xbb\x0d\xe6\x6f\xb8\x6e\x89\x66\xc3\x0a\x85\x0e\xe0\x7c\x92\x0e\x11\xdb\xed\x50\xa4\x67\x4d\xf1\x2e\xac\x9d\x92\xcd\xca\x14\x61\x6e\x1a\x8c\x6a\x48\xa2\x19\xc3\xc8\xe2\x0b\x6f\xba\x03\x7c\x03\x9c\x00\x1f\xb9\x22\x15\x79\xd6\x46\x1d\x5b\xac\x15\x30\x89\xc0\x8a\x03\xab\x55\x1b\x19\xd3\x39\x3c\x54\x8d\xc3\x1a\x72\xb6\x47\xf8\xda\x17\xf9\x15\x36\x1c\x8b\x14\x14\x12\x50\x09\x24\x2b\x0c\x78\x99\x21\x9d\x39\xd3\xcc\xa3\xcc\xa5\xa7\x12\xbe\x19\x18\xf3\x6e\x94\x32\x9e\x81\xd5\x24\xc1\x0b\x67\xe9\x38\x1b\x6b\xa5\xed\x72\xd9\xc5\x9a\x14\xe3\xef\x4c\x6e\x9f\xeb\x2c\x48\xd3\x0b\xd3\xa5\xb4\x44\x05\xa2\x24\x48\xb1\x40\x42\xe0\xa1\x50\x0d\xde\xeb\xc9\xeb\x35\xc1\x6b\x80\xfd\xa4\xe5\xb5\xae\xa4\x44\xd1\xaa\x35\xf9\xa7\x5e\x80\xc7\xd3\x01\xf2\x02\x85\xda\x21\xa3\x93\xe4\xea\x58\x9c\xae\x5d\xc7\xec\x44\x21\x3b\x36\x27\xa8\xda\xe1\x38\x38\xa2\x9e\x12\xa5\xac\x50\x6f\xe8\xc0\x1b\xc5\x33\xc1\xa8\x92\x68\xe4\xb6\x67\x05\x4f\x61\x53\x4a\x0f\x82\x69\xf3\xad\x15\x10\x68\x8f\x92\x6f\xbc\x8e\xf7\x2e\x6f\x91\x86\xd7\xd2\x6d\xf7\xeb\x6a\x1e\xa0\x31\xfd\xc5\xfe\x90\xb3\x27\xba\x7e\xdd\xfb\x93\x7c\xcf\xa4\x49\xf4\xbe\xe9\x91\xea\xde\x04\x90\xc0\x22\x5a\x84\x53\x5f\x21\x8a\xeb\x66\xaf\xf9\x5a\xbf\x29\x9f\xae\x04\x19\xcf\x47\x48\xe0\xc9\x53\xa6\x2e\xbe\x2f\x01\xb8\x70\xea\xf1\x53\x31\x0d\x86\x0f\x42\x69\x70\xa7\x4c\x23\x46\xae\x4c\x96\xa1\x05\xdf\x0c\x4e\xa3\x97\x8b\x0b\x86\xf9\x11\x0e\x76\x08\x67\x8d\x9f\x24\x57\x56\x96\x39\x33\x43\x82\x15\x12\x59\x5a\xc3\x03\xea\x2d\x47\x14\xe3\x69\x3b\xad\x5c\x86\x55\xac\xe0\xf2\xd2\x64\xf5\x7a\x89\xdf\xe0\xba\x94\xa9\xd7\xdd\x03\x53\x13\x69\x9e\x90\x63\x62\xb4\x33\x16\xec\x57\x34\x63\x95\xad\xa9\x62\x85\x0e\x18\xc2\xda\x4f\x09\x7f\x66\x8d\x44\x3a\x89\x2d\x66\x03\xda\xd9\x3d\xda\xf6\x2d\xd6\xd6\xa7\xe3\xeb\x33\x02\x6d\xc6\x3f\x47\xdb\xff\xe9\x4c\xfa\x29\x18\x8d\xc9\xbf\xdf\x86\xce\x63\x6c\x91\xbd\xbf\x0b\x07\x1b\x78\xc3\x60\xb8\x1f\xc7\xa6\xe5\x37\xc6\xc5\x2d\xee\x98\x64\xc4\x4b\xf1\x59\x9f\xcf\xcc\x31\x35\x1a\x59\xbb\x53\x28\xc7\x1d\xb9\x93\x5b\x17\x38\xfd\x95\x0f\x23\x87\x8e\xee\x7a\xe9\x06\xd8\x23\x0c\x12\xf7\xf1\xff\xcd\x96\x8f\x9c\x22\xc6\x3f\x23\x1c\xdb\x77\x09\x7c\x17\x2d\x4e\x3d\x54\xf4\x73\xf9\xc5\x67\x56\x6b\x83\xbb\xc1\xed\x60\x9a\x83\x87\x7b\x78\x1c\xb1\xf9\xf7\x47\xb8\x81\xb2\xc9\xe0\x77\xa2\xec\xee\xb4\x34\x41\x8c\xee\xbf\x06\x06\x1d\x9c\x98\x9a\x70\x13\xa6\x90\xc0\x9b\x8f\x9f\xfe\xb8\x7f\xfb\xd7\x22\x5a\xbc\xad\x14\xca\x37\x6d\xe4\xe3\xec\xef\x00\x00\x00\xff\xff\x4b\x1f\x7b\xe3\x43\x11\x00\x00"
+var _contractsCryptoCdc = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\x58\x5f\x6f\xdb\x36\x10\x7f\xf7\xa7\xb8\xf5\x29\xc6\x52\xc5\x03\x86\x61\x10\xa0\x06\xdd\xd6\x6e\x46\x3a\x6c\x88\x9b\xe5\xc1\x30\x5a\xc6\x3a\x4b\x84\x55\xca\x20\x4f\x76\x0c\xc3\xdf\x7d\x20\x25\x51\x22\x29\x7b\xee\x90\xd6\x02\x4a\xe9\x7e\xf7\xff\x0f\xc9\x8c\x36\xd5\x13\x2c\x4b\x41\x92\x2d\x09\x7e\x95\xfb\x0d\x95\x70\x18\x8d\x00\x00\x34\x69\x55\x09\xc8\x99\xca\xaf\x3e\x41\xca\x88\xc5\x30\x7f\x98\x0a\xfa\x79\x71\x0d\xac\xc8\x4a\xc9\x29\xff\x12\xc3\x1f\x4c\xe5\x6f\xdb\xd7\xb1\xc5\xc0\xc1\x88\xd1\x8f\x44\xaa\xa4\xe8\x78\x22\x23\x53\x4b\x1c\x1b\xcc\x31\xd4\xf8\xc8\x29\xff\xc8\xb2\x50\x31\xb1\x2c\x86\x19\x49\x2e\xb2\x97\xb0\xa2\xd5\xa3\x8d\x69\x84\x13\xcb\x02\xb3\x14\xc9\x6a\x49\x70\x87\xfb\x0f\x5c\xd1\x3b\x41\x72\xdf\x93\xac\x0d\x2f\x90\x60\x8d\xfb\xa9\x48\xf1\x39\x86\xa9\xa0\x80\xba\xa9\x9e\x0a\xbe\xbc\xc3\x7d\x0c\x7f\xb7\xcb\x00\x94\xf7\xdd\xf0\xbc\x0a\xc0\x3b\xe4\x59\x4e\x31\x3c\xbc\xe7\xcf\x3f\xfd\x18\x90\xb9\xba\xc7\x6d\xb9\xc6\x34\x86\x5f\xca\xb2\x18\x59\x00\x17\x9c\xae\xec\x9b\x7e\x1c\xcb\xaf\x1d\xd2\x90\xd9\x2e\xe2\x9c\xcd\x2e\xd2\x35\xd8\xa5\xf9\xd6\xb6\xdf\xc7\xbd\x40\xeb\x47\x61\xb1\x8a\x5a\x7b\x21\xb1\xa6\x87\x20\x6b\x39\x24\x60\xd7\x21\xcc\x31\x1f\x12\xd7\x9d\x10\x5e\xfb\x00\x49\xe3\x4c\x08\xb0\x8e\x40\xd2\x39\x65\x61\xc7\xf3\xa5\xd5\x76\x9f\xfe\x6d\x24\xdf\x9a\x44\xa2\x20\xc9\x51\xc5\x30\xef\x17\xe0\xc2\x4b\xe8\x60\xa0\x1a\x56\x48\x60\xbe\xb0\xd4\x46\xbb\x7e\x6e\x6e\x6e\xe0\x6d\x9a\x2a\x60\x20\x70\xa7\x83\x09\x3b\x4e\x39\x50\x8e\x90\xf1\x2d\x0a\xdf\xcd\xb6\x49\x59\x9a\xba\x25\xf4\xe9\x1b\x56\x8a\x25\x8d\x63\xbf\x07\x1d\xae\x7e\x13\x42\xe2\x44\x20\x2a\x50\x64\x94\x07\x70\x4d\xde\x43\xe2\x88\x75\x1d\x73\xfb\xa3\x5d\xb9\xe6\x7a\x8d\x62\x97\x21\xca\x0b\x81\xf3\x1a\xa2\xdb\x30\xd4\xff\x87\x74\x5b\x5f\x31\xac\x58\xa1\xd0\x01\x8c\x4f\x96\x43\xc4\x36\x1b\x14\xe9\x95\x71\xde\x85\x35\x53\xd2\x50\x4e\x15\xcc\xbd\xc1\x28\x53\x24\xba\x62\x18\xf5\xea\x85\x9b\xe8\x00\x5f\x01\x27\xc0\x67\xae\x48\x45\x1e\xb7\xb1\x58\xd7\x9a\x02\x26\x11\x58\xb1\x63\x7b\xd5\x68\xc6\xf4\x1a\x9e\x2a\x23\x70\x0f\x39\xdb\x22\x7c\xb6\x4e\x7e\x86\x15\xc7\x22\x05\x85\x04\x54\x02\xc9\x0a\x83\xba\xcc\x90\xae\xba\x6c\x4d\x05\x79\x25\x73\xeb\x75\x09\x5f\xd9\x94\xc2\x9b\xc1\x92\xf1\x18\x7a\x41\x12\xbc\x1b\x53\x5e\x98\x7a\xa8\xbe\xc8\x79\xab\xeb\x64\x33\xfe\xc9\xe4\xfa\x5c\x64\x41\xd6\x03\xa5\x8e\x52\x5a\xa2\x02\x51\x12\xa4\x58\x20\x21\xf0\xb0\x51\x6b\xbc\x17\x93\x97\x0b\x82\xf3\xf9\xe8\xbc\xe9\x6e\x5c\x56\x52\xa2\x68\xba\x35\xf9\xaf\x58\xf8\x75\xda\x41\xbe\xa2\x43\xfb\x2a\xed\x46\x71\xb6\x5d\x1d\x8e\xcb\x7b\xd7\x61\xbb\xb0\x91\x1d\x9e\x0b\xba\xda\xa9\x71\xb7\xa9\x4f\x35\xa5\xac\x50\x27\xb4\xab\x1b\xc5\x33\xc1\xa8\x92\x58\xb7\xdb\x96\x15\x3c\x85\x55\x29\x3d\x08\xa6\xe6\xac\x15\x14\xd0\x16\x25\x5f\x79\x11\xb7\x22\x67\x48\xdd\xb6\x34\x6b\xbf\x2e\x5c\x9f\x34\x1a\xd3\xdf\xfa\x07\x39\x4b\x1f\xd7\xdb\xbd\x3f\xc9\xb7\x4c\xd6\x86\x3e\x9a\x18\xa9\x76\x27\x80\x04\x26\xd1\xc4\xc5\xea\x3a\x53\x88\xe2\xce\xe4\x9a\x2f\xf5\x4e\x79\x98\x0a\xaa\x25\x1f\x21\x81\x43\x2f\x56\xfa\xd1\xce\x5b\x17\x80\xf7\x42\x34\x43\x67\x0b\x6e\x7f\x37\x37\xf0\x4e\x28\x0d\x6e\x3b\xd3\x8c\x39\xe0\xaa\xb6\x32\xe4\xe0\xab\x4e\x68\xf4\xf5\xcd\xd5\x9b\x1f\xe1\x60\xf7\xb2\x3f\x64\x24\x57\x3d\x2b\x73\x56\x0f\x09\x56\x48\x64\xe9\x1e\x9e\x50\xa7\x1c\x51\x0c\x9b\xed\x84\x72\x1e\x7a\xb1\x80\xdb\xdb\x7a\xbb\x79\x39\xc3\xef\x71\x59\xca\xd4\x8b\xee\x8e\xa9\x13\x66\x5e\x60\x63\x62\x3a\x61\x50\xd9\xef\x7a\xff\xc8\x11\xd8\x92\x2a\x56\x68\x85\x21\xac\x39\x4a\x78\xa7\x88\x21\x4d\xe7\x13\xd1\xf8\x53\x27\xa0\x99\xdd\x21\x47\xbd\x0b\xf5\x8e\x8e\x2f\x5f\x11\xd8\xd5\xe3\xf9\xb2\xfd\x6e\x8d\xbd\x29\x18\x0d\xb5\x7f\xfb\xcf\x4a\x8c\xbb\x65\x64\x57\xd7\x27\x79\xda\x61\xd0\xad\x87\xb1\x69\xf9\x85\x71\x31\xc3\x0d\x93\x8c\x78\x29\x3e\xea\xcb\x5f\x7d\x4d\x8d\x06\x68\x0f\x0a\xe5\xb0\x20\x77\x72\xaf\xd1\x9b\xd7\x01\x8f\x7f\x96\xfe\xbf\x09\xe8\x8f\x30\x48\xdc\xd7\xef\x4d\xca\x07\x6e\x11\x9e\x9c\x46\xa7\xc3\xfb\x26\x81\x1f\xa2\xc9\xa5\x97\x0a\x3b\x97\xe1\x10\x5c\x12\xcf\xdf\x59\x6d\x2e\xbb\xc1\xed\x60\xcc\xc5\xc3\x11\x71\x3d\xc0\x33\x78\x33\xb9\xe8\x0a\x67\x65\x41\xd2\xc9\x3d\xe1\x76\x7b\x5b\x3a\x51\x18\xed\x5f\x0d\x46\xa3\xc1\x1b\x93\x51\x77\x82\x15\x12\x78\xf5\xfe\xc3\x5f\x8f\xaf\xff\x99\x44\x93\xd7\x95\x42\xf9\x6a\x04\x00\x70\x1c\x1d\x47\xff\x0e\x00\x4b\x1f\x7b\xe3\x43\x11\x00\x00"
 
 func contractsCryptoCdcBytes() ([]byte, error) {
 	return bindataRead(
@@ -90,6 +91,26 @@ func contractsCryptoCdc() (*asset, error) {
 	return a, nil
 }
 
+var _contractsTimeCdc = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcc\x57\x5b\x6f\xdb\x36\x14\x7e\xf7\xaf\x38\xcb\x93\xb4\x76\x4e\x86\x05\x46\x21\xd4\x1d\x92\x0d\x03\x0a\x0c\x18\xb0\x36\xcf\x06\x2d\x1d\x59\x44\x25\xd2\xe3\x65\xb6\x51\xf8\xbf\x0f\xa4\x44\x8a\xba\xc5\xb7\x6e\x18\x92\x07\x45\xe7\xc2\xef\xfb\x74\x2e\xcc\x6c\xab\xd7\x90\x72\xa6\x04\x49\x15\x7c\xa6\x15\xc2\xd7\xd9\x0c\x00\xe0\xfe\xfe\x1e\x9e\x60\xcb\x29\x53\x40\x19\x28\x5a\xe1\x5b\x20\x12\x54\x81\xc0\x74\xb5\x46\x01\x3c\x07\x89\x29\x67\x99\x04\x49\x59\x8a\xd6\xf6\xc2\xe8\x1e\x70\xcb\xd3\xc2\xa6\x31\xf9\xa5\x12\xba\xc9\x2e\x15\xa9\xb6\xee\x08\x67\x2f\x51\xb9\x44\x09\xbc\xfc\x46\xf7\x8b\xc7\xd6\x81\x32\xaa\xa2\x9e\x35\x86\xaf\xde\x6e\x7e\x25\x96\xf9\xbc\xf1\x81\xa5\xcb\xe5\x5d\x8e\x6d\x36\xc3\xea\x4f\x54\x5a\x30\x09\x4a\x68\x04\x9a\x83\x2a\xa8\xb4\xfc\x6a\x70\x54\xc2\x1a\x73\x2e\x6a\x3a\x1b\xfa\x37\xb2\xd6\xea\x13\x19\xdc\xb9\x66\x40\xe5\xb3\x75\x8e\x56\xc0\x55\x81\x22\x69\x69\xc6\x09\x3c\x73\x5e\xf6\xb0\x0a\x7b\x7a\x17\xf2\xfb\x3a\xd6\xfd\x7d\x3d\x70\x92\x2b\x14\x67\xe2\x7e\x32\xbe\x37\xc1\xfe\xf0\xad\x60\x1b\xc0\x92\x54\xd8\x2d\x37\x57\x6d\xa7\xa8\xe0\x5f\x9a\x94\xf2\x26\x26\xcb\xe5\x85\x54\x0a\x0c\x18\xb4\x20\x33\x2d\x88\xa2\x9c\xf9\x0f\x11\x32\x1d\x00\x27\x59\x16\xad\x7c\x4c\x02\xbf\x36\x4f\x71\xc0\x61\x1c\xbc\x37\xb7\x9d\xd1\xe1\xf3\xc6\x67\x75\xaf\xe2\x9b\x29\xf9\xa6\x78\x95\x93\xd4\x6b\x3b\x4b\x2e\x25\xb6\x15\xd8\x7b\x63\x7e\xfa\x2c\xe0\xfd\xb2\x43\x34\x81\xbb\x16\xf3\x8e\xeb\x32\x83\x35\x86\xed\xdb\x4e\xa3\xbb\x4e\xf2\xe3\x35\xaa\xfe\x70\x9d\xaa\x2e\x08\x72\xc1\xab\x40\x59\x24\xa2\xa4\xa6\x4a\x3c\x03\xc5\x4f\xc9\xeb\x72\x7d\x32\xf3\x76\xbc\xe4\x9d\xd8\x67\x09\xfc\x5d\x5d\xf6\x6e\x1a\x18\x6d\xe3\x04\xee\x7a\x2d\x17\x8e\x96\x10\xde\x19\x9a\x3a\x34\x93\x92\x76\xfa\x2e\xd4\x33\x50\xd5\x28\xfa\x04\x25\xb2\x8d\x2a\xcc\xd6\xf1\xcb\x88\x0c\x57\x51\x7f\xeb\x04\x72\xfc\xef\x96\x8e\xaf\x0c\x2a\x41\x16\x5c\xd4\x0a\x13\x36\xd2\x7e\x83\x4a\xa0\xf2\x53\x1d\xf1\xb9\x20\xac\xad\x04\x47\xf7\xbf\x59\x3e\x21\x81\x92\xb3\xcd\x45\xf8\x7f\xb7\x01\xb7\xc2\xff\xf0\x8d\xe0\x93\x9a\x41\x77\xe9\x4c\xa2\xef\xef\x9c\xcb\x71\x5f\xb3\x72\xa4\xae\x80\xf7\xb1\x13\x96\x9d\x83\xb8\x5e\x36\x43\xb8\xee\x71\x1c\xf2\x89\xf6\x7d\x33\xdd\xbe\x93\x24\x32\x9a\xe7\x28\xd0\xdc\x17\x4f\x70\x71\x2d\x31\xc9\x29\x58\x36\x67\x13\x3b\x31\x07\x83\xa2\xec\x0e\xc3\xe9\x52\x0f\x08\xfc\xab\x03\x71\x42\xd1\x50\xbf\x4a\x97\x8a\x6e\x4b\x8a\x19\xac\x0f\x81\x92\x39\x49\x15\x17\x03\xfd\x1a\xf7\x43\xb4\x6a\x3c\xfc\xac\xbb\xb1\x2e\xbe\x6f\xf2\x85\xf0\x03\x12\xdd\xf9\xdb\x9e\xd5\x31\x56\x94\x69\x85\x13\xc6\x82\x6b\x31\x61\xca\xc8\x61\xc2\xb2\x43\xfc\x12\x98\x66\x43\x2d\xc3\x2b\x90\x2d\x4e\x84\x54\x0b\x81\x4c\xc1\xba\xe4\xe9\x97\xb7\x3e\x86\xce\x71\x6e\xed\xf6\x3d\xec\x0a\x9a\x16\xf6\x1f\x29\x42\x99\x0c\x03\xcb\x03\xe0\x1e\x53\xad\x30\x03\x25\x08\x93\x24\xed\x00\x33\xbd\xc9\xf8\x2e\x9a\xb8\x1b\x35\x7a\x5b\x75\x3d\xb6\x3f\xf2\x68\x83\xea\x97\xfa\x80\x67\x03\x20\x8a\xe3\xfe\xc2\x7c\x95\x55\x8d\x9a\xa8\xa0\x46\x0a\xa4\x9b\x42\xcd\x7d\xfc\xc7\x3c\x30\xd6\xfe\x19\x47\x09\x8c\x2b\xc0\x3d\x95\x75\x6c\xae\x99\xe5\xd3\x14\x86\x04\x46\xcb\x0e\x37\x1b\xe9\x99\x45\x44\x35\x07\x25\xf0\xf2\x91\xa9\xc5\x63\xc8\xfb\xe7\x80\x38\xcd\xed\x37\xb3\xe1\xb0\x84\x0d\x36\x44\x89\x4a\x9a\x0c\xf1\x78\x69\x0e\xa4\xb2\x29\xfa\x95\x18\x04\x38\xc4\xe7\x6a\x17\x28\xd2\x61\x1a\x9e\xb9\xaa\xeb\x25\x01\x0b\xfa\xf5\x6f\xeb\x4d\x6d\x33\xd9\xe0\x96\x44\xe7\xdb\xda\xbb\x49\xc8\x3d\xe8\x3c\x58\x8e\x74\xe6\x8f\xf3\x87\x96\xbd\x75\xae\x5b\x6b\xd4\x79\xf1\x30\xf0\x36\xbd\x36\xea\xfb\xd3\xe2\x61\xe8\x9d\x91\xc3\xa8\xf3\xbb\xc5\xe3\x88\xb7\x69\xc9\x51\xf7\xc5\xc3\xe3\xbb\xd6\xff\x38\x3b\xce\xfe\x19\x00\x6b\x4c\x11\x09\xab\x10\x00\x00"
+
+func contractsTimeCdcBytes() ([]byte, error) {
+	return bindataRead(
+		_contractsTimeCdc,
+		"contracts/time.cdc",
+	)
+}
+
+func contractsTimeCdc() (*asset, error) {
+	bytes, err := contractsTimeCdcBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "contracts/time.cdc", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x3e, 0x65, 0xf7, 0x71, 0x53, 0xd2, 0xf, 0xf0, 0xc6, 0x43, 0xf6, 0xf, 0xba, 0x6, 0xfe, 0xc6, 0x38, 0xe4, 0x59, 0x57, 0x3a, 0xa9, 0x2d, 0x43, 0xc9, 0x3f, 0x5c, 0x82, 0x7a, 0x93, 0xdb, 0x61}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -182,6 +203,7 @@ func AssetNames() []string {
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
 	"contracts/crypto.cdc": contractsCryptoCdc,
+	"contracts/time.cdc":   contractsTimeCdc,
 }
 
 // AssetDebug is true if the assets were built with the debug flag enabled.
@@ -230,6 +252,7 @@ type bintree struct {
 var _bintree = &bintree{nil, map[string]*bintree{
 	"contracts": {nil, map[string]*bintree{
 		"crypto.cdc": {contractsCryptoCdc, map[string]*bintree{}},
+		"time.cdc": {contractsTimeCdc, map[string]*bintree{}},
 	}},
 }}
 
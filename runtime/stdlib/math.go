@@ -0,0 +1,563 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stdlib
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/onflow/cadence/fixedpoint"
+	"github.com/onflow/cadence/runtime/common"
+	errors2 "github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// This file defines the math functions built-in to Cadence.
+
+const mathContractTypeName = "Math"
+
+const mathContractDocString = `
+Provides deterministic mathematical functions for fixed-point and integer numbers
+`
+
+var mathFixedPointTypeParameter = &sema.TypeParameter{
+	Name:      "T",
+	TypeBound: sema.FixedPointType,
+}
+
+var mathFixedPointType = &sema.GenericType{
+	TypeParameter: mathFixedPointTypeParameter,
+}
+
+var mathIntegerTypeParameter = &sema.TypeParameter{
+	Name:      "T",
+	TypeBound: sema.IntegerType,
+}
+
+var mathIntegerType = &sema.GenericType{
+	TypeParameter: mathIntegerTypeParameter,
+}
+
+const mathSqrtFunctionName = "sqrt"
+
+const mathSqrtFunctionDocString = `
+Returns the square root of the given fixed-point number, rounded to the nearest representable number.
+
+If the number is negative, the program aborts
+`
+
+var mathSqrtFunctionType = &sema.FunctionType{
+	TypeParameters: []*sema.TypeParameter{
+		mathFixedPointTypeParameter,
+	},
+	Parameters: []*sema.Parameter{
+		{
+			Label:          sema.ArgumentLabelNotRequired,
+			Identifier:     "x",
+			TypeAnnotation: sema.NewTypeAnnotation(mathFixedPointType),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(mathFixedPointType),
+}
+
+const mathLnFunctionName = "ln"
+
+const mathLnFunctionDocString = `
+Returns the natural logarithm of the given fixed-point number, rounded to the nearest representable number.
+
+If the number is not positive, the program aborts
+`
+
+var mathLnFunctionType = &sema.FunctionType{
+	TypeParameters: []*sema.TypeParameter{
+		mathFixedPointTypeParameter,
+	},
+	Parameters: []*sema.Parameter{
+		{
+			Label:          sema.ArgumentLabelNotRequired,
+			Identifier:     "x",
+			TypeAnnotation: sema.NewTypeAnnotation(mathFixedPointType),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(sema.Fix64Type),
+}
+
+const mathExpFunctionName = "exp"
+
+const mathExpFunctionDocString = `
+Returns e raised to the power of the given fixed-point number, rounded to the nearest representable number.
+
+If the result is too large, the program aborts
+`
+
+var mathExpFunctionType = &sema.FunctionType{
+	TypeParameters: []*sema.TypeParameter{
+		mathFixedPointTypeParameter,
+	},
+	Parameters: []*sema.Parameter{
+		{
+			Label:          sema.ArgumentLabelNotRequired,
+			Identifier:     "x",
+			TypeAnnotation: sema.NewTypeAnnotation(mathFixedPointType),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(sema.UFix64Type),
+}
+
+const mathPowFunctionName = "pow"
+
+const mathPowFunctionDocString = `
+Returns the given fixed-point base raised to the power of the given fixed-point exponent,
+rounded to the nearest representable number.
+
+If the exponent is not an integer and the base is negative, the program aborts.
+If the base is zero and the exponent is negative, or if the result is too large, the program aborts
+`
+
+var mathPowFunctionType = &sema.FunctionType{
+	TypeParameters: []*sema.TypeParameter{
+		mathFixedPointTypeParameter,
+	},
+	Parameters: []*sema.Parameter{
+		{
+			Label:          sema.ArgumentLabelNotRequired,
+			Identifier:     "base",
+			TypeAnnotation: sema.NewTypeAnnotation(mathFixedPointType),
+		},
+		{
+			Label:          sema.ArgumentLabelNotRequired,
+			Identifier:     "exponent",
+			TypeAnnotation: sema.NewTypeAnnotation(sema.FixedPointType),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(mathFixedPointType),
+}
+
+const mathPowIntegerFunctionName = "powInteger"
+
+const mathPowIntegerFunctionDocString = `
+Returns the given integer base raised to the power of the given integer exponent.
+
+If the exponent is negative, or if the result does not fit into the type of the base, the program aborts.
+Word types wrap around on overflow, like their multiplication does.
+The result for the types Int and UInt may be at most 4096 bits long
+`
+
+var mathPowIntegerFunctionType = &sema.FunctionType{
+	TypeParameters: []*sema.TypeParameter{
+		mathIntegerTypeParameter,
+	},
+	Parameters: []*sema.Parameter{
+		{
+			Label:          sema.ArgumentLabelNotRequired,
+			Identifier:     "base",
+			TypeAnnotation: sema.NewTypeAnnotation(mathIntegerType),
+		},
+		{
+			Label:          sema.ArgumentLabelNotRequired,
+			Identifier:     "exponent",
+			TypeAnnotation: sema.NewTypeAnnotation(sema.IntegerType),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(mathIntegerType),
+}
+
+var mathContractType = func() *sema.CompositeType {
+
+	ty := &sema.CompositeType{
+		Identifier: mathContractTypeName,
+		Kind:       common.CompositeKindContract,
+	}
+
+	ty.Members = sema.GetMembersAsMap([]*sema.Member{
+		sema.NewPublicFunctionMember(
+			ty,
+			mathSqrtFunctionName,
+			mathSqrtFunctionType,
+			mathSqrtFunctionDocString,
+		),
+		sema.NewPublicFunctionMember(
+			ty,
+			mathLnFunctionName,
+			mathLnFunctionType,
+			mathLnFunctionDocString,
+		),
+		sema.NewPublicFunctionMember(
+			ty,
+			mathExpFunctionName,
+			mathExpFunctionType,
+			mathExpFunctionDocString,
+		),
+		sema.NewPublicFunctionMember(
+			ty,
+			mathPowFunctionName,
+			mathPowFunctionType,
+			mathPowFunctionDocString,
+		),
+		sema.NewPublicFunctionMember(
+			ty,
+			mathPowIntegerFunctionName,
+			mathPowIntegerFunctionType,
+			mathPowIntegerFunctionDocString,
+		),
+	})
+
+	return ty
+}()
+
+// MathError
+
+type MathError struct {
+	FunctionName string
+	Err          error
+	interpreter.LocationRange
+}
+
+func (e MathError) Unwrap() error {
+	return e.Err
+}
+
+func (e MathError) Error() string {
+	return fmt.Sprintf("%s.%s failed: %s", mathContractTypeName, e.FunctionName, e.Err.Error())
+}
+
+var errNegativeExponent = errors.New("exponent is negative")
+
+// maxPowIntegerResultBitLength is the maximum bit length of a result
+// of an integer power for the arbitrary precision integer types Int and UInt
+//
+const maxPowIntegerResultBitLength = 4096
+
+// panicMathError panics with the interpreter error corresponding to the given error.
+//
+// Overflows and divisions by zero are reported like the errors of the arithmetic operators.
+//
+func panicMathError(functionName string, err error, getLocationRange func() interpreter.LocationRange) {
+	switch err {
+	case fixedpoint.ErrOverflow:
+		panic(interpreter.OverflowError{})
+
+	case fixedpoint.ErrDivisionByZero:
+		panic(interpreter.DivisionByZeroError{})
+
+	default:
+		panic(MathError{
+			FunctionName:  functionName,
+			Err:           err,
+			LocationRange: getLocationRange(),
+		})
+	}
+}
+
+func fixedPointValueToBigInt(value interpreter.Value) *big.Int {
+	switch value := value.(type) {
+	case interpreter.Fix64Value:
+		return big.NewInt(int64(value))
+
+	case interpreter.UFix64Value:
+		return new(big.Int).SetUint64(uint64(value))
+
+	default:
+		panic(errors2.NewUnreachableError())
+	}
+}
+
+func newFix64ValueFromBigInt(value *big.Int) interpreter.Fix64Value {
+	if !value.IsInt64() {
+		if value.Sign() < 0 {
+			panic(interpreter.UnderflowError{})
+		}
+		panic(interpreter.OverflowError{})
+	}
+	return interpreter.Fix64Value(value.Int64())
+}
+
+func newUFix64ValueFromBigInt(value *big.Int) interpreter.UFix64Value {
+	if value.Sign() < 0 {
+		panic(interpreter.UnderflowError{})
+	} else if !value.IsUint64() {
+		panic(interpreter.OverflowError{})
+	}
+	return interpreter.UFix64Value(value.Uint64())
+}
+
+// newFixedPointValueFromBigInt returns a fixed-point value
+// of the same type as the given fixed-point value.
+//
+func newFixedPointValueFromBigInt(value *big.Int, sameTypeValue interpreter.Value) interpreter.Value {
+	switch sameTypeValue.(type) {
+	case interpreter.Fix64Value:
+		return newFix64ValueFromBigInt(value)
+
+	case interpreter.UFix64Value:
+		return newUFix64ValueFromBigInt(value)
+
+	default:
+		panic(errors2.NewUnreachableError())
+	}
+}
+
+var mathSqrtFunction = interpreter.NewHostFunctionValue(
+	func(invocation interpreter.Invocation) interpreter.Value {
+		x := invocation.Arguments[0]
+
+		result, err := fixedpoint.Sqrt(fixedPointValueToBigInt(x))
+		if err != nil {
+			panicMathError(mathSqrtFunctionName, err, invocation.GetLocationRange)
+		}
+
+		return newFixedPointValueFromBigInt(result, x)
+	},
+)
+
+var mathLnFunction = interpreter.NewHostFunctionValue(
+	func(invocation interpreter.Invocation) interpreter.Value {
+		x := invocation.Arguments[0]
+
+		result, err := fixedpoint.Ln(fixedPointValueToBigInt(x))
+		if err != nil {
+			panicMathError(mathLnFunctionName, err, invocation.GetLocationRange)
+		}
+
+		return newFix64ValueFromBigInt(result)
+	},
+)
+
+var mathExpFunction = interpreter.NewHostFunctionValue(
+	func(invocation interpreter.Invocation) interpreter.Value {
+		x := invocation.Arguments[0]
+
+		result, err := fixedpoint.Exp(fixedPointValueToBigInt(x))
+		if err != nil {
+			panicMathError(mathExpFunctionName, err, invocation.GetLocationRange)
+		}
+
+		return newUFix64ValueFromBigInt(result)
+	},
+)
+
+var mathPowFunction = interpreter.NewHostFunctionValue(
+	func(invocation interpreter.Invocation) interpreter.Value {
+		base := invocation.Arguments[0]
+		exponent := invocation.Arguments[1]
+
+		result, err := fixedpoint.Pow(
+			fixedPointValueToBigInt(base),
+			fixedPointValueToBigInt(exponent),
+		)
+		if err != nil {
+			panicMathError(mathPowFunctionName, err, invocation.GetLocationRange)
+		}
+
+		return newFixedPointValueFromBigInt(result, base)
+	},
+)
+
+func integerValueToBigInt(value interpreter.Value) *big.Int {
+	switch value := value.(type) {
+	case interpreter.BigNumberValue:
+		return value.ToBigInt()
+
+	case interpreter.UInt64Value:
+		return new(big.Int).SetUint64(uint64(value))
+
+	case interpreter.Word64Value:
+		return new(big.Int).SetUint64(uint64(value))
+
+	case interpreter.NumberValue:
+		return big.NewInt(int64(value.ToInt()))
+
+	default:
+		panic(errors2.NewUnreachableError())
+	}
+}
+
+// powInteger returns base ^ exponent as a value of the same type as the base.
+//
+func powInteger(base interpreter.Value, exponent *big.Int) interpreter.Value {
+
+	var bitSize uint
+	var wraps bool
+
+	switch base.(type) {
+	case interpreter.IntValue, interpreter.UIntValue:
+		bitSize = maxPowIntegerResultBitLength
+	case interpreter.Int8Value, interpreter.UInt8Value:
+		bitSize = 8
+	case interpreter.Int16Value, interpreter.UInt16Value:
+		bitSize = 16
+	case interpreter.Int32Value, interpreter.UInt32Value:
+		bitSize = 32
+	case interpreter.Int64Value, interpreter.UInt64Value:
+		bitSize = 64
+	case interpreter.Int128Value, interpreter.UInt128Value:
+		bitSize = 128
+	case interpreter.Int256Value, interpreter.UInt256Value:
+		bitSize = 256
+	case interpreter.Word8Value:
+		bitSize = 8
+		wraps = true
+	case interpreter.Word16Value:
+		bitSize = 16
+		wraps = true
+	case interpreter.Word32Value:
+		bitSize = 32
+		wraps = true
+	case interpreter.Word64Value:
+		bitSize = 64
+		wraps = true
+	default:
+		panic(errors2.NewUnreachableError())
+	}
+
+	bigBase := integerValueToBigInt(base)
+
+	var result *big.Int
+
+	if wraps {
+		modulus := new(big.Int).Lsh(big.NewInt(1), bitSize)
+		result = new(big.Int).Exp(bigBase, exponent, modulus)
+	} else {
+
+		// The result has at least exponent * (bitLength(|base|) - 1) bits.
+		// Abort early instead of computing a result which does not fit.
+
+		baseBitLength := new(big.Int).Abs(bigBase).BitLen()
+		if baseBitLength > 1 {
+			minResultBitLength := new(big.Int).Mul(
+				exponent,
+				big.NewInt(int64(baseBitLength-1)),
+			)
+			if minResultBitLength.Cmp(new(big.Int).SetUint64(uint64(bitSize))) > 0 {
+				if bigBase.Sign() < 0 && exponent.Bit(0) == 1 {
+					panic(interpreter.UnderflowError{})
+				}
+				panic(interpreter.OverflowError{})
+			}
+		}
+
+		result = new(big.Int).Exp(bigBase, exponent, nil)
+	}
+
+	resultValue := interpreter.NewIntValueFromBigInt(result)
+
+	switch base.(type) {
+	case interpreter.IntValue:
+		if result.BitLen() > maxPowIntegerResultBitLength {
+			if result.Sign() < 0 {
+				panic(interpreter.UnderflowError{})
+			}
+			panic(interpreter.OverflowError{})
+		}
+		return resultValue
+	case interpreter.UIntValue:
+		if result.BitLen() > maxPowIntegerResultBitLength {
+			panic(interpreter.OverflowError{})
+		}
+		return interpreter.NewUIntValueFromBigInt(result)
+	default:
+		return newIntegerValueFromBigInt(result, base)
+	}
+}
+
+// newIntegerValueFromBigInt returns an integer value
+// of the same type as the given integer value.
+//
+// If the value does not fit into the type, the program aborts.
+//
+func newIntegerValueFromBigInt(value *big.Int, sameTypeValue interpreter.Value) interpreter.Value {
+
+	resultValue := interpreter.NewIntValueFromBigInt(value)
+
+	switch sameTypeValue.(type) {
+	case interpreter.IntValue:
+		return resultValue
+	case interpreter.UIntValue:
+		return interpreter.ConvertUInt(resultValue)
+	case interpreter.Int8Value:
+		return interpreter.ConvertInt8(resultValue)
+	case interpreter.Int16Value:
+		return interpreter.ConvertInt16(resultValue)
+	case interpreter.Int32Value:
+		return interpreter.ConvertInt32(resultValue)
+	case interpreter.Int64Value:
+		return interpreter.ConvertInt64(resultValue)
+	case interpreter.Int128Value:
+		return interpreter.ConvertInt128(resultValue)
+	case interpreter.Int256Value:
+		return interpreter.ConvertInt256(resultValue)
+	case interpreter.UInt8Value:
+		return interpreter.ConvertUInt8(resultValue)
+	case interpreter.UInt16Value:
+		return interpreter.ConvertUInt16(resultValue)
+	case interpreter.UInt32Value:
+		return interpreter.ConvertUInt32(resultValue)
+	case interpreter.UInt64Value:
+		return interpreter.ConvertUInt64(resultValue)
+	case interpreter.UInt128Value:
+		return interpreter.ConvertUInt128(resultValue)
+	case interpreter.UInt256Value:
+		return interpreter.ConvertUInt256(resultValue)
+	case interpreter.Word8Value:
+		return interpreter.ConvertWord8(resultValue)
+	case interpreter.Word16Value:
+		return interpreter.ConvertWord16(resultValue)
+	case interpreter.Word32Value:
+		return interpreter.ConvertWord32(resultValue)
+	case interpreter.Word64Value:
+		return interpreter.ConvertWord64(resultValue)
+	default:
+		panic(errors2.NewUnreachableError())
+	}
+}
+
+var mathPowIntegerFunction = interpreter.NewHostFunctionValue(
+	func(invocation interpreter.Invocation) interpreter.Value {
+		base := invocation.Arguments[0]
+		exponent := integerValueToBigInt(invocation.Arguments[1])
+
+		if exponent.Sign() < 0 {
+			panicMathError(mathPowIntegerFunctionName, errNegativeExponent, invocation.GetLocationRange)
+		}
+
+		return powInteger(base, exponent)
+	},
+)
+
+var mathContractValue = newBuiltinContractValue(
+	mathContractType,
+	map[string]interpreter.FunctionValue{
+		mathSqrtFunctionName:       mathSqrtFunction,
+		mathLnFunctionName:         mathLnFunction,
+		mathExpFunctionName:        mathExpFunction,
+		mathPowFunctionName:        mathPowFunction,
+		mathPowIntegerFunctionName: mathPowIntegerFunction,
+	},
+)
+
+// MathContract is the standard library value which provides math functions.
+var MathContract = StandardLibraryValue{
+	Name:      mathContractTypeName,
+	Type:      mathContractType,
+	DocString: mathContractDocString,
+	Value:     mathContractValue,
+	Kind:      common.DeclarationKindContract,
+}
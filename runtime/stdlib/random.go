@@ -0,0 +1,299 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stdlib
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// This file defines the random functions built-in to Cadence.
+
+// RandomSource reads random bytes into the given buffer.
+//
+// The random bytes must be deterministic, i.e. the same for all executions of a transaction,
+// and must not be predictable before the transaction is executed.
+// If the random bytes cannot be read, the function panics.
+//
+type RandomSource func(buffer []byte)
+
+const randomContractTypeName = "Random"
+
+const randomContractDocString = `
+Provides random numbers from the random source of the execution environment.
+
+The random numbers are deterministic: All executions of the same transaction get the same random numbers.
+The random numbers cannot be predicted before the transaction is executed.
+However, a transaction can abort after observing a random number, e.g. if the outcome is unfavorable.
+Use a commit-reveal scheme, i.e. commit to an action in one transaction and reveal its random outcome in a later one
+`
+
+var randomIntegerTypeParameter = &sema.TypeParameter{
+	Name:      "T",
+	TypeBound: sema.IntegerType,
+}
+
+var randomIntegerType = &sema.GenericType{
+	TypeParameter: randomIntegerTypeParameter,
+}
+
+var randomElementTypeParameter = &sema.TypeParameter{
+	Name:      "T",
+	TypeBound: sema.AnyStructType,
+}
+
+var randomArrayType = &sema.VariableSizedType{
+	Type: &sema.GenericType{
+		TypeParameter: randomElementTypeParameter,
+	},
+}
+
+const randomUInt64FunctionName = "uint64"
+
+const randomUInt64FunctionDocString = `
+Returns a random number, uniformly distributed over all UInt64 values
+`
+
+var randomUInt64FunctionType = &sema.FunctionType{
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(sema.UInt64Type),
+}
+
+const randomRangeFunctionName = "range"
+
+const randomRangeFunctionDocString = `
+Returns a random integer which is greater than or equal to the given lower bound, and less than the given upper bound.
+The integers in the range are equally likely, and the result has the type of the lower bound.
+
+If the upper bound is not greater than the lower bound, the program aborts.
+If the range contains integers which do not fit into the type of the lower bound, the program aborts
+`
+
+var randomRangeFunctionType = &sema.FunctionType{
+	TypeParameters: []*sema.TypeParameter{
+		randomIntegerTypeParameter,
+	},
+	Parameters: []*sema.Parameter{
+		{
+			Identifier:     "from",
+			TypeAnnotation: sema.NewTypeAnnotation(randomIntegerType),
+		},
+		{
+			Identifier:     "upTo",
+			TypeAnnotation: sema.NewTypeAnnotation(sema.IntegerType),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(randomIntegerType),
+}
+
+const randomShuffleFunctionName = "shuffle"
+
+const randomShuffleFunctionDocString = `
+Returns a copy of the given array, with the elements in a random order.
+All orders are equally likely
+`
+
+var randomShuffleFunctionType = &sema.FunctionType{
+	TypeParameters: []*sema.TypeParameter{
+		randomElementTypeParameter,
+	},
+	Parameters: []*sema.Parameter{
+		{
+			Label:          sema.ArgumentLabelNotRequired,
+			Identifier:     "array",
+			TypeAnnotation: sema.NewTypeAnnotation(randomArrayType),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(randomArrayType),
+}
+
+var randomContractType = func() *sema.CompositeType {
+
+	ty := &sema.CompositeType{
+		Identifier: randomContractTypeName,
+		Kind:       common.CompositeKindContract,
+	}
+
+	ty.Members = sema.GetMembersAsMap([]*sema.Member{
+		sema.NewPublicFunctionMember(
+			ty,
+			randomUInt64FunctionName,
+			randomUInt64FunctionType,
+			randomUInt64FunctionDocString,
+		),
+		sema.NewPublicFunctionMember(
+			ty,
+			randomRangeFunctionName,
+			randomRangeFunctionType,
+			randomRangeFunctionDocString,
+		),
+		sema.NewPublicFunctionMember(
+			ty,
+			randomShuffleFunctionName,
+			randomShuffleFunctionType,
+			randomShuffleFunctionDocString,
+		),
+	})
+
+	return ty
+}()
+
+// RandomError
+
+type RandomError struct {
+	FunctionName string
+	Err          error
+	interpreter.LocationRange
+}
+
+func (e RandomError) Unwrap() error {
+	return e.Err
+}
+
+func (e RandomError) Error() string {
+	return fmt.Sprintf("%s.%s failed: %s", randomContractTypeName, e.FunctionName, e.Err.Error())
+}
+
+var errEmptyRange = errors.New("upper bound is not greater than lower bound")
+
+// randomBigInt returns a random integer which is greater than or equal to zero,
+// and less than the given positive bound.
+//
+// Random numbers are rejected until one is in the range,
+// so all integers in the range are equally likely,
+// unlike when reducing a random number modulo the bound.
+// Each random number is in the range with a probability of more than 1/2.
+//
+func randomBigInt(source RandomSource, bound *big.Int) *big.Int {
+	max := new(big.Int).Sub(bound, big.NewInt(1))
+
+	bitLength := max.BitLen()
+	if bitLength == 0 {
+		return max
+	}
+
+	buffer := make([]byte, (bitLength+7)/8)
+
+	// The excess bits of the first byte are cleared,
+	// so the random number has the bit length of the maximum
+
+	excessBits := uint(len(buffer)*8 - bitLength)
+
+	result := new(big.Int)
+
+	for {
+		source(buffer)
+		buffer[0] &= 0xff >> excessBits
+
+		result.SetBytes(buffer)
+		if result.Cmp(max) <= 0 {
+			return result
+		}
+	}
+}
+
+func newRandomUInt64Function(source RandomSource) *interpreter.HostFunctionValue {
+	return interpreter.NewHostFunctionValue(
+		func(invocation interpreter.Invocation) interpreter.Value {
+			buffer := make([]byte, 8)
+			source(buffer)
+
+			return interpreter.UInt64Value(new(big.Int).SetBytes(buffer).Uint64())
+		},
+	)
+}
+
+func newRandomRangeFunction(source RandomSource) *interpreter.HostFunctionValue {
+	return interpreter.NewHostFunctionValue(
+		func(invocation interpreter.Invocation) interpreter.Value {
+			from := invocation.Arguments[0]
+			upTo := invocation.Arguments[1]
+
+			bigFrom := integerValueToBigInt(from)
+			bigUpTo := integerValueToBigInt(upTo)
+
+			bound := new(big.Int).Sub(bigUpTo, bigFrom)
+
+			if bound.Sign() <= 0 {
+				panic(RandomError{
+					FunctionName:  randomRangeFunctionName,
+					Err:           errEmptyRange,
+					LocationRange: invocation.GetLocationRange(),
+				})
+			}
+
+			// Abort independent of the random number if the range does not fit into the type
+
+			_ = newIntegerValueFromBigInt(new(big.Int).Sub(bigUpTo, big.NewInt(1)), from)
+
+			result := randomBigInt(source, bound)
+			result.Add(result, bigFrom)
+
+			return newIntegerValueFromBigInt(result, from)
+		},
+	)
+}
+
+func newRandomShuffleFunction(source RandomSource) *interpreter.HostFunctionValue {
+	return interpreter.NewHostFunctionValue(
+		func(invocation interpreter.Invocation) interpreter.Value {
+			array := invocation.Arguments[0].(*interpreter.ArrayValue)
+
+			elements := array.Elements()
+
+			values := make([]interpreter.Value, len(elements))
+			for i, element := range elements {
+				values[i] = element.Copy()
+			}
+
+			// Fisher-Yates shuffle
+
+			for i := len(values) - 1; i > 0; i-- {
+				j := randomBigInt(source, big.NewInt(int64(i+1))).Int64()
+				values[i], values[j] = values[j], values[i]
+			}
+
+			return interpreter.NewArrayValueUnownedNonCopying(values...)
+		},
+	)
+}
+
+// NewRandomContract returns the standard library value which provides random numbers
+// from the given random source.
+//
+func NewRandomContract(source RandomSource) StandardLibraryValue {
+	return StandardLibraryValue{
+		Name:      randomContractTypeName,
+		Type:      randomContractType,
+		DocString: randomContractDocString,
+		Value: newBuiltinContractValue(
+			randomContractType,
+			map[string]interpreter.FunctionValue{
+				randomUInt64FunctionName:  newRandomUInt64Function(source),
+				randomRangeFunctionName:   newRandomRangeFunction(source),
+				randomShuffleFunctionName: newRandomShuffleFunction(source),
+			},
+		),
+		Kind: common.DeclarationKindContract,
+	}
+}
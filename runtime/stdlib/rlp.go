@@ -0,0 +1,206 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stdlib
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib/rlp"
+)
+
+// This file defines the RLP decoding functions built-in to Cadence.
+
+const rlpContractTypeName = "RLP"
+
+const rlpContractDocString = `
+Provides functions to decode Recursive Length Prefix (RLP) encoded data
+`
+
+const rlpDecodeStringFunctionName = "decodeString"
+
+const rlpDecodeStringFunctionDocString = `
+Decodes an RLP-encoded byte array (called string in the context of RLP).
+The byte array should only contain of a single encoded value for a string;
+if the encoded value type does not match, or it has trailing unnecessary bytes, the program aborts.
+If any error is encountered while decoding, the program aborts.
+`
+
+var rlpDecodeStringFunctionType = &sema.FunctionType{
+	Parameters: []*sema.Parameter{
+		{
+			Label:      sema.ArgumentLabelNotRequired,
+			Identifier: "input",
+			TypeAnnotation: sema.NewTypeAnnotation(
+				&sema.VariableSizedType{Type: sema.UInt8Type},
+			),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		&sema.VariableSizedType{Type: sema.UInt8Type},
+	),
+}
+
+const rlpDecodeListFunctionName = "decodeList"
+
+const rlpDecodeListFunctionDocString = `
+Decodes an RLP-encoded list into an array of RLP-encoded items.
+Note that this function does not recursively decode, so each element of the resulting array is RLP-encoded data.
+The byte array should only contain of a single encoded value for a list;
+if the encoded value type does not match, or it has trailing unnecessary bytes, the program aborts.
+If any error is encountered while decoding, the program aborts.
+`
+
+var rlpDecodeListFunctionType = &sema.FunctionType{
+	Parameters: []*sema.Parameter{
+		{
+			Label:      sema.ArgumentLabelNotRequired,
+			Identifier: "input",
+			TypeAnnotation: sema.NewTypeAnnotation(
+				&sema.VariableSizedType{Type: sema.UInt8Type},
+			),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		&sema.VariableSizedType{
+			Type: &sema.VariableSizedType{Type: sema.UInt8Type},
+		},
+	),
+}
+
+var rlpContractType = func() *sema.CompositeType {
+
+	ty := &sema.CompositeType{
+		Identifier: rlpContractTypeName,
+		Kind:       common.CompositeKindContract,
+	}
+
+	ty.Members = sema.GetMembersAsMap([]*sema.Member{
+		sema.NewPublicFunctionMember(
+			ty,
+			rlpDecodeStringFunctionName,
+			rlpDecodeStringFunctionType,
+			rlpDecodeStringFunctionDocString,
+		),
+		sema.NewPublicFunctionMember(
+			ty,
+			rlpDecodeListFunctionName,
+			rlpDecodeListFunctionType,
+			rlpDecodeListFunctionDocString,
+		),
+	})
+
+	return ty
+}()
+
+// RLPDecodeStringError
+
+type RLPDecodeStringError struct {
+	Msg string
+	interpreter.LocationRange
+}
+
+func (e RLPDecodeStringError) Error() string {
+	return fmt.Sprintf("failed to RLP-decode string: %s", e.Msg)
+}
+
+// RLPDecodeListError
+
+type RLPDecodeListError struct {
+	Msg string
+	interpreter.LocationRange
+}
+
+func (e RLPDecodeListError) Error() string {
+	return fmt.Sprintf("failed to RLP-decode list: %s", e.Msg)
+}
+
+var rlpDecodeStringFunction = interpreter.NewHostFunctionValue(
+	func(invocation interpreter.Invocation) interpreter.Value {
+		input, err := interpreter.ByteArrayValueToByteSlice(invocation.Arguments[0])
+		if err != nil {
+			panic(RLPDecodeStringError{
+				Msg:           err.Error(),
+				LocationRange: invocation.GetLocationRange(),
+			})
+		}
+
+		output, bytesRead, err := rlp.DecodeString(input, 0)
+		if err == nil && bytesRead != len(input) {
+			err = rlp.ErrTrailingData
+		}
+		if err != nil {
+			panic(RLPDecodeStringError{
+				Msg:           err.Error(),
+				LocationRange: invocation.GetLocationRange(),
+			})
+		}
+
+		return interpreter.ByteSliceToByteArrayValue(output)
+	},
+)
+
+var rlpDecodeListFunction = interpreter.NewHostFunctionValue(
+	func(invocation interpreter.Invocation) interpreter.Value {
+		input, err := interpreter.ByteArrayValueToByteSlice(invocation.Arguments[0])
+		if err != nil {
+			panic(RLPDecodeListError{
+				Msg:           err.Error(),
+				LocationRange: invocation.GetLocationRange(),
+			})
+		}
+
+		output, bytesRead, err := rlp.DecodeList(input, 0)
+		if err == nil && bytesRead != len(input) {
+			err = rlp.ErrTrailingData
+		}
+		if err != nil {
+			panic(RLPDecodeListError{
+				Msg:           err.Error(),
+				LocationRange: invocation.GetLocationRange(),
+			})
+		}
+
+		values := make([]interpreter.Value, len(output))
+		for i, b := range output {
+			values[i] = interpreter.ByteSliceToByteArrayValue(b)
+		}
+
+		return interpreter.NewArrayValueUnownedNonCopying(values...)
+	},
+)
+
+var rlpContractValue = newBuiltinContractValue(
+	rlpContractType,
+	map[string]interpreter.FunctionValue{
+		rlpDecodeStringFunctionName: rlpDecodeStringFunction,
+		rlpDecodeListFunctionName:   rlpDecodeListFunction,
+	},
+)
+
+// RLPContract is the standard library value which provides RLP decoding.
+var RLPContract = StandardLibraryValue{
+	Name:      rlpContractTypeName,
+	Type:      rlpContractType,
+	DocString: rlpContractDocString,
+	Value:     rlpContractValue,
+	Kind:      common.DeclarationKindContract,
+}
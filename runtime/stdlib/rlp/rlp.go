@@ -0,0 +1,222 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package rlp implements decoding of Recursive Length Prefix (RLP) encoded data,
+// as specified in the appendix B of the Ethereum Yellow Paper.
+//
+// Only canonical encodings are accepted.
+//
+package rlp
+
+import (
+	"errors"
+)
+
+const (
+	ByteRangeStart        = 0x00
+	ByteRangeEnd          = 0x7f
+	ShortStringRangeStart = 0x80
+	ShortStringRangeEnd   = 0xb7
+	LongStringRangeStart  = 0xb8
+	LongStringRangeEnd    = 0xbf
+	ShortListRangeStart   = 0xc0
+	ShortListRangeEnd     = 0xf7
+	LongListRangeStart    = 0xf8
+	LongListRangeEnd      = 0xff
+
+	// MaxShortLengthAllowed is the maximum length of a payload
+	// which is encoded using the short form.
+	MaxShortLengthAllowed = 55
+
+	// MaxLongLengthAllowed is the maximum number of bytes
+	// used to encode the length of a payload in the long form.
+	MaxLongLengthAllowed = 8
+)
+
+var (
+	ErrEmptyInput        = errors.New("input data is empty")
+	ErrInvalidStartIndex = errors.New("invalid start index")
+	ErrIncompleteInput   = errors.New("incomplete input")
+	ErrNonCanonicalInput = errors.New("non-canonical encoded input")
+	ErrDataSizeTooLarge  = errors.New("data size is larger than what is supported")
+	ErrTypeMismatch      = errors.New("type of encoded data does not match")
+	ErrTrailingData      = errors.New("input data has trailing bytes")
+)
+
+// ReadSize reads the header of the item at the given start index of the input.
+//
+// It returns whether the item is a string (as opposed to a list),
+// the index at which the item's payload starts, and the size of the payload.
+//
+func ReadSize(input []byte, startIndex int) (isString bool, dataStartIndex int, dataSize int, err error) {
+	if len(input) == 0 {
+		return false, 0, 0, ErrEmptyInput
+	}
+
+	if startIndex < 0 || startIndex >= len(input) {
+		return false, 0, 0, ErrInvalidStartIndex
+	}
+
+	firstByte := input[startIndex]
+	startIndex++
+
+	// single byte, its own encoding
+
+	if firstByte <= ByteRangeEnd {
+		return true, startIndex - 1, 1, nil
+	}
+
+	// short string or short list: the size is encoded in the first byte
+
+	if firstByte <= ShortStringRangeEnd ||
+		(firstByte >= ShortListRangeStart && firstByte <= ShortListRangeEnd) {
+
+		isString = firstByte <= ShortStringRangeEnd
+
+		var rangeStart byte
+		if isString {
+			rangeStart = ShortStringRangeStart
+		} else {
+			rangeStart = ShortListRangeStart
+		}
+
+		dataSize = int(firstByte - rangeStart)
+
+		// a single byte in the byte range must be encoded as itself
+
+		if isString && dataSize == 1 {
+			if startIndex >= len(input) {
+				return false, 0, 0, ErrIncompleteInput
+			}
+			if input[startIndex] <= ByteRangeEnd {
+				return false, 0, 0, ErrNonCanonicalInput
+			}
+		}
+
+		return isString, startIndex, dataSize, nil
+	}
+
+	// long string or long list: the first byte encodes the size of the size
+
+	isString = firstByte <= LongStringRangeEnd
+
+	var rangeStart byte
+	if isString {
+		rangeStart = LongStringRangeStart
+	} else {
+		rangeStart = LongListRangeStart
+	}
+
+	bytesToReadForLen := int(firstByte-rangeStart) + 1
+
+	if bytesToReadForLen > MaxLongLengthAllowed {
+		return false, 0, 0, ErrDataSizeTooLarge
+	}
+
+	endIndex := startIndex + bytesToReadForLen
+	if endIndex > len(input) {
+		return false, 0, 0, ErrIncompleteInput
+	}
+
+	// the size must not have leading zeros
+
+	if input[startIndex] == 0 {
+		return false, 0, 0, ErrNonCanonicalInput
+	}
+
+	var size uint64
+	for _, b := range input[startIndex:endIndex] {
+		size = size<<8 | uint64(b)
+	}
+
+	// sizes which fit the short form must use the short form
+
+	if size <= MaxShortLengthAllowed {
+		return false, 0, 0, ErrNonCanonicalInput
+	}
+
+	if size > uint64(len(input)) {
+		return false, 0, 0, ErrIncompleteInput
+	}
+
+	return isString, endIndex, int(size), nil
+}
+
+// DecodeString decodes the string item at the given start index of the input.
+//
+// It returns the string's payload and the index after the item.
+//
+func DecodeString(input []byte, startIndex int) (str []byte, nextStartIndex int, err error) {
+	isString, dataStartIndex, dataSize, err := ReadSize(input, startIndex)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if !isString {
+		return nil, 0, ErrTypeMismatch
+	}
+
+	nextStartIndex = dataStartIndex + dataSize
+	if nextStartIndex > len(input) {
+		return nil, 0, ErrIncompleteInput
+	}
+
+	return input[dataStartIndex:nextStartIndex], nextStartIndex, nil
+}
+
+// DecodeList decodes the list item at the given start index of the input.
+//
+// It returns the encoded items of the list and the index after the list.
+// The items are not decoded, so nested lists can be decoded lazily
+// by the caller using DecodeString or DecodeList.
+//
+func DecodeList(input []byte, startIndex int) (encodedItems [][]byte, nextStartIndex int, err error) {
+	isString, dataStartIndex, dataSize, err := ReadSize(input, startIndex)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if isString {
+		return nil, 0, ErrTypeMismatch
+	}
+
+	nextStartIndex = dataStartIndex + dataSize
+	if nextStartIndex > len(input) {
+		return nil, 0, ErrIncompleteInput
+	}
+
+	encodedItems = make([][]byte, 0)
+
+	itemStartIndex := dataStartIndex
+	for itemStartIndex < nextStartIndex {
+		_, itemDataStartIndex, itemSize, err := ReadSize(input, itemStartIndex)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		itemEndIndex := itemDataStartIndex + itemSize
+		if itemEndIndex > nextStartIndex {
+			return nil, 0, ErrIncompleteInput
+		}
+
+		encodedItems = append(encodedItems, input[itemStartIndex:itemEndIndex])
+		itemStartIndex = itemEndIndex
+	}
+
+	return encodedItems, nextStartIndex, nil
+}
@@ -0,0 +1,158 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rlp
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeString(t *testing.T) {
+
+	t.Parallel()
+
+	longString := bytes.Repeat([]byte{0xaa}, 56)
+
+	type testCase struct {
+		name     string
+		input    []byte
+		expected []byte
+		err      error
+	}
+
+	testCases := []testCase{
+		{"empty input", []byte{}, nil, ErrEmptyInput},
+		{"single byte", []byte{0x01}, []byte{0x01}, nil},
+		{"max single byte", []byte{0x7f}, []byte{0x7f}, nil},
+		{"empty string", []byte{0x80}, []byte{}, nil},
+		{"short string", []byte{0x83, 'd', 'o', 'g'}, []byte("dog"), nil},
+		{"single byte as short string", []byte{0x81, 0x80}, []byte{0x80}, nil},
+		{"non-canonical single byte", []byte{0x81, 0x01}, nil, ErrNonCanonicalInput},
+		{"incomplete short string", []byte{0x83, 'd', 'o'}, nil, ErrIncompleteInput},
+		{"long string", append([]byte{0xb8, 56}, longString...), longString, nil},
+		{"non-canonical long string", append([]byte{0xb8, 3}, 'd', 'o', 'g'), nil, ErrNonCanonicalInput},
+		{"long string size with leading zero", append([]byte{0xb9, 0, 56}, longString...), nil, ErrNonCanonicalInput},
+		{"incomplete long string", append([]byte{0xb8, 57}, longString...), nil, ErrIncompleteInput},
+		{"list", []byte{0xc0}, nil, ErrTypeMismatch},
+	}
+
+	for _, testCase := range testCases {
+
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+
+			t.Parallel()
+
+			str, _, err := DecodeString(testCase.input, 0)
+			if testCase.err != nil {
+				require.Equal(t, testCase.err, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, str)
+		})
+	}
+}
+
+func TestDecodeList(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("empty list", func(t *testing.T) {
+
+		t.Parallel()
+
+		items, next, err := DecodeList([]byte{0xc0}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{}, items)
+		assert.Equal(t, 1, next)
+	})
+
+	t.Run("list of strings", func(t *testing.T) {
+
+		t.Parallel()
+
+		// ["cat", "dog"]
+		input := []byte{0xc8, 0x83, 'c', 'a', 't', 0x83, 'd', 'o', 'g'}
+
+		items, next, err := DecodeList(input, 0)
+		require.NoError(t, err)
+		assert.Equal(t,
+			[][]byte{
+				{0x83, 'c', 'a', 't'},
+				{0x83, 'd', 'o', 'g'},
+			},
+			items,
+		)
+		assert.Equal(t, len(input), next)
+	})
+
+	t.Run("nested lists", func(t *testing.T) {
+
+		t.Parallel()
+
+		// [ [], [[]], [ [], [[]] ] ]
+		input := []byte{0xc7, 0xc0, 0xc1, 0xc0, 0xc3, 0xc0, 0xc1, 0xc0}
+
+		items, _, err := DecodeList(input, 0)
+		require.NoError(t, err)
+		require.Len(t, items, 3)
+
+		nested, _, err := DecodeList(items[2], 0)
+		require.NoError(t, err)
+		assert.Equal(t,
+			[][]byte{
+				{0xc0},
+				{0xc1, 0xc0},
+			},
+			nested,
+		)
+	})
+
+	t.Run("item exceeds list", func(t *testing.T) {
+
+		t.Parallel()
+
+		input := []byte{0xc2, 0x83, 'c', 'a', 't'}
+
+		_, _, err := DecodeList(input, 0)
+		require.Equal(t, ErrIncompleteInput, err)
+	})
+
+	t.Run("string", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, _, err := DecodeList([]byte{0x80}, 0)
+		require.Equal(t, ErrTypeMismatch, err)
+	})
+
+	t.Run("invalid start index", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, _, err := DecodeList([]byte{0xc0}, 1)
+		require.Equal(t, ErrInvalidStartIndex, err)
+	})
+}
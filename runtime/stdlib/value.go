@@ -92,3 +92,23 @@ func (values StandardLibraryValues) ToInterpreterValueDeclarations() []interpret
 	}
 	return valueDeclarations
 }
+
+// newBuiltinContractValue returns the value of a built-in contract,
+// i.e. a contract which has no location and is implemented by host functions.
+//
+func newBuiltinContractValue(
+	contractType *sema.CompositeType,
+	functions map[string]interpreter.FunctionValue,
+) *interpreter.CompositeValue {
+	value := interpreter.NewCompositeValue(
+		contractType.Location,
+		contractType.QualifiedIdentifier(),
+		contractType.Kind,
+		nil,
+		nil,
+	)
+
+	value.Functions = functions
+
+	return value
+}
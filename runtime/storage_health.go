@@ -0,0 +1,462 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=StorageIssueKind
+
+// StorageIssueKind is the kind of a problem found in storage by a health check.
+//
+type StorageIssueKind uint
+
+const (
+	StorageIssueKindUnknown StorageIssueKind = iota
+	// StorageIssueKindUndecodable indicates that the stored data cannot be decoded
+	StorageIssueKindUndecodable
+	// StorageIssueKindOwnerMismatch indicates that a stored value,
+	// or a value nested in it, is owned by another account
+	StorageIssueKindOwnerMismatch
+	// StorageIssueKindMissingDeferredValue indicates that a stored dictionary
+	// refers to a deferred value which is not stored
+	StorageIssueKindMissingDeferredValue
+	// StorageIssueKindOrphanedDeferredValue indicates that a deferred value is stored,
+	// but no stored dictionary refers to it
+	StorageIssueKindOrphanedDeferredValue
+)
+
+// StorageIssue is a problem found in storage by a health check.
+//
+type StorageIssue struct {
+	Kind StorageIssueKind
+	StorageKey
+	// Err is the cause of the issue, if any, e.g. the decoding error
+	Err error
+	// Repaired is true if the issue was repaired
+	Repaired bool
+}
+
+func (i StorageIssue) String() string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf(
+		"%s: %s %q",
+		i.Kind,
+		i.Address.ShortHexWithPrefix(),
+		i.Key,
+	))
+	if i.Err != nil {
+		builder.WriteString(": ")
+		builder.WriteString(i.Err.Error())
+	}
+	if i.Repaired {
+		builder.WriteString(" (repaired)")
+	}
+	return builder.String()
+}
+
+// StorageHealthReport is the result of a storage health check.
+//
+type StorageHealthReport struct {
+	// Accounts is the number of checked accounts
+	Accounts int
+	// Keys is the number of checked storage keys
+	Keys   int
+	Issues []StorageIssue
+}
+
+// Healthy returns true if no issues were found.
+//
+func (r *StorageHealthReport) Healthy() bool {
+	return len(r.Issues) == 0
+}
+
+// AccountIterator iterates over the accounts which should be checked,
+// e.g. all accounts of the state.
+//
+type AccountIterator interface {
+	// NextAccount returns the next account.
+	// If there are no more accounts, ok is false.
+	NextAccount() (address Address, ok bool, err error)
+}
+
+type addressesIterator struct {
+	addresses []Address
+}
+
+// NewAddressesIterator returns an AccountIterator for the given addresses.
+//
+func NewAddressesIterator(addresses ...Address) AccountIterator {
+	return &addressesIterator{
+		addresses: addresses,
+	}
+}
+
+func (i *addressesIterator) NextAccount() (Address, bool, error) {
+	if len(i.addresses) == 0 {
+		return Address{}, false, nil
+	}
+	address := i.addresses[0]
+	i.addresses = i.addresses[1:]
+	return address, true, nil
+}
+
+// storageHealthCheckDomains are the first path elements
+// of the storage keys which contain Cadence values.
+// Other storage keys, e.g. those used by the host, are ignored.
+//
+var storageHealthCheckDomains = map[string]bool{
+	common.PathDomainStorage.Identifier(): true,
+	common.PathDomainPrivate.Identifier(): true,
+	common.PathDomainPublic.Identifier():  true,
+	"contract":                            true,
+}
+
+// checkStorageHealth checks all values stored in the given accounts:
+// It verifies that each stored value, including all deferred values, can be decoded,
+// that all values are owned by the account they are stored in,
+// and that deferred values of dictionaries are stored where they are expected.
+//
+// If repair is true, undecodable values and orphaned deferred values are removed.
+// Missing deferred values and owner mismatches are only reported.
+//
+func checkStorageHealth(
+	runtimeInterface Interface,
+	accounts AccountIterator,
+	repair bool,
+) (
+	*StorageHealthReport,
+	error,
+) {
+	report := &StorageHealthReport{}
+
+	for {
+		var address Address
+		var ok bool
+		var err error
+		wrapPanic(func() {
+			address, ok, err = accounts.NextAccount()
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+
+		err = checkAccountStorageHealth(runtimeInterface, address, repair, report)
+		if err != nil {
+			return nil, err
+		}
+
+		report.Accounts++
+	}
+
+	return report, nil
+}
+
+func checkAccountStorageHealth(
+	runtimeInterface Interface,
+	address Address,
+	repair bool,
+	report *StorageHealthReport,
+) error {
+
+	var rawKeys [][]byte
+	var err error
+	wrapPanic(func() {
+		rawKeys, err = runtimeInterface.GetStorageKeys(address[:], nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Split the keys into the keys of top-level values,
+	// and the keys of deferred values, i.e. values nested in dictionaries
+
+	var topLevelKeys []StorageKey
+	deferredKeys := map[string]bool{}
+
+	for _, rawKey := range rawKeys {
+		key := string(rawKey)
+
+		elements := strings.Split(key, "\x1F")
+		if len(elements) < 2 || !storageHealthCheckDomains[elements[0]] {
+			continue
+		}
+
+		if len(elements) == 2 {
+			topLevelKeys = append(topLevelKeys, StorageKey{
+				Address: address,
+				Key:     key,
+			})
+		} else {
+			deferredKeys[key] = true
+		}
+	}
+
+	sort.Slice(topLevelKeys, func(i, j int) bool {
+		return topLevelKeys[i].Key < topLevelKeys[j].Key
+	})
+
+	checker := &storageHealthChecker{
+		runtimeInterface: runtimeInterface,
+		address:          address,
+		storedKeys:       deferredKeys,
+		referencedKeys:   map[string]bool{},
+		report:           report,
+		repair:           repair,
+	}
+
+	var data [][]byte
+	wrapPanic(func() {
+		data, err = runtimeInterface.GetValues(topLevelKeys)
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, key := range topLevelKeys {
+		err = checker.checkStoredValue(key.Key, data[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	// All deferred values which are not referenced by any dictionary are orphaned
+
+	orphanedKeys := make([]string, 0, len(deferredKeys))
+	for key := range deferredKeys {
+		if !checker.referencedKeys[key] {
+			orphanedKeys = append(orphanedKeys, key)
+		}
+	}
+	sort.Strings(orphanedKeys)
+
+	for _, key := range orphanedKeys {
+		checker.reportIssue(StorageIssueKindOrphanedDeferredValue, key, nil)
+	}
+
+	report.Keys += len(topLevelKeys) + len(deferredKeys)
+
+	if len(checker.removals) == 0 {
+		return nil
+	}
+
+	wrapPanic(func() {
+		err = runtimeInterface.SetValues(checker.removals)
+	})
+	return err
+}
+
+type storageHealthChecker struct {
+	runtimeInterface Interface
+	address          Address
+	// storedKeys are the keys of the deferred values which are stored
+	storedKeys map[string]bool
+	// referencedKeys are the keys of the deferred values which are referenced by dictionaries
+	referencedKeys map[string]bool
+	report         *StorageHealthReport
+	repair         bool
+	// removals are the writes which remove broken values, if the checker repairs
+	removals []StorageWrite
+}
+
+// reportIssue reports an issue for the value stored under the given key.
+// Undecodable and orphaned values are removed, if the checker repairs.
+//
+func (c *storageHealthChecker) reportIssue(kind StorageIssueKind, key string, err error) {
+
+	repaired := false
+
+	switch kind {
+	case StorageIssueKindUndecodable, StorageIssueKindOrphanedDeferredValue:
+		if c.repair {
+			c.removals = append(c.removals, StorageWrite{
+				StorageKey: StorageKey{
+					Address: c.address,
+					Key:     key,
+				},
+			})
+			repaired = true
+		}
+	}
+
+	c.report.Issues = append(
+		c.report.Issues,
+		StorageIssue{
+			Kind: kind,
+			StorageKey: StorageKey{
+				Address: c.address,
+				Key:     key,
+			},
+			Err:      err,
+			Repaired: repaired,
+		},
+	)
+}
+
+// checkStoredValue checks the value with the given data, stored under the given key,
+// and all deferred values it refers to.
+//
+func (c *storageHealthChecker) checkStoredValue(key string, data []byte) (err error) {
+
+	data, version := interpreter.StripMagic(data)
+	if len(data) == 0 {
+		return nil
+	}
+
+	value, err := c.decodeValue(key, data, version)
+	if err != nil {
+		c.reportIssue(StorageIssueKindUndecodable, key, err)
+		return nil
+	}
+
+	// Check the owners of all values and gather the deferred values,
+	// which are checked below
+
+	walker := &storageHealthWalker{
+		address: c.address,
+	}
+
+	err = walkDecodedValue(walker, value)
+	if err != nil {
+		c.reportIssue(StorageIssueKindUndecodable, key, err)
+		return nil
+	}
+
+	if walker.ownerMismatch != nil {
+		c.reportIssue(
+			StorageIssueKindOwnerMismatch,
+			key,
+			fmt.Errorf(
+				"value is owned by %s",
+				walker.ownerMismatch.ShortHexWithPrefix(),
+			),
+		)
+	}
+
+	for _, deferredKey := range walker.deferredKeys {
+		c.referencedKeys[deferredKey] = true
+
+		if !c.storedKeys[deferredKey] {
+			c.reportIssue(StorageIssueKindMissingDeferredValue, deferredKey, nil)
+			continue
+		}
+
+		var deferredData []byte
+		wrapPanic(func() {
+			deferredData, err = c.runtimeInterface.GetValue(c.address[:], []byte(deferredKey))
+		})
+		if err != nil {
+			return err
+		}
+
+		err = c.checkStoredValue(deferredKey, deferredData)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *storageHealthChecker) decodeValue(key string, data []byte, version uint16) (value interpreter.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	address := c.address
+
+	return interpreter.DecodeValue(
+		data,
+		&address,
+		[]string{key},
+		version,
+		nil,
+	)
+}
+
+// walkDecodedValue walks the given value.
+// Values are decoded lazily, so walking the value forces the decoding of all nested values,
+// which might fail.
+//
+func walkDecodedValue(walker interpreter.ValueWalker, value interpreter.Value) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if recoveredErr, ok := r.(error); ok {
+				err = recoveredErr
+			} else {
+				err = fmt.Errorf("%v", r)
+			}
+		}
+	}()
+
+	interpreter.WalkValue(walker, value)
+
+	return nil
+}
+
+// storageHealthWalker is a value walker which checks the owners of all values,
+// and gathers the keys of the deferred values of all dictionaries
+//
+type storageHealthWalker struct {
+	address       Address
+	ownerMismatch *common.Address
+	deferredKeys  []string
+}
+
+func (w *storageHealthWalker) WalkValue(value interpreter.Value) interpreter.ValueWalker {
+	if value == nil {
+		return nil
+	}
+
+	if owner := value.GetOwner(); owner != nil && *owner != w.address && w.ownerMismatch == nil {
+		w.ownerMismatch = owner
+	}
+
+	if dictionary, ok := value.(*interpreter.DictionaryValue); ok {
+		deferredKeys := dictionary.DeferredKeys()
+		if deferredKeys != nil {
+
+			if owner := dictionary.DeferredOwner(); owner != nil && *owner != w.address && w.ownerMismatch == nil {
+				w.ownerMismatch = owner
+			}
+
+			keyBase := dictionary.DeferredStorageKeyBase()
+
+			for pair := deferredKeys.Oldest(); pair != nil; pair = pair.Next() {
+				w.deferredKeys = append(
+					w.deferredKeys,
+					strings.Join([]string{keyBase, pair.Key}, "\x1F"),
+				)
+			}
+		}
+	}
+
+	return w
+}
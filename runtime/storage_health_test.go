@@ -0,0 +1,239 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestRuntimeCheckStorageHealth(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0xCA, 0xDE})
+
+	setup := func(t *testing.T) (Runtime, *testRuntimeInterface) {
+
+		runtime := NewInterpreterRuntime()
+
+		deploy := utils.DeploymentTransaction("Test", []byte(simpleDeferralContract))
+
+		setupTx := []byte(`
+          import Test from 0xCADE
+
+          transaction {
+
+              prepare(signer: AuthAccount) {
+                  let c <- Test.createC()
+                  destroy c.insert("a", <-Test.createR(1))
+                  destroy c.insert("b", <-Test.createR(2))
+                  signer.save(<-c, to: /storage/c)
+                  signer.save(42, to: /storage/number)
+                  signer.link<&Test.C>(/public/c, target: /storage/c)
+              }
+           }
+        `)
+
+		var accountCode []byte
+
+		runtimeInterface := &testRuntimeInterface{
+			resolveLocation: singleIdentifierLocationResolver(t),
+			getAccountContractCode: func(_ Address, _ string) ([]byte, error) {
+				return accountCode, nil
+			},
+			storage: newTestStorage(nil, nil),
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{address}, nil
+			},
+			updateAccountContractCode: func(_ Address, _ string, code []byte) error {
+				accountCode = code
+				return nil
+			},
+			emitEvent: func(event cadence.Event) error {
+				return nil
+			},
+		}
+
+		nextTransactionLocation := newTransactionLocationGenerator()
+
+		for _, tx := range [][]byte{deploy, setupTx} {
+			err := runtime.ExecuteTransaction(
+				Script{
+					Source: tx,
+				},
+				Context{
+					Interface: runtimeInterface,
+					Location:  nextTransactionLocation(),
+				},
+			)
+			require.NoError(t, err)
+		}
+
+		return runtime, runtimeInterface
+	}
+
+	storedKeys := func(runtimeInterface *testRuntimeInterface) []string {
+		keys, err := runtimeInterface.GetStorageKeys(address[:], nil)
+		require.NoError(t, err)
+
+		result := make([]string, len(keys))
+		for i, key := range keys {
+			result[i] = string(key)
+		}
+		sort.Strings(result)
+		return result
+	}
+
+	checkHealth := func(t *testing.T, runtime Runtime, runtimeInterface *testRuntimeInterface, repair bool) *StorageHealthReport {
+		report, err := runtime.CheckStorageHealth(
+			NewAddressesIterator(address),
+			repair,
+			Context{
+				Interface: runtimeInterface,
+			},
+		)
+		require.NoError(t, err)
+		return report
+	}
+
+	deferredKey := strings.Join([]string{"storage", "c", "rs", "v", "a"}, "\x1F")
+
+	t.Run("healthy", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime, runtimeInterface := setup(t)
+
+		require.Contains(t, storedKeys(runtimeInterface), deferredKey)
+
+		report := checkHealth(t, runtime, runtimeInterface, false)
+
+		assert.True(t, report.Healthy())
+		assert.Equal(t, 1, report.Accounts)
+		assert.Equal(t, len(storedKeys(runtimeInterface)), report.Keys)
+	})
+
+	t.Run("undecodable", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime, runtimeInterface := setup(t)
+
+		const brokenKey = "storage\x1Fbroken"
+
+		err := runtimeInterface.SetValue(address[:], []byte(brokenKey), []byte{0x1, 0x2, 0x3})
+		require.NoError(t, err)
+
+		// Keys of the host are ignored
+
+		err = runtimeInterface.SetValue(address[:], []byte("exists"), []byte{0x1})
+		require.NoError(t, err)
+
+		report := checkHealth(t, runtime, runtimeInterface, false)
+
+		require.Len(t, report.Issues, 1)
+
+		issue := report.Issues[0]
+		assert.Equal(t, StorageIssueKindUndecodable, issue.Kind)
+		assert.Equal(t, brokenKey, issue.Key)
+		assert.Error(t, issue.Err)
+		assert.False(t, issue.Repaired)
+
+		require.Contains(t, storedKeys(runtimeInterface), brokenKey)
+
+		// Repair
+
+		report = checkHealth(t, runtime, runtimeInterface, true)
+
+		require.Len(t, report.Issues, 1)
+		assert.True(t, report.Issues[0].Repaired)
+
+		assert.NotContains(t, storedKeys(runtimeInterface), brokenKey)
+
+		report = checkHealth(t, runtime, runtimeInterface, false)
+		assert.True(t, report.Healthy())
+	})
+
+	t.Run("missing deferred value", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime, runtimeInterface := setup(t)
+
+		err := runtimeInterface.SetValue(address[:], []byte(deferredKey), nil)
+		require.NoError(t, err)
+
+		report := checkHealth(t, runtime, runtimeInterface, true)
+
+		require.Len(t, report.Issues, 1)
+
+		issue := report.Issues[0]
+		assert.Equal(t, StorageIssueKindMissingDeferredValue, issue.Kind)
+		assert.Equal(t, deferredKey, issue.Key)
+		assert.False(t, issue.Repaired)
+	})
+
+	t.Run("orphaned deferred value", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime, runtimeInterface := setup(t)
+
+		// Remove the resource which has the dictionary,
+		// but not the dictionary's deferred values
+
+		err := runtimeInterface.SetValue(address[:], []byte("storage\x1Fc"), nil)
+		require.NoError(t, err)
+
+		report := checkHealth(t, runtime, runtimeInterface, false)
+
+		kinds := map[StorageIssueKind]int{}
+		for _, issue := range report.Issues {
+			kinds[issue.Kind]++
+		}
+
+		assert.Equal(t,
+			map[StorageIssueKind]int{
+				StorageIssueKindOrphanedDeferredValue: 2,
+			},
+			kinds,
+		)
+
+		// Repair
+
+		report = checkHealth(t, runtime, runtimeInterface, true)
+		require.Len(t, report.Issues, 2)
+
+		report = checkHealth(t, runtime, runtimeInterface, false)
+		assert.True(t, report.Healthy())
+
+		for _, key := range storedKeys(runtimeInterface) {
+			assert.Equal(t, 1, strings.Count(key, "\x1F"), key)
+		}
+	})
+}
@@ -128,6 +128,124 @@ func TestRuntimeStorageWriteCachedIsDeterministic(t *testing.T) {
 	})
 }
 
+func TestRuntimeStorageWriteCachedBatch(t *testing.T) {
+
+	t.Parallel()
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestStorage(nil, nil),
+	}
+
+	var batches [][]StorageWrite
+
+	runtimeInterface.storage.setValues = func(writes []StorageWrite) error {
+		batches = append(batches, writes)
+		return nil
+	}
+
+	runtimeStorage := newRuntimeStorage(runtimeInterface)
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	const storageItemCount = 100
+
+	for i := 0; i < storageItemCount; i++ {
+		runtimeStorage.cache[StorageKey{
+			Address: address,
+			Key:     strconv.Itoa(i),
+		}] = CacheEntry{
+			MustWrite: true,
+			Value:     interpreter.NewIntValueFromInt64(int64(i)),
+		}
+	}
+
+	err := runtimeStorage.writeCached(nil)
+	require.NoError(t, err)
+
+	// All values are written in one batch
+
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0], storageItemCount)
+
+	// Writing again does not write anything,
+	// as the values are not modified
+
+	batches = nil
+
+	for key, entry := range runtimeStorage.cache {
+		entry.MustWrite = false
+		runtimeStorage.cache[key] = entry
+	}
+
+	err = runtimeStorage.writeCached(nil)
+	require.NoError(t, err)
+
+	require.Empty(t, batches)
+}
+
+func TestRuntimeStoragePrefetch(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	var reads []string
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestStorage(
+			func(_, key, _ []byte) {
+				reads = append(reads, string(key))
+			},
+			nil,
+		),
+	}
+
+	runtimeStorage := newRuntimeStorage(runtimeInterface)
+
+	// Store some values
+
+	for _, key := range []string{"a", "b"} {
+		runtimeStorage.cache[StorageKey{
+			Address: address,
+			Key:     key,
+		}] = CacheEntry{
+			MustWrite: true,
+			Value:     interpreter.NewStringValue(key),
+		}
+	}
+
+	err := runtimeStorage.writeCached(nil)
+	require.NoError(t, err)
+
+	runtimeStorage = newRuntimeStorage(runtimeInterface)
+
+	// Prefetch the values, and a missing value.
+	// All keys are read in one batch
+
+	runtimeStorage.prefetch(address, []string{"a", "b", "c"})
+
+	require.Equal(t, []string{"a", "b", "c"}, reads)
+
+	// Reading the values does not read from storage again
+
+	reads = nil
+
+	assert.Equal(t,
+		interpreter.NewSomeValueOwningNonCopying(interpreter.NewStringValue("a")),
+		runtimeStorage.readValue(address, "a", true),
+	)
+	assert.True(t, runtimeStorage.valueExists(address, "b"))
+	assert.False(t, runtimeStorage.valueExists(address, "c"))
+
+	assert.Empty(t, reads)
+
+	// Prefetching again does not read from storage again
+
+	runtimeStorage.prefetch(address, []string{"a", "b", "c"})
+
+	assert.Empty(t, reads)
+}
+
 func BenchmarkRuntimeStorageWriteCached(b *testing.B) {
 	var writes []testWrite
 
@@ -2235,3 +2353,494 @@ func TestRuntimeStorageRecursiveReference(t *testing.T) {
 
 	require.Contains(t, err.Error(), "cannot write non-storable value")
 }
+
+func TestRuntimeStorageIteration(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	newRuntimeInterface := func(storage testRuntimeInterfaceStorage, loggedMessages *[]string) *testRuntimeInterface {
+		return &testRuntimeInterface{
+			storage: storage,
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{address}, nil
+			},
+			log: func(message string) {
+				*loggedMessages = append(*loggedMessages, message)
+			},
+		}
+	}
+
+	const setupTx = `
+      pub resource R {}
+
+      transaction {
+          prepare(signer: AuthAccount) {
+              signer.save(2, to: /storage/b)
+              signer.save("a", to: /storage/a)
+              signer.save(<-{"r": <-create R()}, to: /storage/rs)
+              signer.save([1, "2"] as [AnyStruct], to: /storage/xs)
+              signer.link<&Int>(/public/b, target: /storage/b)
+              signer.link<&String>(/private/a, target: /storage/a)
+          }
+      }
+    `
+
+	setup := func(t *testing.T) testRuntimeInterfaceStorage {
+		storage := newTestStorage(nil, nil)
+
+		var loggedMessages []string
+
+		err := NewInterpreterRuntime().ExecuteTransaction(
+			Script{
+				Source: []byte(setupTx),
+			},
+			Context{
+				Interface: newRuntimeInterface(storage, &loggedMessages),
+				Location:  utils.TestLocation,
+			},
+		)
+		require.NoError(t, err)
+
+		return storage
+	}
+
+	execute := func(t *testing.T, storage testRuntimeInterfaceStorage, code string) []string {
+
+		var loggedMessages []string
+
+		err := NewInterpreterRuntime().ExecuteTransaction(
+			Script{
+				Source: []byte(code),
+			},
+			Context{
+				Interface: newRuntimeInterface(storage, &loggedMessages),
+				Location:  common.TransactionLocation{0x2},
+			},
+		)
+		require.NoError(t, err)
+
+		return loggedMessages
+	}
+
+	t.Run("forEachStored", func(t *testing.T) {
+
+		t.Parallel()
+
+		storage := setup(t)
+
+		loggedMessages := execute(t, storage, `
+          transaction {
+              prepare(signer: AuthAccount) {
+                  signer.forEachStored(fun (path: StoragePath, type: Type): Bool {
+                      log(path)
+                      log(type)
+                      return true
+                  })
+              }
+          }
+        `)
+
+		assert.Equal(t,
+			[]string{
+				"/storage/a",
+				"Type<String>()",
+				"/storage/b",
+				"Type<Int>()",
+				// Like for getType, arrays and dictionaries have no static type yet
+				"/storage/rs",
+				"Type()",
+				"/storage/xs",
+				"Type()",
+			},
+			loggedMessages,
+		)
+	})
+
+	t.Run("forEachStored, stop early", func(t *testing.T) {
+
+		t.Parallel()
+
+		storage := setup(t)
+
+		loggedMessages := execute(t, storage, `
+          transaction {
+              prepare(signer: AuthAccount) {
+                  signer.forEachStored(fun (path: StoragePath, type: Type): Bool {
+                      log(path)
+                      return false
+                  })
+              }
+          }
+        `)
+
+		assert.Equal(t, []string{"/storage/a"}, loggedMessages)
+	})
+
+	t.Run("forEachStored, unwritten changes", func(t *testing.T) {
+
+		t.Parallel()
+
+		storage := setup(t)
+
+		loggedMessages := execute(t, storage, `
+          transaction {
+              prepare(signer: AuthAccount) {
+                  signer.load<Int>(from: /storage/b)
+                  signer.save(true, to: /storage/c)
+
+                  signer.forEachStored(fun (path: StoragePath, type: Type): Bool {
+                      log(path)
+                      return true
+                  })
+              }
+          }
+        `)
+
+		assert.Equal(t,
+			[]string{"/storage/a", "/storage/c", "/storage/rs", "/storage/xs"},
+			loggedMessages,
+		)
+	})
+
+	t.Run("forEachStored, mutation during iteration", func(t *testing.T) {
+
+		t.Parallel()
+
+		storage := setup(t)
+
+		loggedMessages := execute(t, storage, `
+          transaction {
+              prepare(signer: AuthAccount) {
+                  var first = true
+                  signer.forEachStored(fun (path: StoragePath, type: Type): Bool {
+                      log(path)
+                      if first {
+                          signer.load<Int>(from: /storage/b)
+                          signer.save(true, to: /storage/c)
+                          first = false
+                      }
+                      return true
+                  })
+              }
+          }
+        `)
+
+		assert.Equal(t,
+			[]string{"/storage/a", "/storage/rs", "/storage/xs"},
+			loggedMessages,
+		)
+	})
+
+	t.Run("forEachPublic", func(t *testing.T) {
+
+		t.Parallel()
+
+		storage := setup(t)
+
+		loggedMessages := execute(t, storage, `
+          transaction {
+              prepare(signer: AuthAccount) {
+                  signer.forEachPublic(fun (path: PublicPath, type: Type): Bool {
+                      log(path)
+                      log(type)
+                      return true
+                  })
+
+                  getAccount(signer.address).forEachPublic(fun (path: PublicPath, type: Type): Bool {
+                      log(path)
+                      return true
+                  })
+              }
+          }
+        `)
+
+		assert.Equal(t,
+			[]string{
+				"/public/b",
+				"Type<&Int>()",
+				"/public/b",
+			},
+			loggedMessages,
+		)
+	})
+
+	t.Run("metering", func(t *testing.T) {
+
+		t.Parallel()
+
+		storage := setup(t)
+
+		const computationLimit = 5
+
+		runtimeInterface := newRuntimeInterface(storage, new([]string))
+		runtimeInterface.computationLimit = computationLimit
+
+		err := NewInterpreterRuntime().ExecuteTransaction(
+			Script{
+				Source: []byte(`
+                  transaction {
+                      prepare(signer: AuthAccount) {
+                          signer.forEachStored(fun (path: StoragePath, type: Type): Bool {
+                              return true
+                          })
+                      }
+                  }
+                `),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.TransactionLocation{0x2},
+			},
+		)
+
+		var computationLimitErr ComputationLimitExceededError
+		require.ErrorAs(t, err, &computationLimitErr)
+	})
+}
+
+func TestRuntimeStorageLinkAuditing(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	newRuntimeInterface := func(storage testRuntimeInterfaceStorage, loggedMessages *[]string) *testRuntimeInterface {
+		return &testRuntimeInterface{
+			storage: storage,
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{address}, nil
+			},
+			log: func(message string) {
+				*loggedMessages = append(*loggedMessages, message)
+			},
+		}
+	}
+
+	const setupTx = `
+      transaction {
+          prepare(signer: AuthAccount) {
+              signer.save("a", to: /storage/a)
+              signer.save(2, to: /storage/b)
+              signer.link<&String>(/public/a, target: /storage/a)
+              signer.link<&Int>(/public/b, target: /private/b)
+              signer.link<&Int>(/private/b, target: /storage/b)
+              signer.link<&Int>(/private/c, target: /storage/b)
+              signer.link<&Int>(/public/cycle1, target: /public/cycle2)
+              signer.link<&Int>(/public/cycle2, target: /public/cycle1)
+          }
+      }
+    `
+
+	setup := func(t *testing.T) testRuntimeInterfaceStorage {
+		storage := newTestStorage(nil, nil)
+
+		var loggedMessages []string
+
+		err := NewInterpreterRuntime().ExecuteTransaction(
+			Script{
+				Source: []byte(setupTx),
+			},
+			Context{
+				Interface: newRuntimeInterface(storage, &loggedMessages),
+				Location:  utils.TestLocation,
+			},
+		)
+		require.NoError(t, err)
+
+		return storage
+	}
+
+	execute := func(t *testing.T, storage testRuntimeInterfaceStorage, code string) []string {
+
+		var loggedMessages []string
+
+		err := NewInterpreterRuntime().ExecuteTransaction(
+			Script{
+				Source: []byte(code),
+			},
+			Context{
+				Interface: newRuntimeInterface(storage, &loggedMessages),
+				Location:  common.TransactionLocation{0x2},
+			},
+		)
+		require.NoError(t, err)
+
+		return loggedMessages
+	}
+
+	const forEachLinkTx = `
+      transaction {
+          prepare(signer: AuthAccount) {
+              signer.forEachLink(fun (path: CapabilityPath, type: Type, target: Path): Bool {
+                  log(path)
+                  return true
+              })
+          }
+      }
+    `
+
+	t.Run("forEachLink", func(t *testing.T) {
+
+		t.Parallel()
+
+		storage := setup(t)
+
+		loggedMessages := execute(t, storage, `
+          transaction {
+              prepare(signer: AuthAccount) {
+                  signer.forEachLink(fun (path: CapabilityPath, type: Type, target: Path): Bool {
+                      log(path)
+                      log(type)
+                      log(target)
+                      return true
+                  })
+              }
+          }
+        `)
+
+		assert.Equal(t,
+			[]string{
+				"/public/a",
+				"Type<&String>()",
+				"/storage/a",
+				"/public/b",
+				"Type<&Int>()",
+				"/private/b",
+				"/public/cycle1",
+				"Type<&Int>()",
+				"/public/cycle2",
+				"/public/cycle2",
+				"Type<&Int>()",
+				"/public/cycle1",
+				"/private/b",
+				"Type<&Int>()",
+				"/storage/b",
+				"/private/c",
+				"Type<&Int>()",
+				"/storage/b",
+			},
+			loggedMessages,
+		)
+	})
+
+	t.Run("forEachLink, stop early", func(t *testing.T) {
+
+		t.Parallel()
+
+		storage := setup(t)
+
+		loggedMessages := execute(t, storage, `
+          transaction {
+              prepare(signer: AuthAccount) {
+                  signer.forEachLink(fun (path: CapabilityPath, type: Type, target: Path): Bool {
+                      log(path)
+                      return false
+                  })
+              }
+          }
+        `)
+
+		assert.Equal(t, []string{"/public/a"}, loggedMessages)
+	})
+
+	t.Run("unlinkAll, storage path", func(t *testing.T) {
+
+		t.Parallel()
+
+		storage := setup(t)
+
+		loggedMessages := execute(t, storage, `
+          transaction {
+              prepare(signer: AuthAccount) {
+                  log(signer.unlinkAll(targeting: /storage/b))
+                  log(signer.getCapability<&Int>(/public/b).check())
+              }
+          }
+        `)
+
+		assert.Equal(t,
+			[]string{
+				"[/public/b, /private/b, /private/c]",
+				"false",
+			},
+			loggedMessages,
+		)
+
+		loggedMessages = execute(t, storage, forEachLinkTx)
+
+		assert.Equal(t,
+			[]string{
+				"/public/a",
+				"/public/cycle1",
+				"/public/cycle2",
+			},
+			loggedMessages,
+		)
+	})
+
+	t.Run("unlinkAll, capability path", func(t *testing.T) {
+
+		t.Parallel()
+
+		storage := setup(t)
+
+		loggedMessages := execute(t, storage, `
+          transaction {
+              prepare(signer: AuthAccount) {
+                  log(signer.unlinkAll(targeting: /private/b))
+              }
+          }
+        `)
+
+		assert.Equal(t, []string{"[/public/b]"}, loggedMessages)
+
+		loggedMessages = execute(t, storage, forEachLinkTx)
+
+		assert.Equal(t,
+			[]string{
+				"/public/a",
+				"/public/cycle1",
+				"/public/cycle2",
+				"/private/b",
+				"/private/c",
+			},
+			loggedMessages,
+		)
+	})
+
+	t.Run("unlinkAll, cyclic links", func(t *testing.T) {
+
+		t.Parallel()
+
+		storage := setup(t)
+
+		loggedMessages := execute(t, storage, `
+          transaction {
+              prepare(signer: AuthAccount) {
+                  log(signer.unlinkAll(targeting: /public/cycle1))
+              }
+          }
+        `)
+
+		assert.Equal(t, []string{"[/public/cycle1, /public/cycle2]"}, loggedMessages)
+	})
+
+	t.Run("unlinkAll, no links", func(t *testing.T) {
+
+		t.Parallel()
+
+		storage := setup(t)
+
+		loggedMessages := execute(t, storage, `
+          transaction {
+              prepare(signer: AuthAccount) {
+                  log(signer.unlinkAll(targeting: /storage/unknown))
+              }
+          }
+        `)
+
+		assert.Equal(t, []string{"[]"}, loggedMessages)
+	})
+}
@@ -611,6 +611,133 @@ func TestCheckAccount_copy(t *testing.T) {
 	}
 }
 
+func TestCheckAccount_type(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("storage path", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheckAccount(t, `
+          let type = authAccount.type(at: /storage/r)
+        `)
+
+		require.NoError(t, err)
+
+		typeType := RequireGlobalValue(t, checker.Elaboration, "type")
+
+		require.Equal(t,
+			&sema.OptionalType{
+				Type: sema.MetaType,
+			},
+			typeType,
+		)
+	})
+
+	t.Run("public path", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t, `
+          let type = authAccount.type(at: /public/r)
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("public account", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t, `
+          let type = publicAccount.type(at: /storage/r)
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.NotDeclaredMemberError{}, errs[0])
+	})
+}
+
+func TestCheckAccount_check(t *testing.T) {
+
+	t.Parallel()
+
+	for _, typeArgument := range []string{"@R", "S", "@AnyResource", "AnyStruct"} {
+
+		typeArgument := typeArgument
+
+		t.Run(typeArgument, func(t *testing.T) {
+
+			t.Parallel()
+
+			checker, err := ParseAndCheckAccount(t,
+				fmt.Sprintf(
+					`
+                      resource R {}
+
+                      struct S {}
+
+                      let exists = authAccount.check<%s>(from: /storage/r)
+                    `,
+					typeArgument,
+				),
+			)
+
+			require.NoError(t, err)
+
+			existsType := RequireGlobalValue(t, checker.Elaboration, "exists")
+
+			require.Equal(t,
+				sema.BoolType,
+				existsType,
+			)
+		})
+	}
+
+	t.Run("missing type argument", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t, `
+          let exists = authAccount.check(from: /storage/r)
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.TypeParameterTypeInferenceError{}, errs[0])
+	})
+
+	t.Run("public path", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t, `
+          let exists = authAccount.check<Int>(from: /public/r)
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("public account", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t, `
+          let exists = publicAccount.check<Int>(from: /storage/r)
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.NotDeclaredMemberError{}, errs[0])
+	})
+}
+
 func TestCheckAccount_borrow(t *testing.T) {
 
 	t.Parallel()
@@ -1156,6 +1283,202 @@ func TestCheckAccount_getLinkTarget(t *testing.T) {
 	}
 }
 
+func TestCheckAccount_forEachStored(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t, `
+          fun test() {
+              authAccount.forEachStored(fun (path: StoragePath, type: Type): Bool {
+                  return true
+              })
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid path type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t, `
+          fun test() {
+              authAccount.forEachStored(fun (path: PublicPath, type: Type): Bool {
+                  return true
+              })
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("public account", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t, `
+          fun test() {
+              publicAccount.forEachStored(fun (path: StoragePath, type: Type): Bool {
+                  return true
+              })
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.NotDeclaredMemberError{}, errs[0])
+	})
+}
+
+func TestCheckAccount_forEachPublic(t *testing.T) {
+
+	t.Parallel()
+
+	for _, accountVariable := range []string{"authAccount", "publicAccount"} {
+
+		accountVariable := accountVariable
+
+		t.Run(accountVariable, func(t *testing.T) {
+
+			t.Parallel()
+
+			_, err := ParseAndCheckAccount(t,
+				fmt.Sprintf(
+					`
+                      fun test() {
+                          %s.forEachPublic(fun (path: PublicPath, type: Type): Bool {
+                              return true
+                          })
+                      }
+                    `,
+					accountVariable,
+				),
+			)
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCheckAccount_forEachLink(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t, `
+          fun test() {
+              authAccount.forEachLink(fun (path: CapabilityPath, type: Type, target: Path): Bool {
+                  return true
+              })
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid path type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t, `
+          fun test() {
+              authAccount.forEachLink(fun (path: PublicPath, type: Type, target: Path): Bool {
+                  return true
+              })
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("public account", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t, `
+          fun test() {
+              publicAccount.forEachLink(fun (path: CapabilityPath, type: Type, target: Path): Bool {
+                  return true
+              })
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.NotDeclaredMemberError{}, errs[0])
+	})
+}
+
+func TestCheckAccount_unlinkAll(t *testing.T) {
+
+	t.Parallel()
+
+	for _, domain := range common.AllPathDomainsByIdentifier {
+
+		domain := domain
+
+		t.Run(domain.Identifier(), func(t *testing.T) {
+
+			t.Parallel()
+
+			_, err := ParseAndCheckAccount(t,
+				fmt.Sprintf(
+					`
+                      fun test(): [CapabilityPath] {
+                          return authAccount.unlinkAll(targeting: /%s/r)
+                      }
+                    `,
+					domain.Identifier(),
+				),
+			)
+
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("missing label", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t, `
+          fun test() {
+              authAccount.unlinkAll(/storage/r)
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.MissingArgumentLabelError{}, errs[0])
+	})
+
+	t.Run("public account", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t, `
+          fun test() {
+              publicAccount.unlinkAll(targeting: /storage/r)
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.NotDeclaredMemberError{}, errs[0])
+	})
+}
+
 func TestCheckAccount_getCapability(t *testing.T) {
 
 	t.Parallel()
@@ -763,6 +763,153 @@ func TestCheckInvalidArrayContainsNotEquatable(t *testing.T) {
 	assert.IsType(t, &sema.NotEquatableTypeError{}, errs[0])
 }
 
+func TestCheckArrayMap(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let xs = [1, 2, 3]
+          let ys = xs.map(fun (_ x: Int): String {
+              return x.toString()
+          })
+        `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.VariableSizedType{
+				Type: sema.StringType,
+			},
+			RequireGlobalValue(t, checker.Elaboration, "ys"),
+		)
+	})
+
+	t.Run("constant-sized", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let xs: [Int; 2] = [1, 2]
+          let ys = xs.map(fun (_ x: Int): Bool {
+              return x > 1
+          })
+        `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.VariableSizedType{
+				Type: sema.BoolType,
+			},
+			RequireGlobalValue(t, checker.Elaboration, "ys"),
+		)
+	})
+
+	t.Run("invalid element parameter type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let xs = [1, 2, 3]
+          let ys = xs.map(fun (_ x: String): String {
+              return x
+          })
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+}
+
+func TestCheckArrayFilter(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let xs = [1, 2, 3]
+          let ys = xs.filter(fun (_ x: Int): Bool {
+              return x > 1
+          })
+        `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.VariableSizedType{
+				Type: sema.IntType,
+			},
+			RequireGlobalValue(t, checker.Elaboration, "ys"),
+		)
+	})
+
+	t.Run("invalid return type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let xs = [1, 2, 3]
+          let ys = xs.filter(fun (_ x: Int): Int {
+              return x
+          })
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+}
+
+func TestCheckArrayReduce(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let xs = [1, 2, 3]
+          let sum = xs.reduce(initial: "", fun (_ acc: String, _ x: Int): String {
+              return acc.concat(x.toString())
+          })
+        `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			sema.StringType,
+			RequireGlobalValue(t, checker.Elaboration, "sum"),
+		)
+	})
+
+	t.Run("mismatching initial type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let xs = [1, 2, 3]
+          let sum = xs.reduce(initial: "", fun (_ acc: Int, _ x: Int): Int {
+              return acc + x
+          })
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 3)
+
+		assert.IsType(t, &sema.TypeParameterTypeMismatchError{}, errs[0])
+		assert.IsType(t, &sema.TypeParameterTypeMismatchError{}, errs[1])
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[2])
+	})
+}
+
 func TestCheckEmptyArray(t *testing.T) {
 
 	t.Parallel()
@@ -787,6 +934,93 @@ func TestCheckEmptyArrayCall(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestCheckDictionaryForEach(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("forEachKey", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun test() {
+              let x = {1: "one", 2: "two"}
+              x.forEachKey(fun (_ key: Int): Bool {
+                  return key < 2
+              })
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("forEachValue", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun test() {
+              let x = {1: "one", 2: "two"}
+              x.forEachValue(fun (_ value: String): Bool {
+                  return true
+              })
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("forEach", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun test() {
+              let x = {1: "one", 2: "two"}
+              x.forEach(fun (_ key: Int, _ value: String): Bool {
+                  return true
+              })
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid key parameter type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun test() {
+              let x = {1: "one", 2: "two"}
+              x.forEachKey(fun (_ key: String): Bool {
+                  return true
+              })
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("invalid return type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun test() {
+              let x = {1: "one", 2: "two"}
+              x.forEachValue(fun (_ value: String) {})
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+}
+
 func TestCheckDictionaryContainsKey(t *testing.T) {
 
 	t.Parallel()
@@ -435,6 +435,43 @@ func TestCheckCapability_check(t *testing.T) {
 	})
 }
 
+func TestCheckCapability_targetType(t *testing.T) {
+
+	t.Parallel()
+
+	for _, capabilityType := range []string{"Capability", "Capability<&Int>"} {
+
+		capabilityType := capabilityType
+
+		t.Run(capabilityType, func(t *testing.T) {
+
+			t.Parallel()
+
+			checker, err := ParseAndCheckWithPanic(t,
+				fmt.Sprintf(
+					`
+                      let capability: %s = panic("")
+
+                      let type = capability.targetType()
+                    `,
+					capabilityType,
+				),
+			)
+
+			require.NoError(t, err)
+
+			typeType := RequireGlobalValue(t, checker.Elaboration, "type")
+
+			require.Equal(t,
+				&sema.OptionalType{
+					Type: sema.MetaType,
+				},
+				typeType,
+			)
+		})
+	}
+}
+
 func TestCheckCapability_address(t *testing.T) {
 
 	t.Parallel()
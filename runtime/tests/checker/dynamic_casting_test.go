@@ -27,6 +27,7 @@ import (
 
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/parser2"
 	"github.com/onflow/cadence/runtime/sema"
 	"github.com/onflow/cadence/runtime/tests/utils"
 )
@@ -1343,3 +1344,83 @@ func TestCheckAlwaysSucceedingDynamicCast(t *testing.T) {
 		)
 	})
 }
+
+func TestCheckDynamicCastingTypeInterner(t *testing.T) {
+
+	t.Parallel()
+
+	interner := sema.NewTypeInterner()
+
+	checker, err := ParseAndCheckWithOptions(t,
+		`
+          struct S {}
+
+          let x: {String: [S]}? = nil
+          let y: AnyStruct = x
+          let z = y as? {String: [S]}?
+        `,
+		ParseAndCheckOptions{
+			Options: []sema.Option{
+				sema.WithTypeInterner(interner),
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	xType := RequireGlobalValue(t, checker.Elaboration, "x")
+	zType := RequireGlobalValue(t, checker.Elaboration, "z").(*sema.OptionalType)
+
+	assert.Same(t, xType, zType.Type)
+}
+
+func BenchmarkCheckDynamicCasting(b *testing.B) {
+
+	const code = `
+      fun id(_ value: {String: [&[Int?]]}): {String: [&[Int?]]} {
+          return value
+      }
+
+      fun test(_ value: AnyStruct) {
+          let a = value as! {String: [&[Int?]]}
+          let b: {String: [&[Int?]]} = id(a)
+          let c: {String: [&[Int?]]}? = id(b)
+          let d: [{String: [&[Int?]]}] = [a, b, c!, id(a)]
+          let e = value as? [{String: [&[Int?]]}]
+          let f: [{String: [&[Int?]]}] = e ?? d
+          let g = value as? Capability<&{String: [&[Int?]]}>
+      }
+    `
+
+	program, err := parser2.ParseProgram(code)
+	require.NoError(b, err)
+
+	benchmark := func(b *testing.B, options ...sema.Option) {
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			checker, err := sema.NewChecker(
+				program,
+				utils.TestLocation,
+				append(
+					[]sema.Option{
+						sema.WithAccessCheckMode(sema.AccessCheckModeNotSpecifiedUnrestricted),
+					},
+					options...,
+				)...,
+			)
+			require.NoError(b, err)
+
+			err = checker.Check()
+			require.NoError(b, err)
+		}
+	}
+
+	b.Run("not interned", func(b *testing.B) {
+		benchmark(b)
+	})
+
+	b.Run("interned", func(b *testing.B) {
+		benchmark(b, sema.WithTypeInterner(sema.NewTypeInterner()))
+	})
+}
@@ -0,0 +1,160 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/parser2"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestCheckErrorRecovery(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("errors in independent members", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          contract C {
+
+              fun a(): Int {
+                  return true
+              }
+
+              resource R {
+                  fun b() {
+                      let x: Int = c
+                  }
+              }
+
+              fun d() {
+                  let y: X = 1
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 3)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+		assert.IsType(t, &sema.NotDeclaredError{}, errs[1])
+		assert.IsType(t, &sema.NotDeclaredError{}, errs[2])
+	})
+
+	t.Run("unsupported operation", func(t *testing.T) {
+
+		t.Parallel()
+
+		// The parser does not produce unary plus expressions,
+		// but programs may also be constructed, e.g. by tools.
+		// The unsupported operation is reported, and checking continues
+
+		program, err := parser2.ParseProgram(`
+          fun a(x: Int): Int {
+              return -x
+          }
+
+          fun b(): Int {
+              return true
+          }
+        `)
+		require.NoError(t, err)
+
+		returnStatement := program.FunctionDeclarations()[0].FunctionBlock.Block.Statements[0].(*ast.ReturnStatement)
+		returnStatement.Expression.(*ast.UnaryExpression).Operation = ast.OperationPlus
+
+		checker, err := sema.NewChecker(
+			program,
+			utils.TestLocation,
+			sema.WithAccessCheckMode(sema.AccessCheckModeNotSpecifiedUnrestricted),
+		)
+		require.NoError(t, err)
+
+		err = checker.Check()
+
+		errs := ExpectCheckerErrors(t, err, 2)
+
+		require.IsType(t, &sema.UnsupportedOperationError{}, errs[0])
+		assert.Equal(t,
+			ast.OperationPlus,
+			errs[0].(*sema.UnsupportedOperationError).Operation,
+		)
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[1])
+	})
+}
+
+func TestCheckerErrorLocatedErrors(t *testing.T) {
+
+	t.Parallel()
+
+	importedLocation := common.StringLocation("imported")
+
+	_, importedErr := ParseAndCheckWithOptions(t,
+		`
+          pub fun test(): Int {
+              return true
+          }
+        `,
+		ParseAndCheckOptions{
+			Location: importedLocation,
+		},
+	)
+	require.Error(t, importedErr)
+
+	_, err := ParseAndCheckWithOptions(t,
+		`
+          import "imported"
+
+          pub fun test(): Int {
+              return true
+          }
+        `,
+		ParseAndCheckOptions{
+			Options: []sema.Option{
+				sema.WithImportHandler(
+					func(_ *sema.Checker, _ common.Location, _ ast.Range) (sema.Import, error) {
+						return nil, importedErr
+					},
+				),
+			},
+		},
+	)
+
+	ExpectCheckerErrors(t, err, 2)
+
+	var checkerErr *sema.CheckerError
+	require.ErrorAs(t, err, &checkerErr)
+
+	locatedErrors := checkerErr.LocatedErrors()
+	require.Len(t, locatedErrors, 2)
+
+	assert.Equal(t, importedLocation, locatedErrors[0].Location)
+	assert.IsType(t, &sema.TypeMismatchError{}, locatedErrors[0].Err)
+
+	assert.Equal(t, utils.TestLocation, locatedErrors[1].Location)
+	assert.IsType(t, &sema.TypeMismatchError{}, locatedErrors[1].Err)
+}
@@ -250,3 +250,53 @@ func TestCheckInvalidOptionalChainingFieldAssignment(t *testing.T) {
 
 	assert.IsType(t, &sema.UnsupportedOptionalChainingAssignmentError{}, errs[0])
 }
+
+func TestCheckMemberExpressionFieldSlots(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheck(t, `
+      resource R {
+          let a: Int
+          var b: Int
+
+          init() {
+              self.a = 1
+              self.b = 2
+          }
+
+          fun foo(): Int {
+              return self.b
+          }
+      }
+
+      fun test(): UInt64 {
+          let r <- create R()
+          let sum = r.a + r.foo()
+          let id = r.uuid
+          destroy r
+          return id
+      }
+    `)
+
+	require.NoError(t, err)
+
+	slots := map[string][]int{}
+
+	for expression, slot := range checker.Elaboration.MemberExpressionFieldSlots { //nolint:maprangecheck
+		identifier := expression.Identifier.Identifier
+		slots[identifier] = append(slots[identifier], slot)
+	}
+
+	// Resources have the UUID field in the first slot,
+	// the function is not a field
+
+	assert.Equal(t,
+		map[string][]int{
+			"uuid": {0},
+			"a":    {1, 1},
+			"b":    {2, 2},
+		},
+		slots,
+	)
+}
@@ -3518,6 +3518,76 @@ func TestCheckResourceArrayLength(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestCheckInvalidResourceArrayMap(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheck(t, `
+      resource X {}
+
+      fun test() {
+          let xs <- [<-create X()]
+          xs.map(fun (_ x: @X): Int {
+              destroy x
+              return 1
+          })
+          destroy xs
+      }
+    `)
+
+	errs := ExpectCheckerErrors(t, err, 2)
+
+	assert.IsType(t, &sema.InvalidResourceArrayMemberError{}, errs[0])
+	assert.IsType(t, &sema.ResourceLossError{}, errs[1])
+}
+
+func TestCheckInvalidResourceArrayFilter(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheck(t, `
+      resource X {}
+
+      fun test() {
+          let xs <- [<-create X()]
+          let ys <- xs.filter(fun (_ x: @X): Bool {
+              destroy x
+              return true
+          })
+          destroy xs
+          destroy ys
+      }
+    `)
+
+	errs := ExpectCheckerErrors(t, err, 2)
+
+	assert.IsType(t, &sema.InvalidResourceArrayMemberError{}, errs[0])
+	assert.IsType(t, &sema.ResourceLossError{}, errs[1])
+}
+
+func TestCheckInvalidResourceArrayReduce(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheck(t, `
+      resource X {}
+
+      fun test() {
+          let xs <- [<-create X()]
+          xs.reduce(initial: 0, fun (_ acc: Int, _ x: @X): Int {
+              destroy x
+              return acc + 1
+          })
+          destroy xs
+      }
+    `)
+
+	errs := ExpectCheckerErrors(t, err, 2)
+
+	assert.IsType(t, &sema.InvalidResourceArrayMemberError{}, errs[0])
+	assert.IsType(t, &sema.ResourceLossError{}, errs[1])
+}
+
 func TestCheckInvalidResourceArrayConcat(t *testing.T) {
 
 	t.Parallel()
@@ -3674,6 +3744,51 @@ func TestCheckInvalidResourceDictionaryValues(t *testing.T) {
 	assert.IsType(t, &sema.InvalidNestedResourceMoveError{}, errs[1])
 }
 
+func TestCheckResourceDictionaryForEachKey(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheck(t, `
+      resource X {}
+
+      fun isNotEmpty(_ key: String): Bool {
+          return key.length > 0
+      }
+
+      fun test() {
+          let xs <- {"x1": <-create X()}
+          xs.forEachKey(isNotEmpty)
+          destroy xs
+      }
+    `)
+
+	require.NoError(t, err)
+}
+
+func TestCheckInvalidResourceDictionaryForEachValue(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheck(t, `
+      resource X {}
+
+      fun consume(_ x: @X): Bool {
+          destroy x
+          return true
+      }
+
+      fun test() {
+          let xs <- {"x1": <-create X()}
+          xs.forEachValue(consume)
+          destroy xs
+      }
+    `)
+
+	errs := ExpectCheckerErrors(t, err, 1)
+
+	assert.IsType(t, &sema.InvalidResourceDictionaryMemberError{}, errs[0])
+}
+
 func TestCheckInvalidResourceLossAfterMoveThroughDictionaryIndexing(t *testing.T) {
 
 	t.Parallel()
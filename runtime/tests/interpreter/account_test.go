@@ -31,6 +31,7 @@ import (
 	"github.com/onflow/cadence/runtime/sema"
 	"github.com/onflow/cadence/runtime/stdlib"
 	"github.com/onflow/cadence/runtime/tests/checker"
+	"github.com/onflow/cadence/runtime/tests/utils"
 )
 
 func testAccount(
@@ -533,6 +534,149 @@ func TestInterpretAuthAccount_copy(t *testing.T) {
 	})
 }
 
+func TestInterpretAuthAccount_type(t *testing.T) {
+
+	t.Parallel()
+
+	address := interpreter.NewAddressValueFromBytes([]byte{42})
+
+	inter, storedValues := testAccount(
+		t,
+		address,
+		true,
+		`
+          resource R {}
+
+          fun save() {
+              account.save(<-create R(), to: /storage/r)
+              account.save([1, 2], to: /storage/xs)
+          }
+
+          fun typeR(): Type? {
+              return account.type(at: /storage/r)
+          }
+
+          fun typeXs(): Type? {
+              return account.type(at: /storage/xs)
+          }
+
+          fun typeNonExistent(): Type? {
+              return account.type(at: /storage/nonExistent)
+          }
+        `,
+	)
+
+	_, err := inter.Invoke("save")
+	require.NoError(t, err)
+
+	require.Len(t, storedValues, 2)
+
+	value, err := inter.Invoke("typeR")
+	require.NoError(t, err)
+
+	require.Equal(t,
+		interpreter.NewSomeValueOwningNonCopying(
+			interpreter.TypeValue{
+				Type: interpreter.CompositeStaticType{
+					Location:            utils.TestLocation,
+					QualifiedIdentifier: "R",
+				},
+			},
+		),
+		value,
+	)
+
+	value, err = inter.Invoke("typeXs")
+	require.NoError(t, err)
+
+	// Like for getType, arrays have no static type yet
+
+	require.Equal(t,
+		interpreter.NewSomeValueOwningNonCopying(
+			interpreter.TypeValue{},
+		),
+		value,
+	)
+
+	value, err = inter.Invoke("typeNonExistent")
+	require.NoError(t, err)
+
+	require.Equal(t, interpreter.NilValue{}, value)
+
+	// NOTE: check the values were *not* removed from storage
+	require.Len(t, storedValues, 2)
+}
+
+func TestInterpretAuthAccount_check(t *testing.T) {
+
+	t.Parallel()
+
+	address := interpreter.NewAddressValueFromBytes([]byte{42})
+
+	inter, storedValues := testAccount(
+		t,
+		address,
+		true,
+		`
+          resource interface I {}
+
+          resource R: I {}
+
+          resource R2 {}
+
+          fun save() {
+              account.save(<-create R(), to: /storage/r)
+          }
+
+          fun checkR(): Bool {
+              return account.check<@R>(from: /storage/r)
+          }
+
+          fun checkRestricted(): Bool {
+              return account.check<@AnyResource{I}>(from: /storage/r)
+          }
+
+          fun checkAnyResource(): Bool {
+              return account.check<@AnyResource>(from: /storage/r)
+          }
+
+          fun checkR2(): Bool {
+              return account.check<@R2>(from: /storage/r)
+          }
+
+          fun checkReference(): Bool {
+              return account.check<&R>(from: /storage/r)
+          }
+
+          fun checkNonExistent(): Bool {
+              return account.check<@R>(from: /storage/nonExistent)
+          }
+        `,
+	)
+
+	_, err := inter.Invoke("save")
+	require.NoError(t, err)
+
+	require.Len(t, storedValues, 1)
+
+	for name, expected := range map[string]bool{
+		"checkR":           true,
+		"checkRestricted":  true,
+		"checkAnyResource": true,
+		"checkR2":          false,
+		"checkReference":   false,
+		"checkNonExistent": false,
+	} {
+		value, err := inter.Invoke(name)
+		require.NoError(t, err)
+
+		assert.Equal(t, interpreter.BoolValue(expected), value, name)
+	}
+
+	// NOTE: check the resource was *not* removed from storage
+	require.Len(t, storedValues, 1)
+}
+
 func TestInterpretAuthAccount_borrow(t *testing.T) {
 
 	t.Parallel()
@@ -21,9 +21,11 @@ package interpreter_test
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/tests/utils"
 )
 
 func TestInterpretCapability_borrow(t *testing.T) {
@@ -786,6 +788,99 @@ func TestInterpretCapability_check(t *testing.T) {
 	})
 }
 
+func TestInterpretCapability_targetType(t *testing.T) {
+
+	t.Parallel()
+
+	address := interpreter.NewAddressValueFromBytes([]byte{42})
+
+	inter, _ := testAccount(
+		t,
+		address,
+		true,
+		`
+          resource R {}
+
+          resource R2 {}
+
+          fun saveAndLink() {
+              account.save(<-create R(), to: /storage/r)
+
+              account.link<&R>(/public/single, target: /storage/r)
+
+              account.link<&R>(/public/double, target: /public/single)
+
+              account.link<&R>(/public/nonExistent, target: /storage/nonExistent)
+
+              account.link<&R>(/public/loop1, target: /public/loop2)
+              account.link<&R>(/public/loop2, target: /public/loop1)
+
+              account.link<&R2>(/public/r2, target: /storage/r)
+          }
+
+          fun targetType(_ path: CapabilityPath): Type? {
+              return account.getCapability(path).targetType()
+          }
+
+          fun single(): Type? {
+              return targetType(/public/single)
+          }
+
+          fun double(): Type? {
+              return targetType(/public/double)
+          }
+
+          fun nonExistent(): Type? {
+              return targetType(/public/nonExistent)
+          }
+
+          fun loop(): Type? {
+              return targetType(/public/loop1)
+          }
+
+          fun r2(): Type? {
+              return targetType(/public/r2)
+          }
+
+          fun typed(): Type? {
+              return account.getCapability<&R2>(/public/single)!.targetType()
+          }
+
+          fun unlinked(): Type? {
+              return targetType(/public/unlinked)
+          }
+        `,
+	)
+
+	_, err := inter.Invoke("saveAndLink")
+	require.NoError(t, err)
+
+	expectedType := interpreter.NewSomeValueOwningNonCopying(
+		interpreter.TypeValue{
+			Type: interpreter.CompositeStaticType{
+				Location:            utils.TestLocation,
+				QualifiedIdentifier: "R",
+			},
+		},
+	)
+
+	for _, name := range []string{"single", "double", "r2", "typed"} {
+
+		value, err := inter.Invoke(name)
+		require.NoError(t, err)
+
+		assert.Equal(t, expectedType, value, name)
+	}
+
+	for _, name := range []string{"nonExistent", "loop", "unlinked"} {
+
+		value, err := inter.Invoke(name)
+		require.NoError(t, err)
+
+		assert.Equal(t, interpreter.NilValue{}, value, name)
+	}
+}
+
 func TestInterpretCapability_address(t *testing.T) {
 
 	t.Parallel()
@@ -61,6 +61,63 @@ func TestInterpretCompositeValue(t *testing.T) {
 	})
 }
 
+func TestInterpretCompositeValueFields(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      struct S {
+          var a: Int
+          var b: Int
+
+          init() {
+              self.a = 1
+              self.b = 2
+          }
+
+          fun sum(): Int {
+              return self.a + self.b
+          }
+      }
+
+      let s = S()
+
+      fun setA(_ a: Int) {
+          s.a = a
+      }
+
+      fun sum(): Int {
+          return s.sum()
+      }
+    `)
+
+	s := inter.Globals["s"].GetValue().(*interpreter.CompositeValue)
+
+	// The fields are accessed through the map, and by the program
+
+	fields := s.Fields()
+	fields.Set("b", interpreter.NewIntValueFromInt64(10))
+
+	_, err := inter.Invoke("setA", interpreter.NewIntValueFromInt64(5))
+	require.NoError(t, err)
+
+	value, err := inter.Invoke("sum")
+	require.NoError(t, err)
+
+	require.Equal(t,
+		interpreter.NewIntValueFromInt64(15),
+		value,
+	)
+
+	a, ok := fields.Get("a")
+	require.True(t, ok)
+
+	require.Equal(t,
+		interpreter.NewIntValueFromInt64(5),
+		a,
+	)
+}
+
 // Utility methods
 func testCompositeValue(t *testing.T, code string) *interpreter.Interpreter {
 
@@ -3464,3 +3464,98 @@ func TestInterpretResourceConstructorCast(t *testing.T) {
 		}
 	}
 }
+
+func TestInterpretDynamicCastingTypeInterner(t *testing.T) {
+
+	t.Parallel()
+
+	interner := sema.NewTypeInterner()
+
+	inter, err := parseCheckAndInterpretWithOptions(t,
+		`
+          struct S {}
+
+          fun test(): [AnyStruct] {
+              let value: AnyStruct = {"a": [S()]}
+              return [
+                  value as? {String: [S]},
+                  value.isInstance(Type<{String: [S]}>()),
+                  Type<{String: [S]}>().identifier
+              ]
+          }
+        `,
+		ParseCheckAndInterpretOptions{
+			CheckerOptions: []sema.Option{
+				sema.WithTypeInterner(interner),
+			},
+			Options: []interpreter.Option{
+				interpreter.WithTypeInterner(interner),
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	result, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	values := result.(*interpreter.ArrayValue).Elements()
+	require.Len(t, values, 3)
+
+	require.IsType(t, &interpreter.SomeValue{}, values[0])
+	require.Equal(t, interpreter.BoolValue(true), values[1])
+	require.Equal(t,
+		interpreter.NewStringValue("{String:[S.test.S]}"),
+		values[2],
+	)
+
+	require.NotZero(t, interner.Len())
+}
+
+func BenchmarkInterpretDynamicCasting(b *testing.B) {
+
+	const code = `
+      struct S {}
+
+      fun test() {
+          let value: AnyStruct = {"a": [S(), S()], "b": [S()]}
+          var i = 0
+          while i < 10 {
+              value as! {String: [S]}
+              value as? {String: [AnyStruct]}
+              value.isInstance(Type<{String: [S]}>())
+              Type<{String: [S]}>().identifier
+              i = i + 1
+          }
+      }
+    `
+
+	benchmark := func(b *testing.B, options ParseCheckAndInterpretOptions) {
+		inter, err := parseCheckAndInterpretWithOptions(b, code, options)
+		require.NoError(b, err)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			_, err := inter.Invoke("test")
+			require.NoError(b, err)
+		}
+	}
+
+	b.Run("not interned", func(b *testing.B) {
+		benchmark(b, ParseCheckAndInterpretOptions{})
+	})
+
+	b.Run("interned", func(b *testing.B) {
+		interner := sema.NewTypeInterner()
+
+		benchmark(b, ParseCheckAndInterpretOptions{
+			CheckerOptions: []sema.Option{
+				sema.WithTypeInterner(interner),
+			},
+			Options: []interpreter.Option{
+				interpreter.WithTypeInterner(interner),
+			},
+		})
+	})
+}
@@ -4861,6 +4861,143 @@ func TestInterpretArrayContains(t *testing.T) {
 	)
 }
 
+func TestInterpretArrayMap(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      fun test(): [String] {
+          let xs = [1, 2, 3]
+          return xs.map(fun (_ x: Int): String {
+              return (x * 2).toString()
+          })
+      }
+    `)
+
+	value, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		interpreter.NewArrayValueUnownedNonCopying(
+			interpreter.NewStringValue("2"),
+			interpreter.NewStringValue("4"),
+			interpreter.NewStringValue("6"),
+		),
+		value,
+	)
+}
+
+func TestInterpretArrayMapOptionalParameter(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      fun test(): [Bool] {
+          let xs = [1, 2]
+          return xs.map(fun (_ x: Int?): Bool {
+              return x != nil
+          })
+      }
+    `)
+
+	value, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		interpreter.NewArrayValueUnownedNonCopying(
+			interpreter.BoolValue(true),
+			interpreter.BoolValue(true),
+		),
+		value,
+	)
+}
+
+func TestInterpretArrayFilter(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      fun test(): [Int] {
+          let xs = [1, 2, 3, 4]
+          return xs.filter(fun (_ x: Int): Bool {
+              return x % 2 == 0
+          })
+      }
+    `)
+
+	value, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		interpreter.NewArrayValueUnownedNonCopying(
+			interpreter.NewIntValueFromInt64(2),
+			interpreter.NewIntValueFromInt64(4),
+		),
+		value,
+	)
+}
+
+func TestInterpretArrayFilterCopiesElements(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      struct S {
+          var value: Int
+
+          init(value: Int) {
+              self.value = value
+          }
+      }
+
+      fun test(): Int {
+          let xs = [S(value: 1)]
+          let ys = xs.filter(fun (_ s: S): Bool {
+              s.value = 2
+              return true
+          })
+          ys[0].value = 3
+          return xs[0].value
+      }
+    `)
+
+	value, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		interpreter.NewIntValueFromInt64(1),
+		value,
+	)
+}
+
+func TestInterpretArrayReduce(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      fun sum(_ xs: [Int]): Int {
+          return xs.reduce(initial: 0, fun (_ acc: Int, _ x: Int): Int {
+              return acc + x
+          })
+      }
+
+      fun test(): [Int] {
+          return [sum([1, 2, 3, 4]), sum([])]
+      }
+    `)
+
+	value, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		interpreter.NewArrayValueUnownedNonCopying(
+			interpreter.NewIntValueFromInt64(10),
+			interpreter.NewIntValueFromInt64(0),
+		),
+		value,
+	)
+}
+
 func TestInterpretDictionaryContainsKey(t *testing.T) {
 
 	t.Parallel()
@@ -4900,6 +5037,109 @@ func TestInterpretDictionaryContainsKey(t *testing.T) {
 	)
 }
 
+func TestInterpretDictionaryForEach(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("forEachKey", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [String] {
+              let x = {"a": 1, "b": 2, "c": 3}
+              let keys: [String] = []
+              x.forEachKey(fun (_ key: String): Bool {
+                  keys.append(key)
+                  return true
+              })
+              return keys
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			interpreter.NewArrayValueUnownedNonCopying(
+				interpreter.NewStringValue("a"),
+				interpreter.NewStringValue("b"),
+				interpreter.NewStringValue("c"),
+			),
+			value,
+		)
+	})
+
+	t.Run("forEachValue, stop early", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): Int {
+              let x = {"a": 1, "b": 2, "c": 3}
+              var sum = 0
+              x.forEachValue(fun (_ value: Int): Bool {
+                  sum = sum + value
+                  return value < 2
+              })
+              return sum
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			interpreter.NewIntValueFromInt64(3),
+			value,
+		)
+	})
+
+	t.Run("forEach", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): String {
+              let x = {"a": 1, "b": 2}
+              var entries = ""
+              x.forEach(fun (_ key: String, _ value: Int): Bool {
+                  entries = entries.concat(key).concat(value.toString())
+                  return true
+              })
+              return entries
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			interpreter.NewStringValue("a1b2"),
+			value,
+		)
+	})
+
+	t.Run("mutation during iteration", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test() {
+              let x = {"a": 1, "b": 2}
+              x.forEachKey(fun (_ key: String): Bool {
+                  x.remove(key: key)
+                  return true
+              })
+          }
+        `)
+
+		_, err := inter.Invoke("test")
+		require.Error(t, err)
+		require.ErrorAs(t, err, &interpreter.ContainerMutatedDuringIterationError{})
+	})
+}
+
 func TestInterpretStringConcat(t *testing.T) {
 
 	t.Parallel()
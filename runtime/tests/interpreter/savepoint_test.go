@@ -0,0 +1,411 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+)
+
+func TestInterpretSavepoint(t *testing.T) {
+
+	t.Parallel()
+
+	parseCheckAndInterpretWithFunctions := func(
+		t *testing.T,
+		code string,
+		functions ...stdlib.StandardLibraryFunction,
+	) *interpreter.Interpreter {
+
+		standardLibraryFunctions := append(
+			stdlib.StandardLibraryFunctions{
+				stdlib.PanicFunction,
+				stdlib.SavepointFunction,
+			},
+			functions...,
+		)
+
+		valueDeclarations := standardLibraryFunctions.ToSemaValueDeclarations()
+		values := standardLibraryFunctions.ToInterpreterValueDeclarations()
+
+		inter, err := parseCheckAndInterpretWithOptions(t,
+			code,
+			ParseCheckAndInterpretOptions{
+				CheckerOptions: []sema.Option{
+					sema.WithPredeclaredValues(valueDeclarations),
+				},
+				Options: []interpreter.Option{
+					interpreter.WithPredeclaredValues(values),
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		return inter
+	}
+
+	t.Run("commit", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithFunctions(t, `
+          var x = 1
+
+          fun test(): [AnyStruct] {
+              let committed = savepoint(fun (): Bool {
+                  x = 2
+                  return true
+              })
+              return [committed, x]
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			interpreter.NewArrayValueUnownedNonCopying(
+				interpreter.BoolValue(true),
+				interpreter.NewIntValueFromInt64(2),
+			),
+			result,
+		)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithFunctions(t, `
+          struct Counter {
+              pub var count: Int
+
+              init() {
+                  self.count = 0
+              }
+
+              pub fun increment() {
+                  self.count = self.count + 1
+              }
+          }
+
+          var x = 1
+          let numbers: [Int] = [1, 2]
+          let names: {String: Int} = {"a": 1}
+          let counter = Counter()
+
+          fun test(): [AnyStruct] {
+              let committed = savepoint(fun (): Bool {
+                  x = 2
+                  numbers.append(3)
+                  numbers[0] = 10
+                  names["b"] = 2
+                  names.remove(key: "a")
+                  counter.increment()
+                  return false
+              })
+              return [committed, x, numbers.length, numbers[0], names.length, names["a"]!, counter.count]
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			interpreter.NewArrayValueUnownedNonCopying(
+				interpreter.BoolValue(false),
+				interpreter.NewIntValueFromInt64(1),
+				interpreter.NewIntValueFromInt64(2),
+				interpreter.NewIntValueFromInt64(1),
+				interpreter.NewIntValueFromInt64(1),
+				interpreter.NewIntValueFromInt64(1),
+				interpreter.NewIntValueFromInt64(0),
+			),
+			result,
+		)
+	})
+
+	t.Run("batch", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithFunctions(t, `
+          var balance = 10
+
+          fun withdraw(_ amount: Int): Bool {
+              return savepoint(fun (): Bool {
+                  balance = balance - amount
+                  return balance >= 0
+              })
+          }
+
+          fun test(): [AnyStruct] {
+              let results: [Bool] = []
+              for amount in [3, 8, 7, 1] {
+                  results.append(withdraw(amount))
+              }
+              return [results, balance]
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			interpreter.NewArrayValueUnownedNonCopying(
+				interpreter.NewArrayValueUnownedNonCopying(
+					interpreter.BoolValue(true),
+					interpreter.BoolValue(false),
+					interpreter.BoolValue(true),
+					interpreter.BoolValue(false),
+				),
+				interpreter.NewIntValueFromInt64(0),
+			),
+			result,
+		)
+	})
+
+	t.Run("nested, inner rollback", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithFunctions(t, `
+          var x = 0
+          var y = 0
+
+          fun test(): [Int] {
+              savepoint(fun (): Bool {
+                  x = 1
+                  savepoint(fun (): Bool {
+                      y = 1
+                      return false
+                  })
+                  return true
+              })
+              return [x, y]
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			interpreter.NewArrayValueUnownedNonCopying(
+				interpreter.NewIntValueFromInt64(1),
+				interpreter.NewIntValueFromInt64(0),
+			),
+			result,
+		)
+	})
+
+	t.Run("nested, outer rollback", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithFunctions(t, `
+          var x = 0
+          var y = 0
+
+          fun test(): [Int] {
+              savepoint(fun (): Bool {
+                  x = 1
+                  savepoint(fun (): Bool {
+                      y = 1
+                      return true
+                  })
+                  return false
+              })
+              return [x, y]
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			interpreter.NewArrayValueUnownedNonCopying(
+				interpreter.NewIntValueFromInt64(0),
+				interpreter.NewIntValueFromInt64(0),
+			),
+			result,
+		)
+	})
+
+	t.Run("events", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithFunctions(t, `
+          event E(value: Int)
+
+          fun test() {
+              emit E(value: 0)
+              savepoint(fun (): Bool {
+                  emit E(value: 1)
+                  return false
+              })
+              savepoint(fun (): Bool {
+                  emit E(value: 2)
+                  savepoint(fun (): Bool {
+                      emit E(value: 3)
+                      return true
+                  })
+                  return true
+              })
+              emit E(value: 4)
+          }
+        `)
+
+		var values []interpreter.Value
+
+		inter.SetOnEventEmittedHandler(
+			func(_ *interpreter.Interpreter, event *interpreter.CompositeValue, _ *sema.CompositeType) error {
+				values = append(values, event.GetField("value"))
+				return nil
+			},
+		)
+
+		_, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]interpreter.Value{
+				interpreter.NewIntValueFromInt64(0),
+				interpreter.NewIntValueFromInt64(2),
+				interpreter.NewIntValueFromInt64(3),
+				interpreter.NewIntValueFromInt64(4),
+			},
+			values,
+		)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithFunctions(t, `
+          fun test() {
+              savepoint(fun (): Bool {
+                  panic("failed")
+              })
+          }
+        `)
+
+		_, err := inter.Invoke("test")
+		require.Error(t, err)
+
+		require.ErrorAs(t, err, &stdlib.PanicError{})
+	})
+
+	t.Run("failure in try expression", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithFunctions(t, `
+          var x = 0
+          var y = 0
+
+          fun setAndFail() {
+              x = 1
+              savepoint(fun (): Bool {
+                  y = 1
+                  panic("failed")
+              })
+          }
+
+          fun test(): [Int] {
+              try setAndFail()
+
+              // The journal must be intact after the failure
+
+              savepoint(fun (): Bool {
+                  x = 2
+                  return false
+              })
+
+              return [x, y]
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			interpreter.NewArrayValueUnownedNonCopying(
+				interpreter.NewIntValueFromInt64(0),
+				interpreter.NewIntValueFromInt64(0),
+			),
+			result,
+		)
+	})
+
+	t.Run("irreversible effect", func(t *testing.T) {
+
+		t.Parallel()
+
+		effectFunction := stdlib.NewStandardLibraryFunction(
+			"effect",
+			&sema.FunctionType{
+				ReturnTypeAnnotation: sema.NewTypeAnnotation(
+					sema.VoidType,
+				),
+			},
+			``,
+			func(invocation interpreter.Invocation) interpreter.Value {
+				invocation.Interpreter.RecordIrreversibleEffect()
+				return interpreter.VoidValue{}
+			},
+		)
+
+		inter := parseCheckAndInterpretWithFunctions(t,
+			`
+              fun commit(): Bool {
+                  return savepoint(fun (): Bool {
+                      effect()
+                      return true
+                  })
+              }
+
+              fun rollback(): Bool {
+                  return savepoint(fun (): Bool {
+                      effect()
+                      return false
+                  })
+              }
+            `,
+			effectFunction,
+		)
+
+		result, err := inter.Invoke("commit")
+		require.NoError(t, err)
+
+		assert.Equal(t, interpreter.BoolValue(true), result)
+
+		_, err = inter.Invoke("rollback")
+		require.Error(t, err)
+
+		require.ErrorAs(t, err, &interpreter.IrreversibleSavepointRollbackError{})
+	})
+}
@@ -19,6 +19,7 @@
 package interpreter_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/onflow/cadence/runtime/interpreter"
@@ -116,6 +117,226 @@ func TestInterpretStringEncodeHex(t *testing.T) {
 	)
 }
 
+func TestInterpretStringStaticDecodeHex(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      fun test(): [UInt8] {
+          return String.decodeHex("01CADE")
+      }
+	`)
+
+	result, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	require.Equal(t,
+		interpreter.NewArrayValueUnownedNonCopying(
+			interpreter.UInt8Value(1),
+			interpreter.UInt8Value(0xCA),
+			interpreter.UInt8Value(0xDE),
+		),
+		result,
+	)
+}
+
+func TestInterpretStringEncodeBase64(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      fun test(): String {
+          return String.encodeBase64([1 as UInt8, 2, 3, 0xCA, 0xDE])
+      }
+	`)
+
+	result, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	require.Equal(t,
+		interpreter.NewStringValue("AQIDyt4="),
+		result,
+	)
+}
+
+func TestInterpretStringDecodeBase64(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [UInt8] {
+              return String.decodeBase64("AQIDyt4=")
+          }
+	    `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		require.Equal(t,
+			interpreter.NewArrayValueUnownedNonCopying(
+				interpreter.UInt8Value(1),
+				interpreter.UInt8Value(2),
+				interpreter.UInt8Value(3),
+				interpreter.UInt8Value(0xCA),
+				interpreter.UInt8Value(0xDE),
+			),
+			result,
+		)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [UInt8] {
+              return String.decodeBase64("AQIDyt4")
+          }
+	    `)
+
+		_, err := inter.Invoke("test")
+		require.Error(t, err)
+		require.ErrorAs(t, err, &interpreter.InvalidBase64StringError{})
+	})
+}
+
+func TestInterpretStringFormat(t *testing.T) {
+
+	t.Parallel()
+
+	type testCase struct {
+		format    string
+		arguments string
+		expected  string
+	}
+
+	testCases := []testCase{
+		{"Hello, {0}!", `["World"]`, "Hello, World!"},
+		{"{1} {0}", `["a", "b"]`, "b a"},
+		{"{0}{0}", `[1]`, "11"},
+		{"{0} {1} {2}", `[true, nil, [1, 2]]`, "true nil [1, 2]"},
+		{"{0:.2}", `[1.5]`, "1.50"},
+		{"{0:.0}", `[12.99]`, "12"},
+		{"{0:.2}", `[-1.2345]`, "-1.23"},
+		{"[{0:5}]", `[42]`, "[   42]"},
+		{"[{0:-5}]", `["ab"]`, "[ab   ]"},
+		{"{0:05}", `[-42]`, "-0042"},
+		{"{0:08.2}", `[3.14159]`, "00003.14"},
+		{"[{0:3}]", `["\u{1F490}"]`, "[  \U0001F490]"},
+		{"{{{0}}}", `["x"]`, "{x}"},
+	}
+
+	for _, testCase := range testCases {
+
+		testCase := testCase
+
+		t.Run(testCase.format, func(t *testing.T) {
+
+			t.Parallel()
+
+			inter := parseCheckAndInterpret(t,
+				fmt.Sprintf(
+					`
+                      fun test(): String {
+                          let arguments: [AnyStruct] = %[2]s
+                          return String.format("%[1]s", arguments)
+                      }
+                    `,
+					testCase.format,
+					testCase.arguments,
+				),
+			)
+
+			result, err := inter.Invoke("test")
+			require.NoError(t, err)
+
+			require.Equal(t,
+				interpreter.NewStringValue(testCase.expected),
+				result,
+			)
+		})
+	}
+
+	invalidTestCases := []testCase{
+		{format: "{0", arguments: `["a"]`},
+		{format: "0}", arguments: `["a"]`},
+		{format: "{1}", arguments: `["a"]`},
+		{format: "{x}", arguments: `["a"]`},
+		{format: "{-1}", arguments: `["a"]`},
+		{format: "{0:.2}", arguments: `[1]`},
+		{format: "{0:05}", arguments: `["a"]`},
+		{format: "{0:x}", arguments: `["a"]`},
+		{format: "{0:1025}", arguments: `[1]`},
+		{format: "{0:99999999999}", arguments: `[1]`},
+		{format: "{0:.1025}", arguments: `[1.5]`},
+	}
+
+	for _, testCase := range invalidTestCases {
+
+		testCase := testCase
+
+		t.Run(testCase.format, func(t *testing.T) {
+
+			t.Parallel()
+
+			inter := parseCheckAndInterpret(t,
+				fmt.Sprintf(
+					`
+                      fun test(): String {
+                          let arguments: [AnyStruct] = %[2]s
+                          return String.format("%[1]s", arguments)
+                      }
+                    `,
+					testCase.format,
+					testCase.arguments,
+				),
+			)
+
+			_, err := inter.Invoke("test")
+			require.Error(t, err)
+			require.ErrorAs(t, err, &interpreter.InvalidFormatStringError{})
+		})
+	}
+
+	t.Run("metering", func(t *testing.T) {
+
+		t.Parallel()
+
+		var loopIterations int
+
+		inter, err := parseCheckAndInterpretWithOptions(t,
+			`
+              fun test(): String {
+                  return String.format("{0:1024}{0:1024}", [1])
+              }
+            `,
+			ParseCheckAndInterpretOptions{
+				Options: []interpreter.Option{
+					interpreter.WithOnLoopIterationHandler(
+						func(_ *interpreter.Interpreter, _ int) {
+							loopIterations++
+						},
+					),
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		require.IsType(t, &interpreter.StringValue{}, result)
+		require.Len(t, result.(*interpreter.StringValue).Str, 2048)
+
+		// The result is metered in units of 64 bytes
+		require.Equal(t, 32, loopIterations)
+	})
+}
+
 func TestInterpretStringUtf8Field(t *testing.T) {
 
 	t.Parallel()
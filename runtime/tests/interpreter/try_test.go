@@ -0,0 +1,487 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+)
+
+func TestInterpretTry(t *testing.T) {
+
+	t.Parallel()
+
+	parseCheckAndInterpretWithFunctions := func(
+		t *testing.T,
+		code string,
+		functions ...stdlib.StandardLibraryFunction,
+	) *interpreter.Interpreter {
+
+		standardLibraryFunctions := append(
+			stdlib.StandardLibraryFunctions{
+				stdlib.PanicFunction,
+			},
+			functions...,
+		)
+
+		valueDeclarations := standardLibraryFunctions.ToSemaValueDeclarations()
+		values := standardLibraryFunctions.ToInterpreterValueDeclarations()
+
+		inter, err := parseCheckAndInterpretWithOptions(t,
+			code,
+			ParseCheckAndInterpretOptions{
+				CheckerOptions: []sema.Option{
+					sema.WithPredeclaredValues(valueDeclarations),
+				},
+				Options: []interpreter.Option{
+					interpreter.WithPredeclaredValues(values),
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		return inter
+	}
+
+	t.Run("success", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithFunctions(t, `
+          fun answer(): Int {
+              return 42
+          }
+
+          fun test(): Int? {
+              return try answer()
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			interpreter.NewSomeValueOwningNonCopying(
+				interpreter.NewIntValueFromInt64(42),
+			),
+			result,
+		)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithFunctions(t, `
+          fun fail(): Int {
+              panic("failed")
+          }
+
+          fun test(): Int {
+              return try fail() ?? 1
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			interpreter.NewIntValueFromInt64(1),
+			result,
+		)
+	})
+
+	t.Run("failed pre-condition", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithFunctions(t, `
+          fun positive(_ n: Int): Int {
+              pre { n > 0 }
+              return n
+          }
+
+          fun test(): [Bool] {
+              return [
+                  try positive(1) != nil,
+                  try positive(-1) != nil
+              ]
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			interpreter.NewArrayValueUnownedNonCopying(
+				interpreter.BoolValue(true),
+				interpreter.BoolValue(false),
+			),
+			result,
+		)
+	})
+
+	t.Run("composite mutation is rolled back", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithFunctions(t, `
+          struct Counter {
+              pub var count: Int
+
+              init() {
+                  self.count = 0
+              }
+
+              pub fun increment() {
+                  self.count = self.count + 1
+              }
+
+              pub fun incrementAndFail() {
+                  self.increment()
+                  panic("failed")
+              }
+          }
+
+          let counter = Counter()
+
+          fun test(): Int {
+              try counter.increment()
+              try counter.incrementAndFail()
+              return counter.count
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			interpreter.NewIntValueFromInt64(1),
+			result,
+		)
+	})
+
+	t.Run("array and dictionary mutations are rolled back", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithFunctions(t, `
+          let numbers: [Int] = [1, 2]
+          let names: {String: Int} = {"a": 1}
+
+          fun mutateAndFail() {
+              numbers.append(3)
+              numbers[0] = 10
+              numbers.removeFirst()
+              names["b"] = 2
+              names.remove(key: "a")
+              panic("failed")
+          }
+
+          fun test(): [Int] {
+              try mutateAndFail()
+              return numbers.concat([names.length, names["a"]!])
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			interpreter.NewArrayValueUnownedNonCopying(
+				interpreter.NewIntValueFromInt64(1),
+				interpreter.NewIntValueFromInt64(2),
+				interpreter.NewIntValueFromInt64(1),
+				interpreter.NewIntValueFromInt64(1),
+			),
+			result,
+		)
+	})
+
+	t.Run("variable assignment is rolled back", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithFunctions(t, `
+          var x = 1
+
+          fun setAndFail() {
+              x = 2
+              panic("failed")
+          }
+
+          fun test(): Int {
+              try setAndFail()
+              return x
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			interpreter.NewIntValueFromInt64(1),
+			result,
+		)
+	})
+
+	t.Run("nested, inner failure", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithFunctions(t, `
+          let steps: [String] = []
+
+          fun inner() {
+              steps.append("inner")
+              panic("failed")
+          }
+
+          fun outer() {
+              steps.append("outer")
+              try inner()
+              steps.append("after inner")
+          }
+
+          fun test(): [String] {
+              try outer()
+              return steps
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			interpreter.NewArrayValueUnownedNonCopying(
+				interpreter.NewStringValue("outer"),
+				interpreter.NewStringValue("after inner"),
+			),
+			result,
+		)
+	})
+
+	t.Run("nested, outer failure", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithFunctions(t, `
+          let steps: [String] = []
+
+          fun inner() {
+              steps.append("inner")
+          }
+
+          fun outer() {
+              steps.append("outer")
+              try inner()
+              panic("failed")
+          }
+
+          fun test(): Int {
+              try outer()
+              return steps.length
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			interpreter.NewIntValueFromInt64(0),
+			result,
+		)
+	})
+
+	t.Run("destruction is rolled back", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithFunctions(t, `
+          resource R {
+              pub let id: Int
+
+              init(id: Int) {
+                  self.id = id
+              }
+          }
+
+          fun destroyAndFail(_ rs: &{Int: R}) {
+              let r <- rs.remove(key: 1)!
+              destroy r
+              panic("failed")
+          }
+
+          fun test(): Int {
+              let rs <- {1: <-create R(id: 1)}
+              try destroyAndFail(&rs as &{Int: R})
+              let id = rs[1]?.id ?? 0
+              destroy rs
+              return id
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			interpreter.NewIntValueFromInt64(1),
+			result,
+		)
+	})
+
+	t.Run("events", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpretWithFunctions(t, `
+          event E(value: Int)
+
+          fun emitAndFail() {
+              emit E(value: 1)
+              panic("failed")
+          }
+
+          fun emitInner() {
+              emit E(value: 2)
+          }
+
+          fun emitNested() {
+              emit E(value: 3)
+              try emitAndFail()
+              try emitInner()
+              emit E(value: 4)
+          }
+
+          fun test() {
+              emit E(value: 0)
+              try emitAndFail()
+              try emitNested()
+              emit E(value: 5)
+          }
+        `)
+
+		var values []interpreter.Value
+
+		inter.SetOnEventEmittedHandler(
+			func(_ *interpreter.Interpreter, event *interpreter.CompositeValue, _ *sema.CompositeType) error {
+				values = append(values, event.GetField("value"))
+				return nil
+			},
+		)
+
+		_, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]interpreter.Value{
+				interpreter.NewIntValueFromInt64(0),
+				interpreter.NewIntValueFromInt64(3),
+				interpreter.NewIntValueFromInt64(2),
+				interpreter.NewIntValueFromInt64(4),
+				interpreter.NewIntValueFromInt64(5),
+			},
+			values,
+		)
+	})
+
+	t.Run("unrecoverable error", func(t *testing.T) {
+
+		t.Parallel()
+
+		failFunction := stdlib.NewStandardLibraryFunction(
+			"fail",
+			&sema.FunctionType{
+				ReturnTypeAnnotation: sema.NewTypeAnnotation(
+					sema.VoidType,
+				),
+			},
+			``,
+			func(invocation interpreter.Invocation) interpreter.Value {
+				panic(testUnrecoverableError{})
+			},
+		)
+
+		inter := parseCheckAndInterpretWithFunctions(t,
+			`
+              fun test(): Bool {
+                  return try fail() == nil
+              }
+            `,
+			failFunction,
+		)
+
+		_, err := inter.Invoke("test")
+		require.Error(t, err)
+
+		require.ErrorAs(t, err, &testUnrecoverableError{})
+	})
+
+	t.Run("irreversible effect", func(t *testing.T) {
+
+		t.Parallel()
+
+		effectFunction := stdlib.NewStandardLibraryFunction(
+			"effect",
+			&sema.FunctionType{
+				ReturnTypeAnnotation: sema.NewTypeAnnotation(
+					sema.VoidType,
+				),
+			},
+			``,
+			func(invocation interpreter.Invocation) interpreter.Value {
+				invocation.Interpreter.RecordIrreversibleEffect()
+				return interpreter.VoidValue{}
+			},
+		)
+
+		inter := parseCheckAndInterpretWithFunctions(t,
+			`
+              fun effectAndFail() {
+                  effect()
+                  panic("failed")
+              }
+
+              fun test(): Bool {
+                  return try effectAndFail() == nil
+              }
+            `,
+			effectFunction,
+		)
+
+		_, err := inter.Invoke("test")
+		require.Error(t, err)
+
+		require.ErrorAs(t, err, &stdlib.PanicError{})
+	})
+}
+
+type testUnrecoverableError struct{}
+
+func (testUnrecoverableError) Error() string {
+	return "unrecoverable"
+}
+
+func (testUnrecoverableError) IsUnrecoverable() {}
@@ -0,0 +1,258 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestRuntimeTime(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := NewInterpreterRuntime()
+
+	// NOTE: the current block of the test runtime interface has height 1,
+	// and the timestamp of each block is its height in seconds
+
+	executeScript := func(code string) (cadence.Value, error) {
+		return runtime.ExecuteScript(
+			Script{
+				Source: []byte(code),
+			},
+			Context{
+				Interface: &testRuntimeInterface{},
+				Location:  utils.TestLocation,
+			},
+		)
+	}
+
+	t.Run("block timestamps", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, err := executeScript(`
+          import Time
+
+          pub fun main(): [UFix64] {
+              return [
+                  Time.now().seconds,
+                  Time.blockTimestamp(at: 100)!.seconds,
+                  Time.timestampOf(getBlock(at: 42)!).seconds
+              ]
+          }
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			cadence.NewArray([]cadence.Value{
+				cadence.UFix64(1_00000000),
+				cadence.UFix64(100_00000000),
+				cadence.UFix64(42_00000000),
+			}),
+			result,
+		)
+	})
+
+	t.Run("arithmetic and comparison", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, err := executeScript(`
+          import Time
+
+          pub fun main(): [AnyStruct] {
+              let now = Time.now()
+              let later = now.add(Time.day.multiply(2.0))
+              let block = Time.blockTimestamp(at: 100)!
+
+              return [
+                  later.seconds,
+                  later.isAfter(now),
+                  later.isBefore(now),
+                  now.isBefore(block),
+                  later.durationSince(now).equals(Time.hour.multiply(48.0)),
+                  block.subtract(Time.minute).seconds,
+                  Time.week.subtract(Time.day).isLongerThan(Time.day.multiply(6.0)),
+                  Time.second.add(Time.minute).isShorterThan(Time.hour)
+              ]
+          }
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			cadence.NewArray([]cadence.Value{
+				cadence.UFix64(172801_00000000),
+				cadence.NewBool(true),
+				cadence.NewBool(false),
+				cadence.NewBool(true),
+				cadence.NewBool(true),
+				cadence.UFix64(40_00000000),
+				cadence.NewBool(false),
+				cadence.NewBool(true),
+			}),
+			result,
+		)
+	})
+
+	t.Run("timestamp before epoch", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := executeScript(`
+          import Time
+
+          pub fun main(): Time.Timestamp {
+              return Time.now().subtract(Time.minute)
+          }
+        `)
+		require.Error(t, err)
+		require.ErrorAs(t, err, &interpreter.ConditionError{})
+	})
+
+	t.Run("duration since later timestamp", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := executeScript(`
+          import Time
+
+          pub fun main(): Time.Duration {
+              return Time.now().durationSince(Time.blockTimestamp(at: 2)!)
+          }
+        `)
+		require.Error(t, err)
+		require.ErrorAs(t, err, &interpreter.ConditionError{})
+	})
+}
+
+func TestRuntimeTimeLockedContract(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := NewInterpreterRuntime()
+
+	address := common.BytesToAddress([]byte{0x1})
+
+	contract := []byte(`
+      import Time
+
+      pub contract TimeLock {
+
+          pub let unlockTime: Time.Timestamp
+
+          init() {
+              self.unlockTime = Time.now().add(Time.day)
+          }
+
+          pub fun isUnlocked(): Bool {
+              return !Time.now().isBefore(self.unlockTime)
+          }
+      }
+    `)
+
+	script := []byte(`
+      import Time
+      import TimeLock from 0x1
+
+      pub fun main(): [AnyStruct] {
+          return [
+              TimeLock.unlockTime.seconds,
+              TimeLock.isUnlocked(),
+              TimeLock.unlockTime.isAfter(Time.now())
+          ]
+      }
+    `)
+
+	var accountCode []byte
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestStorage(nil, nil),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{address}, nil
+		},
+		resolveLocation: func(identifiers []Identifier, location Location) ([]ResolvedLocation, error) {
+			if addressLocation, ok := location.(common.AddressLocation); ok {
+				location = common.AddressLocation{
+					Address: addressLocation.Address,
+					Name:    identifiers[0].Identifier,
+				}
+			}
+			return []ResolvedLocation{
+				{
+					Location:    location,
+					Identifiers: identifiers,
+				},
+			}, nil
+		},
+		getAccountContractCode: func(_ Address, _ string) (code []byte, err error) {
+			return accountCode, nil
+		},
+		updateAccountContractCode: func(_ Address, _ string, code []byte) error {
+			accountCode = code
+			return nil
+		},
+		emitEvent: func(event cadence.Event) error {
+			return nil
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	err := runtime.ExecuteTransaction(
+		Script{
+			Source: utils.DeploymentTransaction("TimeLock", contract),
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+
+	// The stored timestamp is loaded, and its functions are available
+
+	result, err := runtime.ExecuteScript(
+		Script{
+			Source: script,
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		cadence.NewArray([]cadence.Value{
+			cadence.UFix64(86401_00000000),
+			cadence.NewBool(false),
+			cadence.NewBool(true),
+		}),
+		result,
+	)
+}
@@ -0,0 +1,152 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/stdlib"
+)
+
+func TestRuntimeTry(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("storage writes are rolled back", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := NewInterpreterRuntime()
+
+		script := []byte(`
+          pub fun saveAndFail(_ signer: AuthAccount) {
+              signer.save(1, to: /storage/one)
+              panic("failed")
+          }
+
+          pub fun save(_ signer: AuthAccount) {
+              signer.save(2, to: /storage/two)
+          }
+
+          transaction {
+              prepare(signer: AuthAccount) {
+                  log(try saveAndFail(signer))
+                  log(try save(signer))
+                  log(signer.copy<Int>(from: /storage/one))
+                  log(signer.copy<Int>(from: /storage/two))
+              }
+          }
+        `)
+
+		var loggedMessages []string
+		var writtenKeys []string
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestStorage(
+				nil,
+				func(_, key, value []byte) {
+					if len(value) > 0 {
+						writtenKeys = append(writtenKeys, string(key))
+					}
+				},
+			),
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{{42}}, nil
+			},
+			log: func(message string) {
+				loggedMessages = append(loggedMessages, message)
+			},
+		}
+
+		nextTransactionLocation := newTransactionLocationGenerator()
+
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: script,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]string{"nil", "()", "nil", "2"},
+			loggedMessages,
+		)
+
+		assert.Equal(t,
+			[]string{"storage\x1ftwo"},
+			writtenKeys,
+		)
+	})
+
+	t.Run("irreversible effect", func(t *testing.T) {
+
+		t.Parallel()
+
+		runtime := NewInterpreterRuntime()
+
+		script := []byte(`
+          pub fun createAndFail(_ signer: AuthAccount) {
+              AuthAccount(payer: signer)
+              panic("failed")
+          }
+
+          transaction {
+              prepare(signer: AuthAccount) {
+                  try createAndFail(signer)
+              }
+          }
+        `)
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestStorage(nil, nil),
+			getSigningAccounts: func() ([]Address, error) {
+				return []Address{{42}}, nil
+			},
+			createAccount: func(payer Address) (address Address, err error) {
+				return Address{43}, nil
+			},
+			emitEvent: func(event cadence.Event) error {
+				return nil
+			},
+		}
+
+		nextTransactionLocation := newTransactionLocationGenerator()
+
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: script,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.Error(t, err)
+
+		require.ErrorAs(t, err, &stdlib.PanicError{})
+	})
+}
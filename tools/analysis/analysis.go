@@ -0,0 +1,44 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package analysis implements a framework for the static analysis of Cadence programs.
+//
+// An analyzer inspects a parsed and checked program, provided as a pass,
+// and reports diagnostics for code it considers problematic.
+// The analyzers are run over a set of programs, which are loaded,
+// including their imports, using the Load function.
+//
+// Analyzers may be registered with the RegisterAnalyzer function,
+// which allows them to be provided by plugins.
+//
+package analysis
+
+// Analyzer is a static analysis
+//
+type Analyzer struct {
+	// Name is the name of the analyzer
+	Name string
+	// Description is a human-readable description of the analyzer
+	Description string
+	// Requires are the analyzers which must be run before this analyzer.
+	// Their results are available in the pass
+	Requires []*Analyzer
+	// Run runs the analyzer on the program of the given pass,
+	// and returns its result, if any
+	Run func(*Pass) interface{}
+}
@@ -0,0 +1,224 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+func codeResolver(codes map[common.LocationID]string) func(common.Location, common.Location, ast.Range) (string, error) {
+	return func(location common.Location, _ common.Location, _ ast.Range) (string, error) {
+		code, ok := codes[location.ID()]
+		if !ok {
+			return "", fmt.Errorf("unknown location: %s", location)
+		}
+		return code, nil
+	}
+}
+
+func TestLoad(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("imports", func(t *testing.T) {
+
+		t.Parallel()
+
+		libraryLocation := common.StringLocation("library")
+		mainLocation := common.StringLocation("main")
+
+		config := &analysis.Config{
+			ResolveCode: codeResolver(map[common.LocationID]string{
+				libraryLocation.ID(): `pub fun answer(): Int { return 42 }`,
+				mainLocation.ID(): `
+                  import answer from "library"
+
+                  pub fun main(): Int { return answer() }
+                `,
+			}),
+		}
+
+		programs, err := analysis.Load(config, mainLocation)
+		require.NoError(t, err)
+
+		require.Len(t, programs, 2)
+
+		for _, location := range []common.Location{libraryLocation, mainLocation} {
+			program := programs[location.ID()]
+			require.NotNil(t, program)
+			assert.Equal(t, location, program.Location)
+			assert.NotNil(t, program.Program)
+			assert.NotNil(t, program.Elaboration)
+		}
+	})
+
+	t.Run("cyclic import", func(t *testing.T) {
+
+		t.Parallel()
+
+		aLocation := common.StringLocation("a")
+		bLocation := common.StringLocation("b")
+
+		config := &analysis.Config{
+			ResolveCode: codeResolver(map[common.LocationID]string{
+				aLocation.ID(): `import b from "b"`,
+				bLocation.ID(): `import a from "a"`,
+			}),
+		}
+
+		_, err := analysis.Load(config, aLocation)
+		require.Error(t, err)
+	})
+
+	t.Run("checking error", func(t *testing.T) {
+
+		t.Parallel()
+
+		location := common.StringLocation("main")
+
+		config := &analysis.Config{
+			ResolveCode: codeResolver(map[common.LocationID]string{
+				location.ID(): `pub let x: Int = "1"`,
+			}),
+		}
+
+		_, err := analysis.Load(config, location)
+		require.Error(t, err)
+		require.IsType(t, &sema.CheckerError{}, err)
+	})
+
+	t.Run("missing access modifiers", func(t *testing.T) {
+
+		t.Parallel()
+
+		location := common.StringLocation("main")
+
+		config := &analysis.Config{
+			ResolveCode: codeResolver(map[common.LocationID]string{
+				location.ID(): `contract C { let x: Int; init() { self.x = 1 } }`,
+			}),
+		}
+
+		_, err := analysis.Load(config, location)
+		require.NoError(t, err)
+
+		strict := sema.AccessCheckModeStrict
+		config.AccessCheckMode = &strict
+
+		_, err = analysis.Load(config, location)
+		require.Error(t, err)
+	})
+}
+
+func TestRun(t *testing.T) {
+
+	t.Parallel()
+
+	location := common.StringLocation("main")
+
+	config := &analysis.Config{
+		ResolveCode: codeResolver(map[common.LocationID]string{
+			location.ID(): `
+              pub fun a() {}
+              pub fun b() {}
+            `,
+		}),
+	}
+
+	programs, err := analysis.Load(config, location)
+	require.NoError(t, err)
+
+	runs := 0
+
+	functionsAnalyzer := &analysis.Analyzer{
+		Name: "functions",
+		Run: func(pass *analysis.Pass) interface{} {
+			runs++
+			return pass.Program.Program.FunctionDeclarations()
+		},
+	}
+
+	reportingAnalyzer := &analysis.Analyzer{
+		Name:     "reporting",
+		Requires: []*analysis.Analyzer{functionsAnalyzer},
+		Run: func(pass *analysis.Pass) interface{} {
+			functions := pass.ResultOf[functionsAnalyzer].([]*ast.FunctionDeclaration)
+			for _, function := range functions {
+				pass.Report(analysis.Diagnostic{
+					Location: pass.Program.Location,
+					Category: "test",
+					Message:  function.Identifier.Identifier,
+					Range:    ast.NewRangeFromPositioned(function.Identifier),
+				})
+			}
+			return nil
+		},
+	}
+
+	var diagnostics []analysis.Diagnostic
+
+	programs.Run(
+		[]*analysis.Analyzer{reportingAnalyzer, functionsAnalyzer},
+		func(diagnostic analysis.Diagnostic) {
+			diagnostics = append(diagnostics, diagnostic)
+		},
+	)
+
+	// The required analyzer is only run once
+
+	assert.Equal(t, 1, runs)
+
+	require.Len(t, diagnostics, 2)
+	assert.Equal(t, "a", diagnostics[0].Message)
+	assert.Equal(t, "b", diagnostics[1].Message)
+	assert.Equal(t, location, diagnostics[0].Location)
+}
+
+func TestRegisterAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	analyzer := &analysis.Analyzer{
+		Name: "test-register-analyzer",
+		Run: func(_ *analysis.Pass) interface{} {
+			return nil
+		},
+	}
+
+	analysis.RegisterAnalyzer(analyzer)
+
+	registeredAnalyzer, ok := analysis.LookupAnalyzer(analyzer.Name)
+	require.True(t, ok)
+	assert.Same(t, analyzer, registeredAnalyzer)
+
+	assert.Contains(t, analysis.RegisteredAnalyzers(), analyzer)
+
+	assert.Panics(t, func() {
+		analysis.RegisterAnalyzer(analyzer)
+	})
+}
@@ -0,0 +1,210 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analyzers_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/tools/analysis"
+	"github.com/onflow/cadence/tools/analysis/analyzers"
+)
+
+func analyze(t *testing.T, analyzer *analysis.Analyzer, code string) []analysis.Diagnostic {
+	location := common.StringLocation("test")
+
+	config := &analysis.Config{
+		ResolveCode: func(_ common.Location, _ common.Location, _ ast.Range) (string, error) {
+			return code, nil
+		},
+	}
+
+	programs, err := analysis.Load(config, location)
+	require.NoError(t, err)
+
+	var diagnostics []analysis.Diagnostic
+
+	programs.Run(
+		[]*analysis.Analyzer{analyzer},
+		func(diagnostic analysis.Diagnostic) {
+			diagnostics = append(diagnostics, diagnostic)
+		},
+	)
+
+	return diagnostics
+}
+
+func diagnosticMessages(diagnostics []analysis.Diagnostic) []string {
+	messages := make([]string, 0, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		messages = append(messages, diagnostic.Message)
+	}
+	return messages
+}
+
+func TestRegisteredAnalyzers(t *testing.T) {
+
+	t.Parallel()
+
+	for _, analyzer := range analyzers.Analyzers {
+		registeredAnalyzer, ok := analysis.LookupAnalyzer(analyzer.Name)
+		require.True(t, ok)
+		assert.Same(t, analyzer, registeredAnalyzer)
+	}
+}
+
+func TestMissingAccessModifierAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := analyze(t,
+		analyzers.MissingAccessModifierAnalyzer,
+		`
+          pub fun main() {}
+
+          fun helper() {}
+
+          contract C {
+              pub let x: Int
+              let y: Int
+
+              event E()
+
+              pub resource R {
+                  fun f() {}
+              }
+
+              init() {
+                  self.x = 1
+                  self.y = 2
+              }
+          }
+        `,
+	)
+
+	assert.Equal(t,
+		[]string{
+			"missing access modifier for function `helper`",
+			"missing access modifier for contract `C`",
+			"missing access modifier for field `y`",
+			"missing access modifier for event `E`",
+			"missing access modifier for function `f`",
+		},
+		diagnosticMessages(diagnostics),
+	)
+
+	assert.Equal(t,
+		ast.Position{Offset: 127, Line: 8, Column: 18},
+		diagnostics[2].StartPos,
+	)
+}
+
+func TestUnboundedStorageLoopAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := analyze(t,
+		analyzers.UnboundedStorageLoopAnalyzer,
+		`
+          pub contract C {
+              pub let ids: [UInt64]
+              pub let names: {String: Int}
+
+              pub struct S {
+                  pub let values: [Int]
+
+                  init() {
+                      self.values = []
+                  }
+
+                  pub fun sum(): Int {
+                      var sum = 0
+                      for value in self.values {
+                          sum = sum + value
+                      }
+                      return sum
+                  }
+              }
+
+              pub fun count(): Int {
+                  var count = 0
+                  for id in self.ids {
+                      count = count + 1
+                  }
+                  for name in self.names.keys {
+                      count = count + 1
+                  }
+                  for number in [1, 2, 3] {
+                      count = count + number
+                  }
+                  return count
+              }
+
+              init() {
+                  self.ids = []
+                  self.names = {}
+              }
+          }
+        `,
+	)
+
+	assert.Equal(t,
+		[]string{
+			"loop over stored field `ids`, which may grow unbounded",
+			"loop over stored field `names`, which may grow unbounded",
+		},
+		diagnosticMessages(diagnostics),
+	)
+}
+
+func TestExternalCapabilityForceUnwrapAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := analyze(t,
+		analyzers.ExternalCapabilityForceUnwrapAnalyzer,
+		`
+          pub resource interface Receiver {}
+
+          pub fun main(address: Address) {
+              let account = getAccount(address)
+
+              let receiver = account.getCapability(/public/receiver)
+                  .borrow<&AnyResource{Receiver}>()!
+
+              let optionalReceiver = account.getCapability(/public/receiver)
+                  .borrow<&AnyResource{Receiver}>()
+
+              let capability = account.getCapability<&AnyResource{Receiver}>(/public/receiver)
+              let checked = capability.check()
+          }
+        `,
+	)
+
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t,
+		"force-unwrap of a borrow of a capability of another account",
+		diagnostics[0].Message,
+	)
+	assert.Equal(t, 7, diagnostics[0].StartPos.Line)
+}
@@ -0,0 +1,95 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analyzers
+
+import (
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const capabilityBorrowFunctionName = "borrow"
+
+// ExternalCapabilityForceUnwrapAnalyzer reports force-unwraps of borrows
+// of capabilities of public accounts, e.g. `getAccount(address).getCapability(path).borrow<&R>()!`.
+//
+// The owner of the account can unlink or replace the capability at any time,
+// which would make the program abort
+//
+var ExternalCapabilityForceUnwrapAnalyzer = &analysis.Analyzer{
+	Name:        "external-capability-force-unwrap",
+	Description: "Reports force-unwraps of borrows of capabilities of other accounts",
+	Run: func(pass *analysis.Pass) interface{} {
+		program := pass.Program
+		elaboration := program.Elaboration
+
+		ast.Inspect(program.Program, func(element ast.Element) bool {
+			forceExpression, ok := element.(*ast.ForceExpression)
+			if !ok {
+				return true
+			}
+
+			// Find `<capability>.borrow<T>()!`
+
+			borrowInvocation, ok := forceExpression.Expression.(*ast.InvocationExpression)
+			if !ok {
+				return true
+			}
+
+			borrowMember, ok := borrowInvocation.InvokedExpression.(*ast.MemberExpression)
+			if !ok || borrowMember.Identifier.Identifier != capabilityBorrowFunctionName {
+				return true
+			}
+
+			borrowMemberInfo := elaboration.MemberExpressionMemberInfos[borrowMember]
+			if _, ok := borrowMemberInfo.AccessedType.(*sema.CapabilityType); !ok {
+				return true
+			}
+
+			// Find `<public account>.getCapability(path)`
+
+			getCapabilityInvocation, ok := borrowMember.Expression.(*ast.InvocationExpression)
+			if !ok {
+				return true
+			}
+
+			getCapabilityMember, ok := getCapabilityInvocation.InvokedExpression.(*ast.MemberExpression)
+			if !ok || getCapabilityMember.Identifier.Identifier != sema.PublicAccountGetCapabilityField {
+				return true
+			}
+
+			getCapabilityMemberInfo := elaboration.MemberExpressionMemberInfos[getCapabilityMember]
+			if getCapabilityMemberInfo.AccessedType != sema.PublicAccountType {
+				return true
+			}
+
+			pass.Report(analysis.Diagnostic{
+				Location:         program.Location,
+				Category:         "safety",
+				Message:          "force-unwrap of a borrow of a capability of another account",
+				SecondaryMessage: "the capability may be unlinked by the account. handle the `nil` case instead",
+				Range:            ast.NewRangeFromPositioned(forceExpression),
+			})
+
+			return true
+		})
+
+		return nil
+	},
+}
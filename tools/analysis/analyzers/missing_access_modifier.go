@@ -0,0 +1,107 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analyzers
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+// MissingAccessModifierAnalyzer reports declarations which have no access modifier.
+//
+// The access of declarations should be declared explicitly,
+// as programs with declarations without an access modifier are rejected when deployed
+//
+var MissingAccessModifierAnalyzer = &analysis.Analyzer{
+	Name:        "missing-access-modifier",
+	Description: "Reports declarations which have no access modifier",
+	Run: func(pass *analysis.Pass) interface{} {
+		program := pass.Program
+
+		var report func(declarations []ast.Declaration)
+		report = func(declarations []ast.Declaration) {
+			for _, declaration := range declarations {
+
+				var members *ast.Members
+
+				switch declaration := declaration.(type) {
+				case *ast.CompositeDeclaration:
+					members = declaration.Members
+
+				case *ast.InterfaceDeclaration:
+					members = declaration.Members
+
+				case *ast.FieldDeclaration,
+					*ast.FunctionDeclaration,
+					*ast.VariableDeclaration:
+
+				default:
+					continue
+				}
+
+				if declaration.DeclarationAccess() == ast.AccessNotSpecified {
+					identifier := declaration.DeclarationIdentifier()
+
+					pass.Report(analysis.Diagnostic{
+						Location: program.Location,
+						Category: "access",
+						Message: fmt.Sprintf(
+							"missing access modifier for %s `%s`",
+							declaration.DeclarationKind().Name(),
+							identifier.Identifier,
+						),
+						SecondaryMessage: "declare the access explicitly, e.g. `pub` or `priv`",
+						Range:            ast.NewRangeFromPositioned(identifier),
+					})
+				}
+
+				if members != nil {
+					report(members.Declarations())
+				}
+			}
+		}
+
+		report(topLevelDeclarations(program.Program))
+
+		return nil
+	},
+}
+
+// topLevelDeclarations returns the declarations of the given program
+// which are not part of a transaction, as transactions have no access modifiers
+//
+func topLevelDeclarations(program *ast.Program) []ast.Declaration {
+	declarations := make([]ast.Declaration, 0, len(program.Declarations()))
+
+	for _, declaration := range program.Declarations() {
+		switch declaration.DeclarationKind() {
+		case common.DeclarationKindTransaction,
+			common.DeclarationKindImport,
+			common.DeclarationKindPragma:
+			continue
+		}
+
+		declarations = append(declarations, declaration)
+	}
+
+	return declarations
+}
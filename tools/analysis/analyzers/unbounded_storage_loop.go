@@ -0,0 +1,110 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analyzers
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+// UnboundedStorageLoopAnalyzer reports loops over the fields of resources and contracts,
+// or over the keys or values of such fields.
+//
+// These fields are stored, and can grow unbounded,
+// so a loop over them may eventually exceed the computation limit
+//
+var UnboundedStorageLoopAnalyzer = &analysis.Analyzer{
+	Name:        "unbounded-storage-loop",
+	Description: "Reports loops over stored arrays and dictionaries, which may grow unbounded",
+	Run: func(pass *analysis.Pass) interface{} {
+		program := pass.Program
+		elaboration := program.Elaboration
+
+		ast.Inspect(program.Program, func(element ast.Element) bool {
+			forStatement, ok := element.(*ast.ForStatement)
+			if !ok {
+				return true
+			}
+
+			memberExpression, ok := forStatement.Value.(*ast.MemberExpression)
+			if !ok {
+				return true
+			}
+
+			// Loops over the keys or values of a dictionary field
+			// are also loops over the field
+
+			switch memberExpression.Identifier.Identifier {
+			case "keys", "values":
+				memberInfo := elaboration.MemberExpressionMemberInfos[memberExpression]
+				if _, ok := memberInfo.AccessedType.(*sema.DictionaryType); ok {
+					innerMemberExpression, ok := memberExpression.Expression.(*ast.MemberExpression)
+					if ok {
+						memberExpression = innerMemberExpression
+					}
+				}
+			}
+
+			if !isStoredField(elaboration.MemberExpressionMemberInfos[memberExpression].Member) {
+				return true
+			}
+
+			pass.Report(analysis.Diagnostic{
+				Location: program.Location,
+				Category: "performance",
+				Message: fmt.Sprintf(
+					"loop over stored field `%s`, which may grow unbounded",
+					memberExpression.Identifier.Identifier,
+				),
+				SecondaryMessage: "the loop may eventually exceed the computation limit",
+				Range:            ast.NewRangeFromPositioned(forStatement.Value),
+			})
+
+			return true
+		})
+
+		return nil
+	},
+}
+
+// isStoredField returns true if the given member is a field
+// of a resource or contract, which are stored
+//
+func isStoredField(member *sema.Member) bool {
+	if member == nil || member.DeclarationKind != common.DeclarationKindField {
+		return false
+	}
+
+	compositeType, ok := member.ContainerType.(*sema.CompositeType)
+	if !ok {
+		return false
+	}
+
+	switch compositeType.Kind {
+	case common.CompositeKindResource,
+		common.CompositeKindContract:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,150 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"plugin"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/pretty"
+	"github.com/onflow/cadence/tools/analysis"
+	_ "github.com/onflow/cadence/tools/analysis/analyzers"
+)
+
+var analyzersFlag = flag.String("analyzers", "", "comma-separated names of the analyzers to run. defaults to all analyzers")
+var pluginsFlag = flag.String("plugins", "", "comma-separated paths of plugins which register additional analyzers")
+var listFlag = flag.Bool("list", false, "list the available analyzers")
+
+// An analysis tool for Cadence programs.
+//
+// Usage: analysis [-analyzers name,...] [-plugins path,...] path ...
+//
+// Plugins are Go plugins which register their analyzers
+// using analysis.RegisterAnalyzer when they are loaded.
+//
+func main() {
+	flag.Parse()
+
+	for _, path := range splitList(*pluginsFlag) {
+		_, err := plugin.Open(path)
+		if err != nil {
+			exitWithError(fmt.Sprintf("failed to load plugin %s: %s", path, err))
+		}
+	}
+
+	if *listFlag {
+		for _, analyzer := range analysis.RegisteredAnalyzers() {
+			fmt.Printf("%s\t%s\n", analyzer.Name, analyzer.Description)
+		}
+		return
+	}
+
+	var analyzers []*analysis.Analyzer
+
+	names := splitList(*analyzersFlag)
+	if len(names) == 0 {
+		analyzers = analysis.RegisteredAnalyzers()
+	} else {
+		for _, name := range names {
+			analyzer, ok := analysis.LookupAnalyzer(name)
+			if !ok {
+				exitWithError(fmt.Sprintf("unknown analyzer: %s", name))
+			}
+			analyzers = append(analyzers, analyzer)
+		}
+	}
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		exitWithError("no programs given")
+	}
+
+	codes := map[common.LocationID]string{}
+
+	config := &analysis.Config{
+		ResolveCode: func(
+			location common.Location,
+			_ common.Location,
+			_ ast.Range,
+		) (string, error) {
+			stringLocation, ok := location.(common.StringLocation)
+			if !ok {
+				return "", fmt.Errorf("cannot import `%s`. only files are supported", location)
+			}
+
+			code, err := ioutil.ReadFile(string(stringLocation))
+			if err != nil {
+				return "", err
+			}
+
+			codes[location.ID()] = string(code)
+
+			return string(code), nil
+		},
+	}
+
+	locations := make([]common.Location, 0, len(paths))
+	for _, path := range paths {
+		locations = append(locations, common.StringLocation(path))
+	}
+
+	programs, err := analysis.Load(config, locations...)
+	if err != nil {
+		printErr := pretty.NewErrorPrettyPrinter(os.Stderr, true).
+			PrettyPrintError(err, nil, codes)
+		if printErr != nil {
+			panic(printErr)
+		}
+		os.Exit(1)
+	}
+
+	reported := false
+
+	programs.Run(analyzers, func(diagnostic analysis.Diagnostic) {
+		reported = true
+
+		printErr := pretty.NewErrorPrettyPrinter(os.Stdout, true).
+			PrettyPrintError(diagnostic, diagnostic.Location, codes)
+		if printErr != nil {
+			panic(printErr)
+		}
+	})
+
+	if reported {
+		os.Exit(1)
+	}
+}
+
+func splitList(list string) []string {
+	if len(list) == 0 {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
+
+func exitWithError(message string) {
+	print(pretty.FormatErrorMessage(message, true))
+	os.Exit(2)
+}
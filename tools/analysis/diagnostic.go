@@ -0,0 +1,47 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// Diagnostic is a problem in a program, reported by an analyzer
+//
+type Diagnostic struct {
+	Location common.Location
+	// Category is the category of the problem, usually the name of the analyzer
+	Category         string
+	Message          string
+	SecondaryMessage string
+	ast.Range
+}
+
+func (d Diagnostic) Error() string {
+	return d.Message
+}
+
+func (d Diagnostic) SecondaryError() string {
+	return d.SecondaryMessage
+}
+
+func (d Diagnostic) ImportLocation() common.Location {
+	return d.Location
+}
@@ -0,0 +1,218 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/parser2"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+)
+
+// Program is a parsed and checked program
+//
+type Program struct {
+	Location    common.Location
+	Code        string
+	Program     *ast.Program
+	Elaboration *sema.Elaboration
+}
+
+// Programs are the loaded programs, by location
+//
+type Programs map[common.LocationID]*Program
+
+// Config configures how programs are loaded
+//
+type Config struct {
+	// ResolveCode returns the code of the program at the given location.
+	// The importing location and the import range are only set for imported programs
+	ResolveCode func(
+		location common.Location,
+		importingLocation common.Location,
+		importRange ast.Range,
+	) (string, error)
+	// ResolveLocation optionally resolves the locations of imports,
+	// e.g. an address location to the locations of the individual contracts
+	ResolveLocation sema.LocationHandlerFunc
+	// AccessCheckMode is the access check mode used for checking.
+	// By default, declarations without access modifiers are accepted,
+	// so they can be reported by an analyzer instead
+	AccessCheckMode *sema.AccessCheckMode
+}
+
+var valueDeclarations = append(
+	stdlib.FlowBuiltInFunctions(stdlib.DefaultFlowBuiltinImpls()),
+	stdlib.BuiltinFunctions...,
+).ToSemaValueDeclarations()
+
+var typeDeclarations = append(
+	stdlib.FlowBuiltInTypes,
+	stdlib.BuiltinTypes...,
+).ToTypeDeclarations()
+
+// Load parses and checks the programs at the given locations, and the programs they import
+//
+func Load(config *Config, locations ...common.Location) (Programs, error) {
+	programs := Programs{}
+
+	for _, location := range locations {
+		err := programs.load(config, location, nil, ast.Range{})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return programs, nil
+}
+
+func (programs Programs) load(
+	config *Config,
+	location common.Location,
+	importingLocation common.Location,
+	importRange ast.Range,
+) error {
+	if programs[location.ID()] != nil {
+		return nil
+	}
+
+	code, err := config.ResolveCode(location, importingLocation, importRange)
+	if err != nil {
+		return err
+	}
+
+	program, err := parser2.ParseProgram(code)
+	if err != nil {
+		return err
+	}
+
+	accessCheckMode := sema.AccessCheckModeNotSpecifiedUnrestricted
+	if config.AccessCheckMode != nil {
+		accessCheckMode = *config.AccessCheckMode
+	}
+
+	options := []sema.Option{
+		sema.WithAccessCheckMode(accessCheckMode),
+		sema.WithPredeclaredValues(valueDeclarations),
+		sema.WithPredeclaredTypes(typeDeclarations),
+		sema.WithImportHandler(
+			func(checker *sema.Checker, importedLocation common.Location, importRange ast.Range) (sema.Import, error) {
+
+				importedProgram := programs[importedLocation.ID()]
+				if importedProgram == nil {
+					err := programs.load(config, importedLocation, location, importRange)
+					if err != nil {
+						return nil, err
+					}
+					importedProgram = programs[importedLocation.ID()]
+				}
+
+				// The imported program is still being checked
+				if importedProgram.Elaboration == nil {
+					return nil, fmt.Errorf("cyclic import of %s", importedLocation)
+				}
+
+				return sema.ElaborationImport{
+					Elaboration: importedProgram.Elaboration,
+				}, nil
+			},
+		),
+	}
+
+	if config.ResolveLocation != nil {
+		options = append(options, sema.WithLocationHandler(config.ResolveLocation))
+	}
+
+	checker, err := sema.NewChecker(program, location, options...)
+	if err != nil {
+		return err
+	}
+
+	analysisProgram := &Program{
+		Location: location,
+		Code:     code,
+		Program:  program,
+	}
+	programs[location.ID()] = analysisProgram
+
+	err = checker.Check()
+	if err != nil {
+		return err
+	}
+
+	analysisProgram.Elaboration = checker.Elaboration
+
+	return nil
+}
+
+// Run runs the given analyzers on all programs, in the order of their locations,
+// and reports the diagnostics using the given report function.
+//
+// The analyzers required by the given analyzers are run first,
+// and each analyzer is run at most once per program
+//
+func (programs Programs) Run(analyzers []*Analyzer, report func(Diagnostic)) {
+
+	locationIDs := make([]common.LocationID, 0, len(programs))
+	for locationID := range programs {
+		locationIDs = append(locationIDs, locationID)
+	}
+	sort.Slice(locationIDs, func(i, j int) bool {
+		return locationIDs[i] < locationIDs[j]
+	})
+
+	for _, locationID := range locationIDs {
+		programs[locationID].run(analyzers, report)
+	}
+}
+
+func (program *Program) run(analyzers []*Analyzer, report func(Diagnostic)) {
+	results := map[*Analyzer]interface{}{}
+
+	var runAnalyzer func(analyzer *Analyzer) interface{}
+	runAnalyzer = func(analyzer *Analyzer) interface{} {
+		if result, ok := results[analyzer]; ok {
+			return result
+		}
+
+		resultOf := make(map[*Analyzer]interface{}, len(analyzer.Requires))
+		for _, requiredAnalyzer := range analyzer.Requires {
+			resultOf[requiredAnalyzer] = runAnalyzer(requiredAnalyzer)
+		}
+
+		pass := &Pass{
+			Program:  program,
+			Report:   report,
+			ResultOf: resultOf,
+		}
+
+		result := analyzer.Run(pass)
+		results[analyzer] = result
+
+		return result
+	}
+
+	for _, analyzer := range analyzers {
+		runAnalyzer(analyzer)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2021 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var registeredAnalyzers = map[string]*Analyzer{}
+var registeredAnalyzersLock sync.RWMutex
+
+// RegisterAnalyzer registers the given analyzer under its name.
+//
+// Plugins can register their analyzers when they are loaded,
+// so the analyzers can be selected by name.
+// It panics if an analyzer with the same name is already registered
+//
+func RegisterAnalyzer(analyzer *Analyzer) {
+	registeredAnalyzersLock.Lock()
+	defer registeredAnalyzersLock.Unlock()
+
+	if _, ok := registeredAnalyzers[analyzer.Name]; ok {
+		panic(fmt.Errorf("analyzer already registered: %s", analyzer.Name))
+	}
+
+	registeredAnalyzers[analyzer.Name] = analyzer
+}
+
+// LookupAnalyzer returns the registered analyzer with the given name, if any
+//
+func LookupAnalyzer(name string) (*Analyzer, bool) {
+	registeredAnalyzersLock.RLock()
+	defer registeredAnalyzersLock.RUnlock()
+
+	analyzer, ok := registeredAnalyzers[name]
+	return analyzer, ok
+}
+
+// RegisteredAnalyzers returns all registered analyzers, sorted by name
+//
+func RegisteredAnalyzers() []*Analyzer {
+	registeredAnalyzersLock.RLock()
+	defer registeredAnalyzersLock.RUnlock()
+
+	analyzers := make([]*Analyzer, 0, len(registeredAnalyzers))
+	for _, analyzer := range registeredAnalyzers {
+		analyzers = append(analyzers, analyzer)
+	}
+
+	sort.Slice(analyzers, func(i, j int) bool {
+		return analyzers[i].Name < analyzers[j].Name
+	})
+
+	return analyzers
+}
@@ -19,6 +19,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -28,8 +29,24 @@ import (
 	"github.com/onflow/cadence/tools/docgen"
 )
 
+var formatFlag = flag.String("format", "markdown", "the format of the documentation: markdown or html")
+
 func main() {
-	programArgsCount := len(os.Args) - 1
+	flag.Parse()
+
+	var format docgen.Format
+	switch *formatFlag {
+	case "markdown":
+		format = docgen.FormatMarkdown
+	case "html":
+		format = docgen.FormatHTML
+	default:
+		log.Fatalf("Unsupported format: %s", *formatFlag)
+	}
+
+	args := flag.Args()
+
+	programArgsCount := len(args)
 	if programArgsCount < 2 {
 		log.Fatalf("Not enough arguments: expected 2, found %d", programArgsCount)
 	}
@@ -38,8 +55,8 @@ func main() {
 		log.Fatalf("Too many arguments: expected 2, found %d", programArgsCount)
 	}
 
-	input := os.Args[1]
-	outputDir := os.Args[2]
+	input := args[0]
+	outputDir := args[1]
 
 	content, err := ioutil.ReadFile(input)
 	if err != nil {
@@ -58,7 +75,7 @@ func main() {
 
 	code := string(content)
 
-	docGen := docgen.NewDocGenerator()
+	docGen := docgen.NewDocGenerator(docgen.WithFormat(format))
 	err = docGen.Generate(code, outputDir)
 
 	if err != nil {
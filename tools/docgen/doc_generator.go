@@ -37,6 +37,8 @@ import (
 const nameSeparator = "_"
 const newline = "\n"
 const mdFileExt = ".md"
+const htmlFileExt = ".html"
+const indexFileName = "index"
 const paramPrefix = "@param "
 const returnPrefix = "@return "
 
@@ -54,14 +56,70 @@ var templateFiles = []string{
 	"enum-case-template",
 	"initializer-template",
 	"event-template",
+	"type-references-template",
+}
+
+// Format is the format of the generated documentation
+//
+type Format uint8
+
+const (
+	FormatMarkdown Format = iota
+	FormatHTML
+)
+
+func (f Format) fileExtension() string {
+	switch f {
+	case FormatMarkdown:
+		return mdFileExt
+	case FormatHTML:
+		return htmlFileExt
+	default:
+		panic(fmt.Errorf("unsupported documentation format: %d", f))
+	}
+}
+
+func (f Format) templateProvider() templates.TemplateProvider {
+	switch f {
+	case FormatMarkdown:
+		return templates.NewMarkdownTemplateProvider()
+	case FormatHTML:
+		return templates.NewHTMLTemplateProvider()
+	default:
+		panic(fmt.Errorf("unsupported documentation format: %d", f))
+	}
+}
+
+// Option is a documentation generator option
+//
+type Option func(*DocGenerator)
+
+// WithFormat returns a documentation generator option
+// which sets the format of the generated documentation.
+// The default format is Markdown.
+//
+func WithFormat(format Format) Option {
+	return func(gen *DocGenerator) {
+		gen.format = format
+	}
 }
 
 type DocGenerator struct {
 	entryPageGen     *template.Template
 	compositePageGen *template.Template
+	format           Format
 	typeNames        []string
 	outputDir        string
 	files            InMemoryFiles
+	// typeFileNames are the file names of the documented types, by qualified type name
+	typeFileNames map[string]string
+}
+
+// typeReference is a reference to a documented type
+//
+type typeReference struct {
+	Name     string
+	FileName string
 }
 
 type InMemoryFiles map[string][]byte
@@ -90,28 +148,38 @@ func (w *InMemoryFileWriter) Close() error {
 	return nil
 }
 
-func NewDocGenerator() *DocGenerator {
+func NewDocGenerator(options ...Option) *DocGenerator {
 	gen := &DocGenerator{}
 
-	functions["fileName"] = func(decl ast.Declaration) string {
-		fileNamePrefix := gen.currentFileName()
-		if len(fileNamePrefix) == 0 {
-			return fmt.Sprint(decl.DeclarationIdentifier().String(), mdFileExt)
-		}
+	for _, option := range options {
+		option(gen)
+	}
+
+	funcs := template.FuncMap{}
+	for name, function := range functions {
+		funcs[name] = function
+	}
 
-		return fmt.Sprint(fileNamePrefix, nameSeparator, decl.DeclarationIdentifier().String(), mdFileExt)
+	funcs["fileName"] = func(decl ast.Declaration) string {
+		return gen.declarationFileName(decl)
 	}
 
-	templateProvider := templates.NewMarkdownTemplateProvider()
+	funcs["typeReferences"] = gen.typeReferences
 
-	gen.entryPageGen = newTemplate(baseTemplate, templateProvider)
-	gen.compositePageGen = newTemplate(compositeFullTemplate, templateProvider)
+	templateProvider := gen.format.templateProvider()
+
+	gen.entryPageGen = newTemplate(baseTemplate, templateProvider, funcs)
+	gen.compositePageGen = newTemplate(compositeFullTemplate, templateProvider, funcs)
 
 	return gen
 }
 
-func newTemplate(name string, templateProvider templates.TemplateProvider) *template.Template {
-	rootTemplate := template.New(name).Funcs(functions)
+func newTemplate(
+	name string,
+	templateProvider templates.TemplateProvider,
+	funcs template.FuncMap,
+) *template.Template {
+	rootTemplate := template.New(name).Funcs(funcs)
 
 	for _, templateFile := range templateFiles {
 		content, err := templateProvider.Get(templateFile)
@@ -166,6 +234,10 @@ func (gen *DocGenerator) GenerateInMemory(source string) (InMemoryFiles, error)
 
 func (gen *DocGenerator) genProgram(program *ast.Program) error {
 
+	// Index the documented types, so references to them can be linked
+	gen.typeFileNames = map[string]string{}
+	gen.indexDeclarations(program.Declarations())
+
 	// If its not a sole-declaration, i.e: has multiple top level declarations,
 	// then generated an entry page.
 	if program.SoleContractDeclaration() == nil &&
@@ -173,7 +245,7 @@ func (gen *DocGenerator) genProgram(program *ast.Program) error {
 
 		// Generate entry page
 		// TODO: file name 'index' can conflict with struct names, resulting an overwrite.
-		f, err := gen.fileWriter("index.md")
+		f, err := gen.fileWriter(fmt.Sprint(indexFileName, gen.format.fileExtension()))
 		if err != nil {
 			return err
 		}
@@ -193,6 +265,10 @@ func (gen *DocGenerator) genProgram(program *ast.Program) error {
 func (gen *DocGenerator) genDeclarations(decls []ast.Declaration) error {
 	var err error
 	for _, decl := range decls {
+		if !isPublic(decl) {
+			continue
+		}
+
 		switch astDecl := decl.(type) {
 		case *ast.CompositeDeclaration:
 			err = gen.genCompositeDeclaration(astDecl)
@@ -231,7 +307,7 @@ func (gen *DocGenerator) genCompositeDecl(name string, members *ast.Members, dec
 		gen.typeNames = gen.typeNames[:len(gen.typeNames)-1]
 	}()
 
-	fileName := fmt.Sprint(gen.currentFileName(), mdFileExt)
+	fileName := fmt.Sprint(gen.currentFileName(), gen.format.fileExtension())
 	f, err := gen.fileWriter(fileName)
 	if err != nil {
 		return err
@@ -259,6 +335,238 @@ func (gen *DocGenerator) currentFileName() string {
 	return strings.Join(gen.typeNames, nameSeparator)
 }
 
+// declarationFileName returns the name of the file
+// of the given declaration nested in the current declaration
+//
+func (gen *DocGenerator) declarationFileName(decl ast.Declaration) string {
+	fileNamePrefix := gen.currentFileName()
+	if len(fileNamePrefix) == 0 {
+		return fmt.Sprint(decl.DeclarationIdentifier().String(), gen.format.fileExtension())
+	}
+
+	return fmt.Sprint(
+		fileNamePrefix,
+		nameSeparator,
+		decl.DeclarationIdentifier().String(),
+		gen.format.fileExtension(),
+	)
+}
+
+// indexDeclarations records the file names of the given declarations
+// for which pages are generated, and of their nested declarations
+//
+func (gen *DocGenerator) indexDeclarations(decls []ast.Declaration) {
+	for _, decl := range decls {
+		if !isPublic(decl) {
+			continue
+		}
+
+		var members *ast.Members
+
+		switch astDecl := decl.(type) {
+		case *ast.CompositeDeclaration:
+			if astDecl.DeclarationKind() == common.DeclarationKindEvent {
+				continue
+			}
+			members = astDecl.Members
+
+		case *ast.InterfaceDeclaration:
+			members = astDecl.Members
+
+		default:
+			continue
+		}
+
+		fileName := gen.declarationFileName(decl)
+
+		gen.typeNames = append(gen.typeNames, decl.DeclarationIdentifier().String())
+
+		qualifiedName := strings.Join(gen.typeNames, ".")
+		gen.typeFileNames[qualifiedName] = fileName
+
+		gen.indexDeclarations(members.Declarations())
+
+		gen.typeNames = gen.typeNames[:len(gen.typeNames)-1]
+	}
+}
+
+// typeReferences returns the references to documented types
+// in the signature of the given declaration, e.g. the types of its fields and parameters.
+//
+// References to the type of the current page are omitted
+//
+func (gen *DocGenerator) typeReferences(decl ast.Declaration) []typeReference {
+
+	// Type names are resolved in the scope of the declaration,
+	// i.e. in the current type and the enclosing types.
+	// Composites and interfaces are also their own scope
+
+	scope := gen.typeNames
+
+	switch decl.(type) {
+	case *ast.CompositeDeclaration, *ast.InterfaceDeclaration:
+		name := decl.DeclarationIdentifier().String()
+		if len(scope) == 0 || scope[len(scope)-1] != name {
+			scope = append(scope[:len(scope):len(scope)], name)
+		}
+	}
+
+	currentFileName := fmt.Sprint(gen.currentFileName(), gen.format.fileExtension())
+
+	references := make([]typeReference, 0)
+	seen := map[string]struct{}{}
+
+	for _, nominalType := range declarationNominalTypes(decl) {
+		name := nominalType.String()
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+
+		fileName, ok := gen.resolveTypeFileName(scope, name)
+		if !ok || fileName == currentFileName {
+			continue
+		}
+
+		references = append(references, typeReference{
+			Name:     name,
+			FileName: fileName,
+		})
+	}
+
+	return references
+}
+
+// resolveTypeFileName returns the file name of the type with the given name,
+// resolved from the innermost to the outermost of the given scope
+//
+func (gen *DocGenerator) resolveTypeFileName(scope []string, name string) (string, bool) {
+	for i := len(scope); i >= 0; i-- {
+		qualifiedName := strings.Join(append(scope[:i:i], name), ".")
+		fileName, ok := gen.typeFileNames[qualifiedName]
+		if ok {
+			return fileName, true
+		}
+	}
+
+	return "", false
+}
+
+// declarationNominalTypes returns the nominal types in the signature of the given declaration
+//
+func declarationNominalTypes(decl ast.Declaration) []*ast.NominalType {
+	var nominalTypes []*ast.NominalType
+
+	addTypeAnnotation := func(typeAnnotation *ast.TypeAnnotation) {
+		if typeAnnotation == nil {
+			return
+		}
+		nominalTypes = appendNominalTypes(nominalTypes, typeAnnotation.Type)
+	}
+
+	addFunction := func(function *ast.FunctionDeclaration) {
+		if function.ParameterList != nil {
+			for _, parameter := range function.ParameterList.Parameters {
+				addTypeAnnotation(parameter.TypeAnnotation)
+			}
+		}
+		addTypeAnnotation(function.ReturnTypeAnnotation)
+	}
+
+	addFields := func(members *ast.Members) {
+		for _, field := range members.Fields() {
+			if isPublic(field) {
+				addTypeAnnotation(field.TypeAnnotation)
+			}
+		}
+	}
+
+	switch decl := decl.(type) {
+	case *ast.CompositeDeclaration:
+		if decl.DeclarationKind() == common.DeclarationKindEvent {
+			for _, specialFunction := range decl.Members.SpecialFunctions() {
+				addFunction(specialFunction.FunctionDeclaration)
+			}
+			break
+		}
+
+		nominalTypes = append(nominalTypes, decl.Conformances...)
+		addFields(decl.Members)
+
+	case *ast.InterfaceDeclaration:
+		addFields(decl.Members)
+
+	case *ast.FunctionDeclaration:
+		addFunction(decl)
+
+	case *ast.FieldDeclaration:
+		addTypeAnnotation(decl.TypeAnnotation)
+	}
+
+	return nominalTypes
+}
+
+func appendNominalTypes(nominalTypes []*ast.NominalType, ty ast.Type) []*ast.NominalType {
+	switch ty := ty.(type) {
+	case *ast.NominalType:
+		return append(nominalTypes, ty)
+
+	case *ast.OptionalType:
+		return appendNominalTypes(nominalTypes, ty.Type)
+
+	case *ast.VariableSizedType:
+		return appendNominalTypes(nominalTypes, ty.Type)
+
+	case *ast.ConstantSizedType:
+		return appendNominalTypes(nominalTypes, ty.Type)
+
+	case *ast.DictionaryType:
+		nominalTypes = appendNominalTypes(nominalTypes, ty.KeyType)
+		return appendNominalTypes(nominalTypes, ty.ValueType)
+
+	case *ast.FunctionType:
+		for _, parameterTypeAnnotation := range ty.ParameterTypeAnnotations {
+			nominalTypes = appendNominalTypes(nominalTypes, parameterTypeAnnotation.Type)
+		}
+		if ty.ReturnTypeAnnotation != nil {
+			nominalTypes = appendNominalTypes(nominalTypes, ty.ReturnTypeAnnotation.Type)
+		}
+		return nominalTypes
+
+	case *ast.ReferenceType:
+		return appendNominalTypes(nominalTypes, ty.Type)
+
+	case *ast.RestrictedType:
+		if ty.Type != nil {
+			nominalTypes = appendNominalTypes(nominalTypes, ty.Type)
+		}
+		return append(nominalTypes, ty.Restrictions...)
+
+	case *ast.InstantiationType:
+		nominalTypes = appendNominalTypes(nominalTypes, ty.Type)
+		for _, typeArgument := range ty.TypeArguments {
+			nominalTypes = appendNominalTypes(nominalTypes, typeArgument.Type)
+		}
+		return nominalTypes
+	}
+
+	return nominalTypes
+}
+
+// isPublic returns true if the given declaration is publicly accessible.
+// Declarations without an access modifier are considered public
+//
+func isPublic(decl ast.Declaration) bool {
+	switch decl.DeclarationAccess() {
+	case ast.AccessNotSpecified,
+		ast.AccessPublic,
+		ast.AccessPublicSettable:
+		return true
+	default:
+		return false
+	}
+}
+
 var functions = template.FuncMap{
 	"hasConformance": func(declaration ast.Declaration) bool {
 		switch declaration.DeclarationKind() {
@@ -298,7 +606,7 @@ var functions = template.FuncMap{
 		decls := make([]*ast.CompositeDeclaration, 0)
 
 		for _, decl := range declarations {
-			if decl.DeclarationKind() == common.DeclarationKindEnum {
+			if isPublic(decl) && decl.DeclarationKind() == common.DeclarationKindEnum {
 				decls = append(decls, decl)
 			}
 		}
@@ -310,6 +618,10 @@ var functions = template.FuncMap{
 		decls := make([]*ast.CompositeDeclaration, 0)
 
 		for _, decl := range declarations {
+			if !isPublic(decl) {
+				continue
+			}
+
 			switch decl.DeclarationKind() {
 			case common.DeclarationKindStructure,
 				common.DeclarationKindResource:
@@ -324,7 +636,37 @@ var functions = template.FuncMap{
 	"events": func(declarations []*ast.CompositeDeclaration) []*ast.CompositeDeclaration {
 		decls := make([]*ast.CompositeDeclaration, 0)
 		for _, decl := range declarations {
-			if decl.DeclarationKind() == common.DeclarationKindEvent {
+			if isPublic(decl) && decl.DeclarationKind() == common.DeclarationKindEvent {
+				decls = append(decls, decl)
+			}
+		}
+		return decls
+	},
+
+	"interfaces": func(declarations []*ast.InterfaceDeclaration) []*ast.InterfaceDeclaration {
+		decls := make([]*ast.InterfaceDeclaration, 0)
+		for _, decl := range declarations {
+			if isPublic(decl) {
+				decls = append(decls, decl)
+			}
+		}
+		return decls
+	},
+
+	"functions": func(declarations []*ast.FunctionDeclaration) []*ast.FunctionDeclaration {
+		decls := make([]*ast.FunctionDeclaration, 0)
+		for _, decl := range declarations {
+			if isPublic(decl) {
+				decls = append(decls, decl)
+			}
+		}
+		return decls
+	},
+
+	"fields": func(declarations []*ast.FieldDeclaration) []*ast.FieldDeclaration {
+		decls := make([]*ast.FieldDeclaration, 0)
+		for _, decl := range declarations {
+			if isPublic(decl) {
 				decls = append(decls, decl)
 			}
 		}
@@ -333,6 +675,10 @@ var functions = template.FuncMap{
 
 	"formatDoc": formatDocs,
 
+	"lineBreaks": func(text string) string {
+		return strings.ReplaceAll(text, newline, "<br/>"+newline)
+	},
+
 	"formatFuncDoc": formatFunctionDocs,
 }
 
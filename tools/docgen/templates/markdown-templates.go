@@ -26,6 +26,9 @@ import (
 //go:embed markdown
 var mdTemplateFiles embed.FS
 
+//go:embed html
+var htmlTemplateFiles embed.FS
+
 // MarkdownTemplateProvider is a provider for the markdown template files.
 //
 type MarkdownTemplateProvider struct {
@@ -43,3 +46,21 @@ func (t MarkdownTemplateProvider) Get(templateName string) (string, error) {
 
 	return string(content), nil
 }
+
+// HTMLTemplateProvider is a provider for the HTML template files.
+//
+type HTMLTemplateProvider struct {
+}
+
+func NewHTMLTemplateProvider() HTMLTemplateProvider {
+	return HTMLTemplateProvider{}
+}
+
+func (t HTMLTemplateProvider) Get(templateName string) (string, error) {
+	content, err := htmlTemplateFiles.ReadFile(path.Join("html", templateName))
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
@@ -88,6 +88,58 @@ func TestDocGenForSingleContractFile(t *testing.T) {
 	}
 }
 
+func TestDocGenForPublicDeclarations(t *testing.T) {
+
+	content, err := ioutil.ReadFile(path.Join("samples", "sample4.cdc"))
+	require.NoError(t, err)
+
+	test := func(format docgen.Format, fileNames []string) {
+
+		docGen := docgen.NewDocGenerator(docgen.WithFormat(format))
+
+		docFiles, err := docGen.GenerateInMemory(string(content))
+		require.NoError(t, err)
+
+		// Non-public declarations are not documented
+
+		actualFileNames := make([]string, 0, len(docFiles))
+		for fileName := range docFiles {
+			actualFileNames = append(actualFileNames, fileName)
+		}
+		require.ElementsMatch(t, fileNames, actualFileNames)
+
+		for fileName, fileContent := range docFiles {
+			expectedContent, err := ioutil.ReadFile(path.Join("outputs", fileName))
+			require.NoError(t, err)
+			assert.Equal(t, string(expectedContent), string(fileContent))
+		}
+	}
+
+	t.Run("Markdown", func(t *testing.T) {
+		test(
+			docgen.FormatMarkdown,
+			[]string{
+				"Tokens.md",
+				"Tokens_Config.md",
+				"Tokens_Receiver.md",
+				"Tokens_Vault.md",
+			},
+		)
+	})
+
+	t.Run("HTML", func(t *testing.T) {
+		test(
+			docgen.FormatHTML,
+			[]string{
+				"Tokens.html",
+				"Tokens_Config.html",
+				"Tokens_Receiver.html",
+				"Tokens_Vault.html",
+			},
+		)
+	})
+}
+
 func TestDocGenErrors(t *testing.T) {
 
 	t.Parallel()